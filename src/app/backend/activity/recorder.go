@@ -0,0 +1,130 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activity
+
+import (
+	"sync"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultMaxActions is the default number of actions kept per user.
+const DefaultMaxActions = 100
+
+// Resource identifies a resource touched by an action.
+type Resource struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// Action is a change a user made through the dashboard.
+type Action struct {
+	Time metaV1.Time `json:"time"`
+
+	// HTTP method and route of the change, e.g. PUT /api/v1/scale/{kind}/{namespace}/{name}/.
+	Method string `json:"method"`
+	Route  string `json:"route"`
+
+	// Status code of the response. Failed changes are recorded too.
+	StatusCode int `json:"statusCode"`
+
+	// Resource touched by the change. Nil if the route does not identify a single resource.
+	Resource *Resource `json:"resource,omitempty"`
+}
+
+// TouchedResource is a resource touched by actions of a user.
+type TouchedResource struct {
+	Resource
+
+	// Time of the latest action touching the resource.
+	LastTouched metaV1.Time `json:"lastTouched"`
+
+	// Number of recorded actions touching the resource.
+	Actions int `json:"actions"`
+}
+
+// Activity is a feed of recent actions of a user.
+type Activity struct {
+	// Recent actions, newest first.
+	Actions []Action `json:"actions"`
+
+	// Resources touched by the actions, most recently touched first.
+	Resources []TouchedResource `json:"resources"`
+}
+
+// Recorder records changes made through the dashboard per user. Complete audit of the changes is
+// in the API server audit log. The recorder keeps only recent actions in memory, to show users
+// what they recently worked on.
+type Recorder interface {
+	// Record adds action of given user.
+	Record(user string, action Action)
+
+	// GetActivity returns recent actions of given user.
+	GetActivity(user string) Activity
+}
+
+// recorder is an in-memory implementation of Recorder.
+type recorder struct {
+	// Maximum number of actions kept per user.
+	maxActions int
+
+	lock sync.RWMutex
+	// Actions keyed by user, oldest first.
+	actions map[string][]Action
+}
+
+// NewRecorder creates recorder that keeps given number of actions per user.
+func NewRecorder(maxActions int) Recorder {
+	return &recorder{maxActions: maxActions, actions: make(map[string][]Action)}
+}
+
+// Record adds action, dropping the oldest actions above the limit.
+func (self *recorder) Record(user string, action Action) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	actions := append(self.actions[user], action)
+	if len(actions) > self.maxActions {
+		actions = actions[len(actions)-self.maxActions:]
+	}
+	self.actions[user] = actions
+}
+
+// GetActivity returns copy of recorded actions and resources touched by them.
+func (self *recorder) GetActivity(user string) Activity {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	activity := Activity{Actions: make([]Action, 0), Resources: make([]TouchedResource, 0)}
+	touched := make(map[Resource]int)
+	actions := self.actions[user]
+	for i := len(actions) - 1; i >= 0; i-- {
+		action := actions[i]
+		activity.Actions = append(activity.Actions, action)
+		if action.Resource == nil {
+			continue
+		}
+
+		if index, ok := touched[*action.Resource]; ok {
+			activity.Resources[index].Actions++
+			continue
+		}
+		touched[*action.Resource] = len(activity.Resources)
+		activity.Resources = append(activity.Resources, TouchedResource{Resource: *action.Resource,
+			LastTouched: action.Time, Actions: 1})
+	}
+	return activity
+}
@@ -0,0 +1,72 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activity
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRecorder(t *testing.T) {
+	start := time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+	newAction := func(minutes int, resource *Resource) Action {
+		return Action{Time: metaV1.NewTime(start.Add(time.Duration(minutes) * time.Minute)),
+			Method: "PUT", Route: "/api/v1/scale/{kind}/{namespace}/{name}/", StatusCode: 200,
+			Resource: resource}
+	}
+	web := &Resource{Kind: "deployment", Namespace: "prod", Name: "web"}
+	db := &Resource{Kind: "statefulset", Namespace: "prod", Name: "db"}
+
+	recorder := NewRecorder(3)
+	recorder.Record("jane", newAction(0, db))
+	recorder.Record("jane", newAction(1, web))
+	recorder.Record("john", newAction(2, db))
+	recorder.Record("jane", newAction(3, nil))
+	recorder.Record("jane", newAction(4, web))
+
+	cases := []struct {
+		user     string
+		expected Activity
+	}{
+		{
+			"jane",
+			Activity{
+				Actions: []Action{newAction(4, web), newAction(3, nil), newAction(1, web)},
+				Resources: []TouchedResource{
+					{Resource: *web, LastTouched: metaV1.NewTime(start.Add(4 * time.Minute)), Actions: 2},
+				},
+			},
+		},
+		{
+			"john",
+			Activity{
+				Actions: []Action{newAction(2, db)},
+				Resources: []TouchedResource{
+					{Resource: *db, LastTouched: metaV1.NewTime(start.Add(2 * time.Minute)), Actions: 1},
+				},
+			},
+		},
+		{"joe", Activity{Actions: []Action{}, Resources: []TouchedResource{}}},
+	}
+	for _, c := range cases {
+		actual := recorder.GetActivity(c.user)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetActivity(%#v) == \ngot: %#v, \nexpected %#v", c.user, actual, c.expected)
+		}
+	}
+}
@@ -74,6 +74,28 @@ type TypeMeta struct {
 type ListMeta struct {
 	// Total number of items on the list. Used for pagination.
 	TotalItems int `json:"totalItems"`
+
+	// Total number of items on the list before filters were applied. TotalItems is equal to it
+	// when no filter was applied.
+	TotalBeforeFilter int `json:"totalBeforeFilter,omitempty"`
+
+	// Applied filters of the list in the format of filterBy query parameter. Empty when the list
+	// is not filtered.
+	Filter string `json:"filter,omitempty"`
+
+	// Effective sort of the list in the format of sortBy query parameter, including tie-breakers
+	// added by the backend. Empty when the list is not sorted.
+	Sort string `json:"sort,omitempty"`
+
+	// Number of the returned page, starting from 1. Empty when the list is not paginated.
+	Page int `json:"page,omitempty"`
+
+	// Number of items per page applied by the backend, after page size limits. Empty when the
+	// list is not paginated.
+	ItemsPerPage int `json:"itemsPerPage,omitempty"`
+
+	// Warnings about the list, e.g. when it was built from more objects than expected.
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // NewObjectMeta returns internal endpoint name for the given service properties, e.g.,
@@ -103,6 +125,7 @@ type ResourceKind string
 // List of all resource kinds supported by the UI.
 const (
 	ResourceKindConfigMap               = "configmap"
+	ResourceKindCronJob                 = "cronjob"
 	ResourceKindDaemonSet               = "daemonset"
 	ResourceKindDeployment              = "deployment"
 	ResourceKindEvent                   = "event"
@@ -111,10 +134,12 @@ const (
 	ResourceKindJob                     = "job"
 	ResourceKindLimitRange              = "limitrange"
 	ResourceKindNamespace               = "namespace"
+	ResourceKindNetworkPolicy           = "networkpolicy"
 	ResourceKindNode                    = "node"
 	ResourceKindPersistentVolumeClaim   = "persistentvolumeclaim"
 	ResourceKindPersistentVolume        = "persistentvolume"
 	ResourceKindPod                     = "pod"
+	ResourceKindPodDisruptionBudget     = "poddisruptionbudget"
 	ResourceKindReplicaSet              = "replicaset"
 	ResourceKindReplicationController   = "replicationcontroller"
 	ResourceKindResourceQuota           = "resourcequota"
@@ -127,6 +152,18 @@ const (
 	ResourceKindRbacClusterRole         = "clusterrole"
 	ResourceKindRbacRoleBinding         = "rolebinding"
 	ResourceKindRbacClusterRoleBinding  = "clusterrolebinding"
+	ResourceKindNodePool                = "nodepool"
+	ResourceKindNodeClaim               = "nodeclaim"
+	ResourceKindCertificate             = "certificate"
+	ResourceKindIssuer                  = "issuer"
+	ResourceKindClusterIssuer           = "clusterissuer"
+	ResourceKindOrder                   = "order"
+	ResourceKindMachine                 = "machine"
+	ResourceKindMachineSet              = "machineset"
+	ResourceKindMachineDeployment       = "machinedeployment"
+	ResourceKindBackup                  = "backup"
+	ResourceKindRestore                 = "restore"
+	ResourceKindSchedule                = "schedule"
 )
 
 // ClientType represents type of client that is used to perform generic operations on resources.
@@ -0,0 +1,82 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// RequestIDHeader is the header carrying ID of the dashboard request. It is set by the backend
+	// if the client did not provide one.
+	RequestIDHeader = "X-Request-Id"
+
+	// RequestUserHeader is the header authenticating proxies pass the name of the dashboard user in.
+	RequestUserHeader = "X-Forwarded-User"
+
+	// RequestGroupsHeader is the header authenticating proxies pass comma separated groups of the
+	// dashboard user in.
+	RequestGroupsHeader = "X-Forwarded-Groups"
+
+	// Maximum length of audit values put into the user agent.
+	maxAuditValueLength = 64
+)
+
+// getAuditUserAgent returns user agent of requests to the API server made for given dashboard
+// request. Request ID and user of the dashboard request are appended to the default user agent, so
+// that entries of cluster audit log can be correlated with dashboard actions.
+func getAuditUserAgent(req *restful.Request) string {
+	userAgent := rest.DefaultKubernetesUserAgent()
+	if req == nil || req.Request == nil {
+		return userAgent
+	}
+
+	annotations := make([]string, 0)
+	if requestID := sanitizeAuditValue(req.HeaderParameter(RequestIDHeader)); requestID != "" {
+		annotations = append(annotations, "request-id="+requestID)
+	}
+	if user := sanitizeAuditValue(req.HeaderParameter(RequestUserHeader)); user != "" {
+		annotations = append(annotations, "user="+user)
+	}
+	if len(annotations) == 0 {
+		return userAgent
+	}
+
+	return fmt.Sprintf("%s dashboard (%s)", userAgent, strings.Join(annotations, "; "))
+}
+
+// sanitizeAuditValue drops characters that could break the user agent format and truncates given
+// value.
+func sanitizeAuditValue(value string) string {
+	result := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case strings.ContainsRune("-_.@:", r):
+			return r
+		default:
+			return -1
+		}
+	}, value)
+
+	if len(result) > maxAuditValueLength {
+		result = result[:maxAuditValueLength]
+	}
+	return result
+}
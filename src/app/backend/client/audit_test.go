@@ -0,0 +1,54 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/client-go/rest"
+)
+
+func TestGetAuditUserAgent(t *testing.T) {
+	cases := []struct {
+		header   http.Header
+		expected string
+	}{
+		{http.Header{}, ""},
+		{http.Header{RequestIDHeader: {"abc123"}}, " dashboard (request-id=abc123)"},
+		{
+			http.Header{RequestIDHeader: {"abc123"}, RequestUserHeader: {"jane@example.com"}},
+			" dashboard (request-id=abc123; user=jane@example.com)",
+		},
+		{http.Header{RequestUserHeader: {"jane (admin); x=y"}}, " dashboard (user=janeadminxy)"},
+		{http.Header{RequestIDHeader: {strings.Repeat("a", 100)}},
+			" dashboard (request-id=" + strings.Repeat("a", maxAuditValueLength) + ")"},
+	}
+	for _, c := range cases {
+		request := restful.NewRequest(&http.Request{Header: c.header})
+		actual := getAuditUserAgent(request)
+		expected := rest.DefaultKubernetesUserAgent() + c.expected
+		if actual != expected {
+			t.Errorf("getAuditUserAgent(%#v) returns %#v, expected %#v", c.header, actual, expected)
+		}
+	}
+
+	if actual := getAuditUserAgent(nil); actual != rest.DefaultKubernetesUserAgent() {
+		t.Errorf("getAuditUserAgent(nil) returns %#v, expected %#v", actual,
+			rest.DefaultKubernetesUserAgent())
+	}
+}
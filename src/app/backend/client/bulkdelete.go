@@ -0,0 +1,82 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"fmt"
+
+	"github.com/kubernetes/dashboard/src/app/backend/operation"
+)
+
+// ResourceReference identifies a resource of given kind, e.g. pod.
+type ResourceReference struct {
+	Kind string `json:"kind"`
+
+	// Namespace of the resource. Empty for resources not in namespace.
+	Namespace string `json:"namespace"`
+
+	Name string `json:"name"`
+}
+
+// BulkDeleteSpec is a specification of resources deleted at once.
+type BulkDeleteSpec struct {
+	Resources []ResourceReference `json:"resources"`
+}
+
+// BulkDeleteResult is a result of deleting resources at once.
+type BulkDeleteResult struct {
+	Deleted []ResourceReference `json:"deleted"`
+
+	// Resources not deleted because of errors. The errors are in the log of the operation.
+	Failed []ResourceReference `json:"failed"`
+}
+
+// DeleteResources deletes given resources one by one and reports progress to given tracker.
+// Failing deletions don't stop the others, but fail the operation at the end. Resources not
+// deleted before cancellation are left in place.
+func DeleteResources(verber ResourceVerber, resources []ResourceReference,
+	tracker operation.Tracker) (interface{}, error) {
+	result := &BulkDeleteResult{
+		Deleted: make([]ResourceReference, 0),
+		Failed:  make([]ResourceReference, 0),
+	}
+
+	for i, resource := range resources {
+		select {
+		case <-tracker.Cancelled():
+			tracker.Logf("Cancelled, %d of %d resources deleted", len(result.Deleted),
+				len(resources))
+			return result, nil
+		default:
+		}
+
+		err := verber.Delete(resource.Kind, resource.Namespace != "", resource.Namespace,
+			resource.Name)
+		if err != nil {
+			tracker.Logf("Cannot delete %s %s: %s", resource.Kind, resource.Name, err)
+			result.Failed = append(result.Failed, resource)
+		} else {
+			tracker.Logf("Deleted %s %s", resource.Kind, resource.Name)
+			result.Deleted = append(result.Deleted, resource)
+		}
+		tracker.SetProgress((i + 1) * 100 / len(resources))
+	}
+
+	if len(result.Failed) > 0 {
+		return result, fmt.Errorf("Failed to delete %d of %d resources", len(result.Failed),
+			len(resources))
+	}
+	return result, nil
+}
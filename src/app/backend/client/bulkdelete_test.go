@@ -0,0 +1,83 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type fakeTracker struct {
+	logs      []string
+	progress  int
+	cancelled chan struct{}
+}
+
+func (self *fakeTracker) Logf(format string, args ...interface{}) {
+	self.logs = append(self.logs, fmt.Sprintf(format, args...))
+}
+
+func (self *fakeTracker) SetProgress(percent int) {
+	self.progress = percent
+}
+
+func (self *fakeTracker) Cancelled() <-chan struct{} {
+	return self.cancelled
+}
+
+func TestDeleteResources(t *testing.T) {
+	verber := ResourceVerber{
+		client: &FakeRESTClient{response: &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}},
+		appsClient: &FakeRESTClient{err: errors.New("err from apps")},
+	}
+	resources := []ResourceReference{
+		{Kind: "pod", Namespace: "default", Name: "web-1"},
+		{Kind: "statefulset", Namespace: "default", Name: "db"},
+		{Kind: "pod", Name: "web-2"},
+		{Kind: "namespace", Name: "default"},
+	}
+	tracker := &fakeTracker{cancelled: make(chan struct{})}
+
+	result, err := DeleteResources(verber, resources, tracker)
+
+	expected := &BulkDeleteResult{
+		Deleted: []ResourceReference{resources[0], resources[3]},
+		Failed:  []ResourceReference{resources[1], resources[2]},
+	}
+	if err == nil || !reflect.DeepEqual(result, expected) {
+		t.Errorf("DeleteResources() == \ngot: %#v, %v \nexpected %#v and error", result, err,
+			expected)
+	}
+	if len(tracker.logs) != len(resources) || tracker.progress != 100 {
+		t.Errorf("DeleteResources() reports %d log lines and progress %d, expected %d and 100",
+			len(tracker.logs), tracker.progress, len(resources))
+	}
+
+	close(tracker.cancelled)
+	result, err = DeleteResources(verber, resources, tracker)
+	expected = &BulkDeleteResult{Deleted: []ResourceReference{}, Failed: []ResourceReference{}}
+	if err != nil || !reflect.DeepEqual(result, expected) {
+		t.Errorf("DeleteResources() when cancelled == \ngot: %#v, %v \nexpected %#v", result, err,
+			expected)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+// PreviewParameter is the name of query parameter that turns changes requested by a dashboard
+// request into server-side dry runs.
+const PreviewParameter = "preview"
+
+// dryRunMinMinorVersion is the minor version of Kubernetes 1.x that enables server-side dry run
+// by default.
+const dryRunMinMinorVersion = 13
+
+// IsPreview returns true if given request asks only for a preview of its changes.
+func IsPreview(req *restful.Request) bool {
+	return req != nil && req.Request != nil && req.QueryParameter(PreviewParameter) == "true"
+}
+
+// IsDryRunSupported returns true if the API server is able to run changes through admission
+// without persisting them.
+func IsDryRunSupported(client discovery.ServerVersionInterface) (bool, error) {
+	info, err := client.ServerVersion()
+	if err != nil {
+		return false, err
+	}
+
+	major, err := strconv.Atoi(info.Major)
+	if err != nil {
+		return false, nil
+	}
+	// Minor versions of some providers have suffixes, e.g. 13+.
+	minor, err := strconv.Atoi(strings.TrimRight(info.Minor, "+"))
+	if err != nil {
+		return false, nil
+	}
+
+	return major > 1 || (major == 1 && minor >= dryRunMinMinorVersion), nil
+}
+
+// setDryRun makes all changes done with given config server-side dry runs.
+func setDryRun(cfg *rest.Config) {
+	wrapTransport := cfg.WrapTransport
+	cfg.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		if wrapTransport != nil {
+			rt = wrapTransport(rt)
+		}
+		return &dryRunRoundTripper{rt}
+	}
+}
+
+// dryRunRoundTripper adds dry run parameter to all requests that change objects.
+type dryRunRoundTripper struct {
+	rt http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (self *dryRunRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return self.rt.RoundTrip(req)
+	}
+
+	url := *req.URL
+	query := url.Query()
+	query.Set("dryRun", "All")
+	url.RawQuery = query.Encode()
+
+	dryRunReq := *req
+	dryRunReq.URL = &url
+	return self.rt.RoundTrip(&dryRunReq)
+}
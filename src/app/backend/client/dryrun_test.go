@@ -0,0 +1,93 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/version"
+)
+
+type fakeServerVersion struct {
+	info *version.Info
+	err  error
+}
+
+func (self *fakeServerVersion) ServerVersion() (*version.Info, error) {
+	return self.info, self.err
+}
+
+func TestIsDryRunSupported(t *testing.T) {
+	cases := []struct {
+		major, minor string
+		expected     bool
+	}{
+		{"1", "6", false},
+		{"1", "12", false},
+		{"1", "13", true},
+		{"1", "14+", true},
+		{"2", "0", true},
+		{"", "", false},
+	}
+	for _, c := range cases {
+		actual, err := IsDryRunSupported(&fakeServerVersion{info: &version.Info{Major: c.major, Minor: c.minor}})
+		if err != nil || actual != c.expected {
+			t.Errorf("IsDryRunSupported(%s.%s) returns %#v, %v, expected %#v", c.major, c.minor, actual,
+				err, c.expected)
+		}
+	}
+
+	if _, err := IsDryRunSupported(&fakeServerVersion{err: errors.New("unreachable")}); err == nil {
+		t.Error("IsDryRunSupported() should return error of unreachable server")
+	}
+}
+
+type recordingRoundTripper struct {
+	requests []*http.Request
+}
+
+func (self *recordingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	self.requests = append(self.requests, req)
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func TestDryRunRoundTripper(t *testing.T) {
+	cases := []struct {
+		method, url, expected string
+	}{
+		{"GET", "http://localhost/api/v1/pods?limit=1", "limit=1"},
+		{"POST", "http://localhost/api/v1/namespaces/foo/pods", "dryRun=All"},
+		{"PUT", "http://localhost/api/v1/nodes/bar?pretty=true", "dryRun=All&pretty=true"},
+		{"DELETE", "http://localhost/api/v1/nodes/bar", "dryRun=All"},
+	}
+	for _, c := range cases {
+		recorder := &recordingRoundTripper{}
+		req, err := http.NewRequest(c.method, c.url, nil)
+		if err != nil {
+			t.Fatal("Cannot mockup request")
+		}
+
+		(&dryRunRoundTripper{recorder}).RoundTrip(req)
+		if actual := recorder.requests[0].URL.RawQuery; actual != c.expected {
+			t.Errorf("RoundTrip(%s %s) sends query %#v, expected %#v", c.method, c.url, actual, c.expected)
+		}
+		if req.URL.String() != c.url {
+			t.Errorf("RoundTrip(%s %s) should not modify original request but got %s", c.method, c.url,
+				req.URL)
+		}
+	}
+}
@@ -94,6 +94,10 @@ func (self *clientManager) Config(req *restful.Request) (*rest.Config, error) {
 	}
 
 	self.initConfig(cfg)
+	cfg.UserAgent = getAuditUserAgent(req)
+	if IsPreview(req) {
+		setDryRun(cfg)
+	}
 	return cfg, nil
 }
 
@@ -134,7 +138,9 @@ func (self *clientManager) buildConfigFromFlags(apiserverHost, kubeConfigPath st
 	}
 
 	if self.isRunningInCluster() {
-		return self.inClusterConfig, nil
+		// Return a copy, as the config is modified for every request.
+		cfg := *self.inClusterConfig
+		return &cfg, nil
 	}
 
 	return nil, errors.New("Could not create client config. Check logs for more information")
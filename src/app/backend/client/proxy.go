@@ -0,0 +1,75 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Access modes of the dashboard, which tell how requests reach it.
+const (
+	// AccessModeDirect is used when the dashboard is accessed directly or through a reverse proxy
+	// serving it under its own origin.
+	AccessModeDirect = "direct"
+
+	// AccessModeKubectlProxy is used when the dashboard is accessed through kubectl proxy and the
+	// API server service proxy. Browser origin is then a loopback address different from the
+	// host the dashboard sees, and no authorization header is passed on, so requests use
+	// credentials of the dashboard itself. The mode only relaxes the origin check of exec and attach
+	// WebSockets and is reported in settings. Nothing else depends on it: reads need no origin and
+	// writes are checked by CSRF tokens, which do not depend on origin either, so the frontend gets
+	// and sends them through kubectl proxy like it does in direct mode.
+	AccessModeKubectlProxy = "kubectlProxy"
+)
+
+// KubectlProxyModeEnabled tells whether requests may be taken as coming through kubectl proxy. The
+// detection relies on origin and referrer headers, which are controlled by clients, so it is only
+// enabled by flag for dashboards reachable solely through the API server service proxy.
+var KubectlProxyModeEnabled = false
+
+// GetAccessMode returns access mode of given request. If kubectl proxy mode is enabled, requests
+// without authorization header whose origin, or referrer when origin is not sent, is a loopback
+// address different from the request host are taken as coming through kubectl proxy.
+func GetAccessMode(r *http.Request) string {
+	if !KubectlProxyModeEnabled || r.Header.Get("Authorization") != "" {
+		return AccessModeDirect
+	}
+
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		origin = r.Header.Get("Referer")
+	}
+	originURL, err := url.Parse(origin)
+	if err != nil || originURL.Host == "" || originURL.Host == r.Host {
+		return AccessModeDirect
+	}
+
+	if isLoopbackHost(originURL.Hostname()) {
+		return AccessModeKubectlProxy
+	}
+	return AccessModeDirect
+}
+
+// isLoopbackHost returns true for localhost and loopback IPs.
+func isLoopbackHost(host string) bool {
+	if strings.ToLower(host) == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
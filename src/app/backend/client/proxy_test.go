@@ -0,0 +1,61 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGetAccessMode(t *testing.T) {
+	KubectlProxyModeEnabled = true
+	defer func() { KubectlProxyModeEnabled = false }()
+
+	cases := []struct {
+		headers  map[string]string
+		expected string
+	}{
+		{map[string]string{}, AccessModeDirect},
+		{map[string]string{"Origin": "http://localhost:8001"}, AccessModeKubectlProxy},
+		{map[string]string{"Referer": "http://127.0.0.1:8001/api/v1/namespaces/kube-system/" +
+			"services/https:kubernetes-dashboard:/proxy/"}, AccessModeKubectlProxy},
+		{map[string]string{"Origin": "http://[::1]:8001"}, AccessModeKubectlProxy},
+		{map[string]string{"Origin": "http://localhost:8001", "Authorization": "Bearer token"},
+			AccessModeDirect},
+		{map[string]string{"Origin": "http://10.0.0.1:9090"}, AccessModeDirect},
+		{map[string]string{"Origin": "https://dashboard.example.com"}, AccessModeDirect},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest("GET", "https://dashboard.example.com/api/v1/settings", nil)
+		for key, value := range c.headers {
+			req.Header.Set(key, value)
+		}
+
+		actual := GetAccessMode(req)
+		if actual != c.expected {
+			t.Errorf("GetAccessMode(%#v) == \ngot %#v, \nexpected %#v", c.headers, actual, c.expected)
+		}
+	}
+}
+
+func TestGetAccessModeKubectlProxyModeDisabled(t *testing.T) {
+	req, _ := http.NewRequest("GET", "https://dashboard.example.com/api/v1/settings", nil)
+	req.Header.Set("Origin", "http://localhost:8001")
+
+	actual := GetAccessMode(req)
+	if actual != AccessModeDirect {
+		t.Errorf("GetAccessMode() == \ngot %#v, \nexpected %#v", actual, AccessModeDirect)
+	}
+}
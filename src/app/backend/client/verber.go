@@ -15,11 +15,13 @@
 package client
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 	restclient "k8s.io/client-go/rest"
 )
 
@@ -102,38 +104,43 @@ func (verber *ResourceVerber) Delete(kind string, namespaceSet bool, namespace s
 		Error()
 }
 
-// Put puts new resource version of the given kind in the given namespace with the given name.
+// Put puts new resource version of the given kind in the given namespace with the given name. It
+// returns the resource as stored by the API server.
 func (verber *ResourceVerber) Put(kind string, namespaceSet bool, namespace string, name string,
-	object *runtime.Unknown) error {
+	object *runtime.Unknown) (runtime.Object, error) {
 
 	resourceSpec, ok := api.KindToAPIMapping[kind]
 	if !ok {
-		return fmt.Errorf("Unknown resource kind: %s", kind)
+		return nil, fmt.Errorf("Unknown resource kind: %s", kind)
 	}
 
 	if namespaceSet != resourceSpec.Namespaced {
 		if namespaceSet {
-			return fmt.Errorf("Set namespace for not-namespaced resource kind: %s", kind)
+			return nil, fmt.Errorf("Set namespace for not-namespaced resource kind: %s", kind)
 		} else {
-			return fmt.Errorf("Set no namespace for namespaced resource kind: %s", kind)
+			return nil, fmt.Errorf("Set no namespace for namespaced resource kind: %s", kind)
 		}
 	}
 
 	client := verber.getRESTClientByType(resourceSpec.ClientType)
 
+	result := &runtime.Unknown{}
 	req := client.Put().
 		Resource(resourceSpec.Resource).
 		Name(name).
 		SetHeader("Content-Type", "application/json").
+		SetHeader("Accept", "application/json").
 		Body([]byte(object.Raw))
 
 	if resourceSpec.Namespaced {
 		req.Namespace(namespace)
 	}
 
-	return req.
+	err := req.
 		Do().
-		Error()
+		Into(result)
+
+	return result, err
 }
 
 // Get gets the resource of the given kind in the given namespace with the given name.
@@ -169,3 +176,78 @@ func (verber *ResourceVerber) Get(kind string, namespaceSet bool, namespace stri
 
 	return result, err
 }
+
+// Watch watches resources of the given kind in the given namespace, or in all namespaces if the
+// namespace is empty. Changes after the given resource version are watched, or current resources
+// are sent as added first if it is empty.
+func (verber *ResourceVerber) Watch(kind string, namespace string, resourceVersion string) (
+	watch.Interface, error) {
+	resourceSpec, ok := api.KindToAPIMapping[kind]
+	if !ok {
+		return nil, fmt.Errorf("Unknown resource kind: %s", kind)
+	}
+
+	if namespace != "" && !resourceSpec.Namespaced {
+		return nil, fmt.Errorf("Set namespace for not-namespaced resource kind: %s", kind)
+	}
+
+	client := verber.getRESTClientByType(resourceSpec.ClientType)
+
+	req := client.Get().
+		Resource(resourceSpec.Resource).
+		Param("watch", "true")
+
+	if resourceSpec.Namespaced {
+		req.Namespace(namespace)
+	}
+	if resourceVersion != "" {
+		req.Param("resourceVersion", resourceVersion)
+	}
+
+	return req.Watch()
+}
+
+// List lists resources of the given kind in the given namespace, or in all namespaces if the
+// namespace is empty. It returns the resources and resource version of the list, from which
+// changes of the resources can be watched.
+func (verber *ResourceVerber) List(kind string, namespace string) ([]runtime.Object, string, error) {
+	resourceSpec, ok := api.KindToAPIMapping[kind]
+	if !ok {
+		return nil, "", fmt.Errorf("Unknown resource kind: %s", kind)
+	}
+
+	if namespace != "" && !resourceSpec.Namespaced {
+		return nil, "", fmt.Errorf("Set namespace for not-namespaced resource kind: %s", kind)
+	}
+
+	client := verber.getRESTClientByType(resourceSpec.ClientType)
+
+	req := client.Get().
+		Resource(resourceSpec.Resource).
+		SetHeader("Accept", "application/json")
+
+	if resourceSpec.Namespaced {
+		req.Namespace(namespace)
+	}
+
+	raw, err := req.DoRaw()
+	if err != nil {
+		return nil, "", err
+	}
+
+	list := struct {
+		Metadata struct {
+			ResourceVersion string `json:"resourceVersion"`
+		} `json:"metadata"`
+		Items []json.RawMessage `json:"items"`
+	}{}
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, "", err
+	}
+
+	objects := make([]runtime.Object, 0, len(list.Items))
+	for _, item := range list.Items {
+		objects = append(objects, &runtime.Unknown{Raw: item, ContentType: runtime.ContentTypeJSON})
+	}
+	return objects, list.Metadata.ResourceVersion, nil
+}
@@ -2,8 +2,10 @@ package client
 
 import (
 	"errors"
+	"io/ioutil"
 	"net/http"
 	"reflect"
+	"strings"
 	"testing"
 
 	testapi "k8s.io/apimachinery/pkg/api/testing"
@@ -128,7 +130,7 @@ func TestGetShouldThrowErrorOnUnknownResourceKind(t *testing.T) {
 func TestPutShouldThrowErrorOnUnknownResourceKind(t *testing.T) {
 	verber := ResourceVerber{client: &FakeRESTClient{}}
 
-	err := verber.Put("foo", false, "", "baz", nil)
+	_, err := verber.Put("foo", false, "", "baz", nil)
 
 	if !reflect.DeepEqual(err, errors.New("Unknown resource kind: foo")) {
 		t.Fatalf("Expected error on verber put but got %#v", err)
@@ -148,7 +150,7 @@ func TestGetShouldRespectNamespacednessOfResourceKind(t *testing.T) {
 func TestPutShouldRespectNamespacednessOfResourceKind(t *testing.T) {
 	verber := ResourceVerber{client: &FakeRESTClient{}}
 
-	err := verber.Put("service", false, "", "baz", nil)
+	_, err := verber.Put("service", false, "", "baz", nil)
 
 	if !reflect.DeepEqual(err, errors.New("Set no namespace for namespaced resource kind: service")) {
 		t.Fatalf("Expected error on verber put but got %#v", err)
@@ -178,7 +180,7 @@ func TestGetShouldRespectNotNamespacednessOfResourceKind(t *testing.T) {
 func TestPutShouldRespectNotNamespacednessOfResourceKind(t *testing.T) {
 	verber := ResourceVerber{client: &FakeRESTClient{}}
 
-	err := verber.Put("namespace", true, "bar", "baz", nil)
+	_, err := verber.Put("namespace", true, "bar", "baz", nil)
 
 	if !reflect.DeepEqual(err, errors.New("Set namespace for not-namespaced resource kind: namespace")) {
 		t.Fatalf("Expected error on verber put but got %#v", err)
@@ -194,3 +196,22 @@ func TestDeleteShouldRespectNotNamespacednessOfResourceKind(t *testing.T) {
 		t.Fatalf("Expected error on verber delete but got %#v", err)
 	}
 }
+
+func TestListShouldReturnItemsAndResourceVersion(t *testing.T) {
+	verber := ResourceVerber{client: &FakeRESTClient{response: &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body: ioutil.NopCloser(strings.NewReader(
+			`{"metadata": {"resourceVersion": "42"}, "items": [{"metadata": {"name": "foo"}}]}`)),
+	}}}
+
+	objects, resourceVersion, err := verber.List("pod", "bar")
+
+	if err != nil {
+		t.Fatalf("Expected no error on verber list but got %#v", err)
+	}
+	if resourceVersion != "42" || len(objects) != 1 ||
+		string(objects[0].(*runtime.Unknown).Raw) != `{"metadata": {"name": "foo"}}` {
+		t.Fatalf("Expected pod foo of list version 42 but got %#v, %s", objects, resourceVersion)
+	}
+}
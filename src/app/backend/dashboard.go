@@ -21,12 +21,27 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/kubernetes/dashboard/src/app/backend/client"
+	"github.com/kubernetes/dashboard/src/app/backend/endpointhistory"
 	"github.com/kubernetes/dashboard/src/app/backend/handler"
+	"github.com/kubernetes/dashboard/src/app/backend/integration/alertmanager"
+	"github.com/kubernetes/dashboard/src/app/backend/integration/dnsresolver"
+	"github.com/kubernetes/dashboard/src/app/backend/integration/gitsource"
+	"github.com/kubernetes/dashboard/src/app/backend/integration/logsource"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/metricsserver"
+	metricprometheus "github.com/kubernetes/dashboard/src/app/backend/integration/metric/prometheus"
+	"github.com/kubernetes/dashboard/src/app/backend/replicahistory"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/settings"
+	"github.com/kubernetes/dashboard/src/app/backend/subscription"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/pflag"
+	"k8s.io/client-go/kubernetes"
 )
 
 var (
@@ -44,6 +59,77 @@ var (
 		"to connect to in the format of protocol://address:port, e.g., "+
 		"http://localhost:8082. If not specified, the assumption is that the binary runs inside a "+
 		"Kubernetes cluster and service proxy will be used.")
+	argMetricClient = pflag.String("metric-client", "auto", "The source of CPU and memory metrics, "+
+		"either heapster, metrics-server, prometheus or auto. Auto uses Heapster when --heapster-host "+
+		"is set, Prometheus when --prometheus-host is set, Heapster when the heapster service exists "+
+		"in kube-system namespace, and metrics-server otherwise, if the cluster serves the "+
+		"metrics.k8s.io API.")
+	argPrometheusHost = pflag.String("prometheus-host", "", "The address of the Prometheus server "+
+		"that scrapes cAdvisor metrics of the cluster in the format of protocol://address:port, e.g., "+
+		"http://localhost:9090.")
+	argPrometheusBearerTokenFile = pflag.String("prometheus-bearer-token-file", "", "File containing "+
+		"the bearer token to authorize requests to Prometheus with. If not specified, requests are "+
+		"not authorized.")
+	argAlertmanagerHost = pflag.String("alertmanager-host", "", "The address of the Alertmanager "+
+		"to query for active alerts in the format of protocol://address:port, e.g., "+
+		"http://localhost:9093. If not specified, the Alertmanager integration is disabled.")
+	argSettingsNamespace = pflag.String("settings-namespace", "kube-system", "The namespace of the "+
+		"config map that stores dashboard settings.")
+	argLogSource = pflag.String("log-source", "", "The backend to read historical logs of restarted "+
+		"and deleted pods from, either loki or elasticsearch. If not specified, only live logs are available.")
+	argLogSourceHost = pflag.String("log-source-host", "", "The address of the historical log backend "+
+		"in the format of protocol://address:port, e.g., http://localhost:3100.")
+	argLogSourceIndex = pflag.String("log-source-index", "logstash-*", "The Elasticsearch index "+
+		"pattern to search for historical logs.")
+	argReplicasHistoryInterval = pflag.Duration("replicas-history-interval", time.Minute, "How often "+
+		"replica counts of deployments are recorded for the replicas history. Set to 0 to disable the recording.")
+	argReplicasHistoryWindow = pflag.Duration("replicas-history-window", 6*time.Hour, "How long "+
+		"recorded replica counts of deployments are kept for.")
+	argEndpointHistoryWindow = pflag.Duration("endpoint-history-window", 0, "How long readiness "+
+		"transitions of endpoints are kept for to report endpoint churn of services. Transitions are "+
+		"observed by an informer of endpoints in all namespaces, which keeps them in memory. Set to 0 "+
+		"to disable the recording.")
+	argEnableSubscriptions = pflag.Bool("enable-subscriptions", false, "Whether users can subscribe "+
+		"to changes of resources. Changes are observed by informers of pods, workloads, services and "+
+		"config maps in all namespaces, which keep all these resources in memory.")
+	argBaseHref = pflag.String("base-href", "/", "The path prefix the dashboard is served under, "+
+		"e.g. /dashboard/ when it is exposed behind an ingress path. Applies to static files, API "+
+		"and WebSocket endpoints.")
+	argTrustedProxies = pflag.StringSlice("trusted-proxies", []string{}, "Comma separated list of "+
+		"CIDRs of reverse proxies trusted to set X-Forwarded-For, X-Forwarded-Proto and "+
+		"X-Forwarded-Host headers, e.g. 10.0.0.0/8.")
+	argCORSAllowedOrigins = pflag.StringSlice("cors-allowed-origins", []string{}, "Comma separated "+
+		"list of origins allowed to call the API from browsers, e.g. https://tools.example.com. "+
+		"Entries are matched exactly unless prefixed with regexp:, in which case they are regular "+
+		"expressions matching the whole origin. * allows any origin, but not together with "+
+		"--cors-allow-credentials. CORS is disabled if empty.")
+	argCORSAllowedHeaders = pflag.StringSlice("cors-allowed-headers", handler.DefaultCORSAllowedHeaders,
+		"Comma separated list of request headers allowed in cross-origin API calls.")
+	argCORSAllowCredentials = pflag.Bool("cors-allow-credentials", false, "Whether cross-origin API "+
+		"calls may include credentials, e.g. cookies and authorization headers.")
+	argEnableDebugEndpoints = pflag.Bool("enable-debug-endpoints", false, "Whether to serve pprof "+
+		"profiles under /debug/pprof/ and runtime variables under /debug/vars. Access requires a "+
+		"bearer token of a user allowed to get the same path from the API server.")
+	argMaxListObjects = pflag.Int("max-list-objects", 0, "Number of pods and events above which a "+
+		"single list request holds pods without spec details and keeps only the latest warning "+
+		"events, lists get a warning. Full lists are still fetched. Set to 0 to disable.")
+	argEnableResourceCache = pflag.Bool("enable-resource-cache", false, "Whether lists of "+
+		"deployments, replica sets, pods and events are served from memory. They are observed by "+
+		"informers in all namespaces, which keep all these resources in memory, and every cached list "+
+		"costs an access review of the user instead.")
+	argExternalDNSResolver = pflag.String("external-dns-resolver", "", "The name server used to "+
+		"verify that DNS records managed by external-dns resolve, in the format of host:port, e.g. "+
+		"8.8.8.8:53, or 'system' for name servers of the host. Records are not resolved if empty.")
+	argEnableKubectlProxyMode = pflag.Bool("enable-kubectl-proxy-mode", false, "Whether requests "+
+		"without authorization header from loopback origins are taken as coming through kubectl "+
+		"proxy, which accepts their WebSocket connections. Origins are set by clients, so enable it "+
+		"only if the dashboard is reachable solely through the API server service proxy.")
+	argAPIOnly = pflag.Bool("api-only", false, "Whether to serve only the API, i.e. no frontend "+
+		"assets, locale handling and frontend configuration. Use it when the dashboard is used as a "+
+		"backend of other UIs.")
+	argGitSourceHosts = pflag.StringSlice("git-source-hosts", gitsource.AllowedHosts, "Comma "+
+		"separated list of hosts manifests can be deployed from with deploy from Git. Repositories on "+
+		"github.com are fetched through the GitHub API, on other hosts through the GitLab API.")
 	argKubeConfigFile = pflag.String("kubeconfig", "", "Path to kubeconfig file with authorization and master location information.")
 )
 
@@ -63,12 +149,26 @@ func main() {
 		log.Printf("Using kubeconfig file: %s", *argKubeConfigFile)
 	}
 
+	common.MaxFanOutObjects = *argMaxListObjects
+	gitsource.AllowedHosts = *argGitSourceHosts
+
 	clientManager := client.NewClientManager(*argKubeConfigFile, *argApiserverHost)
 	apiserverClient, err := clientManager.Client(nil)
 	if err != nil {
 		handleFatalInitError(err)
 	}
 
+	if *argEnableKubectlProxyMode {
+		log.Print("Kubectl proxy mode is enabled")
+		client.KubectlProxyModeEnabled = true
+	}
+
+	if *argEnableResourceCache {
+		resourceCache := common.NewResourceCache()
+		resourceCache.Start(apiserverClient)
+		common.SharedCache = resourceCache
+	}
+
 	versionInfo, err := apiserverClient.ServerVersion()
 	if err != nil {
 		handleFatalInitError(err)
@@ -76,37 +176,149 @@ func main() {
 
 	log.Printf("Successful initial request to the apiserver, version: %s", versionInfo.String())
 
-	heapsterRESTClient, err := heapster.CreateHeapsterRESTClient(*argHeapsterHost,
-		apiserverClient)
+	metricClient, err := createMetricClient(apiserverClient)
+	if err != nil {
+		log.Printf("Could not create metric client: %s. Continuing.", err)
+	}
+
+	alertmanagerClient := alertmanager.CreateAlertmanagerClient(*argAlertmanagerHost)
+
+	logSource, err := logsource.CreateLogSource(*argLogSource, *argLogSourceHost, *argLogSourceIndex)
 	if err != nil {
-		log.Printf("Could not create heapster client: %s. Continuing.", err)
+		log.Printf("Could not create log source: %s. Continuing.", err)
+	}
+
+	var replicasRecorder replicahistory.Recorder
+	if *argReplicasHistoryInterval > 0 {
+		replicasRecorder = replicahistory.NewRecorder(*argReplicasHistoryWindow)
+		replicasRecorder.Start(apiserverClient, *argReplicasHistoryInterval)
+	}
+
+	var endpointRecorder endpointhistory.Recorder
+	if *argEndpointHistoryWindow > 0 {
+		endpointRecorder = endpointhistory.NewRecorder(*argEndpointHistoryWindow)
+		endpointRecorder.Start(apiserverClient)
+	}
+
+	var subscriptions subscription.Manager
+	if *argEnableSubscriptions {
+		subscriptions = subscription.NewManager()
+		subscriptions.Start(apiserverClient)
 	}
 
 	apiHandler, err := handler.CreateHTTPAPIHandler(
-		heapsterRESTClient,
-		clientManager)
+		metricClient,
+		alertmanagerClient,
+		clientManager,
+		settings.NewSettingsManager(*argSettingsNamespace),
+		logSource,
+		replicasRecorder,
+		endpointRecorder,
+		subscriptions,
+		dnsresolver.CreateResolver(*argExternalDNSResolver),
+		handler.CORSConfig{
+			AllowedOrigins:   *argCORSAllowedOrigins,
+			AllowedHeaders:   *argCORSAllowedHeaders,
+			AllowCredentials: *argCORSAllowCredentials,
+		})
 	if err != nil {
 		handleFatalInitError(err)
 	}
 
 	// Run a HTTP server that serves static public files from './public' and handles API calls.
 	// TODO(bryk): Disable directory listing.
-	http.Handle("/", handler.MakeGzipHandler(handler.CreateLocaleHandler()))
-	http.Handle("/api/", apiHandler)
-	// TODO(maciaszczykm): Move to /appConfig.json as it was discussed in #640.
-	http.Handle("/api/appConfig.json", handler.AppHandler(handler.ConfigHandler))
-	http.Handle("/metrics", prometheus.Handler())
+	// Default serve mux is not used, as importing pprof and expvar registers them there.
+	mux := http.NewServeMux()
+	mux.Handle("/api/", apiHandler)
+	if *argAPIOnly {
+		log.Print("Serving only the API, frontend is disabled")
+	} else {
+		mux.Handle("/", handler.MakeGzipHandler(handler.CreateLocaleHandler()))
+		// TODO(maciaszczykm): Move to /appConfig.json as it was discussed in #640.
+		mux.Handle("/api/appConfig.json", handler.AppHandler(handler.ConfigHandler))
+	}
+	mux.Handle("/metrics", prometheus.Handler())
+	if *argEnableDebugEndpoints {
+		log.Print("Serving debug endpoints under /debug/")
+		mux.Handle("/debug/", handler.CreateDebugHandler(clientManager))
+	}
+
+	log.Printf("Using base href: %s", handler.NormalizeBaseHref(*argBaseHref))
+	rootHandler := handler.CreateBaseHrefHandler(*argBaseHref, mux)
+
+	trustedProxies, err := handler.ParseTrustedProxies(*argTrustedProxies)
+	if err != nil {
+		log.Fatalf("Invalid --trusted-proxies flag: %s", err)
+	}
+	if len(trustedProxies) > 0 {
+		log.Printf("Using trusted proxies: %s", strings.Join(*argTrustedProxies, ","))
+	}
+	rootHandler = handler.CreateForwardedHeadersHandler(trustedProxies, rootHandler)
 
 	// Listen for http and https
 	addr := fmt.Sprintf("%s:%d", *argInsecureBindAddress, *argInsecurePort)
-	go log.Fatal(http.ListenAndServe(addr, nil))
+	go log.Fatal(http.ListenAndServe(addr, rootHandler))
 	secureAddr := fmt.Sprintf("%s:%d", *argBindAddress, *argPort)
 	if len(*argCertFile) != 0 && len(*argKeyFile) != 0 {
-		go log.Fatal(http.ListenAndServeTLS(secureAddr, *argCertFile, *argKeyFile, nil))
+		go log.Fatal(http.ListenAndServeTLS(secureAddr, *argCertFile, *argKeyFile, rootHandler))
 	}
 	select {}
 }
 
+// createMetricClient creates client of the metric source selected by --metric-client flag.
+func createMetricClient(apiserverClient *kubernetes.Clientset) (metricapi.MetricClient, error) {
+	source := *argMetricClient
+	if source == "auto" {
+		source = detectMetricSource(apiserverClient)
+	}
+
+	switch source {
+	case "heapster":
+		return heapster.CreateHeapsterRESTClient(*argHeapsterHost, apiserverClient)
+	case "metrics-server":
+		apiPath, err := metricsserver.GetAPIPath(apiserverClient.Discovery())
+		if err != nil {
+			return nil, err
+		}
+		if apiPath == "" {
+			return nil, fmt.Errorf("the cluster does not serve %s API", metricsserver.GroupName)
+		}
+		log.Printf("Creating metrics-server client for %s", apiPath)
+		metricClient := metricsserver.NewMetricsServerClient(apiserverClient.Core().RESTClient(),
+			apiPath, metricsserver.DefaultWindow)
+		metricClient.Start(metricsserver.DefaultInterval)
+		return metricClient, nil
+	case "prometheus":
+		metricClient, err := metricprometheus.CreatePrometheusClient(*argPrometheusHost,
+			*argPrometheusBearerTokenFile)
+		if err != nil {
+			return nil, err
+		}
+		return metricClient, nil
+	default:
+		return nil, fmt.Errorf("unknown metric client %s", source)
+	}
+}
+
+// detectMetricSource returns heapster when Heapster is configured, prometheus when Prometheus is,
+// heapster when Heapster is deployed in the cluster and metrics-server when only metrics-server is.
+func detectMetricSource(apiserverClient *kubernetes.Clientset) string {
+	if *argHeapsterHost != "" {
+		return "heapster"
+	}
+	if *argPrometheusHost != "" {
+		return "prometheus"
+	}
+	if heapster.IsInClusterHeapsterAvailable(apiserverClient) {
+		return "heapster"
+	}
+	if apiPath, err := metricsserver.GetAPIPath(apiserverClient.Discovery()); err == nil && apiPath != "" {
+		log.Print("Heapster is not deployed, falling back to metrics-server")
+		return "metrics-server"
+	}
+	return "heapster"
+}
+
 /**
  * Handles fatal init error that prevents server from doing any work. Prints verbose error
  * message and quits the server.
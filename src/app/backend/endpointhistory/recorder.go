@@ -0,0 +1,232 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpointhistory
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Maximum number of transitions kept per service.
+const maxTransitions = 1000
+
+// Transition is a change of readiness of an endpoint address of a service.
+type Transition struct {
+	Timestamp metaV1.Time `json:"timestamp"`
+
+	// IP of the endpoint address.
+	IP string `json:"ip"`
+
+	// Name of the object the address belongs to, usually a pod. Empty if not known.
+	TargetName string `json:"targetName,omitempty"`
+
+	// Whether the address became ready. False also for ready addresses removed from endpoints.
+	Ready bool `json:"ready"`
+}
+
+// Recorder records readiness transitions of endpoint addresses of services. Kubernetes keeps only
+// the current endpoints, so the recorder watches them and keeps transitions in memory.
+type Recorder interface {
+	// Start starts watching endpoints in all namespaces, using given client.
+	Start(client kubernetes.Interface)
+
+	// Window returns how long transitions are kept for.
+	Window() time.Duration
+
+	// GetTransitions returns transitions of endpoints of given service since given time, oldest
+	// first.
+	GetTransitions(namespace, name string, since time.Time) []Transition
+}
+
+// recorder is an in-memory implementation of Recorder.
+type recorder struct {
+	// How long transitions are kept for.
+	window time.Duration
+
+	lock sync.RWMutex
+	// Whether the initial list of endpoints was received. Endpoints listed initially are not
+	// changes, so they add no transitions.
+	synced bool
+	// Transitions keyed by namespace/name of service.
+	transitions map[string][]Transition
+}
+
+// NewRecorder creates recorder that keeps transitions for given time.
+func NewRecorder(window time.Duration) Recorder {
+	return &recorder{window: window, transitions: make(map[string][]Transition)}
+}
+
+// Start starts the endpoints informer in background.
+func (self *recorder) Start(client kubernetes.Interface) {
+	log.Printf("Recording endpoint transitions for %s", self.window)
+	listWatch := cache.NewListWatchFromClient(client.CoreV1().RESTClient(), "endpoints",
+		metaV1.NamespaceAll, fields.Everything())
+	_, controller := cache.NewInformer(listWatch, &v1.Endpoints{}, 0, cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if endpoints, ok := obj.(*v1.Endpoints); ok {
+				self.record(nil, endpoints, time.Now())
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldEndpoints, oldOk := oldObj.(*v1.Endpoints)
+			newEndpoints, newOk := newObj.(*v1.Endpoints)
+			if oldOk && newOk {
+				self.record(oldEndpoints, newEndpoints, time.Now())
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = unknown.Obj
+			}
+			if endpoints, ok := obj.(*v1.Endpoints); ok {
+				self.forget(endpoints.Namespace, endpoints.Name)
+			}
+		},
+	})
+	go controller.Run(make(chan struct{}))
+
+	go func() {
+		cache.WaitForCacheSync(make(chan struct{}), controller.HasSynced)
+		self.lock.Lock()
+		self.synced = true
+		self.lock.Unlock()
+		log.Print("Endpoints informer synced")
+	}()
+}
+
+// Window returns how long transitions are kept for.
+func (self *recorder) Window() time.Duration {
+	return self.window
+}
+
+// addressState is readiness of an endpoint address.
+type addressState struct {
+	targetName string
+	ready      bool
+}
+
+// getAddressStates returns readiness of addresses of given endpoints keyed by IP.
+func getAddressStates(endpoints *v1.Endpoints) map[string]addressState {
+	states := make(map[string]addressState)
+	if endpoints == nil {
+		return states
+	}
+	add := func(address v1.EndpointAddress, ready bool) {
+		state := addressState{ready: ready}
+		if address.TargetRef != nil {
+			state.targetName = address.TargetRef.Name
+		}
+		states[address.IP] = state
+	}
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.NotReadyAddresses {
+			add(address, false)
+		}
+		for _, address := range subset.Addresses {
+			add(address, true)
+		}
+	}
+	return states
+}
+
+// record adds transitions of addresses whose readiness differs between given old and new
+// endpoints. Addresses missing in endpoints are considered not ready. Transitions older than the
+// window are dropped.
+func (self *recorder) record(oldEndpoints, newEndpoints *v1.Endpoints, now time.Time) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if oldEndpoints == nil && !self.synced {
+		return
+	}
+	if oldEndpoints != nil && oldEndpoints.ResourceVersion != "" &&
+		oldEndpoints.ResourceVersion == newEndpoints.ResourceVersion {
+		return
+	}
+
+	oldStates, newStates := getAddressStates(oldEndpoints), getAddressStates(newEndpoints)
+	ips := make([]string, 0, len(oldStates)+len(newStates))
+	for ip := range newStates {
+		ips = append(ips, ip)
+	}
+	for ip := range oldStates {
+		if _, ok := newStates[ip]; !ok {
+			ips = append(ips, ip)
+		}
+	}
+	sort.Strings(ips)
+
+	key := newEndpoints.Namespace + "/" + newEndpoints.Name
+	transitions := self.transitions[key]
+	for _, ip := range ips {
+		oldState, newState := oldStates[ip], newStates[ip]
+		if oldState.ready == newState.ready {
+			continue
+		}
+		targetName := newState.targetName
+		if targetName == "" {
+			targetName = oldState.targetName
+		}
+		transitions = append(transitions, Transition{
+			Timestamp:  metaV1.NewTime(now),
+			IP:         ip,
+			TargetName: targetName,
+			Ready:      newState.ready,
+		})
+	}
+
+	oldest := now.Add(-self.window)
+	start := 0
+	for start < len(transitions) && transitions[start].Timestamp.Time.Before(oldest) {
+		start++
+	}
+	if len(transitions)-start > maxTransitions {
+		start = len(transitions) - maxTransitions
+	}
+	if len(transitions) > start {
+		self.transitions[key] = transitions[start:]
+	} else {
+		delete(self.transitions, key)
+	}
+}
+
+// forget drops transitions of deleted endpoints.
+func (self *recorder) forget(namespace, name string) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	delete(self.transitions, namespace+"/"+name)
+}
+
+// GetTransitions returns a copy of recorded transitions since given time.
+func (self *recorder) GetTransitions(namespace, name string, since time.Time) []Transition {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	result := make([]Transition, 0)
+	for _, transition := range self.transitions[namespace+"/"+name] {
+		if !transition.Timestamp.Time.Before(since) {
+			result = append(result, transition)
+		}
+	}
+	return result
+}
@@ -0,0 +1,103 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpointhistory
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+func endpoints(name, version string, ready []string, notReady []string) *v1.Endpoints {
+	subset := v1.EndpointSubset{}
+	for _, ip := range ready {
+		subset.Addresses = append(subset.Addresses, v1.EndpointAddress{IP: ip,
+			TargetRef: &v1.ObjectReference{Name: "pod-" + ip}})
+	}
+	for _, ip := range notReady {
+		subset.NotReadyAddresses = append(subset.NotReadyAddresses, v1.EndpointAddress{IP: ip,
+			TargetRef: &v1.ObjectReference{Name: "pod-" + ip}})
+	}
+	return &v1.Endpoints{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default", ResourceVersion: version},
+		Subsets:    []v1.EndpointSubset{subset},
+	}
+}
+
+func transition(t time.Time, ip string, ready bool) Transition {
+	return Transition{Timestamp: metaV1.NewTime(t), IP: ip, TargetName: "pod-" + ip, Ready: ready}
+}
+
+func TestRecord(t *testing.T) {
+	start := time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+	r := &recorder{window: time.Hour, transitions: make(map[string][]Transition)}
+
+	// Endpoints listed before the informer synced are not transitions.
+	r.record(nil, endpoints("app", "1", []string{"10.0.0.1"}, nil), start)
+	r.synced = true
+
+	v1Endpoints := endpoints("app", "1", []string{"10.0.0.1"}, nil)
+	v2Endpoints := endpoints("app", "2", []string{"10.0.0.1"}, []string{"10.0.0.2"})
+	v3Endpoints := endpoints("app", "3", []string{"10.0.0.2"}, []string{"10.0.0.1"})
+	v4Endpoints := endpoints("app", "4", nil, []string{"10.0.0.1"})
+	// A new not ready address is not a transition.
+	r.record(v1Endpoints, v2Endpoints, start.Add(time.Minute))
+	// Resync with the same version adds nothing.
+	r.record(v2Endpoints, v2Endpoints, start.Add(2*time.Minute))
+	r.record(v2Endpoints, v3Endpoints, start.Add(30*time.Minute))
+	// Removed ready address becomes not ready.
+	r.record(v3Endpoints, v4Endpoints, start.Add(80*time.Minute))
+
+	r.record(nil, endpoints("new", "1", []string{"10.0.1.1"}, nil), start.Add(80*time.Minute))
+	r.record(nil, endpoints("gone", "1", []string{"10.0.2.1"}, nil), start.Add(80*time.Minute))
+	r.forget("default", "gone")
+
+	cases := []struct {
+		name     string
+		since    time.Time
+		expected []Transition
+	}{
+		{
+			"app",
+			start,
+			[]Transition{
+				transition(start.Add(30*time.Minute), "10.0.0.1", false),
+				transition(start.Add(30*time.Minute), "10.0.0.2", true),
+				transition(start.Add(80*time.Minute), "10.0.0.2", false),
+			},
+		},
+		{
+			"app",
+			start.Add(time.Hour),
+			[]Transition{transition(start.Add(80*time.Minute), "10.0.0.2", false)},
+		},
+		{
+			"new",
+			start,
+			[]Transition{transition(start.Add(80*time.Minute), "10.0.1.1", true)},
+		},
+		{"gone", start, []Transition{}},
+	}
+	for _, c := range cases {
+		actual := r.GetTransitions("default", c.name, c.since)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetTransitions(%#v, %v) == \ngot: %#v, \nexpected %#v", c.name, c.since, actual,
+				c.expected)
+		}
+	}
+}
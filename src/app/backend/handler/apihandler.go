@@ -15,32 +15,58 @@
 package handler
 
 import (
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	restful "github.com/emicklei/go-restful"
+	"github.com/kubernetes/dashboard/src/app/backend/activity"
 	"github.com/kubernetes/dashboard/src/app/backend/api"
 	"github.com/kubernetes/dashboard/src/app/backend/client"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	"github.com/kubernetes/dashboard/src/app/backend/endpointhistory"
+	"github.com/kubernetes/dashboard/src/app/backend/integration/alertmanager"
+	"github.com/kubernetes/dashboard/src/app/backend/integration/dnsresolver"
+	"github.com/kubernetes/dashboard/src/app/backend/integration/gitsource"
+	"github.com/kubernetes/dashboard/src/app/backend/integration/logsource"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	"github.com/kubernetes/dashboard/src/app/backend/link"
+	"github.com/kubernetes/dashboard/src/app/backend/operation"
+	"github.com/kubernetes/dashboard/src/app/backend/replicahistory"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/admission"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/availability"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/certificate"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/certmanager"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/cluster"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/clusterapi"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/clusterautoscaler"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/config"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/configmap"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/container"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/controlplane"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/cronjob"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/daemonset"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/deployment"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/deprecation"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/discovery"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/disruption"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/externaldns"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/horizontalpodautoscaler"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/ingress"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/job"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/karpenter"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/labelselector"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/logs"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
 	ns "github.com/kubernetes/dashboard/src/app/backend/resource/namespace"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/node"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/overview"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/persistentvolume"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/persistentvolumeclaim"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/pod"
@@ -48,18 +74,29 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/rbacroles"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/replicaset"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/replicationcontroller"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/restart"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/scaling"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/secret"
 	resourceService "github.com/kubernetes/dashboard/src/app/backend/resource/service"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/slo"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/statefulset"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/storageclass"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/thirdpartyresource"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/upgrade"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/velero"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/workload"
 	"github.com/kubernetes/dashboard/src/app/backend/search"
+	"github.com/kubernetes/dashboard/src/app/backend/settings"
+	"github.com/kubernetes/dashboard/src/app/backend/subscription"
 	"github.com/kubernetes/dashboard/src/app/backend/validation"
 	"golang.org/x/net/xsrftoken"
 	errorsK8s "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	authorizationApi "k8s.io/client-go/pkg/apis/authorization/v1"
 )
 
 const (
@@ -72,20 +109,51 @@ const (
 
 // APIHandler is a representation of API handler. Structure contains client, Heapster client and client configuration.
 type APIHandler struct {
-	heapsterClient heapster.HeapsterClient
-	manager        client.ClientManager
+	heapsterClient     metricapi.MetricClient
+	alertmanagerClient alertmanager.AlertmanagerClient
+	alertBadges        *alertmanager.BadgeCache
+	manager            client.ClientManager
+	settingsManager    settings.SettingsManager
+	logSource          logsource.LogSource
+	replicasRecorder   replicahistory.Recorder
+	endpointRecorder   endpointhistory.Recorder
+	operations         operation.Manager
+	activityRecorder   activity.Recorder
+	subscriptions      subscription.Manager
+	dnsResolver        dnsresolver.Resolver
+	sharedSettings     *sharedSettings
 }
 
 // CreateHTTPAPIHandler creates a new HTTP handler that handles all requests to the API of the backend.
-func CreateHTTPAPIHandler(heapsterClient heapster.HeapsterClient, manager client.ClientManager) (
-	http.Handler, error) {
-	apiHandler := APIHandler{heapsterClient: heapsterClient, manager: manager}
+func CreateHTTPAPIHandler(heapsterClient metricapi.MetricClient,
+	alertmanagerClient alertmanager.AlertmanagerClient, manager client.ClientManager,
+	settingsManager settings.SettingsManager, logSource logsource.LogSource,
+	replicasRecorder replicahistory.Recorder, endpointRecorder endpointhistory.Recorder,
+	subscriptions subscription.Manager, dnsResolver dnsresolver.Resolver,
+	corsConfig CORSConfig) (http.Handler, error) {
+	apiHandler := APIHandler{heapsterClient: heapsterClient, alertmanagerClient: alertmanagerClient,
+		manager: manager, settingsManager: settingsManager, logSource: logSource,
+		alertBadges:      alertmanager.NewBadgeCache(alertmanagerClient),
+		replicasRecorder: replicasRecorder,
+		endpointRecorder: endpointRecorder,
+		operations:       operation.NewManager(operation.DefaultRetention),
+		activityRecorder: activity.NewRecorder(activity.DefaultMaxActions),
+		subscriptions:    subscriptions,
+		dnsResolver:      dnsResolver,
+		sharedSettings:   &sharedSettings{manager: manager, settingsManager: settingsManager}}
 	wsContainer := restful.NewContainer()
 	wsContainer.EnableContentEncoding(true)
+	if err := installCORSFilter(wsContainer, corsConfig); err != nil {
+		return nil, err
+	}
 
 	apiV1Ws := new(restful.WebService)
 
 	InstallFilters(apiV1Ws, manager)
+	apiV1Ws.Filter(apiHandler.activityFilter)
+	apiV1Ws.Filter(apiHandler.sharedSettings.paginationLimitsFilter)
+	apiV1Ws.Filter(apiHandler.maintenanceFreezeFilter)
+	apiV1Ws.Filter(apiHandler.previewFilter)
 
 	apiV1Ws.Path("/api/v1").
 		Consumes(restful.MIME_JSON).
@@ -112,6 +180,11 @@ func CreateHTTPAPIHandler(heapsterClient heapster.HeapsterClient, manager client
 			To(apiHandler.handleImageReferenceValidity).
 			Reads(validation.ImageReferenceValiditySpec{}).
 			Writes(validation.ImageReferenceValidity{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/appdeployment/validate/scheduling").
+			To(apiHandler.handleSchedulingValidity).
+			Reads(validation.SchedulingValiditySpec{}).
+			Writes(validation.SchedulingValidity{}))
 	apiV1Ws.Route(
 		apiV1Ws.POST("/appdeployment/validate/protocol").
 			To(apiHandler.handleProtocolValidity).
@@ -127,6 +200,21 @@ func CreateHTTPAPIHandler(heapsterClient heapster.HeapsterClient, manager client
 			To(apiHandler.handleDeployFromFile).
 			Reads(deployment.AppDeploymentFromFileSpec{}).
 			Writes(deployment.AppDeploymentFromFileResponse{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/appdeploymentfromfile/render").
+			To(apiHandler.handleRenderDeploymentFile).
+			Reads(deployment.AppDeploymentFromFileSpec{}).
+			Writes(deployment.AppDeploymentFromFileResponse{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/appdeploymentfromgit").
+			To(apiHandler.handleDeployFromGit).
+			Reads(deployment.AppDeploymentFromGitSpec{}).
+			Writes(deployment.AppDeploymentFromGitResponse{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/appdeploymentfromgit/render").
+			To(apiHandler.handleRenderGitSource).
+			Reads(deployment.AppDeploymentFromGitSpec{}).
+			Writes(deployment.AppDeploymentFromGitResponse{}))
 
 	apiV1Ws.Route(
 		apiV1Ws.GET("/replicationcontroller").
@@ -140,10 +228,6 @@ func CreateHTTPAPIHandler(heapsterClient heapster.HeapsterClient, manager client
 		apiV1Ws.GET("/replicationcontroller/{namespace}/{replicationController}").
 			To(apiHandler.handleGetReplicationControllerDetail).
 			Writes(replicationcontroller.ReplicationControllerDetail{}))
-	apiV1Ws.Route(
-		apiV1Ws.POST("/replicationcontroller/{namespace}/{replicationController}/update/pod").
-			To(apiHandler.handleUpdateReplicasCount).
-			Reads(replicationcontroller.ReplicationControllerSpec{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/replicationcontroller/{namespace}/{replicationController}/pod").
 			To(apiHandler.handleGetReplicationControllerPods).
@@ -225,7 +309,11 @@ func CreateHTTPAPIHandler(heapsterClient heapster.HeapsterClient, manager client
 	apiV1Ws.Route(
 		apiV1Ws.GET("/pod/{namespace}/{pod}/container").
 			To(apiHandler.handleGetPodContainers).
-			Writes(pod.PodDetail{}))
+			Writes(container.PodContainerList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/pod/{namespace}/{pod}/volume/{volume}").
+			To(apiHandler.handleGetPodVolumePreview).
+			Writes(pod.VolumePreview{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/pod/{namespace}/{pod}/log").
 			To(apiHandler.handleLogs).
@@ -234,10 +322,41 @@ func CreateHTTPAPIHandler(heapsterClient heapster.HeapsterClient, manager client
 		apiV1Ws.GET("/pod/{namespace}/{pod}/log/{container}").
 			To(apiHandler.handleLogs).
 			Writes(logs.LogDetails{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/pod/{namespace}/{pod}/log/{container}/follow").
+			To(apiHandler.handleFollowLogs))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/watch/{kind}").
+			To(apiHandler.handleWatch))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/watch/{kind}/{namespace}").
+			To(apiHandler.handleWatch))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/pod/{namespace}/{pod}/log/{container}/file").
+			To(apiHandler.handleLogFile).
+			Produces("text/plain"))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/pod/{namespace}/{pod}/attach/{container}").
+			To(apiHandler.handleAttach))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/pod/{namespace}/{pod}/exec/{container}").
+			To(apiHandler.handleExec))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/pod/{namespace}/{pod}/event").
 			To(apiHandler.handleGetPodEvents).
 			Writes(common.EventList{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/pod/{namespace}/{pod}/evict").
+			To(apiHandler.handleEvictPod).
+			Writes(pod.EvictionResult{}))
+	apiV1Ws.Route(
+		apiV1Ws.DELETE("/pod/{namespace}/{pod}/force").
+			To(apiHandler.handleForceDeletePod))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/imagedrift/{source}/{target}").
+			To(apiHandler.handleGetImageDrift).
+			Writes(deployment.ImageDrift{}))
 
 	apiV1Ws.Route(
 		apiV1Ws.GET("/deployment").
@@ -259,15 +378,180 @@ func CreateHTTPAPIHandler(heapsterClient heapster.HeapsterClient, manager client
 		apiV1Ws.GET("/deployment/{namespace}/{deployment}/oldreplicaset").
 			To(apiHandler.handleGetDeploymentOldReplicaSets).
 			Writes(replicaset.ReplicaSetList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/deployment/{namespace}/{deployment}/replicahistory").
+			To(apiHandler.handleGetDeploymentReplicasHistory).
+			Writes(replicahistory.ReplicasHistory{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/deployment/{namespace}/{deployment}/changelog").
+			To(apiHandler.handleGetDeploymentChangelog).
+			Writes(deployment.Changelog{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/deployment/{namespace}/{deployment}/revisiondiff").
+			To(apiHandler.handleGetDeploymentRevisionDiff).
+			Writes(deployment.RevisionDiff{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/deployment/{namespace}/{deployment}/rollback").
+			To(apiHandler.handleRollbackDeployment).
+			Reads(deployment.RollbackSpec{}))
+	apiV1Ws.Route(
+		apiV1Ws.PUT("/deployment/{namespace}/{deployment}/pause").
+			To(apiHandler.handlePauseDeployment))
+	apiV1Ws.Route(
+		apiV1Ws.PUT("/deployment/{namespace}/{deployment}/resume").
+			To(apiHandler.handleResumeDeployment))
 
 	apiV1Ws.Route(
 		apiV1Ws.PUT("/scale/{kind}/{namespace}/{name}/").
 			To(apiHandler.handleScaleResource).
 			Writes(scaling.ReplicaCounts{}))
+	apiV1Ws.Route(
+		apiV1Ws.PUT("/restart/{kind}/{namespace}/{name}").
+			To(apiHandler.handleRestartResource))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/scale/{kind}/{namespace}/{name}").
 			To(apiHandler.handleGetReplicaCount).
 			Writes(scaling.ReplicaCounts{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/scale/{kind}/{namespace}/{name}/capacity").
+			To(apiHandler.handleGetScaleCapacity).
+			Writes(validation.ScaleCapacity{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/activity").
+			To(apiHandler.handleGetActivity).
+			Writes(activity.Activity{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/subscription").
+			To(apiHandler.handleGetSubscriptions).
+			Writes(subscription.SubscriptionList{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/subscription").
+			To(apiHandler.handleSubscribe).
+			Reads(subscription.SubscriptionSpec{}).
+			Writes(subscription.Subscription{}))
+	apiV1Ws.Route(
+		apiV1Ws.DELETE("/subscription/{id}").
+			To(apiHandler.handleUnsubscribe))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/subscription/notification").
+			To(apiHandler.handleGetNotifications).
+			Writes(subscription.NotificationList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/subscription/notification/stream").
+			To(apiHandler.handleWatchNotifications))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/operations/{id}").
+			To(apiHandler.handleGetOperation).
+			Writes(operation.Operation{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/operations/{id}/cancel").
+			To(apiHandler.handleCancelOperation).
+			Writes(operation.Operation{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/settings").
+			To(apiHandler.handleGetSettings).
+			Writes(settings.Settings{}))
+	apiV1Ws.Route(
+		apiV1Ws.PUT("/settings").
+			To(apiHandler.handleSaveSettings).
+			Reads(settings.Settings{}).
+			Writes(settings.Settings{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/view").
+			To(apiHandler.handleGetViewList).
+			Writes([]settings.View{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/view").
+			To(apiHandler.handleSaveView).
+			Reads(settings.View{}).
+			Writes(settings.View{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/view/{token}").
+			To(apiHandler.handleGetView).
+			Writes(settings.View{}))
+	apiV1Ws.Route(
+		apiV1Ws.DELETE("/view/{token}").
+			To(apiHandler.handleDeleteView))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/webhook").
+			To(apiHandler.handleGetWebhookList).
+			Writes([]settings.Webhook{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/webhook").
+			To(apiHandler.handleCreateWebhook).
+			Reads(settings.Webhook{}).
+			Writes(settings.CreatedWebhook{}))
+	apiV1Ws.Route(
+		apiV1Ws.DELETE("/webhook/{name}").
+			To(apiHandler.handleDeleteWebhook))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/webhook/record").
+			To(apiHandler.handleGetWebhookRecords).
+			Writes([]settings.WebhookRecord{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/webhook/trigger/{namespace}/{deployment}").
+			To(apiHandler.handleTriggerWebhook).
+			Reads(webhookCall{}).
+			Writes(settings.WebhookRecord{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.POST("/link").
+			To(apiHandler.handleCreateDeepLink).
+			Reads(settings.QueryState{}).
+			Writes(settings.DeepLink{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/link/{token}").
+			To(apiHandler.handleResolveDeepLink).
+			Writes(settings.DeepLink{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/alert").
+			To(apiHandler.handleGetAlertList).
+			Writes(alertmanager.AlertList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/alert/{namespace}").
+			To(apiHandler.handleGetAlertList).
+			Writes(alertmanager.AlertList{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/alert/silence").
+			To(apiHandler.handleCreateSilence).
+			Reads(alertmanager.Silence{}).
+			Writes(alertmanager.SilenceResponse{}))
+	apiV1Ws.Route(
+		apiV1Ws.DELETE("/alert/silence/{id}").
+			To(apiHandler.handleDeleteSilence))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/disruption/{namespace}").
+			To(apiHandler.handleGetNamespaceDisruptions).
+			Writes(disruption.DisruptionList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/disruption/{kind}/{namespace}/{name}").
+			To(apiHandler.handleGetWorkloadDisruptions).
+			Writes(disruption.DisruptionList{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/slo/{namespace}").
+			To(apiHandler.handleGetSLOList).
+			Writes(slo.SLOList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/slo/{kind}/{namespace}/{name}").
+			To(apiHandler.handleGetSLO).
+			Writes(slo.SLOStatus{}))
+	apiV1Ws.Route(
+		apiV1Ws.PUT("/slo/{kind}/{namespace}/{name}").
+			To(apiHandler.handlePutSLO).
+			Reads(slo.SLOSpec{}).
+			Writes(slo.SLOStatus{}))
+	apiV1Ws.Route(
+		apiV1Ws.DELETE("/slo/{kind}/{namespace}/{name}").
+			To(apiHandler.handleDeleteSLO))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/daemonset").
 			To(apiHandler.handleGetDaemonSetList).
@@ -325,11 +609,48 @@ func CreateHTTPAPIHandler(heapsterClient heapster.HeapsterClient, manager client
 		apiV1Ws.GET("/job/{namespace}/{job}/pod").
 			To(apiHandler.handleGetJobPods).
 			Writes(pod.PodList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/job/{namespace}/{job}/indexlog").
+			To(apiHandler.handleGetJobIndexedLogs).
+			Writes(job.JobIndexedLogs{}))
+	apiV1Ws.Route(
+		apiV1Ws.PUT("/job/{namespace}/{job}/suspend").
+			To(apiHandler.handleSuspendJob).
+			Writes(job.JobSuspendStatus{}))
+	apiV1Ws.Route(
+		apiV1Ws.PUT("/job/{namespace}/{job}/resume").
+			To(apiHandler.handleResumeJob).
+			Writes(job.JobSuspendStatus{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/job/{namespace}/{job}/event").
 			To(apiHandler.handleGetJobEvents).
 			Writes(common.EventList{}))
 
+	apiV1Ws.Route(
+		apiV1Ws.GET("/cronjob").
+			To(apiHandler.handleGetCronJobList).
+			Writes(cronjob.CronJobList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/cronjob/{namespace}").
+			To(apiHandler.handleGetCronJobList).
+			Writes(cronjob.CronJobList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/cronjob/{namespace}/{name}").
+			To(apiHandler.handleGetCronJobDetail).
+			Writes(cronjob.CronJobDetail{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/cronjob/{namespace}/{name}/trigger").
+			To(apiHandler.handleTriggerCronJob).
+			Writes(cronjob.CronJobRun{}))
+	apiV1Ws.Route(
+		apiV1Ws.PUT("/cronjob/{namespace}/{name}/suspend").
+			To(apiHandler.handleSuspendCronJob).
+			Writes(cronjob.CronJobSuspendStatus{}))
+	apiV1Ws.Route(
+		apiV1Ws.PUT("/cronjob/{namespace}/{name}/resume").
+			To(apiHandler.handleResumeCronJob).
+			Writes(cronjob.CronJobSuspendStatus{}))
+
 	apiV1Ws.Route(
 		apiV1Ws.POST("/namespace").
 			To(apiHandler.handleCreateNamespace).
@@ -347,6 +668,14 @@ func CreateHTTPAPIHandler(heapsterClient heapster.HeapsterClient, manager client
 		apiV1Ws.GET("/namespace/{name}/event").
 			To(apiHandler.handleGetNamespaceEvents).
 			Writes(common.EventList{}))
+	apiV1Ws.Route(
+		apiV1Ws.PUT("/namespace/{name}/maintenance").
+			To(apiHandler.handlePutNamespaceMaintenance).
+			Reads(common.NamespaceMaintenance{}).
+			Writes(common.NamespaceMaintenance{}))
+	apiV1Ws.Route(
+		apiV1Ws.DELETE("/namespace/{name}/maintenance").
+			To(apiHandler.handleDeleteNamespaceMaintenance))
 
 	apiV1Ws.Route(
 		apiV1Ws.GET("/secret").
@@ -366,6 +695,24 @@ func CreateHTTPAPIHandler(heapsterClient heapster.HeapsterClient, manager client
 			Reads(secret.ImagePullSecretSpec{}).
 			Writes(secret.Secret{}))
 
+	apiV1Ws.Route(
+		apiV1Ws.GET("/externaldns").
+			To(apiHandler.handleGetExternalDNSRecordList).
+			Writes(externaldns.RecordList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/externaldns/{namespace}").
+			To(apiHandler.handleGetExternalDNSRecordList).
+			Writes(externaldns.RecordList{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/tlscertificate").
+			To(apiHandler.handleGetCertificateReport).
+			Writes(certificate.CertificateReport{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/tlscertificate/{namespace}").
+			To(apiHandler.handleGetCertificateReport).
+			Writes(certificate.CertificateReport{}))
+
 	apiV1Ws.Route(
 		apiV1Ws.GET("/configmap").
 			To(apiHandler.handleGetConfigMapList).
@@ -395,6 +742,21 @@ func CreateHTTPAPIHandler(heapsterClient heapster.HeapsterClient, manager client
 		apiV1Ws.GET("/service/{namespace}/{service}/pod").
 			To(apiHandler.handleGetServicePods).
 			Writes(pod.PodList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/service/{namespace}/{service}/loadbalancer").
+			To(apiHandler.handleGetLoadBalancerStatus).
+			Writes(resourceService.LoadBalancerStatus{}))
+	apiV1Ws.Route(
+		apiV1Ws.PUT("/service/{namespace}/{service}/loadbalancer/retry").
+			To(apiHandler.handleRetryLoadBalancer))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/endpointchurn").
+			To(apiHandler.handleGetEndpointChurn).
+			Writes(resourceService.EndpointChurnList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/endpointchurn/{namespace}").
+			To(apiHandler.handleGetEndpointChurn).
+			Writes(resourceService.EndpointChurnList{}))
 
 	apiV1Ws.Route(
 		apiV1Ws.GET("/ingress").
@@ -421,6 +783,11 @@ func CreateHTTPAPIHandler(heapsterClient heapster.HeapsterClient, manager client
 		apiV1Ws.GET("/statefulset/{namespace}/{statefulset}").
 			To(apiHandler.handleGetStatefulSetDetail).
 			Writes(statefulset.StatefulSetDetail{}))
+	apiV1Ws.Route(
+		apiV1Ws.PUT("/statefulset/{namespace}/{statefulset}/partition").
+			To(apiHandler.handleUpdateStatefulSetPartition).
+			Reads(statefulset.PartitionSpec{}).
+			Writes(statefulset.StatefulSetUpdateStrategy{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/statefulset/{namespace}/{statefulset}/pod").
 			To(apiHandler.handleGetStatefulSetPods).
@@ -446,7 +813,250 @@ func CreateHTTPAPIHandler(heapsterClient heapster.HeapsterClient, manager client
 		apiV1Ws.GET("/node/{name}/pod").
 			To(apiHandler.handleGetNodePods).
 			Writes(pod.PodList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/node/{name}/timeline").
+			To(apiHandler.handleGetNodeConditionTimeline).
+			Writes(node.NodeConditionTimeline{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/node/{name}/logfile").
+			To(apiHandler.handleGetNodeLogFiles).
+			Writes(node.NodeLogFileList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/node/{name}/log").
+			To(apiHandler.handleGetNodeLog).
+			Writes(node.NodeLog{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/node/{name}/journal/{unit}").
+			To(apiHandler.handleGetNodeJournal).
+			Writes(node.NodeLog{}))
+	apiV1Ws.Route(
+		apiV1Ws.PUT("/node/{name}/cordon").
+			To(apiHandler.handleCordonNode))
+	apiV1Ws.Route(
+		apiV1Ws.PUT("/node/{name}/uncordon").
+			To(apiHandler.handleUncordonNode))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/node/{name}/drain").
+			To(apiHandler.handleDrainNode).
+			Writes(operation.Operation{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/nodepool").
+			To(apiHandler.handleGetNodePoolList).
+			Writes(karpenter.NodePoolList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/nodepool/{name}").
+			To(apiHandler.handleGetNodePoolDetail).
+			Writes(karpenter.NodePoolDetail{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/nodeclaim").
+			To(apiHandler.handleGetNodeClaimList).
+			Writes(karpenter.NodeClaimList{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/machine").
+			To(apiHandler.handleGetMachineList).
+			Writes(clusterapi.MachineList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/machine/{namespace}").
+			To(apiHandler.handleGetMachineList).
+			Writes(clusterapi.MachineList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/machine/{namespace}/{name}").
+			To(apiHandler.handleGetMachineDetail).
+			Writes(clusterapi.MachineDetail{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/machineset").
+			To(apiHandler.handleGetMachineSetList).
+			Writes(clusterapi.MachineSetList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/machineset/{namespace}").
+			To(apiHandler.handleGetMachineSetList).
+			Writes(clusterapi.MachineSetList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/machineset/{namespace}/{name}").
+			To(apiHandler.handleGetMachineSetDetail).
+			Writes(clusterapi.MachineSetDetail{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/machinedeployment").
+			To(apiHandler.handleGetMachineDeploymentList).
+			Writes(clusterapi.MachineDeploymentList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/machinedeployment/{namespace}").
+			To(apiHandler.handleGetMachineDeploymentList).
+			Writes(clusterapi.MachineDeploymentList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/machinedeployment/{namespace}/{name}").
+			To(apiHandler.handleGetMachineDeploymentDetail).
+			Writes(clusterapi.MachineDeploymentDetail{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/backup").
+			To(apiHandler.handleGetBackupList).
+			Writes(velero.BackupList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/backup/{namespace}").
+			To(apiHandler.handleGetBackupList).
+			Writes(velero.BackupList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/backup/{namespace}/{name}").
+			To(apiHandler.handleGetBackupDetail).
+			Writes(velero.BackupDetail{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/backup/{namespace}/{name}/resources").
+			To(apiHandler.handleGetBackupResources).
+			Writes(velero.BackupResourceList{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/backup").
+			To(apiHandler.handleCreateBackup).
+			Reads(velero.BackupSpec{}).
+			Writes(velero.Backup{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/restore").
+			To(apiHandler.handleGetRestoreList).
+			Writes(velero.RestoreList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/restore/{namespace}").
+			To(apiHandler.handleGetRestoreList).
+			Writes(velero.RestoreList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/restore/{namespace}/{name}").
+			To(apiHandler.handleGetRestoreDetail).
+			Writes(velero.Restore{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/restore").
+			To(apiHandler.handleCreateRestore).
+			Reads(velero.RestoreSpec{}).
+			Writes(velero.Restore{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/schedule").
+			To(apiHandler.handleGetScheduleList).
+			Writes(velero.ScheduleList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/schedule/{namespace}").
+			To(apiHandler.handleGetScheduleList).
+			Writes(velero.ScheduleList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/schedule/{namespace}/{name}").
+			To(apiHandler.handleGetScheduleDetail).
+			Writes(velero.ScheduleDetail{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/schedule/{namespace}/{name}/trigger").
+			To(apiHandler.handleTriggerSchedule).
+			Writes(velero.Backup{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/certificate").
+			To(apiHandler.handleGetCertManagerCertificateList).
+			Writes(certmanager.CertificateList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/certificate/{namespace}").
+			To(apiHandler.handleGetCertManagerCertificateList).
+			Writes(certmanager.CertificateList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/certificate/{namespace}/{name}").
+			To(apiHandler.handleGetCertManagerCertificateDetail).
+			Writes(certmanager.CertificateDetail{}))
+	apiV1Ws.Route(
+		apiV1Ws.PUT("/certificate/{namespace}/{name}/renew").
+			To(apiHandler.handleRenewCertManagerCertificate))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/issuer").
+			To(apiHandler.handleGetCertManagerIssuerList).
+			Writes(certmanager.IssuerList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/issuer/{namespace}").
+			To(apiHandler.handleGetCertManagerIssuerList).
+			Writes(certmanager.IssuerList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/issuer/{namespace}/{name}").
+			To(apiHandler.handleGetCertManagerIssuerDetail).
+			Writes(certmanager.IssuerDetail{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/clusterissuer/{name}").
+			To(apiHandler.handleGetCertManagerClusterIssuerDetail).
+			Writes(certmanager.IssuerDetail{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/order").
+			To(apiHandler.handleGetCertManagerOrderList).
+			Writes(certmanager.OrderList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/order/{namespace}").
+			To(apiHandler.handleGetCertManagerOrderList).
+			Writes(certmanager.OrderList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/order/{namespace}/{name}").
+			To(apiHandler.handleGetCertManagerOrderDetail).
+			Writes(certmanager.OrderDetail{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/clusterautoscaler").
+			To(apiHandler.handleGetClusterAutoscalerStatus).
+			Writes(clusterautoscaler.ClusterAutoscalerStatus{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/admission").
+			To(apiHandler.handleGetAdmissionReport).
+			Writes(admission.AdmissionReport{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/admission/{namespace}").
+			To(apiHandler.handleGetAdmissionReport).
+			Writes(admission.AdmissionReport{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/availability").
+			To(apiHandler.handleGetAvailabilityReport).
+			Writes(availability.AvailabilityReport{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/availability/{namespace}").
+			To(apiHandler.handleGetAvailabilityReport).
+			Writes(availability.AvailabilityReport{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/controlplane").
+			To(apiHandler.handleGetControlPlaneStatus).
+			Writes(controlplane.ControlPlaneStatus{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/deprecation").
+			To(apiHandler.handleGetDeprecationReport).
+			Writes(deprecation.DeprecationReport{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/upgradereadiness").
+			To(apiHandler.handleGetUpgradeReadiness).
+			Writes(upgrade.UpgradeReadiness{}))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/txt/pod").
+			To(apiHandler.handleGetPodsText).
+			Produces("text/plain"))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/txt/pod/{namespace}").
+			To(apiHandler.handleGetPodsText).
+			Produces("text/plain"))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/txt/deployment").
+			To(apiHandler.handleGetDeploymentsText).
+			Produces("text/plain"))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/txt/deployment/{namespace}").
+			To(apiHandler.handleGetDeploymentsText).
+			Produces("text/plain"))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/txt/node").
+			To(apiHandler.handleGetNodeListText).
+			Produces("text/plain"))
+
+	apiV1Ws.Route(
+		apiV1Ws.GET("/overview").
+			To(apiHandler.handleGetOverview).
+			Writes(overview.Overview{}))
 
+	apiV1Ws.Route(
+		apiV1Ws.POST("/_raw/delete").
+			To(apiHandler.handleDeleteResources).
+			Reads(client.BulkDeleteSpec{}).
+			Writes(operation.Operation{}))
 	apiV1Ws.Route(
 		apiV1Ws.DELETE("/_raw/{kind}/namespace/{namespace}/name/{name}").
 			To(apiHandler.handleDeleteResource))
@@ -531,6 +1141,15 @@ func CreateHTTPAPIHandler(heapsterClient heapster.HeapsterClient, manager client
 			To(apiHandler.handleSearch).
 			Writes(search.SearchResult{}))
 
+	apiV1Ws.Route(
+		apiV1Ws.GET("/labelselector").
+			To(apiHandler.handleGetLabelSelectorResult).
+			Writes(labelselector.LabelSelectorResult{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/labelselector/{namespace}").
+			To(apiHandler.handleGetLabelSelectorResult).
+			Writes(labelselector.LabelSelectorResult{}))
+
 	return wsContainer, nil
 }
 
@@ -589,6 +1208,11 @@ func (apiHandler *APIHandler) handleGetStatefulSetList(request *restful.Request,
 		handleInternalError(response, err)
 		return
 	}
+
+	badges := apiHandler.alertBadges.Get()
+	for i, item := range result.StatefulSets {
+		result.StatefulSets[i].Alerts = badges.Get(api.ResourceKindStatefulSet, item.ObjectMeta.Namespace, item.ObjectMeta.Name)
+	}
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
@@ -606,6 +1230,31 @@ func (apiHandler *APIHandler) handleGetStatefulSetDetail(request *restful.Reques
 		handleInternalError(response, err)
 		return
 	}
+	result.Links = apiHandler.getLinks(api.ResourceKindStatefulSet, result.ObjectMeta)
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleUpdateStatefulSetPartition(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("statefulset")
+	spec := new(statefulset.PartitionSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	result, err := statefulset.UpdatePartition(k8sClient, namespace, name, spec)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
@@ -732,16 +1381,17 @@ func (apiHandler *APIHandler) handleGetServicePods(request *restful.Request, res
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetNodeList(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetLoadBalancerStatus(request *restful.Request,
+	response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	dataSelect := parseDataSelectPathParameter(request)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := node.GetNodeList(k8sClient, dataSelect, apiHandler.heapsterClient)
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("service")
+	result, err := resourceService.GetLoadBalancerStatus(k8sClient, namespace, name)
 	if err != nil {
 		handleInternalError(response, err)
 		return
@@ -749,49 +1399,46 @@ func (apiHandler *APIHandler) handleGetNodeList(request *restful.Request, respon
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetCluster(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleRetryLoadBalancer(request *restful.Request,
+	response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	dataSelect := parseDataSelectPathParameter(request)
-	dataSelect.MetricQuery = dataselect.NoMetrics
-	result, err := cluster.GetCluster(k8sClient, dataSelect, &apiHandler.heapsterClient)
-	if err != nil {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("service")
+	if err := resourceService.RetryLoadBalancer(k8sClient, namespace, name); err != nil {
 		handleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	response.WriteHeader(http.StatusOK)
 }
 
-func (apiHandler *APIHandler) handleGetNodeDetail(request *restful.Request, response *restful.Response) {
-	k8sClient, err := apiHandler.manager.Client(request)
-	if err != nil {
-		handleInternalError(response, err)
+func (apiHandler *APIHandler) handleGetEndpointChurn(request *restful.Request,
+	response *restful.Response) {
+	if apiHandler.endpointRecorder == nil {
+		handleInternalError(response, errorsK8s.NewServiceUnavailable(
+			"Recording of endpoint transitions is disabled"))
 		return
 	}
 
-	name := request.PathParameter("name")
-	result, err := node.GetNodeDetail(k8sClient, apiHandler.heapsterClient, name)
+	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
-}
 
-func (apiHandler *APIHandler) handleGetNodeEvents(request *restful.Request, response *restful.Response) {
-	k8sClient, err := apiHandler.manager.Client(request)
+	window, err := parseChurnWindow(request, apiHandler.endpointRecorder.Window())
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	name := request.PathParameter("name")
-	dataSelect := parseDataSelectPathParameter(request)
-	result, err := event.GetNodeEvents(k8sClient, dataSelect, name)
+	namespace := parseNamespacePathParameter(request)
+	result, err := resourceService.GetEndpointChurnList(k8sClient, apiHandler.endpointRecorder,
+		namespace, window)
 	if err != nil {
 		handleInternalError(response, err)
 		return
@@ -799,7 +1446,37 @@ func (apiHandler *APIHandler) handleGetNodeEvents(request *restful.Request, resp
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetNodePods(request *restful.Request, response *restful.Response) {
+// defaultChurnWindow is the window of endpoint churn used when not set by the request.
+const defaultChurnWindow = 15 * time.Minute
+
+// parseChurnWindow returns the window of endpoint churn set by window query parameter, e.g. 30m.
+// The window can't be longer than transitions are recorded for.
+func parseChurnWindow(request *restful.Request, max time.Duration) (time.Duration, error) {
+	value := request.QueryParameter("window")
+	if value == "" {
+		if defaultChurnWindow > max {
+			return max, nil
+		}
+		return defaultChurnWindow, nil
+	}
+	window, err := time.ParseDuration(value)
+	if err != nil || window <= 0 || window > max {
+		return 0, errorsK8s.NewBadRequest(fmt.Sprintf("Invalid window %s, expected duration up "+
+			"to %s", value, max))
+	}
+	return window, nil
+}
+
+func (apiHandler *APIHandler) handleCordonNode(request *restful.Request, response *restful.Response) {
+	apiHandler.setNodeUnschedulable(request, response, true)
+}
+
+func (apiHandler *APIHandler) handleUncordonNode(request *restful.Request, response *restful.Response) {
+	apiHandler.setNodeUnschedulable(request, response, false)
+}
+
+func (apiHandler *APIHandler) setNodeUnschedulable(request *restful.Request,
+	response *restful.Response, unschedulable bool) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
@@ -807,157 +1484,160 @@ func (apiHandler *APIHandler) handleGetNodePods(request *restful.Request, respon
 	}
 
 	name := request.PathParameter("name")
-	dataSelect := parseDataSelectPathParameter(request)
-	result, err := node.GetNodePods(k8sClient, apiHandler.heapsterClient, dataSelect, name)
-	if err != nil {
+	if err := node.CordonNode(k8sClient, name, unschedulable); err != nil {
 		handleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	response.WriteHeader(http.StatusOK)
 }
 
-func (apiHandler *APIHandler) handleDeploy(request *restful.Request, response *restful.Response) {
+// Handles draining a node. The drain runs as an operation, which is returned right away and polled
+// by the client for progress. Cancelling the operation stops the drain.
+func (apiHandler *APIHandler) handleDrainNode(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	appDeploymentSpec := new(deployment.AppDeploymentSpec)
-	if err := request.ReadEntity(appDeploymentSpec); err != nil {
+	options, err := parseDrainOptions(request)
+	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
-	if err := deployment.DeployApp(appDeploymentSpec, k8sClient); err != nil {
+
+	name := request.PathParameter("name")
+	op, err := apiHandler.operations.Start("drain", getViewUser(request).Name,
+		func(tracker operation.Tracker) (interface{}, error) {
+			return node.DrainNode(k8sClient, name, options, tracker)
+		})
+	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusCreated, appDeploymentSpec)
+	response.WriteHeaderAndEntity(http.StatusAccepted, op)
 }
 
-func (apiHandler *APIHandler) handleScaleResource(request *restful.Request, response *restful.Response) {
+// parseDrainOptions parses node drain options from gracePeriodSeconds, ignoreDaemonSets, force
+// and timeout query parameters.
+func parseDrainOptions(request *restful.Request) (node.DrainOptions, error) {
+	options := node.DrainOptions{
+		IgnoreDaemonSets: request.QueryParameter("ignoreDaemonSets") == "true",
+		Force:            request.QueryParameter("force") == "true",
+		Timeout:          node.DefaultDrainTimeout,
+	}
+
+	if value := request.QueryParameter("gracePeriodSeconds"); value != "" {
+		gracePeriod, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || gracePeriod < 0 {
+			return options, errorsK8s.NewBadRequest("Invalid gracePeriodSeconds " + value)
+		}
+		options.GracePeriodSeconds = &gracePeriod
+	}
+	if value := request.QueryParameter("timeout"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return options, errorsK8s.NewBadRequest("Invalid timeout " + value)
+		}
+		options.Timeout = timeout
+	}
+	return options, nil
+}
+
+func (apiHandler *APIHandler) handleGetNodeList(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	namespace := request.PathParameter("namespace")
-	kind := request.PathParameter("kind")
-	name := request.PathParameter("name")
-	count := request.QueryParameter("scaleBy")
-	replicaCountSpec, err := scaling.ScaleResource(k8sClient, kind, namespace, name, count)
+	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := node.GetNodeList(k8sClient, dataSelect, apiHandler.heapsterClient)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, replicaCountSpec)
+	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetReplicaCount(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetCluster(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	namespace := request.PathParameter("namespace")
-	kind := request.PathParameter("kind")
-	name := request.PathParameter("name")
-	scaleSpec, err := scaling.GetScaleSpec(k8sClient, kind, namespace, name)
+	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect.MetricQuery = dataselect.NoMetrics
+	result, err := cluster.GetCluster(k8sClient, dataSelect, &apiHandler.heapsterClient)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, scaleSpec)
+	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleDeployFromFile(request *restful.Request, response *restful.Response) {
-	deploymentSpec := new(deployment.AppDeploymentFromFileSpec)
-	if err := request.ReadEntity(deploymentSpec); err != nil {
+func (apiHandler *APIHandler) handleGetNodeDetail(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	isDeployed, err := deployment.DeployAppFromFile(deploymentSpec, deployment.CreateObjectFromInfoFn)
-	if !isDeployed {
+	name := request.PathParameter("name")
+	result, err := node.GetNodeDetail(k8sClient, apiHandler.heapsterClient, name)
+	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
-
-	errorMessage := ""
-	if err != nil {
-		errorMessage = err.Error()
-	}
-
-	response.WriteHeaderAndEntity(http.StatusCreated, deployment.AppDeploymentFromFileResponse{
-		Name:    deploymentSpec.Name,
-		Content: deploymentSpec.Content,
-		Error:   errorMessage,
-	})
+	result.Links = apiHandler.getLinks(api.ResourceKindNode, result.ObjectMeta)
+	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleNameValidity(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetNodeConditionTimeline(request *restful.Request,
+	response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	spec := new(validation.AppNameValiditySpec)
-	if err := request.ReadEntity(spec); err != nil {
-		handleInternalError(response, err)
-		return
-	}
-
-	validity, err := validation.ValidateAppName(spec, k8sClient)
+	name := request.PathParameter("name")
+	result, err := node.GetNodeConditionTimeline(k8sClient, name)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
-
-	response.WriteHeaderAndEntity(http.StatusOK, validity)
+	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (APIHandler *APIHandler) handleImageReferenceValidity(request *restful.Request, response *restful.Response) {
-	spec := new(validation.ImageReferenceValiditySpec)
-	if err := request.ReadEntity(spec); err != nil {
-		handleInternalError(response, err)
-		return
-	}
-
-	validity, err := validation.ValidateImageReference(spec)
+func (apiHandler *APIHandler) handleGetNodeLogFiles(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, validity)
-}
 
-func (apiHandler *APIHandler) handleProtocolValidity(request *restful.Request, response *restful.Response) {
-	spec := new(validation.ProtocolValiditySpec)
-	if err := request.ReadEntity(spec); err != nil {
+	name := request.PathParameter("name")
+	result, err := node.GetNodeLogFiles(k8sClient, name)
+	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, validation.ValidateProtocol(spec))
-}
-
-func (apiHandler *APIHandler) handleGetAvailableProcotols(request *restful.Request, response *restful.Response) {
-	response.WriteHeaderAndEntity(http.StatusOK, deployment.GetAvailableProtocols())
+	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetReplicationControllerList(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetNodeLog(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	namespace := parseNamespacePathParameter(request)
-	dataSelect := parseDataSelectPathParameter(request)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := replicationcontroller.GetReplicationControllerList(k8sClient, namespace, dataSelect, &apiHandler.heapsterClient)
+	name := request.PathParameter("name")
+	path := request.QueryParameter("path")
+	result, err := node.GetNodeLog(k8sClient, name, path, parseTailLinesParameter(request))
 	if err != nil {
 		handleInternalError(response, err)
 		return
@@ -965,17 +1645,16 @@ func (apiHandler *APIHandler) handleGetReplicationControllerList(request *restfu
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetWorkloads(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetNodeJournal(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	namespace := parseNamespacePathParameter(request)
-	dataSelect := parseDataSelectPathParameter(request)
-	dataSelect.MetricQuery = dataselect.NoMetrics
-	result, err := workload.GetWorkloads(k8sClient, apiHandler.heapsterClient, namespace, dataSelect)
+	name := request.PathParameter("name")
+	unit := request.PathParameter("unit")
+	result, err := node.GetNodeJournal(k8sClient, name, unit, parseTailLinesParameter(request))
 	if err != nil {
 		handleInternalError(response, err)
 		return
@@ -983,7 +1662,8 @@ func (apiHandler *APIHandler) handleGetWorkloads(request *restful.Request, respo
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleSearch(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetCertManagerCertificateList(request *restful.Request,
+	response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
@@ -992,8 +1672,7 @@ func (apiHandler *APIHandler) handleSearch(request *restful.Request, response *r
 
 	namespace := parseNamespacePathParameter(request)
 	dataSelect := parseDataSelectPathParameter(request)
-	dataSelect.MetricQuery = dataselect.NoMetrics
-	result, err := search.Search(k8sClient, apiHandler.heapsterClient, namespace, dataSelect)
+	result, err := certmanager.GetCertificateList(k8sClient, namespace, dataSelect)
 	if err != nil {
 		handleInternalError(response, err)
 		return
@@ -1001,16 +1680,17 @@ func (apiHandler *APIHandler) handleSearch(request *restful.Request, response *r
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetDiscovery(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetCertManagerCertificateDetail(request *restful.Request,
+	response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	namespace := parseNamespacePathParameter(request)
-	dsQuery := parseDataSelectPathParameter(request)
-	result, err := discovery.GetDiscovery(k8sClient, namespace, dsQuery)
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	result, err := certmanager.GetCertificateDetail(k8sClient, namespace, name)
 	if err != nil {
 		handleInternalError(response, err)
 		return
@@ -1018,24 +1698,25 @@ func (apiHandler *APIHandler) handleGetDiscovery(request *restful.Request, respo
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetConfig(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleRenewCertManagerCertificate(request *restful.Request,
+	response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	namespace := parseNamespacePathParameter(request)
-	dsQuery := parseDataSelectPathParameter(request)
-	result, err := config.GetConfig(k8sClient, namespace, dsQuery)
-	if err != nil {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	if err := certmanager.RenewCertificate(k8sClient, namespace, name); err != nil {
 		handleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	response.WriteHeader(http.StatusOK)
 }
 
-func (apiHandler *APIHandler) handleGetReplicaSets(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetCertManagerIssuerList(request *restful.Request,
+	response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
@@ -1044,8 +1725,7 @@ func (apiHandler *APIHandler) handleGetReplicaSets(request *restful.Request, res
 
 	namespace := parseNamespacePathParameter(request)
 	dataSelect := parseDataSelectPathParameter(request)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := replicaset.GetReplicaSetList(k8sClient, namespace, dataSelect, &apiHandler.heapsterClient)
+	result, err := certmanager.GetIssuerList(k8sClient, namespace, dataSelect)
 	if err != nil {
 		handleInternalError(response, err)
 		return
@@ -1053,7 +1733,8 @@ func (apiHandler *APIHandler) handleGetReplicaSets(request *restful.Request, res
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetReplicaSetDetail(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetCertManagerIssuerDetail(request *restful.Request,
+	response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
@@ -1061,56 +1742,53 @@ func (apiHandler *APIHandler) handleGetReplicaSetDetail(request *restful.Request
 	}
 
 	namespace := request.PathParameter("namespace")
-	replicaSet := request.PathParameter("replicaSet")
-	result, err := replicaset.GetReplicaSetDetail(k8sClient, apiHandler.heapsterClient, namespace, replicaSet)
-
+	name := request.PathParameter("name")
+	result, err := certmanager.GetIssuerDetail(k8sClient, namespace, name)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
-
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetReplicaSetPods(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetCertManagerClusterIssuerDetail(request *restful.Request,
+	response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	namespace := request.PathParameter("namespace")
-	replicaSet := request.PathParameter("replicaSet")
-	dataSelect := parseDataSelectPathParameter(request)
-	result, err := replicaset.GetReplicaSetPods(k8sClient, apiHandler.heapsterClient, dataSelect, replicaSet, namespace)
+	name := request.PathParameter("name")
+	result, err := certmanager.GetClusterIssuerDetail(k8sClient, name)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
-
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetReplicaSetServices(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetCertManagerOrderList(request *restful.Request,
+	response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	namespace := request.PathParameter("namespace")
-	replicaSet := request.PathParameter("replicaSet")
+	namespace := parseNamespacePathParameter(request)
 	dataSelect := parseDataSelectPathParameter(request)
-	result, err := replicaset.GetReplicaSetServices(k8sClient, dataSelect, namespace, replicaSet)
+	result, err := certmanager.GetOrderList(k8sClient, namespace, dataSelect,
+		request.QueryParameter("certificate"))
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
-
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetReplicaSetEvents(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetCertManagerOrderDetail(request *restful.Request,
+	response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
@@ -1118,29 +1796,24 @@ func (apiHandler *APIHandler) handleGetReplicaSetEvents(request *restful.Request
 	}
 
 	namespace := request.PathParameter("namespace")
-	name := request.PathParameter("replicaSet")
-	dataSelect := parseDataSelectPathParameter(request)
-	result, err := replicaset.GetReplicaSetEvents(k8sClient, dataSelect, namespace, name)
+	name := request.PathParameter("name")
+	result, err := certmanager.GetOrderDetail(k8sClient, namespace, name)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusOK, result)
-
 }
 
-func (apiHandler *APIHandler) handleGetPodEvents(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetNodePoolList(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	log.Println("Getting events related to a pod in namespace")
-	namespace := request.PathParameter("namespace")
-	name := request.PathParameter("pod")
 	dataSelect := parseDataSelectPathParameter(request)
-	result, err := pod.GetEventsForPod(k8sClient, dataSelect, namespace, name)
+	result, err := karpenter.GetNodePoolList(k8sClient, dataSelect)
 	if err != nil {
 		handleInternalError(response, err)
 		return
@@ -1148,17 +1821,15 @@ func (apiHandler *APIHandler) handleGetPodEvents(request *restful.Request, respo
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetDeployments(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetNodePoolDetail(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	namespace := parseNamespacePathParameter(request)
-	dataSelect := parseDataSelectPathParameter(request)
-	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := deployment.GetDeploymentList(k8sClient, namespace, dataSelect, &apiHandler.heapsterClient)
+	name := request.PathParameter("name")
+	result, err := karpenter.GetNodePoolDetail(k8sClient, name)
 	if err != nil {
 		handleInternalError(response, err)
 		return
@@ -1166,35 +1837,33 @@ func (apiHandler *APIHandler) handleGetDeployments(request *restful.Request, res
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetDeploymentDetail(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetNodeClaimList(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	namespace := request.PathParameter("namespace")
-	name := request.PathParameter("deployment")
-	result, err := deployment.GetDeploymentDetail(k8sClient, apiHandler.heapsterClient, namespace, name)
+	dataSelect := parseDataSelectPathParameter(request)
+	result, err := karpenter.GetNodeClaimList(k8sClient, dataSelect, request.QueryParameter("nodePool"))
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
-
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetDeploymentEvents(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetMachineList(request *restful.Request,
+	response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	namespace := request.PathParameter("namespace")
-	name := request.PathParameter("deployment")
+	namespace := parseNamespacePathParameter(request)
 	dataSelect := parseDataSelectPathParameter(request)
-	result, err := deployment.GetDeploymentEvents(k8sClient, dataSelect, namespace, name)
+	result, err := clusterapi.GetMachineList(k8sClient, namespace, dataSelect)
 	if err != nil {
 		handleInternalError(response, err)
 		return
@@ -1202,7 +1871,8 @@ func (apiHandler *APIHandler) handleGetDeploymentEvents(request *restful.Request
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetDeploymentOldReplicaSets(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetMachineDetail(request *restful.Request,
+	response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
@@ -1210,9 +1880,8 @@ func (apiHandler *APIHandler) handleGetDeploymentOldReplicaSets(request *restful
 	}
 
 	namespace := request.PathParameter("namespace")
-	name := request.PathParameter("deployment")
-	dataSelect := parseDataSelectPathParameter(request)
-	result, err := deployment.GetDeploymentOldReplicaSets(k8sClient, dataSelect, namespace, name)
+	name := request.PathParameter("name")
+	result, err := clusterapi.GetMachineDetail(k8sClient, namespace, name)
 	if err != nil {
 		handleInternalError(response, err)
 		return
@@ -1220,7 +1889,8 @@ func (apiHandler *APIHandler) handleGetDeploymentOldReplicaSets(request *restful
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetPods(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetMachineSetList(request *restful.Request,
+	response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
@@ -1229,8 +1899,7 @@ func (apiHandler *APIHandler) handleGetPods(request *restful.Request, response *
 
 	namespace := parseNamespacePathParameter(request)
 	dataSelect := parseDataSelectPathParameter(request)
-	dataSelect.MetricQuery = dataselect.StandardMetrics // download standard metrics - cpu, and memory - by default
-	result, err := pod.GetPodList(k8sClient, apiHandler.heapsterClient, namespace, dataSelect)
+	result, err := clusterapi.GetMachineSetList(k8sClient, namespace, dataSelect)
 	if err != nil {
 		handleInternalError(response, err)
 		return
@@ -1238,7 +1907,8 @@ func (apiHandler *APIHandler) handleGetPods(request *restful.Request, response *
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetPodDetail(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetMachineSetDetail(request *restful.Request,
+	response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
@@ -1246,8 +1916,8 @@ func (apiHandler *APIHandler) handleGetPodDetail(request *restful.Request, respo
 	}
 
 	namespace := request.PathParameter("namespace")
-	name := request.PathParameter("pod")
-	result, err := pod.GetPodDetail(k8sClient, apiHandler.heapsterClient, namespace, name)
+	name := request.PathParameter("name")
+	result, err := clusterapi.GetMachineSetDetail(k8sClient, namespace, name)
 	if err != nil {
 		handleInternalError(response, err)
 		return
@@ -1255,16 +1925,17 @@ func (apiHandler *APIHandler) handleGetPodDetail(request *restful.Request, respo
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetReplicationControllerDetail(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetMachineDeploymentList(request *restful.Request,
+	response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	namespace := request.PathParameter("namespace")
-	name := request.PathParameter("replicationController")
-	result, err := replicationcontroller.GetReplicationControllerDetail(k8sClient, apiHandler.heapsterClient, namespace, name)
+	namespace := parseNamespacePathParameter(request)
+	dataSelect := parseDataSelectPathParameter(request)
+	result, err := clusterapi.GetMachineDeploymentList(k8sClient, namespace, dataSelect)
 	if err != nil {
 		handleInternalError(response, err)
 		return
@@ -1272,7 +1943,8 @@ func (apiHandler *APIHandler) handleGetReplicationControllerDetail(request *rest
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleUpdateReplicasCount(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetMachineDeploymentDetail(request *restful.Request,
+	response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
@@ -1280,96 +1952,98 @@ func (apiHandler *APIHandler) handleUpdateReplicasCount(request *restful.Request
 	}
 
 	namespace := request.PathParameter("namespace")
-	name := request.PathParameter("replicationController")
-	spec := new(replicationcontroller.ReplicationControllerSpec)
-	if err := request.ReadEntity(spec); err != nil {
-		handleInternalError(response, err)
-		return
-	}
-
-	if err := replicationcontroller.UpdateReplicasCount(k8sClient, namespace, name, spec); err != nil {
+	name := request.PathParameter("name")
+	result, err := clusterapi.GetMachineDeploymentDetail(k8sClient, namespace, name)
+	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
-
-	response.WriteHeader(http.StatusAccepted)
+	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetResource(request *restful.Request, response *restful.Response) {
-	verber, err := apiHandler.manager.VerberClient(request)
+func (apiHandler *APIHandler) handleGetBackupList(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	kind := request.PathParameter("kind")
-	namespace, ok := request.PathParameters()["namespace"]
-	name := request.PathParameter("name")
-	result, err := verber.Get(kind, ok, namespace, name)
+	namespace := parseNamespacePathParameter(request)
+	dataSelect := parseDataSelectPathParameter(request)
+	result, err := velero.GetBackupList(k8sClient, namespace, dataSelect)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
-
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handlePutResource(
-	request *restful.Request, response *restful.Response) {
-	verber, err := apiHandler.manager.VerberClient(request)
+func (apiHandler *APIHandler) handleGetBackupDetail(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	kind := request.PathParameter("kind")
-	namespace, ok := request.PathParameters()["namespace"]
+	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("name")
-	putSpec := &runtime.Unknown{}
-	if err := request.ReadEntity(putSpec); err != nil {
+	result, err := velero.GetBackupDetail(k8sClient, namespace, name)
+	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
 
-	if err := verber.Put(kind, ok, namespace, name, putSpec); err != nil {
+func (apiHandler *APIHandler) handleGetRestoreList(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	response.WriteHeader(http.StatusCreated)
+	namespace := parseNamespacePathParameter(request)
+	dataSelect := parseDataSelectPathParameter(request)
+	result, err := velero.GetRestoreList(k8sClient, namespace, dataSelect)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleDeleteResource(
-	request *restful.Request, response *restful.Response) {
-	verber, err := apiHandler.manager.VerberClient(request)
+func (apiHandler *APIHandler) handleGetRestoreDetail(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	kind := request.PathParameter("kind")
-	namespace, ok := request.PathParameters()["namespace"]
+	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("name")
-
-	if err := verber.Delete(kind, ok, namespace, name); err != nil {
+	result, err := velero.GetRestoreDetail(k8sClient, namespace, name)
+	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
-
-	response.WriteHeader(http.StatusOK)
+	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetReplicationControllerPods(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetScheduleList(request *restful.Request,
+	response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	namespace := request.PathParameter("namespace")
-	rc := request.PathParameter("replicationController")
+	namespace := parseNamespacePathParameter(request)
 	dataSelect := parseDataSelectPathParameter(request)
-	result, err := replicationcontroller.GetReplicationControllerPods(k8sClient, apiHandler.heapsterClient, dataSelect, rc, namespace)
+	result, err := velero.GetScheduleList(k8sClient, namespace, dataSelect)
 	if err != nil {
 		handleInternalError(response, err)
 		return
@@ -1377,16 +2051,2033 @@ func (apiHandler *APIHandler) handleGetReplicationControllerPods(request *restfu
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleCreateNamespace(request *restful.Request, response *restful.Response) {
+func (apiHandler *APIHandler) handleGetScheduleDetail(request *restful.Request,
+	response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	namespaceSpec := new(ns.NamespaceSpec)
-	if err := request.ReadEntity(namespaceSpec); err != nil {
-		handleInternalError(response, err)
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	result, err := velero.GetScheduleDetail(k8sClient, namespace, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetBackupResources(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	result, err := velero.GetBackupResources(k8sClient, namespace, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleCreateBackup(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	spec := new(velero.BackupSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	result, err := velero.CreateBackup(k8sClient, spec)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusCreated, result)
+}
+
+func (apiHandler *APIHandler) handleCreateRestore(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	spec := new(velero.RestoreSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	result, err := velero.CreateRestore(k8sClient, spec)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusCreated, result)
+}
+
+func (apiHandler *APIHandler) handleTriggerSchedule(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	result, err := velero.TriggerSchedule(k8sClient, namespace, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusCreated, result)
+}
+
+func (apiHandler *APIHandler) handleGetClusterAutoscalerStatus(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	dataSelect := parseDataSelectPathParameter(request)
+	result, err := clusterautoscaler.GetClusterAutoscalerStatus(k8sClient, dataSelect)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetAdmissionReport(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	result, err := admission.GetAdmissionReport(k8sClient, namespace)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetAvailabilityReport(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	result, err := availability.GetAvailabilityReport(k8sClient, namespace)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetControlPlaneStatus(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	result, err := controlplane.GetControlPlaneStatus(k8sClient)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetDeprecationReport(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	result, err := deprecation.GetDeprecationReport(k8sClient)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetUpgradeReadiness(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	checks := make([]string, 0)
+	if value := request.QueryParameter("checks"); value != "" {
+		checks = strings.Split(value, ",")
+	}
+	result, err := upgrade.GetUpgradeReadiness(k8sClient, checks,
+		request.QueryParameter("targetRelease"))
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetPodsText(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect.MetricQuery = dataselect.NoMetrics
+	result, err := pod.GetPodList(k8sClient, apiHandler.heapsterClient, namespace, dataSelect)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	serveTextTable(response.ResponseWriter, toPodTextTable(result, time.Now()))
+}
+
+func (apiHandler *APIHandler) handleGetDeploymentsText(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect.MetricQuery = dataselect.NoMetrics
+	result, err := deployment.GetDeploymentList(k8sClient, namespace, dataSelect,
+		&apiHandler.heapsterClient)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	serveTextTable(response.ResponseWriter, toDeploymentTextTable(result, time.Now()))
+}
+
+func (apiHandler *APIHandler) handleGetNodeListText(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect.MetricQuery = dataselect.NoMetrics
+	result, err := node.GetNodeList(k8sClient, dataSelect, apiHandler.heapsterClient)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	serveTextTable(response.ResponseWriter, toNodeTextTable(result, time.Now()))
+}
+
+func (apiHandler *APIHandler) handleGetOverview(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	top := overview.DefaultTopConsumers
+	if value := request.QueryParameter("top"); value != "" {
+		top, err = strconv.Atoi(value)
+		if err != nil || top < 0 {
+			handleInternalError(response, errorsK8s.NewBadRequest("Invalid top "+value))
+			return
+		}
+	}
+	result, err := overview.GetOverview(k8sClient, top)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetNodeEvents(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	name := request.PathParameter("name")
+	dataSelect := parseDataSelectPathParameter(request)
+	result, err := event.GetNodeEvents(k8sClient, dataSelect, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetNodePods(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	name := request.PathParameter("name")
+	dataSelect := parseDataSelectPathParameter(request)
+	result, err := node.GetNodePods(k8sClient, apiHandler.heapsterClient, dataSelect, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleDeploy(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	appDeploymentSpec := new(deployment.AppDeploymentSpec)
+	if err := request.ReadEntity(appDeploymentSpec); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	kind := appDeploymentSpec.Kind
+	if kind == "" {
+		kind = deployment.AppKindDeployment
+	}
+	request.SetAttribute(activityResourceAttribute, activity.Resource{Kind: kind,
+		Namespace: appDeploymentSpec.Namespace, Name: appDeploymentSpec.Name})
+	if err := apiHandler.checkMaintenanceFreeze(request, appDeploymentSpec.Namespace); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	waitReady, err := parseWaitReady(request, appDeploymentSpec)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	objects, err := deployment.DeployApp(appDeploymentSpec, k8sClient)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	if client.IsPreview(request) {
+		// Nothing was created, return objects as they would be stored after admission.
+		response.WriteHeaderAndEntity(http.StatusOK, objects)
+		return
+	}
+	if waitReady > 0 && request.QueryParameter("async") == "true" {
+		// Respond right away, the client polls the operation instead.
+		op, err := apiHandler.operations.Start("waitReady", getViewUser(request).Name,
+			waitForDeploymentReady(k8sClient, appDeploymentSpec.Namespace, appDeploymentSpec.Name,
+				waitReady))
+		if err != nil {
+			handleInternalError(response, err)
+			return
+		}
+		response.WriteHeaderAndEntity(http.StatusAccepted, op)
+		return
+	}
+	if waitReady > 0 {
+		readiness, err := deployment.WaitForDeploymentReady(k8sClient, appDeploymentSpec.Namespace,
+			appDeploymentSpec.Name, waitReady, nil)
+		if err != nil {
+			handleInternalError(response, err)
+			return
+		}
+		if !readiness.Ready {
+			// The deployment is created, but did not become ready in time.
+			response.WriteHeaderAndEntity(http.StatusGatewayTimeout, readiness)
+			return
+		}
+	}
+	response.WriteHeaderAndEntity(http.StatusCreated, appDeploymentSpec)
+}
+
+// waitForDeploymentReady returns operation action waiting for given deployment to be ready. The
+// operation fails with readiness diagnostics as its result if the deployment is not ready in time.
+func waitForDeploymentReady(client kubernetes.Interface, namespace, name string,
+	timeout time.Duration) operation.Func {
+	return func(tracker operation.Tracker) (interface{}, error) {
+		tracker.Logf("Waiting %s for %s deployment in %s namespace to be ready", timeout, name,
+			namespace)
+		readiness, err := deployment.WaitForDeploymentReady(client, namespace, name, timeout, tracker)
+		if err != nil {
+			return nil, err
+		}
+		if !readiness.Ready {
+			return readiness, fmt.Errorf("Deployment %s is not ready after %s", name, timeout)
+		}
+		return readiness, nil
+	}
+}
+
+// handleGetActivity returns recent changes made by the user of the request. Users are known only
+// when the dashboard runs behind an authenticating proxy; activity of unknown users is empty.
+func (apiHandler *APIHandler) handleGetActivity(request *restful.Request, response *restful.Response) {
+	user := getViewUser(request).Name
+	result := activity.Activity{Actions: make([]activity.Action, 0),
+		Resources: make([]activity.TouchedResource, 0)}
+	if user != "" {
+		result = apiHandler.activityRecorder.GetActivity(user)
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// getSubscriptionUser returns user of given request who manages subscriptions, or writes error
+// response and returns an empty string if subscriptions are disabled or the user is unknown.
+// Users are known only when the dashboard runs behind an authenticating proxy.
+func (apiHandler *APIHandler) getSubscriptionUser(request *restful.Request,
+	response *restful.Response) string {
+	if apiHandler.subscriptions == nil {
+		handleInternalError(response, errorsK8s.NewServiceUnavailable("Subscriptions are disabled"))
+		return ""
+	}
+	user := getViewUser(request).Name
+	if user == "" {
+		handleInternalError(response, errorsK8s.NewBadRequest(
+			"Subscriptions are available only to users authenticated by a proxy"))
+	}
+	return user
+}
+
+func (apiHandler *APIHandler) handleGetSubscriptions(request *restful.Request, response *restful.Response) {
+	user := apiHandler.getSubscriptionUser(request, response)
+	if user == "" {
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, apiHandler.subscriptions.GetSubscriptions(user))
+}
+
+func (apiHandler *APIHandler) handleSubscribe(request *restful.Request, response *restful.Response) {
+	user := apiHandler.getSubscriptionUser(request, response)
+	if user == "" {
+		return
+	}
+
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	spec := new(subscription.SubscriptionSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	// Changes are observed with the dashboard's own credentials, so the user has to be allowed to
+	// watch subscribed resources.
+	if err := subscription.CheckAccess(k8sClient, *spec); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	result, err := apiHandler.subscriptions.Subscribe(user, *spec)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusCreated, result)
+}
+
+func (apiHandler *APIHandler) handleUnsubscribe(request *restful.Request, response *restful.Response) {
+	user := apiHandler.getSubscriptionUser(request, response)
+	if user == "" {
+		return
+	}
+
+	id := request.PathParameter("id")
+	if !apiHandler.subscriptions.Unsubscribe(user, id) {
+		handleInternalError(response, errorsK8s.NewNotFound(schema.GroupResource{Resource: "subscription"},
+			id))
+		return
+	}
+	response.WriteHeader(http.StatusOK)
+}
+
+// handleGetNotifications returns notifications of the user newer than the one with ID given by
+// since query parameter.
+func (apiHandler *APIHandler) handleGetNotifications(request *restful.Request, response *restful.Response) {
+	user := apiHandler.getSubscriptionUser(request, response)
+	if user == "" {
+		return
+	}
+
+	since := int64(0)
+	if value := request.QueryParameter("since"); value != "" {
+		var err error
+		if since, err = strconv.ParseInt(value, 10, 64); err != nil {
+			handleInternalError(response, errorsK8s.NewBadRequest("Invalid since parameter "+value))
+			return
+		}
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, apiHandler.subscriptions.GetNotifications(user, since))
+}
+
+// handleWatchNotifications pushes new notifications of the user over WebSocket, see
+// notificationhandler.go.
+func (apiHandler *APIHandler) handleWatchNotifications(request *restful.Request,
+	response *restful.Response) {
+	user := apiHandler.getSubscriptionUser(request, response)
+	if user == "" {
+		return
+	}
+
+	notifications, stop := apiHandler.subscriptions.Watch(user)
+	defer stop()
+	serveNotifications(response.ResponseWriter, request.Request, notifications)
+}
+
+func (apiHandler *APIHandler) handleGetOperation(request *restful.Request, response *restful.Response) {
+	op, ok := apiHandler.operations.Get(request.PathParameter("id"))
+	if !ok || !isOperationVisible(request, op) {
+		handleInternalError(response, errorsK8s.NewNotFound(schema.GroupResource{Resource: "operation"},
+			request.PathParameter("id")))
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, op)
+}
+
+func (apiHandler *APIHandler) handleCancelOperation(request *restful.Request, response *restful.Response) {
+	op, ok := apiHandler.operations.Get(request.PathParameter("id"))
+	if !ok || !isOperationVisible(request, op) {
+		handleInternalError(response, errorsK8s.NewNotFound(schema.GroupResource{Resource: "operation"},
+			request.PathParameter("id")))
+		return
+	}
+	op, _ = apiHandler.operations.Cancel(op.ID)
+	response.WriteHeaderAndEntity(http.StatusOK, op)
+}
+
+// isOperationVisible checks if the user of given request started given operation. Operations
+// started by unknown users are visible to anyone who knows their ID.
+func isOperationVisible(request *restful.Request, op *operation.Operation) bool {
+	return op.User == "" || op.User == getViewUser(request).Name
+}
+
+// parseWaitReady returns timeout of waiting for the deployment of given spec to become ready, set
+// by waitReady query parameter, e.g. 2m. Zero if not waiting.
+func parseWaitReady(request *restful.Request, spec *deployment.AppDeploymentSpec) (time.Duration,
+	error) {
+	value := request.QueryParameter("waitReady")
+	if value == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 || timeout > deployment.MaxWaitReadyTimeout {
+		return 0, errorsK8s.NewBadRequest(fmt.Sprintf("Invalid waitReady timeout %s, expected "+
+			"duration up to %s", value, deployment.MaxWaitReadyTimeout))
+	}
+	if spec.Kind != "" && spec.Kind != deployment.AppKindDeployment {
+		return 0, errorsK8s.NewBadRequest(fmt.Sprintf("Waiting for %s to be ready is not supported",
+			spec.Kind))
+	}
+	return timeout, nil
+}
+
+func (apiHandler *APIHandler) handleGetDeploymentChangelog(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	// Changelog is still useful without webhook calls, e.g. if the user cannot read settings.
+	records, err := apiHandler.settingsManager.GetWebhookRecords(k8sClient)
+	if err != nil {
+		log.Printf("Cannot get records of webhook calls: %s", err.Error())
+		records = make([]settings.WebhookRecord, 0)
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("deployment")
+	result, err := deployment.GetDeploymentChangelog(k8sClient, records, namespace, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// handleGetDeploymentRevisionDiff compares pod templates of revisions given by from and to query
+// parameters. The live pod template is compared if to is not given.
+func (apiHandler *APIHandler) handleGetDeploymentRevisionDiff(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	fromRevision, err := strconv.ParseInt(request.QueryParameter("from"), 10, 64)
+	if err != nil {
+		handleInternalError(response, errorsK8s.NewBadRequest("Invalid from revision: "+err.Error()))
+		return
+	}
+	toRevision := int64(deployment.LiveRevision)
+	if to := request.QueryParameter("to"); to != "" {
+		toRevision, err = strconv.ParseInt(to, 10, 64)
+		if err != nil {
+			handleInternalError(response, errorsK8s.NewBadRequest("Invalid to revision: "+err.Error()))
+			return
+		}
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("deployment")
+	result, err := deployment.GetDeploymentRevisionDiff(k8sClient, namespace, name, fromRevision,
+		toRevision)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleRollbackDeployment(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	spec := new(deployment.RollbackSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("deployment")
+	if err := deployment.RollbackDeployment(k8sClient, namespace, name, spec.Revision); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeader(http.StatusOK)
+}
+
+func (apiHandler *APIHandler) handlePauseDeployment(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("deployment")
+	if err := deployment.PauseDeployment(k8sClient, namespace, name); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeader(http.StatusOK)
+}
+
+func (apiHandler *APIHandler) handleResumeDeployment(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("deployment")
+	if err := deployment.ResumeDeployment(k8sClient, namespace, name); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeader(http.StatusOK)
+}
+
+func (apiHandler *APIHandler) handleGetDeploymentReplicasHistory(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("deployment")
+	result, err := deployment.GetDeploymentReplicasHistory(k8sClient, apiHandler.replicasRecorder,
+		namespace, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleRestartResource(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	kind := request.PathParameter("kind")
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	if err := restart.RestartResource(k8sClient, kind, namespace, name); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeader(http.StatusOK)
+}
+
+func (apiHandler *APIHandler) handleScaleResource(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	kind := request.PathParameter("kind")
+	name := request.PathParameter("name")
+	replicas, err := parseReplicas(request.QueryParameter("scaleBy"))
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	if request.QueryParameter("checkCapacity") == "true" {
+		capacity, err := validation.ValidateScaleCapacity(k8sClient, kind, namespace, name, replicas)
+		if err != nil {
+			handleInternalError(response, err)
+			return
+		}
+		if capacity.PendingReplicas > 0 {
+			handleInternalError(response, errorsK8s.NewConflict(schema.GroupResource{Resource: kind}, name,
+				fmt.Errorf("%d of replicas would remain pending due to insufficient capacity",
+					capacity.PendingReplicas)))
+			return
+		}
+	}
+
+	replicaCountSpec, err := scaling.ScaleResource(k8sClient, kind, namespace, name, replicas)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, replicaCountSpec)
+}
+
+func (apiHandler *APIHandler) handleGetScaleCapacity(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	kind := request.PathParameter("kind")
+	name := request.PathParameter("name")
+	replicas, err := parseReplicas(request.QueryParameter("scaleBy"))
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	capacity, err := validation.ValidateScaleCapacity(k8sClient, kind, namespace, name, replicas)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, capacity)
+}
+
+// parseReplicas parses number of replicas of scaleBy query parameter.
+func parseReplicas(count string) (int32, error) {
+	replicas, err := strconv.ParseInt(count, 10, 32)
+	if err != nil || replicas < 0 {
+		return 0, errorsK8s.NewBadRequest(fmt.Sprintf("Invalid number of replicas: %s", count))
+	}
+	return int32(replicas), nil
+}
+
+// getLinks returns links to external tools configured in settings for given resource, followed by
+// links from its links annotation. Settings are read with the dashboard's own credentials, since
+// they are shared by all users.
+func (apiHandler *APIHandler) getLinks(kind api.ResourceKind, objectMeta api.ObjectMeta) []link.Link {
+	annotationLinks := link.GetAnnotationLinks(objectMeta.Annotations)
+	s, err := apiHandler.sharedSettings.Get()
+	if err != nil {
+		log.Printf("Couldn't read settings: %s", err)
+		return annotationLinks
+	}
+
+	return append(link.GetLinks(s.LinkTemplates, kind, objectMeta.Namespace, objectMeta.Name),
+		annotationLinks...)
+}
+
+func (apiHandler *APIHandler) handleGetSettings(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	result, err := apiHandler.settingsManager.GetSettings(k8sClient)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	result.AccessMode = client.GetAccessMode(request.Request)
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleSaveSettings(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	s := new(settings.Settings)
+	if err := request.ReadEntity(s); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	s.AccessMode = ""
+
+	if err := apiHandler.settingsManager.SaveSettings(k8sClient, *s); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	apiHandler.sharedSettings.Invalidate()
+	response.WriteHeaderAndEntity(http.StatusOK, s)
+}
+
+func (apiHandler *APIHandler) handleGetViewList(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	result, err := settings.GetViewList(apiHandler.settingsManager, k8sClient, getViewUser(request))
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetView(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	token := request.PathParameter("token")
+	result, err := settings.GetViewByToken(apiHandler.settingsManager, k8sClient, token)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleSaveView(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	view := new(settings.View)
+	if err := request.ReadEntity(view); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	result, err := settings.SaveView(apiHandler.settingsManager, k8sClient, getViewUser(request), *view)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleDeleteView(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	token := request.PathParameter("token")
+	if err := settings.DeleteView(apiHandler.settingsManager, k8sClient, getViewUser(request), token); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeader(http.StatusOK)
+}
+
+func (apiHandler *APIHandler) handleCreateDeepLink(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	state := new(settings.QueryState)
+	if err := request.ReadEntity(state); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	result, err := settings.CreateDeepLink(apiHandler.settingsManager, k8sClient, *state)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusCreated, result)
+}
+
+func (apiHandler *APIHandler) handleResolveDeepLink(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	token := request.PathParameter("token")
+	result, err := settings.ResolveDeepLink(apiHandler.settingsManager, k8sClient, token)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetWebhookList(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	result, err := settings.GetWebhookList(apiHandler.settingsManager, k8sClient)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleCreateWebhook(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	webhook := new(settings.Webhook)
+	if err := request.ReadEntity(webhook); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	result, err := settings.CreateWebhook(apiHandler.settingsManager, k8sClient,
+		request.HeaderParameter(client.RequestUserHeader), *webhook)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusCreated, result)
+}
+
+func (apiHandler *APIHandler) handleDeleteWebhook(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	name := request.PathParameter("name")
+	if err := settings.DeleteWebhook(apiHandler.settingsManager, k8sClient, name); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeader(http.StatusOK)
+}
+
+func (apiHandler *APIHandler) handleGetWebhookRecords(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	result, err := apiHandler.settingsManager.GetWebhookRecords(k8sClient)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// webhookTokenHeader is the header of webhook calls that holds the webhook token.
+const webhookTokenHeader = "X-Webhook-Token"
+
+// webhookCall is the body of a webhook call.
+type webhookCall struct {
+	// Action to trigger, e.g. restart.
+	Action string `json:"action"`
+
+	// Container and its new image for setImage action. Container can be empty for deployments
+	// with a single container.
+	Container string `json:"container,omitempty"`
+	Image     string `json:"image,omitempty"`
+}
+
+// handleTriggerWebhook redeploys a deployment on behalf of an external system. The call is
+// authenticated by webhook token only, so the dashboard's own credentials are used for the change.
+func (apiHandler *APIHandler) handleTriggerWebhook(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(nil)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	call := new(webhookCall)
+	if err := request.ReadEntity(call); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("deployment")
+	webhook, err := settings.AuthorizeWebhook(apiHandler.settingsManager, k8sClient,
+		request.HeaderParameter(webhookTokenHeader), namespace, name, call.Action)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	switch call.Action {
+	case settings.WebhookActionRestart:
+		err = restart.RestartResource(k8sClient, api.ResourceKindDeployment, namespace, name)
+	case settings.WebhookActionSetImage:
+		err = deployment.SetDeploymentImage(k8sClient, namespace, name, call.Container, call.Image)
+	}
+
+	record := settings.WebhookRecord{
+		Webhook:    webhook.Name,
+		Namespace:  namespace,
+		Deployment: name,
+		Action:     call.Action,
+		Image:      call.Image,
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	recordErr := settings.RecordWebhookCall(apiHandler.settingsManager, k8sClient, &record)
+	if recordErr != nil {
+		log.Printf("Cannot store record of webhook call: %s", recordErr.Error())
+	}
+
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, record)
+}
+
+// getViewUser returns the user of saved views making given request, as passed on by authenticating
+// proxies. Name is empty without such a proxy.
+func getViewUser(request *restful.Request) settings.ViewUser {
+	user := settings.ViewUser{
+		Name:   request.HeaderParameter(client.RequestUserHeader),
+		Groups: make([]string, 0),
+	}
+	for _, group := range strings.Split(request.HeaderParameter(client.RequestGroupsHeader), ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			user.Groups = append(user.Groups, group)
+		}
+	}
+	return user
+}
+
+func (apiHandler *APIHandler) handleGetAlertList(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	if err := apiHandler.authorizeAlerts(request, namespace); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	result, err := alertmanager.GetAlertList(apiHandler.alertmanagerClient, namespace)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleCreateSilence(request *restful.Request, response *restful.Response) {
+	// Silences mute alerts of all namespaces.
+	if err := apiHandler.authorizeAlerts(request, ""); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	silence := new(alertmanager.Silence)
+	if err := request.ReadEntity(silence); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	result, err := alertmanager.CreateSilence(apiHandler.alertmanagerClient, *silence)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusCreated, result)
+}
+
+func (apiHandler *APIHandler) handleDeleteSilence(request *restful.Request, response *restful.Response) {
+	if err := apiHandler.authorizeAlerts(request, ""); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	id := request.PathParameter("id")
+	if err := alertmanager.DeleteSilence(apiHandler.alertmanagerClient, id); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeader(http.StatusOK)
+}
+
+// authorizeAlerts returns forbidden error unless the user can list pods in given namespace, all
+// namespaces if empty. Alerts are about workloads, so access to them follows access to pods.
+func (apiHandler *APIHandler) authorizeAlerts(request *restful.Request, namespace string) error {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		return err
+	}
+
+	allowed, err := canListPods(k8sClient, namespace)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return errorsK8s.NewForbidden(schema.GroupResource{Resource: "pods"}, "",
+			fmt.Errorf("access to alerts requires permission to list pods"))
+	}
+	return nil
+}
+
+// canListPods checks if the user of given client can list pods in given namespace, all namespaces
+// if empty.
+func canListPods(client kubernetes.Interface, namespace string) (bool, error) {
+	review, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(
+		&authorizationApi.SelfSubjectAccessReview{
+			Spec: authorizationApi.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationApi.ResourceAttributes{
+					Verb:      "list",
+					Resource:  "pods",
+					Namespace: namespace,
+				},
+			},
+		})
+	if err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
+func (apiHandler *APIHandler) handleGetNamespaceDisruptions(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	result, err := disruption.GetNamespaceDisruptions(k8sClient, namespace)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetWorkloadDisruptions(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	kind := request.PathParameter("kind")
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	result, err := disruption.GetWorkloadDisruptions(k8sClient, kind, namespace, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetSLOList(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	result, err := slo.GetSLOList(k8sClient, namespace)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetSLO(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	kind := request.PathParameter("kind")
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	result, err := slo.GetSLO(k8sClient, kind, namespace, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handlePutSLO(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	spec := new(slo.SLOSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	kind := request.PathParameter("kind")
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	result, err := slo.SetSLO(k8sClient, kind, namespace, name, *spec)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleDeleteSLO(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	kind := request.PathParameter("kind")
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	if err := slo.DeleteSLO(k8sClient, kind, namespace, name); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeader(http.StatusOK)
+}
+
+func (apiHandler *APIHandler) handleGetReplicaCount(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	kind := request.PathParameter("kind")
+	name := request.PathParameter("name")
+	scaleSpec, err := scaling.GetScaleSpec(k8sClient, kind, namespace, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, scaleSpec)
+}
+
+func (apiHandler *APIHandler) handleDeployFromFile(request *restful.Request, response *restful.Response) {
+	dashboardClient, userClient, err := apiHandler.getMaintenanceFreezeClients(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	deploymentSpec := new(deployment.AppDeploymentFromFileSpec)
+	if err := request.ReadEntity(deploymentSpec); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	isDeployed, err := deployment.DeployAppFromFile(deploymentSpec,
+		withMaintenanceFreeze(dashboardClient, userClient, deployment.CreateObjectFromInfoFn))
+	if !isDeployed {
+		handleInternalError(response, err)
+		return
+	}
+
+	errorMessage := ""
+	if err != nil {
+		errorMessage = err.Error()
+	}
+
+	response.WriteHeaderAndEntity(http.StatusCreated, deployment.AppDeploymentFromFileResponse{
+		Name:    deploymentSpec.Name,
+		Content: deploymentSpec.Content,
+		Error:   errorMessage,
+	})
+}
+
+// handleRenderDeploymentFile returns content of deployment file as it would be deployed, i.e.
+// rendered from a kustomization and with substituted parameters, so that it can be reviewed first.
+func (apiHandler *APIHandler) handleRenderDeploymentFile(request *restful.Request,
+	response *restful.Response) {
+	deploymentSpec := new(deployment.AppDeploymentFromFileSpec)
+	if err := request.ReadEntity(deploymentSpec); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	content, err := deployment.RenderDeploymentFile(deploymentSpec)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, deployment.AppDeploymentFromFileResponse{
+		Name:    deploymentSpec.Name,
+		Content: content,
+	})
+}
+
+func (apiHandler *APIHandler) handleDeployFromGit(request *restful.Request, response *restful.Response) {
+	dashboardClient, k8sClient, err := apiHandler.getMaintenanceFreezeClients(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	deploymentSpec := new(deployment.AppDeploymentFromGitSpec)
+	if err := request.ReadEntity(deploymentSpec); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	result, err := deployment.DeployAppFromGit(deploymentSpec, k8sClient, gitsource.ArchiveFetcher{},
+		withMaintenanceFreeze(dashboardClient, k8sClient, deployment.CreateObjectFromInfoFn))
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusCreated, result)
+}
+
+// handleRenderGitSource returns manifests fetched from Git as they would be deployed.
+func (apiHandler *APIHandler) handleRenderGitSource(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	deploymentSpec := new(deployment.AppDeploymentFromGitSpec)
+	if err := request.ReadEntity(deploymentSpec); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	fileSpec, commit, err := deployment.RenderGitSource(deploymentSpec, k8sClient,
+		gitsource.ArchiveFetcher{})
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, deployment.AppDeploymentFromGitResponse{
+		Commit:  commit,
+		Content: fileSpec.Content,
+	})
+}
+
+func (apiHandler *APIHandler) handleNameValidity(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	spec := new(validation.AppNameValiditySpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	validity, err := validation.ValidateAppName(spec, k8sClient)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, validity)
+}
+
+func (apiHandler *APIHandler) handleSchedulingValidity(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	spec := new(validation.SchedulingValiditySpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	validity, err := validation.ValidateScheduling(spec, k8sClient)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, validity)
+}
+
+func (APIHandler *APIHandler) handleImageReferenceValidity(request *restful.Request, response *restful.Response) {
+	spec := new(validation.ImageReferenceValiditySpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	validity, err := validation.ValidateImageReference(spec)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, validity)
+}
+
+func (apiHandler *APIHandler) handleProtocolValidity(request *restful.Request, response *restful.Response) {
+	spec := new(validation.ProtocolValiditySpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, validation.ValidateProtocol(spec))
+}
+
+func (apiHandler *APIHandler) handleGetAvailableProcotols(request *restful.Request, response *restful.Response) {
+	response.WriteHeaderAndEntity(http.StatusOK, deployment.GetAvailableProtocols())
+}
+
+func (apiHandler *APIHandler) handleGetReplicationControllerList(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := replicationcontroller.GetReplicationControllerList(k8sClient, namespace, dataSelect, &apiHandler.heapsterClient)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetWorkloads(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect.MetricQuery = dataselect.NoMetrics
+	result, err := workload.GetWorkloads(k8sClient, apiHandler.heapsterClient, namespace, dataSelect)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleSearch(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect.MetricQuery = dataselect.NoMetrics
+	dataSelect = search.NewSearchQuery(request.QueryParameter("q"), dataSelect)
+	result, err := search.Search(k8sClient, apiHandler.heapsterClient, namespace, dataSelect)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetLabelSelectorResult(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	selector := request.QueryParameter("selector")
+	result, err := labelselector.GetLabelSelectorResult(k8sClient, namespace, selector)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetDiscovery(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	dsQuery := parseDataSelectPathParameter(request)
+	result, err := discovery.GetDiscovery(k8sClient, namespace, dsQuery)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetConfig(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	dsQuery := parseDataSelectPathParameter(request)
+	result, err := config.GetConfig(k8sClient, namespace, dsQuery)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetReplicaSets(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := replicaset.GetReplicaSetList(k8sClient, namespace, dataSelect, &apiHandler.heapsterClient)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetReplicaSetDetail(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	replicaSet := request.PathParameter("replicaSet")
+	result, err := replicaset.GetReplicaSetDetail(k8sClient, apiHandler.heapsterClient, namespace, replicaSet)
+
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	result.Links = apiHandler.getLinks(api.ResourceKindReplicaSet, result.ObjectMeta)
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetReplicaSetPods(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	replicaSet := request.PathParameter("replicaSet")
+	dataSelect := parseDataSelectPathParameter(request)
+	result, err := replicaset.GetReplicaSetPods(k8sClient, apiHandler.heapsterClient, dataSelect, replicaSet, namespace)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetReplicaSetServices(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	replicaSet := request.PathParameter("replicaSet")
+	dataSelect := parseDataSelectPathParameter(request)
+	result, err := replicaset.GetReplicaSetServices(k8sClient, dataSelect, namespace, replicaSet)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetReplicaSetEvents(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("replicaSet")
+	dataSelect := parseDataSelectPathParameter(request)
+	result, err := replicaset.GetReplicaSetEvents(k8sClient, dataSelect, namespace, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+
+}
+
+func (apiHandler *APIHandler) handleGetPodEvents(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	log.Println("Getting events related to a pod in namespace")
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("pod")
+	dataSelect := parseDataSelectPathParameter(request)
+	result, err := pod.GetEventsForPod(k8sClient, dataSelect, namespace, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleEvictPod(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("pod")
+	result, err := pod.EvictPod(k8sClient, namespace, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	if !result.Evicted {
+		// Budgets refused the eviction, return them so that the user can see why.
+		response.WriteHeaderAndEntity(http.StatusTooManyRequests, result)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleForceDeletePod(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("pod")
+	if err := pod.ForceDeletePod(k8sClient, namespace, name); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeader(http.StatusOK)
+}
+
+func (apiHandler *APIHandler) handleGetImageDrift(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	source := request.PathParameter("source")
+	target := request.PathParameter("target")
+	result, err := deployment.GetImageDrift(k8sClient, source, target)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetDeployments(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := deployment.GetDeploymentList(k8sClient, namespace, dataSelect, &apiHandler.heapsterClient)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	badges := apiHandler.alertBadges.Get()
+	for i, item := range result.Deployments {
+		result.Deployments[i].Alerts = badges.Get(api.ResourceKindDeployment, item.ObjectMeta.Namespace, item.ObjectMeta.Name)
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetDeploymentDetail(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("deployment")
+	result, err := deployment.GetDeploymentDetail(k8sClient, apiHandler.heapsterClient, namespace, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	result.Links = apiHandler.getLinks(api.ResourceKindDeployment, result.ObjectMeta)
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetDeploymentEvents(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("deployment")
+	dataSelect := parseDataSelectPathParameter(request)
+	result, err := deployment.GetDeploymentEvents(k8sClient, dataSelect, namespace, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetDeploymentOldReplicaSets(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("deployment")
+	dataSelect := parseDataSelectPathParameter(request)
+	result, err := deployment.GetDeploymentOldReplicaSets(k8sClient, dataSelect, namespace, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetPods(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	dataSelect := parseDataSelectPathParameter(request)
+	dataSelect.MetricQuery = dataselect.StandardMetrics // download standard metrics - cpu, and memory - by default
+	result, err := pod.GetPodList(k8sClient, apiHandler.heapsterClient, namespace, dataSelect)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	badges := apiHandler.alertBadges.Get()
+	for i, item := range result.Pods {
+		result.Pods[i].Alerts = badges.Get(api.ResourceKindPod, item.ObjectMeta.Namespace, item.ObjectMeta.Name)
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetPodDetail(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("pod")
+	result, err := pod.GetPodDetail(k8sClient, apiHandler.heapsterClient, namespace, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	result.Links = apiHandler.getLinks(api.ResourceKindPod, result.ObjectMeta)
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetReplicationControllerDetail(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("replicationController")
+	result, err := replicationcontroller.GetReplicationControllerDetail(k8sClient, apiHandler.heapsterClient, namespace, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	result.Links = apiHandler.getLinks(api.ResourceKindReplicationController, result.ObjectMeta)
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetResource(request *restful.Request, response *restful.Response) {
+	verber, err := apiHandler.manager.VerberClient(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	kind := request.PathParameter("kind")
+	namespace, ok := request.PathParameters()["namespace"]
+	name := request.PathParameter("name")
+	result, err := verber.Get(kind, ok, namespace, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handlePutResource(
+	request *restful.Request, response *restful.Response) {
+	verber, err := apiHandler.manager.VerberClient(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	kind := request.PathParameter("kind")
+	namespace, ok := request.PathParameters()["namespace"]
+	name := request.PathParameter("name")
+	putSpec := &runtime.Unknown{}
+	if err := request.ReadEntity(putSpec); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	result, err := verber.Put(kind, ok, namespace, name, putSpec)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	if client.IsPreview(request) {
+		response.WriteHeaderAndEntity(http.StatusOK, result)
+		return
+	}
+	response.WriteHeader(http.StatusCreated)
+}
+
+func (apiHandler *APIHandler) handleDeleteResource(
+	request *restful.Request, response *restful.Response) {
+	verber, err := apiHandler.manager.VerberClient(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	kind := request.PathParameter("kind")
+	namespace, ok := request.PathParameters()["namespace"]
+	name := request.PathParameter("name")
+
+	if err := verber.Delete(kind, ok, namespace, name); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+}
+
+// handleDeleteResources starts operation deleting given resources and responds with it, polled by
+// the client for progress.
+func (apiHandler *APIHandler) handleDeleteResources(request *restful.Request,
+	response *restful.Response) {
+	verber, err := apiHandler.manager.VerberClient(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	spec := new(client.BulkDeleteSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	if len(spec.Resources) == 0 {
+		handleInternalError(response, errorsK8s.NewBadRequest("No resources to delete"))
+		return
+	}
+
+	op, err := apiHandler.operations.Start("bulkDelete", getViewUser(request).Name,
+		func(tracker operation.Tracker) (interface{}, error) {
+			return client.DeleteResources(verber, spec.Resources, tracker)
+		})
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusAccepted, op)
+}
+
+func (apiHandler *APIHandler) handleGetReplicationControllerPods(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	rc := request.PathParameter("replicationController")
+	dataSelect := parseDataSelectPathParameter(request)
+	result, err := replicationcontroller.GetReplicationControllerPods(k8sClient, apiHandler.heapsterClient, dataSelect, rc, namespace)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleCreateNamespace(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespaceSpec := new(ns.NamespaceSpec)
+	if err := request.ReadEntity(namespaceSpec); err != nil {
+		handleInternalError(response, err)
 		return
 	}
 	if err := ns.CreateNamespace(namespaceSpec, k8sClient); err != nil {
@@ -1445,6 +4136,45 @@ func (apiHandler *APIHandler) handleGetNamespaceEvents(request *restful.Request,
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
+func (apiHandler *APIHandler) handlePutNamespaceMaintenance(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	maintenance := new(common.NamespaceMaintenance)
+	if err := request.ReadEntity(maintenance); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	name := request.PathParameter("name")
+	result, err := ns.SetNamespaceMaintenance(k8sClient, name, *maintenance)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleDeleteNamespaceMaintenance(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	name := request.PathParameter("name")
+	if err := ns.DeleteNamespaceMaintenance(k8sClient, name); err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeader(http.StatusOK)
+}
+
 func (apiHandler *APIHandler) handleCreateImagePullSecret(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
@@ -1457,6 +4187,10 @@ func (apiHandler *APIHandler) handleCreateImagePullSecret(request *restful.Reque
 		handleInternalError(response, err)
 		return
 	}
+	if err := apiHandler.checkMaintenanceFreeze(request, spec.Namespace); err != nil {
+		handleInternalError(response, err)
+		return
+	}
 	result, err := secret.CreateSecret(k8sClient, spec)
 	if err != nil {
 		handleInternalError(response, err)
@@ -1499,6 +4233,40 @@ func (apiHandler *APIHandler) handleGetSecretList(request *restful.Request, resp
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
+func (apiHandler *APIHandler) handleGetExternalDNSRecordList(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	result, err := externaldns.GetRecordList(k8sClient, apiHandler.dnsResolver, namespace)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetCertificateReport(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	result, err := certificate.GetCertificateReport(k8sClient, namespace)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
 func (apiHandler *APIHandler) handleGetConfigMapList(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
@@ -1513,19 +4281,211 @@ func (apiHandler *APIHandler) handleGetConfigMapList(request *restful.Request, r
 		handleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
-}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetConfigMapDetail(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("configmap")
+	result, err := configmap.GetConfigMapDetail(k8sClient, namespace, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetPersistentVolumeList(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	dataSelect := parseDataSelectPathParameter(request)
+	result, err := persistentvolume.GetPersistentVolumeList(k8sClient, dataSelect)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetThirdPartyResource(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	dataSelect := parseDataSelectPathParameter(request)
+	result, err := thirdpartyresource.GetThirdPartyResourceList(k8sClient, dataSelect)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetThirdPartyResourceDetail(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	cfg, err := apiHandler.manager.Config(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	name := request.PathParameter("thirdpartyresource")
+	result, err := thirdpartyresource.GetThirdPartyResourceDetail(k8sClient, cfg, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetThirdPartyResourceObjects(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	cfg, err := apiHandler.manager.Config(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	name := request.PathParameter("thirdpartyresource")
+	dataSelect := parseDataSelectPathParameter(request)
+	result, err := thirdpartyresource.GetThirdPartyResourceObjects(k8sClient, cfg, dataSelect, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetPersistentVolumeDetail(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	name := request.PathParameter("persistentvolume")
+	result, err := persistentvolume.GetPersistentVolumeDetail(k8sClient, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetPersistentVolumeClaimList(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	dataSelect := parseDataSelectPathParameter(request)
+	result, err := persistentvolumeclaim.GetPersistentVolumeClaimList(k8sClient, namespace, dataSelect)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetPersistentVolumeClaimDetail(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	result, err := persistentvolumeclaim.GetPersistentVolumeClaimDetail(k8sClient, namespace, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleLogs(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	podID := request.PathParameter("pod")
+	containerID := request.PathParameter("container")
+
+	refTimestamp := request.QueryParameter("referenceTimestamp")
+	if refTimestamp == "" {
+		refTimestamp = logs.NewestTimestamp
+	}
+
+	refLineNum, err := strconv.Atoi(request.QueryParameter("referenceLineNum"))
+	if err != nil {
+		refLineNum = 0
+	}
+
+	offsetFrom, err1 := strconv.Atoi(request.QueryParameter("offsetFrom"))
+	offsetTo, err2 := strconv.Atoi(request.QueryParameter("offsetTo"))
+
+	var logSelector *logs.Selection
+	if pageToken := request.QueryParameter("pageToken"); pageToken != "" {
+		logSelector, err = logs.ParsePageToken(pageToken)
+		if err != nil {
+			handleInternalError(response, errorsK8s.NewBadRequest(err.Error()))
+			return
+		}
+	} else if aroundTimestamp := request.QueryParameter("aroundTimestamp"); aroundTimestamp != "" {
+		context, err := strconv.Atoi(request.QueryParameter("context"))
+		if err != nil || context < 0 {
+			context = logs.DefaultDisplayNumLogLines / 2
+		}
+		logSelector = logs.NewContextSelection(logs.LogTimestamp(aroundTimestamp), context)
+	} else if err1 != nil || err2 != nil {
+		logSelector = logs.DefaultSelection
+	} else {
+		logSelector = &logs.Selection{
+			ReferencePoint: logs.LogLineId{
+				LogTimestamp: logs.LogTimestamp(refTimestamp),
+				LineNum:      refLineNum,
+			},
+			OffsetFrom: offsetFrom,
+			OffsetTo:   offsetTo,
+		}
+	}
 
-func (apiHandler *APIHandler) handleGetConfigMapDetail(request *restful.Request, response *restful.Response) {
-	k8sClient, err := apiHandler.manager.Client(request)
+	logFilter, err := parseLogFilter(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	namespace := request.PathParameter("namespace")
-	name := request.PathParameter("configmap")
-	result, err := configmap.GetConfigMapDetail(k8sClient, namespace, name)
+	result, err := container.GetPodLogs(k8sClient, apiHandler.logSource, namespace, podID, containerID,
+		logSelector, logFilter)
 	if err != nil {
 		handleInternalError(response, err)
 		return
@@ -1533,135 +4493,168 @@ func (apiHandler *APIHandler) handleGetConfigMapDetail(request *restful.Request,
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetPersistentVolumeList(request *restful.Request, response *restful.Response) {
+// Handles following logs of a container. The connection is upgraded to WebSocket and new log lines
+// are pushed as JSON messages, see logstreamhandler.go.
+func (apiHandler *APIHandler) handleFollowLogs(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	dataSelect := parseDataSelectPathParameter(request)
-	result, err := persistentvolume.GetPersistentVolumeList(k8sClient, dataSelect)
+	logFilter, err := parseLogFilter(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
-}
 
-func (apiHandler *APIHandler) handleGetThirdPartyResource(request *restful.Request, response *restful.Response) {
-	k8sClient, err := apiHandler.manager.Client(request)
-	if err != nil {
-		handleInternalError(response, err)
-		return
+	tailLines, err := strconv.ParseInt(request.QueryParameter("tailLines"), 10, 64)
+	if err != nil || tailLines < 0 {
+		tailLines = int64(logs.DefaultDisplayNumLogLines)
 	}
 
-	dataSelect := parseDataSelectPathParameter(request)
-	result, err := thirdpartyresource.GetThirdPartyResourceList(k8sClient, dataSelect)
-	if err != nil {
-		handleInternalError(response, err)
-		return
-	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	namespace := request.PathParameter("namespace")
+	podID := request.PathParameter("pod")
+	containerID := request.PathParameter("container")
+	serveLogStream(response.ResponseWriter, request.Request,
+		func(stop <-chan struct{}, send func(logs.LogLine) error) error {
+			return container.FollowPodLogs(k8sClient, namespace, podID, containerID, tailLines,
+				logFilter, stop, send)
+		})
 }
 
-func (apiHandler *APIHandler) handleGetThirdPartyResourceDetail(request *restful.Request, response *restful.Response) {
-	k8sClient, err := apiHandler.manager.Client(request)
+// Handles watching changes of resources of a kind, in a namespace or in all namespaces. The
+// connection is upgraded to WebSocket and changes are pushed as JSON messages, see
+// watchhandler.go. Passing resourceVersion resumes the watch after the given version.
+func (apiHandler *APIHandler) handleWatch(request *restful.Request, response *restful.Response) {
+	verber, err := apiHandler.manager.VerberClient(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	cfg, err := apiHandler.manager.Config(request)
-	if err != nil {
-		handleInternalError(response, err)
-		return
+	kind := request.PathParameter("kind")
+	namespace := request.PathParameter("namespace")
+	resourceVersion := request.QueryParameter("resourceVersion")
+	// Without resource version current resources are listed and sent as added first. Watch starts
+	// from version of the list, objects added by the API server to a watch without version come in
+	// no particular order, so their versions cannot be used to resume it.
+	var objects []runtime.Object
+	if resourceVersion == "" {
+		objects, resourceVersion, err = verber.List(kind, namespace)
+		if err != nil {
+			handleInternalError(response, err)
+			return
+		}
 	}
-
-	name := request.PathParameter("thirdpartyresource")
-	result, err := thirdpartyresource.GetThirdPartyResourceDetail(k8sClient, cfg, name)
+	// The first watch is started before the upgrade, so that its errors, e.g. missing
+	// permissions, are returned with the right status code.
+	watcher, err := verber.Watch(kind, namespace, resourceVersion)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+
+	serveWatchStream(response.ResponseWriter, request.Request, objects, watcher, resourceVersion,
+		func(resourceVersion string) (watch.Interface, error) {
+			return verber.Watch(kind, namespace, resourceVersion)
+		})
 }
 
-func (apiHandler *APIHandler) handleGetThirdPartyResourceObjects(request *restful.Request, response *restful.Response) {
+// Handles downloading the whole log of a container as a file. The log is streamed to the response
+// as it is read, so that large logs are not held in memory.
+func (apiHandler *APIHandler) handleLogFile(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	cfg, err := apiHandler.manager.Config(request)
+	previous, sinceTime, err := parseLogFileOptions(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	name := request.PathParameter("thirdpartyresource")
-	dataSelect := parseDataSelectPathParameter(request)
-	result, err := thirdpartyresource.GetThirdPartyResourceObjects(k8sClient, cfg, dataSelect, name)
+	namespace := request.PathParameter("namespace")
+	podID := request.PathParameter("pod")
+	containerID := request.PathParameter("container")
+	logFile, err := container.GetPodLogFile(k8sClient, namespace, podID, containerID, previous,
+		sinceTime)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	defer logFile.Close()
+
+	fileName := podID + "-" + containerID
+	if previous {
+		fileName += "-previous"
+	}
+	serveLogFile(response.ResponseWriter, fileName+".log", logFile)
 }
 
-func (apiHandler *APIHandler) handleGetPersistentVolumeDetail(request *restful.Request, response *restful.Response) {
-	k8sClient, err := apiHandler.manager.Client(request)
+// parseLogFileOptions parses previous and sinceTime query parameters of log file download.
+func parseLogFileOptions(request *restful.Request) (bool, *metaV1.Time, error) {
+	previous := request.QueryParameter("previous") == "true"
+	since := request.QueryParameter("sinceTime")
+	if since == "" {
+		return previous, nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, since)
 	if err != nil {
-		handleInternalError(response, err)
-		return
+		return previous, nil, errorsK8s.NewBadRequest("Invalid sinceTime: " + err.Error())
 	}
+	return previous, &metaV1.Time{Time: parsed}, nil
+}
 
-	name := request.PathParameter("persistentvolume")
-	result, err := persistentvolume.GetPersistentVolumeDetail(k8sClient, name)
-	if err != nil {
-		handleInternalError(response, err)
-		return
+// serveLogFile writes given log as an attachment with given file name.
+func serveLogFile(w http.ResponseWriter, fileName string, logFile io.Reader) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, logFile); err != nil {
+		log.Printf("Couldn't send log file %s: %s", fileName, err)
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
-func (apiHandler *APIHandler) handleGetPersistentVolumeClaimList(request *restful.Request, response *restful.Response) {
+// Handles attaching to the process running in a container. The connection is upgraded to
+// WebSocket, see attachhandler.go for the framing.
+func (apiHandler *APIHandler) handleAttach(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	namespace := parseNamespacePathParameter(request)
-	dataSelect := parseDataSelectPathParameter(request)
-	result, err := persistentvolumeclaim.GetPersistentVolumeClaimList(k8sClient, namespace, dataSelect)
+	cfg, err := apiHandler.manager.Config(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+
+	namespace := request.PathParameter("namespace")
+	podID := request.PathParameter("pod")
+	containerID := request.PathParameter("container")
+	stdin := request.QueryParameter("stdin") != "false"
+	tty := request.QueryParameter("tty") == "true"
+
+	serveAttach(response.ResponseWriter, request.Request, stdin, tty,
+		func(streams container.AttachStreams) error {
+			return container.AttachToContainer(k8sClient, cfg, namespace, podID, containerID, streams)
+		})
 }
 
-func (apiHandler *APIHandler) handleGetPersistentVolumeClaimDetail(request *restful.Request, response *restful.Response) {
+// Handles executing a command, by default an interactive shell, in a container. The connection is
+// upgraded to WebSocket the same way as for attaching, see attachhandler.go for the framing.
+func (apiHandler *APIHandler) handleExec(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
 	}
 
-	namespace := request.PathParameter("namespace")
-	name := request.PathParameter("name")
-	result, err := persistentvolumeclaim.GetPersistentVolumeClaimDetail(k8sClient, namespace, name)
-	if err != nil {
-		handleInternalError(response, err)
-		return
-	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
-}
-
-func (apiHandler *APIHandler) handleLogs(request *restful.Request, response *restful.Response) {
-	k8sClient, err := apiHandler.manager.Client(request)
+	cfg, err := apiHandler.manager.Config(request)
 	if err != nil {
 		handleInternalError(response, err)
 		return
@@ -1670,35 +4663,27 @@ func (apiHandler *APIHandler) handleLogs(request *restful.Request, response *res
 	namespace := request.PathParameter("namespace")
 	podID := request.PathParameter("pod")
 	containerID := request.PathParameter("container")
+	command, stdin, tty := parseExecOptions(request)
 
-	refTimestamp := request.QueryParameter("referenceTimestamp")
-	if refTimestamp == "" {
-		refTimestamp = logs.NewestTimestamp
-	}
+	serveAttach(response.ResponseWriter, request.Request, stdin, tty,
+		func(streams container.AttachStreams) error {
+			return container.ExecInContainer(k8sClient, cfg, namespace, podID, containerID, command,
+				streams)
+		})
+}
 
-	refLineNum, err := strconv.Atoi(request.QueryParameter("referenceLineNum"))
+func (apiHandler *APIHandler) handleGetPodVolumePreview(request *restful.Request,
+	response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
-		refLineNum = 0
-	}
-
-	offsetFrom, err1 := strconv.Atoi(request.QueryParameter("offsetFrom"))
-	offsetTo, err2 := strconv.Atoi(request.QueryParameter("offsetTo"))
-
-	var logSelector *logs.Selection
-	if err1 != nil || err2 != nil {
-		logSelector = logs.DefaultSelection
-	} else {
-		logSelector = &logs.Selection{
-			ReferencePoint: logs.LogLineId{
-				LogTimestamp: logs.LogTimestamp(refTimestamp),
-				LineNum:      refLineNum,
-			},
-			OffsetFrom: offsetFrom,
-			OffsetTo:   offsetTo,
-		}
+		handleInternalError(response, err)
+		return
 	}
 
-	result, err := container.GetPodLogs(k8sClient, namespace, podID, containerID, logSelector)
+	namespace := request.PathParameter("namespace")
+	podName := request.PathParameter("pod")
+	volumeName := request.PathParameter("volume")
+	result, err := pod.GetPodVolumePreview(k8sClient, namespace, podName, volumeName)
 	if err != nil {
 		handleInternalError(response, err)
 		return
@@ -1706,6 +4691,13 @@ func (apiHandler *APIHandler) handleLogs(request *restful.Request, response *res
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
+// parseExecOptions parses executed command from repeated command query parameter, and stdin and
+// tty query parameters. Stdin and TTY are enabled unless set to false, as needed by a shell.
+func parseExecOptions(request *restful.Request) (command []string, stdin, tty bool) {
+	return request.Request.URL.Query()["command"], request.QueryParameter("stdin") != "false",
+		request.QueryParameter("tty") != "false"
+}
+
 func (apiHandler *APIHandler) handleGetPodContainers(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
@@ -1787,6 +4779,11 @@ func (apiHandler *APIHandler) handleGetDaemonSetList(request *restful.Request, r
 		handleInternalError(response, err)
 		return
 	}
+
+	badges := apiHandler.alertBadges.Get()
+	for i, item := range result.DaemonSets {
+		result.DaemonSets[i].Alerts = badges.Get(api.ResourceKindDaemonSet, item.ObjectMeta.Namespace, item.ObjectMeta.Name)
+	}
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
@@ -1805,6 +4802,7 @@ func (apiHandler *APIHandler) handleGetDaemonSetDetail(
 		handleInternalError(response, err)
 		return
 	}
+	result.Links = apiHandler.getLinks(api.ResourceKindDaemonSet, result.ObjectMeta)
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
@@ -1892,7 +4890,8 @@ func (apiHandler *APIHandler) handleGetHorizontalPodAutoscalerList(request *rest
 	}
 
 	namespace := parseNamespacePathParameter(request)
-	result, err := horizontalpodautoscaler.GetHorizontalPodAutoscalerList(k8sClient, namespace)
+	dataSelect := parseDataSelectPathParameter(request)
+	result, err := horizontalpodautoscaler.GetHorizontalPodAutoscalerList(k8sClient, namespace, dataSelect)
 	if err != nil {
 		handleInternalError(response, err)
 		return
@@ -1951,6 +4950,7 @@ func (apiHandler *APIHandler) handleGetJobDetail(request *restful.Request, respo
 		handleInternalError(response, err)
 		return
 	}
+	result.Links = apiHandler.getLinks(api.ResourceKindJob, result.ObjectMeta)
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
@@ -1972,6 +4972,127 @@ func (apiHandler *APIHandler) handleGetJobPods(request *restful.Request, respons
 	response.WriteHeaderAndEntity(http.StatusOK, result)
 }
 
+func (apiHandler *APIHandler) handleGetCronJobList(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	dataSelect := parseDataSelectPathParameter(request)
+	result, err := cronjob.GetCronJobList(k8sClient, namespace, dataSelect)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetCronJobDetail(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	result, err := cronjob.GetCronJobDetail(k8sClient, namespace, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	result.Links = apiHandler.getLinks(api.ResourceKindCronJob, result.ObjectMeta)
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleTriggerCronJob(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	result, err := cronjob.TriggerCronJob(k8sClient, namespace, name)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusCreated, result)
+}
+
+func (apiHandler *APIHandler) handleSuspendCronJob(request *restful.Request, response *restful.Response) {
+	apiHandler.setCronJobSuspend(request, response, true)
+}
+
+func (apiHandler *APIHandler) handleResumeCronJob(request *restful.Request, response *restful.Response) {
+	apiHandler.setCronJobSuspend(request, response, false)
+}
+
+func (apiHandler *APIHandler) setCronJobSuspend(request *restful.Request, response *restful.Response,
+	suspend bool) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	result, err := cronjob.SuspendCronJob(k8sClient, namespace, name, suspend)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetJobIndexedLogs(request *restful.Request, response *restful.Response) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("job")
+	result, err := job.GetJobIndexedLogs(k8sClient, namespace, name, parseTailLinesParameter(request))
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleSuspendJob(request *restful.Request, response *restful.Response) {
+	apiHandler.setJobSuspend(request, response, true)
+}
+
+func (apiHandler *APIHandler) handleResumeJob(request *restful.Request, response *restful.Response) {
+	apiHandler.setJobSuspend(request, response, false)
+}
+
+func (apiHandler *APIHandler) setJobSuspend(request *restful.Request, response *restful.Response,
+	suspend bool) {
+	k8sClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("job")
+	result, err := job.SuspendJob(k8sClient, namespace, name, suspend)
+	if err != nil {
+		handleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
 func (apiHandler *APIHandler) handleGetJobEvents(request *restful.Request, response *restful.Response) {
 	k8sClient, err := apiHandler.manager.Client(request)
 	if err != nil {
@@ -2038,23 +5159,100 @@ func parseNamespacePathParameter(request *restful.Request) *common.NamespaceQuer
 	return common.NewNamespaceQuery(nonEmptyNamespaces)
 }
 
+// parseLogFilter parses server side log filtering parameters. Levels and fields are comma separated
+// lists, fields in the key:value format. Time range is given as RFC3339 timestamps.
+func parseLogFilter(request *restful.Request) (*logs.LogFilter, error) {
+	filter := &logs.LogFilter{
+		Structured: request.QueryParameter("structured") == "true",
+		Search:     request.QueryParameter("search"),
+		Fields:     make(map[string]string),
+	}
+
+	if levels := request.QueryParameter("level"); levels != "" {
+		filter.Levels = strings.Split(levels, ",")
+	}
+
+	if fields := request.QueryParameter("field"); fields != "" {
+		for _, field := range strings.Split(fields, ",") {
+			parts := strings.SplitN(field, ":", 2)
+			if len(parts) != 2 {
+				return nil, errorsK8s.NewBadRequest("Invalid log field filter: " + field)
+			}
+			filter.Fields[parts[0]] = parts[1]
+		}
+	}
+
+	var err error
+	if since := request.QueryParameter("since"); since != "" {
+		if filter.Since, err = time.Parse(time.RFC3339Nano, since); err != nil {
+			return nil, errorsK8s.NewBadRequest("Invalid since time: " + err.Error())
+		}
+	}
+	if until := request.QueryParameter("until"); until != "" {
+		if filter.Until, err = time.Parse(time.RFC3339Nano, until); err != nil {
+			return nil, errorsK8s.NewBadRequest("Invalid until time: " + err.Error())
+		}
+	}
+
+	return filter, nil
+}
+
+// parseTailLinesParameter parses number of last log lines to return. Falls back to the default
+// number of log lines when the parameter is missing or invalid and is at most logs.MaxTailLines.
+func parseTailLinesParameter(request *restful.Request) int {
+	tailLines, err := strconv.Atoi(request.QueryParameter("tailLines"))
+	if err != nil || tailLines <= 0 {
+		return logs.DefaultDisplayNumLogLines
+	}
+	if tailLines > logs.MaxTailLines {
+		return logs.MaxTailLines
+	}
+	return tailLines
+}
+
+// wholeListPaths are paths of lists returned whole to requests without pagination parameters,
+// e.g. namespaces for the namespace selector. Other lists return the first page then.
+var wholeListPaths = map[string]bool{
+	"/api/v1/namespace":          true,
+	"/api/v1/thirdpartyresource": true,
+}
+
+// Parses pagination query parameters, with number of items per page within the limits from
+// settings. Missing parameters are replaced with defaults, except for lists in wholeListPaths.
 func parsePaginationPathParameter(request *restful.Request) *dataselect.PaginationQuery {
-	itemsPerPage, err := strconv.ParseInt(request.QueryParameter("itemsPerPage"), 10, 0)
-	if err != nil {
+	itemsPerPageParam := request.QueryParameter("itemsPerPage")
+	pageParam := request.QueryParameter("page")
+	if itemsPerPageParam == "" && pageParam == "" && wholeListPaths[request.Request.URL.Path] {
 		return dataselect.NoPagination
 	}
 
-	page, err := strconv.ParseInt(request.QueryParameter("page"), 10, 0)
+	limits := getPaginationLimits(request)
+	itemsPerPage, err := strconv.ParseInt(itemsPerPageParam, 10, 0)
 	if err != nil {
-		return dataselect.NoPagination
+		itemsPerPage = int64(limits.DefaultItemsPerPage)
+	}
+
+	page, err := strconv.ParseInt(pageParam, 10, 0)
+	if err != nil {
+		page = 1
 	}
 
 	// Frontend pages start from 1 and backend starts from 0
-	return dataselect.NewPaginationQuery(int(itemsPerPage), int(page-1))
+	return dataselect.NewPaginationQuery(int(itemsPerPage), int(page-1)).Limit(limits)
 }
 
 func parseFilterPathParameter(request *restful.Request) *dataselect.FilterQuery {
-	return dataselect.NewFilterQuery(strings.Split(request.QueryParameter("filterBy"), ","))
+	filterQuery := dataselect.NewFilterQuery(strings.Split(request.QueryParameter("filterBy"), ","))
+
+	// Invalid expressions are rejected by validateFilterExpressionFilter before.
+	expression, err := dataselect.ParseFilterExpression(request.QueryParameter(filterExpressionParameter))
+	if err != nil || len(expression.FilterByList) == 0 {
+		return filterQuery
+	}
+	return &dataselect.FilterQuery{
+		FilterByList: append(append([]dataselect.FilterBy{}, filterQuery.FilterByList...),
+			expression.FilterByList...),
+	}
 }
 
 // Parses query parameters of the request and returns a SortQuery object
@@ -2086,11 +5284,31 @@ func parseMetricPathParameter(request *restful.Request) *dataselect.MetricQuery
 
 }
 
+// Parses metric window query parameters of the request. Returns nil if none of them is set.
+// Invalid values are replaced by defaults.
+func parseMetricWindowPathParameter(request *restful.Request) *dataselect.MetricWindowQuery {
+	windowParam := request.QueryParameter("metricWindow")
+	maxDataPointsParam := request.QueryParameter("maxDataPoints")
+	downsamplingParam := request.QueryParameter("downsampling")
+	if windowParam == "" && maxDataPointsParam == "" && downsamplingParam == "" {
+		return nil
+	}
+
+	window, _ := time.ParseDuration(windowParam)
+	maxDataPoints, _ := strconv.Atoi(maxDataPointsParam)
+	return dataselect.NewMetricWindowQuery(window, maxDataPoints, metric.AggregationName(downsamplingParam))
+}
+
 // Parses query parameters of the request and returns a DataSelectQuery object
 func parseDataSelectPathParameter(request *restful.Request) *dataselect.DataSelectQuery {
 	paginationQuery := parsePaginationPathParameter(request)
 	sortQuery := parseSortPathParameter(request)
 	filterQuery := parseFilterPathParameter(request)
 	metricQuery := parseMetricPathParameter(request)
-	return dataselect.NewDataSelectQuery(paginationQuery, sortQuery, filterQuery, metricQuery)
+	dataSelect := dataselect.NewDataSelectQuery(paginationQuery, sortQuery, filterQuery, metricQuery)
+	dataSelect.MetricWindowQuery = parseMetricWindowPathParameter(request)
+	dataSelect.ItemMetrics = request.QueryParameter("itemMetrics") == "true"
+	dataSelect.MetricRollup = metric.AggregationName(request.QueryParameter(metricRollupParameter))
+	dataSelect.LabelSelector = request.QueryParameter("labelSelector")
+	return dataSelect
 }
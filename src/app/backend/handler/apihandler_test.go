@@ -1,19 +1,38 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"bytes"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/emicklei/go-restful"
 	"github.com/kubernetes/dashboard/src/app/backend/client"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/deployment"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/logs"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/node"
+	"github.com/kubernetes/dashboard/src/app/backend/settings"
+	errorsK8s "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/pkg/api/v1"
+	authorizationApi "k8s.io/client-go/pkg/apis/authorization/v1"
+	core "k8s.io/client-go/testing"
+	kubectlResource "k8s.io/kubernetes/pkg/kubectl/resource"
 )
 
 func TestCreateHTTPAPIHandler(t *testing.T) {
-	_, err := CreateHTTPAPIHandler(nil, client.NewClientManager("", "http://localhost:8080"))
+	_, err := CreateHTTPAPIHandler(nil, nil, client.NewClientManager("", "http://localhost:8080"),
+		settings.NewSettingsManager("kube-system"), nil, nil, nil, nil, nil, CORSConfig{})
 	if err != nil {
 		t.Fatal("CreateHTTPAPIHandler() cannot create HTTP API handler")
 	}
@@ -49,6 +68,83 @@ func TestShouldDoCsrfValidation(t *testing.T) {
 	}
 }
 
+func TestShouldCheckMaintenanceFreeze(t *testing.T) {
+	cases := []struct {
+		method, namespace string
+		expected          bool
+	}{
+		{"GET", "foo", false},
+		{"PUT", "foo", true},
+		{"DELETE", "foo", true},
+		{"POST", "", false},
+		{"PUT", "foo,bar", false},
+	}
+	for _, c := range cases {
+		request := restful.NewRequest(&http.Request{Method: c.method})
+		request.PathParameters()["namespace"] = c.namespace
+		actual := shouldCheckMaintenanceFreeze(request)
+		if actual != c.expected {
+			t.Errorf("shouldCheckMaintenanceFreeze(%#v, %#v) returns %#v, expected %#v", c.method,
+				c.namespace, actual, c.expected)
+		}
+	}
+}
+
+func TestWithMaintenanceFreeze(t *testing.T) {
+	frozen := &v1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "frozen", Annotations: map[string]string{
+		common.NamespaceMaintenanceAnnotationKey: `{"freeze":true}`}}}
+	open := &v1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "open"}}
+	// Fake access reviews are never allowed, so the user cannot update frozen namespaces.
+	dashboardClient := fake.NewSimpleClientset(frozen, open)
+	userClient := fake.NewSimpleClientset()
+
+	cases := []struct {
+		namespace       string
+		expectForbidden bool
+	}{
+		{"frozen", true},
+		{"open", false},
+		{"", false},
+	}
+	for _, c := range cases {
+		created := false
+		create := withMaintenanceFreeze(dashboardClient, userClient,
+			func(info *kubectlResource.Info) (bool, error) {
+				created = true
+				return true, nil
+			})
+
+		_, err := create(&kubectlResource.Info{Namespace: c.namespace, Name: "app"})
+		if errorsK8s.IsForbidden(err) != c.expectForbidden || created == c.expectForbidden {
+			t.Errorf("withMaintenanceFreeze() deploying into namespace %#v returns error %v and "+
+				"creates object: %v, expected forbidden: %v", c.namespace, err, created,
+				c.expectForbidden)
+		}
+	}
+}
+
+func TestCheckMaintenanceFreeze(t *testing.T) {
+	frozen := &v1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "frozen", Annotations: map[string]string{
+		common.NamespaceMaintenanceAnnotationKey: `{"freeze":true}`}}}
+	userClient := fake.NewSimpleClientset()
+
+	err := checkMaintenanceFreeze(fake.NewSimpleClientset(frozen), userClient, "frozen")
+	if !errorsK8s.IsForbidden(err) {
+		t.Errorf("checkMaintenanceFreeze() of frozen namespace returns %v, expected forbidden", err)
+	}
+
+	failingClient := fake.NewSimpleClientset()
+	failingClient.PrependReactor("get", "namespaces", func(action core.Action) (bool, runtime.Object, error) {
+		return true, &v1.Namespace{}, fmt.Errorf("connection refused")
+	})
+	err = checkMaintenanceFreeze(failingClient, userClient, "frozen")
+	if statusError, ok := err.(*errorsK8s.StatusError); !ok ||
+		statusError.Status().Code != http.StatusServiceUnavailable {
+		t.Errorf("checkMaintenanceFreeze() failing to read namespace returns %v, expected service "+
+			"unavailable", err)
+	}
+}
+
 func TestMapUrlToResource(t *testing.T) {
 	cases := []struct {
 		url, expected string
@@ -93,3 +189,373 @@ func TestFormatRequestLog(t *testing.T) {
 		}
 	}
 }
+
+func TestParseLogFilter(t *testing.T) {
+	cases := []struct {
+		query       string
+		expected    *logs.LogFilter
+		expectError bool
+	}{
+		{
+			"",
+			&logs.LogFilter{Fields: map[string]string{}},
+			false,
+		},
+		{
+			"structured=true&level=error,warn&field=app:foo,code:500&search=timeout" +
+				"&since=2017-05-05T10:00:00Z",
+			&logs.LogFilter{
+				Structured: true,
+				Levels:     []string{"error", "warn"},
+				Fields:     map[string]string{"app": "foo", "code": "500"},
+				Search:     "timeout",
+				Since:      time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC),
+			},
+			false,
+		},
+		{"field=app", nil, true},
+		{"until=yesterday", nil, true},
+	}
+	for _, c := range cases {
+		req, err := http.NewRequest("GET", "/api/v1/pod/default/foo/log?"+c.query, nil)
+		if err != nil {
+			t.Fatal("Cannot mockup request")
+		}
+
+		actual, err := parseLogFilter(restful.NewRequest(req))
+		if (err != nil) != c.expectError {
+			t.Errorf("parseLogFilter(%#v) returns error %v, expected error: %v", c.query, err, c.expectError)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("parseLogFilter(%#v) returns %#v, expected %#v", c.query, actual, c.expected)
+		}
+	}
+}
+
+func TestParsePaginationPathParameter(t *testing.T) {
+	limits := dataselect.PaginationLimits{DefaultItemsPerPage: 10, MaxItemsPerPage: 50}
+	cases := []struct {
+		url      string
+		expected *dataselect.PaginationQuery
+	}{
+		{"/api/v1/pod", dataselect.NewPaginationQuery(10, 0)},
+		{"/api/v1/pod?sortBy=d,name", dataselect.NewPaginationQuery(10, 0)},
+		{"/api/v1/pod?itemsPerPage=20&page=2", dataselect.NewPaginationQuery(20, 1)},
+		{"/api/v1/pod?itemsPerPage=1000000&page=1", dataselect.NewPaginationQuery(50, 0)},
+		{"/api/v1/pod?itemsPerPage=-1&page=1", dataselect.NewPaginationQuery(10, 0)},
+		{"/api/v1/pod?page=3", dataselect.NewPaginationQuery(10, 2)},
+		{"/api/v1/pod?itemsPerPage=5", dataselect.NewPaginationQuery(5, 0)},
+		{"/api/v1/namespace", dataselect.NoPagination},
+		{"/api/v1/namespace?sortBy=d,name", dataselect.NoPagination},
+		{"/api/v1/namespace?itemsPerPage=5", dataselect.NewPaginationQuery(5, 0)},
+	}
+	for _, c := range cases {
+		req, err := http.NewRequest("GET", c.url, nil)
+		if err != nil {
+			t.Fatal("Cannot mockup request")
+		}
+		request := restful.NewRequest(req)
+		request.SetAttribute(paginationLimitsAttribute, limits)
+
+		actual := parsePaginationPathParameter(request)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("parsePaginationPathParameter(%#v) returns %#v, expected %#v", c.url, actual, c.expected)
+		}
+	}
+}
+
+func TestParseMetricWindowPathParameter(t *testing.T) {
+	cases := []struct {
+		query    string
+		expected *dataselect.MetricWindowQuery
+	}{
+		{"", nil},
+		{"metricWindow=24h&maxDataPoints=100", dataselect.NewMetricWindowQuery(24*time.Hour, 100,
+			metric.MaxAggregation)},
+		{"maxDataPoints=50&downsampling=average", dataselect.NewMetricWindowQuery(0, 50,
+			metric.AverageAggregation)},
+		{"metricWindow=foo&maxDataPoints=-1&downsampling=bar", dataselect.NewMetricWindowQuery(0, 0,
+			metric.MaxAggregation)},
+	}
+	for _, c := range cases {
+		req, err := http.NewRequest("GET", "/api/v1/deployment?"+c.query, nil)
+		if err != nil {
+			t.Fatal("Cannot mockup request")
+		}
+
+		actual := parseMetricWindowPathParameter(restful.NewRequest(req))
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("parseMetricWindowPathParameter(%#v) returns %#v, expected %#v", c.query, actual,
+				c.expected)
+		}
+	}
+}
+
+func TestGetViewUser(t *testing.T) {
+	cases := []struct {
+		header   http.Header
+		expected settings.ViewUser
+	}{
+		{http.Header{}, settings.ViewUser{Groups: []string{}}},
+		{
+			http.Header{client.RequestUserHeader: {"jane"}, client.RequestGroupsHeader: {"ops, dev,"}},
+			settings.ViewUser{Name: "jane", Groups: []string{"ops", "dev"}},
+		},
+	}
+	for _, c := range cases {
+		actual := getViewUser(restful.NewRequest(&http.Request{Header: c.header}))
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("getViewUser(%#v) returns %#v, expected %#v", c.header, actual, c.expected)
+		}
+	}
+}
+
+func TestToPaginationLimits(t *testing.T) {
+	cases := []struct {
+		settings settings.Settings
+		expected dataselect.PaginationLimits
+	}{
+		{settings.Settings{}, dataselect.DefaultPaginationLimits},
+		{
+			settings.Settings{ItemsPerPage: 25, MaxItemsPerPage: 200},
+			dataselect.PaginationLimits{DefaultItemsPerPage: 25, MaxItemsPerPage: 200},
+		},
+		{
+			settings.Settings{ItemsPerPage: 500, MaxItemsPerPage: 200},
+			dataselect.PaginationLimits{DefaultItemsPerPage: 200, MaxItemsPerPage: 200},
+		},
+	}
+	for _, c := range cases {
+		actual := toPaginationLimits(c.settings)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("toPaginationLimits(%#v) returns %#v, expected %#v", c.settings, actual, c.expected)
+		}
+	}
+}
+
+func TestParseChurnWindow(t *testing.T) {
+	cases := []struct {
+		query       string
+		max         time.Duration
+		expected    time.Duration
+		expectError bool
+	}{
+		{"", time.Hour, 15 * time.Minute, false},
+		{"", 5 * time.Minute, 5 * time.Minute, false},
+		{"window=30m", time.Hour, 30 * time.Minute, false},
+		{"window=2h", time.Hour, 0, true},
+		{"window=-1m", time.Hour, 0, true},
+		{"window=long", time.Hour, 0, true},
+	}
+	for _, c := range cases {
+		req, err := http.NewRequest("GET", "/api/v1/endpointchurn?"+c.query, nil)
+		if err != nil {
+			t.Fatal("Cannot mockup request")
+		}
+
+		actual, err := parseChurnWindow(restful.NewRequest(req), c.max)
+		if (err != nil) != c.expectError {
+			t.Errorf("parseChurnWindow(%#v, %v) returns error %v, expected error: %v", c.query, c.max,
+				err, c.expectError)
+		}
+		if actual != c.expected {
+			t.Errorf("parseChurnWindow(%#v, %v) returns %v, expected %v", c.query, c.max, actual,
+				c.expected)
+		}
+	}
+}
+
+func TestParseWaitReady(t *testing.T) {
+	cases := []struct {
+		query       string
+		kind        string
+		expected    time.Duration
+		expectError bool
+	}{
+		{"", "", 0, false},
+		{"waitReady=2m", "", 2 * time.Minute, false},
+		{"waitReady=30s", deployment.AppKindDeployment, 30 * time.Second, false},
+		{"waitReady=soon", "", 0, true},
+		{"waitReady=1h", "", 0, true},
+		{"waitReady=2m", deployment.AppKindDaemonSet, 0, true},
+	}
+	for _, c := range cases {
+		req, err := http.NewRequest("POST", "/api/v1/appdeployment?"+c.query, nil)
+		if err != nil {
+			t.Fatal("Cannot mockup request")
+		}
+
+		actual, err := parseWaitReady(restful.NewRequest(req), &deployment.AppDeploymentSpec{Kind: c.kind})
+		if (err != nil) != c.expectError {
+			t.Errorf("parseWaitReady(%#v, %#v) returns error %v, expected error: %v", c.query, c.kind,
+				err, c.expectError)
+		}
+		if actual != c.expected {
+			t.Errorf("parseWaitReady(%#v, %#v) returns %v, expected %v", c.query, c.kind, actual,
+				c.expected)
+		}
+	}
+}
+
+func TestParseDrainOptions(t *testing.T) {
+	gracePeriod := int64(30)
+	cases := []struct {
+		query       string
+		expected    node.DrainOptions
+		expectError bool
+	}{
+		{"", node.DrainOptions{Timeout: node.DefaultDrainTimeout}, false},
+		{
+			"ignoreDaemonSets=true&force=true&gracePeriodSeconds=30&timeout=10m",
+			node.DrainOptions{IgnoreDaemonSets: true, Force: true, GracePeriodSeconds: &gracePeriod,
+				Timeout: 10 * time.Minute},
+			false,
+		},
+		{"gracePeriodSeconds=-1", node.DrainOptions{}, true},
+		{"timeout=soon", node.DrainOptions{}, true},
+	}
+	for _, c := range cases {
+		req, err := http.NewRequest("POST", "/api/v1/node/foo/drain?"+c.query, nil)
+		if err != nil {
+			t.Fatal("Cannot mockup request")
+		}
+
+		actual, err := parseDrainOptions(restful.NewRequest(req))
+		if (err != nil) != c.expectError {
+			t.Errorf("parseDrainOptions(%#v) returns error %v, expected error: %v", c.query, err,
+				c.expectError)
+		}
+		if !c.expectError && !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("parseDrainOptions(%#v) returns %#v, expected %#v", c.query, actual, c.expected)
+		}
+	}
+}
+
+func TestParseExecOptions(t *testing.T) {
+	cases := []struct {
+		query           string
+		expectedCommand []string
+		expectedStdin   bool
+		expectedTTY     bool
+	}{
+		{"", nil, true, true},
+		{"command=ls&command=-l&stdin=false&tty=false", []string{"ls", "-l"}, false, false},
+		{"command=top&tty=true", []string{"top"}, true, true},
+	}
+	for _, c := range cases {
+		req, err := http.NewRequest("GET", "/api/v1/pod/default/foo/exec/bar?"+c.query, nil)
+		if err != nil {
+			t.Fatal("Cannot mockup request")
+		}
+
+		command, stdin, tty := parseExecOptions(restful.NewRequest(req))
+		if !reflect.DeepEqual(command, c.expectedCommand) || stdin != c.expectedStdin ||
+			tty != c.expectedTTY {
+			t.Errorf("parseExecOptions(%#v) returns %#v, %t, %t, expected %#v, %t, %t", c.query,
+				command, stdin, tty, c.expectedCommand, c.expectedStdin, c.expectedTTY)
+		}
+	}
+}
+
+func TestParseTailLinesParameter(t *testing.T) {
+	cases := []struct {
+		query    string
+		expected int
+	}{
+		{"", logs.DefaultDisplayNumLogLines},
+		{"tailLines=-5", logs.DefaultDisplayNumLogLines},
+		{"tailLines=abc", logs.DefaultDisplayNumLogLines},
+		{"tailLines=20", 20},
+		{"tailLines=2000000000", logs.MaxTailLines},
+	}
+	for _, c := range cases {
+		req, err := http.NewRequest("GET", "/api/v1/node/foo/logs/file?"+c.query, nil)
+		if err != nil {
+			t.Fatal("Cannot mockup request")
+		}
+
+		actual := parseTailLinesParameter(restful.NewRequest(req))
+		if actual != c.expected {
+			t.Errorf("parseTailLinesParameter(%#v) == %d, expected %d", c.query, actual, c.expected)
+		}
+	}
+}
+
+func TestParseLogFileOptions(t *testing.T) {
+	since := metaV1.NewTime(time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC))
+	cases := []struct {
+		query             string
+		expectedPrevious  bool
+		expectedSinceTime *metaV1.Time
+		expectError       bool
+	}{
+		{"", false, nil, false},
+		{"previous=true&sinceTime=2017-05-05T10:00:00Z", true, &since, false},
+		{"sinceTime=yesterday", false, nil, true},
+	}
+	for _, c := range cases {
+		req, err := http.NewRequest("GET", "/api/v1/pod/default/foo/log/bar/file?"+c.query, nil)
+		if err != nil {
+			t.Fatal("Cannot mockup request")
+		}
+
+		previous, sinceTime, err := parseLogFileOptions(restful.NewRequest(req))
+		if (err != nil) != c.expectError {
+			t.Errorf("parseLogFileOptions(%#v) returns error %v, expected error: %v", c.query, err,
+				c.expectError)
+		}
+		if previous != c.expectedPrevious || !reflect.DeepEqual(sinceTime, c.expectedSinceTime) {
+			t.Errorf("parseLogFileOptions(%#v) returns %t, %#v, expected %t, %#v", c.query, previous,
+				sinceTime, c.expectedPrevious, c.expectedSinceTime)
+		}
+	}
+}
+
+func TestServeLogFile(t *testing.T) {
+	recorder := httptest.NewRecorder()
+
+	serveLogFile(recorder, "foo-bar.log", strings.NewReader("started\nstopped\n"))
+
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "started\nstopped\n" {
+		t.Errorf("serveLogFile() responds with %d: %#v, expected 200 with the log", recorder.Code,
+			recorder.Body.String())
+	}
+	expectedDisposition := `attachment; filename="foo-bar.log"`
+	if disposition := recorder.Header().Get("Content-Disposition"); disposition != expectedDisposition {
+		t.Errorf("serveLogFile() sets Content-Disposition %#v, expected %#v", disposition,
+			expectedDisposition)
+	}
+}
+
+func TestCanListPods(t *testing.T) {
+	// Fake access reviews are never allowed.
+	allowed, err := canListPods(fake.NewSimpleClientset(), "default")
+	if err != nil {
+		t.Fatalf("canListPods() returns error: %v", err)
+	}
+	if allowed {
+		t.Error("canListPods() returns true, expected false")
+	}
+
+	fakeClient := fake.NewSimpleClientset()
+	var attributes *authorizationApi.ResourceAttributes
+	fakeClient.PrependReactor("create", "selfsubjectaccessreviews",
+		func(action core.Action) (bool, runtime.Object, error) {
+			review := action.(core.CreateAction).GetObject().(*authorizationApi.SelfSubjectAccessReview)
+			attributes = review.Spec.ResourceAttributes
+			review.Status.Allowed = true
+			return true, review, nil
+		})
+
+	allowed, err = canListPods(fakeClient, "default")
+	if err != nil {
+		t.Fatalf("canListPods() returns error: %v", err)
+	}
+	if !allowed {
+		t.Error("canListPods() returns false, expected true")
+	}
+	expected := &authorizationApi.ResourceAttributes{Verb: "list", Resource: "pods", Namespace: "default"}
+	if !reflect.DeepEqual(attributes, expected) {
+		t.Errorf("canListPods() reviews \ngot %#v, \nexpected %#v", attributes, expected)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/kubernetes/dashboard/src/app/backend/client"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/container"
+	"golang.org/x/net/websocket"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// Channels of attach WebSocket messages. Every message is a binary frame starting with one byte
+// naming the channel, followed by raw payload. This is the same framing as used by channel.k8s.io
+// protocol of the API server, so arbitrary binary data can be sent in both directions.
+const (
+	attachStdinChannel byte = iota
+	attachStdoutChannel
+	attachStderrChannel
+	// Error channel carries the error message if attaching failed or the stream broke.
+	attachErrorChannel
+	// Resize channel carries JSON encoded terminal size, e.g. {"width":80,"height":24}.
+	attachResizeChannel
+)
+
+// frameConn is a message oriented connection. Separation is done to allow testing.
+type frameConn interface {
+	ReadFrame() ([]byte, error)
+	WriteFrame(frame []byte) error
+}
+
+// webSocketFrameConn is a frameConn exchanging binary WebSocket messages.
+type webSocketFrameConn struct {
+	ws *websocket.Conn
+}
+
+// ReadFrame reads next WebSocket message.
+func (c webSocketFrameConn) ReadFrame() ([]byte, error) {
+	var frame []byte
+	err := websocket.Message.Receive(c.ws, &frame)
+	return frame, err
+}
+
+// WriteFrame sends frame as binary WebSocket message.
+func (c webSocketFrameConn) WriteFrame(frame []byte) error {
+	return websocket.Message.Send(c.ws, frame)
+}
+
+// attachSession multiplexes container streams over a frameConn. It is the stdin reader and the
+// terminal size queue of the attached process.
+type attachSession struct {
+	conn      frameConn
+	writeLock sync.Mutex
+	pending   []byte
+	closed    bool
+	sizes     chan remotecommand.TerminalSize
+}
+
+func newAttachSession(conn frameConn) *attachSession {
+	return &attachSession{conn: conn, sizes: make(chan remotecommand.TerminalSize, 1)}
+}
+
+// Read reads stdin of the process from stdin frames. Resize frames are handed over to the size
+// queue, frames of other channels are ignored.
+func (s *attachSession) Read(p []byte) (int, error) {
+	for len(s.pending) == 0 {
+		if s.closed {
+			return 0, io.EOF
+		}
+		frame, err := s.conn.ReadFrame()
+		if err != nil {
+			s.closed = true
+			close(s.sizes)
+			return 0, io.EOF
+		}
+		if len(frame) == 0 {
+			continue
+		}
+
+		switch frame[0] {
+		case attachStdinChannel:
+			s.pending = frame[1:]
+		case attachResizeChannel:
+			size := remotecommand.TerminalSize{}
+			if err := json.Unmarshal(frame[1:], &size); err != nil {
+				log.Printf("Invalid terminal size received: %s", err)
+				continue
+			}
+			// Only the latest size matters, drop the one not consumed yet.
+			select {
+			case <-s.sizes:
+			default:
+			}
+			s.sizes <- size
+		}
+	}
+
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+// Next returns the next terminal size, or nil when the connection has been closed.
+func (s *attachSession) Next() *remotecommand.TerminalSize {
+	size, ok := <-s.sizes
+	if !ok {
+		return nil
+	}
+	return &size
+}
+
+// write sends payload prefixed with channel byte.
+func (s *attachSession) write(channel byte, payload []byte) error {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+	return s.conn.WriteFrame(append([]byte{channel}, payload...))
+}
+
+// channel returns writer of given output channel.
+func (s *attachSession) channel(channel byte) io.Writer {
+	return channelWriter{session: s, channel: channel}
+}
+
+type channelWriter struct {
+	session *attachSession
+	channel byte
+}
+
+// Write sends p as one frame of the channel.
+func (w channelWriter) Write(p []byte) (int, error) {
+	if err := w.session.write(w.channel, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// run attaches session streams using attach function and reports its error on the error channel.
+func (s *attachSession) run(stdin, tty bool, attach func(container.AttachStreams) error) {
+	streams := container.AttachStreams{
+		Stdout: s.channel(attachStdoutChannel),
+		Stderr: s.channel(attachStderrChannel),
+		TTY:    tty,
+		Sizes:  s,
+	}
+	if stdin {
+		streams.Stdin = s
+	} else {
+		// Keep reading the connection, so that resize frames and closing are noticed.
+		go io.Copy(ioutil.Discard, s)
+	}
+
+	if err := attach(streams); err != nil {
+		log.Printf("Attach session finished with error: %s", err)
+		s.write(attachErrorChannel, []byte(err.Error()))
+	}
+}
+
+// serveAttach upgrades the request to WebSocket connection and runs attach session over it.
+func serveAttach(w http.ResponseWriter, r *http.Request, stdin, tty bool,
+	attach func(container.AttachStreams) error) {
+	server := websocket.Server{
+		Handshake: checkSameOrigin,
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+			ws.PayloadType = websocket.BinaryFrame
+			newAttachSession(webSocketFrameConn{ws: ws}).run(stdin, tty, attach)
+		},
+	}
+	server.ServeHTTP(w, r)
+}
+
+// checkSameOrigin rejects WebSocket connections opened by pages of other origins, as browsers do
+// not apply same origin policy to WebSockets and the connection carries user's credentials.
+func checkSameOrigin(config *websocket.Config, r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+
+	originURL, err := url.Parse(origin)
+	if err != nil {
+		return err
+	}
+	// Behind kubectl proxy the page origin is the loopback address of kubectl, which can reach
+	// the API server on behalf of the local user anyway. It is only accepted if kubectl proxy mode
+	// is enabled.
+	if originURL.Host != r.Host && client.GetAccessMode(r) != client.AccessModeKubectlProxy {
+		return errors.New("Cross origin WebSocket connection rejected: " + origin)
+	}
+	config.Origin = originURL
+	return nil
+}
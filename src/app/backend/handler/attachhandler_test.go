@@ -0,0 +1,112 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/client"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/container"
+	"golang.org/x/net/websocket"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+type fakeFrameConn struct {
+	received [][]byte
+	sent     [][]byte
+}
+
+func (c *fakeFrameConn) ReadFrame() ([]byte, error) {
+	if len(c.received) == 0 {
+		return nil, errors.New("closed")
+	}
+	frame := c.received[0]
+	c.received = c.received[1:]
+	return frame, nil
+}
+
+func (c *fakeFrameConn) WriteFrame(frame []byte) error {
+	c.sent = append(c.sent, frame)
+	return nil
+}
+
+func TestAttachSession(t *testing.T) {
+	conn := &fakeFrameConn{received: [][]byte{
+		{attachStdinChannel, 0, 'a'},
+		append([]byte{attachResizeChannel}, `{"width":80}`...),
+		{},
+		{attachStdinChannel, 0xff},
+	}}
+	session := newAttachSession(conn)
+
+	var stdin []byte
+	var size *remotecommand.TerminalSize
+	session.run(true, true, func(streams container.AttachStreams) error {
+		stdin, _ = ioutil.ReadAll(streams.Stdin)
+		size = streams.Sizes.Next()
+		streams.Stdout.Write([]byte{0, 'b'})
+		return errors.New("exited")
+	})
+
+	if !reflect.DeepEqual(stdin, []byte{0, 'a', 0xff}) {
+		t.Errorf("Stdin read from session == \ngot: %#v, \nexpected %#v", stdin, []byte{0, 'a', 0xff})
+	}
+	if size == nil || size.Width != 80 {
+		t.Errorf("Terminal size read from session == \ngot: %#v, \nexpected width 80", size)
+	}
+	if next := session.Next(); next != nil {
+		t.Errorf("Terminal size of closed session == \ngot: %#v, \nexpected nil", next)
+	}
+
+	expected := [][]byte{
+		{attachStdoutChannel, 0, 'b'},
+		append([]byte{attachErrorChannel}, "exited"...),
+	}
+	if !reflect.DeepEqual(conn.sent, expected) {
+		t.Errorf("Frames sent by session == \ngot: %#v, \nexpected %#v", conn.sent, expected)
+	}
+}
+
+func TestCheckSameOrigin(t *testing.T) {
+	defer func() { client.KubectlProxyModeEnabled = false }()
+
+	cases := []struct {
+		origin           string
+		kubectlProxyMode bool
+		expectError      bool
+	}{
+		{"", false, false},
+		{"https://dashboard.example.com", false, false},
+		{"https://evil.example.com", false, true},
+		{"http://localhost:8001", false, true},
+		{"http://localhost:8001", true, false},
+		{"https://evil.example.com", true, true},
+	}
+	for _, c := range cases {
+		client.KubectlProxyModeEnabled = c.kubectlProxyMode
+		req, _ := http.NewRequest("GET", "https://dashboard.example.com/api/v1/pod/a/b/attach/c", nil)
+		req.Header.Set("Origin", c.origin)
+
+		err := checkSameOrigin(&websocket.Config{}, req)
+		if (err != nil) != c.expectError {
+			t.Errorf("checkSameOrigin() with origin %#v and kubectl proxy mode %v returns error %v, "+
+				"expected error: %v", c.origin, c.kubectlProxyMode, err, c.expectError)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"net/http"
+	"strings"
+)
+
+// NormalizeBaseHref returns given base href in the /prefix/ form. Empty base href is the root.
+func NormalizeBaseHref(baseHref string) string {
+	baseHref = strings.Trim(baseHref, "/")
+	if baseHref == "" {
+		return "/"
+	}
+	return "/" + baseHref + "/"
+}
+
+// CreateBaseHrefHandler serves given handler under given base href, so that the dashboard works
+// behind a proxy serving it under a path prefix. Requests without the prefix, e.g. from a proxy
+// rewriting paths, are served as they are. This applies to static files, API calls and WebSocket
+// endpoints alike, as the request is passed on with the prefix stripped off.
+func CreateBaseHrefHandler(baseHref string, handler http.Handler) http.Handler {
+	baseHref = NormalizeBaseHref(baseHref)
+	if baseHref == "/" {
+		return handler
+	}
+
+	prefix := strings.TrimSuffix(baseHref, "/")
+	stripped := http.StripPrefix(prefix, handler)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == prefix:
+			target := baseHref
+			if r.URL.RawQuery != "" {
+				target += "?" + r.URL.RawQuery
+			}
+			http.Redirect(w, r, target, http.StatusMovedPermanently)
+		case strings.HasPrefix(r.URL.Path, baseHref):
+			stripped.ServeHTTP(w, r)
+		default:
+			handler.ServeHTTP(w, r)
+		}
+	})
+}
@@ -0,0 +1,71 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNormalizeBaseHref(t *testing.T) {
+	cases := []struct {
+		baseHref, expected string
+	}{
+		{"", "/"},
+		{"/", "/"},
+		{"dashboard", "/dashboard/"},
+		{"/dashboard", "/dashboard/"},
+		{"/foo/dashboard/", "/foo/dashboard/"},
+	}
+	for _, c := range cases {
+		actual := NormalizeBaseHref(c.baseHref)
+		if actual != c.expected {
+			t.Errorf("NormalizeBaseHref(%#v) returns %#v, expected %#v", c.baseHref, actual, c.expected)
+		}
+	}
+}
+
+func TestCreateBaseHrefHandler(t *testing.T) {
+	cases := []struct {
+		baseHref, url    string
+		expectedCode     int
+		expectedPath     string
+		expectedLocation string
+	}{
+		{"/", "/api/v1/pod", http.StatusOK, "/api/v1/pod", ""},
+		{"/dashboard/", "/dashboard/api/v1/pod", http.StatusOK, "/api/v1/pod", ""},
+		{"/dashboard/", "/dashboard/", http.StatusOK, "/", ""},
+		{"/dashboard/", "/api/v1/pod", http.StatusOK, "/api/v1/pod", ""},
+		{"/dashboard/", "/dashboard?foo=bar", http.StatusMovedPermanently, "", "/dashboard/?foo=bar"},
+	}
+	for _, c := range cases {
+		servedPath := ""
+		handler := CreateBaseHrefHandler(c.baseHref, http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				servedPath = r.URL.Path
+			}))
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", c.url, nil))
+
+		if recorder.Code != c.expectedCode || servedPath != c.expectedPath ||
+			recorder.Header().Get("Location") != c.expectedLocation {
+			t.Errorf("CreateBaseHrefHandler(%#v) serving %#v returns code %d, path %#v, location %#v, "+
+				"expected %d, %#v, %#v", c.baseHref, c.url, recorder.Code, servedPath,
+				recorder.Header().Get("Location"), c.expectedCode, c.expectedPath, c.expectedLocation)
+		}
+	}
+}
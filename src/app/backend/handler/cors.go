@@ -0,0 +1,102 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+)
+
+// DefaultCORSAllowedHeaders are request headers external API consumers are allowed to send by
+// default.
+var DefaultCORSAllowedHeaders = []string{"Content-Type", "Accept", "Authorization", "X-CSRF-TOKEN"}
+
+// CORSOriginRegexpPrefix marks allowed origins that are regular expressions, e.g.
+// regexp:https://.*\.example\.com. The expression has to match the whole origin.
+const CORSOriginRegexpPrefix = "regexp:"
+
+// CORSConfig is a policy for cross-origin calls of the API from browsers.
+type CORSConfig struct {
+	// Origins allowed to call the API, e.g. https://tools.example.com. Entries are matched exactly
+	// unless prefixed with CORSOriginRegexpPrefix. CORS is disabled if empty. Use * to allow any
+	// origin, which is refused together with credentials.
+	AllowedOrigins []string
+
+	// Request headers allowed in cross-origin calls.
+	AllowedHeaders []string
+
+	// Whether cross-origin calls may include credentials, e.g. cookies and authorization headers.
+	AllowCredentials bool
+}
+
+// installCORSFilter installs CORS filter configured by given policy into given container. The
+// filter is installed on the container, so that preflight requests are answered before routes are
+// matched. Returns error if the policy is invalid.
+func installCORSFilter(container *restful.Container, config CORSConfig) error {
+	if len(config.AllowedOrigins) == 0 {
+		return nil
+	}
+
+	allowedOrigins, err := toCORSOriginPatterns(config.AllowedOrigins)
+	if err != nil {
+		return err
+	}
+	if len(allowedOrigins) == 0 && config.AllowCredentials {
+		return errors.New("CORS: allowing any origin is refused when credentials are allowed")
+	}
+
+	allowedHeaders := config.AllowedHeaders
+	if len(allowedHeaders) == 0 {
+		allowedHeaders = DefaultCORSAllowedHeaders
+	}
+
+	cors := restful.CrossOriginResourceSharing{
+		AllowedDomains: allowedOrigins,
+		AllowedHeaders: allowedHeaders,
+		CookiesAllowed: config.AllowCredentials,
+		Container:      container,
+	}
+	container.Filter(cors.Filter)
+	return nil
+}
+
+// toCORSOriginPatterns converts allowed origins to anchored regular expressions, as go-restful
+// matches origins that are not equal to any allowed domain by unanchored regular expressions.
+// Returns empty list, which allows all origins, if any of the origins is *.
+func toCORSOriginPatterns(origins []string) ([]string, error) {
+	patterns := make([]string, 0, len(origins))
+	for _, origin := range origins {
+		if origin == "*" {
+			// Empty list of domains allows all origins.
+			return []string{}, nil
+		}
+
+		var pattern string
+		if strings.HasPrefix(origin, CORSOriginRegexpPrefix) {
+			pattern = "^(?:" + strings.TrimPrefix(origin, CORSOriginRegexpPrefix) + ")$"
+		} else {
+			pattern = "^" + regexp.QuoteMeta(origin) + "$"
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("CORS: invalid allowed origin %q: %v", origin, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+)
+
+func TestInstallCORSFilter(t *testing.T) {
+	cases := []struct {
+		config                   CORSConfig
+		method, origin           string
+		expectedAllowOrigin      string
+		expectedAllowCredentials string
+	}{
+		{CORSConfig{}, "GET", "https://tools.example.com", "", ""},
+		{
+			CORSConfig{AllowedOrigins: []string{"https://tools.example.com"}},
+			"GET", "https://tools.example.com", "https://tools.example.com", "",
+		},
+		{
+			CORSConfig{AllowedOrigins: []string{"https://tools.example.com"}, AllowCredentials: true},
+			"OPTIONS", "https://tools.example.com", "https://tools.example.com", "true",
+		},
+		{
+			CORSConfig{AllowedOrigins: []string{"https://tools.example.com"}},
+			"OPTIONS", "https://evil.com", "", "",
+		},
+		{
+			CORSConfig{AllowedOrigins: []string{"https://tools.example.com"}},
+			"GET", "https://tools.example.com.evil.com", "", "",
+		},
+		{
+			CORSConfig{AllowedOrigins: []string{"https://tools.example.com"}},
+			"GET", "https://toolsXexample.com", "", "",
+		},
+		{
+			CORSConfig{AllowedOrigins: []string{`regexp:https://[a-z]+\.example\.com`}},
+			"GET", "https://tools.example.com", "https://tools.example.com", "",
+		},
+		{
+			CORSConfig{AllowedOrigins: []string{`regexp:https://[a-z]+\.example\.com`}},
+			"GET", "https://tools.example.com.evil.com", "", "",
+		},
+		{CORSConfig{AllowedOrigins: []string{"*"}}, "GET", "https://evil.com", "https://evil.com", ""},
+	}
+	for _, c := range cases {
+		container := restful.NewContainer()
+		if err := installCORSFilter(container, c.config); err != nil {
+			t.Fatalf("installCORSFilter(%#v) returns error: %v", c.config, err)
+		}
+		ws := new(restful.WebService)
+		ws.Path("/api/v1")
+		ws.Route(ws.GET("/pod").To(func(request *restful.Request, response *restful.Response) {}))
+		container.Add(ws)
+
+		request := httptest.NewRequest(c.method, "/api/v1/pod", nil)
+		request.Header.Set("Origin", c.origin)
+		if c.method == "OPTIONS" {
+			request.Header.Set("Access-Control-Request-Method", "GET")
+		}
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, request)
+
+		allowOrigin := recorder.Header().Get("Access-Control-Allow-Origin")
+		allowCredentials := recorder.Header().Get("Access-Control-Allow-Credentials")
+		if allowOrigin != c.expectedAllowOrigin || allowCredentials != c.expectedAllowCredentials ||
+			recorder.Code != http.StatusOK && c.expectedAllowOrigin != "" {
+			t.Errorf("installCORSFilter(%#v) serving %s from %s returns code %d, allow origin %#v, "+
+				"allow credentials %#v, expected %#v, %#v", c.config, c.method, c.origin, recorder.Code,
+				allowOrigin, allowCredentials, c.expectedAllowOrigin, c.expectedAllowCredentials)
+		}
+	}
+}
+
+func TestInstallCORSFilterInvalidConfig(t *testing.T) {
+	cases := []CORSConfig{
+		{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+		{AllowedOrigins: []string{"https://tools.example.com", "*"}, AllowCredentials: true},
+		{AllowedOrigins: []string{"regexp:https://(tools"}},
+	}
+	for _, c := range cases {
+		if err := installCORSFilter(restful.NewContainer(), c); err == nil {
+			t.Errorf("installCORSFilter(%#v) returns no error, expected error", c)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"expvar"
+	"log"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+	"github.com/kubernetes/dashboard/src/app/backend/client"
+	"k8s.io/client-go/kubernetes"
+	authorizationApi "k8s.io/client-go/pkg/apis/authorization/v1"
+)
+
+// CreateDebugHandler creates a handler serving pprof profiles under /debug/pprof/ and expvar
+// variables, e.g. memory statistics, under /debug/vars. Only users allowed to get the same path
+// from the API server, which by default are cluster admins, can access them. Requests have to
+// carry a bearer token, so that the dashboard's own service account is never used for the check.
+func CreateDebugHandler(manager client.ClientManager) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			http.Error(w, "Debug endpoints require a bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		k8sClient, err := manager.Client(restful.NewRequest(r))
+		if err != nil {
+			log.Print(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		allowed, err := isDebugAllowed(k8sClient, r.URL.Path)
+		if err != nil {
+			log.Print(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			http.Error(w, "Access to debug endpoints is forbidden", http.StatusForbidden)
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// isDebugAllowed checks if the user of given client is allowed to get given non-resource path
+// from the API server.
+func isDebugAllowed(client kubernetes.Interface, path string) (bool, error) {
+	review, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(
+		&authorizationApi.SelfSubjectAccessReview{
+			Spec: authorizationApi.SelfSubjectAccessReviewSpec{
+				NonResourceAttributes: &authorizationApi.NonResourceAttributes{
+					Path: path,
+					Verb: "get",
+				},
+			},
+		})
+	if err != nil {
+		return false, err
+	}
+
+	return review.Status.Allowed, nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/client"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCreateDebugHandler(t *testing.T) {
+	handler := CreateDebugHandler(client.NewClientManager("", "http://localhost:8080"))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/debug/vars", nil))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("CreateDebugHandler() serving request without token returns code %d, expected %d",
+			recorder.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestIsDebugAllowed(t *testing.T) {
+	// Fake access reviews are never allowed.
+	allowed, err := isDebugAllowed(fake.NewSimpleClientset(), "/debug/vars")
+	if err != nil {
+		t.Fatalf("isDebugAllowed() returns error: %v", err)
+	}
+	if allowed {
+		t.Error("isDebugAllowed() returns true, expected false")
+	}
+}
@@ -15,6 +15,8 @@
 package handler
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -24,18 +26,52 @@ import (
 	"time"
 
 	"github.com/emicklei/go-restful"
+	"github.com/kubernetes/dashboard/src/app/backend/activity"
 	"github.com/kubernetes/dashboard/src/app/backend/client"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
+	ns "github.com/kubernetes/dashboard/src/app/backend/resource/namespace"
 	"golang.org/x/net/xsrftoken"
+	errorsK8s "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/kubernetes"
+	kubectlResource "k8s.io/kubernetes/pkg/kubectl/resource"
 )
 
 // InstallFilters installs defined filter for given web service
 func InstallFilters(ws *restful.WebService, manager client.ClientManager) {
+	ws.Filter(requestIDFilter)
 	ws.Filter(requestAndResponseLogger)
 	ws.Filter(metricsFilter)
+	ws.Filter(validateMetricRollupFilter)
+	ws.Filter(validateFilterExpressionFilter)
 	ws.Filter(validateXSRFFilter(manager.CSRFKey()))
 }
 
+// requestIDFilter is a web-service filter function that makes sure every request has an ID. The
+// ID is passed on to the API server in the user agent and returned in the response headers.
+func requestIDFilter(request *restful.Request, response *restful.Response,
+	chain *restful.FilterChain) {
+	requestID := request.HeaderParameter(client.RequestIDHeader)
+	if requestID == "" {
+		requestID = generateRequestID()
+		request.Request.Header.Set(client.RequestIDHeader, requestID)
+	}
+	response.AddHeader(client.RequestIDHeader, requestID)
+	chain.ProcessFilter(request, response)
+}
+
+// generateRequestID returns random request ID.
+func generateRequestID() string {
+	bytes := make([]byte, 8)
+	if _, err := rand.Read(bytes); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(bytes)
+}
+
 // logRequestAndReponse is a web-service filter function used for request and response logging.
 func requestAndResponseLogger(request *restful.Request, response *restful.Response,
 	chain *restful.FilterChain) {
@@ -120,6 +156,12 @@ func shouldDoCsrfValidation(req *restful.Request) bool {
 		return false
 	}
 
+	// Webhook calls come from external systems and are authenticated by webhook tokens instead of
+	// browser sessions.
+	if strings.HasPrefix(req.SelectedRoutePath(), "/api/v1/webhook/trigger/") {
+		return false
+	}
+
 	return true
 }
 
@@ -132,3 +174,213 @@ func mapUrlToResource(url string) *string {
 	}
 	return &parts[3]
 }
+
+// metricRollupParameter is the name of query parameter that selects aggregation of pod metrics
+// rolled up to controllers.
+const metricRollupParameter = "metricRollup"
+
+// validateMetricRollupFilter is a web-service filter function that rejects requests with
+// unsupported metric rollup.
+func validateMetricRollupFilter(request *restful.Request, response *restful.Response,
+	chain *restful.FilterChain) {
+	rollup := metric.AggregationName(request.QueryParameter(metricRollupParameter))
+	if !dataselect.IsValidMetricRollup(rollup) {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest,
+			fmt.Sprintf("Unsupported metric rollup %s\n", rollup))
+		return
+	}
+	chain.ProcessFilter(request, response)
+}
+
+// filterExpressionParameter is the name of query parameter with filter expression, e.g.
+// name~"^api-",status=Failed.
+const filterExpressionParameter = "filter"
+
+// validateFilterExpressionFilter is a web-service filter function that rejects requests with
+// invalid filter expression.
+func validateFilterExpressionFilter(request *restful.Request, response *restful.Response,
+	chain *restful.FilterChain) {
+	if _, err := dataselect.ParseFilterExpression(request.QueryParameter(filterExpressionParameter)); err != nil {
+		response.AddHeader("Content-Type", "text/plain")
+		response.WriteErrorString(http.StatusBadRequest,
+			fmt.Sprintf("Invalid filter expression: %s\n", err))
+		return
+	}
+	chain.ProcessFilter(request, response)
+}
+
+// maintenanceFreezeFilter is a web-service filter function that rejects changes to namespaces
+// frozen for maintenance, unless the user is allowed to update the namespace itself. Maintenance is
+// read with the dashboard's own client, the user's client is only used to review the update
+// permission. Changes are rejected if the freeze cannot be checked.
+func (apiHandler *APIHandler) maintenanceFreezeFilter(request *restful.Request,
+	response *restful.Response, chain *restful.FilterChain) {
+	if shouldCheckMaintenanceFreeze(request) {
+		if err := apiHandler.checkMaintenanceFreeze(request,
+			request.PathParameter("namespace")); err != nil {
+			handleInternalError(response, err)
+			return
+		}
+	}
+	chain.ProcessFilter(request, response)
+}
+
+// checkMaintenanceFreeze returns error if changes to given namespace are blocked for the user of
+// given request. Handlers of changes that take the namespace from request body, e.g. deploys, call
+// it themselves, as maintenanceFreezeFilter only sees the namespace path parameter.
+func (apiHandler *APIHandler) checkMaintenanceFreeze(request *restful.Request,
+	namespace string) error {
+	dashboardClient, userClient, err := apiHandler.getMaintenanceFreezeClients(request)
+	if err != nil {
+		return newMaintenanceFreezeCheckError(namespace, err)
+	}
+	return checkMaintenanceFreeze(dashboardClient, userClient, namespace)
+}
+
+// getMaintenanceFreezeClients returns the dashboard's own client and client of the user of given
+// request.
+func (apiHandler *APIHandler) getMaintenanceFreezeClients(request *restful.Request) (
+	kubernetes.Interface, kubernetes.Interface, error) {
+	dashboardClient, err := apiHandler.manager.Client(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	userClient, err := apiHandler.manager.Client(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dashboardClient, userClient, nil
+}
+
+// checkMaintenanceFreeze returns forbidden error if given namespace is frozen for the user of
+// given user client and service unavailable error if the freeze cannot be checked. Objects without
+// namespace, e.g. cluster scoped ones, are not frozen.
+func checkMaintenanceFreeze(dashboardClient, userClient kubernetes.Interface, namespace string) error {
+	if namespace == "" {
+		return nil
+	}
+	frozen, err := ns.IsFrozen(dashboardClient, userClient, namespace)
+	if err != nil {
+		return newMaintenanceFreezeCheckError(namespace, err)
+	}
+	if frozen {
+		return errorsK8s.NewForbidden(schema.GroupResource{Resource: "namespaces"}, namespace,
+			fmt.Errorf("namespace %s is frozen for maintenance", namespace))
+	}
+	return nil
+}
+
+func newMaintenanceFreezeCheckError(namespace string, err error) error {
+	return errorsK8s.NewServiceUnavailable(fmt.Sprintf(
+		"Cannot check maintenance freeze of namespace %s: %s", namespace, err))
+}
+
+// withMaintenanceFreeze wraps given function creating objects of deployed files, so that objects
+// in namespaces frozen for the user are not created.
+func withMaintenanceFreeze(dashboardClient, userClient kubernetes.Interface,
+	create func(info *kubectlResource.Info) (bool, error)) func(info *kubectlResource.Info) (bool, error) {
+	return func(info *kubectlResource.Info) (bool, error) {
+		if err := checkMaintenanceFreeze(dashboardClient, userClient, info.Namespace); err != nil {
+			return false, err
+		}
+		return create(info)
+	}
+}
+
+// Changes are all non-GET requests targeting a single namespace given in the path.
+func shouldCheckMaintenanceFreeze(request *restful.Request) bool {
+	namespace := request.PathParameter("namespace")
+	return request.Request.Method != http.MethodGet && namespace != "" &&
+		!strings.Contains(namespace, ",")
+}
+
+// previewRoutes are routes of changes that can be previewed. Their handlers return objects as they
+// would be stored, without persisting them.
+var previewRoutes = map[string]bool{
+	"POST /api/v1/appdeployment":                                true,
+	"PUT /api/v1/_raw/{kind}/namespace/{namespace}/name/{name}": true,
+	"PUT /api/v1/_raw/{kind}/name/{name}":                       true,
+}
+
+// previewFilter is a web-service filter function that rejects preview requests, which could not
+// be fulfilled by a server-side dry run. This prevents changes meant as previews to be persisted.
+func (apiHandler *APIHandler) previewFilter(request *restful.Request,
+	response *restful.Response, chain *restful.FilterChain) {
+	if client.IsPreview(request) {
+		if !previewRoutes[request.Request.Method+" "+request.SelectedRoutePath()] {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest, "Preview is not supported for this request\n")
+			return
+		}
+
+		k8sClient, err := apiHandler.manager.Client(request)
+		if err != nil {
+			handleInternalError(response, err)
+			return
+		}
+		supported, err := client.IsDryRunSupported(k8sClient.Discovery())
+		if err != nil {
+			handleInternalError(response, err)
+			return
+		}
+		if !supported {
+			response.AddHeader("Content-Type", "text/plain")
+			response.WriteErrorString(http.StatusBadRequest,
+				"Preview requires server-side dry run of Kubernetes 1.13 or newer\n")
+			return
+		}
+	}
+	chain.ProcessFilter(request, response)
+}
+
+// activityResourceAttribute is the name of request attribute handlers set to the resource they
+// changed, when the route does not identify it, e.g. an app deployed from a form.
+const activityResourceAttribute = "activityResource"
+
+// activityFilter is a web-service filter function that records changes made by known users for
+// their activity feed.
+func (apiHandler *APIHandler) activityFilter(request *restful.Request, response *restful.Response,
+	chain *restful.FilterChain) {
+	chain.ProcessFilter(request, response)
+
+	user := getViewUser(request).Name
+	method := request.Request.Method
+	if user == "" || method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+		return
+	}
+	apiHandler.activityRecorder.Record(user, activity.Action{
+		Time:       metaV1.Now(),
+		Method:     method,
+		Route:      request.SelectedRoutePath(),
+		StatusCode: response.StatusCode(),
+		Resource:   getActivityResource(request),
+	})
+}
+
+// getActivityResource returns resource changed by given request. Routes of most resources name the
+// parameter with the name of the resource after its kind, e.g. /deployment/{namespace}/{deployment},
+// other routes have kind and name parameters.
+func getActivityResource(request *restful.Request) *activity.Resource {
+	if resource, ok := request.Attribute(activityResourceAttribute).(activity.Resource); ok {
+		return &resource
+	}
+
+	route := strings.Split(strings.TrimPrefix(request.SelectedRoutePath(), "/api/v1/"), "/")
+	kind := request.PathParameter("kind")
+	if kind == "" {
+		kind = route[0]
+	}
+	name := request.PathParameter("name")
+	for i := 1; name == "" && i < len(route); i++ {
+		// tokens identify shared views and webhooks, so they are not recorded
+		if strings.HasPrefix(route[i], "{") && route[i] != "{namespace}" && route[i] != "{kind}" &&
+			route[i] != "{token}" {
+			name = request.PathParameter(strings.Trim(route[i], "{}"))
+		}
+	}
+	if name == "" {
+		return nil
+	}
+	return &activity.Resource{Kind: kind, Namespace: request.PathParameter("namespace"), Name: name}
+}
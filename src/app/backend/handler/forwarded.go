@@ -0,0 +1,144 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/kubernetes/dashboard/src/app/backend/client"
+)
+
+// Headers set by reverse proxies to pass on the original client address, scheme and host.
+const (
+	forwardedForHeader   = "X-Forwarded-For"
+	forwardedProtoHeader = "X-Forwarded-Proto"
+	forwardedHostHeader  = "X-Forwarded-Host"
+)
+
+// ParseTrustedProxies parses given list of CIDRs, e.g. 10.0.0.0/8. Single IPs are accepted too.
+func ParseTrustedProxies(cidrs []string) ([]*net.IPNet, error) {
+	result := make([]*net.IPNet, 0)
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		if !strings.Contains(cidr, "/") {
+			if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid trusted proxy %q: %s", cidr, err)
+		}
+		result = append(result, network)
+	}
+	return result, nil
+}
+
+// CreateForwardedHeadersHandler creates a handler that derives client address, scheme and host of
+// requests from X-Forwarded-* headers set by trusted proxies before passing them on to given
+// handler. Request remote address, URL scheme and host are overwritten, so that request logging
+// and redirects see the original client request. Forwarded headers of requests that do not come
+// from trusted proxies are dropped, so that clients cannot spoof them. This includes the user
+// and groups headers set by authenticating proxies, which end up in the API server audit log and
+// identify owners of saved views. Derived values are only used by request logging, the backend has
+// no rate limiting or OIDC login redirects that would use them yet.
+func CreateForwardedHeadersHandler(trustedProxies []*net.IPNet, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isTrustedProxy(trustedProxies, remoteIP(r.RemoteAddr)) {
+			r.Header.Del(forwardedForHeader)
+			r.Header.Del(forwardedProtoHeader)
+			r.Header.Del(forwardedHostHeader)
+			r.Header.Del(client.RequestUserHeader)
+			r.Header.Del(client.RequestGroupsHeader)
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		forwardedFor := strings.Join(r.Header[forwardedForHeader], ",")
+		if clientIP := getForwardedClientIP(trustedProxies, forwardedFor); clientIP != "" {
+			r.RemoteAddr = clientIP
+		}
+		if proto := lastHeaderValue(r.Header[forwardedProtoHeader]); proto == "http" || proto == "https" {
+			r.URL.Scheme = proto
+		}
+		if host := lastHeaderValue(r.Header[forwardedHostHeader]); host != "" {
+			r.Host = host
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// getForwardedClientIP returns the address of the client given X-Forwarded-For header value. The
+// header is read from the right, as entries on the left could have been set by the client itself,
+// so the client is the last address that is not a trusted proxy.
+func getForwardedClientIP(trustedProxies []*net.IPNet, forwardedFor string) string {
+	if forwardedFor == "" {
+		return ""
+	}
+
+	addresses := strings.Split(forwardedFor, ",")
+	for i := len(addresses) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(addresses[i]))
+		if ip == nil {
+			// Entries left of a malformed one cannot be trusted.
+			return ""
+		}
+		if i == 0 || !isTrustedProxy(trustedProxies, ip) {
+			return ip.String()
+		}
+	}
+	return ""
+}
+
+// isTrustedProxy checks if given IP belongs to one of trusted proxy networks.
+func isTrustedProxy(trustedProxies []*net.IPNet, ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP returns IP of given remote address in host:port or host form.
+func remoteIP(remoteAddr string) net.IP {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+// lastHeaderValue returns the last entry of comma separated values of a header, which was set by
+// the trusted proxy closest to the dashboard. Proxies append to these headers, so entries on the
+// left could have been set by the client itself, as with X-Forwarded-For.
+func lastHeaderValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	entries := strings.Split(values[len(values)-1], ",")
+	return strings.TrimSpace(entries[len(entries)-1])
+}
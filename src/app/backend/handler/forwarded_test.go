@@ -0,0 +1,134 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	cases := []struct {
+		cidrs       []string
+		expected    []string
+		expectError bool
+	}{
+		{[]string{}, []string{}, false},
+		{[]string{"10.0.0.0/8", " 192.168.1.1", "::1"}, []string{"10.0.0.0/8", "192.168.1.1/32", "::1/128"}, false},
+		{[]string{"foo"}, nil, true},
+	}
+	for _, c := range cases {
+		networks, err := ParseTrustedProxies(c.cidrs)
+		if (err != nil) != c.expectError {
+			t.Errorf("ParseTrustedProxies(%#v) returns error %v, expected error: %v", c.cidrs, err, c.expectError)
+		}
+		var actual []string
+		if networks != nil {
+			actual = make([]string, 0)
+			for _, network := range networks {
+				actual = append(actual, network.String())
+			}
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("ParseTrustedProxies(%#v) returns %#v, expected %#v", c.cidrs, actual, c.expected)
+		}
+	}
+}
+
+func TestCreateForwardedHeadersHandler(t *testing.T) {
+	trustedProxies, _ := ParseTrustedProxies([]string{"10.0.0.0/8"})
+	cases := []struct {
+		remoteAddr, forwardedFor, proto, host string
+		expectedAddr, expectedScheme          string
+		expectedHost, expectedForwardedFor    string
+	}{
+		{"1.2.3.4:5000", "6.6.6.6", "https", "evil.com", "1.2.3.4:5000", "", "example.com", ""},
+		{"10.0.0.1:5000", "1.2.3.4", "https", "dashboard.com", "1.2.3.4", "https", "dashboard.com", "1.2.3.4"},
+		{"10.0.0.1:5000", "6.6.6.6, 1.2.3.4, 10.0.0.2", "", "", "1.2.3.4", "", "example.com",
+			"6.6.6.6, 1.2.3.4, 10.0.0.2"},
+		{"10.0.0.1:5000", "10.0.0.3, 10.0.0.2", "ftp", "", "10.0.0.3", "", "example.com", "10.0.0.3, 10.0.0.2"},
+		{"10.0.0.1:5000", "1.2.3.4, foo", "", "", "10.0.0.1:5000", "", "example.com", "1.2.3.4, foo"},
+		// Entries on the left are set by the client, the nearest proxy appends the last one.
+		{"10.0.0.1:5000", "1.2.3.4", "http, https", "evil.com, dashboard.com", "1.2.3.4", "https",
+			"dashboard.com", "1.2.3.4"},
+	}
+	for _, c := range cases {
+		var served *http.Request
+		handler := CreateForwardedHeadersHandler(trustedProxies, http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				served = r
+			}))
+
+		request := httptest.NewRequest("GET", "/api/v1/pod", nil)
+		request.RemoteAddr = c.remoteAddr
+		request.Header.Set(forwardedForHeader, c.forwardedFor)
+		request.Header.Set(forwardedProtoHeader, c.proto)
+		request.Header.Set(forwardedHostHeader, c.host)
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+
+		if served.RemoteAddr != c.expectedAddr || served.URL.Scheme != c.expectedScheme ||
+			served.Host != c.expectedHost || served.Header.Get(forwardedForHeader) != c.expectedForwardedFor {
+			t.Errorf("CreateForwardedHeadersHandler() serving request from %#v forwarded for %#v "+
+				"returns address %#v, scheme %#v, host %#v, forwarded for %#v, expected %#v, %#v, %#v, %#v",
+				c.remoteAddr, c.forwardedFor, served.RemoteAddr, served.URL.Scheme, served.Host,
+				served.Header.Get(forwardedForHeader), c.expectedAddr, c.expectedScheme, c.expectedHost,
+				c.expectedForwardedFor)
+		}
+	}
+}
+
+func TestCreateForwardedHeadersHandlerMultipleHeaders(t *testing.T) {
+	trustedProxies, _ := ParseTrustedProxies([]string{"10.0.0.0/8"})
+	var served *http.Request
+	handler := CreateForwardedHeadersHandler(trustedProxies, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			served = r
+		}))
+
+	request := httptest.NewRequest("GET", "/api/v1/pod", nil)
+	request.RemoteAddr = "10.0.0.1:5000"
+	request.Header.Add(forwardedForHeader, "6.6.6.6")
+	request.Header.Add(forwardedForHeader, "1.2.3.4")
+	request.Header.Add(forwardedHostHeader, "evil.com")
+	request.Header.Add(forwardedHostHeader, "dashboard.com")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	if served.RemoteAddr != "1.2.3.4" || served.Host != "dashboard.com" {
+		t.Errorf("CreateForwardedHeadersHandler() serving request with multiple forwarded headers "+
+			"returns address %#v, host %#v, expected %#v, %#v", served.RemoteAddr, served.Host,
+			"1.2.3.4", "dashboard.com")
+	}
+}
+
+func TestIsTrustedProxy(t *testing.T) {
+	trustedProxies, _ := ParseTrustedProxies([]string{"10.0.0.0/8"})
+	cases := []struct {
+		ip       net.IP
+		expected bool
+	}{
+		{nil, false},
+		{net.ParseIP("10.1.2.3"), true},
+		{net.ParseIP("11.1.2.3"), false},
+	}
+	for _, c := range cases {
+		actual := isTrustedProxy(trustedProxies, c.ip)
+		if actual != c.expected {
+			t.Errorf("isTrustedProxy(%#v) returns %#v, expected %#v", c.ip, actual, c.expected)
+		}
+	}
+}
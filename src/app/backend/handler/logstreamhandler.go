@@ -0,0 +1,70 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/logs"
+	"golang.org/x/net/websocket"
+)
+
+// logStreamError is the last message of a log or other JSON stream that ended with an error.
+type logStreamError struct {
+	Error string `json:"error"`
+}
+
+// serveLogStream upgrades the request to WebSocket connection and sends every log line passed to
+// send by follow as JSON encoded text message. Follow has to return when stop is closed, which
+// happens when the client disconnects. If follow fails, its error is sent as the last message.
+func serveLogStream(w http.ResponseWriter, r *http.Request,
+	follow func(stop <-chan struct{}, send func(logs.LogLine) error) error) {
+	serveJSONStream(w, r, "Log", func(stop <-chan struct{}, send func(interface{}) error) error {
+		return follow(stop, func(line logs.LogLine) error {
+			return send(line)
+		})
+	})
+}
+
+// serveJSONStream upgrades the request to WebSocket connection and sends every message passed to
+// send by follow as JSON encoded text message, the same way as serveLogStream. Name of the stream
+// is used in the log.
+func serveJSONStream(w http.ResponseWriter, r *http.Request, name string,
+	follow func(stop <-chan struct{}, send func(interface{}) error) error) {
+	server := websocket.Server{
+		Handshake: checkSameOrigin,
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			disconnected := make(chan struct{})
+			go func() {
+				io.Copy(ioutil.Discard, ws)
+				close(disconnected)
+			}()
+
+			err := follow(disconnected, func(message interface{}) error {
+				return websocket.JSON.Send(ws, message)
+			})
+			if err != nil {
+				log.Printf("%s stream finished with error: %s", name, err)
+				websocket.JSON.Send(ws, logStreamError{Error: err.Error()})
+			}
+		},
+	}
+	server.ServeHTTP(w, r)
+}
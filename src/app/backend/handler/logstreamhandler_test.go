@@ -0,0 +1,64 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/logs"
+	"golang.org/x/net/websocket"
+)
+
+func TestServeLogStream(t *testing.T) {
+	expected := logs.LogLine{Timestamp: "2017-05-05T10:00:00Z", Content: "started"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveLogStream(w, r, func(stop <-chan struct{}, send func(logs.LogLine) error) error {
+			if err := send(expected); err != nil {
+				return err
+			}
+			return errors.New("container not found")
+		})
+	}))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	ws, err := websocket.Dial(url, "", server.URL)
+	if err != nil {
+		t.Fatalf("Cannot connect to %s: %v", url, err)
+	}
+	defer ws.Close()
+
+	actual := logs.LogLine{}
+	if err := websocket.JSON.Receive(ws, &actual); err != nil {
+		t.Fatalf("Cannot receive log line: %v", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("serveLogStream() sends \ngot: %#v, \nexpected %#v", actual, expected)
+	}
+
+	streamErr := logStreamError{}
+	err = websocket.JSON.Receive(ws, &streamErr)
+	if err != nil || streamErr.Error != "container not found" {
+		t.Errorf("serveLogStream() should send error of the stream, got: %#v, %v", streamErr, err)
+	}
+	if err := websocket.JSON.Receive(ws, &actual); err == nil {
+		t.Error("serveLogStream() keeps connection open after the stream ended")
+	}
+}
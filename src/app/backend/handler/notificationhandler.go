@@ -0,0 +1,58 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/kubernetes/dashboard/src/app/backend/subscription"
+	"golang.org/x/net/websocket"
+)
+
+// serveNotifications upgrades the request to WebSocket connection and sends every notification
+// read from given channel as JSON encoded text message, until the channel is closed or the client
+// disconnects. Messages sent by the client are ignored.
+func serveNotifications(w http.ResponseWriter, r *http.Request,
+	notifications <-chan subscription.Notification) {
+	server := websocket.Server{
+		Handshake: checkSameOrigin,
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			disconnected := make(chan struct{})
+			go func() {
+				io.Copy(ioutil.Discard, ws)
+				close(disconnected)
+			}()
+
+			for {
+				select {
+				case notification, ok := <-notifications:
+					if !ok {
+						return
+					}
+					if err := websocket.JSON.Send(ws, notification); err != nil {
+						return
+					}
+				case <-disconnected:
+					return
+				}
+			}
+		},
+	}
+	server.ServeHTTP(w, r)
+}
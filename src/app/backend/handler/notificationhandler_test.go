@@ -0,0 +1,57 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/subscription"
+	"golang.org/x/net/websocket"
+)
+
+func TestServeNotifications(t *testing.T) {
+	notifications := make(chan subscription.Notification, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveNotifications(w, r, notifications)
+	}))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	ws, err := websocket.Dial(url, "", server.URL)
+	if err != nil {
+		t.Fatalf("Cannot connect to %s: %v", url, err)
+	}
+	defer ws.Close()
+
+	expected := subscription.Notification{ID: 1, Type: subscription.NotificationDeleted,
+		Kind: "pod", Namespace: "default", Name: "foo"}
+	notifications <- expected
+	close(notifications)
+
+	actual := subscription.Notification{}
+	if err := websocket.JSON.Receive(ws, &actual); err != nil {
+		t.Fatalf("Cannot receive notification: %v", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("serveNotifications() sends \ngot: %#v, \nexpected %#v", actual, expected)
+	}
+	if err := websocket.JSON.Receive(ws, &actual); err == nil {
+		t.Error("serveNotifications() keeps connection open after notifications are closed")
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/kubernetes/dashboard/src/app/backend/client"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	"github.com/kubernetes/dashboard/src/app/backend/settings"
+)
+
+// Period of time settings read for internal use are cached for.
+const sharedSettingsTTL = 30 * time.Second
+
+// Request attribute holding pagination limits, set by paginationLimitsFilter.
+const paginationLimitsAttribute = "paginationLimits"
+
+// sharedSettings reads settings with the dashboard's own credentials for internal use, e.g. for
+// links and pagination limits. Settings are cached, so that the config map is not read on every
+// request.
+type sharedSettings struct {
+	manager         client.ClientManager
+	settingsManager settings.SettingsManager
+
+	lock    sync.Mutex
+	cached  settings.Settings
+	expires time.Time
+}
+
+// Get returns cached settings or reads them if the cache expired.
+func (self *sharedSettings) Get() (settings.Settings, error) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if time.Now().Before(self.expires) {
+		return self.cached, nil
+	}
+
+	k8sClient, err := self.manager.Client(nil)
+	if err != nil {
+		return settings.Settings{}, err
+	}
+
+	s, err := self.settingsManager.GetSettings(k8sClient)
+	if err != nil {
+		return settings.Settings{}, err
+	}
+
+	self.cached = s
+	self.expires = time.Now().Add(sharedSettingsTTL)
+	return s, nil
+}
+
+// Invalidate drops cached settings, e.g. after they were saved.
+func (self *sharedSettings) Invalidate() {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.expires = time.Time{}
+}
+
+// paginationLimitsFilter is a web-service filter function that sets pagination limits from
+// settings as request attribute. Lists requested without pagination get the default page, see
+// parsePaginationPathParameter.
+func (self *sharedSettings) paginationLimitsFilter(request *restful.Request, response *restful.Response,
+	chain *restful.FilterChain) {
+	limits := dataselect.DefaultPaginationLimits
+	if s, err := self.Get(); err == nil {
+		limits = toPaginationLimits(s)
+	}
+	request.SetAttribute(paginationLimitsAttribute, limits)
+	chain.ProcessFilter(request, response)
+}
+
+// toPaginationLimits returns pagination limits of settings. Missing or invalid values are replaced
+// with defaults.
+func toPaginationLimits(s settings.Settings) dataselect.PaginationLimits {
+	limits := dataselect.DefaultPaginationLimits
+	if s.MaxItemsPerPage > 0 {
+		limits.MaxItemsPerPage = s.MaxItemsPerPage
+	}
+	if s.ItemsPerPage > 0 {
+		limits.DefaultItemsPerPage = s.ItemsPerPage
+	}
+	if limits.DefaultItemsPerPage > limits.MaxItemsPerPage {
+		limits.DefaultItemsPerPage = limits.MaxItemsPerPage
+	}
+	return limits
+}
+
+// getPaginationLimits returns pagination limits set by paginationLimitsFilter.
+func getPaginationLimits(request *restful.Request) dataselect.PaginationLimits {
+	if limits, ok := request.Attribute(paginationLimitsAttribute).(dataselect.PaginationLimits); ok {
+		return limits
+	}
+	return dataselect.DefaultPaginationLimits
+}
@@ -0,0 +1,135 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/deployment"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/node"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/pod"
+)
+
+// textTableNone is shown in empty cells of text tables.
+const textTableNone = "<none>"
+
+// textTable is a list rendered as plain text with aligned columns, for clients without JSON
+// tooling, e.g. curl.
+type textTable struct {
+	header []string
+	rows   [][]string
+}
+
+// serveTextTable writes given table as plain text response.
+func serveTextTable(w http.ResponseWriter, table textTable) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if err := writeTextTable(w, table); err != nil {
+		log.Printf("Couldn't write text table: %s", err)
+	}
+}
+
+// writeTextTable writes given table with columns aligned by spaces, one row per line.
+func writeTextTable(w io.Writer, table textTable) error {
+	writer := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(writer, strings.Join(table.header, "\t"))
+	for _, row := range table.rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			if cell == "" {
+				cell = textTableNone
+			}
+			cells[i] = cell
+		}
+		fmt.Fprintln(writer, strings.Join(cells, "\t"))
+	}
+	return writer.Flush()
+}
+
+func toPodTextTable(list *pod.PodList, now time.Time) textTable {
+	table := textTable{header: []string{"NAMESPACE", "NAME", "STATUS", "RESTARTS", "AGE"}}
+	for _, item := range list.Pods {
+		table.rows = append(table.rows, []string{
+			item.ObjectMeta.Namespace,
+			item.ObjectMeta.Name,
+			item.PodStatus.Status,
+			fmt.Sprint(item.RestartCount),
+			formatTextAge(item.ObjectMeta, now),
+		})
+	}
+	return table
+}
+
+func toDeploymentTextTable(list *deployment.DeploymentList, now time.Time) textTable {
+	table := textTable{header: []string{"NAMESPACE", "NAME", "PODS", "IMAGES", "AGE"}}
+	for _, item := range list.Deployments {
+		table.rows = append(table.rows, []string{
+			item.ObjectMeta.Namespace,
+			item.ObjectMeta.Name,
+			fmt.Sprintf("%d/%d", item.Pods.Running, item.Pods.Desired),
+			strings.Join(item.ContainerImages, ","),
+			formatTextAge(item.ObjectMeta, now),
+		})
+	}
+	return table
+}
+
+func toNodeTextTable(list *node.NodeList, now time.Time) textTable {
+	table := textTable{
+		header: []string{"NAME", "READY", "CPU REQUESTS", "MEMORY REQUESTS", "PODS", "AGE"},
+	}
+	for _, item := range list.Nodes {
+		resources := item.AllocatedResources
+		table.rows = append(table.rows, []string{
+			item.ObjectMeta.Name,
+			string(item.Ready),
+			fmt.Sprintf("%dm (%.0f%%)", resources.CPURequests, resources.CPURequestsFraction),
+			fmt.Sprintf("%dMi (%.0f%%)", resources.MemoryRequests/(1024*1024),
+				resources.MemoryRequestsFraction),
+			fmt.Sprintf("%d/%d", resources.AllocatedPods, resources.PodCapacity),
+			formatTextAge(item.ObjectMeta, now),
+		})
+	}
+	return table
+}
+
+// formatTextAge returns age of the object in the most significant unit, e.g. 5m or 3d.
+func formatTextAge(meta api.ObjectMeta, now time.Time) string {
+	if meta.CreationTimestamp.IsZero() {
+		return ""
+	}
+
+	age := now.Sub(meta.CreationTimestamp.Time)
+	if age < 0 {
+		age = 0
+	}
+	switch {
+	case age < 2*time.Minute:
+		return fmt.Sprintf("%ds", int(age.Seconds()))
+	case age < 2*time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+}
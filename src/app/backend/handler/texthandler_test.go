@@ -0,0 +1,134 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/deployment"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/node"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/pod"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+func TestToPodTextTable(t *testing.T) {
+	now := time.Date(2017, 5, 5, 12, 0, 0, 0, time.UTC)
+	list := &pod.PodList{Pods: []pod.Pod{
+		{
+			ObjectMeta: api.ObjectMeta{Namespace: "default", Name: "web-1",
+				CreationTimestamp: metaV1.NewTime(now.Add(-90 * time.Second))},
+			PodStatus:    pod.PodStatus{Status: "Running"},
+			RestartCount: 2,
+		},
+		{
+			ObjectMeta: api.ObjectMeta{Namespace: "kube-system", Name: "dns",
+				CreationTimestamp: metaV1.NewTime(now.Add(-72 * time.Hour))},
+		},
+	}}
+	expected := "NAMESPACE     NAME    STATUS    RESTARTS   AGE\n" +
+		"default       web-1   Running   2          90s\n" +
+		"kube-system   dns     <none>    0          3d\n"
+
+	var buffer bytes.Buffer
+	if err := writeTextTable(&buffer, toPodTextTable(list, now)); err != nil {
+		t.Fatalf("writeTextTable() == \ngot err %#v", err)
+	}
+	if actual := buffer.String(); actual != expected {
+		t.Errorf("toPodTextTable(%#v) == \ngot %q, \nexpected %q", list, actual, expected)
+	}
+}
+
+func TestToDeploymentTextTable(t *testing.T) {
+	now := time.Date(2017, 5, 5, 12, 0, 0, 0, time.UTC)
+	list := &deployment.DeploymentList{Deployments: []deployment.Deployment{
+		{
+			ObjectMeta: api.ObjectMeta{Namespace: "default", Name: "web",
+				CreationTimestamp: metaV1.NewTime(now.Add(-5 * time.Minute))},
+			Pods:            common.PodInfo{Running: 2, Desired: 3},
+			ContainerImages: []string{"nginx:1.13", "fluentd"},
+		},
+		{
+			ObjectMeta: api.ObjectMeta{Namespace: "default", Name: "empty"},
+		},
+	}}
+	expected := "NAMESPACE   NAME    PODS   IMAGES               AGE\n" +
+		"default     web     2/3    nginx:1.13,fluentd   5m\n" +
+		"default     empty   0/0    <none>               <none>\n"
+
+	var buffer bytes.Buffer
+	if err := writeTextTable(&buffer, toDeploymentTextTable(list, now)); err != nil {
+		t.Fatalf("writeTextTable() == \ngot err %#v", err)
+	}
+	if actual := buffer.String(); actual != expected {
+		t.Errorf("toDeploymentTextTable(%#v) == \ngot %q, \nexpected %q", list, actual, expected)
+	}
+}
+
+func TestToNodeTextTable(t *testing.T) {
+	now := time.Date(2017, 5, 5, 12, 0, 0, 0, time.UTC)
+	list := &node.NodeList{Nodes: []node.Node{
+		{
+			ObjectMeta: api.ObjectMeta{Name: "node-1",
+				CreationTimestamp: metaV1.NewTime(now.Add(-3 * time.Hour))},
+			Ready: v1.ConditionTrue,
+			AllocatedResources: node.NodeAllocatedResources{
+				CPURequests: 500, CPURequestsFraction: 25,
+				MemoryRequests: 512 * 1024 * 1024, MemoryRequestsFraction: 12.5,
+				AllocatedPods: 4, PodCapacity: 110,
+			},
+		},
+	}}
+	expected := "NAME     READY   CPU REQUESTS   MEMORY REQUESTS   PODS    AGE\n" +
+		"node-1   True    500m (25%)     512Mi (12%)       4/110   3h\n"
+
+	var buffer bytes.Buffer
+	if err := writeTextTable(&buffer, toNodeTextTable(list, now)); err != nil {
+		t.Fatalf("writeTextTable() == \ngot err %#v", err)
+	}
+	if actual := buffer.String(); actual != expected {
+		t.Errorf("toNodeTextTable(%#v) == \ngot %q, \nexpected %q", list, actual, expected)
+	}
+}
+
+func TestFormatTextAge(t *testing.T) {
+	now := time.Date(2017, 5, 5, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		age      time.Duration
+		expected string
+	}{
+		{-time.Minute, "0s"},
+		{30 * time.Second, "30s"},
+		{10 * time.Minute, "10m"},
+		{5 * time.Hour, "5h"},
+		{50 * time.Hour, "2d"},
+	}
+
+	for _, c := range cases {
+		meta := api.ObjectMeta{CreationTimestamp: metaV1.NewTime(now.Add(-c.age))}
+		actual := formatTextAge(meta, now)
+		if actual != c.expected {
+			t.Errorf("formatTextAge(%v) == \ngot %#v, \nexpected %#v", c.age, actual, c.expected)
+		}
+	}
+
+	if actual := formatTextAge(api.ObjectMeta{}, now); actual != "" {
+		t.Errorf("formatTextAge(<zero>) == \ngot %#v, \nexpected %#v", actual, "")
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/websocket"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// How long to wait before watching again after a watch ended without sending any event.
+const rewatchDelay = time.Second
+
+// watchStreamEvent is a message of a watch stream.
+type watchStreamEvent struct {
+	// Type of the change, or ERROR if the stream ended with an error.
+	Type watch.EventType `json:"type"`
+
+	// Changed object. For ERROR it is status of the error, if sent by the API server. Status with
+	// 410 code means that the resource version is too old and the list has to be fetched again.
+	Object runtime.Object `json:"object,omitempty"`
+
+	// Resource version of the changed object. Passing the last one to a new watch stream
+	// resumes the watch after reconnect.
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+
+	// Error that ended the stream, when not sent by the API server.
+	Error string `json:"error,omitempty"`
+}
+
+// serveWatchStream upgrades the request to WebSocket connection, sends given listed objects as
+// added with resource version of the list and then every event of given watcher as JSON encoded
+// text message. Watches end after a timeout set by the API server, so when the watcher ends,
+// rewatch is called with the last resource version to continue the stream. The stream ends when
+// the client disconnects or with an error message.
+func serveWatchStream(w http.ResponseWriter, r *http.Request, objects []runtime.Object,
+	watcher watch.Interface, resourceVersion string,
+	rewatch func(resourceVersion string) (watch.Interface, error)) {
+	server := websocket.Server{
+		Handshake: checkSameOrigin,
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			for _, object := range objects {
+				message := watchStreamEvent{Type: watch.Added, Object: object,
+					ResourceVersion: resourceVersion}
+				if err := websocket.JSON.Send(ws, message); err != nil {
+					watcher.Stop()
+					return
+				}
+			}
+
+			disconnected := make(chan struct{})
+			go func() {
+				io.Copy(ioutil.Discard, ws)
+				close(disconnected)
+			}()
+
+			for {
+				sent, ended := sendWatchEvents(ws, watcher, &resourceVersion, disconnected)
+				watcher.Stop()
+				if ended {
+					return
+				}
+
+				if sent == 0 {
+					select {
+					case <-disconnected:
+						return
+					case <-time.After(rewatchDelay):
+					}
+				}
+
+				var err error
+				if watcher, err = rewatch(resourceVersion); err != nil {
+					log.Printf("Watch stream finished with error: %s", err)
+					websocket.JSON.Send(ws, watchStreamEvent{Type: watch.Error, Error: err.Error()})
+					return
+				}
+			}
+		},
+	}
+	server.ServeHTTP(w, r)
+}
+
+// sendWatchEvents sends events of given watcher until it ends, and updates given resource version
+// with the last sent one. Returns number of sent events and whether the stream has to end, because
+// the client disconnected or an error event was sent as the last message.
+func sendWatchEvents(ws *websocket.Conn, watcher watch.Interface, resourceVersion *string,
+	disconnected <-chan struct{}) (int, bool) {
+	sent := 0
+	for {
+		select {
+		case <-disconnected:
+			return sent, true
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return sent, false
+			}
+
+			message := watchStreamEvent{Type: event.Type, Object: event.Object}
+			if event.Type == watch.Error {
+				log.Printf("Watch stream finished with error event: %#v", event.Object)
+				websocket.JSON.Send(ws, message)
+				return sent, true
+			}
+			if accessor, err := meta.Accessor(event.Object); err == nil {
+				message.ResourceVersion = accessor.GetResourceVersion()
+			}
+
+			if err := websocket.JSON.Send(ws, message); err != nil {
+				return sent, true
+			}
+			if message.ResourceVersion != "" {
+				*resourceVersion = message.ResourceVersion
+			}
+			sent++
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/websocket"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+func TestServeWatchStream(t *testing.T) {
+	first, second := watch.NewFake(), watch.NewFake()
+	rewatched := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		listed := []runtime.Object{&runtime.Unknown{Raw: []byte(`{"metadata": {"name": "bar"}}`),
+			ContentType: runtime.ContentTypeJSON}}
+		serveWatchStream(w, r, listed, first, "3", func(resourceVersion string) (watch.Interface, error) {
+			rewatched <- resourceVersion
+			return second, nil
+		})
+	}))
+	defer server.Close()
+
+	go func() {
+		first.Add(&v1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: "foo", ResourceVersion: "5"}})
+		// The API server ends watches after a timeout.
+		first.Stop()
+		second.Error(&metaV1.Status{Code: http.StatusGone, Reason: metaV1.StatusReasonExpired})
+	}()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	ws, err := websocket.Dial(url, "", server.URL)
+	if err != nil {
+		t.Fatalf("Cannot connect to %s: %v", url, err)
+	}
+	defer ws.Close()
+
+	event := struct {
+		Type            watch.EventType        `json:"type"`
+		Object          map[string]interface{} `json:"object"`
+		ResourceVersion string                 `json:"resourceVersion"`
+	}{}
+	if err := websocket.JSON.Receive(ws, &event); err != nil {
+		t.Fatalf("Cannot receive watch event: %v", err)
+	}
+	metadata, _ := event.Object["metadata"].(map[string]interface{})
+	if event.Type != watch.Added || event.ResourceVersion != "3" || metadata["name"] != "bar" {
+		t.Errorf("serveWatchStream() should send listed pod with resource version of the list, got: %#v",
+			event)
+	}
+
+	if err := websocket.JSON.Receive(ws, &event); err != nil {
+		t.Fatalf("Cannot receive watch event: %v", err)
+	}
+	if event.Type != watch.Added || event.ResourceVersion != "5" {
+		t.Errorf("serveWatchStream() should send added pod with resource version 5, got: %#v", event)
+	}
+
+	event.Object = nil
+	if err := websocket.JSON.Receive(ws, &event); err != nil {
+		t.Fatalf("Cannot receive watch event: %v", err)
+	}
+	if event.Type != watch.Error || event.Object["code"] != float64(http.StatusGone) {
+		t.Errorf("serveWatchStream() should send error status of the watch, got: %#v", event)
+	}
+	if resourceVersion := <-rewatched; resourceVersion != "5" {
+		t.Errorf("serveWatchStream() should watch again from resource version 5, got: %s",
+			resourceVersion)
+	}
+	if err := websocket.JSON.Receive(ws, &event); err == nil {
+		t.Error("serveWatchStream() keeps connection open after the error")
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alertmanager
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+)
+
+// Alert labels used to map alerts to Kubernetes resources. These are the labels exported by
+// kube-state-metrics and used by common alerting rules.
+const (
+	namespaceLabel = "namespace"
+	severityLabel  = "severity"
+)
+
+// workloadLabels maps alert labels to kinds of resources they identify.
+var workloadLabels = map[string]api.ResourceKind{
+	"deployment":  api.ResourceKindDeployment,
+	"statefulset": api.ResourceKindStatefulSet,
+	"daemonset":   api.ResourceKindDaemonSet,
+	"job_name":    api.ResourceKindJob,
+	"pod":         api.ResourceKindPod,
+}
+
+// Alert severities counted in alert badges.
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+)
+
+// AlertStatus is a status of an alert in Alertmanager.
+type AlertStatus struct {
+	// State of the alert, i.e. active, suppressed or unprocessed.
+	State       string   `json:"state"`
+	SilencedBy  []string `json:"silencedBy"`
+	InhibitedBy []string `json:"inhibitedBy"`
+}
+
+// Alert is a single alert fired by Prometheus and managed by Alertmanager.
+type Alert struct {
+	Fingerprint  string            `json:"fingerprint"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Status       AlertStatus       `json:"status"`
+}
+
+// AlertList contains a list of alerts.
+type AlertList struct {
+	ListMeta api.ListMeta `json:"listMeta"`
+	Alerts   []Alert      `json:"alerts"`
+}
+
+// Matcher matches alerts by label value.
+type Matcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+// Silence mutes alerts matching all of its matchers for a period of time.
+type Silence struct {
+	Matchers  []Matcher `json:"matchers"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	CreatedBy string    `json:"createdBy"`
+	Comment   string    `json:"comment"`
+}
+
+// SilenceResponse is returned after a silence is created.
+type SilenceResponse struct {
+	SilenceID string `json:"silenceID"`
+}
+
+// AlertBadge summarizes active alerts of a single resource.
+type AlertBadge struct {
+	Critical int `json:"critical"`
+	Warning  int `json:"warning"`
+	Other    int `json:"other"`
+}
+
+// AlertBadges indexes alert badges by resource kind, namespace and name.
+type AlertBadges map[api.ResourceKind]map[string]map[string]*AlertBadge
+
+// ErrAlertmanagerDisabled is returned when Alertmanager integration is not configured.
+var ErrAlertmanagerDisabled = errors.New("Alertmanager integration is not configured")
+
+// GetAlertList returns active alerts. When namespace is not empty only alerts with matching
+// namespace label are returned.
+func GetAlertList(client AlertmanagerClient, namespace string) (*AlertList, error) {
+	log.Print("Getting list of active alerts")
+
+	if client == nil {
+		return nil, ErrAlertmanagerDisabled
+	}
+
+	alerts, err := client.GetAlerts()
+	if err != nil {
+		return nil, err
+	}
+
+	alertList := &AlertList{Alerts: make([]Alert, 0)}
+	for _, alert := range alerts {
+		if namespace == "" || alert.Labels[namespaceLabel] == namespace {
+			alertList.Alerts = append(alertList.Alerts, alert)
+		}
+	}
+	alertList.ListMeta = api.ListMeta{TotalItems: len(alertList.Alerts)}
+
+	return alertList, nil
+}
+
+// CreateSilence creates a new silence and returns its ID.
+func CreateSilence(client AlertmanagerClient, silence Silence) (*SilenceResponse, error) {
+	log.Printf("Creating silence for %d matchers", len(silence.Matchers))
+
+	if client == nil {
+		return nil, ErrAlertmanagerDisabled
+	}
+	if len(silence.Matchers) == 0 {
+		return nil, errors.New("Silence has to have at least one matcher")
+	}
+
+	id, err := client.CreateSilence(silence)
+	if err != nil {
+		return nil, err
+	}
+	return &SilenceResponse{SilenceID: id}, nil
+}
+
+// DeleteSilence expires silence with given ID.
+func DeleteSilence(client AlertmanagerClient, id string) error {
+	log.Printf("Deleting silence %s", id)
+
+	if client == nil {
+		return ErrAlertmanagerDisabled
+	}
+	return client.DeleteSilence(id)
+}
+
+// GetAlertBadges returns badges of resources that have active, not silenced alerts. Returns empty
+// badges when the integration is disabled or Alertmanager cannot be reached, so that lists can be
+// returned without alert information.
+func GetAlertBadges(client AlertmanagerClient) AlertBadges {
+	badges := AlertBadges{}
+	if client == nil {
+		return badges
+	}
+
+	alerts, err := client.GetAlerts()
+	if err != nil {
+		log.Printf("Couldn't get alerts from Alertmanager: %s", err)
+		return badges
+	}
+
+	return toAlertBadges(alerts)
+}
+
+func toAlertBadges(alerts []Alert) AlertBadges {
+	badges := AlertBadges{}
+	for _, alert := range alerts {
+		if len(alert.Status.SilencedBy) > 0 || len(alert.Status.InhibitedBy) > 0 {
+			continue
+		}
+
+		namespace, ok := alert.Labels[namespaceLabel]
+		if !ok {
+			continue
+		}
+
+		for label, kind := range workloadLabels {
+			name, ok := alert.Labels[label]
+			if !ok {
+				continue
+			}
+			badges.add(kind, namespace, name, alert.Labels[severityLabel])
+		}
+	}
+	return badges
+}
+
+func (badges AlertBadges) add(kind api.ResourceKind, namespace, name, severity string) {
+	if badges[kind] == nil {
+		badges[kind] = make(map[string]map[string]*AlertBadge)
+	}
+	if badges[kind][namespace] == nil {
+		badges[kind][namespace] = make(map[string]*AlertBadge)
+	}
+
+	badge := badges[kind][namespace][name]
+	if badge == nil {
+		badge = &AlertBadge{}
+		badges[kind][namespace][name] = badge
+	}
+
+	switch severity {
+	case SeverityCritical:
+		badge.Critical++
+	case SeverityWarning:
+		badge.Warning++
+	default:
+		badge.Other++
+	}
+}
+
+// Get returns alert badge of given resource or nil if it has no active alerts.
+func (badges AlertBadges) Get(kind api.ResourceKind, namespace, name string) *AlertBadge {
+	return badges[kind][namespace][name]
+}
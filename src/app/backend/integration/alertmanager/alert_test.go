@@ -0,0 +1,154 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alertmanager
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+)
+
+type FakeAlertmanagerClient struct {
+	alerts []Alert
+	err    error
+}
+
+func (c FakeAlertmanagerClient) GetAlerts() ([]Alert, error) {
+	return c.alerts, c.err
+}
+
+func (c FakeAlertmanagerClient) CreateSilence(silence Silence) (string, error) {
+	return "silence-id", c.err
+}
+
+func (c FakeAlertmanagerClient) DeleteSilence(id string) error {
+	return c.err
+}
+
+var testAlerts = []Alert{
+	{Fingerprint: "1", Labels: map[string]string{"namespace": "default", "deployment": "foo",
+		"severity": "critical"}},
+	{Fingerprint: "2", Labels: map[string]string{"namespace": "default", "deployment": "foo",
+		"pod": "foo-1", "severity": "warning"}},
+	{Fingerprint: "3", Labels: map[string]string{"namespace": "kube-system", "daemonset": "bar"}},
+	{Fingerprint: "4", Labels: map[string]string{"namespace": "default", "deployment": "foo",
+		"severity": "critical"}, Status: AlertStatus{SilencedBy: []string{"silence-id"}}},
+	{Fingerprint: "5", Labels: map[string]string{"alertname": "Watchdog"}},
+}
+
+func TestGetAlertBadges(t *testing.T) {
+	cases := []struct {
+		client   AlertmanagerClient
+		expected AlertBadges
+	}{
+		{nil, AlertBadges{}},
+		{FakeAlertmanagerClient{err: errors.New("connection refused")}, AlertBadges{}},
+		{
+			FakeAlertmanagerClient{alerts: testAlerts},
+			AlertBadges{
+				api.ResourceKindDeployment: {"default": {"foo": {Critical: 1, Warning: 1}}},
+				api.ResourceKindPod:        {"default": {"foo-1": {Warning: 1}}},
+				api.ResourceKindDaemonSet:  {"kube-system": {"bar": {Other: 1}}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := GetAlertBadges(c.client)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetAlertBadges(%#v) == \ngot: %#v, \nexpected %#v", c.client, actual, c.expected)
+		}
+	}
+
+	badges := GetAlertBadges(FakeAlertmanagerClient{alerts: testAlerts})
+	if badge := badges.Get(api.ResourceKindStatefulSet, "default", "foo"); badge != nil {
+		t.Errorf("Get() for resource without alerts == %#v, expected nil", badge)
+	}
+}
+
+func TestGetAlertList(t *testing.T) {
+	cases := []struct {
+		namespace string
+		expected  []string
+	}{
+		{"", []string{"1", "2", "3", "4", "5"}},
+		{"kube-system", []string{"3"}},
+		{"empty", []string{}},
+	}
+
+	for _, c := range cases {
+		list, err := GetAlertList(FakeAlertmanagerClient{alerts: testAlerts}, c.namespace)
+		if err != nil {
+			t.Fatalf("GetAlertList(%#v) returned error: %s", c.namespace, err)
+		}
+
+		actual := make([]string, 0)
+		for _, alert := range list.Alerts {
+			actual = append(actual, alert.Fingerprint)
+		}
+		if !reflect.DeepEqual(actual, c.expected) || list.ListMeta.TotalItems != len(c.expected) {
+			t.Errorf("GetAlertList(%#v) == \ngot: %#v, \nexpected %#v", c.namespace, actual, c.expected)
+		}
+	}
+
+	if _, err := GetAlertList(nil, ""); err != ErrAlertmanagerDisabled {
+		t.Errorf("GetAlertList(nil) returned error %v, expected %v", err, ErrAlertmanagerDisabled)
+	}
+}
+
+func TestRemoteAlertmanagerClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v2/alerts":
+			w.Write([]byte(`[{"fingerprint": "1", "labels": {"namespace": "default"}}]`))
+		case r.Method == "POST" && r.URL.Path == "/api/v2/silences":
+			w.Write([]byte(`{"silenceID": "abc"}`))
+		case r.Method == "DELETE" && r.URL.Path == "/api/v2/silence/abc":
+			w.WriteHeader(http.StatusOK)
+		case r.Method == "DELETE" && r.URL.EscapedPath() == "/api/v2/silence/a%2Fb":
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := CreateAlertmanagerClient(server.URL + "/")
+
+	alerts, err := client.GetAlerts()
+	expected := []Alert{{Fingerprint: "1", Labels: map[string]string{"namespace": "default"}}}
+	if err != nil || !reflect.DeepEqual(alerts, expected) {
+		t.Errorf("GetAlerts() == %#v, %v, expected %#v", alerts, err, expected)
+	}
+
+	id, err := client.CreateSilence(Silence{Matchers: []Matcher{{Name: "namespace", Value: "default"}}})
+	if err != nil || id != "abc" {
+		t.Errorf("CreateSilence() == %#v, %v, expected %#v", id, err, "abc")
+	}
+
+	if err := client.DeleteSilence("abc"); err != nil {
+		t.Errorf("DeleteSilence(abc) returned error: %s", err)
+	}
+	if err := client.DeleteSilence("a/b"); err != nil {
+		t.Errorf("DeleteSilence(a/b) returned error: %s", err)
+	}
+	if err := client.DeleteSilence("unknown"); err == nil {
+		t.Error("DeleteSilence(unknown) expected to return error")
+	}
+}
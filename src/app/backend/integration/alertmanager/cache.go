@@ -0,0 +1,67 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alertmanager
+
+import (
+	"sync"
+	"time"
+)
+
+// BadgeRefreshInterval is how long alert badges are served from cache before they are fetched
+// from Alertmanager again.
+const BadgeRefreshInterval = 30 * time.Second
+
+// BadgeCache keeps alert badges of recently fetched alerts, so that resource lists don't wait for
+// Alertmanager. Stale badges are refreshed in background while the previous ones are still served.
+type BadgeCache struct {
+	client AlertmanagerClient
+
+	mutex      sync.Mutex
+	badges     AlertBadges
+	fetchedAt  time.Time
+	refreshing bool
+}
+
+// NewBadgeCache creates cache of alert badges of given client. Client can be nil when the
+// integration is disabled.
+func NewBadgeCache(client AlertmanagerClient) *BadgeCache {
+	return &BadgeCache{client: client, badges: AlertBadges{}}
+}
+
+// Get returns cached alert badges and starts their refresh if they are stale. Badges are empty
+// until the first refresh finishes. Returned badges must not be modified.
+func (self *BadgeCache) Get() AlertBadges {
+	if self == nil || self.client == nil {
+		return AlertBadges{}
+	}
+
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	if !self.refreshing && time.Since(self.fetchedAt) > BadgeRefreshInterval {
+		self.refreshing = true
+		go self.refresh()
+	}
+	return self.badges
+}
+
+func (self *BadgeCache) refresh() {
+	badges := GetAlertBadges(self.client)
+
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	self.badges = badges
+	self.fetchedAt = time.Now()
+	self.refreshing = false
+}
@@ -0,0 +1,62 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alertmanager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+)
+
+// blockingAlertmanagerClient returns alerts only after release is closed.
+type blockingAlertmanagerClient struct {
+	FakeAlertmanagerClient
+	release chan struct{}
+}
+
+func (c blockingAlertmanagerClient) GetAlerts() ([]Alert, error) {
+	<-c.release
+	return c.FakeAlertmanagerClient.GetAlerts()
+}
+
+func TestBadgeCache(t *testing.T) {
+	client := blockingAlertmanagerClient{
+		FakeAlertmanagerClient: FakeAlertmanagerClient{alerts: testAlerts},
+		release:                make(chan struct{}),
+	}
+	cache := NewBadgeCache(client)
+
+	if badges := cache.Get(); len(badges) != 0 {
+		t.Errorf("Get() before refresh == \ngot %#v, \nexpected no badges", badges)
+	}
+	close(client.release)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for cache.Get().Get(api.ResourceKindDeployment, "default", "foo") == nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("Get() == \ngot no badges after refresh")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	var disabled *BadgeCache
+	if badges := disabled.Get(); len(badges) != 0 {
+		t.Errorf("Get() of nil cache == \ngot %#v, \nexpected no badges", badges)
+	}
+	if badges := NewBadgeCache(nil).Get(); len(badges) != 0 {
+		t.Errorf("Get() of disabled integration == \ngot %#v, \nexpected no badges", badges)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alertmanager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// requestTimeout is the timeout of requests to Alertmanager. Alerts are embedded into list
+// responses, so slow Alertmanager must not block the dashboard.
+const requestTimeout = 5 * time.Second
+
+// AlertmanagerClient is a client used to make requests to an Alertmanager instance.
+type AlertmanagerClient interface {
+	// GetAlerts returns all active alerts.
+	GetAlerts() ([]Alert, error)
+
+	// CreateSilence creates a new silence and returns its ID.
+	CreateSilence(silence Silence) (string, error)
+
+	// DeleteSilence expires silence with given ID.
+	DeleteSilence(id string) error
+}
+
+// RemoteAlertmanagerClient is an implementation of Alertmanager client that talks with the v2 API
+// of Alertmanager over HTTP.
+type RemoteAlertmanagerClient struct {
+	host   string
+	client *http.Client
+}
+
+// CreateAlertmanagerClient creates new Alertmanager client. Returns nil client when alertmanagerHost
+// is empty, i.e. the integration is disabled. alertmanagerHost param is in the format of
+// protocol://address:port, e.g., http://localhost:9093.
+func CreateAlertmanagerClient(alertmanagerHost string) AlertmanagerClient {
+	if alertmanagerHost == "" {
+		return nil
+	}
+
+	log.Printf("Creating Alertmanager client for %s", alertmanagerHost)
+	return RemoteAlertmanagerClient{
+		host:   strings.TrimSuffix(alertmanagerHost, "/"),
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// GetAlerts returns all active alerts, including silenced and inhibited ones.
+func (c RemoteAlertmanagerClient) GetAlerts() ([]Alert, error) {
+	alerts := make([]Alert, 0)
+	err := c.do("GET", "/api/v2/alerts?active=true", nil, &alerts)
+	return alerts, err
+}
+
+// CreateSilence creates a new silence and returns its ID.
+func (c RemoteAlertmanagerClient) CreateSilence(silence Silence) (string, error) {
+	result := SilenceResponse{}
+	err := c.do("POST", "/api/v2/silences", silence, &result)
+	return result.SilenceID, err
+}
+
+// DeleteSilence expires silence with given ID.
+func (c RemoteAlertmanagerClient) DeleteSilence(id string) error {
+	return c.do("DELETE", "/api/v2/silence/"+url.PathEscape(id), nil, nil)
+}
+
+func (c RemoteAlertmanagerClient) do(method, path string, body interface{}, result interface{}) error {
+	var reader *bytes.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(raw)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	request, err := http.NewRequest(method, c.host+path, reader)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	raw, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("Alertmanager responded with %d: %s", response.StatusCode,
+			strings.TrimSpace(string(raw)))
+	}
+
+	if result == nil || len(raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(raw, result)
+}
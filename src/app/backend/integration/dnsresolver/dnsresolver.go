@@ -0,0 +1,71 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnsresolver
+
+import (
+	"context"
+	"log"
+	"net"
+	"time"
+)
+
+// lookupTimeout is the timeout of a single DNS lookup.
+const lookupTimeout = 5 * time.Second
+
+// ResolverSystem makes the resolver use name servers configured on the host of the dashboard.
+const ResolverSystem = "system"
+
+// Resolver resolves host names, so that DNS records managed by external-dns can be verified
+// to actually resolve.
+type Resolver interface {
+	// LookupHost returns IP addresses of the host. Canonical names are followed, so the addresses
+	// of the host they point to are returned for them.
+	LookupHost(host string) ([]string, error)
+}
+
+// CreateResolver creates a resolver querying given name server. Returns nil resolver when server is
+// empty, i.e. DNS records are not verified. server is either ResolverSystem or address of a name
+// server in the format of host:port, e.g. 8.8.8.8:53. Authoritative name server of a zone can be
+// used to see records as soon as external-dns creates them, before caches expire.
+func CreateResolver(server string) Resolver {
+	switch server {
+	case "":
+		return nil
+	case ResolverSystem:
+		log.Print("Creating DNS resolver using name servers of the host")
+		return &netResolver{resolver: net.DefaultResolver}
+	default:
+		log.Printf("Creating DNS resolver using %s name server", server)
+		dialer := &net.Dialer{Timeout: lookupTimeout}
+		return &netResolver{resolver: &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, server)
+			},
+		}}
+	}
+}
+
+// netResolver resolves hosts with resolver of the standard library.
+type netResolver struct {
+	resolver *net.Resolver
+}
+
+// LookupHost implements Resolver.
+func (self *netResolver) LookupHost(host string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+	return self.resolver.LookupHost(ctx, host)
+}
@@ -0,0 +1,420 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitsource
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fetchTimeout is the timeout of every request made when fetching a single Git source.
+const fetchTimeout = 60 * time.Second
+
+const (
+	// MaxManifestsSize is the maximum size in bytes of all manifests fetched from a Git source.
+	MaxManifestsSize = 1 << 20
+
+	// MaxManifestFiles is the maximum number of manifest files fetched from a Git source.
+	MaxManifestFiles = 1000
+
+	// MaxArchiveSize is the maximum size in bytes of a downloaded repository archive.
+	MaxArchiveSize = 32 << 20
+
+	// MaxArchiveContentSize is the maximum size in bytes of unpacked content of a repository
+	// archive, including files outside of the source path.
+	MaxArchiveContentSize = 128 << 20
+
+	// maxRefsSize is the maximum size in bytes of the list of references of a repository.
+	maxRefsSize = 4 << 20
+
+	// Maximum number of redirects followed when fetching a Git source.
+	maxRedirects = 10
+)
+
+// AllowedHosts are hosts of repositories manifests can be fetched from. Repositories hosted on
+// github.com are fetched through the GitHub API, all other hosts have to serve the GitLab API.
+var AllowedHosts = []string{"github.com", "gitlab.com"}
+
+// manifestExtensions are extensions of files fetched as manifests.
+var manifestExtensions = map[string]bool{".yaml": true, ".yml": true, ".json": true}
+
+var commitRegexp = regexp.MustCompile("^[0-9a-f]{40}$")
+
+// Source is a location of manifests in a Git repository.
+type Source struct {
+	// HTTPS URL of the repository, e.g. https://github.com/org/repo.git.
+	URL string `json:"url"`
+
+	// Branch, tag or commit to fetch. HEAD of the repository is fetched if empty.
+	Ref string `json:"ref"`
+
+	// Path of directory or file in the repository. Root of the repository is used if empty.
+	Path string `json:"path"`
+}
+
+// Credentials are used to authenticate to private repositories. Password is an access token
+// with read access to the repository, as archives are downloaded through APIs of Git hosts.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// Manifests are files fetched from a Git source.
+type Manifests struct {
+	// SHA of the fetched commit.
+	Commit string
+
+	// Contents of manifest files keyed by their path relative to the source path.
+	Files map[string]string
+}
+
+// Fetcher fetches manifests from Git repositories. It never pushes anything.
+type Fetcher interface {
+	// Fetch returns manifests of given source. Credentials are optional.
+	Fetch(source Source, credentials *Credentials) (*Manifests, error)
+}
+
+// ArchiveFetcher is an implementation of Fetcher that resolves the ref over the smart HTTP
+// protocol of Git and downloads an archive of the commit over HTTPS. It does not need git.
+type ArchiveFetcher struct {
+	// transport of requests, proxy from environment of the dashboard is used if nil.
+	transport http.RoundTripper
+}
+
+// Fetch resolves the ref of given source and returns manifests found under its path in the
+// archive of the commit.
+func (self ArchiveFetcher) Fetch(source Source, credentials *Credentials) (*Manifests, error) {
+	repository, err := validateSource(source)
+	if err != nil {
+		return nil, err
+	}
+
+	client := self.newClient(repository.Host)
+	refs, err := getRefs(client, repository, credentials)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := resolveRef(refs, source.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := get(client, getArchiveURL(repository, commit), credentials, repository.Host)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	files, err := readArchive(&limitedReader{reader: archive, remaining: MaxArchiveSize,
+		err: &sizeLimitError{"Git archive", MaxArchiveSize}}, cleanPath(source.Path))
+	if err != nil {
+		return nil, err
+	}
+	return &Manifests{Commit: commit, Files: files}, nil
+}
+
+// newClient returns client following redirects only to HTTPS URLs on given host or its
+// subdomains, e.g. codeload.github.com for archives of GitHub.
+func (self ArchiveFetcher) newClient(host string) *http.Client {
+	transport := self.transport
+	if transport == nil {
+		transport = &http.Transport{Proxy: http.ProxyFromEnvironment}
+	}
+	return &http.Client{
+		Timeout:   fetchTimeout,
+		Transport: transport,
+		CheckRedirect: func(request *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			if request.URL.Scheme != "https" || !isHostOrSubdomain(request.URL.Host, host) {
+				return fmt.Errorf("redirect to %s refused", request.URL)
+			}
+			return nil
+		},
+	}
+}
+
+// validateSource allows only HTTPS sources on allowed hosts, so that the dashboard cannot be
+// used to reach arbitrary servers, and returns URL of the repository.
+func validateSource(source Source) (*url.URL, error) {
+	repository, err := url.Parse(source.URL)
+	if err != nil || repository.Scheme != "https" || repository.User != nil ||
+		repository.RawQuery != "" || repository.Fragment != "" || getRepositoryPath(repository) == "" {
+		return nil, fmt.Errorf("Git source has to be an HTTPS URL without credentials: %s", source.URL)
+	}
+	if !isAllowedHost(repository.Host) {
+		return nil, fmt.Errorf("Git sources are not allowed on %s", repository.Host)
+	}
+	if repository.Host == "github.com" && strings.Count(getRepositoryPath(repository), "/") != 1 {
+		return nil, fmt.Errorf("GitHub source has to be in https://github.com/org/repo format: %s",
+			source.URL)
+	}
+	if strings.HasPrefix(source.Ref, "-") {
+		return nil, fmt.Errorf("Invalid Git ref: %s", source.Ref)
+	}
+	if sourcePath := cleanPath(source.Path); sourcePath == ".." || strings.HasPrefix(sourcePath, "../") {
+		return nil, fmt.Errorf("Git source path %s is outside of the repository", source.Path)
+	}
+	return repository, nil
+}
+
+func isAllowedHost(host string) bool {
+	for _, allowed := range AllowedHosts {
+		if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func isHostOrSubdomain(host, domain string) bool {
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+func cleanPath(sourcePath string) string {
+	return path.Clean(strings.TrimPrefix(sourcePath, "/"))
+}
+
+// getRepositoryPath returns path of the repository on its host without .git suffix.
+func getRepositoryPath(repository *url.URL) string {
+	return strings.TrimSuffix(strings.Trim(repository.Path, "/"), ".git")
+}
+
+// getArchiveURL returns URL of gzipped tar archive of given commit. GitHub API is used for
+// github.com, GitLab API for all other hosts.
+func getArchiveURL(repository *url.URL, commit string) string {
+	repositoryPath := getRepositoryPath(repository)
+	if repository.Host == "github.com" {
+		return "https://api.github.com/repos/" + repositoryPath + "/tarball/" + commit
+	}
+	return "https://" + repository.Host + "/api/v4/projects/" + url.PathEscape(repositoryPath) +
+		"/repository/archive.tar.gz?sha=" + commit
+}
+
+// get requests given URL with credentials. GitHub takes access tokens as basic auth password,
+// GitLab API only in its own header. Response body has to be closed by the caller.
+func get(client *http.Client, requestURL string, credentials *Credentials, host string) (
+	io.ReadCloser, error) {
+	request, err := http.NewRequest("GET", requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if credentials != nil {
+		request.SetBasicAuth(credentials.Username, credentials.Password)
+		if host != "github.com" {
+			request.Header.Set("PRIVATE-TOKEN", credentials.Password)
+		}
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot fetch Git source: %s", err.Error())
+	}
+	if response.StatusCode != http.StatusOK {
+		response.Body.Close()
+		return nil, fmt.Errorf("Cannot fetch Git source: %s responded with %d", request.URL.Host,
+			response.StatusCode)
+	}
+	return response.Body, nil
+}
+
+// getRefs returns commits of references of the repository, as advertised by its smart HTTP
+// endpoint.
+func getRefs(client *http.Client, repository *url.URL, credentials *Credentials) (
+	map[string]string, error) {
+	refsURL := *repository
+	refsURL.Path = strings.TrimSuffix(refsURL.Path, "/") + "/info/refs"
+	refsURL.RawQuery = "service=git-upload-pack"
+	body, err := get(client, refsURL.String(), credentials, repository.Host)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return parseRefs(io.LimitReader(body, maxRefsSize))
+}
+
+// parseRefs parses reference advertisement of version 0 of the Git protocol, made of pkt-lines
+// with commit and name of a reference each. Capabilities after the first reference are ignored.
+func parseRefs(reader io.Reader) (map[string]string, error) {
+	refs := make(map[string]string)
+	buffered := bufio.NewReader(reader)
+	for {
+		length := make([]byte, 4)
+		if _, err := io.ReadFull(buffered, length); err == io.EOF {
+			return refs, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("Invalid Git references: %s", err.Error())
+		}
+		size, err := strconv.ParseUint(string(length), 16, 16)
+		if err != nil || (size != 0 && size < 4) {
+			return nil, fmt.Errorf("Invalid Git references: bad pkt-line length %q", length)
+		}
+		if size == 0 {
+			continue
+		}
+
+		line := make([]byte, size-4)
+		if _, err := io.ReadFull(buffered, line); err != nil {
+			return nil, fmt.Errorf("Invalid Git references: %s", err.Error())
+		}
+		text := strings.TrimSuffix(strings.SplitN(string(line), "\x00", 2)[0], "\n")
+		if strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.SplitN(text, " ", 2)
+		if len(fields) == 2 && commitRegexp.MatchString(fields[0]) {
+			refs[fields[1]] = fields[0]
+		}
+	}
+}
+
+// resolveRef returns commit of given branch, tag or commit. Annotated tags are resolved to
+// commits they point to.
+func resolveRef(refs map[string]string, ref string) (string, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	if commitRegexp.MatchString(ref) {
+		return ref, nil
+	}
+	for _, name := range []string{ref, "refs/heads/" + ref, "refs/tags/" + ref} {
+		if commit, ok := refs[name+"^{}"]; ok {
+			return commit, nil
+		}
+		if commit, ok := refs[name]; ok {
+			return commit, nil
+		}
+	}
+	return "", fmt.Errorf("Git ref %s not found", ref)
+}
+
+// readArchive reads manifest files of given file or directory from gzipped tar archive of a
+// repository, with the repository in its only top-level directory.
+func readArchive(reader io.Reader, sourcePath string) (map[string]string, error) {
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, getArchiveError(err)
+	}
+	defer gzipReader.Close()
+
+	content := &limitedReader{reader: gzipReader, remaining: MaxArchiveContentSize,
+		err: &sizeLimitError{"Content of Git archive", MaxArchiveContentSize}}
+	tarReader := tar.NewReader(content)
+	files := make(map[string]string)
+	size := 0
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, getArchiveError(err)
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+
+		name, ok := getManifestName(header.Name, sourcePath)
+		if !ok || (!manifestExtensions[path.Ext(name)] && !isKustomizationFile(name)) {
+			continue
+		}
+		if len(files) >= MaxManifestFiles {
+			return nil, fmt.Errorf("Git source has more than %d manifests", MaxManifestFiles)
+		}
+		size += int(header.Size)
+		if size > MaxManifestsSize {
+			return nil, fmt.Errorf("Manifests are larger than %d bytes", MaxManifestsSize)
+		}
+		data, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return nil, getArchiveError(err)
+		}
+		files[name] = string(data)
+	}
+	return files, nil
+}
+
+// limitedReader reads at most given number of bytes and then fails with given error, so that
+// too large archives are told apart from truncated ones.
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+	err       *sizeLimitError
+}
+
+func (self *limitedReader) Read(buffer []byte) (int, error) {
+	if self.remaining <= 0 {
+		return 0, self.err
+	}
+	if int64(len(buffer)) > self.remaining {
+		buffer = buffer[:self.remaining]
+	}
+	n, err := self.reader.Read(buffer)
+	self.remaining -= int64(n)
+	return n, err
+}
+
+// sizeLimitError is returned when a limit of archive size is exceeded.
+type sizeLimitError struct {
+	what  string
+	limit int64
+}
+
+func (self *sizeLimitError) Error() string {
+	return fmt.Sprintf("%s is larger than %d bytes", self.what, self.limit)
+}
+
+// getArchiveError returns errors of size limits as they are, other errors as invalid archive.
+func getArchiveError(err error) error {
+	if _, ok := err.(*sizeLimitError); ok {
+		return err
+	}
+	return fmt.Errorf("Invalid Git archive: %s", err.Error())
+}
+
+// getManifestName returns path of archive entry relative to the source path, or false if the
+// entry is outside of it. A source path of a file gives its base name.
+func getManifestName(entryName, sourcePath string) (string, bool) {
+	parts := strings.SplitN(path.Clean(entryName), "/", 2)
+	if len(parts) != 2 {
+		return "", false
+	}
+	name := parts[1]
+	switch {
+	case sourcePath == ".":
+		return name, true
+	case name == sourcePath:
+		return path.Base(name), true
+	case strings.HasPrefix(name, sourcePath+"/"):
+		return strings.TrimPrefix(name, sourcePath+"/"), true
+	}
+	return "", false
+}
+
+func isKustomizationFile(name string) bool {
+	return path.Base(name) == "Kustomization"
+}
@@ -0,0 +1,103 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logsource
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/logs"
+)
+
+// elasticsearchLogSource reads logs from Elasticsearch. Documents are expected to follow the
+// format of fluentd Kubernetes metadata filter, which is used by the cluster logging addon.
+type elasticsearchLogSource struct {
+	host   string
+	index  string
+	client *http.Client
+}
+
+// elasticsearchResponse is a subset of Elasticsearch search response.
+type elasticsearchResponse struct {
+	Hits struct {
+		Hits []struct {
+			Source struct {
+				Timestamp time.Time `json:"@timestamp"`
+				Log       string    `json:"log"`
+				Message   string    `json:"message"`
+			} `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// GetLogs implements LogSource interface.
+func (self *elasticsearchLogSource) GetLogs(namespace, pod, container string, since time.Time,
+	limit int) (logs.LogLines, error) {
+
+	query := map[string]interface{}{
+		"size": limit,
+		"sort": []interface{}{map[string]interface{}{"@timestamp": map[string]string{"order": "asc"}}},
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []interface{}{
+					map[string]interface{}{"term": map[string]string{"kubernetes.namespace_name": namespace}},
+					map[string]interface{}{"term": map[string]string{"kubernetes.pod_name": pod}},
+					map[string]interface{}{"term": map[string]string{"kubernetes.container_name": container}},
+					map[string]interface{}{"range": map[string]interface{}{
+						"@timestamp": map[string]string{"gte": since.UTC().Format(time.RFC3339Nano)},
+					}},
+				},
+			},
+		},
+	}
+
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := self.client.Post(self.host+"/"+self.index+"/_search", "application/json",
+		bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Elasticsearch responded with %d", response.StatusCode)
+	}
+
+	result := elasticsearchResponse{}
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	logLines := logs.LogLines{}
+	for _, hit := range result.Hits.Hits {
+		content := hit.Source.Log
+		if content == "" {
+			content = hit.Source.Message
+		}
+		logLines = append(logLines, logs.LogLine{
+			Timestamp: formatTimestamp(hit.Source.Timestamp),
+			Content:   strings.TrimSuffix(content, "\n"),
+		})
+	}
+	return logLines, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logsource
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/logs"
+)
+
+// requestTimeout is the timeout of requests to log backends.
+const requestTimeout = 10 * time.Second
+
+// Supported log source kinds.
+const (
+	LogSourceLoki          = "loki"
+	LogSourceElasticsearch = "elasticsearch"
+)
+
+// LogSource is a source of historical container logs, e.g. Loki or Elasticsearch. It allows to
+// read logs of restarted and deleted pods that are no longer available from the kubelet.
+type LogSource interface {
+	// GetLogs returns at most limit log lines of given container written after since, ordered
+	// from the oldest. Timestamps of returned lines are in RFC3339Nano format, the same as
+	// timestamps of kubelet logs.
+	GetLogs(namespace, pod, container string, since time.Time, limit int) (logs.LogLines, error)
+}
+
+// CreateLogSource creates log source of given kind. Returns nil log source when kind is empty, i.e.
+// historical logs are disabled. host param is in the format of protocol://address:port, e.g.,
+// http://localhost:3100. index is used only by Elasticsearch log source.
+func CreateLogSource(kind, host, index string) (LogSource, error) {
+	host = strings.TrimSuffix(host, "/")
+	client := &http.Client{Timeout: requestTimeout}
+
+	switch kind {
+	case "":
+		return nil, nil
+	case LogSourceLoki:
+		log.Printf("Creating Loki log source for %s", host)
+		return &lokiLogSource{host: host, client: client}, nil
+	case LogSourceElasticsearch:
+		log.Printf("Creating Elasticsearch log source for %s, index %s", host, index)
+		return &elasticsearchLogSource{host: host, index: index, client: client}, nil
+	default:
+		return nil, fmt.Errorf("Unknown log source: %s", kind)
+	}
+}
+
+// formatTimestamp formats time the way kubelet does in timestamped logs.
+func formatTimestamp(t time.Time) logs.LogTimestamp {
+	return logs.LogTimestamp(t.UTC().Format(time.RFC3339Nano))
+}
@@ -0,0 +1,98 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logsource
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/logs"
+)
+
+func TestCreateLogSource(t *testing.T) {
+	cases := []struct {
+		kind        string
+		expectNil   bool
+		expectError bool
+	}{
+		{"", true, false},
+		{LogSourceLoki, false, false},
+		{LogSourceElasticsearch, false, false},
+		{"splunk", true, true},
+	}
+
+	for _, c := range cases {
+		actual, err := CreateLogSource(c.kind, "http://localhost:3100", "logstash-*")
+		if (actual == nil) != c.expectNil || (err != nil) != c.expectError {
+			t.Errorf("CreateLogSource(%#v) == %#v, %v", c.kind, actual, err)
+		}
+	}
+}
+
+func TestLokiLogSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expectedQuery := `{namespace="default", pod="foo", container="bar"}`
+		if r.URL.Path != "/loki/api/v1/query_range" || r.URL.Query().Get("query") != expectedQuery {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"data": {"result": [
+			{"values": [["1493978400000000000", "first"], ["1493978402000000000", "third"]]},
+			{"values": [["1493978401500000000", "second"]]}
+		]}}`))
+	}))
+	defer server.Close()
+
+	source, _ := CreateLogSource(LogSourceLoki, server.URL, "")
+	actual, err := source.GetLogs("default", "foo", "bar", time.Unix(0, 0), 10)
+	expected := logs.LogLines{
+		{Timestamp: "2017-05-05T10:00:00Z", Content: "first"},
+		{Timestamp: "2017-05-05T10:00:01.5Z", Content: "second"},
+		{Timestamp: "2017-05-05T10:00:02Z", Content: "third"},
+	}
+	if err != nil || !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GetLogs() == \ngot: %#v, %v, \nexpected %#v", actual, err, expected)
+	}
+}
+
+func TestElasticsearchLogSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := map[string]interface{}{}
+		if r.URL.Path != "/logstash-*/_search" || json.NewDecoder(r.Body).Decode(&query) != nil ||
+			query["size"] != float64(10) {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"hits": {"hits": [
+			{"_source": {"@timestamp": "2017-05-05T10:00:00Z", "log": "first\n"}},
+			{"_source": {"@timestamp": "2017-05-05T10:00:01.5Z", "message": "second"}}
+		]}}`))
+	}))
+	defer server.Close()
+
+	source, _ := CreateLogSource(LogSourceElasticsearch, server.URL, "logstash-*")
+	actual, err := source.GetLogs("default", "foo", "bar", time.Unix(0, 0), 10)
+	expected := logs.LogLines{
+		{Timestamp: "2017-05-05T10:00:00Z", Content: "first"},
+		{Timestamp: "2017-05-05T10:00:01.5Z", Content: "second"},
+	}
+	if err != nil || !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GetLogs() == \ngot: %#v, %v, \nexpected %#v", actual, err, expected)
+	}
+}
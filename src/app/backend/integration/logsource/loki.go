@@ -0,0 +1,109 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logsource
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/logs"
+)
+
+// lokiLogSource reads logs from Loki using its HTTP query API. Streams are expected to be labeled
+// with namespace, pod and container labels, which is the default of Promtail Kubernetes config.
+type lokiLogSource struct {
+	host   string
+	client *http.Client
+}
+
+// lokiResponse is a subset of Loki query_range response.
+type lokiResponse struct {
+	Data struct {
+		Result []struct {
+			// Pairs of nanosecond Unix timestamp and log line.
+			Values [][]string `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// GetLogs implements LogSource interface.
+func (self *lokiLogSource) GetLogs(namespace, pod, container string, since time.Time,
+	limit int) (logs.LogLines, error) {
+
+	query := url.Values{}
+	query.Set("query", fmt.Sprintf(`{namespace=%q, pod=%q, container=%q}`, namespace, pod, container))
+	query.Set("start", strconv.FormatInt(since.UnixNano(), 10))
+	query.Set("end", strconv.FormatInt(time.Now().UnixNano(), 10))
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("direction", "forward")
+
+	response, err := self.client.Get(self.host + "/loki/api/v1/query_range?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Loki responded with %d", response.StatusCode)
+	}
+
+	result := lokiResponse{}
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	entries := make([]timedLine, 0)
+	for _, stream := range result.Data.Result {
+		for _, value := range stream.Values {
+			if len(value) != 2 {
+				continue
+			}
+			nanos, err := strconv.ParseInt(value[0], 10, 64)
+			if err != nil {
+				continue
+			}
+			entries = append(entries, timedLine{time.Unix(0, nanos), value[1]})
+		}
+	}
+
+	// Lines of different streams are interleaved, so they have to be sorted again.
+	sort.Stable(byTime(entries))
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	logLines := logs.LogLines{}
+	for _, entry := range entries {
+		logLines = append(logLines, logs.LogLine{Timestamp: formatTimestamp(entry.time), Content: entry.line})
+	}
+	return logLines, nil
+}
+
+// timedLine is a log line with parsed timestamp.
+type timedLine struct {
+	time time.Time
+	line string
+}
+
+type byTime []timedLine
+
+func (self byTime) Len() int           { return len(self) }
+func (self byTime) Swap(i, j int)      { self[i], self[j] = self[j], self[i] }
+func (self byTime) Less(i, j int) bool { return self[i].time.Before(self[j].time) }
@@ -0,0 +1,103 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/heapster/metrics/api/v1/types"
+)
+
+// ModelSource is a source of metrics that has no Heapster model API of its own. Requests to the
+// model API are translated to it by ModelRequest.
+type ModelSource interface {
+	// GetPodMetrics returns given metric of pods with given names in given namespace since given
+	// time, in the order of the names. Zero time is the beginning of the default window.
+	GetPodMetrics(namespace string, names []string, metricName string, since time.Time) (
+		[]types.MetricResult, error)
+
+	// GetNodeMetric returns given metric of node with given name since given time.
+	GetNodeMetric(name, metricName string, since time.Time) (types.MetricResult, error)
+}
+
+// ModelRequest is a request to the Heapster model API served by a model source.
+type ModelRequest struct {
+	Source ModelSource
+
+	// Path of the request, e.g. /model/namespaces/default/pod-list/foo,bar/metrics/cpu/usage_rate.
+	Path string
+}
+
+// DoRaw returns metrics in the format of the Heapster model API. Paths of a single pod or node and
+// of a pod list are supported.
+func (r ModelRequest) DoRaw() ([]byte, error) {
+	path, since, err := parseModelPath(r.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(path, "/metrics/", 2)
+	if len(parts) == 2 {
+		resource, metricName := strings.Split(parts[0], "/"), parts[1]
+		switch {
+		case len(resource) == 4 && resource[0] == "namespaces" &&
+			(resource[2] == "pod-list" || resource[2] == "pods"):
+			names := strings.Split(resource[3], ",")
+			if resource[2] == "pods" {
+				names = names[:1]
+			}
+			items, err := r.Source.GetPodMetrics(resource[1], names, metricName, since)
+			if err != nil {
+				return nil, err
+			}
+			if resource[2] == "pods" {
+				return json.Marshal(items[0])
+			}
+			return json.Marshal(types.MetricResultList{Items: items})
+		case len(resource) == 2 && resource[0] == "nodes":
+			result, err := r.Source.GetNodeMetric(resource[1], metricName, since)
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(result)
+		}
+	}
+	return nil, NewMetricNotFoundError(r.Path)
+}
+
+// NewMetricNotFoundError returns error of metrics requested by given path that are not served.
+func NewMetricNotFoundError(path string) error {
+	return k8serrors.NewNotFound(schema.GroupResource{Resource: "metrics"}, path)
+}
+
+// parseModelPath returns path of given request without the model prefix and the beginning of the
+// requested metric window.
+func parseModelPath(path string) (string, time.Time, error) {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/model/"), "?", 2)
+	if len(parts) < 2 {
+		return parts[0], time.Time{}, nil
+	}
+	query, err := url.ParseQuery(parts[1])
+	if err != nil || query.Get("start") == "" {
+		return parts[0], time.Time{}, err
+	}
+	since, err := time.Parse(time.RFC3339, query.Get("start"))
+	return parts[0], since, err
+}
@@ -0,0 +1,31 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+// MetricClient is a client used to make requests to a source of metrics, e.g. Heapster or
+// metrics-server. Requests use paths of the Heapster model API, which every implementation
+// serves as far as it has the data.
+type MetricClient interface {
+	// Creates a new GET HTTP request to the metric source, specified by the path param. The path
+	// param is without the API prefix, e.g.,
+	// /model/namespaces/default/pod-list/foo/metrics/memory-usage
+	Get(path string) RequestInterface
+}
+
+// RequestInterface is an interface that allows to make operations on pure request object.
+// Separation is done to allow testing.
+type RequestInterface interface {
+	DoRaw() ([]byte, error)
+}
@@ -16,26 +16,20 @@ package heapster
 
 import (
 	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/kubernetes/dashboard/src/app/backend/client"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
 
-// HeapsterClient  is a client used to make requests to a Heapster instance.
-type HeapsterClient interface {
-	// Creates a new GET HTTP request to heapster, specified by the path param, to the V1 API
-	// endpoint. The path param is without the API prefix, e.g.,
-	// /model/namespaces/default/pod-list/foo/metrics/memory-usage
-	Get(path string) RequestInterface
-}
-
-// RequestInterface is an interface that allows to make operations on pure request object.
-// Separation is done to allow testing.
-type RequestInterface interface {
-	DoRaw() ([]byte, error)
-}
-
 // InClusterHeapsterClient is an in-cluster implementation of a Heapster client. Talks with Heapster
 // through service proxy.
 type InClusterHeapsterClient struct {
@@ -43,12 +37,13 @@ type InClusterHeapsterClient struct {
 }
 
 // Get creates request to given path.
-func (c InClusterHeapsterClient) Get(path string) RequestInterface {
-	return c.client.Get().Prefix("proxy").
+func (c InClusterHeapsterClient) Get(path string) metricapi.RequestInterface {
+	path, query := splitQuery(path)
+	return withParams(c.client.Get().Prefix("proxy").
 		Namespace("kube-system").
 		Resource("services").
 		Name("heapster").
-		Suffix("/api/v1" + path)
+		Suffix("/api/v1"+path), query)
 }
 
 // RemoteHeapsterClient is an implementation of a remote Heapster client. Talks with Heapster
@@ -58,8 +53,33 @@ type RemoteHeapsterClient struct {
 }
 
 // Get creates request to given path.
-func (c RemoteHeapsterClient) Get(path string) RequestInterface {
-	return c.client.Get().Suffix(path)
+func (c RemoteHeapsterClient) Get(path string) metricapi.RequestInterface {
+	path, query := splitQuery(path)
+	return withParams(c.client.Get().Suffix(path), query)
+}
+
+// splitQuery splits given path into the path itself and its query parameters, as request suffix
+// would escape them otherwise.
+func splitQuery(path string) (string, url.Values) {
+	parts := strings.SplitN(path, "?", 2)
+	if len(parts) < 2 {
+		return path, nil
+	}
+	query, err := url.ParseQuery(parts[1])
+	if err != nil {
+		return parts[0], nil
+	}
+	return parts[0], query
+}
+
+// withParams sets given query parameters on given request.
+func withParams(request *rest.Request, query url.Values) *rest.Request {
+	for name, values := range query {
+		for _, value := range values {
+			request = request.Param(name, value)
+		}
+	}
+	return request
 }
 
 // CreateHeapsterRESTClient creates new Heapster REST client. When heapsterHost param is empty
@@ -67,18 +87,48 @@ func (c RemoteHeapsterClient) Get(path string) RequestInterface {
 // service proxy. heapsterHost param is in the format of protocol://address:port,
 // e.g., http://localhost:8002.
 func CreateHeapsterRESTClient(heapsterHost string, apiclient *kubernetes.Clientset) (
-	HeapsterClient, error) {
+	metricapi.MetricClient, error) {
 
 	if heapsterHost == "" {
 		log.Print("Creating in-cluster Heapster client")
-		return InClusterHeapsterClient{client: apiclient.Core().RESTClient()}, nil
+		return NewRetryingHeapsterClient(InClusterHeapsterClient{client: apiclient.Core().RESTClient()},
+			DefaultRetries, DefaultBackoff), nil
 	}
 
-	cfg := &rest.Config{Host: heapsterHost, QPS: client.DefaultQPS, Burst: client.DefaultBurst}
+	cfg := &rest.Config{Host: heapsterHost, QPS: client.DefaultQPS, Burst: client.DefaultBurst,
+		Transport: newPooledTransport(), Timeout: requestTimeout}
 	restClient, err := kubernetes.NewForConfig(cfg)
 	if err != nil {
 		return nil, err
 	}
 	log.Printf("Creating remote Heapster client for %s", heapsterHost)
-	return RemoteHeapsterClient{client: restClient.Core().RESTClient()}, nil
+	return NewRetryingHeapsterClient(RemoteHeapsterClient{client: restClient.Core().RESTClient()},
+		DefaultRetries, DefaultBackoff), nil
+}
+
+// IsInClusterHeapsterAvailable checks if the Heapster service the in-cluster client talks to
+// exists. Heapster is assumed to be available when the service cannot be read, e.g. because of
+// missing permissions.
+func IsInClusterHeapsterAvailable(apiclient kubernetes.Interface) bool {
+	_, err := apiclient.CoreV1().Services("kube-system").Get("heapster", metaV1.GetOptions{})
+	return !k8serrors.IsNotFound(err)
+}
+
+// Timeout of a single remote Heapster request.
+const requestTimeout = 30 * time.Second
+
+// newPooledTransport returns transport keeping enough idle connections to Heapster for metrics of
+// a whole list to be downloaded in parallel without opening new connections. Default transport
+// keeps only two idle connections per host.
+func newPooledTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		Dial: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).Dial,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 100,
+		IdleConnTimeout:     90 * time.Second,
+	}
 }
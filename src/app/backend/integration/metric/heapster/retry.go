@@ -0,0 +1,88 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heapster
+
+import (
+	"log"
+	"net"
+	"net/url"
+	"time"
+
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Default retry policy of Heapster requests.
+const (
+	DefaultRetries = 2
+	DefaultBackoff = 200 * time.Millisecond
+)
+
+// RetryingHeapsterClient is a Heapster client retrying requests that failed because of transient
+// errors, e.g. timeouts or Heapster being restarted.
+type RetryingHeapsterClient struct {
+	client  metricapi.MetricClient
+	retries int
+	backoff time.Duration
+}
+
+// NewRetryingHeapsterClient wraps given client, so that failed requests are retried given number
+// of times. Backoff doubles after every retry.
+func NewRetryingHeapsterClient(client metricapi.MetricClient, retries int,
+	backoff time.Duration) RetryingHeapsterClient {
+	return RetryingHeapsterClient{client: client, retries: retries, backoff: backoff}
+}
+
+// Get creates request to given path.
+func (c RetryingHeapsterClient) Get(path string) metricapi.RequestInterface {
+	return retryingRequest{client: c, path: path}
+}
+
+// retryingRequest is a request that is created again for every retry.
+type retryingRequest struct {
+	client RetryingHeapsterClient
+	path   string
+}
+
+// DoRaw performs the request and retries it in case of transient errors.
+func (r retryingRequest) DoRaw() ([]byte, error) {
+	backoff := r.client.backoff
+	for attempt := 0; ; attempt++ {
+		result, err := r.client.client.Get(r.path).DoRaw()
+		if err == nil || attempt >= r.client.retries || !isTransient(err) {
+			return result, err
+		}
+
+		log.Printf("Retrying Heapster request %s after error: %s", r.path, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// isTransient checks if given error may go away when the request is retried.
+func isTransient(err error) bool {
+	if statusErr, ok := err.(*k8serrors.StatusError); ok {
+		code := statusErr.Status().Code
+		return code >= 500 || code == 429
+	}
+	if _, ok := err.(*url.Error); ok {
+		// Transport errors, e.g. refused connections.
+		return true
+	}
+	if netErr, ok := err.(net.Error); ok {
+		return netErr.Timeout() || netErr.Temporary()
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heapster
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+type fakeResponse struct {
+	result []byte
+	err    error
+}
+
+type fakeHeapsterClient struct {
+	responses []fakeResponse
+	requests  *int
+}
+
+func (c fakeHeapsterClient) Get(path string) metricapi.RequestInterface {
+	response := c.responses[*c.requests]
+	*c.requests++
+	return fakeRequest(response)
+}
+
+type fakeRequest fakeResponse
+
+func (r fakeRequest) DoRaw() ([]byte, error) {
+	return r.result, r.err
+}
+
+func TestRetryingHeapsterClient(t *testing.T) {
+	unavailable := k8serrors.NewServiceUnavailable("heapster is restarting")
+	cases := []struct {
+		info             string
+		responses        []fakeResponse
+		expected         []byte
+		expectError      bool
+		expectedRequests int
+	}{
+		{"success", []fakeResponse{{[]byte("ok"), nil}}, []byte("ok"), false, 1},
+		{
+			"transient error",
+			[]fakeResponse{{nil, unavailable}, {[]byte("ok"), nil}},
+			[]byte("ok"), false, 2,
+		},
+		{
+			"transport error",
+			[]fakeResponse{{nil, &url.Error{Op: "Get", URL: "heapster", Err: errors.New("refused")}},
+				{[]byte("ok"), nil}},
+			[]byte("ok"), false, 2,
+		},
+		{
+			"permanent error",
+			[]fakeResponse{{nil, k8serrors.NewBadRequest("invalid")}},
+			nil, true, 1,
+		},
+		{
+			"retries exhausted",
+			[]fakeResponse{{nil, unavailable}, {nil, unavailable}, {nil, unavailable}},
+			nil, true, 3,
+		},
+	}
+	for _, c := range cases {
+		requests := 0
+		client := NewRetryingHeapsterClient(fakeHeapsterClient{c.responses, &requests}, 2, 0)
+
+		actual, err := client.Get("/model/metrics").DoRaw()
+		if (err != nil) != c.expectError {
+			t.Errorf("%s: DoRaw() returns error %v, expected error: %v", c.info, err, c.expectError)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s: DoRaw() returns %#v, expected %#v", c.info, actual, c.expected)
+		}
+		if requests != c.expectedRequests {
+			t.Errorf("%s: DoRaw() performed %d requests, expected %d", c.info, requests, c.expectedRequests)
+		}
+	}
+}
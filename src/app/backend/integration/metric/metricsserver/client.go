@@ -0,0 +1,237 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	api "k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/rest"
+	types "k8s.io/heapster/metrics/api/v1/types"
+)
+
+// GroupName is the API group metrics-server serves resource metrics in.
+const GroupName = "metrics.k8s.io"
+
+// Names of metrics served by the client. Units are the same as in Heapster, i.e. millicores and
+// bytes.
+const (
+	CpuUsage    = "cpu/usage_rate"
+	MemoryUsage = "memory/usage"
+)
+
+// DefaultWindow is how long scraped metrics are kept for. It matches the Heapster model window,
+// which sparklines are designed for.
+const DefaultWindow = 15 * time.Minute
+
+// DefaultInterval is how often metrics are scraped. Metrics-server resolution is one minute by
+// default.
+const DefaultInterval = time.Minute
+
+// resourceMetrics are metrics of a node or a pod as served by metrics-server.
+type resourceMetrics struct {
+	ObjectMeta metaV1.ObjectMeta `json:"metadata"`
+	Timestamp  metaV1.Time       `json:"timestamp"`
+
+	// Usage of a node.
+	Usage api.ResourceList `json:"usage"`
+
+	// Usage of containers of a pod.
+	Containers []struct {
+		Name  string           `json:"name"`
+		Usage api.ResourceList `json:"usage"`
+	} `json:"containers"`
+}
+
+type resourceMetricsList struct {
+	Items []resourceMetrics `json:"items"`
+}
+
+// MetricsServerClient is a metric client backed by metrics-server. Metrics-server serves only
+// current usage, so the client scrapes it periodically and keeps history of the usage in memory.
+// It serves the part of the Heapster model API that the dashboard uses.
+type MetricsServerClient struct {
+	client rest.Interface
+	// Path of the metrics API, e.g. /apis/metrics.k8s.io/v1beta1.
+	apiPath string
+	// How long points are kept for.
+	window time.Duration
+
+	lock sync.RWMutex
+	// Points keyed by path of the resource in the Heapster model API, e.g. nodes/foo, and metric name.
+	points map[string]map[string][]types.MetricPoint
+}
+
+// NewMetricsServerClient creates client that reads metrics from given API path with given client
+// of the API server and keeps history of given length.
+func NewMetricsServerClient(client rest.Interface, apiPath string,
+	window time.Duration) *MetricsServerClient {
+	return &MetricsServerClient{client: client, apiPath: apiPath, window: window,
+		points: make(map[string]map[string][]types.MetricPoint)}
+}
+
+// GetAPIPath returns path of the metrics API served by the cluster, or an empty string if
+// metrics-server is not deployed.
+func GetAPIPath(client discovery.ServerGroupsInterface) (string, error) {
+	groups, err := client.ServerGroups()
+	if err != nil {
+		return "", err
+	}
+	for _, group := range groups.Groups {
+		if group.Name == GroupName {
+			return "/apis/" + group.PreferredVersion.GroupVersion, nil
+		}
+	}
+	return "", nil
+}
+
+// Start starts scraping metrics every interval in background.
+func (self *MetricsServerClient) Start(interval time.Duration) {
+	log.Printf("Scraping metrics from %s every %s", self.apiPath, interval)
+	go func() {
+		for {
+			if err := self.scrape(); err != nil {
+				log.Printf("Couldn't scrape metrics from metrics-server: %s", err)
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
+
+func (self *MetricsServerClient) scrape() error {
+	nodes, err := self.list("nodes")
+	if err != nil {
+		return err
+	}
+	pods, err := self.list("pods")
+	if err != nil {
+		return err
+	}
+
+	self.record(nodes, pods, time.Now())
+	return nil
+}
+
+func (self *MetricsServerClient) list(resource string) ([]resourceMetrics, error) {
+	raw, err := common.JSONRequest(self.client.Get().AbsPath(self.apiPath, resource)).DoRaw()
+	if err != nil {
+		return nil, err
+	}
+	list := &resourceMetricsList{}
+	if err := json.Unmarshal(raw, list); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// record adds points of given nodes and pods and drops points older than the window. History of
+// nodes and pods that don't exist anymore is dropped.
+func (self *MetricsServerClient) record(nodes, pods []resourceMetrics, now time.Time) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	points := make(map[string]map[string][]types.MetricPoint)
+	oldest := now.Add(-self.window)
+	add := func(key string, item resourceMetrics, usage api.ResourceList) {
+		timestamp := item.Timestamp.Time
+		if timestamp.IsZero() {
+			timestamp = now
+		}
+		cpu, memory := usage[api.ResourceCPU], usage[api.ResourceMemory]
+		points[key] = map[string][]types.MetricPoint{
+			CpuUsage:    self.addPoint(key, CpuUsage, timestamp, uint64(cpu.MilliValue()), oldest),
+			MemoryUsage: self.addPoint(key, MemoryUsage, timestamp, uint64(memory.Value()), oldest),
+		}
+	}
+
+	for _, node := range nodes {
+		add("nodes/"+node.ObjectMeta.Name, node, node.Usage)
+	}
+	for _, pod := range pods {
+		// Usage of a pod is the sum of usage of its containers.
+		usage := api.ResourceList{}
+		for _, container := range pod.Containers {
+			for name, quantity := range container.Usage {
+				sum := usage[name]
+				sum.Add(quantity)
+				usage[name] = sum
+			}
+		}
+		add(fmt.Sprintf("namespaces/%s/pods/%s", pod.ObjectMeta.Namespace, pod.ObjectMeta.Name), pod,
+			usage)
+	}
+	self.points = points
+}
+
+// addPoint returns history of given metric with given point added, unless metrics-server has not
+// refreshed it since the last scrape, and points older than given time dropped.
+func (self *MetricsServerClient) addPoint(key, metricName string, timestamp time.Time, value uint64,
+	oldest time.Time) []types.MetricPoint {
+	history := self.points[key][metricName]
+	if last := len(history) - 1; last < 0 || history[last].Timestamp.Before(timestamp) {
+		history = append(history, types.MetricPoint{Timestamp: timestamp, Value: value})
+	}
+
+	start := 0
+	for start < len(history) && history[start].Timestamp.Before(oldest) {
+		start++
+	}
+	return history[start:]
+}
+
+// getMetric returns recorded points of given metric of given resource since given time.
+func (self *MetricsServerClient) getMetric(key, metricName string, since time.Time) types.MetricResult {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	result := types.MetricResult{Metrics: make([]types.MetricPoint, 0)}
+	for _, point := range self.points[key][metricName] {
+		if !point.Timestamp.Before(since) {
+			result.Metrics = append(result.Metrics, point)
+			result.LatestTimestamp = point.Timestamp
+		}
+	}
+	return result
+}
+
+// Get creates request to given path of the Heapster model API.
+func (self *MetricsServerClient) Get(path string) metricapi.RequestInterface {
+	return metricapi.ModelRequest{Source: self, Path: path}
+}
+
+// GetPodMetrics returns recorded points of given metric of given pods.
+func (self *MetricsServerClient) GetPodMetrics(namespace string, names []string, metricName string,
+	since time.Time) ([]types.MetricResult, error) {
+	items := make([]types.MetricResult, 0, len(names))
+	for _, name := range names {
+		key := fmt.Sprintf("namespaces/%s/pods/%s", namespace, name)
+		items = append(items, self.getMetric(key, metricName, since))
+	}
+	return items, nil
+}
+
+// GetNodeMetric returns recorded points of given metric of given node.
+func (self *MetricsServerClient) GetNodeMetric(name, metricName string, since time.Time) (
+	types.MetricResult, error) {
+	return self.getMetric("nodes/"+name, metricName, since), nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	api "k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/rest"
+	types "k8s.io/heapster/metrics/api/v1/types"
+)
+
+func TestMetricsServerClient(t *testing.T) {
+	start := time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+	newUsage := func(cpu, memory string) api.ResourceList {
+		return api.ResourceList{api.ResourceCPU: resource.MustParse(cpu),
+			api.ResourceMemory: resource.MustParse(memory)}
+	}
+	newNode := func(timestamp time.Time, cpu string) resourceMetrics {
+		return resourceMetrics{ObjectMeta: metaV1.ObjectMeta{Name: "node-1"},
+			Timestamp: metaV1.NewTime(timestamp), Usage: newUsage(cpu, "1Ki")}
+	}
+	newPod := func(timestamp time.Time) resourceMetrics {
+		pod := resourceMetrics{ObjectMeta: metaV1.ObjectMeta{Name: "foo", Namespace: "default"},
+			Timestamp: metaV1.NewTime(timestamp)}
+		for _, name := range []string{"app", "proxy"} {
+			pod.Containers = append(pod.Containers, struct {
+				Name  string           `json:"name"`
+				Usage api.ResourceList `json:"usage"`
+			}{name, newUsage("150m", "1Mi")})
+		}
+		return pod
+	}
+
+	client := NewMetricsServerClient(nil, "/apis/metrics.k8s.io/v1beta1", 15*time.Minute)
+	client.record([]resourceMetrics{newNode(start, "1")}, nil, start)
+	client.record([]resourceMetrics{newNode(start.Add(time.Minute), "2")},
+		[]resourceMetrics{newPod(start.Add(time.Minute))}, start.Add(time.Minute))
+	// metrics-server did not refresh metrics of the node
+	client.record([]resourceMetrics{newNode(start.Add(time.Minute), "3")},
+		[]resourceMetrics{newPod(start.Add(2 * time.Minute))}, start.Add(2*time.Minute))
+	// points older than the window are dropped
+	client.record([]resourceMetrics{newNode(start.Add(16*time.Minute), "4")},
+		[]resourceMetrics{newPod(start.Add(2 * time.Minute))}, start.Add(16*time.Minute))
+
+	cases := []struct {
+		path        string
+		expected    interface{}
+		expectError bool
+	}{
+		{
+			"/model/nodes/node-1/metrics/cpu/usage_rate",
+			types.MetricResult{Metrics: []types.MetricPoint{
+				{Timestamp: start.Add(time.Minute), Value: 2000},
+				{Timestamp: start.Add(16 * time.Minute), Value: 4000},
+			}, LatestTimestamp: start.Add(16 * time.Minute)},
+			false,
+		},
+		{
+			"/model/nodes/node-1/metrics/memory/usage?start=2017-05-05T10:10:00Z",
+			types.MetricResult{Metrics: []types.MetricPoint{
+				{Timestamp: start.Add(16 * time.Minute), Value: 1024},
+			}, LatestTimestamp: start.Add(16 * time.Minute)},
+			false,
+		},
+		{
+			"/model/namespaces/default/pod-list/foo,bar/metrics/cpu/usage_rate",
+			types.MetricResultList{Items: []types.MetricResult{
+				{Metrics: []types.MetricPoint{
+					{Timestamp: start.Add(time.Minute), Value: 300},
+					{Timestamp: start.Add(2 * time.Minute), Value: 300},
+				}, LatestTimestamp: start.Add(2 * time.Minute)},
+				{Metrics: []types.MetricPoint{}},
+			}},
+			false,
+		},
+		{"/model/namespaces/default/pod-list/foo/metrics/cpu/usage_rate?start=today", nil, true},
+		{"/model/metrics/cpu/usage_rate", nil, true},
+	}
+	for _, c := range cases {
+		raw, err := client.Get(c.path).DoRaw()
+		if (err != nil) != c.expectError {
+			t.Errorf("Get(%#v).DoRaw() returns error %v, expected error: %v", c.path, err, c.expectError)
+		}
+		if err != nil || c.expectError {
+			continue
+		}
+
+		expected, _ := json.Marshal(c.expected)
+		if !reflect.DeepEqual(raw, expected) {
+			t.Errorf("Get(%#v).DoRaw() == \ngot: %s, \nexpected %s", c.path, raw, expected)
+		}
+	}
+}
+
+func TestMetricsServerClientList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Like the API server, answer in protobuf unless JSON is accepted explicitly.
+		if !strings.HasPrefix(r.Header.Get("Accept"), runtime.ContentTypeJSON) {
+			w.Header().Set("Content-Type", "application/vnd.kubernetes.protobuf")
+			w.Write([]byte("k8s\x00"))
+			return
+		}
+		w.Header().Set("Content-Type", runtime.ContentTypeJSON)
+		w.Write([]byte(`{"items": [{"metadata": {"name": "node-1"}, "usage": {"cpu": "1"}}]}`))
+	}))
+	defer server.Close()
+
+	k8sClient, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL,
+		ContentConfig: rest.ContentConfig{ContentType: "application/vnd.kubernetes.protobuf"}})
+	if err != nil {
+		t.Fatalf("Cannot create client: %s", err)
+	}
+
+	client := NewMetricsServerClient(k8sClient.CoreV1().RESTClient(), "/apis/metrics.k8s.io/v1beta1",
+		15*time.Minute)
+	items, err := client.list("nodes")
+	if err != nil || len(items) != 1 || items[0].ObjectMeta.Name != "node-1" {
+		t.Errorf("list(\"nodes\") == \ngot: %#v, %v, \nexpected node-1 metrics", items, err)
+	}
+}
@@ -0,0 +1,278 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	types "k8s.io/heapster/metrics/api/v1/types"
+)
+
+// Names of metrics served by the client. Units are the same as in Heapster, i.e. millicores and
+// bytes.
+const (
+	CpuUsage    = "cpu/usage_rate"
+	MemoryUsage = "memory/usage"
+)
+
+// DefaultWindow is the metric window used when a request does not set its beginning. It matches
+// the Heapster model window, which sparklines are designed for.
+const DefaultWindow = 15 * time.Minute
+
+// step is the resolution of returned metrics. It matches the Heapster model resolution.
+const step = time.Minute
+
+// rateWindow is the range cumulative CPU usage is turned into usage rate over. It has to cover
+// at least two scrapes of cAdvisor.
+const rateWindow = "2m"
+
+// requestTimeout is the timeout of requests to Prometheus. Metrics are embedded into list
+// responses, so slow Prometheus must not block the dashboard.
+const requestTimeout = 10 * time.Second
+
+// maxResponseSize is the maximum size of a Prometheus response in bytes. Responses are read
+// into memory, so a misbehaving Prometheus must not exhaust it.
+var maxResponseSize int64 = 16 << 20
+
+// Labels of cAdvisor metrics that identify a pod, its container and a node. These are the names
+// used by the kubelet and the node label set by the example Kubernetes scrape configuration.
+var (
+	PodLabel       = "pod_name"
+	ContainerLabel = "container_name"
+	NodeLabel      = "instance"
+)
+
+// PrometheusClient is a metric client backed by Prometheus that scrapes cAdvisor metrics of the
+// cluster. It serves the part of the Heapster model API that the dashboard uses by translating
+// requests to range queries.
+type PrometheusClient struct {
+	host        string
+	bearerToken string
+	client      *http.Client
+	now         func() time.Time
+}
+
+// CreatePrometheusClient creates new Prometheus client. prometheusHost param is in the format of
+// protocol://address:port, e.g., http://localhost:9090. Requests are authorized with the bearer
+// token stored in bearerTokenFile, unless it is empty.
+func CreatePrometheusClient(prometheusHost, bearerTokenFile string) (*PrometheusClient, error) {
+	if prometheusHost == "" {
+		return nil, fmt.Errorf("Prometheus host is not set")
+	}
+
+	bearerToken := ""
+	if bearerTokenFile != "" {
+		raw, err := ioutil.ReadFile(bearerTokenFile)
+		if err != nil {
+			return nil, err
+		}
+		bearerToken = strings.TrimSpace(string(raw))
+	}
+
+	log.Printf("Creating Prometheus client for %s", prometheusHost)
+	return &PrometheusClient{
+		host:        strings.TrimSuffix(prometheusHost, "/"),
+		bearerToken: bearerToken,
+		client:      &http.Client{Timeout: requestTimeout},
+		now:         time.Now,
+	}, nil
+}
+
+// Get creates request to given path of the Heapster model API.
+func (self *PrometheusClient) Get(path string) metricapi.RequestInterface {
+	return metricapi.ModelRequest{Source: self, Path: path}
+}
+
+// queryResult is a time series returned by a range query.
+type queryResult struct {
+	Metric map[string]string `json:"metric"`
+	// Pairs of Unix timestamp in seconds and value formatted as a string.
+	Values [][]interface{} `json:"values"`
+}
+
+type queryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string        `json:"resultType"`
+		Result     []queryResult `json:"result"`
+	} `json:"data"`
+}
+
+// queryRange evaluates given PromQL expression over window starting at given time and returns
+// resulting time series keyed by value of given label.
+func (self *PrometheusClient) queryRange(query, label string, since time.Time) (
+	map[string]types.MetricResult, error) {
+	end := self.now()
+	if since.IsZero() {
+		since = end.Add(-DefaultWindow)
+	}
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("start", strconv.FormatInt(since.Unix(), 10))
+	params.Set("end", strconv.FormatInt(end.Unix(), 10))
+	params.Set("step", strconv.FormatInt(int64(step/time.Second), 10))
+
+	req, err := http.NewRequest("GET", self.host+"/api/v1/query_range?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if self.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+self.bearerToken)
+	}
+
+	response, err := self.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	raw, err := ioutil.ReadAll(io.LimitReader(response.Body, maxResponseSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(raw)) > maxResponseSize {
+		return nil, fmt.Errorf("Prometheus response exceeds %d bytes", maxResponseSize)
+	}
+
+	result := &queryResponse{}
+	if err := json.Unmarshal(raw, result); err != nil || result.Status != "success" {
+		if result.Error == "" {
+			result.Error = strings.TrimSpace(string(raw))
+		}
+		return nil, fmt.Errorf("Prometheus responded with %d: %s", response.StatusCode, result.Error)
+	}
+
+	series := make(map[string]types.MetricResult)
+	for _, item := range result.Data.Result {
+		series[item.Metric[label]] = toMetricResult(item.Values)
+	}
+	return series, nil
+}
+
+// toMetricResult converts values of a time series to metric points. Values that can't be parsed
+// are skipped.
+func toMetricResult(values [][]interface{}) types.MetricResult {
+	result := types.MetricResult{Metrics: make([]types.MetricPoint, 0)}
+	for _, value := range values {
+		if len(value) != 2 {
+			continue
+		}
+		timestamp, ok := value[0].(float64)
+		if !ok {
+			continue
+		}
+		formatted, ok := value[1].(string)
+		if !ok {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(formatted, 64)
+		if err != nil || math.IsNaN(parsed) {
+			continue
+		}
+
+		point := types.MetricPoint{
+			Timestamp: time.Unix(0, int64(timestamp*float64(time.Second))).UTC(),
+			Value:     uint64(math.Max(0, math.Floor(parsed+0.5))),
+		}
+		result.Metrics = append(result.Metrics, point)
+		result.LatestTimestamp = point.Timestamp
+	}
+	return result
+}
+
+// getQuery returns PromQL expression of given metric of series matching given selector summed by
+// given label, or an empty string if the metric is not supported.
+func getQuery(metricName, selector, label string) string {
+	switch metricName {
+	case CpuUsage:
+		return fmt.Sprintf("sum(rate(container_cpu_usage_seconds_total{%s}[%s])) by (%s) * 1000",
+			selector, rateWindow, label)
+	case MemoryUsage:
+		return fmt.Sprintf("sum(container_memory_usage_bytes{%s}) by (%s)", selector, label)
+	default:
+		return ""
+	}
+}
+
+// getPodSelector returns selector of containers of pods with given names in given namespace.
+// Series of the pod sandbox and of the whole pod are skipped, as their usage is included in the
+// usage of the containers or equals to it.
+func getPodSelector(namespace string, names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, regexp.QuoteMeta(name))
+	}
+	return fmt.Sprintf(`namespace=%q,%s=~%q,%s!="",%s!="POD"`, namespace, PodLabel,
+		strings.Join(quoted, "|"), ContainerLabel, ContainerLabel)
+}
+
+// getNodeSelector returns selector of the root cgroup of node with given name, which covers
+// usage of the whole node.
+func getNodeSelector(name string) string {
+	return fmt.Sprintf(`id="/",%s=%q`, NodeLabel, name)
+}
+
+// GetPodMetrics returns given metric of given pods queried from Prometheus.
+func (self *PrometheusClient) GetPodMetrics(namespace string, names []string, metricName string,
+	since time.Time) ([]types.MetricResult, error) {
+	query := getQuery(metricName, getPodSelector(namespace, names), PodLabel)
+	if query == "" {
+		return nil, metricapi.NewMetricNotFoundError(metricName)
+	}
+	series, err := self.queryRange(query, PodLabel, since)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]types.MetricResult, 0, len(names))
+	for _, name := range names {
+		items = append(items, getSeries(series, name))
+	}
+	return items, nil
+}
+
+// GetNodeMetric returns given metric of given node queried from Prometheus.
+func (self *PrometheusClient) GetNodeMetric(name, metricName string, since time.Time) (
+	types.MetricResult, error) {
+	query := getQuery(metricName, getNodeSelector(name), NodeLabel)
+	if query == "" {
+		return types.MetricResult{}, metricapi.NewMetricNotFoundError(metricName)
+	}
+	series, err := self.queryRange(query, NodeLabel, since)
+	if err != nil {
+		return types.MetricResult{}, err
+	}
+	return getSeries(series, name), nil
+}
+
+// getSeries returns time series of given resource, or an empty one if Prometheus has no data.
+func getSeries(series map[string]types.MetricResult, name string) types.MetricResult {
+	if result, ok := series[name]; ok {
+		return result
+	}
+	return types.MetricResult{Metrics: make([]types.MetricPoint, 0)}
+}
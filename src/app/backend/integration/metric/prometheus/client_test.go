@@ -0,0 +1,117 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	types "k8s.io/heapster/metrics/api/v1/types"
+)
+
+func TestPrometheusClient(t *testing.T) {
+	start := time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+	queries := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		queries = append(queries, r.URL.Query().Get("query")+" "+r.URL.Query().Get("start"))
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"matrix","result":[
+			{"metric":{"pod_name":"foo","instance":"node-1"},"values":[[%d,"0.3"],[%d.5,"1024"]]},
+			{"metric":{"pod_name":"bar","instance":"node-2"},"values":[[%d,"NaN"]]}]}}`,
+			start.Unix(), start.Add(time.Minute).Unix(), start.Unix())
+	}))
+	defer server.Close()
+
+	client := &PrometheusClient{host: server.URL, bearerToken: "secret", client: http.DefaultClient,
+		now: func() time.Time { return start.Add(time.Hour) }}
+	foo := types.MetricResult{Metrics: []types.MetricPoint{
+		{Timestamp: start, Value: 0},
+		{Timestamp: start.Add(time.Minute + 500*time.Millisecond), Value: 1024},
+	}, LatestTimestamp: start.Add(time.Minute + 500*time.Millisecond)}
+	empty := types.MetricResult{Metrics: []types.MetricPoint{}}
+
+	cases := []struct {
+		path          string
+		expected      interface{}
+		expectedQuery string
+		expectError   bool
+	}{
+		{
+			"/model/namespaces/default/pod-list/foo,bar,baz/metrics/cpu/usage_rate",
+			types.MetricResultList{Items: []types.MetricResult{foo, empty, empty}},
+			`sum(rate(container_cpu_usage_seconds_total{namespace="default",pod_name=~"foo|bar|baz",` +
+				`container_name!="",container_name!="POD"}[2m])) by (pod_name) * 1000 1493981100`,
+			false,
+		},
+		{
+			"/model/namespaces/default/pods/foo/metrics/memory/usage?start=2017-05-05T10:50:00Z",
+			foo,
+			`sum(container_memory_usage_bytes{namespace="default",pod_name=~"foo",container_name!="",` +
+				`container_name!="POD"}) by (pod_name) 1493981400`,
+			false,
+		},
+		{
+			"/model/nodes/node-1/metrics/memory/usage",
+			foo,
+			`sum(container_memory_usage_bytes{id="/",instance="node-1"}) by (instance) 1493981100`,
+			false,
+		},
+		{"/model/nodes/node-1/metrics/network/rx_rate", nil, "", true},
+		{"/model/namespaces/default/metrics/cpu/usage_rate", nil, "", true},
+	}
+	for _, c := range cases {
+		queries = queries[:0]
+		raw, err := client.Get(c.path).DoRaw()
+		if (err != nil) != c.expectError {
+			t.Errorf("Get(%#v).DoRaw() returns error %v, expected error: %v", c.path, err, c.expectError)
+		}
+		if c.expectError {
+			continue
+		}
+
+		actual := reflect.New(reflect.TypeOf(c.expected))
+		if err := json.Unmarshal(raw, actual.Interface()); err != nil {
+			t.Fatalf("Cannot unmarshal response of %#v: %v", c.path, err)
+		}
+		if !reflect.DeepEqual(actual.Elem().Interface(), c.expected) {
+			t.Errorf("Get(%#v).DoRaw() == \ngot: %#v, \nexpected %#v", c.path,
+				actual.Elem().Interface(), c.expected)
+		}
+		if len(queries) != 1 || queries[0] != c.expectedQuery {
+			t.Errorf("Get(%#v).DoRaw() sent queries %#v, expected %#v", c.path, queries,
+				c.expectedQuery)
+		}
+	}
+
+	client.bearerToken = ""
+	if _, err := client.Get("/model/nodes/node-1/metrics/memory/usage").DoRaw(); err == nil {
+		t.Error("Get().DoRaw() returns no error for unauthorized request")
+	}
+
+	client.bearerToken = "secret"
+	defer func(size int64) { maxResponseSize = size }(maxResponseSize)
+	maxResponseSize = 64
+	if _, err := client.Get("/model/nodes/node-1/metrics/memory/usage").DoRaw(); err == nil {
+		t.Error("Get().DoRaw() returns no error for response exceeding maximum size")
+	}
+}
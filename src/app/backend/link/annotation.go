@@ -0,0 +1,58 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package link
+
+import (
+	"encoding/json"
+	"log"
+	"net/url"
+	"sort"
+)
+
+// LinksAnnotation is an annotation with links of a resource. Its value is a JSON object mapping
+// link names to URLs, e.g. {"runbook": "https://wiki/runbook", "repo": "https://git/app"}.
+const LinksAnnotation = "dashboard.kubernetes.io/links"
+
+// GetAnnotationLinks returns links parsed from the links annotation, sorted by name. Only
+// absolute http(s) URLs are returned, so that annotations can't inject scripts to the frontend.
+func GetAnnotationLinks(annotations map[string]string) []Link {
+	value, ok := annotations[LinksAnnotation]
+	if !ok {
+		return nil
+	}
+
+	urls := map[string]string{}
+	if err := json.Unmarshal([]byte(value), &urls); err != nil {
+		log.Printf("Skipping invalid %s annotation: %s", LinksAnnotation, err)
+		return nil
+	}
+
+	links := make([]Link, 0)
+	for name, rawURL := range urls {
+		parsed, err := url.Parse(rawURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			continue
+		}
+		links = append(links, Link{Name: name, URL: rawURL})
+	}
+	sort.Sort(linksByName(links))
+	return links
+}
+
+type linksByName []Link
+
+func (self linksByName) Len() int           { return len(self) }
+func (self linksByName) Swap(i, j int)      { self[i], self[j] = self[j], self[i] }
+func (self linksByName) Less(i, j int) bool { return self[i].Name < self[j].Name }
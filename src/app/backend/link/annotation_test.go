@@ -0,0 +1,45 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package link
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetAnnotationLinks(t *testing.T) {
+	cases := []struct {
+		annotations map[string]string
+		expected    []Link
+	}{
+		{nil, nil},
+		{map[string]string{LinksAnnotation: "not json"}, nil},
+		{
+			map[string]string{LinksAnnotation: `{"runbook": "https://wiki/runbook", "repo": "http://git/app",
+				"script": "javascript:alert(1)", "relative": "/foo"}`},
+			[]Link{
+				{Name: "repo", URL: "http://git/app"},
+				{Name: "runbook", URL: "https://wiki/runbook"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := GetAnnotationLinks(c.annotations)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetAnnotationLinks(%#v) == \ngot: %#v, \nexpected %#v", c.annotations, actual, c.expected)
+		}
+	}
+}
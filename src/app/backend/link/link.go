@@ -0,0 +1,64 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package link
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/settings"
+)
+
+// Link is a link to an external tool, e.g. Grafana dashboard of a pod.
+type Link struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// GetLinks returns links generated from given templates for the resource of given kind, namespace
+// and name. Variables are URL encoded, so they can be used both in paths and in query parameters.
+func GetLinks(templates []settings.LinkTemplate, kind api.ResourceKind, namespace,
+	name string) []Link {
+
+	replacer := strings.NewReplacer(
+		"{namespace}", url.QueryEscape(namespace),
+		"{name}", url.QueryEscape(name),
+		"{kind}", url.QueryEscape(string(kind)),
+	)
+
+	links := make([]Link, 0)
+	for _, template := range templates {
+		if !appliesTo(template, kind) {
+			continue
+		}
+		links = append(links, Link{Name: template.Name, URL: replacer.Replace(template.URL)})
+	}
+	return links
+}
+
+// appliesTo returns true if the template should be used for resources of given kind.
+func appliesTo(template settings.LinkTemplate, kind api.ResourceKind) bool {
+	if len(template.Kinds) == 0 {
+		return true
+	}
+
+	for _, k := range template.Kinds {
+		if api.ResourceKind(strings.ToLower(k)) == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package link
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/settings"
+)
+
+func TestGetLinks(t *testing.T) {
+	templates := []settings.LinkTemplate{
+		{Name: "Grafana", Kinds: []string{"Pod", "deployment"},
+			URL: "http://grafana/d/k8s?var-namespace={namespace}&var-{kind}={name}"},
+		{Name: "Kibana", URL: "http://kibana/app/discover#/?query={name}"},
+	}
+
+	cases := []struct {
+		kind            api.ResourceKind
+		namespace, name string
+		expected        []Link
+	}{
+		{
+			api.ResourceKindPod, "default", "foo-1",
+			[]Link{
+				{Name: "Grafana", URL: "http://grafana/d/k8s?var-namespace=default&var-pod=foo-1"},
+				{Name: "Kibana", URL: "http://kibana/app/discover#/?query=foo-1"},
+			},
+		},
+		{
+			api.ResourceKindNode, "", "node 1",
+			[]Link{{Name: "Kibana", URL: "http://kibana/app/discover#/?query=node+1"}},
+		},
+	}
+
+	for _, c := range cases {
+		actual := GetLinks(templates, c.kind, c.namespace, c.name)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetLinks(%#v, %#v, %#v) == \ngot: %#v, \nexpected %#v",
+				c.kind, c.namespace, c.name, actual, c.expected)
+		}
+	}
+}
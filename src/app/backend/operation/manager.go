@@ -0,0 +1,286 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operation
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Status of an operation.
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// Retention of finished operations. Running operations are always kept.
+const (
+	// How long finished operations are kept for.
+	DefaultRetention = time.Hour
+	// Maximum number of kept finished operations.
+	maxFinishedOperations = 1000
+	// Maximum number of log lines kept per operation.
+	maxLogLines = 1000
+)
+
+// Operation is a long-running action, e.g. waiting for a deployment to be ready, whose progress
+// is polled instead of keeping the HTTP request open. Operations are used by node drain, bulk
+// delete and waiting for deployments to be ready. Dashboard doesn't install Helm charts, so there
+// are no Helm operations.
+type Operation struct {
+	// Unguessable identifier of the operation.
+	ID string `json:"id"`
+
+	// Type of the action: drain, bulkDelete or waitReady.
+	Type string `json:"type"`
+
+	// Name of the user who started the operation. Empty if the user is not known.
+	User string `json:"user"`
+
+	Status Status `json:"status"`
+
+	// Progress of the operation in percent.
+	Progress int `json:"progress"`
+
+	// Log lines of the operation, oldest first.
+	Logs []string `json:"logs"`
+
+	// Error of the failed operation.
+	Error string `json:"error,omitempty"`
+
+	// Result of the finished operation, e.g. readiness of a deployment.
+	Result interface{} `json:"result,omitempty"`
+
+	Started  metaV1.Time  `json:"started"`
+	Finished *metaV1.Time `json:"finished,omitempty"`
+}
+
+// Tracker is used by actions to report their progress and to find out they were cancelled.
+type Tracker interface {
+	// Logf adds line to the log of the operation.
+	Logf(format string, args ...interface{})
+
+	// SetProgress sets progress of the operation in percent.
+	SetProgress(percent int)
+
+	// Cancelled returns channel that is closed when the operation is cancelled.
+	Cancelled() <-chan struct{}
+}
+
+// Func is an action run by an operation. Returned result or error finish the operation.
+type Func func(tracker Tracker) (interface{}, error)
+
+// Manager runs actions as operations in background and keeps their state for polling.
+type Manager interface {
+	// Start starts given action of given type on behalf of given user and returns the operation.
+	Start(operationType, user string, action Func) (*Operation, error)
+
+	// Get returns copy of the operation with given ID.
+	Get(id string) (*Operation, bool)
+
+	// Cancel cancels the operation with given ID and returns its copy. Actions finish when they
+	// notice the cancellation, finished operations are not changed.
+	Cancel(id string) (*Operation, bool)
+}
+
+// operation is an operation with its cancellation channel.
+type operation struct {
+	Operation
+	cancel    chan struct{}
+	manager   *manager
+	cancelled bool
+}
+
+// manager is an in-memory implementation of Manager.
+type manager struct {
+	// How long finished operations are kept for.
+	retention time.Duration
+
+	lock       sync.RWMutex
+	operations map[string]*operation
+}
+
+// NewManager creates manager that keeps finished operations for given time.
+func NewManager(retention time.Duration) Manager {
+	return &manager{retention: retention, operations: make(map[string]*operation)}
+}
+
+// Start runs given action in background.
+func (self *manager) Start(operationType, user string, action Func) (*Operation, error) {
+	id, err := generateID()
+	if err != nil {
+		return nil, err
+	}
+
+	op := &operation{
+		Operation: Operation{ID: id, Type: operationType, User: user, Status: StatusRunning,
+			Logs: make([]string, 0), Started: metaV1.Now()},
+		cancel:  make(chan struct{}),
+		manager: self,
+	}
+
+	self.lock.Lock()
+	self.prune(time.Now())
+	self.operations[id] = op
+	result := op.copy()
+	self.lock.Unlock()
+
+	log.Printf("Starting %s operation %s", operationType, id)
+	go op.run(action)
+	return result, nil
+}
+
+// Get returns copy of the operation.
+func (self *manager) Get(id string) (*Operation, bool) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	op, ok := self.operations[id]
+	if !ok {
+		return nil, false
+	}
+	return op.copy(), true
+}
+
+// Cancel closes cancellation channel of the running operation.
+func (self *manager) Cancel(id string) (*Operation, bool) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	op, ok := self.operations[id]
+	if !ok {
+		return nil, false
+	}
+	if op.Status == StatusRunning && !op.cancelled {
+		log.Printf("Cancelling %s operation %s", op.Type, id)
+		op.cancelled = true
+		close(op.cancel)
+	}
+	return op.copy(), true
+}
+
+// prune drops operations that finished before the retention and the oldest finished operations
+// above the limit. Must be called with the lock held.
+func (self *manager) prune(now time.Time) {
+	finished := make([]*operation, 0)
+	for id, op := range self.operations {
+		if op.Finished == nil {
+			continue
+		}
+		if op.Finished.Time.Before(now.Add(-self.retention)) {
+			delete(self.operations, id)
+			continue
+		}
+		finished = append(finished, op)
+	}
+
+	for len(finished) >= maxFinishedOperations {
+		oldest := 0
+		for i := range finished {
+			if finished[i].Finished.Time.Before(finished[oldest].Finished.Time) {
+				oldest = i
+			}
+		}
+		delete(self.operations, finished[oldest].ID)
+		finished = append(finished[:oldest], finished[oldest+1:]...)
+	}
+}
+
+// run runs given action and records its outcome.
+func (self *operation) run(action Func) {
+	result, err := runAction(action, self)
+
+	self.manager.lock.Lock()
+	defer self.manager.lock.Unlock()
+
+	finished := metaV1.Now()
+	self.Finished = &finished
+	self.Result = result
+	switch {
+	case self.cancelled:
+		self.Status = StatusCancelled
+	case err != nil:
+		self.Status = StatusFailed
+		self.Error = err.Error()
+	default:
+		self.Status = StatusSucceeded
+		self.Progress = 100
+	}
+	log.Printf("Operation %s %s", self.ID, self.Status)
+}
+
+// runAction runs given action, so that panics fail the operation instead of the dashboard.
+func runAction(action Func, tracker Tracker) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Operation panicked: %v", r)
+		}
+	}()
+	return action(tracker)
+}
+
+// Logf adds line to the log of the operation.
+func (self *operation) Logf(format string, args ...interface{}) {
+	self.manager.lock.Lock()
+	defer self.manager.lock.Unlock()
+
+	self.Logs = append(self.Logs, fmt.Sprintf(format, args...))
+	if len(self.Logs) > maxLogLines {
+		self.Logs = self.Logs[len(self.Logs)-maxLogLines:]
+	}
+}
+
+// SetProgress sets progress of the operation, bounded to 0-100 percent.
+func (self *operation) SetProgress(percent int) {
+	self.manager.lock.Lock()
+	defer self.manager.lock.Unlock()
+
+	if percent < 0 {
+		percent = 0
+	} else if percent > 100 {
+		percent = 100
+	}
+	self.Progress = percent
+}
+
+// Cancelled returns cancellation channel of the operation.
+func (self *operation) Cancelled() <-chan struct{} {
+	return self.cancel
+}
+
+// copy returns copy of the operation state. Must be called with the lock held.
+func (self *operation) copy() *Operation {
+	result := self.Operation
+	result.Logs = append(make([]string, 0, len(self.Logs)), self.Logs...)
+	return &result
+}
+
+func generateID() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operation
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// waitFinished polls given operation until it finishes.
+func waitFinished(t *testing.T, manager Manager, id string) *Operation {
+	for i := 0; i < 1000; i++ {
+		if op, ok := manager.Get(id); ok && op.Status != StatusRunning {
+			return op
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Operation %s did not finish", id)
+	return nil
+}
+
+func TestManager(t *testing.T) {
+	cases := []struct {
+		action   Func
+		cancel   bool
+		status   Status
+		progress int
+		logs     []string
+		err      string
+		result   interface{}
+	}{
+		{
+			func(tracker Tracker) (interface{}, error) {
+				tracker.Logf("Step %d", 1)
+				tracker.SetProgress(50)
+				return "done", nil
+			},
+			false, StatusSucceeded, 100, []string{"Step 1"}, "", "done",
+		},
+		{
+			func(tracker Tracker) (interface{}, error) {
+				tracker.SetProgress(120)
+				return nil, errors.New("timeout")
+			},
+			false, StatusFailed, 100, []string{}, "timeout", nil,
+		},
+		{
+			func(tracker Tracker) (interface{}, error) {
+				<-tracker.Cancelled()
+				return nil, errors.New("cancelled")
+			},
+			true, StatusCancelled, 0, []string{}, "", nil,
+		},
+		{
+			func(tracker Tracker) (interface{}, error) {
+				panic("boom")
+			},
+			false, StatusFailed, 0, []string{}, "Operation panicked: boom", nil,
+		},
+	}
+	for _, c := range cases {
+		manager := NewManager(DefaultRetention)
+		started, err := manager.Start("test", "jane", c.action)
+		if err != nil {
+			t.Fatalf("Start() returns error %v", err)
+		}
+		if started.Status != StatusRunning || started.User != "jane" || len(started.ID) != 32 {
+			t.Errorf("Start() returns %#v, expected running operation of jane", started)
+		}
+		if c.cancel {
+			manager.Cancel(started.ID)
+		}
+
+		actual := waitFinished(t, manager, started.ID)
+		if actual.Status != c.status || actual.Progress != c.progress || actual.Error != c.err ||
+			!reflect.DeepEqual(actual.Logs, c.logs) || !reflect.DeepEqual(actual.Result, c.result) ||
+			actual.Finished == nil {
+			t.Errorf("Operation == \ngot: %#v, \nexpected status %s, progress %d, logs %#v, "+
+				"error %#v, result %#v", actual, c.status, c.progress, c.logs, c.err, c.result)
+		}
+	}
+
+	if _, ok := NewManager(DefaultRetention).Get("missing"); ok {
+		t.Error("Get(missing) returns operation, expected none")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	now := time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+	newOperation := func(id string, finished time.Duration) *operation {
+		op := &operation{Operation: Operation{ID: id}}
+		if finished != 0 {
+			time := metaV1.NewTime(now.Add(-finished))
+			op.Finished = &time
+		}
+		return op
+	}
+	manager := &manager{retention: time.Hour, operations: map[string]*operation{
+		"running": newOperation("running", 0),
+		"recent":  newOperation("recent", time.Minute),
+		"old":     newOperation("old", 2*time.Hour),
+	}}
+
+	manager.prune(now)
+	if _, ok := manager.operations["old"]; ok || len(manager.operations) != 2 {
+		t.Errorf("prune() keeps %#v, expected running and recent operations", manager.operations)
+	}
+}
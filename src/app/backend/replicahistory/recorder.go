@@ -0,0 +1,139 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replicahistory
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+// Maximum number of points kept per deployment.
+const maxPoints = 1000
+
+// ReplicasPoint is desired and ready replica count of a deployment at a point in time.
+type ReplicasPoint struct {
+	Timestamp metaV1.Time `json:"timestamp"`
+	Desired   int32       `json:"desired"`
+	Ready     int32       `json:"ready"`
+}
+
+// ReplicasHistory is a history of replica counts of a deployment, oldest first. A point is added
+// only when counts change, so counts stay the same until the next point.
+type ReplicasHistory struct {
+	Points []ReplicasPoint `json:"points"`
+}
+
+// Recorder records replica counts of deployments over time. Kubernetes does not keep history of
+// replica counts, so the recorder samples them periodically and keeps the history in memory.
+type Recorder interface {
+	// Start starts recording replica counts of all deployments every interval, using given client.
+	Start(client kubernetes.Interface, interval time.Duration)
+
+	// GetHistory returns recorded history of given deployment.
+	GetHistory(namespace, name string) []ReplicasPoint
+}
+
+// recorder is an in-memory implementation of Recorder.
+type recorder struct {
+	// How long points are kept for.
+	window time.Duration
+
+	lock sync.RWMutex
+	// History keyed by namespace/name of deployment.
+	points map[string][]ReplicasPoint
+}
+
+// NewRecorder creates recorder that keeps history of given length.
+func NewRecorder(window time.Duration) Recorder {
+	return &recorder{window: window, points: make(map[string][]ReplicasPoint)}
+}
+
+// Start starts recording in background.
+func (self *recorder) Start(client kubernetes.Interface, interval time.Duration) {
+	log.Printf("Recording replica counts of deployments every %s", interval)
+	go func() {
+		for {
+			self.sample(client)
+			time.Sleep(interval)
+		}
+	}()
+}
+
+func (self *recorder) sample(client kubernetes.Interface) {
+	channels := &common.ResourceChannels{
+		DeploymentList: common.GetDeploymentListChannel(client, common.NewNamespaceQuery(nil), 1),
+	}
+
+	deployments := <-channels.DeploymentList.List
+	if err := <-channels.DeploymentList.Error; err != nil {
+		log.Printf("Couldn't record replica counts of deployments: %s", err)
+		return
+	}
+
+	self.record(deployments.Items, time.Now())
+}
+
+// record adds points of deployments whose replica counts changed and drops points older than
+// the window. History of deployments that don't exist anymore is dropped.
+func (self *recorder) record(deployments []extensions.Deployment, now time.Time) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	points := make(map[string][]ReplicasPoint)
+	oldest := now.Add(-self.window)
+	for _, deployment := range deployments {
+		key := deployment.Namespace + "/" + deployment.Name
+		history := self.points[key]
+
+		point := ReplicasPoint{
+			Timestamp: metaV1.NewTime(now),
+			Ready:     deployment.Status.ReadyReplicas,
+		}
+		if deployment.Spec.Replicas != nil {
+			point.Desired = *deployment.Spec.Replicas
+		}
+
+		last := len(history) - 1
+		if last < 0 || history[last].Desired != point.Desired || history[last].Ready != point.Ready {
+			history = append(history, point)
+		}
+
+		// Keep the newest point older than the window, it holds counts at the window start.
+		start := 0
+		for start < len(history)-1 && history[start+1].Timestamp.Time.Before(oldest) {
+			start++
+		}
+		if len(history)-start > maxPoints {
+			start = len(history) - maxPoints
+		}
+		points[key] = history[start:]
+	}
+	self.points = points
+}
+
+// GetHistory returns a copy of recorded points.
+func (self *recorder) GetHistory(namespace, name string) []ReplicasPoint {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	history := self.points[namespace+"/"+name]
+	return append(make([]ReplicasPoint, 0, len(history)), history...)
+}
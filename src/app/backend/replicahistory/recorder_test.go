@@ -0,0 +1,70 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replicahistory
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+func deployment(name string, desired, ready int32) extensions.Deployment {
+	return extensions.Deployment{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       extensions.DeploymentSpec{Replicas: &desired},
+		Status:     extensions.DeploymentStatus{ReadyReplicas: ready},
+	}
+}
+
+func point(t time.Time, desired, ready int32) ReplicasPoint {
+	return ReplicasPoint{Timestamp: metaV1.NewTime(t), Desired: desired, Ready: ready}
+}
+
+func TestRecord(t *testing.T) {
+	start := time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+	r := &recorder{window: time.Hour, points: make(map[string][]ReplicasPoint)}
+
+	r.record([]extensions.Deployment{deployment("app", 1, 1), deployment("gone", 1, 0)}, start)
+	// Unchanged counts add no point.
+	r.record([]extensions.Deployment{deployment("app", 1, 1)}, start.Add(time.Minute))
+	r.record([]extensions.Deployment{deployment("app", 3, 1)}, start.Add(30*time.Minute))
+	r.record([]extensions.Deployment{deployment("app", 3, 3)}, start.Add(70*time.Minute))
+	r.record([]extensions.Deployment{deployment("app", 3, 2)}, start.Add(100*time.Minute))
+
+	cases := []struct {
+		name     string
+		expected []ReplicasPoint
+	}{
+		{
+			"app",
+			// The first point is out of the window, but holds counts at the window start.
+			[]ReplicasPoint{
+				point(start.Add(30*time.Minute), 3, 1),
+				point(start.Add(70*time.Minute), 3, 3),
+				point(start.Add(100*time.Minute), 3, 2),
+			},
+		},
+		{"gone", []ReplicasPoint{}},
+	}
+	for _, c := range cases {
+		actual := r.GetHistory("default", c.name)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetHistory(%#v) == \ngot: %#v, \nexpected %#v", c.name, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"log"
+	"sort"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+)
+
+// SlowWebhookSeconds is the average latency of webhook calls above which a webhook is considered
+// slow. Webhooks time out after 10 seconds by default (30 seconds in older versions).
+const SlowWebhookSeconds = 1.0
+
+// AdmissionReport contains admission webhooks that fail or are slow, since those commonly break
+// creation of pods by controllers without the workload reporting any error.
+type AdmissionReport struct {
+	// Webhooks with failures or metrics, most failing first.
+	Webhooks []WebhookStats `json:"webhooks"`
+
+	// True when API server metrics could be read, latencies are known only then.
+	MetricsAvailable bool `json:"metricsAvailable"`
+
+	// Error of reading API server metrics, e.g. when the user is not allowed to read them.
+	MetricsError string `json:"metricsError,omitempty"`
+}
+
+// WebhookStats contains failures of a webhook found in recent events and its call statistics from
+// API server metrics.
+type WebhookStats struct {
+	// Name of the webhook as configured in webhook configuration, e.g. validate.kyverno.svc.
+	Name string `json:"name"`
+
+	// Type of the webhook, validating or admit (i.e. mutating), known from metrics only.
+	Type string `json:"type,omitempty"`
+
+	// Numbers of failures found in events by kind.
+	Timeouts   int `json:"timeouts"`
+	CallErrors int `json:"callErrors"`
+	Denials    int `json:"denials"`
+
+	// Failures found in events by namespace, most failing first.
+	Namespaces []NamespaceFailures `json:"namespaces"`
+
+	LastFailureTime    *metaV1.Time `json:"lastFailureTime"`
+	LastFailureMessage string       `json:"lastFailureMessage,omitempty"`
+
+	// Number of calls and summed latency of calls since API server start.
+	Calls               int64   `json:"calls"`
+	TotalLatencySeconds float64 `json:"totalLatencySeconds"`
+
+	// Average latency of calls, 0 when no calls are known.
+	AverageLatencySeconds float64 `json:"averageLatencySeconds"`
+
+	// Number of requests rejected by the webhook or by failing to call it, since API server start.
+	Rejections int64 `json:"rejections"`
+
+	// True when average latency is above SlowWebhookSeconds.
+	Slow bool `json:"slow"`
+}
+
+// Failures returns number of failures of the webhook found in events.
+func (self WebhookStats) Failures() int {
+	return self.Timeouts + self.CallErrors + self.Denials
+}
+
+// GetAdmissionReport returns failing and slow admission webhooks from recent events in given
+// namespaces and from API server metrics, when those can be read.
+func GetAdmissionReport(client client.Interface, nsQuery *common.NamespaceQuery) (*AdmissionReport, error) {
+	log.Print("Getting admission webhook report")
+
+	channels := &common.ResourceChannels{
+		EventList: common.GetEventListChannel(client, nsQuery, 1),
+	}
+
+	events := <-channels.EventList.List
+	if err := <-channels.EventList.Error; err != nil {
+		return nil, err
+	}
+
+	webhooks := make(map[string]*WebhookStats)
+	addEventFailures(webhooks, events.Items)
+
+	report := &AdmissionReport{}
+	families, err := getMetrics(client)
+	if err != nil {
+		report.MetricsError = err.Error()
+	} else {
+		report.MetricsAvailable = true
+		addMetrics(webhooks, families)
+	}
+
+	report.Webhooks = toWebhookList(webhooks)
+	return report, nil
+}
+
+func toWebhookList(webhooks map[string]*WebhookStats) []WebhookStats {
+	result := make([]WebhookStats, 0, len(webhooks))
+	for _, stats := range webhooks {
+		if stats.Calls > 0 {
+			stats.AverageLatencySeconds = stats.TotalLatencySeconds / float64(stats.Calls)
+		}
+		stats.Slow = stats.AverageLatencySeconds > SlowWebhookSeconds
+		result = append(result, *stats)
+	}
+	sort.Sort(webhooksByFailures(result))
+	return result
+}
+
+type webhooksByFailures []WebhookStats
+
+func (self webhooksByFailures) Len() int      { return len(self) }
+func (self webhooksByFailures) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+func (self webhooksByFailures) Less(i, j int) bool {
+	if self[i].Failures() != self[j].Failures() {
+		return self[i].Failures() > self[j].Failures()
+	}
+	if self[i].Rejections != self[j].Rejections {
+		return self[i].Rejections > self[j].Rejections
+	}
+	return self[i].Name < self[j].Name
+}
@@ -0,0 +1,120 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+func TestParseWebhookFailure(t *testing.T) {
+	cases := []struct {
+		message  string
+		expected *webhookFailure
+	}{
+		{"Created pod: nginx-1", nil},
+		{
+			`Error creating: Internal error occurred: failed calling webhook "validate.kyverno.svc": ` +
+				`Post https://kyverno-svc.kyverno.svc:443/validate: context deadline exceeded`,
+			&webhookFailure{webhook: "validate.kyverno.svc", kind: FailureKindTimeout},
+		},
+		{
+			`Error creating: Internal error occurred: failed calling admission webhook "mpod.kb.io": ` +
+				`Post https://webhook.system.svc:443/mutate: no endpoints available for service "webhook"`,
+			&webhookFailure{webhook: "mpod.kb.io", kind: FailureKindCallError},
+		},
+		{
+			`Error creating: admission webhook "vpod.kb.io" denied the request: image is not signed`,
+			&webhookFailure{webhook: "vpod.kb.io", kind: FailureKindDenied},
+		},
+	}
+	for _, c := range cases {
+		actual := parseWebhookFailure(c.message)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("parseWebhookFailure(%#v) == \ngot %#v, \nexpected %#v", c.message, actual, c.expected)
+		}
+	}
+}
+
+func TestAddEventFailures(t *testing.T) {
+	first := metaV1.NewTime(time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC))
+	last := metaV1.NewTime(time.Date(2017, 5, 5, 11, 0, 0, 0, time.UTC))
+	denied := `Error creating: admission webhook "vpod.kb.io" denied the request: image is not signed`
+	events := []v1.Event{
+		{ObjectMeta: metaV1.ObjectMeta{Namespace: "a"}, Reason: "FailedCreate", Count: 3,
+			LastTimestamp: first, Message: denied},
+		{ObjectMeta: metaV1.ObjectMeta{Namespace: "b"}, Reason: "FailedCreate", LastTimestamp: last,
+			Message: `failed calling webhook "vpod.kb.io": context deadline exceeded`},
+		{ObjectMeta: metaV1.ObjectMeta{Namespace: "b"}, Reason: "SuccessfulCreate", Count: 5,
+			LastTimestamp: last, Message: denied},
+	}
+	expected := map[string]*WebhookStats{
+		"vpod.kb.io": {
+			Name:     "vpod.kb.io",
+			Timeouts: 1,
+			Denials:  3,
+			Namespaces: []NamespaceFailures{
+				{Namespace: "a", Failures: 3},
+				{Namespace: "b", Failures: 1},
+			},
+			LastFailureTime:    &last,
+			LastFailureMessage: `failed calling webhook "vpod.kb.io": context deadline exceeded`,
+		},
+	}
+
+	actual := make(map[string]*WebhookStats)
+	addEventFailures(actual, events)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("addEventFailures(%#v) == \ngot %#v, \nexpected %#v", events, actual["vpod.kb.io"],
+			expected["vpod.kb.io"])
+	}
+}
+
+func TestAddMetrics(t *testing.T) {
+	metrics := `# TYPE apiserver_admission_webhook_admission_duration_seconds histogram
+apiserver_admission_webhook_admission_duration_seconds_bucket{name="vpod.kb.io",operation="CREATE",rejected="false",type="validating",le="+Inf"} 3
+apiserver_admission_webhook_admission_duration_seconds_sum{name="vpod.kb.io",operation="CREATE",rejected="false",type="validating"} 4.5
+apiserver_admission_webhook_admission_duration_seconds_count{name="vpod.kb.io",operation="CREATE",rejected="false",type="validating"} 3
+apiserver_admission_webhook_admission_duration_seconds_bucket{name="vpod.kb.io",operation="UPDATE",rejected="true",type="validating",le="+Inf"} 1
+apiserver_admission_webhook_admission_duration_seconds_sum{name="vpod.kb.io",operation="UPDATE",rejected="true",type="validating"} 1.5
+apiserver_admission_webhook_admission_duration_seconds_count{name="vpod.kb.io",operation="UPDATE",rejected="true",type="validating"} 1
+# TYPE apiserver_admission_webhook_rejection_count counter
+apiserver_admission_webhook_rejection_count{error_type="no_error",name="vpod.kb.io",operation="UPDATE",rejection_code="403",type="validating"} 1
+apiserver_admission_webhook_rejection_count{error_type="calling_webhook_error",name="mpod.kb.io",operation="CREATE",rejection_code="500",type="admit"} 2
+`
+	parser := expfmt.TextParser{}
+	families, err := parser.TextToMetricFamilies(strings.NewReader(metrics))
+	if err != nil {
+		t.Fatalf("TextToMetricFamilies(%#v) == \ngot err %#v", metrics, err)
+	}
+
+	webhooks := make(map[string]*WebhookStats)
+	addMetrics(webhooks, families)
+	actual := toWebhookList(webhooks)
+	expected := []WebhookStats{
+		{Name: "mpod.kb.io", Namespaces: []NamespaceFailures{}, Rejections: 2},
+		{Name: "vpod.kb.io", Type: "validating", Namespaces: []NamespaceFailures{}, Calls: 4,
+			TotalLatencySeconds: 6, AverageLatencySeconds: 1.5, Rejections: 1, Slow: true},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("addMetrics(%#v) == \ngot %#v, \nexpected %#v", metrics, actual, expected)
+	}
+}
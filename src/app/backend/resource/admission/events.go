@@ -0,0 +1,154 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+// Reasons of events reported by controllers and API server when admission rejects creation of an
+// object.
+var failureReasons = map[string]bool{
+	"FailedCreate":  true,
+	"InternalError": true,
+}
+
+// Kinds of webhook failures found in event messages.
+const (
+	// FailureKindTimeout is a call to webhook that didn't finish within its timeout.
+	FailureKindTimeout = "Timeout"
+
+	// FailureKindCallError is a call to webhook that failed, e.g. as its service has no endpoints.
+	FailureKindCallError = "CallError"
+
+	// FailureKindDenied is a request denied by webhook.
+	FailureKindDenied = "Denied"
+)
+
+var (
+	// callErrorRegexp matches messages of failed webhook calls, e.g.
+	// `Internal error occurred: failed calling webhook "validate.kyverno.svc": Post ...`.
+	callErrorRegexp = regexp.MustCompile(`failed calling (?:admission )?webhook "([^"]+)"`)
+
+	// deniedRegexp matches messages of requests denied by webhook, e.g.
+	// `admission webhook "vpod.kb.io" denied the request: ...`.
+	deniedRegexp = regexp.MustCompile(`admission webhook "([^"]+)" denied the request`)
+)
+
+// NamespaceFailures is number of webhook failures seen in a namespace.
+type NamespaceFailures struct {
+	Namespace string `json:"namespace"`
+	Failures  int    `json:"failures"`
+}
+
+// webhookFailure is a failure of a webhook found in message of an event.
+type webhookFailure struct {
+	webhook string
+	kind    string
+}
+
+// parseWebhookFailure returns webhook failure described by event message, or nil when message is
+// not about a webhook.
+func parseWebhookFailure(message string) *webhookFailure {
+	if match := deniedRegexp.FindStringSubmatch(message); match != nil {
+		return &webhookFailure{webhook: match[1], kind: FailureKindDenied}
+	}
+
+	match := callErrorRegexp.FindStringSubmatch(message)
+	if match == nil {
+		return nil
+	}
+	failure := &webhookFailure{webhook: match[1], kind: FailureKindCallError}
+	if strings.Contains(message, "deadline exceeded") || strings.Contains(message, "timeout") ||
+		strings.Contains(message, "Timeout") {
+		failure.kind = FailureKindTimeout
+	}
+	return failure
+}
+
+// addEventFailures counts webhook failures found in given events into webhook stats by name.
+func addEventFailures(webhooks map[string]*WebhookStats, events []v1.Event) {
+	namespaces := make(map[string]map[string]int)
+
+	for _, event := range events {
+		if !failureReasons[event.Reason] {
+			continue
+		}
+		failure := parseWebhookFailure(event.Message)
+		if failure == nil {
+			continue
+		}
+
+		// Controllers emit the event again with increased count instead of a new one.
+		count := int(event.Count)
+		if count < 1 {
+			count = 1
+		}
+
+		stats := getWebhookStats(webhooks, failure.webhook)
+		switch failure.kind {
+		case FailureKindTimeout:
+			stats.Timeouts += count
+		case FailureKindCallError:
+			stats.CallErrors += count
+		case FailureKindDenied:
+			stats.Denials += count
+		}
+
+		if stats.LastFailureTime == nil || stats.LastFailureTime.Before(event.LastTimestamp) {
+			lastTimestamp := event.LastTimestamp
+			stats.LastFailureTime = &lastTimestamp
+			stats.LastFailureMessage = event.Message
+		}
+
+		if namespaces[failure.webhook] == nil {
+			namespaces[failure.webhook] = make(map[string]int)
+		}
+		namespaces[failure.webhook][event.Namespace] += count
+	}
+
+	for webhook, counts := range namespaces {
+		stats := webhooks[webhook]
+		for namespace, failures := range counts {
+			stats.Namespaces = append(stats.Namespaces, NamespaceFailures{Namespace: namespace,
+				Failures: failures})
+		}
+		sort.Sort(namespaceFailuresByCount(stats.Namespaces))
+	}
+}
+
+func getWebhookStats(webhooks map[string]*WebhookStats, name string) *WebhookStats {
+	stats, ok := webhooks[name]
+	if !ok {
+		stats = &WebhookStats{Name: name, Namespaces: make([]NamespaceFailures, 0)}
+		webhooks[name] = stats
+	}
+	return stats
+}
+
+type namespaceFailuresByCount []NamespaceFailures
+
+func (self namespaceFailuresByCount) Len() int      { return len(self) }
+func (self namespaceFailuresByCount) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+func (self namespaceFailuresByCount) Less(i, j int) bool {
+	if self[i].Failures != self[j].Failures {
+		return self[i].Failures > self[j].Failures
+	}
+	return self[i].Namespace < self[j].Namespace
+}
@@ -0,0 +1,83 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"bytes"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	client "k8s.io/client-go/kubernetes"
+)
+
+// Metrics of admission webhook calls exposed by API server.
+const (
+	admissionDurationMetric = "apiserver_admission_webhook_admission_duration_seconds"
+	rejectionCountMetric    = "apiserver_admission_webhook_rejection_count"
+)
+
+// Labels of admission webhook metrics.
+const (
+	webhookNameLabel = "name"
+	webhookTypeLabel = "type"
+)
+
+// getMetrics returns API server metrics in the Prometheus text format.
+func getMetrics(client client.Interface) (map[string]*dto.MetricFamily, error) {
+	raw, err := client.CoreV1().RESTClient().Get().AbsPath("/metrics").DoRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	parser := expfmt.TextParser{}
+	return parser.TextToMetricFamilies(bytes.NewReader(raw))
+}
+
+// addMetrics adds latencies and rejections of webhook calls from API server metrics into webhook
+// stats by name. Metrics are cumulative since API server start and summed over all operations.
+func addMetrics(webhooks map[string]*WebhookStats, families map[string]*dto.MetricFamily) {
+	if family, ok := families[admissionDurationMetric]; ok {
+		for _, metric := range family.GetMetric() {
+			histogram := metric.GetHistogram()
+			if histogram == nil {
+				continue
+			}
+			stats := getWebhookStats(webhooks, getLabel(metric, webhookNameLabel))
+			stats.Type = getLabel(metric, webhookTypeLabel)
+			stats.Calls += int64(histogram.GetSampleCount())
+			stats.TotalLatencySeconds += histogram.GetSampleSum()
+		}
+	}
+
+	if family, ok := families[rejectionCountMetric]; ok {
+		for _, metric := range family.GetMetric() {
+			counter := metric.GetCounter()
+			if counter == nil {
+				continue
+			}
+			stats := getWebhookStats(webhooks, getLabel(metric, webhookNameLabel))
+			stats.Rejections += int64(counter.GetValue())
+		}
+	}
+}
+
+func getLabel(metric *dto.Metric, name string) string {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,175 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package availability
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	client "k8s.io/client-go/kubernetes"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+	policy "k8s.io/client-go/pkg/apis/policy/v1beta1"
+)
+
+// MaxScore is the score of deployments without findings.
+const MaxScore = 100
+
+// AvailabilityReport lists high availability anti-patterns of deployments.
+type AvailabilityReport struct {
+	// Average score of the deployments, MaxScore when there are no deployments.
+	Score int `json:"score"`
+
+	// Deployments with their findings, lowest score first.
+	Deployments []DeploymentAvailability `json:"deployments"`
+}
+
+// DeploymentAvailability is a high availability score of a deployment with findings lowering it.
+type DeploymentAvailability struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	// MaxScore minus weights of the findings.
+	Score int `json:"score"`
+
+	Findings []Finding `json:"findings"`
+}
+
+// rawDeploymentList is a deployment list with fields not known to the client, which are needed by
+// the rules.
+type rawDeploymentList struct {
+	Items []struct {
+		Spec struct {
+			Template struct {
+				Spec struct {
+					TopologySpreadConstraints []json.RawMessage `json:"topologySpreadConstraints"`
+				} `json:"spec"`
+			} `json:"template"`
+		} `json:"spec"`
+	} `json:"items"`
+}
+
+// GetAvailabilityReport checks deployments in given namespaces for high availability
+// anti-patterns and returns them scored.
+func GetAvailabilityReport(client client.Interface, nsQuery *common.NamespaceQuery) (
+	*AvailabilityReport, error) {
+	log.Print("Getting high availability report of deployments")
+
+	channels := &common.ResourceChannels{
+		PodDisruptionBudgetList: common.GetPodDisruptionBudgetListChannel(client, nsQuery, 1),
+	}
+
+	// Topology spread constraints are not known to the client, so deployments are read raw.
+	raw, err := common.JSONRequest(client.ExtensionsV1beta1().RESTClient().Get().
+		Namespace(nsQuery.ToRequestParam()).
+		Resource("deployments")).
+		DoRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	deployments := &extensions.DeploymentList{}
+	if err := json.Unmarshal(raw, deployments); err != nil {
+		return nil, err
+	}
+	rawDeployments := &rawDeploymentList{}
+	if err := json.Unmarshal(raw, rawDeployments); err != nil {
+		return nil, err
+	}
+
+	pdbs := <-channels.PodDisruptionBudgetList.List
+	if err := <-channels.PodDisruptionBudgetList.Error; err != nil {
+		return nil, err
+	}
+
+	targets := make([]target, 0)
+	for i, deployment := range deployments.Items {
+		if !nsQuery.Matches(deployment.Namespace) {
+			continue
+		}
+		targets = append(targets, target{
+			deployment:       deployment,
+			topologySpread:   len(rawDeployments.Items[i].Spec.Template.Spec.TopologySpreadConstraints) > 0,
+			disruptionBudget: hasDisruptionBudget(deployment, pdbs.Items),
+		})
+	}
+	return toAvailabilityReport(targets), nil
+}
+
+func toAvailabilityReport(targets []target) *AvailabilityReport {
+	report := &AvailabilityReport{
+		Score:       MaxScore,
+		Deployments: make([]DeploymentAvailability, 0),
+	}
+
+	total := 0
+	for _, target := range targets {
+		findings := lint(target)
+		score := MaxScore
+		for _, finding := range findings {
+			score -= finding.Weight
+		}
+		total += score
+
+		report.Deployments = append(report.Deployments, DeploymentAvailability{
+			ObjectMeta: api.NewObjectMeta(target.deployment.ObjectMeta),
+			TypeMeta:   api.NewTypeMeta(api.ResourceKindDeployment),
+			Score:      score,
+			Findings:   findings,
+		})
+	}
+	if len(targets) > 0 {
+		report.Score = total / len(targets)
+	}
+
+	sort.Sort(deploymentsByScore(report.Deployments))
+	return report
+}
+
+// hasDisruptionBudget returns true if pods of given deployment are selected by any of given
+// budgets.
+func hasDisruptionBudget(deployment extensions.Deployment, pdbs []policy.PodDisruptionBudget) bool {
+	for _, pdb := range pdbs {
+		if pdb.Namespace != deployment.Namespace || pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metaV1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			continue
+		}
+		if selector.Matches(labels.Set(deployment.Spec.Template.Labels)) {
+			return true
+		}
+	}
+	return false
+}
+
+type deploymentsByScore []DeploymentAvailability
+
+func (self deploymentsByScore) Len() int      { return len(self) }
+func (self deploymentsByScore) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+func (self deploymentsByScore) Less(i, j int) bool {
+	if self[i].Score != self[j].Score {
+		return self[i].Score < self[j].Score
+	}
+	if self[i].ObjectMeta.Namespace != self[j].ObjectMeta.Namespace {
+		return self[i].ObjectMeta.Namespace < self[j].ObjectMeta.Namespace
+	}
+	return self[i].ObjectMeta.Name < self[j].ObjectMeta.Name
+}
@@ -0,0 +1,178 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package availability
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+	policy "k8s.io/client-go/pkg/apis/policy/v1beta1"
+	"k8s.io/client-go/rest"
+)
+
+// protobufContentType is the content type negotiated by dashboard clients.
+const protobufContentType = "application/vnd.kubernetes.protobuf"
+
+// newProtobufServer returns a server that serves given objects by path. Like the API server, it
+// answers in protobuf unless JSON is accepted explicitly.
+func newProtobufServer(t *testing.T, objects map[string]runtime.Object) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		object, ok := objects[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		mediaType := protobufContentType
+		if strings.HasPrefix(r.Header.Get("Accept"), runtime.ContentTypeJSON) {
+			mediaType = runtime.ContentTypeJSON
+		}
+		info, _ := runtime.SerializerInfoForMediaType(scheme.Codecs.SupportedMediaTypes(), mediaType)
+		kinds, _, err := scheme.Scheme.ObjectKinds(object)
+		if err != nil {
+			t.Fatalf("Cannot serve %#v: %s", object, err)
+		}
+
+		w.Header().Set("Content-Type", mediaType)
+		encoder := scheme.Codecs.EncoderForVersion(info.Serializer, kinds[0].GroupVersion())
+		if err := encoder.Encode(object, w); err != nil {
+			t.Errorf("Cannot serve %#v: %s", object, err)
+		}
+	}))
+}
+
+func newDeployment(name string, replicas int32, containers ...v1.Container) extensions.Deployment {
+	return extensions.Deployment{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: extensions.DeploymentSpec{
+			Replicas: &replicas,
+			Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: containers}},
+		},
+	}
+}
+
+var healthyContainer = v1.Container{
+	Name:  "app",
+	Image: "registry:5000/app:1.2",
+	Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("100m"),
+		v1.ResourceMemory: resource.MustParse("64Mi"),
+	}},
+	LivenessProbe: &v1.Probe{},
+}
+
+func TestLint(t *testing.T) {
+	cases := []struct {
+		target   target
+		expected []string
+	}{
+		{
+			target{deployment: newDeployment("web", 3, healthyContainer), topologySpread: true,
+				disruptionBudget: true},
+			[]string{},
+		},
+		{
+			target{deployment: newDeployment("web", 1, v1.Container{Name: "app", Image: "app"})},
+			[]string{RuleSingleReplica, RuleNoDisruptionBudget, RuleNoResourceRequests,
+				RuleLatestTag, RuleNoLivenessProbe},
+		},
+		{
+			target{deployment: newDeployment("web", 2, healthyContainer), disruptionBudget: true},
+			[]string{RuleNoSpread},
+		},
+	}
+	for _, c := range cases {
+		actual := make([]string, 0)
+		for _, finding := range lint(c.target) {
+			actual = append(actual, finding.Rule)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("lint(%s) == \ngot %#v, \nexpected %#v", c.target.deployment.Name, actual,
+				c.expected)
+		}
+	}
+}
+
+func TestIsLatestImage(t *testing.T) {
+	cases := []struct {
+		image    string
+		expected bool
+	}{
+		{"nginx", true},
+		{"nginx:latest", true},
+		{"registry:5000/nginx", true},
+		{"registry:5000/nginx:1.13", false},
+		{"nginx@sha256:abc", false},
+	}
+	for _, c := range cases {
+		actual := isLatestImage(c.image)
+		if actual != c.expected {
+			t.Errorf("isLatestImage(%#v) == \ngot %#v, \nexpected %#v", c.image, actual, c.expected)
+		}
+	}
+}
+
+func TestToAvailabilityReport(t *testing.T) {
+	report := toAvailabilityReport([]target{
+		{deployment: newDeployment("good", 3, healthyContainer), topologySpread: true,
+			disruptionBudget: true},
+		{deployment: newDeployment("bad", 1, healthyContainer)},
+	})
+
+	if report.Score != 80 {
+		t.Errorf("toAvailabilityReport() == \ngot score %d, \nexpected 80", report.Score)
+	}
+	if len(report.Deployments) != 2 || report.Deployments[0].ObjectMeta.Name != "bad" ||
+		report.Deployments[0].Score != 60 || report.Deployments[1].Score != MaxScore {
+		t.Errorf("toAvailabilityReport() == \ngot %#v, \nexpected bad deployment first", report.Deployments)
+	}
+}
+
+func TestGetAvailabilityReportFromProtobufServer(t *testing.T) {
+	server := newProtobufServer(t, map[string]runtime.Object{
+		"/apis/extensions/v1beta1/deployments": &extensions.DeploymentList{
+			Items: []extensions.Deployment{newDeployment("web", 1, healthyContainer)},
+		},
+		"/apis/policy/v1beta1/poddisruptionbudgets": &policy.PodDisruptionBudgetList{},
+	})
+	defer server.Close()
+
+	client, err := kubernetes.NewForConfig(&rest.Config{
+		Host:          server.URL,
+		ContentConfig: rest.ContentConfig{ContentType: protobufContentType},
+	})
+	if err != nil {
+		t.Fatalf("Cannot create client: %s", err)
+	}
+
+	report, err := GetAvailabilityReport(client, common.NewNamespaceQuery(nil))
+	if err != nil {
+		t.Fatalf("GetAvailabilityReport() == \ngot err %#v", err)
+	}
+	if len(report.Deployments) != 1 || report.Deployments[0].ObjectMeta.Name != "web" {
+		t.Errorf("GetAvailabilityReport() == \ngot %#v, \nexpected web deployment", report)
+	}
+}
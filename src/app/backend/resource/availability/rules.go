@@ -0,0 +1,151 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package availability
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+// Rules checked by the linter.
+const (
+	RuleSingleReplica      = "SingleReplica"
+	RuleNoDisruptionBudget = "NoDisruptionBudget"
+	RuleNoSpread           = "NoSpread"
+	RuleNoResourceRequests = "NoResourceRequests"
+	RuleLatestTag          = "LatestTag"
+	RuleNoLivenessProbe    = "NoLivenessProbe"
+)
+
+// ruleWeights are score penalties of the rules, which add up to MaxScore.
+var ruleWeights = map[string]int{
+	RuleSingleReplica:      25,
+	RuleNoDisruptionBudget: 15,
+	RuleNoSpread:           15,
+	RuleNoResourceRequests: 15,
+	RuleLatestTag:          15,
+	RuleNoLivenessProbe:    15,
+}
+
+// Finding is a high availability anti-pattern found in a deployment.
+type Finding struct {
+	Rule string `json:"rule"`
+
+	// Points the finding takes from the score.
+	Weight int `json:"weight"`
+
+	Message string `json:"message"`
+}
+
+// target is a deployment linted with facts not available in the deployment itself.
+type target struct {
+	deployment extensions.Deployment
+
+	// Whether pod template has topology spread constraints.
+	topologySpread bool
+
+	// Whether pods are selected by a pod disruption budget.
+	disruptionBudget bool
+}
+
+// lint returns findings of given deployment in order of the rules.
+func lint(target target) []Finding {
+	findings := make([]Finding, 0)
+	add := func(rule, format string, args ...interface{}) {
+		findings = append(findings, Finding{
+			Rule:    rule,
+			Weight:  ruleWeights[rule],
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	replicas := int32(1)
+	if target.deployment.Spec.Replicas != nil {
+		replicas = *target.deployment.Spec.Replicas
+	}
+	template := target.deployment.Spec.Template.Spec
+
+	if replicas == 1 {
+		add(RuleSingleReplica, "Deployment runs a single replica, which is unavailable while its pod "+
+			"is rescheduled")
+	}
+	if !target.disruptionBudget {
+		add(RuleNoDisruptionBudget, "No pod disruption budget limits voluntary disruptions of pods")
+	}
+	if replicas > 1 && !target.topologySpread &&
+		(template.Affinity == nil || template.Affinity.PodAntiAffinity == nil) {
+		add(RuleNoSpread, "Neither pod anti-affinity nor topology spread constraints keep replicas "+
+			"on different nodes")
+	}
+	if containers := getContainersWithoutRequests(template.Containers); len(containers) > 0 {
+		add(RuleNoResourceRequests, "Containers without CPU or memory requests: %s",
+			strings.Join(containers, ", "))
+	}
+	if images := getLatestImages(template.Containers); len(images) > 0 {
+		add(RuleLatestTag, "Images without a pinned tag: %s", strings.Join(images, ", "))
+	}
+	if containers := getContainersWithoutLiveness(template.Containers); len(containers) > 0 {
+		add(RuleNoLivenessProbe, "Containers without liveness probe: %s",
+			strings.Join(containers, ", "))
+	}
+	return findings
+}
+
+func getContainersWithoutRequests(containers []v1.Container) []string {
+	result := make([]string, 0)
+	for _, container := range containers {
+		_, cpu := container.Resources.Requests[v1.ResourceCPU]
+		_, memory := container.Resources.Requests[v1.ResourceMemory]
+		if !cpu || !memory {
+			result = append(result, container.Name)
+		}
+	}
+	return result
+}
+
+func getContainersWithoutLiveness(containers []v1.Container) []string {
+	result := make([]string, 0)
+	for _, container := range containers {
+		if container.LivenessProbe == nil {
+			result = append(result, container.Name)
+		}
+	}
+	return result
+}
+
+func getLatestImages(containers []v1.Container) []string {
+	result := make([]string, 0)
+	for _, container := range containers {
+		if isLatestImage(container.Image) {
+			result = append(result, container.Image)
+		}
+	}
+	return result
+}
+
+// isLatestImage returns true if given image has no tag or the latest tag, so that it can change
+// between restarts of pods. Images referenced by digest are pinned.
+func isLatestImage(image string) bool {
+	if strings.Contains(image, "@") {
+		return false
+	}
+	// Registry host may contain a port, so the tag is searched in the last path segment only.
+	name := image[strings.LastIndex(image, "/")+1:]
+	i := strings.LastIndex(name, ":")
+	return i < 0 || name[i+1:] == "latest"
+}
@@ -0,0 +1,299 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/certmanager"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+// ExpiryWarningPeriod is how long before expiry a certificate is reported as expiring.
+const ExpiryWarningPeriod = 30 * 24 * time.Hour
+
+// CertificateStatus is an expiry status of a TLS certificate.
+type CertificateStatus string
+
+const (
+	// CertificateStatusValid means that the certificate does not expire soon.
+	CertificateStatusValid CertificateStatus = "Valid"
+	// CertificateStatusExpiring means that the certificate expires within ExpiryWarningPeriod.
+	CertificateStatusExpiring CertificateStatus = "Expiring"
+	// CertificateStatusExpired means that the certificate is no longer valid.
+	CertificateStatusExpired CertificateStatus = "Expired"
+	// CertificateStatusInvalid means that the secret does not contain a parsable certificate.
+	CertificateStatusInvalid CertificateStatus = "Invalid"
+	// CertificateStatusMissing means that the secret is referenced, but does not exist.
+	CertificateStatusMissing CertificateStatus = "Missing"
+)
+
+// IngressReference is an ingress that serves a TLS certificate.
+type IngressReference struct {
+	Name  string   `json:"name"`
+	Hosts []string `json:"hosts"`
+}
+
+// Certificate is a TLS certificate stored in a secret.
+type Certificate struct {
+	// Object meta of the secret with the certificate.
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	// Subject common name and DNS names of the certificate.
+	CommonName string   `json:"commonName,omitempty"`
+	DNSNames   []string `json:"dnsNames"`
+
+	// Common name of the issuer of the certificate.
+	Issuer string `json:"issuer,omitempty"`
+
+	// Validity period of the certificate, empty when the certificate is invalid or missing.
+	NotBefore *metaV1.Time `json:"notBefore,omitempty"`
+	NotAfter  *metaV1.Time `json:"notAfter,omitempty"`
+
+	Status CertificateStatus `json:"status"`
+
+	// Human readable problems with the certificate, e.g. that it will not be renewed.
+	Warnings []string `json:"warnings"`
+
+	// Ingresses in the namespace of the secret that serve the certificate.
+	Ingresses []IngressReference `json:"ingresses"`
+
+	// cert-manager certificate that manages the secret, nil when it's not managed by cert-manager.
+	CertManager *certmanager.Certificate `json:"certManager,omitempty"`
+}
+
+// CertificateReport contains TLS certificates sorted by expiry, the ones expiring first are first.
+type CertificateReport struct {
+	ListMeta api.ListMeta `json:"listMeta"`
+
+	// Whether cert-manager is installed in the cluster.
+	CertManagerInstalled bool `json:"certManagerInstalled"`
+
+	Certificates []Certificate `json:"certificates"`
+}
+
+// GetCertificateReport returns TLS certificates of TLS secrets and of secrets referenced by
+// ingresses in given namespaces.
+func GetCertificateReport(client client.Interface, nsQuery *common.NamespaceQuery) (
+	*CertificateReport, error) {
+	log.Print("Getting TLS certificate report")
+
+	channels := &common.ResourceChannels{
+		SecretList:  common.GetSecretListChannel(client, nsQuery, 1),
+		IngressList: common.GetIngressListChannel(client, nsQuery, 1),
+	}
+
+	certManagerCertificates, certManagerInstalled, err := certmanager.GetCertificates(client, nsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := <-channels.SecretList.List
+	if err := <-channels.SecretList.Error; err != nil {
+		return nil, err
+	}
+
+	ingresses := <-channels.IngressList.List
+	if err := <-channels.IngressList.Error; err != nil {
+		return nil, err
+	}
+
+	report := getCertificateReport(secrets.Items, ingresses.Items, certManagerCertificates, time.Now())
+	report.CertManagerInstalled = certManagerInstalled
+	return report, nil
+}
+
+func getCertificateReport(secrets []v1.Secret, ingresses []extensions.Ingress,
+	certManagerCertificates []certmanager.Certificate, now time.Time) *CertificateReport {
+	certificates := map[string]*Certificate{}
+	getCertificate := func(namespace, name string) *Certificate {
+		key := namespace + "/" + name
+		if _, ok := certificates[key]; !ok {
+			certificates[key] = &Certificate{
+				ObjectMeta: api.ObjectMeta{Name: name, Namespace: namespace},
+				TypeMeta:   api.NewTypeMeta(api.ResourceKindSecret),
+				DNSNames:   []string{},
+				Status:     CertificateStatusMissing,
+				Warnings:   []string{},
+				Ingresses:  []IngressReference{},
+			}
+		}
+		return certificates[key]
+	}
+
+	// Certificates of secrets that are not of TLS type are reported only when an ingress or
+	// cert-manager references them.
+	referenced := map[string]bool{}
+	for _, ingress := range ingresses {
+		for _, tls := range ingress.Spec.TLS {
+			// Ingress without secret name uses the default certificate of the controller.
+			if tls.SecretName == "" {
+				continue
+			}
+			certificate := getCertificate(ingress.ObjectMeta.Namespace, tls.SecretName)
+			certificate.Ingresses = append(certificate.Ingresses, IngressReference{
+				Name:  ingress.ObjectMeta.Name,
+				Hosts: tls.Hosts,
+			})
+			referenced[ingress.ObjectMeta.Namespace+"/"+tls.SecretName] = true
+		}
+	}
+	for i, manager := range certManagerCertificates {
+		if manager.SecretName == "" {
+			continue
+		}
+		getCertificate(manager.ObjectMeta.Namespace, manager.SecretName).CertManager =
+			&certManagerCertificates[i]
+		referenced[manager.ObjectMeta.Namespace+"/"+manager.SecretName] = true
+	}
+
+	for _, secret := range secrets {
+		if secret.Type != v1.SecretTypeTLS &&
+			!referenced[secret.ObjectMeta.Namespace+"/"+secret.ObjectMeta.Name] {
+			continue
+		}
+		certificate := getCertificate(secret.ObjectMeta.Namespace, secret.ObjectMeta.Name)
+		certificate.ObjectMeta = api.NewObjectMeta(secret.ObjectMeta)
+		setCertificateData(certificate, secret.Data[v1.TLSCertKey], now)
+	}
+
+	result := &CertificateReport{Certificates: make([]Certificate, 0, len(certificates))}
+	for _, certificate := range certificates {
+		certificate.Warnings = getCertificateWarnings(certificate, now)
+		result.Certificates = append(result.Certificates, *certificate)
+	}
+	sort.Sort(certificatesByExpiry(result.Certificates))
+	result.ListMeta = api.ListMeta{TotalItems: len(result.Certificates)}
+	return result
+}
+
+// setCertificateData sets details and status of the certificate from PEM encoded data of the
+// secret. Only the first certificate is used, the rest of the data is its chain.
+func setCertificateData(certificate *Certificate, data []byte, now time.Time) {
+	certificate.Status = CertificateStatusInvalid
+	var block *pem.Block
+	for {
+		block, data = pem.Decode(data)
+		if block == nil || block.Type == "CERTIFICATE" {
+			break
+		}
+	}
+	if block == nil {
+		return
+	}
+	parsed, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return
+	}
+
+	certificate.CommonName = parsed.Subject.CommonName
+	certificate.Issuer = parsed.Issuer.CommonName
+	if parsed.DNSNames != nil {
+		certificate.DNSNames = parsed.DNSNames
+	}
+	notBefore, notAfter := metaV1.NewTime(parsed.NotBefore), metaV1.NewTime(parsed.NotAfter)
+	certificate.NotBefore, certificate.NotAfter = &notBefore, &notAfter
+
+	switch {
+	case now.After(parsed.NotAfter):
+		certificate.Status = CertificateStatusExpired
+	case now.Add(ExpiryWarningPeriod).After(parsed.NotAfter):
+		certificate.Status = CertificateStatusExpiring
+	default:
+		certificate.Status = CertificateStatusValid
+	}
+
+	for _, ingress := range certificate.Ingresses {
+		for _, host := range ingress.Hosts {
+			if parsed.VerifyHostname(host) != nil {
+				certificate.Warnings = append(certificate.Warnings, fmt.Sprintf(
+					"Host %s of ingress %s is not covered by the certificate.", host, ingress.Name))
+			}
+		}
+	}
+}
+
+// getCertificateWarnings returns warnings about the certificate, e.g. when it's expiring and
+// nothing is going to renew it.
+func getCertificateWarnings(certificate *Certificate, now time.Time) []string {
+	warnings := []string{}
+	manager := certificate.CertManager
+
+	switch certificate.Status {
+	case CertificateStatusMissing:
+		if manager != nil && manager.Ready != v1.ConditionTrue {
+			warnings = append(warnings, fmt.Sprintf(
+				"Secret does not exist, cert-manager has not issued the certificate yet. %s",
+				manager.Message))
+		} else {
+			warnings = append(warnings, "Secret does not exist.")
+		}
+	case CertificateStatusInvalid:
+		warnings = append(warnings, "Secret does not contain a valid PEM encoded certificate.")
+	case CertificateStatusExpired:
+		warnings = append(warnings, fmt.Sprintf("Certificate expired on %s.",
+			certificate.NotAfter.UTC().Format(time.RFC3339)))
+	}
+
+	if certificate.Status == CertificateStatusExpired || certificate.Status == CertificateStatusExpiring {
+		switch {
+		case manager == nil:
+			if certificate.Status == CertificateStatusExpiring {
+				warnings = append(warnings, fmt.Sprintf(
+					"Certificate expires in %d days and is not renewed by cert-manager.",
+					int(certificate.NotAfter.Sub(now).Hours()/24)))
+			}
+		case manager.Ready != v1.ConditionTrue:
+			warnings = append(warnings, fmt.Sprintf("cert-manager cannot renew the certificate. %s",
+				manager.Message))
+		case manager.RenewalTime != nil && manager.RenewalTime.Time.Before(now):
+			warnings = append(warnings, "Renewal time of cert-manager has passed, but the certificate "+
+				"was not renewed.")
+		}
+	}
+
+	return append(warnings, certificate.Warnings...)
+}
+
+// certificatesByExpiry sorts certificates so that missing and invalid ones are first, followed by
+// the ones that expire first.
+type certificatesByExpiry []Certificate
+
+func (self certificatesByExpiry) Len() int      { return len(self) }
+func (self certificatesByExpiry) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+func (self certificatesByExpiry) Less(i, j int) bool {
+	left, right := self[i].NotAfter, self[j].NotAfter
+	if (left == nil) != (right == nil) {
+		return left == nil
+	}
+	if left != nil && !left.Time.Equal(right.Time) {
+		return left.Time.Before(right.Time)
+	}
+	if self[i].ObjectMeta.Namespace != self[j].ObjectMeta.Namespace {
+		return self[i].ObjectMeta.Namespace < self[j].ObjectMeta.Namespace
+	}
+	return self[i].ObjectMeta.Name < self[j].ObjectMeta.Name
+}
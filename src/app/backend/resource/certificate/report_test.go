@@ -0,0 +1,130 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/certmanager"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+func newCertificatePEM(t *testing.T, dnsNames []string, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() returned error: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("x509.CreateCertificate() returned error: %s", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func newTLSSecret(name string, certificate []byte) v1.Secret {
+	return v1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default"},
+		Type:       v1.SecretTypeTLS,
+		Data:       map[string][]byte{v1.TLSCertKey: certificate},
+	}
+}
+
+func TestGetCertificateReport(t *testing.T) {
+	now := time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	secrets := []v1.Secret{
+		newTLSSecret("valid", newCertificatePEM(t, []string{"foo.com"}, now.Add(90*day))),
+		newTLSSecret("expiring", newCertificatePEM(t, []string{"bar.com"}, now.Add(10*day))),
+		newTLSSecret("renewed", newCertificatePEM(t, []string{"baz.com"}, now.Add(20*day))),
+		newTLSSecret("expired", newCertificatePEM(t, []string{"qux.com"}, now.Add(-day))),
+		newTLSSecret("invalid", []byte("foo")),
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "opaque", Namespace: "default"},
+			Type:       v1.SecretTypeOpaque,
+		},
+	}
+	ingresses := []extensions.Ingress{{
+		ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: extensions.IngressSpec{TLS: []extensions.IngressTLS{
+			{Hosts: []string{"foo.com", "www.foo.com"}, SecretName: "valid"},
+			{Hosts: []string{"bar.com"}, SecretName: "missing"},
+			{Hosts: []string{"default.com"}},
+		}},
+	}}
+	certManagerCertificates := []certmanager.Certificate{{
+		ObjectMeta: api.ObjectMeta{Name: "baz", Namespace: "default"},
+		SecretName: "renewed",
+		Ready:      v1.ConditionTrue,
+	}}
+
+	expected := []struct {
+		name     string
+		status   CertificateStatus
+		warnings []string
+	}{
+		{"invalid", CertificateStatusInvalid,
+			[]string{"Secret does not contain a valid PEM encoded certificate."}},
+		{"missing", CertificateStatusMissing, []string{"Secret does not exist."}},
+		{"expired", CertificateStatusExpired, []string{"Certificate expired on 2017-05-04T10:00:00Z."}},
+		{"expiring", CertificateStatusExpiring,
+			[]string{"Certificate expires in 10 days and is not renewed by cert-manager."}},
+		{"renewed", CertificateStatusExpiring, []string{}},
+		{"valid", CertificateStatusValid,
+			[]string{"Host www.foo.com of ingress web is not covered by the certificate."}},
+	}
+
+	actual := getCertificateReport(secrets, ingresses, certManagerCertificates, now)
+	if actual.ListMeta.TotalItems != len(expected) || len(actual.Certificates) != len(expected) {
+		t.Fatalf("getCertificateReport() returned %d certificates, expected %d", len(actual.Certificates),
+			len(expected))
+	}
+	for i, e := range expected {
+		c := actual.Certificates[i]
+		if c.ObjectMeta.Name != e.name || c.Status != e.status || !reflect.DeepEqual(c.Warnings, e.warnings) {
+			t.Errorf("getCertificateReport() certificate %d == \ngot: %s %s %#v, \nexpected %s %s %#v", i,
+				c.ObjectMeta.Name, c.Status, c.Warnings, e.name, e.status, e.warnings)
+		}
+	}
+
+	renewed := actual.Certificates[4]
+	if renewed.CertManager == nil || renewed.CertManager.ObjectMeta.Name != "baz" ||
+		renewed.CertManager.Ready != v1.ConditionTrue {
+		t.Errorf("getCertificateReport() cert-manager certificate == \ngot: %#v", renewed.CertManager)
+	}
+	if renewed.CommonName != "baz.com" || !reflect.DeepEqual(renewed.DNSNames, []string{"baz.com"}) {
+		t.Errorf("getCertificateReport() certificate names == \ngot: %s %#v", renewed.CommonName,
+			renewed.DNSNames)
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certmanager
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+// IssuerReference is a reference to the issuer of a certificate.
+type IssuerReference struct {
+	Name string `json:"name"`
+
+	// Kind of the issuer, Issuer or ClusterIssuer.
+	Kind string `json:"kind"`
+}
+
+// Certificate is a presentation layer view of cert-manager Certificate resource.
+type Certificate struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	// Name of the secret the certificate is stored in.
+	SecretName string `json:"secretName"`
+
+	DNSNames []string        `json:"dnsNames"`
+	Issuer   IssuerReference `json:"issuer"`
+
+	// Status and message of the Ready condition of the certificate.
+	Ready   v1.ConditionStatus `json:"ready"`
+	Message string             `json:"message,omitempty"`
+
+	// Expiry of the issued certificate and time when cert-manager will renew it.
+	NotAfter    *metaV1.Time `json:"notAfter,omitempty"`
+	RenewalTime *metaV1.Time `json:"renewalTime,omitempty"`
+}
+
+// CertificateList contains a list of cert-manager certificates.
+type CertificateList struct {
+	ListMeta     api.ListMeta  `json:"listMeta"`
+	Certificates []Certificate `json:"certificates"`
+}
+
+// CertificateDetail contains certificate with its conditions and ACME orders created for it.
+type CertificateDetail struct {
+	Certificate
+	Conditions []Condition `json:"conditions"`
+	OrderList  OrderList   `json:"orderList"`
+}
+
+// rawCertificate is a subset of the Certificate custom resource used by the dashboard.
+type rawCertificate struct {
+	ObjectMeta metaV1.ObjectMeta `json:"metadata"`
+	Spec       struct {
+		SecretName string          `json:"secretName"`
+		CommonName string          `json:"commonName"`
+		DNSNames   []string        `json:"dnsNames"`
+		IssuerRef  IssuerReference `json:"issuerRef"`
+	} `json:"spec"`
+	Status struct {
+		Conditions  []Condition  `json:"conditions"`
+		NotAfter    *metaV1.Time `json:"notAfter"`
+		RenewalTime *metaV1.Time `json:"renewalTime"`
+	} `json:"status"`
+}
+
+type rawCertificateList struct {
+	Items []rawCertificate `json:"items"`
+}
+
+// GetCertificates returns all cert-manager certificates in given namespaces. Second value is false
+// when cert-manager is not installed in the cluster.
+func GetCertificates(client client.Interface, nsQuery *common.NamespaceQuery) ([]Certificate, bool,
+	error) {
+	rawCertificates, found, err := getCertificates(client, nsQuery)
+	if err != nil || !found {
+		return nil, found, err
+	}
+
+	certificates := make([]Certificate, len(rawCertificates))
+	for i, certificate := range rawCertificates {
+		certificates[i] = toCertificate(certificate)
+	}
+	return certificates, true, nil
+}
+
+// GetCertificateList returns a list of cert-manager certificates in given namespaces. Returns empty
+// list when cert-manager is not installed.
+func GetCertificateList(client client.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*CertificateList, error) {
+	log.Print("Getting list of cert-manager certificates")
+
+	certificates, _, err := getCertificates(client, nsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	return toCertificateList(certificates, dsQuery), nil
+}
+
+// GetCertificateDetail returns cert-manager certificate with given name along with its orders.
+func GetCertificateDetail(client client.Interface, namespace, name string) (*CertificateDetail,
+	error) {
+	log.Printf("Getting details of %s cert-manager certificate in %s namespace", name, namespace)
+
+	groupVersion, err := common.GetInstalledGroupVersion(client, "certificates", name,
+		CertManagerGroup, LegacyCertManagerGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var certificate rawCertificate
+	if err := common.GetCustomResource(client, groupVersion, namespace, "certificates", name,
+		&certificate); err != nil {
+		return nil, err
+	}
+
+	orders, err := getOrders(client, common.NewSameNamespaceQuery(namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	return &CertificateDetail{
+		Certificate: toCertificate(certificate),
+		Conditions:  toConditions(certificate.Status.Conditions),
+		OrderList:   *toOrderList(filterOrdersByCertificate(orders, name), dataselect.DefaultDataSelect),
+	}, nil
+}
+
+// RenewCertificate makes cert-manager issue given certificate again, before its renewal time. It
+// sets the Issuing condition of the certificate the same way cmctl renew does, which is supported
+// only by cert-manager.io API group.
+func RenewCertificate(client client.Interface, namespace, name string) error {
+	log.Printf("Renewing %s cert-manager certificate in %s namespace", name, namespace)
+
+	groupVersion, found, err := common.GetGroupVersion(client, CertManagerGroup,
+		LegacyCertManagerGroup)
+	if err != nil {
+		return err
+	}
+	if !found || !strings.HasPrefix(groupVersion, CertManagerGroup+"/") {
+		return k8serrors.NewBadRequest("Renewing certificates is supported only by " +
+			CertManagerGroup + " API of cert-manager")
+	}
+
+	path := common.GetCustomResourcePath(groupVersion, namespace, "certificates", name)
+	raw, err := common.JSONRequest(client.CoreV1().RESTClient().Get().AbsPath(path)).Do().Raw()
+	if err != nil {
+		return err
+	}
+
+	patch, err := getRenewPatch(raw, time.Now())
+	if err != nil {
+		return err
+	}
+
+	_, err = client.CoreV1().RESTClient().Patch(types.MergePatchType).
+		AbsPath(path + "/status").
+		Body(patch).
+		DoRaw()
+	return err
+}
+
+// getRenewPatch returns merge patch of certificate status that sets its Issuing condition. Merge
+// patch replaces whole list of conditions, so other conditions are kept as they are.
+func getRenewPatch(raw []byte, now time.Time) ([]byte, error) {
+	certificate := struct {
+		Status struct {
+			Conditions []map[string]interface{} `json:"conditions"`
+		} `json:"status"`
+	}{}
+	if err := json.Unmarshal(raw, &certificate); err != nil {
+		return nil, err
+	}
+
+	conditions := make([]map[string]interface{}, 0)
+	for _, condition := range certificate.Status.Conditions {
+		if condition["type"] != "Issuing" {
+			conditions = append(conditions, condition)
+		}
+	}
+	conditions = append(conditions, map[string]interface{}{
+		"type":               "Issuing",
+		"status":             v1.ConditionTrue,
+		"reason":             "ManuallyTriggered",
+		"message":            "Certificate re-issuance manually triggered",
+		"lastTransitionTime": metaV1.NewTime(now),
+	})
+
+	return json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{"conditions": conditions},
+	})
+}
+
+func getCertificates(client client.Interface, nsQuery *common.NamespaceQuery) ([]rawCertificate,
+	bool, error) {
+	groupVersion, found, err := common.GetGroupVersion(client, CertManagerGroup,
+		LegacyCertManagerGroup)
+	if err != nil || !found {
+		return nil, found, err
+	}
+
+	var list rawCertificateList
+	if err := common.GetCustomResource(client, groupVersion, nsQuery.ToRequestParam(),
+		"certificates", "", &list); err != nil {
+		return nil, true, err
+	}
+
+	certificates := make([]rawCertificate, 0)
+	for _, certificate := range list.Items {
+		if nsQuery.Matches(certificate.ObjectMeta.Namespace) {
+			certificates = append(certificates, certificate)
+		}
+	}
+	return certificates, true, nil
+}
+
+func toCertificateList(certificates []rawCertificate,
+	dsQuery *dataselect.DataSelectQuery) *CertificateList {
+	certificateList := &CertificateList{
+		Certificates: make([]Certificate, 0),
+		ListMeta:     api.ListMeta{TotalItems: len(certificates)},
+	}
+
+	certificateCells, filteredTotal := dataselect.GenericDataSelectWithFilter(
+		toCertificateCells(certificates), dsQuery)
+	certificates = fromCertificateCells(certificateCells)
+	certificateList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal,
+		certificateList.ListMeta.TotalItems)
+
+	for _, certificate := range certificates {
+		certificateList.Certificates = append(certificateList.Certificates, toCertificate(certificate))
+	}
+
+	return certificateList
+}
+
+func toCertificate(certificate rawCertificate) Certificate {
+	dnsNames := certificate.Spec.DNSNames
+	if dnsNames == nil {
+		dnsNames = make([]string, 0)
+	}
+	if certificate.Spec.CommonName != "" && !containsString(dnsNames, certificate.Spec.CommonName) {
+		dnsNames = append([]string{certificate.Spec.CommonName}, dnsNames...)
+	}
+
+	issuer := certificate.Spec.IssuerRef
+	if issuer.Kind == "" {
+		issuer.Kind = "Issuer"
+	}
+
+	ready, message := getReadyCondition(certificate.Status.Conditions)
+	return Certificate{
+		ObjectMeta:  api.NewObjectMeta(certificate.ObjectMeta),
+		TypeMeta:    api.NewTypeMeta(api.ResourceKindCertificate),
+		SecretName:  certificate.Spec.SecretName,
+		DNSNames:    dnsNames,
+		Issuer:      issuer,
+		Ready:       ready,
+		Message:     message,
+		NotAfter:    certificate.Status.NotAfter,
+		RenewalTime: certificate.Status.RenewalTime,
+	}
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
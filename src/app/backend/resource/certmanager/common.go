@@ -0,0 +1,185 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certmanager
+
+import (
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+const (
+	// CertManagerGroup is the API group of cert-manager custom resources.
+	CertManagerGroup = "cert-manager.io"
+	// LegacyCertManagerGroup is the API group used by cert-manager before version 0.11.
+	LegacyCertManagerGroup = "certmanager.k8s.io"
+)
+
+// Annotations cert-manager puts on resources it creates and reads from ingresses. Legacy API group
+// uses the same keys with LegacyCertManagerGroup prefix.
+const (
+	certificateNameAnnotation = "/certificate-name"
+	issuerAnnotation          = "/issuer"
+	clusterIssuerAnnotation   = "/cluster-issuer"
+)
+
+// getAnnotation returns value of cert-manager annotation with given key, legacy annotations are
+// used when current ones are not set.
+func getAnnotation(meta metaV1.ObjectMeta, key string) string {
+	if value, ok := meta.Annotations[CertManagerGroup+key]; ok {
+		return value
+	}
+	return meta.Annotations[LegacyCertManagerGroup+key]
+}
+
+// GetCertificateName returns name of the cert-manager certificate that created given resource,
+// e.g. a secret. It is empty for resources not created by cert-manager.
+func GetCertificateName(meta metaV1.ObjectMeta) string {
+	return getAnnotation(meta, certificateNameAnnotation)
+}
+
+// Condition is a status condition of cert-manager resource.
+type Condition struct {
+	Type               string             `json:"type"`
+	Status             v1.ConditionStatus `json:"status"`
+	Reason             string             `json:"reason,omitempty"`
+	Message            string             `json:"message,omitempty"`
+	LastTransitionTime *metaV1.Time       `json:"lastTransitionTime,omitempty"`
+}
+
+// getReadyCondition returns status and message of the Ready condition.
+func getReadyCondition(conditions []Condition) (v1.ConditionStatus, string) {
+	for _, condition := range conditions {
+		if condition.Type == "Ready" {
+			return condition.Status, condition.Message
+		}
+	}
+	return v1.ConditionUnknown, ""
+}
+
+func toConditions(conditions []Condition) []Condition {
+	if conditions == nil {
+		return make([]Condition, 0)
+	}
+	return conditions
+}
+
+// The code below allows to perform complex data section on []rawCertificate.
+
+type CertificateCell rawCertificate
+
+func (self CertificateCell) GetProperty(name dataselect.PropertyName) dataselect.ComparableValue {
+	switch name {
+	case dataselect.NameProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Name)
+	case dataselect.CreationTimestampProperty:
+		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
+	case dataselect.NamespaceProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.UIDProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.UID)
+	default:
+		// if name is not supported then just return a constant dummy value, sort will have no effect.
+		return nil
+	}
+}
+
+func toCertificateCells(std []rawCertificate) []dataselect.DataCell {
+	cells := make([]dataselect.DataCell, len(std))
+	for i := range std {
+		cells[i] = CertificateCell(std[i])
+	}
+	return cells
+}
+
+func fromCertificateCells(cells []dataselect.DataCell) []rawCertificate {
+	std := make([]rawCertificate, len(cells))
+	for i := range std {
+		std[i] = rawCertificate(cells[i].(CertificateCell))
+	}
+	return std
+}
+
+// The code below allows to perform complex data section on []rawIssuer.
+
+type IssuerCell rawIssuer
+
+func (self IssuerCell) GetProperty(name dataselect.PropertyName) dataselect.ComparableValue {
+	switch name {
+	case dataselect.NameProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Name)
+	case dataselect.CreationTimestampProperty:
+		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
+	case dataselect.NamespaceProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.UIDProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.UID)
+	default:
+		// if name is not supported then just return a constant dummy value, sort will have no effect.
+		return nil
+	}
+}
+
+func toIssuerCells(std []rawIssuer) []dataselect.DataCell {
+	cells := make([]dataselect.DataCell, len(std))
+	for i := range std {
+		cells[i] = IssuerCell(std[i])
+	}
+	return cells
+}
+
+func fromIssuerCells(cells []dataselect.DataCell) []rawIssuer {
+	std := make([]rawIssuer, len(cells))
+	for i := range std {
+		std[i] = rawIssuer(cells[i].(IssuerCell))
+	}
+	return std
+}
+
+// The code below allows to perform complex data section on []rawOrder.
+
+type OrderCell rawOrder
+
+func (self OrderCell) GetProperty(name dataselect.PropertyName) dataselect.ComparableValue {
+	switch name {
+	case dataselect.NameProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Name)
+	case dataselect.CreationTimestampProperty:
+		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
+	case dataselect.NamespaceProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.UIDProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.UID)
+	default:
+		// if name is not supported then just return a constant dummy value, sort will have no effect.
+		return nil
+	}
+}
+
+func toOrderCells(std []rawOrder) []dataselect.DataCell {
+	cells := make([]dataselect.DataCell, len(std))
+	for i := range std {
+		cells[i] = OrderCell(std[i])
+	}
+	return cells
+}
+
+func fromOrderCells(cells []dataselect.DataCell) []rawOrder {
+	std := make([]rawOrder, len(cells))
+	for i := range std {
+		std[i] = rawOrder(cells[i].(OrderCell))
+	}
+	return std
+}
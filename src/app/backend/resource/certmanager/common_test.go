@@ -0,0 +1,180 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certmanager
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+func TestToCertificateList(t *testing.T) {
+	raw := `{"items": [{
+		"metadata": {"name": "web", "namespace": "default"},
+		"spec": {"secretName": "web-tls", "commonName": "foo.com", "dnsNames": ["www.foo.com"],
+			"issuerRef": {"name": "letsencrypt", "kind": "ClusterIssuer"}},
+		"status": {
+			"conditions": [{"type": "Ready", "status": "False", "message": "Issuing certificate"}],
+			"notAfter": "2017-06-05T10:00:00Z",
+			"renewalTime": "2017-05-06T10:00:00Z"
+		}
+	}]}`
+
+	var list rawCertificateList
+	if err := json.Unmarshal([]byte(raw), &list); err != nil {
+		t.Fatalf("json.Unmarshal(%#v) returned error: %s", raw, err)
+	}
+
+	notAfter := metaV1.NewTime(time.Date(2017, 6, 5, 10, 0, 0, 0, time.UTC).Local())
+	renewalTime := metaV1.NewTime(time.Date(2017, 5, 6, 10, 0, 0, 0, time.UTC).Local())
+	expected := &CertificateList{
+		ListMeta: api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
+		Certificates: []Certificate{{
+			ObjectMeta:  api.ObjectMeta{Name: "web", Namespace: "default"},
+			TypeMeta:    api.TypeMeta{Kind: api.ResourceKindCertificate},
+			SecretName:  "web-tls",
+			DNSNames:    []string{"foo.com", "www.foo.com"},
+			Issuer:      IssuerReference{Name: "letsencrypt", Kind: "ClusterIssuer"},
+			Ready:       v1.ConditionFalse,
+			Message:     "Issuing certificate",
+			NotAfter:    &notAfter,
+			RenewalTime: &renewalTime,
+		}},
+	}
+
+	actual := toCertificateList(list.Items, dataselect.NoDataSelect)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toCertificateList(%#v) == \ngot: %#v, \nexpected %#v", list.Items, actual, expected)
+	}
+}
+
+func TestGetRenewPatch(t *testing.T) {
+	raw := []byte(`{"status": {"conditions": [
+		{"type": "Ready", "status": "True", "observedGeneration": 2},
+		{"type": "Issuing", "status": "False"}
+	]}}`)
+
+	actual, err := getRenewPatch(raw, time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("getRenewPatch() returned error: %s", err)
+	}
+
+	expected := `{"status":{"conditions":[{"observedGeneration":2,"status":"True","type":"Ready"},` +
+		`{"lastTransitionTime":"2017-05-05T10:00:00Z","message":"Certificate re-issuance manually ` +
+		`triggered","reason":"ManuallyTriggered","status":"True","type":"Issuing"}]}}`
+	if string(actual) != expected {
+		t.Errorf("getRenewPatch() == \ngot: %s, \nexpected %s", actual, expected)
+	}
+}
+
+func TestToIssuerList(t *testing.T) {
+	issuers := []rawIssuer{{
+		Kind:       "Issuer",
+		ObjectMeta: metaV1.ObjectMeta{Name: "ca", Namespace: "default"},
+		Spec:       map[string]json.RawMessage{"ca": json.RawMessage(`{"secretName": "ca"}`)},
+	}}
+	clusterIssuers := []rawIssuer{{
+		Kind:       "ClusterIssuer",
+		ObjectMeta: metaV1.ObjectMeta{Name: "letsencrypt"},
+		Spec: map[string]json.RawMessage{
+			"acme": json.RawMessage(`{"server": "https://acme-v02.api.letsencrypt.org/directory"}`),
+		},
+	}}
+	clusterIssuers[0].Status.Conditions = []Condition{{Type: "Ready", Status: v1.ConditionTrue}}
+
+	expected := &IssuerList{
+		ListMeta: api.ListMeta{TotalItems: 2, TotalBeforeFilter: 2},
+		Issuers: []Issuer{
+			{
+				ObjectMeta: api.ObjectMeta{Name: "ca", Namespace: "default"},
+				TypeMeta:   api.TypeMeta{Kind: api.ResourceKindIssuer},
+				Type:       "ca",
+				Ready:      v1.ConditionUnknown,
+			},
+			{
+				ObjectMeta: api.ObjectMeta{Name: "letsencrypt"},
+				TypeMeta:   api.TypeMeta{Kind: api.ResourceKindClusterIssuer},
+				Type:       "acme",
+				Server:     "https://acme-v02.api.letsencrypt.org/directory",
+				Ready:      v1.ConditionTrue,
+			},
+		},
+	}
+
+	actual := toIssuerList(issuers, clusterIssuers, dataselect.NoDataSelect)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toIssuerList() == \ngot: %#v, \nexpected %#v", actual, expected)
+	}
+}
+
+func TestFilterOrdersByCertificate(t *testing.T) {
+	orders := []rawOrder{
+		{ObjectMeta: metaV1.ObjectMeta{Name: "web-1", Annotations: map[string]string{
+			"cert-manager.io/certificate-name": "web"}}},
+		{ObjectMeta: metaV1.ObjectMeta{Name: "api-1", Annotations: map[string]string{
+			"certmanager.k8s.io/certificate-name": "api"}}},
+	}
+
+	cases := []struct {
+		certificate string
+		expected    []string
+	}{
+		{"", []string{"web-1", "api-1"}},
+		{"web", []string{"web-1"}},
+		{"api", []string{"api-1"}},
+		{"db", []string{}},
+	}
+	for _, c := range cases {
+		actual := []string{}
+		for _, order := range filterOrdersByCertificate(orders, c.certificate) {
+			actual = append(actual, order.ObjectMeta.Name)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("filterOrdersByCertificate(%#v) == \ngot: %#v, \nexpected %#v", c.certificate,
+				actual, c.expected)
+		}
+	}
+}
+
+func TestGetIngressCertificates(t *testing.T) {
+	tls := []extensions.IngressTLS{{SecretName: "web-tls"}, {SecretName: "web-tls"}, {SecretName: ""}}
+	cases := []struct {
+		annotations map[string]string
+		expected    []string
+	}{
+		{nil, []string{}},
+		{map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt"}, []string{"web-tls"}},
+		{map[string]string{"certmanager.k8s.io/issuer": "ca"}, []string{"web-tls"}},
+		{map[string]string{"kubernetes.io/tls-acme": "true"}, []string{"web-tls"}},
+	}
+	for _, c := range cases {
+		ingress := &extensions.Ingress{
+			ObjectMeta: metaV1.ObjectMeta{Annotations: c.annotations},
+			Spec:       extensions.IngressSpec{TLS: tls},
+		}
+		actual := GetIngressCertificates(ingress)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetIngressCertificates(%#v) == \ngot: %#v, \nexpected %#v", c.annotations, actual,
+				c.expected)
+		}
+	}
+}
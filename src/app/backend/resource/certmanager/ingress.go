@@ -0,0 +1,41 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certmanager
+
+import (
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+// tlsACMEAnnotation makes cert-manager issue certificates of an ingress with the default issuer.
+const tlsACMEAnnotation = "kubernetes.io/tls-acme"
+
+// GetIngressCertificates returns names of certificates cert-manager creates for TLS secrets of the
+// ingress. Certificates are created only for ingresses annotated with an issuer and they have the
+// same names as the secrets.
+func GetIngressCertificates(ingress *extensions.Ingress) []string {
+	certificates := make([]string, 0)
+	if getAnnotation(ingress.ObjectMeta, issuerAnnotation) == "" &&
+		getAnnotation(ingress.ObjectMeta, clusterIssuerAnnotation) == "" &&
+		ingress.ObjectMeta.Annotations[tlsACMEAnnotation] != "true" {
+		return certificates
+	}
+
+	for _, tls := range ingress.Spec.TLS {
+		if tls.SecretName != "" && !containsString(certificates, tls.SecretName) {
+			certificates = append(certificates, tls.SecretName)
+		}
+	}
+	return certificates
+}
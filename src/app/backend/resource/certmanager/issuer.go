@@ -0,0 +1,235 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certmanager
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+// issuerTypes are types of issuers supported by cert-manager, each issuer has one of them set in
+// its spec.
+var issuerTypes = []string{"acme", "ca", "vault", "venafi", "selfSigned"}
+
+// Issuer is a presentation layer view of cert-manager Issuer and ClusterIssuer resources.
+type Issuer struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	// Type of the issuer, e.g. acme or ca.
+	Type string `json:"type"`
+
+	// URL of ACME server, set only for acme issuers.
+	Server string `json:"server,omitempty"`
+
+	// Status and message of the Ready condition of the issuer.
+	Ready   v1.ConditionStatus `json:"ready"`
+	Message string             `json:"message,omitempty"`
+}
+
+// IssuerList contains a list of cert-manager issuers and cluster issuers.
+type IssuerList struct {
+	ListMeta api.ListMeta `json:"listMeta"`
+	Issuers  []Issuer     `json:"issuers"`
+}
+
+// IssuerDetail contains issuer with its conditions and certificates issued by it.
+type IssuerDetail struct {
+	Issuer
+	Conditions      []Condition     `json:"conditions"`
+	CertificateList CertificateList `json:"certificateList"`
+}
+
+// rawIssuer is a subset of the Issuer and ClusterIssuer custom resources used by the dashboard.
+type rawIssuer struct {
+	// Kind of the issuer, Issuer or ClusterIssuer.
+	Kind       string                     `json:"kind"`
+	ObjectMeta metaV1.ObjectMeta          `json:"metadata"`
+	Spec       map[string]json.RawMessage `json:"spec"`
+	Status     struct {
+		Conditions []Condition `json:"conditions"`
+	} `json:"status"`
+}
+
+type rawIssuerList struct {
+	Items []rawIssuer `json:"items"`
+}
+
+// GetIssuerList returns a list of cert-manager issuers in given namespaces followed by all cluster
+// issuers. Returns empty list when cert-manager is not installed.
+func GetIssuerList(client client.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*IssuerList, error) {
+	log.Print("Getting list of cert-manager issuers")
+
+	groupVersion, found, err := common.GetGroupVersion(client, CertManagerGroup,
+		LegacyCertManagerGroup)
+	if err != nil || !found {
+		return toIssuerList(nil, nil, dsQuery), err
+	}
+
+	var issuers rawIssuerList
+	if err := common.GetCustomResource(client, groupVersion, nsQuery.ToRequestParam(), "issuers",
+		"", &issuers); err != nil {
+		return nil, err
+	}
+
+	var clusterIssuers rawIssuerList
+	if err := common.GetCustomResource(client, groupVersion, "", "clusterissuers", "",
+		&clusterIssuers); err != nil {
+		return nil, err
+	}
+
+	namespaced := make([]rawIssuer, 0)
+	for _, issuer := range issuers.Items {
+		if nsQuery.Matches(issuer.ObjectMeta.Namespace) {
+			issuer.Kind = "Issuer"
+			namespaced = append(namespaced, issuer)
+		}
+	}
+	for i := range clusterIssuers.Items {
+		clusterIssuers.Items[i].Kind = "ClusterIssuer"
+	}
+
+	return toIssuerList(namespaced, clusterIssuers.Items, dsQuery), nil
+}
+
+// GetIssuerDetail returns cert-manager issuer with given name along with certificates issued by it.
+func GetIssuerDetail(client client.Interface, namespace, name string) (*IssuerDetail, error) {
+	log.Printf("Getting details of %s cert-manager issuer in %s namespace", name, namespace)
+	return getIssuerDetail(client, "Issuer", namespace, name)
+}
+
+// GetClusterIssuerDetail returns cert-manager cluster issuer with given name along with
+// certificates issued by it.
+func GetClusterIssuerDetail(client client.Interface, name string) (*IssuerDetail, error) {
+	log.Printf("Getting details of %s cert-manager cluster issuer", name)
+	return getIssuerDetail(client, "ClusterIssuer", "", name)
+}
+
+func getIssuerDetail(client client.Interface, kind, namespace, name string) (*IssuerDetail, error) {
+	resource, nsQuery := "issuers", common.NewSameNamespaceQuery(namespace)
+	if kind == "ClusterIssuer" {
+		resource, nsQuery = "clusterissuers", common.NewNamespaceQuery(nil)
+	}
+
+	groupVersion, err := common.GetInstalledGroupVersion(client, resource, name, CertManagerGroup,
+		LegacyCertManagerGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var issuer rawIssuer
+	if err := common.GetCustomResource(client, groupVersion, namespace, resource, name,
+		&issuer); err != nil {
+		return nil, err
+	}
+	issuer.Kind = kind
+
+	certificates, _, err := getCertificates(client, nsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IssuerDetail{
+		Issuer:     toIssuer(issuer),
+		Conditions: toConditions(issuer.Status.Conditions),
+		CertificateList: *toCertificateList(filterCertificatesByIssuer(certificates, kind, name),
+			dataselect.DefaultDataSelect),
+	}, nil
+}
+
+func filterCertificatesByIssuer(certificates []rawCertificate, kind, name string) []rawCertificate {
+	result := make([]rawCertificate, 0)
+	for _, certificate := range certificates {
+		issuer := toCertificate(certificate).Issuer
+		if issuer.Kind == kind && issuer.Name == name {
+			result = append(result, certificate)
+		}
+	}
+	return result
+}
+
+func toIssuerList(issuers, clusterIssuers []rawIssuer, dsQuery *dataselect.DataSelectQuery) *IssuerList {
+	issuerList := &IssuerList{
+		Issuers:  make([]Issuer, 0),
+		ListMeta: api.ListMeta{TotalItems: len(issuers) + len(clusterIssuers)},
+	}
+
+	all := append(append(make([]rawIssuer, 0), issuers...), clusterIssuers...)
+
+	issuerCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toIssuerCells(all), dsQuery)
+	all = fromIssuerCells(issuerCells)
+	issuerList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, issuerList.ListMeta.TotalItems)
+
+	for _, issuer := range all {
+		issuerList.Issuers = append(issuerList.Issuers, toIssuer(issuer))
+	}
+
+	return issuerList
+}
+
+func toIssuer(issuer rawIssuer) Issuer {
+	kinds := map[string]api.ResourceKind{
+		"Issuer":        api.ResourceKindIssuer,
+		"ClusterIssuer": api.ResourceKindClusterIssuer,
+	}
+	ready, message := getReadyCondition(issuer.Status.Conditions)
+	issuerType := getIssuerType(issuer.Spec)
+
+	result := Issuer{
+		ObjectMeta: api.NewObjectMeta(issuer.ObjectMeta),
+		TypeMeta:   api.NewTypeMeta(kinds[issuer.Kind]),
+		Type:       issuerType,
+		Ready:      ready,
+		Message:    message,
+	}
+	if issuerType == "acme" {
+		acme := struct {
+			Server string `json:"server"`
+		}{}
+		if json.Unmarshal(issuer.Spec["acme"], &acme) == nil {
+			result.Server = acme.Server
+		}
+	}
+	return result
+}
+
+// getIssuerType returns type of the issuer set in its spec. Issuers of external types, e.g. AWS
+// PCA, are not known in advance, so the first spec field is used for them.
+func getIssuerType(spec map[string]json.RawMessage) string {
+	for _, issuerType := range issuerTypes {
+		if _, ok := spec[issuerType]; ok {
+			return issuerType
+		}
+	}
+
+	keys := make([]string, 0, len(spec))
+	for key := range spec {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if len(keys) > 0 {
+		return keys[0]
+	}
+	return ""
+}
@@ -0,0 +1,198 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certmanager
+
+import (
+	"log"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+)
+
+// Order is a presentation layer view of cert-manager Order resource, i.e. an ACME order of
+// a certificate.
+type Order struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	// Name of the certificate the order was created for.
+	Certificate string `json:"certificate"`
+
+	DNSNames []string        `json:"dnsNames"`
+	Issuer   IssuerReference `json:"issuer"`
+
+	// State of the order, e.g. pending, valid or invalid, and reason of the state.
+	State  string `json:"state"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// OrderList contains a list of cert-manager orders.
+type OrderList struct {
+	ListMeta api.ListMeta `json:"listMeta"`
+	Orders   []Order      `json:"orders"`
+}
+
+// Authorization is an ACME authorization of a single identifier of an order.
+type Authorization struct {
+	Identifier   string `json:"identifier"`
+	Wildcard     bool   `json:"wildcard"`
+	InitialState string `json:"initialState,omitempty"`
+}
+
+// OrderDetail contains order with its URL and authorizations.
+type OrderDetail struct {
+	Order
+	URL            string          `json:"url"`
+	Authorizations []Authorization `json:"authorizations"`
+}
+
+// rawOrder is a subset of the Order custom resource used by the dashboard.
+type rawOrder struct {
+	ObjectMeta metaV1.ObjectMeta `json:"metadata"`
+	Spec       struct {
+		CommonName string          `json:"commonName"`
+		DNSNames   []string        `json:"dnsNames"`
+		IssuerRef  IssuerReference `json:"issuerRef"`
+	} `json:"spec"`
+	Status struct {
+		URL            string          `json:"url"`
+		State          string          `json:"state"`
+		Reason         string          `json:"reason"`
+		Authorizations []Authorization `json:"authorizations"`
+	} `json:"status"`
+}
+
+type rawOrderList struct {
+	Items []rawOrder `json:"items"`
+}
+
+// GetOrderList returns a list of cert-manager orders in given namespaces. When certificate is not
+// empty only orders created for given certificate are returned. Returns empty list when
+// cert-manager is not installed.
+func GetOrderList(client client.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery, certificate string) (*OrderList, error) {
+	log.Print("Getting list of cert-manager orders")
+
+	orders, err := getOrders(client, nsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	return toOrderList(filterOrdersByCertificate(orders, certificate), dsQuery), nil
+}
+
+// GetOrderDetail returns cert-manager order with given name.
+func GetOrderDetail(client client.Interface, namespace, name string) (*OrderDetail, error) {
+	log.Printf("Getting details of %s cert-manager order in %s namespace", name, namespace)
+
+	groupVersion, err := common.GetInstalledGroupVersion(client, "orders", name, CertManagerGroup,
+		LegacyCertManagerGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var order rawOrder
+	if err := common.GetCustomResource(client, groupVersion, namespace, "orders", name,
+		&order); err != nil {
+		return nil, err
+	}
+
+	authorizations := order.Status.Authorizations
+	if authorizations == nil {
+		authorizations = make([]Authorization, 0)
+	}
+
+	return &OrderDetail{
+		Order:          toOrder(order),
+		URL:            order.Status.URL,
+		Authorizations: authorizations,
+	}, nil
+}
+
+func getOrders(client client.Interface, nsQuery *common.NamespaceQuery) ([]rawOrder, error) {
+	groupVersion, found, err := common.GetGroupVersion(client, CertManagerGroup,
+		LegacyCertManagerGroup)
+	if err != nil || !found {
+		return make([]rawOrder, 0), err
+	}
+
+	var list rawOrderList
+	if err := common.GetCustomResource(client, groupVersion, nsQuery.ToRequestParam(), "orders", "",
+		&list); err != nil {
+		return nil, err
+	}
+
+	orders := make([]rawOrder, 0)
+	for _, order := range list.Items {
+		if nsQuery.Matches(order.ObjectMeta.Namespace) {
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+func filterOrdersByCertificate(orders []rawOrder, certificate string) []rawOrder {
+	if certificate == "" {
+		return orders
+	}
+
+	result := make([]rawOrder, 0)
+	for _, order := range orders {
+		if GetCertificateName(order.ObjectMeta) == certificate {
+			result = append(result, order)
+		}
+	}
+	return result
+}
+
+func toOrderList(orders []rawOrder, dsQuery *dataselect.DataSelectQuery) *OrderList {
+	orderList := &OrderList{
+		Orders:   make([]Order, 0),
+		ListMeta: api.ListMeta{TotalItems: len(orders)},
+	}
+
+	orderCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toOrderCells(orders), dsQuery)
+	orders = fromOrderCells(orderCells)
+	orderList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, orderList.ListMeta.TotalItems)
+
+	for _, order := range orders {
+		orderList.Orders = append(orderList.Orders, toOrder(order))
+	}
+
+	return orderList
+}
+
+func toOrder(order rawOrder) Order {
+	dnsNames := order.Spec.DNSNames
+	if dnsNames == nil {
+		dnsNames = make([]string, 0)
+	}
+	if order.Spec.CommonName != "" && !containsString(dnsNames, order.Spec.CommonName) {
+		dnsNames = append([]string{order.Spec.CommonName}, dnsNames...)
+	}
+
+	return Order{
+		ObjectMeta:  api.NewObjectMeta(order.ObjectMeta),
+		TypeMeta:    api.NewTypeMeta(api.ResourceKindOrder),
+		Certificate: GetCertificateName(order.ObjectMeta),
+		DNSNames:    dnsNames,
+		Issuer:      order.Spec.IssuerRef,
+		State:       order.Status.State,
+		Reason:      order.Status.Reason,
+	}
+}
@@ -17,7 +17,7 @@ package cluster
 import (
 	"log"
 
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/namespace"
@@ -39,7 +39,7 @@ type Cluster struct {
 
 // GetCluster returns a list of all cluster resources in the cluster.
 func GetCluster(client *kubernetes.Clientset, dsQuery *dataselect.DataSelectQuery,
-	heapsterClient *heapster.HeapsterClient) (*Cluster, error) {
+	heapsterClient *metricapi.MetricClient) (*Cluster, error) {
 	log.Print("Getting cluster category")
 	channels := &common.ResourceChannels{
 		NamespaceList:        common.GetNamespaceListChannel(client, 1),
@@ -56,7 +56,7 @@ func GetCluster(client *kubernetes.Clientset, dsQuery *dataselect.DataSelectQuer
 // GetClusterFromChannels returns a list of all cluster in the cluster, from the
 // channel sources.
 func GetClusterFromChannels(client *kubernetes.Clientset, channels *common.ResourceChannels,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (
+	dsQuery *dataselect.DataSelectQuery, heapsterClient *metricapi.MetricClient) (
 	*Cluster, error) {
 
 	nsChan := make(chan *namespace.NamespaceList)
@@ -0,0 +1,318 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterapi
+
+import (
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+// ClusterAPIGroup is the API group of Cluster API custom resources.
+const ClusterAPIGroup = "cluster.x-k8s.io"
+
+// Labels Cluster API puts on machines and machine sets it creates.
+const (
+	clusterNameLabel       = "cluster.x-k8s.io/cluster-name"
+	machineSetNameLabel    = "cluster.x-k8s.io/set-name"
+	machineDeploymentLabel = "cluster.x-k8s.io/deployment-name"
+)
+
+// getNodeReadiness returns status of the Ready condition of all nodes in the cluster by node name.
+func getNodeReadiness(client client.Interface) (map[string]v1.ConditionStatus, error) {
+	nodes, err := client.CoreV1().Nodes().List(metaV1.ListOptions{
+		LabelSelector: labels.Everything().String(),
+		FieldSelector: fields.Everything().String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	readiness := make(map[string]v1.ConditionStatus)
+	for _, node := range nodes.Items {
+		readiness[node.Name] = v1.ConditionUnknown
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == v1.NodeReady {
+				readiness[node.Name] = condition.Status
+			}
+		}
+	}
+	return readiness, nil
+}
+
+// Condition is a status condition of Cluster API resource.
+type Condition struct {
+	Type               string             `json:"type"`
+	Status             v1.ConditionStatus `json:"status"`
+	Severity           string             `json:"severity,omitempty"`
+	Reason             string             `json:"reason,omitempty"`
+	Message            string             `json:"message,omitempty"`
+	LastTransitionTime *metaV1.Time       `json:"lastTransitionTime,omitempty"`
+}
+
+// getReadyCondition returns status of the Ready condition.
+func getReadyCondition(conditions []Condition) v1.ConditionStatus {
+	for _, condition := range conditions {
+		if condition.Type == "Ready" {
+			return condition.Status
+		}
+	}
+	return v1.ConditionUnknown
+}
+
+func toConditions(conditions []Condition) []Condition {
+	if conditions == nil {
+		return make([]Condition, 0)
+	}
+	return conditions
+}
+
+// objectReference is a reference to the infrastructure or bootstrap provider resource.
+type objectReference struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+func (ref *objectReference) String() string {
+	if ref == nil {
+		return ""
+	}
+	if ref.Kind == "" {
+		return ref.Name
+	}
+	return ref.Kind + "/" + ref.Name
+}
+
+// rawMachineTemplate is a subset of the machine template of machine sets and machine deployments.
+type rawMachineTemplate struct {
+	Spec struct {
+		Version           *string          `json:"version"`
+		InfrastructureRef *objectReference `json:"infrastructureRef"`
+	} `json:"spec"`
+}
+
+// rawMachine is a subset of the Machine custom resource used by the dashboard.
+type rawMachine struct {
+	ObjectMeta metaV1.ObjectMeta `json:"metadata"`
+	Spec       struct {
+		ClusterName       string           `json:"clusterName"`
+		Version           *string          `json:"version"`
+		ProviderID        *string          `json:"providerID"`
+		InfrastructureRef *objectReference `json:"infrastructureRef"`
+	} `json:"spec"`
+	Status struct {
+		NodeRef        *objectReference `json:"nodeRef"`
+		Phase          string           `json:"phase"`
+		FailureReason  *string          `json:"failureReason"`
+		FailureMessage *string          `json:"failureMessage"`
+		Conditions     []Condition      `json:"conditions"`
+	} `json:"status"`
+}
+
+type rawMachineList struct {
+	Items []rawMachine `json:"items"`
+}
+
+// rawMachineSet is a subset of the MachineSet custom resource used by the dashboard.
+type rawMachineSet struct {
+	ObjectMeta metaV1.ObjectMeta `json:"metadata"`
+	Spec       struct {
+		ClusterName string             `json:"clusterName"`
+		Replicas    *int32             `json:"replicas"`
+		Template    rawMachineTemplate `json:"template"`
+	} `json:"spec"`
+	Status rawReplicaStatus `json:"status"`
+}
+
+type rawMachineSetList struct {
+	Items []rawMachineSet `json:"items"`
+}
+
+// rawMachineDeployment is a subset of the MachineDeployment custom resource used by the dashboard.
+type rawMachineDeployment struct {
+	ObjectMeta metaV1.ObjectMeta `json:"metadata"`
+	Spec       struct {
+		ClusterName string             `json:"clusterName"`
+		Replicas    *int32             `json:"replicas"`
+		Paused      bool               `json:"paused"`
+		Template    rawMachineTemplate `json:"template"`
+		Strategy    struct {
+			Type string `json:"type"`
+		} `json:"strategy"`
+	} `json:"spec"`
+	Status struct {
+		rawReplicaStatus
+		Phase string `json:"phase"`
+	} `json:"status"`
+}
+
+type rawMachineDeploymentList struct {
+	Items []rawMachineDeployment `json:"items"`
+}
+
+// rawReplicaStatus is the replica status shared by machine sets and machine deployments.
+type rawReplicaStatus struct {
+	Replicas            int32       `json:"replicas"`
+	UpdatedReplicas     int32       `json:"updatedReplicas"`
+	ReadyReplicas       int32       `json:"readyReplicas"`
+	AvailableReplicas   int32       `json:"availableReplicas"`
+	UnavailableReplicas int32       `json:"unavailableReplicas"`
+	Conditions          []Condition `json:"conditions"`
+}
+
+// MachineReplicas describes desired and observed machines of a machine set or machine deployment.
+type MachineReplicas struct {
+	// Desired number of machines, nil when not set.
+	Desired *int32 `json:"desired"`
+
+	Current     int32 `json:"current"`
+	Updated     int32 `json:"updated"`
+	Ready       int32 `json:"ready"`
+	Available   int32 `json:"available"`
+	Unavailable int32 `json:"unavailable"`
+}
+
+func toMachineReplicas(desired *int32, status rawReplicaStatus) MachineReplicas {
+	return MachineReplicas{
+		Desired:     desired,
+		Current:     status.Replicas,
+		Updated:     status.UpdatedReplicas,
+		Ready:       status.ReadyReplicas,
+		Available:   status.AvailableReplicas,
+		Unavailable: status.UnavailableReplicas,
+	}
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// The code below allows to perform complex data section on []rawMachine.
+
+type MachineCell rawMachine
+
+func (self MachineCell) GetProperty(name dataselect.PropertyName) dataselect.ComparableValue {
+	switch name {
+	case dataselect.NameProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Name)
+	case dataselect.CreationTimestampProperty:
+		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
+	case dataselect.NamespaceProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.UIDProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.UID)
+	case dataselect.StatusProperty:
+		return dataselect.StdComparableString(self.Status.Phase)
+	default:
+		// if name is not supported then just return a constant dummy value, sort will have no effect.
+		return nil
+	}
+}
+
+func toMachineCells(std []rawMachine) []dataselect.DataCell {
+	cells := make([]dataselect.DataCell, len(std))
+	for i := range std {
+		cells[i] = MachineCell(std[i])
+	}
+	return cells
+}
+
+func fromMachineCells(cells []dataselect.DataCell) []rawMachine {
+	std := make([]rawMachine, len(cells))
+	for i := range std {
+		std[i] = rawMachine(cells[i].(MachineCell))
+	}
+	return std
+}
+
+// The code below allows to perform complex data section on []rawMachineSet.
+
+type MachineSetCell rawMachineSet
+
+func (self MachineSetCell) GetProperty(name dataselect.PropertyName) dataselect.ComparableValue {
+	switch name {
+	case dataselect.NameProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Name)
+	case dataselect.CreationTimestampProperty:
+		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
+	case dataselect.NamespaceProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.UIDProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.UID)
+	default:
+		// if name is not supported then just return a constant dummy value, sort will have no effect.
+		return nil
+	}
+}
+
+func toMachineSetCells(std []rawMachineSet) []dataselect.DataCell {
+	cells := make([]dataselect.DataCell, len(std))
+	for i := range std {
+		cells[i] = MachineSetCell(std[i])
+	}
+	return cells
+}
+
+func fromMachineSetCells(cells []dataselect.DataCell) []rawMachineSet {
+	std := make([]rawMachineSet, len(cells))
+	for i := range std {
+		std[i] = rawMachineSet(cells[i].(MachineSetCell))
+	}
+	return std
+}
+
+// The code below allows to perform complex data section on []rawMachineDeployment.
+
+type MachineDeploymentCell rawMachineDeployment
+
+func (self MachineDeploymentCell) GetProperty(name dataselect.PropertyName) dataselect.ComparableValue {
+	switch name {
+	case dataselect.NameProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Name)
+	case dataselect.CreationTimestampProperty:
+		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
+	case dataselect.NamespaceProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.UIDProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.UID)
+	case dataselect.StatusProperty:
+		return dataselect.StdComparableString(self.Status.Phase)
+	default:
+		// if name is not supported then just return a constant dummy value, sort will have no effect.
+		return nil
+	}
+}
+
+func toMachineDeploymentCells(std []rawMachineDeployment) []dataselect.DataCell {
+	cells := make([]dataselect.DataCell, len(std))
+	for i := range std {
+		cells[i] = MachineDeploymentCell(std[i])
+	}
+	return cells
+}
+
+func fromMachineDeploymentCells(cells []dataselect.DataCell) []rawMachineDeployment {
+	std := make([]rawMachineDeployment, len(cells))
+	for i := range std {
+		std[i] = rawMachineDeployment(cells[i].(MachineDeploymentCell))
+	}
+	return std
+}
@@ -0,0 +1,188 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/rest"
+)
+
+func TestGetMachines(t *testing.T) {
+	raw := `{"items": [
+		{
+			"metadata": {"name": "workers-abc-1", "namespace": "capi", "labels": {
+				"cluster.x-k8s.io/cluster-name": "prod",
+				"cluster.x-k8s.io/set-name": "workers-abc",
+				"cluster.x-k8s.io/deployment-name": "workers"
+			}},
+			"spec": {"clusterName": "prod", "version": "v1.27.3", "providerID": "aws:///i-1",
+				"infrastructureRef": {"kind": "AWSMachine", "name": "workers-abc-1"}},
+			"status": {"phase": "Running", "nodeRef": {"kind": "Node", "name": "ip-10-0-0-1"},
+				"conditions": [{"type": "Ready", "status": "True"}]}
+		},
+		{
+			"metadata": {"name": "control-plane-1", "namespace": "capi", "labels": {
+				"cluster.x-k8s.io/cluster-name": "prod"
+			}},
+			"status": {"phase": "Failed", "failureReason": "CreateError",
+				"failureMessage": "instance quota exceeded"}
+		}
+	]}`
+
+	var list rawMachineList
+	if err := json.Unmarshal([]byte(raw), &list); err != nil {
+		t.Fatalf("json.Unmarshal(%#v) returned error: %s", raw, err)
+	}
+	readiness := map[string]v1.ConditionStatus{"ip-10-0-0-1": v1.ConditionFalse}
+
+	cases := []struct {
+		machineSet string
+		expected   *MachineList
+	}{
+		{
+			"workers-abc",
+			&MachineList{
+				ListMeta: api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
+				Machines: []Machine{{
+					ObjectMeta: api.ObjectMeta{Name: "workers-abc-1", Namespace: "capi",
+						Labels: map[string]string{
+							"cluster.x-k8s.io/cluster-name":    "prod",
+							"cluster.x-k8s.io/set-name":        "workers-abc",
+							"cluster.x-k8s.io/deployment-name": "workers",
+						}},
+					TypeMeta:          api.TypeMeta{Kind: api.ResourceKindMachine},
+					ClusterName:       "prod",
+					MachineSet:        "workers-abc",
+					MachineDeployment: "workers",
+					Phase:             "Running",
+					Version:           "v1.27.3",
+					ProviderID:        "aws:///i-1",
+					InfrastructureRef: "AWSMachine/workers-abc-1",
+					NodeName:          "ip-10-0-0-1",
+					NodeReady:         v1.ConditionFalse,
+					Ready:             v1.ConditionTrue,
+				}},
+			},
+		},
+		{
+			"",
+			&MachineList{
+				ListMeta: api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
+				Machines: []Machine{{
+					ObjectMeta: api.ObjectMeta{Name: "control-plane-1", Namespace: "capi",
+						Labels: map[string]string{"cluster.x-k8s.io/cluster-name": "prod"}},
+					TypeMeta:       api.TypeMeta{Kind: api.ResourceKindMachine},
+					ClusterName:    "prod",
+					Phase:          "Failed",
+					Ready:          v1.ConditionUnknown,
+					FailureReason:  "CreateError",
+					FailureMessage: "instance quota exceeded",
+				}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := toMachineList(filterMachinesByLabel(list.Items, machineSetNameLabel, c.machineSet),
+			readiness, dataselect.NoDataSelect)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("toMachineList(%#v) == \ngot: %#v, \nexpected %#v", c.machineSet, actual, c.expected)
+		}
+	}
+}
+
+func TestToMachineDeploymentList(t *testing.T) {
+	raw := `{"items": [{
+		"metadata": {"name": "workers", "namespace": "capi"},
+		"spec": {
+			"clusterName": "prod",
+			"replicas": 3,
+			"strategy": {"type": "RollingUpdate"},
+			"template": {"spec": {"version": "v1.27.3",
+				"infrastructureRef": {"kind": "AWSMachineTemplate", "name": "workers"}}}
+		},
+		"status": {"phase": "ScalingUp", "replicas": 3, "updatedReplicas": 3, "readyReplicas": 2,
+			"availableReplicas": 2, "unavailableReplicas": 1}
+	}]}`
+
+	var list rawMachineDeploymentList
+	if err := json.Unmarshal([]byte(raw), &list); err != nil {
+		t.Fatalf("json.Unmarshal(%#v) returned error: %s", raw, err)
+	}
+	replicas := int32(3)
+	expected := &MachineDeploymentList{
+		ListMeta: api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
+		MachineDeployments: []MachineDeployment{{
+			ObjectMeta:        api.ObjectMeta{Name: "workers", Namespace: "capi"},
+			TypeMeta:          api.TypeMeta{Kind: api.ResourceKindMachineDeployment},
+			ClusterName:       "prod",
+			Phase:             "ScalingUp",
+			Strategy:          "RollingUpdate",
+			Version:           "v1.27.3",
+			InfrastructureRef: "AWSMachineTemplate/workers",
+			Replicas: MachineReplicas{Desired: &replicas, Current: 3, Updated: 3, Ready: 2,
+				Available: 2, Unavailable: 1},
+		}},
+	}
+
+	actual := toMachineDeploymentList(list.Items, dataselect.NoDataSelect)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toMachineDeploymentList(%#v) == \ngot: %#v, \nexpected %#v", list.Items, actual,
+			expected)
+	}
+}
+
+func TestGetDetailNotInstalled(t *testing.T) {
+	requested := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		if r.URL.Path == "/apis" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"kind": "APIGroupList", "groups": []}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("Cannot create client: %v", err)
+	}
+
+	if _, err := GetMachineDetail(client, "capi", "workers-abc-1"); !k8serrors.IsNotFound(err) {
+		t.Errorf("GetMachineDetail() without Cluster API returns %v, expected not found", err)
+	}
+	if _, err := GetMachineSetDetail(client, "capi", "workers-abc"); !k8serrors.IsNotFound(err) {
+		t.Errorf("GetMachineSetDetail() without Cluster API returns %v, expected not found", err)
+	}
+	if _, err := GetMachineDeploymentDetail(client, "capi", "workers"); !k8serrors.IsNotFound(err) {
+		t.Errorf("GetMachineDeploymentDetail() without Cluster API returns %v, expected not found", err)
+	}
+	for _, path := range requested {
+		if path != "/apis" && path != "/api" {
+			t.Errorf("Get*Detail() without Cluster API requests %s", path)
+		}
+	}
+}
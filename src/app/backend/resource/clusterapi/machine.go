@@ -0,0 +1,198 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterapi
+
+import (
+	"log"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+// Machine is a presentation layer view of Cluster API Machine resource, i.e. a single node
+// managed by Cluster API.
+type Machine struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	ClusterName string `json:"clusterName"`
+
+	// Names of the machine set and machine deployment that own the machine. Empty for machines
+	// created directly.
+	MachineSet        string `json:"machineSet"`
+	MachineDeployment string `json:"machineDeployment"`
+
+	// Lifecycle phase of the machine, e.g. Provisioning, Running or Deleting.
+	Phase string `json:"phase"`
+
+	// Kubernetes version of the machine, e.g. v1.27.3.
+	Version string `json:"version"`
+
+	ProviderID string `json:"providerID"`
+
+	// Infrastructure provider resource of the machine, e.g. AWSMachine/worker-abc12.
+	InfrastructureRef string `json:"infrastructureRef"`
+
+	// Name of the node of the machine. Empty until the node joins the cluster.
+	NodeName string `json:"nodeName"`
+
+	// Status of the Ready condition of the node. Empty when the node is not found in the cluster,
+	// e.g. when the machine belongs to a different workload cluster.
+	NodeReady v1.ConditionStatus `json:"nodeReady"`
+
+	// Status of the Ready condition of the machine.
+	Ready v1.ConditionStatus `json:"ready"`
+
+	FailureReason  string `json:"failureReason,omitempty"`
+	FailureMessage string `json:"failureMessage,omitempty"`
+}
+
+// MachineList contains a list of Cluster API machines.
+type MachineList struct {
+	ListMeta api.ListMeta `json:"listMeta"`
+	Machines []Machine    `json:"machines"`
+}
+
+// MachineDetail contains machine and its conditions.
+type MachineDetail struct {
+	Machine
+	Conditions []Condition `json:"conditions"`
+}
+
+// GetMachineList returns a list of Cluster API machines. Returns empty list when Cluster API is not
+// installed.
+func GetMachineList(client client.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*MachineList, error) {
+	log.Print("Getting list of Cluster API machines")
+
+	machines, err := getMachines(client, nsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	readiness, err := getNodeReadiness(client)
+	if err != nil {
+		return nil, err
+	}
+
+	return toMachineList(machines, readiness, dsQuery), nil
+}
+
+// GetMachineDetail returns Cluster API machine with given name along with its conditions. Returns
+// not found error when Cluster API is not installed.
+func GetMachineDetail(client client.Interface, namespace, name string) (*MachineDetail, error) {
+	log.Printf("Getting details of %s Cluster API machine in %s namespace", name, namespace)
+
+	groupVersion, err := common.GetInstalledGroupVersion(client, "machines", name, ClusterAPIGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var machine rawMachine
+	if err := common.GetCustomResource(client, groupVersion, namespace, "machines", name,
+		&machine); err != nil {
+		return nil, err
+	}
+
+	readiness, err := getNodeReadiness(client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MachineDetail{
+		Machine:    toMachine(machine, readiness),
+		Conditions: toConditions(machine.Status.Conditions),
+	}, nil
+}
+
+func getMachines(client client.Interface, nsQuery *common.NamespaceQuery) ([]rawMachine, error) {
+	groupVersion, ok, err := common.GetGroupVersion(client, ClusterAPIGroup)
+	if err != nil || !ok {
+		return make([]rawMachine, 0), err
+	}
+
+	var list rawMachineList
+	if err := common.GetCustomResource(client, groupVersion, nsQuery.ToRequestParam(), "machines",
+		"", &list); err != nil {
+		return nil, err
+	}
+
+	machines := make([]rawMachine, 0)
+	for _, machine := range list.Items {
+		if nsQuery.Matches(machine.ObjectMeta.Namespace) {
+			machines = append(machines, machine)
+		}
+	}
+	return machines, nil
+}
+
+// filterMachinesByLabel returns machines with given value of given Cluster API label, e.g. machines
+// of a machine set.
+func filterMachinesByLabel(machines []rawMachine, label, value string) []rawMachine {
+	result := make([]rawMachine, 0)
+	for _, machine := range machines {
+		if machine.ObjectMeta.Labels[label] == value {
+			result = append(result, machine)
+		}
+	}
+	return result
+}
+
+func toMachineList(machines []rawMachine, readiness map[string]v1.ConditionStatus,
+	dsQuery *dataselect.DataSelectQuery) *MachineList {
+	machineList := &MachineList{
+		Machines: make([]Machine, 0),
+		ListMeta: api.ListMeta{TotalItems: len(machines)},
+	}
+
+	machineCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toMachineCells(machines), dsQuery)
+	machines = fromMachineCells(machineCells)
+	machineList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, machineList.ListMeta.TotalItems)
+
+	for _, machine := range machines {
+		machineList.Machines = append(machineList.Machines, toMachine(machine, readiness))
+	}
+
+	return machineList
+}
+
+func toMachine(machine rawMachine, readiness map[string]v1.ConditionStatus) Machine {
+	result := Machine{
+		ObjectMeta:        api.NewObjectMeta(machine.ObjectMeta),
+		TypeMeta:          api.NewTypeMeta(api.ResourceKindMachine),
+		ClusterName:       machine.Spec.ClusterName,
+		MachineSet:        machine.ObjectMeta.Labels[machineSetNameLabel],
+		MachineDeployment: machine.ObjectMeta.Labels[machineDeploymentLabel],
+		Phase:             machine.Status.Phase,
+		Version:           stringValue(machine.Spec.Version),
+		ProviderID:        stringValue(machine.Spec.ProviderID),
+		InfrastructureRef: machine.Spec.InfrastructureRef.String(),
+		Ready:             getReadyCondition(machine.Status.Conditions),
+		FailureReason:     stringValue(machine.Status.FailureReason),
+		FailureMessage:    stringValue(machine.Status.FailureMessage),
+	}
+	if result.ClusterName == "" {
+		result.ClusterName = machine.ObjectMeta.Labels[clusterNameLabel]
+	}
+
+	if machine.Status.NodeRef != nil {
+		result.NodeName = machine.Status.NodeRef.Name
+		result.NodeReady = readiness[result.NodeName]
+	}
+	return result
+}
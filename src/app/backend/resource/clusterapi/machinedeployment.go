@@ -0,0 +1,174 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterapi
+
+import (
+	"log"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	client "k8s.io/client-go/kubernetes"
+)
+
+// MachineDeployment is a presentation layer view of Cluster API MachineDeployment resource.
+type MachineDeployment struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	ClusterName string `json:"clusterName"`
+
+	// Rollout phase of the machine deployment, e.g. ScalingUp or Running.
+	Phase string `json:"phase"`
+
+	// Rollout strategy of the machine deployment, e.g. RollingUpdate.
+	Strategy string `json:"strategy"`
+
+	// True when rollouts of the machine deployment are paused.
+	Paused bool `json:"paused"`
+
+	// Kubernetes version of machines created from the machine deployment.
+	Version string `json:"version"`
+
+	// Infrastructure provider template of machines, e.g. AWSMachineTemplate/worker.
+	InfrastructureRef string `json:"infrastructureRef"`
+
+	Replicas MachineReplicas `json:"replicas"`
+}
+
+// MachineDeploymentList contains a list of Cluster API machine deployments.
+type MachineDeploymentList struct {
+	ListMeta           api.ListMeta        `json:"listMeta"`
+	MachineDeployments []MachineDeployment `json:"machineDeployments"`
+}
+
+// MachineDeploymentDetail contains machine deployment along with its machine sets and machines.
+type MachineDeploymentDetail struct {
+	MachineDeployment
+	Conditions     []Condition    `json:"conditions"`
+	MachineSetList MachineSetList `json:"machineSetList"`
+	MachineList    MachineList    `json:"machineList"`
+}
+
+// GetMachineDeploymentList returns a list of Cluster API machine deployments. Returns empty list
+// when Cluster API is not installed.
+func GetMachineDeploymentList(client client.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*MachineDeploymentList, error) {
+	log.Print("Getting list of Cluster API machine deployments")
+
+	machineDeploymentList := &MachineDeploymentList{MachineDeployments: make([]MachineDeployment, 0)}
+
+	groupVersion, ok, err := common.GetGroupVersion(client, ClusterAPIGroup)
+	if err != nil || !ok {
+		return machineDeploymentList, err
+	}
+
+	var list rawMachineDeploymentList
+	if err := common.GetCustomResource(client, groupVersion, nsQuery.ToRequestParam(),
+		"machinedeployments", "",
+		&list); err != nil {
+		return nil, err
+	}
+
+	machineDeployments := make([]rawMachineDeployment, 0)
+	for _, machineDeployment := range list.Items {
+		if nsQuery.Matches(machineDeployment.ObjectMeta.Namespace) {
+			machineDeployments = append(machineDeployments, machineDeployment)
+		}
+	}
+
+	return toMachineDeploymentList(machineDeployments, dsQuery), nil
+}
+
+// GetMachineDeploymentDetail returns Cluster API machine deployment with given name along with its
+// machine sets and machines. Returns not found error when Cluster API is not installed.
+func GetMachineDeploymentDetail(client client.Interface, namespace, name string) (
+	*MachineDeploymentDetail, error) {
+	log.Printf("Getting details of %s Cluster API machine deployment in %s namespace", name,
+		namespace)
+
+	groupVersion, err := common.GetInstalledGroupVersion(client, "machinedeployments", name,
+		ClusterAPIGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var machineDeployment rawMachineDeployment
+	if err := common.GetCustomResource(client, groupVersion, namespace, "machinedeployments", name,
+		&machineDeployment); err != nil {
+		return nil, err
+	}
+
+	nsQuery := common.NewSameNamespaceQuery(namespace)
+	machineSets, err := getMachineSets(client, nsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	machines, err := getMachines(client, nsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	readiness, err := getNodeReadiness(client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MachineDeploymentDetail{
+		MachineDeployment: toMachineDeployment(machineDeployment),
+		Conditions:        toConditions(machineDeployment.Status.Conditions),
+		MachineSetList: *toMachineSetList(filterMachineSetsByDeployment(machineSets, name),
+			dataselect.DefaultDataSelect),
+		MachineList: *toMachineList(filterMachinesByLabel(machines, machineDeploymentLabel, name),
+			readiness, dataselect.DefaultDataSelect),
+	}, nil
+}
+
+func toMachineDeploymentList(machineDeployments []rawMachineDeployment,
+	dsQuery *dataselect.DataSelectQuery) *MachineDeploymentList {
+	machineDeploymentList := &MachineDeploymentList{
+		MachineDeployments: make([]MachineDeployment, 0),
+		ListMeta:           api.ListMeta{TotalItems: len(machineDeployments)},
+	}
+
+	machineDeploymentCells, filteredTotal := dataselect.GenericDataSelectWithFilter(
+		toMachineDeploymentCells(machineDeployments), dsQuery)
+	machineDeployments = fromMachineDeploymentCells(machineDeploymentCells)
+	machineDeploymentList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal,
+		machineDeploymentList.ListMeta.TotalItems)
+
+	for _, machineDeployment := range machineDeployments {
+		machineDeploymentList.MachineDeployments = append(machineDeploymentList.MachineDeployments,
+			toMachineDeployment(machineDeployment))
+	}
+
+	return machineDeploymentList
+}
+
+func toMachineDeployment(machineDeployment rawMachineDeployment) MachineDeployment {
+	return MachineDeployment{
+		ObjectMeta:        api.NewObjectMeta(machineDeployment.ObjectMeta),
+		TypeMeta:          api.NewTypeMeta(api.ResourceKindMachineDeployment),
+		ClusterName:       machineDeployment.Spec.ClusterName,
+		Phase:             machineDeployment.Status.Phase,
+		Strategy:          machineDeployment.Spec.Strategy.Type,
+		Paused:            machineDeployment.Spec.Paused,
+		Version:           stringValue(machineDeployment.Spec.Template.Spec.Version),
+		InfrastructureRef: machineDeployment.Spec.Template.Spec.InfrastructureRef.String(),
+		Replicas: toMachineReplicas(machineDeployment.Spec.Replicas,
+			machineDeployment.Status.rawReplicaStatus),
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterapi
+
+import (
+	"log"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	client "k8s.io/client-go/kubernetes"
+)
+
+// MachineSet is a presentation layer view of Cluster API MachineSet resource.
+type MachineSet struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	ClusterName string `json:"clusterName"`
+
+	// Name of the machine deployment that owns the machine set. Empty for machine sets created
+	// directly.
+	MachineDeployment string `json:"machineDeployment"`
+
+	// Kubernetes version of machines created from the machine set.
+	Version string `json:"version"`
+
+	// Infrastructure provider template of machines, e.g. AWSMachineTemplate/worker.
+	InfrastructureRef string `json:"infrastructureRef"`
+
+	Replicas MachineReplicas `json:"replicas"`
+}
+
+// MachineSetList contains a list of Cluster API machine sets.
+type MachineSetList struct {
+	ListMeta    api.ListMeta `json:"listMeta"`
+	MachineSets []MachineSet `json:"machineSets"`
+}
+
+// MachineSetDetail contains machine set and machines created from it.
+type MachineSetDetail struct {
+	MachineSet
+	Conditions  []Condition `json:"conditions"`
+	MachineList MachineList `json:"machineList"`
+}
+
+// GetMachineSetList returns a list of Cluster API machine sets. Returns empty list when Cluster API
+// is not installed.
+func GetMachineSetList(client client.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*MachineSetList, error) {
+	log.Print("Getting list of Cluster API machine sets")
+
+	machineSets, err := getMachineSets(client, nsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	return toMachineSetList(machineSets, dsQuery), nil
+}
+
+// GetMachineSetDetail returns Cluster API machine set with given name along with its machines.
+// Returns not found error when Cluster API is not installed.
+func GetMachineSetDetail(client client.Interface, namespace, name string) (*MachineSetDetail, error) {
+	log.Printf("Getting details of %s Cluster API machine set in %s namespace", name, namespace)
+
+	groupVersion, err := common.GetInstalledGroupVersion(client, "machinesets", name,
+		ClusterAPIGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var machineSet rawMachineSet
+	if err := common.GetCustomResource(client, groupVersion, namespace, "machinesets", name,
+		&machineSet); err != nil {
+		return nil, err
+	}
+
+	machines, err := getMachines(client, common.NewSameNamespaceQuery(namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	readiness, err := getNodeReadiness(client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MachineSetDetail{
+		MachineSet: toMachineSet(machineSet),
+		Conditions: toConditions(machineSet.Status.Conditions),
+		MachineList: *toMachineList(filterMachinesByLabel(machines, machineSetNameLabel, name),
+			readiness, dataselect.DefaultDataSelect),
+	}, nil
+}
+
+func getMachineSets(client client.Interface, nsQuery *common.NamespaceQuery) ([]rawMachineSet,
+	error) {
+	groupVersion, ok, err := common.GetGroupVersion(client, ClusterAPIGroup)
+	if err != nil || !ok {
+		return make([]rawMachineSet, 0), err
+	}
+
+	var list rawMachineSetList
+	if err := common.GetCustomResource(client, groupVersion, nsQuery.ToRequestParam(),
+		"machinesets", "", &list); err != nil {
+		return nil, err
+	}
+
+	machineSets := make([]rawMachineSet, 0)
+	for _, machineSet := range list.Items {
+		if nsQuery.Matches(machineSet.ObjectMeta.Namespace) {
+			machineSets = append(machineSets, machineSet)
+		}
+	}
+	return machineSets, nil
+}
+
+func filterMachineSetsByDeployment(machineSets []rawMachineSet, deployment string) []rawMachineSet {
+	result := make([]rawMachineSet, 0)
+	for _, machineSet := range machineSets {
+		if machineSet.ObjectMeta.Labels[machineDeploymentLabel] == deployment {
+			result = append(result, machineSet)
+		}
+	}
+	return result
+}
+
+func toMachineSetList(machineSets []rawMachineSet,
+	dsQuery *dataselect.DataSelectQuery) *MachineSetList {
+	machineSetList := &MachineSetList{
+		MachineSets: make([]MachineSet, 0),
+		ListMeta:    api.ListMeta{TotalItems: len(machineSets)},
+	}
+
+	machineSetCells, filteredTotal := dataselect.GenericDataSelectWithFilter(
+		toMachineSetCells(machineSets), dsQuery)
+	machineSets = fromMachineSetCells(machineSetCells)
+	machineSetList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal,
+		machineSetList.ListMeta.TotalItems)
+
+	for _, machineSet := range machineSets {
+		machineSetList.MachineSets = append(machineSetList.MachineSets, toMachineSet(machineSet))
+	}
+
+	return machineSetList
+}
+
+func toMachineSet(machineSet rawMachineSet) MachineSet {
+	return MachineSet{
+		ObjectMeta:        api.NewObjectMeta(machineSet.ObjectMeta),
+		TypeMeta:          api.NewTypeMeta(api.ResourceKindMachineSet),
+		ClusterName:       machineSet.Spec.ClusterName,
+		MachineDeployment: machineSet.ObjectMeta.Labels[machineDeploymentLabel],
+		Version:           stringValue(machineSet.Spec.Template.Spec.Version),
+		InfrastructureRef: machineSet.Spec.Template.Spec.InfrastructureRef.String(),
+		Replicas:          toMachineReplicas(machineSet.Spec.Replicas, machineSet.Status),
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterautoscaler
+
+import (
+	"bufio"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// StatusCondition is a single condition reported by cluster autoscaler, e.g. health or scale-up
+// state of the whole cluster or of a node group.
+type StatusCondition struct {
+	// Status of the condition, e.g. Healthy, NoActivity, InProgress or CandidatesPresent.
+	Status string `json:"status"`
+
+	// Counters reported together with the status, e.g. ready=3 or candidates=1.
+	Details map[string]int `json:"details"`
+
+	// Time at which the condition was last probed by cluster autoscaler.
+	LastProbeTime string `json:"lastProbeTime"`
+
+	// Time at which the condition changed its status last time.
+	LastTransitionTime string `json:"lastTransitionTime"`
+}
+
+// ClusterWideStatus contains conditions of the whole cluster.
+type ClusterWideStatus struct {
+	Health    StatusCondition `json:"health"`
+	ScaleUp   StatusCondition `json:"scaleUp"`
+	ScaleDown StatusCondition `json:"scaleDown"`
+}
+
+// NodeGroupStatus contains conditions and size limits of a single node group.
+type NodeGroupStatus struct {
+	Name      string          `json:"name"`
+	Health    StatusCondition `json:"health"`
+	ScaleUp   StatusCondition `json:"scaleUp"`
+	ScaleDown StatusCondition `json:"scaleDown"`
+
+	// Minimum and maximum number of nodes in the node group.
+	MinSize int `json:"minSize"`
+	MaxSize int `json:"maxSize"`
+
+	// Number of nodes requested from the cloud provider.
+	CloudProviderTarget int `json:"cloudProviderTarget"`
+}
+
+// detailRegexp matches key=value counters, e.g. "ready=3" or "maxSize=5".
+var detailRegexp = regexp.MustCompile(`(\w+)=(\d+)`)
+
+// statusTimeRegexp matches the first line of the status, e.g.
+// "Cluster-autoscaler status at 2017-05-05 10:03:03.260561511 +0000 UTC:".
+var statusTimeRegexp = regexp.MustCompile(`^Cluster-autoscaler status at (.*):$`)
+
+// parseStatus parses human readable status written by cluster autoscaler into its status config
+// map. Returns time of the status, cluster wide status and list of node group statuses. Unknown
+// lines are ignored, so that newer versions of the format are still partially understood.
+func parseStatus(status string) (string, ClusterWideStatus, []NodeGroupStatus) {
+	updateTime := ""
+	clusterWide := ClusterWideStatus{}
+	nodeGroups := make([]NodeGroupStatus, 0)
+
+	inNodeGroups := false
+	var current *StatusCondition
+
+	scanner := bufio.NewScanner(strings.NewReader(status))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if matches := statusTimeRegexp.FindStringSubmatch(line); matches != nil {
+			updateTime = matches[1]
+			continue
+		}
+
+		switch line {
+		case "Cluster-wide:":
+			inNodeGroups = false
+			current = nil
+			continue
+		case "NodeGroups:":
+			inNodeGroups = true
+			current = nil
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key, value := parts[0], strings.TrimSpace(parts[1])
+
+		if key == "LastProbeTime" || key == "LastTransitionTime" {
+			if current == nil {
+				continue
+			}
+			if key == "LastProbeTime" {
+				current.LastProbeTime = value
+			} else {
+				current.LastTransitionTime = value
+			}
+			continue
+		}
+
+		if inNodeGroups && key == "Name" {
+			nodeGroups = append(nodeGroups, NodeGroupStatus{Name: value})
+			current = nil
+			continue
+		}
+
+		var group *NodeGroupStatus
+		if inNodeGroups {
+			if len(nodeGroups) == 0 {
+				continue
+			}
+			group = &nodeGroups[len(nodeGroups)-1]
+		}
+
+		condition := parseCondition(value)
+		switch key {
+		case "Health":
+			if group != nil {
+				group.Health = condition
+				group.MinSize = condition.Details["minSize"]
+				group.MaxSize = condition.Details["maxSize"]
+				group.CloudProviderTarget = condition.Details["cloudProviderTarget"]
+				current = &group.Health
+			} else {
+				clusterWide.Health = condition
+				current = &clusterWide.Health
+			}
+		case "ScaleUp":
+			if group != nil {
+				group.ScaleUp = condition
+				current = &group.ScaleUp
+			} else {
+				clusterWide.ScaleUp = condition
+				current = &clusterWide.ScaleUp
+			}
+		case "ScaleDown":
+			if group != nil {
+				group.ScaleDown = condition
+				current = &group.ScaleDown
+			} else {
+				clusterWide.ScaleDown = condition
+				current = &clusterWide.ScaleDown
+			}
+		default:
+			current = nil
+		}
+	}
+
+	return updateTime, clusterWide, nodeGroups
+}
+
+// parseCondition parses condition value, e.g. "Healthy (ready=3 unready=0 registered=3)".
+func parseCondition(value string) StatusCondition {
+	condition := StatusCondition{Details: make(map[string]int)}
+
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return condition
+	}
+	condition.Status = fields[0]
+
+	for _, matches := range detailRegexp.FindAllStringSubmatch(value, -1) {
+		count, err := strconv.Atoi(matches[2])
+		if err != nil {
+			continue
+		}
+		condition.Details[matches[1]] = count
+	}
+
+	return condition
+}
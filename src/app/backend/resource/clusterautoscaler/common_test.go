@@ -0,0 +1,109 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterautoscaler
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testStatus = `Cluster-autoscaler status at 2017-05-05 10:03:03 +0000 UTC:
+Cluster-wide:
+  Health:      Healthy (ready=3 unready=0 notStarted=0 longNotStarted=0 registered=3)
+               LastProbeTime:      2017-05-05 10:03:03 +0000 UTC
+               LastTransitionTime: 2017-05-05 09:56:38 +0000 UTC
+  ScaleUp:     InProgress (ready=3 registered=3)
+               LastProbeTime:      2017-05-05 10:03:03 +0000 UTC
+               LastTransitionTime: 2017-05-05 10:01:00 +0000 UTC
+  ScaleDown:   NoCandidates (candidates=0)
+               LastProbeTime:      2017-05-05 10:03:03 +0000 UTC
+               LastTransitionTime: 2017-05-05 09:56:38 +0000 UTC
+
+NodeGroups:
+  Name:        default-pool
+  Health:      Healthy (ready=3 unready=0 notStarted=0 longNotStarted=0 registered=3 cloudProviderTarget=4 (minSize=1, maxSize=5))
+               LastProbeTime:      2017-05-05 10:03:03 +0000 UTC
+               LastTransitionTime: 2017-05-05 09:56:38 +0000 UTC
+  ScaleUp:     InProgress (ready=3 cloudProviderTarget=4)
+               LastProbeTime:      2017-05-05 10:03:03 +0000 UTC
+               LastTransitionTime: 2017-05-05 10:01:00 +0000 UTC
+  ScaleDown:   NoCandidates (candidates=0)
+               LastProbeTime:      2017-05-05 10:03:03 +0000 UTC
+               LastTransitionTime: 2017-05-05 09:56:38 +0000 UTC
+`
+
+func TestParseStatus(t *testing.T) {
+	probe := "2017-05-05 10:03:03 +0000 UTC"
+	start := "2017-05-05 09:56:38 +0000 UTC"
+	scaleUp := "2017-05-05 10:01:00 +0000 UTC"
+
+	cases := []struct {
+		status              string
+		expectedTime        string
+		expectedClusterWide ClusterWideStatus
+		expectedNodeGroups  []NodeGroupStatus
+	}{
+		{
+			"", "", ClusterWideStatus{}, []NodeGroupStatus{},
+		},
+		{
+			testStatus,
+			"2017-05-05 10:03:03 +0000 UTC",
+			ClusterWideStatus{
+				Health: StatusCondition{Status: "Healthy", Details: map[string]int{
+					"ready": 3, "unready": 0, "notStarted": 0, "longNotStarted": 0, "registered": 3},
+					LastProbeTime: probe, LastTransitionTime: start},
+				ScaleUp: StatusCondition{Status: "InProgress",
+					Details:       map[string]int{"ready": 3, "registered": 3},
+					LastProbeTime: probe, LastTransitionTime: scaleUp},
+				ScaleDown: StatusCondition{Status: "NoCandidates",
+					Details:       map[string]int{"candidates": 0},
+					LastProbeTime: probe, LastTransitionTime: start},
+			},
+			[]NodeGroupStatus{{
+				Name: "default-pool",
+				Health: StatusCondition{Status: "Healthy", Details: map[string]int{
+					"ready": 3, "unready": 0, "notStarted": 0, "longNotStarted": 0, "registered": 3,
+					"cloudProviderTarget": 4, "minSize": 1, "maxSize": 5},
+					LastProbeTime: probe, LastTransitionTime: start},
+				ScaleUp: StatusCondition{Status: "InProgress",
+					Details:       map[string]int{"ready": 3, "cloudProviderTarget": 4},
+					LastProbeTime: probe, LastTransitionTime: scaleUp},
+				ScaleDown: StatusCondition{Status: "NoCandidates",
+					Details:       map[string]int{"candidates": 0},
+					LastProbeTime: probe, LastTransitionTime: start},
+				MinSize:             1,
+				MaxSize:             5,
+				CloudProviderTarget: 4,
+			}},
+		},
+	}
+
+	for _, c := range cases {
+		actualTime, actualClusterWide, actualNodeGroups := parseStatus(c.status)
+		if actualTime != c.expectedTime {
+			t.Errorf("parseStatus(%#v) time == \ngot: %#v, \nexpected %#v",
+				c.status, actualTime, c.expectedTime)
+		}
+		if !reflect.DeepEqual(actualClusterWide, c.expectedClusterWide) {
+			t.Errorf("parseStatus(%#v) cluster wide == \ngot: %#v, \nexpected %#v",
+				c.status, actualClusterWide, c.expectedClusterWide)
+		}
+		if !reflect.DeepEqual(actualNodeGroups, c.expectedNodeGroups) {
+			t.Errorf("parseStatus(%#v) node groups == \ngot: %#v, \nexpected %#v",
+				c.status, actualNodeGroups, c.expectedNodeGroups)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterautoscaler
+
+import (
+	"log"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+const (
+	// StatusConfigMapNamespace is the namespace where cluster autoscaler writes its status.
+	StatusConfigMapNamespace = "kube-system"
+
+	// StatusConfigMapName is the name of the config map with the cluster autoscaler status.
+	StatusConfigMapName = "cluster-autoscaler-status"
+
+	// statusConfigMapKey is the key in config map data that holds the status.
+	statusConfigMapKey = "status"
+)
+
+// ClusterAutoscalerStatus contains cluster scaling information reported by cluster autoscaler.
+type ClusterAutoscalerStatus struct {
+	// True when cluster autoscaler status config map exists, i.e. cluster autoscaler is deployed.
+	Available bool `json:"available"`
+
+	// Time at which the status was last written by cluster autoscaler.
+	LastUpdateTime string `json:"lastUpdateTime"`
+
+	ClusterWide ClusterWideStatus `json:"clusterWide"`
+	NodeGroups  []NodeGroupStatus `json:"nodeGroups"`
+
+	// Number of pods that cannot be scheduled on any of the existing nodes.
+	UnschedulablePods int `json:"unschedulablePods"`
+
+	// Scale-up and scale-down activity reported by cluster autoscaler as events.
+	EventList common.EventList `json:"eventList"`
+}
+
+// GetClusterAutoscalerStatus returns cluster autoscaler status parsed from its status config map.
+func GetClusterAutoscalerStatus(client client.Interface, dsQuery *dataselect.DataSelectQuery) (
+	*ClusterAutoscalerStatus, error) {
+
+	log.Print("Getting cluster autoscaler status")
+
+	result := &ClusterAutoscalerStatus{
+		NodeGroups: make([]NodeGroupStatus, 0),
+		EventList:  common.EventList{Events: make([]common.Event, 0)},
+	}
+
+	configMap, err := client.CoreV1().ConfigMaps(StatusConfigMapNamespace).Get(StatusConfigMapName,
+		metaV1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	if err == nil {
+		result.Available = true
+		result.LastUpdateTime, result.ClusterWide, result.NodeGroups =
+			parseStatus(configMap.Data[statusConfigMapKey])
+
+		events, err := event.GetEvents(client, StatusConfigMapNamespace, StatusConfigMapName)
+		if err != nil {
+			return nil, err
+		}
+		result.EventList = event.CreateEventList(event.FillEventsType(events), dsQuery)
+	}
+
+	result.UnschedulablePods, err = getUnschedulablePodCount(client)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// getUnschedulablePodCount returns number of pending pods that scheduler failed to place.
+func getUnschedulablePodCount(client client.Interface) (int, error) {
+	channels := &common.ResourceChannels{
+		PodList: common.GetPodListChannelWithOptions(client, common.NewNamespaceQuery(nil),
+			metaV1.ListOptions{
+				LabelSelector: labels.Everything().String(),
+				FieldSelector: fields.OneTermEqualSelector("status.phase", string(v1.PodPending)).String(),
+			}, 1),
+	}
+
+	pods := <-channels.PodList.List
+	if err := <-channels.PodList.Error; err != nil {
+		return 0, err
+	}
+
+	return countUnschedulablePods(pods.Items), nil
+}
+
+func countUnschedulablePods(pods []v1.Pod) int {
+	count := 0
+	for _, pod := range pods {
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionFalse &&
+				condition.Reason == v1.PodReasonUnschedulable {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
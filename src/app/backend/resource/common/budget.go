@@ -0,0 +1,237 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"sort"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	client "k8s.io/client-go/kubernetes"
+	api "k8s.io/client-go/pkg/api/v1"
+)
+
+// MaxFanOutObjects is the number of pods and events above which a single list fan-out trims them.
+// Pods are then held in metadata-only mode, i.e. without the spec details lists do not use, and
+// only the latest warning events are kept. The API server of this version cannot list objects in
+// chunks, so pods and events of several namespaces are listed namespace by namespace and no further
+// namespaces are listed once the cap is reached. Only warning events are requested. Lists get a
+// warning above it. Zero means no trimming.
+var MaxFanOutObjects = 0
+
+// warningEventListOptions select events that are rolled up into warnings of lists.
+var warningEventListOptions = metaV1.ListOptions{
+	LabelSelector: labels.Everything().String(),
+	FieldSelector: fields.OneTermEqualSelector("type", api.EventTypeWarning).String(),
+}
+
+// lastAppliedConfigAnnotationKey holds the whole object as last applied by kubectl, it is dropped
+// from pods held in metadata-only mode.
+const lastAppliedConfigAnnotationKey = "kubectl.kubernetes.io/last-applied-configuration"
+
+// GetFanOutPodListChannel is GetPodListChannel for pods lists are built from. Pods above the cap
+// are held in metadata-only mode.
+func GetFanOutPodListChannel(client client.Interface, nsQuery *NamespaceQuery,
+	numReads int) PodListChannel {
+	return GetFanOutPodListChannelWithOptions(client, nsQuery, listEverything, numReads)
+}
+
+// GetFanOutPodListChannelWithOptions is GetFanOutPodListChannel plus list options.
+func GetFanOutPodListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) PodListChannel {
+	channel := PodListChannel{
+		List:  make(chan *api.PodList, numReads),
+		Error: make(chan error, numReads),
+	}
+
+	go func() {
+		list, err := listFanOutPods(client, nsQuery, options)
+		limitPodList(list)
+		for i := 0; i < numReads; i++ {
+			channel.List <- list
+			channel.Error <- err
+		}
+	}()
+
+	return channel
+}
+
+// getFanOutNamespaces returns namespaces whose pods and events are listed one by one, so that no
+// further namespaces are listed once the cap is reached. Nil is returned when they are listed at
+// once, i.e. without the cap, for a single namespace or when namespaces cannot be listed.
+func getFanOutNamespaces(client client.Interface, nsQuery *NamespaceQuery) []string {
+	if MaxFanOutObjects <= 0 || len(nsQuery.namespaces) == 1 {
+		return nil
+	}
+	if len(nsQuery.namespaces) > 1 {
+		return nsQuery.namespaces
+	}
+
+	list, err := client.CoreV1().Namespaces().List(listEverything)
+	if err != nil || len(list.Items) == 0 {
+		return nil
+	}
+	namespaces := make([]string, 0, len(list.Items))
+	for _, namespace := range list.Items {
+		namespaces = append(namespaces, namespace.Name)
+	}
+	return namespaces
+}
+
+// listFanOutPods lists pods of given namespaces. Namespaces above the cap are not listed.
+func listFanOutPods(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions) (*api.PodList, error) {
+	namespaces := getFanOutNamespaces(client, nsQuery)
+	if namespaces == nil {
+		pods := GetPodListChannelWithOptions(client, nsQuery, options, 1)
+		return <-pods.List, <-pods.Error
+	}
+
+	result := &api.PodList{}
+	for _, namespace := range namespaces {
+		pods := GetPodListChannelWithOptions(client, NewSameNamespaceQuery(namespace), options, 1)
+		list, err := <-pods.List, <-pods.Error
+		if err != nil {
+			return list, err
+		}
+		result.Items = append(result.Items, list.Items...)
+		if len(result.Items) > MaxFanOutObjects {
+			break
+		}
+	}
+	return result, nil
+}
+
+// listFanOutEvents lists warning events of given namespaces. Namespaces above the cap are not
+// listed. All events are listed without the cap.
+func listFanOutEvents(client client.Interface, nsQuery *NamespaceQuery) (*api.EventList, error) {
+	if MaxFanOutObjects <= 0 {
+		events := GetEventListChannel(client, nsQuery, 1)
+		return <-events.List, <-events.Error
+	}
+	namespaces := getFanOutNamespaces(client, nsQuery)
+	if namespaces == nil {
+		events := GetEventListChannelWithOptions(client, nsQuery, warningEventListOptions, 1)
+		return <-events.List, <-events.Error
+	}
+
+	result := &api.EventList{}
+	for _, namespace := range namespaces {
+		events := GetEventListChannelWithOptions(client, NewSameNamespaceQuery(namespace),
+			warningEventListOptions, 1)
+		list, err := <-events.List, <-events.Error
+		if err != nil {
+			return list, err
+		}
+		result.Items = append(result.Items, list.Items...)
+		if len(result.Items) > MaxFanOutObjects {
+			break
+		}
+	}
+	return result, nil
+}
+
+// limitPodList switches pods of lists above the cap to metadata-only mode. Pods are replaced in
+// place, the objects they share with the resource cache are not changed.
+func limitPodList(list *api.PodList) {
+	if list == nil || MaxFanOutObjects <= 0 || len(list.Items) <= MaxFanOutObjects {
+		return
+	}
+	for i := range list.Items {
+		list.Items[i] = toMetadataOnlyPod(&list.Items[i])
+	}
+}
+
+// toMetadataOnlyPod returns copy of given pod with metadata, status and the parts of spec lists
+// use, i.e. node name and names, images and resources of containers.
+func toMetadataOnlyPod(pod *api.Pod) api.Pod {
+	result := api.Pod{TypeMeta: pod.TypeMeta, ObjectMeta: pod.ObjectMeta, Status: pod.Status}
+	if _, ok := pod.Annotations[lastAppliedConfigAnnotationKey]; ok {
+		result.Annotations = make(map[string]string, len(pod.Annotations)-1)
+		for key, value := range pod.Annotations {
+			if key != lastAppliedConfigAnnotationKey {
+				result.Annotations[key] = value
+			}
+		}
+	}
+	result.Spec.NodeName = pod.Spec.NodeName
+	result.Spec.InitContainers = toMetadataOnlyContainers(pod.Spec.InitContainers)
+	result.Spec.Containers = toMetadataOnlyContainers(pod.Spec.Containers)
+	return result
+}
+
+func toMetadataOnlyContainers(containers []api.Container) []api.Container {
+	if containers == nil {
+		return nil
+	}
+	result := make([]api.Container, len(containers))
+	for i, container := range containers {
+		result[i] = api.Container{Name: container.Name, Image: container.Image,
+			Resources: container.Resources}
+	}
+	return result
+}
+
+// limitEventList drops events above the cap. Lists only use warning events, so normal events are
+// dropped first and then the oldest warnings.
+func limitEventList(list *api.EventList) {
+	if list == nil || MaxFanOutObjects <= 0 || len(list.Items) <= MaxFanOutObjects {
+		return
+	}
+	items := make([]api.Event, 0, MaxFanOutObjects)
+	for _, event := range list.Items {
+		// Type is not filled by older API servers, such events are kept.
+		if event.Type != api.EventTypeNormal {
+			items = append(items, event)
+		}
+	}
+	if len(items) > MaxFanOutObjects {
+		sort.Sort(eventsByLastTimestamp(items))
+		items = append([]api.Event{}, items[:MaxFanOutObjects]...)
+	}
+	list.Items = items
+}
+
+// eventsByLastTimestamp sorts events from the latest.
+type eventsByLastTimestamp []api.Event
+
+func (self eventsByLastTimestamp) Len() int      { return len(self) }
+func (self eventsByLastTimestamp) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+func (self eventsByLastTimestamp) Less(i, j int) bool {
+	return self[j].LastTimestamp.Before(self[i].LastTimestamp)
+}
+
+// GetListBudgetWarnings returns warnings for lists built from given pods and events if any of
+// them reached the cap. Pods above it are held in metadata-only mode and pods and events above it
+// may have been dropped.
+func GetListBudgetWarnings(pods *api.PodList, events *api.EventList) []string {
+	if MaxFanOutObjects <= 0 {
+		return nil
+	}
+
+	var warnings []string
+	if pods != nil && len(pods.Items) > MaxFanOutObjects {
+		warnings = append(warnings, fmt.Sprintf("The list was built from %d pods, more than the limit "+
+			"of %d objects, pod details are limited to metadata and status and pods of some "+
+			"namespaces may be left out", len(pods.Items), MaxFanOutObjects))
+	}
+	if events != nil && len(events.Items) >= MaxFanOutObjects {
+		warnings = append(warnings, fmt.Sprintf("Events reached the limit of %d objects, "+
+			"warnings of the list may be incomplete", MaxFanOutObjects))
+	}
+	return warnings
+}
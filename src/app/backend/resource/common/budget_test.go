@@ -0,0 +1,181 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	api "k8s.io/client-go/pkg/api/v1"
+	core "k8s.io/client-go/testing"
+)
+
+func TestGetFanOutPodListChannel(t *testing.T) {
+	defer func(max int) { MaxFanOutObjects = max }(MaxFanOutObjects)
+
+	container := api.Container{Name: "app", Image: "app:1", Env: []api.EnvVar{{Name: "A", Value: "a"}},
+		Resources: api.ResourceRequirements{Limits: api.ResourceList{
+			api.ResourceCPU: resource.MustParse("1")}}}
+	newPod := func(name string) *api.Pod {
+		return &api.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default", Annotations: map[string]string{
+				lastAppliedConfigAnnotationKey: "{}", "a": "b"}},
+			Spec:   api.PodSpec{NodeName: "node", Containers: []api.Container{container}},
+			Status: api.PodStatus{Phase: api.PodRunning},
+		}
+	}
+	cases := []struct {
+		max      int
+		expected api.Pod
+	}{
+		{0, *newPod("a")},
+		{2, *newPod("a")},
+		{1, api.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "a", Namespace: "default",
+				Annotations: map[string]string{"a": "b"}},
+			Spec: api.PodSpec{NodeName: "node", Containers: []api.Container{{Name: "app",
+				Image: "app:1", Resources: container.Resources}}},
+			Status: api.PodStatus{Phase: api.PodRunning},
+		}},
+	}
+	for _, c := range cases {
+		MaxFanOutObjects = c.max
+		client := fake.NewSimpleClientset(newPod("a"), newPod("b"))
+		channel := GetFanOutPodListChannel(client, NewNamespaceQuery(nil), 1)
+		list, err := <-channel.List, <-channel.Error
+		if err != nil {
+			t.Fatalf("GetFanOutPodListChannel() returns error: %v", err)
+		}
+		// Pods of a single list are never dropped, so that counts of lists stay correct.
+		if len(list.Items) != 2 {
+			t.Fatalf("GetFanOutPodListChannel() with limit %d returns %d pods, expected 2", c.max,
+				len(list.Items))
+		}
+		if !reflect.DeepEqual(list.Items[0], c.expected) {
+			t.Errorf("GetFanOutPodListChannel() with limit %d == \ngot: %#v, \nexpected %#v", c.max,
+				list.Items[0], c.expected)
+		}
+	}
+}
+
+func TestGetFanOutListsBounded(t *testing.T) {
+	defer func(max int) { MaxFanOutObjects = max }(MaxFanOutObjects)
+	MaxFanOutObjects = 1
+
+	var objects []runtime.Object
+	for _, namespace := range []string{"a", "b", "c"} {
+		objects = append(objects, &api.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: namespace}})
+		for _, name := range []string{"x", "y"} {
+			meta := metaV1.ObjectMeta{Name: name, Namespace: namespace}
+			objects = append(objects, &api.Pod{ObjectMeta: meta},
+				&api.Event{ObjectMeta: meta, Type: api.EventTypeWarning})
+		}
+	}
+	client := fake.NewSimpleClientset(objects...)
+
+	pods := GetFanOutPodListChannel(client, NewNamespaceQuery([]string{"a", "b", "c"}), 1)
+	if list, err := <-pods.List, <-pods.Error; err != nil || len(list.Items) != 2 {
+		t.Fatalf("GetFanOutPodListChannel() returns %#v, %v, expected pods of namespace a", list, err)
+	}
+	events := GetWarningEventListChannel(client, NewNamespaceQuery([]string{"a", "b", "c"}), 1)
+	if list, err := <-events.List, <-events.Error; err != nil || len(list.Items) != 1 {
+		t.Fatalf("GetWarningEventListChannel() returns %#v, %v, expected latest event of namespace a",
+			list, err)
+	}
+
+	for _, action := range client.Actions() {
+		listAction, ok := action.(core.ListAction)
+		if !ok || action.GetResource().Resource == "namespaces" {
+			continue
+		}
+		if action.GetNamespace() != "a" {
+			t.Errorf("Fan-out above the limit lists %s of namespace %s, expected only namespace a",
+				action.GetResource().Resource, action.GetNamespace())
+		}
+		fieldSelector := listAction.GetListRestrictions().Fields.String()
+		if action.GetResource().Resource == "events" && fieldSelector != "type=Warning" {
+			t.Errorf("Fan-out lists events with field selector %#v, expected type=Warning",
+				fieldSelector)
+		}
+	}
+}
+
+func TestLimitEventList(t *testing.T) {
+	defer func(max int) { MaxFanOutObjects = max }(MaxFanOutObjects)
+
+	newEvent := func(name, eventType string, minute int) api.Event {
+		return api.Event{ObjectMeta: metaV1.ObjectMeta{Name: name}, Type: eventType,
+			LastTimestamp: metaV1.NewTime(time.Date(2017, 5, 5, 10, minute, 0, 0, time.UTC))}
+	}
+	events := []api.Event{
+		newEvent("a", api.EventTypeWarning, 1),
+		newEvent("b", api.EventTypeNormal, 2),
+		newEvent("c", api.EventTypeWarning, 3),
+		newEvent("d", "", 4),
+	}
+	cases := []struct {
+		max      int
+		expected []string
+	}{
+		{0, []string{"a", "b", "c", "d"}},
+		{4, []string{"a", "b", "c", "d"}},
+		{3, []string{"a", "c", "d"}},
+		{2, []string{"d", "c"}},
+	}
+	for _, c := range cases {
+		MaxFanOutObjects = c.max
+		list := &api.EventList{Items: append([]api.Event{}, events...)}
+		limitEventList(list)
+		actual := []string{}
+		for _, event := range list.Items {
+			actual = append(actual, event.Name)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("limitEventList() with limit %d == \ngot: %#v, \nexpected %#v", c.max, actual,
+				c.expected)
+		}
+	}
+}
+
+func TestGetListBudgetWarnings(t *testing.T) {
+	defer func(max int) { MaxFanOutObjects = max }(MaxFanOutObjects)
+
+	pods := &api.PodList{Items: make([]api.Pod, 2)}
+	events := &api.EventList{Items: make([]api.Event, 3)}
+	cases := []struct {
+		max      int
+		expected []string
+	}{
+		{0, nil},
+		{4, nil},
+		{3, []string{"Events reached the limit of 3 objects, warnings of the list may be incomplete"}},
+		{1, []string{"The list was built from 2 pods, more than the limit of 1 objects, pod details " +
+			"are limited to metadata and status and pods of some namespaces may be left out",
+			"Events reached the limit of 1 objects, warnings of the list may be incomplete"}},
+	}
+	for _, c := range cases {
+		MaxFanOutObjects = c.max
+		actual := GetListBudgetWarnings(pods, events)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetListBudgetWarnings() with limit %d == \ngot: %#v, \nexpected %#v", c.max, actual,
+				c.expected)
+		}
+	}
+}
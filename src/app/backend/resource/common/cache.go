@@ -0,0 +1,159 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"log"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	client "k8s.io/client-go/kubernetes"
+	kubeapi "k8s.io/client-go/pkg/api"
+	api "k8s.io/client-go/pkg/api/v1"
+	authorizationApi "k8s.io/client-go/pkg/apis/authorization/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+// SharedCache serves lists of deployments, replica sets, pods and events from memory when set.
+// Nil means these lists are always fetched from the API server.
+var SharedCache *ResourceCache
+
+// ResourceCache keeps frequently listed resources of all namespaces in memory, kept up to date
+// by informers, so that repeated list requests don't hit the API server. Objects are watched with
+// the dashboard's own credentials, so every served list is preceded by an access review of the
+// user.
+type ResourceCache struct {
+	// Cached resources keyed by resource name.
+	resources map[string]*cachedResource
+}
+
+// cachedResource is a store of objects of a single resource.
+type cachedResource struct {
+	// API group and name of the resource, used for access reviews.
+	group    string
+	resource string
+
+	indexer   cache.Indexer
+	hasSynced func() bool
+}
+
+// NewResourceCache creates resource cache. It serves nothing until started.
+func NewResourceCache() *ResourceCache {
+	return &ResourceCache{resources: make(map[string]*cachedResource)}
+}
+
+// Start starts informers of cached resources in background, using given client.
+func (self *ResourceCache) Start(client client.Interface) {
+	log.Print("Caching deployments, replica sets, pods and events of all namespaces")
+	self.watch(client.CoreV1().RESTClient(), "", "pods", &api.Pod{})
+	self.watch(client.CoreV1().RESTClient(), "", "events", &api.Event{})
+	self.watch(client.ExtensionsV1beta1().RESTClient(), "extensions", "deployments",
+		&extensions.Deployment{})
+	self.watch(client.ExtensionsV1beta1().RESTClient(), "extensions", "replicasets",
+		&extensions.ReplicaSet{})
+}
+
+func (self *ResourceCache) watch(restClient rest.Interface, group, resource string,
+	obj runtime.Object) {
+	listWatch := cache.NewListWatchFromClient(restClient, resource, metaV1.NamespaceAll,
+		fields.Everything())
+	indexer, controller := cache.NewIndexerInformer(listWatch, obj, 0,
+		cache.ResourceEventHandlerFuncs{},
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	go controller.Run(make(chan struct{}))
+
+	self.resources[resource] = &cachedResource{
+		group:     group,
+		resource:  resource,
+		indexer:   indexer,
+		hasSynced: controller.HasSynced,
+	}
+}
+
+// list returns copies of cached objects of given resource that the user of given client would
+// get by listing them. False is returned when the list has to be fetched from the API server
+// instead: the cache is not enabled or synced yet, the options can't be applied to the cache or
+// the user can't list the resource, so that the API server returns the error.
+func (self *ResourceCache) list(client client.Interface, resource string,
+	nsQuery *NamespaceQuery, options metaV1.ListOptions) ([]interface{}, bool) {
+	if self == nil {
+		return nil, false
+	}
+	cached, ok := self.resources[resource]
+	if !ok || !cached.hasSynced() || options.ResourceVersion != "" {
+		return nil, false
+	}
+
+	labelSelector, err := labels.Parse(options.LabelSelector)
+	if err != nil {
+		return nil, false
+	}
+	fieldSelector, err := fields.ParseSelector(options.FieldSelector)
+	if err != nil || !fieldSelector.Empty() {
+		return nil, false
+	}
+
+	namespace := nsQuery.ToRequestParam()
+	if !canList(client, cached.group, cached.resource, namespace) {
+		return nil, false
+	}
+
+	var objects []interface{}
+	if namespace == api.NamespaceAll {
+		objects = cached.indexer.List()
+	} else {
+		objects, err = cached.indexer.Index(cache.NamespaceIndex,
+			&metaV1.ObjectMeta{Namespace: namespace})
+		if err != nil {
+			return nil, false
+		}
+	}
+
+	result := make([]interface{}, 0, len(objects))
+	for _, obj := range objects {
+		meta, ok := obj.(metaV1.Object)
+		if !ok || !labelSelector.Matches(labels.Set(meta.GetLabels())) {
+			continue
+		}
+		// Objects in the store are shared, callers get their own copies.
+		copied, err := kubeapi.Scheme.DeepCopy(obj)
+		if err != nil {
+			return nil, false
+		}
+		result = append(result, copied)
+	}
+	return result, true
+}
+
+// canList checks if the user of given client can list given resource in given namespace, all
+// namespaces if empty.
+func canList(client client.Interface, group, resource, namespace string) bool {
+	review, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(
+		&authorizationApi.SelfSubjectAccessReview{
+			Spec: authorizationApi.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationApi.ResourceAttributes{
+					Verb:      "list",
+					Group:     group,
+					Resource:  resource,
+					Namespace: namespace,
+				},
+			},
+		})
+	return err == nil && review.Status.Allowed
+}
@@ -0,0 +1,102 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	api "k8s.io/client-go/pkg/api/v1"
+	authorizationApi "k8s.io/client-go/pkg/apis/authorization/v1"
+	core "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestSharedCachePodList(t *testing.T) {
+	defer func(sharedCache *ResourceCache) { SharedCache = sharedCache }(SharedCache)
+
+	pod := func(namespace, name, app string) *api.Pod {
+		return &api.Pod{ObjectMeta: metaV1.ObjectMeta{Namespace: namespace, Name: name,
+			Labels: map[string]string{"app": app}}}
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	indexer.Add(pod("a", "cached-1", "foo"))
+	indexer.Add(pod("a", "cached-2", "bar"))
+	indexer.Add(pod("b", "cached-3", "foo"))
+
+	synced := true
+	SharedCache = &ResourceCache{resources: map[string]*cachedResource{
+		"pods": {resource: "pods", indexer: indexer, hasSynced: func() bool { return synced }},
+	}}
+
+	cases := []struct {
+		namespaces []string
+		options    metaV1.ListOptions
+		allowed    bool
+		synced     bool
+		expected   []string
+	}{
+		{[]string{"a"}, listEverything, true, true, []string{"cached-1", "cached-2"}},
+		{[]string{"a"}, metaV1.ListOptions{LabelSelector: "app=foo"}, true, true,
+			[]string{"cached-1"}},
+		{nil, metaV1.ListOptions{LabelSelector: "app=foo"}, true, true,
+			[]string{"cached-1", "cached-3"}},
+		{[]string{"a"}, listEverything, false, true, []string{"live"}},
+		{[]string{"a"}, listEverything, true, false, []string{"live"}},
+		{[]string{"a"}, metaV1.ListOptions{FieldSelector: "spec.nodeName=node"}, true, true,
+			[]string{"live"}},
+	}
+	for _, c := range cases {
+		synced = c.synced
+		fakeClient := fake.NewSimpleClientset(pod("a", "live", "foo"))
+		fakeClient.PrependReactor("create", "selfsubjectaccessreviews",
+			func(action core.Action) (bool, runtime.Object, error) {
+				return true, &authorizationApi.SelfSubjectAccessReview{
+					Status: authorizationApi.SubjectAccessReviewStatus{Allowed: c.allowed},
+				}, nil
+			})
+
+		channel := GetPodListChannelWithOptions(fakeClient, NewNamespaceQuery(c.namespaces),
+			c.options, 1)
+		list := <-channel.List
+		if err := <-channel.Error; err != nil {
+			t.Fatalf("GetPodListChannelWithOptions() returned error: %s", err)
+		}
+
+		actual := make([]string, 0)
+		for _, item := range list.Items {
+			actual = append(actual, item.Name)
+			// Changes of listed pods must not leak into the cache.
+			item.Labels["app"] = "changed"
+		}
+		sort.Strings(actual)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetPodListChannelWithOptions(%#v, %#v) with allowed: %v, synced: %v == "+
+				"\ngot: %#v, \nexpected %#v", c.namespaces, c.options, c.allowed, c.synced,
+				actual, c.expected)
+		}
+	}
+
+	for _, obj := range indexer.List() {
+		if app := obj.(*api.Pod).Labels["app"]; app == "changed" {
+			t.Errorf("Cached pod %s was changed by a caller", obj.(*api.Pod).Name)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	client "k8s.io/client-go/kubernetes"
+)
+
+// GetGroupVersion returns preferred group version of the first of given API groups served by the
+// cluster, e.g. velero.io/v1. Second value is false when custom resources of none of the groups
+// are installed in the cluster.
+func GetGroupVersion(client client.Interface, groups ...string) (string, bool, error) {
+	serverGroups, err := client.Discovery().ServerGroups()
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, name := range groups {
+		for _, group := range serverGroups.Groups {
+			if group.Name == name {
+				return group.PreferredVersion.GroupVersion, true, nil
+			}
+		}
+	}
+
+	return "", false, nil
+}
+
+// GetInstalledGroupVersion returns preferred group version of the first of given API groups or
+// not found error of given resource when none of them is installed, for actions that cannot be
+// performed without it.
+func GetInstalledGroupVersion(client client.Interface, resource, name string,
+	groups ...string) (string, error) {
+	groupVersion, found, err := GetGroupVersion(client, groups...)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", k8serrors.NewNotFound(schema.GroupResource{Group: groups[0], Resource: resource},
+			name)
+	}
+	return groupVersion, nil
+}
+
+// GetCustomResourcePath returns path of given custom resource. Namespace is empty for cluster
+// scoped resources and for listing resources in all namespaces, name is empty for lists.
+func GetCustomResourcePath(groupVersion, namespace, resource, name string) string {
+	path := "/apis/" + groupVersion
+	if namespace != "" {
+		path += "/namespaces/" + namespace
+	}
+	path += "/" + resource
+	if name != "" {
+		path += "/" + name
+	}
+	return path
+}
+
+// GetCustomResource gets given custom resource or list of them and unmarshals it into result.
+// Custom resource types are not known to the client, so the core REST client is used with an
+// absolute path.
+func GetCustomResource(client client.Interface, groupVersion, namespace, resource, name string,
+	result interface{}) error {
+	raw, err := JSONRequest(client.CoreV1().RESTClient().Get().
+		AbsPath(GetCustomResourcePath(groupVersion, namespace, resource, name))).Do().Raw()
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, result)
+}
@@ -0,0 +1,115 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func newCustomResourceServer(t *testing.T) (*httptest.Server, kubernetes.Interface) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/apis":
+			w.Write([]byte(`{"kind": "APIGroupList", "groups": [
+				{"name": "certmanager.k8s.io", "preferredVersion": {"groupVersion": "certmanager.k8s.io/v1alpha1"}},
+				{"name": "cert-manager.io", "preferredVersion": {"groupVersion": "cert-manager.io/v1"}}]}`))
+		case "/apis/cert-manager.io/v1/namespaces/default/certificates/web":
+			if r.Header.Get("Accept") != "application/json" {
+				w.WriteHeader(http.StatusNotAcceptable)
+				return
+			}
+			w.Write([]byte(`{"metadata": {"name": "web"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("Cannot create client: %v", err)
+	}
+	return server, client
+}
+
+func TestGetGroupVersion(t *testing.T) {
+	server, client := newCustomResourceServer(t)
+	defer server.Close()
+
+	cases := []struct {
+		groups               []string
+		expectedGroupVersion string
+		expectedFound        bool
+	}{
+		{[]string{"cert-manager.io", "certmanager.k8s.io"}, "cert-manager.io/v1", true},
+		{[]string{"certmanager.k8s.io"}, "certmanager.k8s.io/v1alpha1", true},
+		{[]string{"velero.io"}, "", false},
+	}
+	for _, c := range cases {
+		groupVersion, found, err := GetGroupVersion(client, c.groups...)
+		if err != nil {
+			t.Fatalf("GetGroupVersion(%#v) returned error: %s", c.groups, err)
+		}
+		if groupVersion != c.expectedGroupVersion || found != c.expectedFound {
+			t.Errorf("GetGroupVersion(%#v) == %s, %t, expected %s, %t", c.groups, groupVersion,
+				found, c.expectedGroupVersion, c.expectedFound)
+		}
+	}
+
+	if _, err := GetInstalledGroupVersion(client, "backups", "daily", "velero.io"); !k8serrors.IsNotFound(err) {
+		t.Errorf("GetInstalledGroupVersion() of not installed group returns %v, expected not found", err)
+	}
+}
+
+func TestGetCustomResource(t *testing.T) {
+	server, client := newCustomResourceServer(t)
+	defer server.Close()
+
+	result := struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}{}
+	if err := GetCustomResource(client, "cert-manager.io/v1", "default", "certificates", "web",
+		&result); err != nil {
+		t.Fatalf("GetCustomResource() returned error: %s", err)
+	}
+	if result.Metadata.Name != "web" {
+		t.Errorf("GetCustomResource() got name %s, expected web", result.Metadata.Name)
+	}
+}
+
+func TestGetCustomResourcePath(t *testing.T) {
+	cases := []struct {
+		namespace, name string
+		expected        string
+	}{
+		{"default", "web", "/apis/cert-manager.io/v1/namespaces/default/certificates/web"},
+		{"default", "", "/apis/cert-manager.io/v1/namespaces/default/certificates"},
+		{"", "", "/apis/cert-manager.io/v1/certificates"},
+	}
+	for _, c := range cases {
+		actual := GetCustomResourcePath("cert-manager.io/v1", c.namespace, "certificates", c.name)
+		if actual != c.expected {
+			t.Errorf("GetCustomResourcePath(%s, %s) == %s, expected %s", c.namespace, c.name,
+				actual, c.expected)
+		}
+	}
+}
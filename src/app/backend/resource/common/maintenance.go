@@ -0,0 +1,77 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	api "k8s.io/client-go/pkg/api/v1"
+)
+
+// NamespaceMaintenanceAnnotationKey is the namespace annotation that marks namespace as in
+// maintenance. Its value is JSON encoded NamespaceMaintenance.
+const NamespaceMaintenanceAnnotationKey = "dashboard.alpha.kubernetes.io/maintenance"
+
+// NamespaceMaintenance describes maintenance window of a namespace. Warnings of resources in
+// namespaces in maintenance are not rolled up into lists.
+type NamespaceMaintenance struct {
+	// Reason of the maintenance, e.g. link to the change request.
+	Reason string `json:"reason,omitempty"`
+
+	// Freeze blocks changes made through the dashboard by users that are not allowed to update
+	// the namespace itself.
+	Freeze bool `json:"freeze"`
+
+	// Since is the time the namespace was put in maintenance.
+	Since metaV1.Time `json:"since"`
+}
+
+// GetNamespaceMaintenance returns maintenance stored in given namespace annotations or nil if the
+// namespace is not in maintenance. Invalid annotation values are treated as maintenance without
+// freeze, so that warnings stay suppressed.
+func GetNamespaceMaintenance(annotations map[string]string) *NamespaceMaintenance {
+	raw, ok := annotations[NamespaceMaintenanceAnnotationKey]
+	if !ok {
+		return nil
+	}
+
+	maintenance := &NamespaceMaintenance{}
+	if err := json.Unmarshal([]byte(raw), maintenance); err != nil {
+		return &NamespaceMaintenance{}
+	}
+	return maintenance
+}
+
+// FilterMaintenanceEvents returns events that do not belong to namespaces in maintenance.
+func FilterMaintenanceEvents(events []api.Event, namespaces []api.Namespace) []api.Event {
+	inMaintenance := make(map[string]bool)
+	for _, namespace := range namespaces {
+		if GetNamespaceMaintenance(namespace.Annotations) != nil {
+			inMaintenance[namespace.Name] = true
+		}
+	}
+	if len(inMaintenance) == 0 {
+		return events
+	}
+
+	result := make([]api.Event, 0)
+	for _, event := range events {
+		if !inMaintenance[event.Namespace] {
+			result = append(result, event)
+		}
+	}
+	return result
+}
@@ -0,0 +1,79 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	api "k8s.io/client-go/pkg/api/v1"
+)
+
+func TestGetNamespaceMaintenance(t *testing.T) {
+	since := metaV1.NewTime(time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC).Local())
+	cases := []struct {
+		annotations map[string]string
+		expected    *NamespaceMaintenance
+	}{
+		{nil, nil},
+		{map[string]string{"foo": "bar"}, nil},
+		{
+			map[string]string{NamespaceMaintenanceAnnotationKey: `{"reason":"upgrade","freeze":true,` +
+				`"since":"2017-05-05T10:00:00Z"}`},
+			&NamespaceMaintenance{Reason: "upgrade", Freeze: true, Since: since},
+		},
+		{
+			map[string]string{NamespaceMaintenanceAnnotationKey: "invalid"},
+			&NamespaceMaintenance{},
+		},
+	}
+	for _, c := range cases {
+		actual := GetNamespaceMaintenance(c.annotations)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetNamespaceMaintenance(%#v) == \ngot: %#v, \nexpected %#v",
+				c.annotations, actual, c.expected)
+		}
+	}
+}
+
+func TestFilterMaintenanceEvents(t *testing.T) {
+	events := []api.Event{
+		{ObjectMeta: metaV1.ObjectMeta{Name: "a", Namespace: "default"}},
+		{ObjectMeta: metaV1.ObjectMeta{Name: "b", Namespace: "frozen"}},
+	}
+	cases := []struct {
+		namespaces []api.Namespace
+		expected   []api.Event
+	}{
+		{nil, events},
+		{
+			[]api.Namespace{
+				{ObjectMeta: metaV1.ObjectMeta{Name: "default"}},
+				{ObjectMeta: metaV1.ObjectMeta{Name: "frozen",
+					Annotations: map[string]string{NamespaceMaintenanceAnnotationKey: "{}"}}},
+			},
+			events[:1],
+		},
+	}
+	for _, c := range cases {
+		actual := FilterMaintenanceEvents(events, c.namespaces)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("FilterMaintenanceEvents(%#v, %#v) == \ngot: %#v, \nexpected %#v",
+				events, c.namespaces, actual, c.expected)
+		}
+	}
+}
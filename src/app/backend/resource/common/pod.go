@@ -114,3 +114,13 @@ func EqualIgnoreHash(template1, template2 v1.PodTemplateSpec) bool {
 	template1.Labels, template2.Labels = nil, nil
 	return helper.Semantic.DeepEqual(template1, template2)
 }
+
+// IsPodReady returns true if given pod has ready condition set to true.
+func IsPodReady(pod *v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -21,7 +21,7 @@ import (
 	"strings"
 	"time"
 
-	client "github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	heapster "k8s.io/heapster/metrics/api/v1/types"
 )
 
@@ -59,7 +59,7 @@ type PodMetrics struct {
 // Return Pods metrics for the given list of pods. Returns error in case of errors when talking
 // with heapster.
 func getPodListMetrics(podNamesByNamespace map[string][]string,
-	heapsterClient client.HeapsterClient) (*MetricsByPod, error) {
+	heapsterClient metricapi.MetricClient) (*MetricsByPod, error) {
 	log.Print("Getting pod metrics")
 
 	result := &MetricsByPod{MetricsMap: make(map[string]map[string]PodMetrics)}
@@ -107,7 +107,7 @@ func createMetricPath(namespace string, podNames []string, metricName string) st
 }
 
 // Retrieves raw metrics from Heapster.
-func getRawMetrics(heapsterClient client.HeapsterClient, metricPath string) ([]byte, error) {
+func getRawMetrics(heapsterClient metricapi.MetricClient, metricPath string) ([]byte, error) {
 	resultRaw, err := heapsterClient.Get(metricPath).DoRaw()
 
 	if err != nil {
@@ -0,0 +1,33 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+)
+
+// JSONRequest makes the API server answer given raw request in JSON. Dashboard clients negotiate
+// protobuf, in which built-in resources are served, so raw responses decoded as JSON have to ask
+// for it.
+func JSONRequest(request *rest.Request) *rest.Request {
+	return request.SetHeader("Accept", runtime.ContentTypeJSON)
+}
+
+// JSONBody sets given JSON encoded body of the request with its content type, which the client
+// doesn't set for raw bodies.
+func JSONBody(request *rest.Request, body []byte) *rest.Request {
+	return request.SetHeader("Content-Type", runtime.ContentTypeJSON).Body(body)
+}
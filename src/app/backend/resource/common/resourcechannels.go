@@ -15,7 +15,7 @@
 package common
 
 import (
-	kdClient "github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
@@ -24,7 +24,9 @@ import (
 	apps "k8s.io/client-go/pkg/apis/apps/v1beta1"
 	autoscaling "k8s.io/client-go/pkg/apis/autoscaling/v1"
 	batch "k8s.io/client-go/pkg/apis/batch/v1"
+	batch2 "k8s.io/client-go/pkg/apis/batch/v2alpha1"
 	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+	policy "k8s.io/client-go/pkg/apis/policy/v1beta1"
 	rbac "k8s.io/client-go/pkg/apis/rbac/v1alpha1"
 	storage "k8s.io/client-go/pkg/apis/storage/v1beta1"
 )
@@ -56,9 +58,15 @@ type ResourceChannels struct {
 	// List and error channels to Jobs.
 	JobList JobListChannel
 
+	// List and error channels to Cron Jobs.
+	CronJobList CronJobListChannel
+
 	// List and error channels to Services.
 	ServiceList ServiceListChannel
 
+	// List and error channels to Endpoints.
+	EndpointsList EndpointsListChannel
+
 	// List and error channels to Ingresses.
 	IngressList IngressListChannel
 
@@ -118,6 +126,12 @@ type ResourceChannels struct {
 
 	// List and error channels to ClusterRoleBindings
 	ClusterRoleBindingList ClusterRoleBindingListChannel
+
+	// List and error channels to NetworkPolicies
+	NetworkPolicyList NetworkPolicyListChannel
+
+	// List and error channels to PodDisruptionBudgets
+	PodDisruptionBudgetList PodDisruptionBudgetListChannel
 }
 
 // ServiceListChannel is a list and error channels to Services.
@@ -130,13 +144,19 @@ type ServiceListChannel struct {
 // must be read numReads times.
 func GetServiceListChannel(client client.Interface, nsQuery *NamespaceQuery,
 	numReads int) ServiceListChannel {
+	return GetServiceListChannelWithOptions(client, nsQuery, listEverything, numReads)
+}
+
+// GetServiceListChannelWithOptions is GetServiceListChannel plus listing options.
+func GetServiceListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) ServiceListChannel {
 
 	channel := ServiceListChannel{
 		List:  make(chan *api.ServiceList, numReads),
 		Error: make(chan error, numReads),
 	}
 	go func() {
-		list, err := client.CoreV1().Services(nsQuery.ToRequestParam()).List(listEverything)
+		list, err := client.CoreV1().Services(nsQuery.ToRequestParam()).List(options)
 		var filteredItems []api.Service
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -153,6 +173,39 @@ func GetServiceListChannel(client client.Interface, nsQuery *NamespaceQuery,
 	return channel
 }
 
+// EndpointsListChannel is a list and error channels to Endpoints.
+type EndpointsListChannel struct {
+	List  chan *api.EndpointsList
+	Error chan error
+}
+
+// GetEndpointsListChannel returns a pair of channels to an Endpoints list and errors that both
+// must be read numReads times.
+func GetEndpointsListChannel(client client.Interface, nsQuery *NamespaceQuery,
+	numReads int) EndpointsListChannel {
+
+	channel := EndpointsListChannel{
+		List:  make(chan *api.EndpointsList, numReads),
+		Error: make(chan error, numReads),
+	}
+	go func() {
+		list, err := client.CoreV1().Endpoints(nsQuery.ToRequestParam()).List(listEverything)
+		var filteredItems []api.Endpoints
+		for _, item := range list.Items {
+			if nsQuery.Matches(item.ObjectMeta.Namespace) {
+				filteredItems = append(filteredItems, item)
+			}
+		}
+		list.Items = filteredItems
+		for i := 0; i < numReads; i++ {
+			channel.List <- list
+			channel.Error <- err
+		}
+	}()
+
+	return channel
+}
+
 // IngressListChannel is a list and error channels to Ingresss.
 type IngressListChannel struct {
 	List  chan *extensions.IngressList
@@ -288,7 +341,16 @@ func GetEventListChannelWithOptions(client client.Interface,
 	}
 
 	go func() {
-		list, err := client.CoreV1().Events(nsQuery.ToRequestParam()).List(options)
+		var list *api.EventList
+		var err error
+		if items, ok := SharedCache.list(client, "events", nsQuery, options); ok {
+			list = &api.EventList{}
+			for _, item := range items {
+				list.Items = append(list.Items, *item.(*api.Event))
+			}
+		} else {
+			list, err = client.CoreV1().Events(nsQuery.ToRequestParam()).List(options)
+		}
 		var filteredItems []api.Event
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -305,6 +367,35 @@ func GetEventListChannelWithOptions(client client.Interface,
 	return channel
 }
 
+// GetWarningEventListChannel is GetEventListChannel for events that are rolled up into warnings
+// of resource lists. Events from namespaces in maintenance are left out. If namespaces cannot be
+// listed, e.g. because of missing permissions, all events are returned. Events above
+// MaxFanOutObjects are dropped.
+func GetWarningEventListChannel(client client.Interface,
+	nsQuery *NamespaceQuery, numReads int) EventListChannel {
+	channel := EventListChannel{
+		List:  make(chan *api.EventList, numReads),
+		Error: make(chan error, numReads),
+	}
+
+	go func() {
+		list, err := listFanOutEvents(client, nsQuery)
+		if err == nil {
+			namespaces, nsErr := client.CoreV1().Namespaces().List(listEverything)
+			if nsErr == nil {
+				list.Items = FilterMaintenanceEvents(list.Items, namespaces.Items)
+			}
+		}
+		limitEventList(list)
+		for i := 0; i < numReads; i++ {
+			channel.List <- list
+			channel.Error <- err
+		}
+	}()
+
+	return channel
+}
+
 // PodListChannel is a list and error channels to Nodes.
 type PodListChannel struct {
 	List  chan *api.PodList
@@ -328,7 +419,16 @@ func GetPodListChannelWithOptions(client client.Interface, nsQuery *NamespaceQue
 	}
 
 	go func() {
-		list, err := client.CoreV1().Pods(nsQuery.ToRequestParam()).List(options)
+		var list *api.PodList
+		var err error
+		if items, ok := SharedCache.list(client, "pods", nsQuery, options); ok {
+			list = &api.PodList{}
+			for _, item := range items {
+				list.Items = append(list.Items, *item.(*api.Pod))
+			}
+		} else {
+			list, err = client.CoreV1().Pods(nsQuery.ToRequestParam()).List(options)
+		}
 		var filteredItems []api.Pod
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -356,6 +456,13 @@ type ReplicationControllerListChannel struct {
 // numReads times.
 func GetReplicationControllerListChannel(client client.Interface,
 	nsQuery *NamespaceQuery, numReads int) ReplicationControllerListChannel {
+	return GetReplicationControllerListChannelWithOptions(client, nsQuery, listEverything, numReads)
+}
+
+// GetReplicationControllerListChannelWithOptions is GetReplicationControllerListChannel plus
+// listing options.
+func GetReplicationControllerListChannelWithOptions(client client.Interface,
+	nsQuery *NamespaceQuery, options metaV1.ListOptions, numReads int) ReplicationControllerListChannel {
 
 	channel := ReplicationControllerListChannel{
 		List:  make(chan *api.ReplicationControllerList, numReads),
@@ -364,7 +471,7 @@ func GetReplicationControllerListChannel(client client.Interface,
 
 	go func() {
 		list, err := client.CoreV1().ReplicationControllers(nsQuery.ToRequestParam()).
-			List(listEverything)
+			List(options)
 		var filteredItems []api.ReplicationController
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -391,6 +498,12 @@ type DeploymentListChannel struct {
 // that both must be read numReads times.
 func GetDeploymentListChannel(client client.Interface,
 	nsQuery *NamespaceQuery, numReads int) DeploymentListChannel {
+	return GetDeploymentListChannelWithOptions(client, nsQuery, listEverything, numReads)
+}
+
+// GetDeploymentListChannelWithOptions is GetDeploymentListChannel plus listing options.
+func GetDeploymentListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) DeploymentListChannel {
 
 	channel := DeploymentListChannel{
 		List:  make(chan *extensions.DeploymentList, numReads),
@@ -398,8 +511,17 @@ func GetDeploymentListChannel(client client.Interface,
 	}
 
 	go func() {
-		list, err := client.ExtensionsV1beta1().Deployments(nsQuery.ToRequestParam()).
-			List(listEverything)
+		var list *extensions.DeploymentList
+		var err error
+		if items, ok := SharedCache.list(client, "deployments", nsQuery, options); ok {
+			list = &extensions.DeploymentList{}
+			for _, item := range items {
+				list.Items = append(list.Items, *item.(*extensions.Deployment))
+			}
+		} else {
+			list, err = client.ExtensionsV1beta1().Deployments(nsQuery.ToRequestParam()).
+				List(options)
+		}
 		var filteredItems []extensions.Deployment
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -439,8 +561,17 @@ func GetReplicaSetListChannelWithOptions(client client.Interface,
 	}
 
 	go func() {
-		list, err := client.ExtensionsV1beta1().ReplicaSets(nsQuery.ToRequestParam()).
-			List(options)
+		var list *extensions.ReplicaSetList
+		var err error
+		if items, ok := SharedCache.list(client, "replicasets", nsQuery, options); ok {
+			list = &extensions.ReplicaSetList{}
+			for _, item := range items {
+				list.Items = append(list.Items, *item.(*extensions.ReplicaSet))
+			}
+		} else {
+			list, err = client.ExtensionsV1beta1().ReplicaSets(nsQuery.ToRequestParam()).
+				List(options)
+		}
 		var filteredItems []extensions.ReplicaSet
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -467,6 +598,13 @@ type DaemonSetListChannel struct {
 // both must be read numReads times.
 func GetDaemonSetListChannel(client client.Interface,
 	nsQuery *NamespaceQuery, numReads int) DaemonSetListChannel {
+	return GetDaemonSetListChannelWithOptions(client, nsQuery, listEverything, numReads)
+}
+
+// GetDaemonSetListChannelWithOptions is GetDaemonSetListChannel plus listing options.
+func GetDaemonSetListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) DaemonSetListChannel {
+
 	channel := DaemonSetListChannel{
 		List:  make(chan *extensions.DaemonSetList, numReads),
 		Error: make(chan error, numReads),
@@ -474,7 +612,7 @@ func GetDaemonSetListChannel(client client.Interface,
 
 	go func() {
 		list, err := client.ExtensionsV1beta1().DaemonSets(nsQuery.ToRequestParam()).
-			List(listEverything)
+			List(options)
 		var filteredItems []extensions.DaemonSet
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -501,13 +639,20 @@ type JobListChannel struct {
 // both must be read numReads times.
 func GetJobListChannel(client client.Interface,
 	nsQuery *NamespaceQuery, numReads int) JobListChannel {
+	return GetJobListChannelWithOptions(client, nsQuery, listEverything, numReads)
+}
+
+// GetJobListChannelWithOptions is GetJobListChannel plus listing options.
+func GetJobListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) JobListChannel {
+
 	channel := JobListChannel{
 		List:  make(chan *batch.JobList, numReads),
 		Error: make(chan error, numReads),
 	}
 
 	go func() {
-		list, err := client.BatchV1().Jobs(nsQuery.ToRequestParam()).List(listEverything)
+		list, err := client.BatchV1().Jobs(nsQuery.ToRequestParam()).List(options)
 		var filteredItems []batch.Job
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -524,6 +669,40 @@ func GetJobListChannel(client client.Interface,
 	return channel
 }
 
+// CronJobListChannel is a list and error channels to Cron Jobs.
+type CronJobListChannel struct {
+	List  chan *batch2.CronJobList
+	Error chan error
+}
+
+// GetCronJobListChannel returns a pair of channels to a CronJob list and errors that
+// both must be read numReads times.
+func GetCronJobListChannel(client client.Interface,
+	nsQuery *NamespaceQuery, numReads int) CronJobListChannel {
+
+	channel := CronJobListChannel{
+		List:  make(chan *batch2.CronJobList, numReads),
+		Error: make(chan error, numReads),
+	}
+
+	go func() {
+		list, err := client.BatchV2alpha1().CronJobs(nsQuery.ToRequestParam()).List(listEverything)
+		var filteredItems []batch2.CronJob
+		for _, item := range list.Items {
+			if nsQuery.Matches(item.ObjectMeta.Namespace) {
+				filteredItems = append(filteredItems, item)
+			}
+		}
+		list.Items = filteredItems
+		for i := 0; i < numReads; i++ {
+			channel.List <- list
+			channel.Error <- err
+		}
+	}()
+
+	return channel
+}
+
 // StatefulSetListChannel is a list and error channels to Nodes.
 type StatefulSetListChannel struct {
 	List  chan *apps.StatefulSetList
@@ -534,6 +713,13 @@ type StatefulSetListChannel struct {
 // both must be read numReads times.
 func GetStatefulSetListChannel(client client.Interface,
 	nsQuery *NamespaceQuery, numReads int) StatefulSetListChannel {
+	return GetStatefulSetListChannelWithOptions(client, nsQuery, listEverything, numReads)
+}
+
+// GetStatefulSetListChannelWithOptions is GetStatefulSetListChannel plus listing options.
+func GetStatefulSetListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) StatefulSetListChannel {
+
 	channel := StatefulSetListChannel{
 		List:  make(chan *apps.StatefulSetList, numReads),
 		Error: make(chan error, numReads),
@@ -541,7 +727,7 @@ func GetStatefulSetListChannel(client client.Interface,
 
 	go func() {
 		statefulSets, err := client.AppsV1beta1().StatefulSets(nsQuery.ToRequestParam()).
-			List(listEverything)
+			List(options)
 		var filteredItems []apps.StatefulSet
 		for _, item := range statefulSets.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -568,6 +754,12 @@ type ConfigMapListChannel struct {
 // both must be read numReads times.
 func GetConfigMapListChannel(client client.Interface, nsQuery *NamespaceQuery,
 	numReads int) ConfigMapListChannel {
+	return GetConfigMapListChannelWithOptions(client, nsQuery, listEverything, numReads)
+}
+
+// GetConfigMapListChannelWithOptions is GetConfigMapListChannel plus listing options.
+func GetConfigMapListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) ConfigMapListChannel {
 
 	channel := ConfigMapListChannel{
 		List:  make(chan *api.ConfigMapList, numReads),
@@ -576,7 +768,7 @@ func GetConfigMapListChannel(client client.Interface, nsQuery *NamespaceQuery,
 
 	go func() {
 		list, err := client.CoreV1().ConfigMaps(nsQuery.ToRequestParam()).
-			List(listEverything)
+			List(options)
 		var filteredItems []api.ConfigMap
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -603,6 +795,12 @@ type SecretListChannel struct {
 // both must be read numReads times.
 func GetSecretListChannel(client client.Interface, nsQuery *NamespaceQuery,
 	numReads int) SecretListChannel {
+	return GetSecretListChannelWithOptions(client, nsQuery, listEverything, numReads)
+}
+
+// GetSecretListChannelWithOptions is GetSecretListChannel plus listing options.
+func GetSecretListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery,
+	options metaV1.ListOptions, numReads int) SecretListChannel {
 
 	channel := SecretListChannel{
 		List:  make(chan *api.SecretList, numReads),
@@ -611,7 +809,7 @@ func GetSecretListChannel(client client.Interface, nsQuery *NamespaceQuery,
 
 	go func() {
 		list, err := client.CoreV1().Secrets(nsQuery.ToRequestParam()).
-			List(listEverything)
+			List(options)
 		var filteredItems []api.Secret
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -765,6 +963,13 @@ type PersistentVolumeClaimListChannel struct {
 // and errors that both must be read numReads times.
 func GetPersistentVolumeClaimListChannel(client client.Interface, nsQuery *NamespaceQuery,
 	numReads int) PersistentVolumeClaimListChannel {
+	return GetPersistentVolumeClaimListChannelWithOptions(client, nsQuery, listEverything, numReads)
+}
+
+// GetPersistentVolumeClaimListChannelWithOptions is GetPersistentVolumeClaimListChannel plus
+// listing options.
+func GetPersistentVolumeClaimListChannelWithOptions(client client.Interface,
+	nsQuery *NamespaceQuery, options metaV1.ListOptions, numReads int) PersistentVolumeClaimListChannel {
 
 	channel := PersistentVolumeClaimListChannel{
 		List:  make(chan *api.PersistentVolumeClaimList, numReads),
@@ -773,7 +978,7 @@ func GetPersistentVolumeClaimListChannel(client client.Interface, nsQuery *Names
 
 	go func() {
 		list, err := client.CoreV1().PersistentVolumeClaims(nsQuery.ToRequestParam()).
-			List(listEverything)
+			List(options)
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -819,7 +1024,7 @@ type PodMetricsChannel struct {
 
 // GetPodListMetricsChannel returns a pair of channels to MetricsByPod and errors that
 // both must be read numReads times.
-func GetPodListMetricsChannel(heapsterClient kdClient.HeapsterClient, pods []api.Pod,
+func GetPodListMetricsChannel(heapsterClient metricapi.MetricClient, pods []api.Pod,
 	numReads int) PodMetricsChannel {
 	channel := PodMetricsChannel{
 		MetricsByPod: make(chan *MetricsByPod, numReads),
@@ -845,7 +1050,7 @@ func GetPodListMetricsChannel(heapsterClient kdClient.HeapsterClient, pods []api
 
 // GetPodMetricsChannel returns a pair of channels to MetricsByPod and errors that
 // both must be read 1 time.
-func GetPodMetricsChannel(heapsterClient kdClient.HeapsterClient, name,
+func GetPodMetricsChannel(heapsterClient metricapi.MetricClient, name,
 	namespace string) PodMetricsChannel {
 	channel := PodMetricsChannel{
 		MetricsByPod: make(chan *MetricsByPod, 1),
@@ -940,6 +1145,81 @@ func GetStorageClassListChannel(client client.Interface, numReads int) StorageCl
 	return channel
 }
 
+// NetworkPolicyListChannel is a list and error channels to network policies.
+type NetworkPolicyListChannel struct {
+	List  chan *extensions.NetworkPolicyList
+	Error chan error
+}
+
+// GetNetworkPolicyListChannel returns a pair of channels to a network policy list and errors
+// that both must be read numReads times.
+func GetNetworkPolicyListChannel(client client.Interface, nsQuery *NamespaceQuery,
+	numReads int) NetworkPolicyListChannel {
+
+	channel := NetworkPolicyListChannel{
+		List:  make(chan *extensions.NetworkPolicyList, numReads),
+		Error: make(chan error, numReads),
+	}
+
+	go func() {
+		// Typed client for network policies is not available in this version of client-go.
+		list := &extensions.NetworkPolicyList{}
+		err := client.ExtensionsV1beta1().RESTClient().Get().
+			Namespace(nsQuery.ToRequestParam()).
+			Resource("networkpolicies").
+			Do().
+			Into(list)
+		var filteredItems []extensions.NetworkPolicy
+		for _, item := range list.Items {
+			if nsQuery.Matches(item.ObjectMeta.Namespace) {
+				filteredItems = append(filteredItems, item)
+			}
+		}
+		list.Items = filteredItems
+		for i := 0; i < numReads; i++ {
+			channel.List <- list
+			channel.Error <- err
+		}
+	}()
+
+	return channel
+}
+
+// PodDisruptionBudgetListChannel is a list and error channels to pod disruption budgets.
+type PodDisruptionBudgetListChannel struct {
+	List  chan *policy.PodDisruptionBudgetList
+	Error chan error
+}
+
+// GetPodDisruptionBudgetListChannel returns a pair of channels to a pod disruption budget list
+// and errors that both must be read numReads times.
+func GetPodDisruptionBudgetListChannel(client client.Interface, nsQuery *NamespaceQuery,
+	numReads int) PodDisruptionBudgetListChannel {
+
+	channel := PodDisruptionBudgetListChannel{
+		List:  make(chan *policy.PodDisruptionBudgetList, numReads),
+		Error: make(chan error, numReads),
+	}
+
+	go func() {
+		list, err := client.PolicyV1beta1().PodDisruptionBudgets(nsQuery.ToRequestParam()).
+			List(listEverything)
+		var filteredItems []policy.PodDisruptionBudget
+		for _, item := range list.Items {
+			if nsQuery.Matches(item.ObjectMeta.Namespace) {
+				filteredItems = append(filteredItems, item)
+			}
+		}
+		list.Items = filteredItems
+		for i := 0; i < numReads; i++ {
+			channel.List <- list
+			channel.Error <- err
+		}
+	}()
+
+	return channel
+}
+
 var listEverything = metaV1.ListOptions{
 	LabelSelector: labels.Everything().String(),
 	FieldSelector: fields.Everything().String(),
@@ -0,0 +1,190 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/labels"
+	api "k8s.io/client-go/pkg/api/v1"
+)
+
+// Namespace annotations read by PodNodeSelector and PodTolerationRestriction admission plugins.
+const (
+	// NamespaceNodeSelectorAnnotationKey holds node selector added to all pods of the namespace,
+	// e.g. pool=apps.
+	NamespaceNodeSelectorAnnotationKey = "scheduler.alpha.kubernetes.io/node-selector"
+
+	// NamespaceDefaultTolerationsAnnotationKey holds JSON encoded tolerations added to all pods of
+	// the namespace.
+	NamespaceDefaultTolerationsAnnotationKey = "scheduler.alpha.kubernetes.io/defaultTolerations"
+
+	// NamespaceTolerationsWhitelistAnnotationKey holds JSON encoded tolerations pods of the
+	// namespace are allowed to have.
+	NamespaceTolerationsWhitelistAnnotationKey = "scheduler.alpha.kubernetes.io/tolerationsWhitelist"
+)
+
+// NamespaceScheduling describes default scheduling of pods of a namespace enforced at admission.
+type NamespaceScheduling struct {
+	// Node selector merged into node selectors of pods.
+	NodeSelector map[string]string `json:"nodeSelector"`
+
+	// Tolerations added to tolerations of pods.
+	DefaultTolerations []api.Toleration `json:"defaultTolerations"`
+
+	// Tolerations pods are allowed to have. Empty means all tolerations are allowed.
+	TolerationsWhitelist []api.Toleration `json:"tolerationsWhitelist"`
+}
+
+// SchedulingAdmission describes how admission changes scheduling of pods created in a namespace.
+type SchedulingAdmission struct {
+	// Node selector of pods after admission.
+	NodeSelector map[string]string
+
+	// Tolerations of pods after admission.
+	Tolerations []api.Toleration
+
+	// Warnings about changes made at admission and reasons of rejection.
+	Warnings []string
+
+	// True if pods will be rejected by admission.
+	Rejected bool
+}
+
+// GetNamespaceScheduling returns default scheduling stored in given namespace annotations or nil
+// if there is none. Invalid annotation values are ignored.
+func GetNamespaceScheduling(annotations map[string]string) *NamespaceScheduling {
+	nodeSelector, hasNodeSelector := annotations[NamespaceNodeSelectorAnnotationKey]
+	defaults, hasDefaults := annotations[NamespaceDefaultTolerationsAnnotationKey]
+	whitelist, hasWhitelist := annotations[NamespaceTolerationsWhitelistAnnotationKey]
+	if !hasNodeSelector && !hasDefaults && !hasWhitelist {
+		return nil
+	}
+
+	scheduling := &NamespaceScheduling{
+		NodeSelector:         make(map[string]string),
+		DefaultTolerations:   make([]api.Toleration, 0),
+		TolerationsWhitelist: make([]api.Toleration, 0),
+	}
+	if selector, err := labels.ConvertSelectorToLabelsMap(nodeSelector); err == nil {
+		scheduling.NodeSelector = selector
+	} else {
+		log.Printf("Invalid namespace node selector %s: %s", nodeSelector, err)
+	}
+	if hasDefaults {
+		if err := json.Unmarshal([]byte(defaults), &scheduling.DefaultTolerations); err != nil {
+			log.Printf("Invalid namespace default tolerations %s: %s", defaults, err)
+		}
+	}
+	if hasWhitelist {
+		if err := json.Unmarshal([]byte(whitelist), &scheduling.TolerationsWhitelist); err != nil {
+			log.Printf("Invalid namespace tolerations whitelist %s: %s", whitelist, err)
+		}
+	}
+	return scheduling
+}
+
+// Admit returns scheduling of pods with given node selector and tolerations after admission to the
+// namespace. Node selector of the namespace is merged into pod node selector, conflicting selectors
+// are rejected. Default tolerations are added, unless pods already tolerate taints of the same key
+// and effect. Pods with tolerations that are not whitelisted are rejected.
+func (self *NamespaceScheduling) Admit(nodeSelector map[string]string,
+	tolerations []api.Toleration) SchedulingAdmission {
+	admission := SchedulingAdmission{
+		NodeSelector: make(map[string]string),
+		Tolerations:  append([]api.Toleration{}, tolerations...),
+		Warnings:     make([]string, 0),
+	}
+	for key, value := range nodeSelector {
+		admission.NodeSelector[key] = value
+	}
+
+	// iterate in order of keys, so that warnings are stable
+	keys := make([]string, 0)
+	for key := range self.NodeSelector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := self.NodeSelector[key]
+		podValue, ok := admission.NodeSelector[key]
+		if !ok {
+			admission.NodeSelector[key] = value
+			admission.Warnings = append(admission.Warnings,
+				fmt.Sprintf("Namespace node selector %s=%s will be added to pods", key, value))
+		} else if podValue != value {
+			admission.Rejected = true
+			admission.Warnings = append(admission.Warnings,
+				fmt.Sprintf("Node selector %s=%s conflicts with namespace node selector %s=%s, "+
+					"pods will be rejected", key, podValue, key, value))
+		}
+	}
+
+	for _, toleration := range self.DefaultTolerations {
+		if !hasTolerationOf(tolerations, toleration.Key, toleration.Effect) {
+			admission.Tolerations = append(admission.Tolerations, toleration)
+			admission.Warnings = append(admission.Warnings,
+				fmt.Sprintf("Namespace default toleration %s will be added to pods", formatToleration(toleration)))
+		}
+	}
+
+	if len(self.TolerationsWhitelist) > 0 {
+		for i := range admission.Tolerations {
+			if !isTolerationWhitelisted(&admission.Tolerations[i], self.TolerationsWhitelist) {
+				admission.Rejected = true
+				admission.Warnings = append(admission.Warnings,
+					fmt.Sprintf("Toleration %s is not whitelisted in the namespace, pods will be rejected",
+						formatToleration(admission.Tolerations[i])))
+			}
+		}
+	}
+
+	return admission
+}
+
+// hasTolerationOf returns true if there is a toleration of given taint key and effect.
+func hasTolerationOf(tolerations []api.Toleration, key string, effect api.TaintEffect) bool {
+	for _, toleration := range tolerations {
+		if toleration.Key == key && toleration.Effect == effect {
+			return true
+		}
+	}
+	return false
+}
+
+// isTolerationWhitelisted returns true if given toleration matches one of whitelisted ones.
+func isTolerationWhitelisted(toleration *api.Toleration, whitelist []api.Toleration) bool {
+	for i := range whitelist {
+		if whitelist[i].MatchToleration(toleration) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatToleration returns toleration in key=value:effect format known from kubectl taint.
+func formatToleration(toleration api.Toleration) string {
+	result := toleration.Key
+	if toleration.Value != "" {
+		result += "=" + toleration.Value
+	}
+	if toleration.Effect != "" {
+		result += ":" + string(toleration.Effect)
+	}
+	return result
+}
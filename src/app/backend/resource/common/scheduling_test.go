@@ -0,0 +1,106 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	api "k8s.io/client-go/pkg/api/v1"
+)
+
+func TestGetNamespaceScheduling(t *testing.T) {
+	cases := []struct {
+		annotations map[string]string
+		expected    *NamespaceScheduling
+	}{
+		{nil, nil},
+		{map[string]string{"foo": "bar"}, nil},
+		{
+			map[string]string{
+				NamespaceNodeSelectorAnnotationKey:         "pool=apps, zone=a",
+				NamespaceTolerationsWhitelistAnnotationKey: `[{"key": "dedicated", "operator": "Exists"}]`,
+			},
+			&NamespaceScheduling{
+				NodeSelector:         map[string]string{"pool": "apps", "zone": "a"},
+				DefaultTolerations:   []api.Toleration{},
+				TolerationsWhitelist: []api.Toleration{{Key: "dedicated", Operator: api.TolerationOpExists}},
+			},
+		},
+		{
+			map[string]string{
+				NamespaceNodeSelectorAnnotationKey:       "pool",
+				NamespaceDefaultTolerationsAnnotationKey: "invalid",
+			},
+			&NamespaceScheduling{
+				NodeSelector:         map[string]string{},
+				DefaultTolerations:   []api.Toleration{},
+				TolerationsWhitelist: []api.Toleration{},
+			},
+		},
+	}
+	for _, c := range cases {
+		actual := GetNamespaceScheduling(c.annotations)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetNamespaceScheduling(%#v) == \ngot: %#v, \nexpected %#v", c.annotations, actual,
+				c.expected)
+		}
+	}
+}
+
+func TestAdmit(t *testing.T) {
+	dedicated := api.Toleration{Key: "dedicated", Operator: api.TolerationOpExists,
+		Effect: api.TaintEffectNoSchedule}
+	gpu := api.Toleration{Key: "gpu", Operator: api.TolerationOpExists, Effect: api.TaintEffectNoSchedule}
+	scheduling := &NamespaceScheduling{
+		NodeSelector:         map[string]string{"pool": "apps"},
+		DefaultTolerations:   []api.Toleration{dedicated},
+		TolerationsWhitelist: []api.Toleration{dedicated},
+	}
+	cases := []struct {
+		nodeSelector map[string]string
+		tolerations  []api.Toleration
+		expected     SchedulingAdmission
+	}{
+		{
+			map[string]string{"pool": "apps"}, []api.Toleration{dedicated},
+			SchedulingAdmission{
+				NodeSelector: map[string]string{"pool": "apps"},
+				Tolerations:  []api.Toleration{dedicated},
+				Warnings:     []string{},
+			},
+		},
+		{
+			nil, []api.Toleration{gpu},
+			SchedulingAdmission{
+				NodeSelector: map[string]string{"pool": "apps"},
+				Tolerations:  []api.Toleration{gpu, dedicated},
+				Warnings: []string{
+					"Namespace node selector pool=apps will be added to pods",
+					"Namespace default toleration dedicated:NoSchedule will be added to pods",
+					"Toleration gpu:NoSchedule is not whitelisted in the namespace, pods will be rejected",
+				},
+				Rejected: true,
+			},
+		},
+	}
+	for _, c := range cases {
+		actual := scheduling.Admit(c.nodeSelector, c.tolerations)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Admit(%#v, %#v) == \ngot: %#v, \nexpected %#v", c.nodeSelector, c.tolerations, actual,
+				c.expected)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import api "k8s.io/client-go/pkg/api/v1"
+
+// ResourceStatus is a status summary of a list of workloads or pods. It counts all resources of the
+// list that match its filter, not only the current page. Every resource is counted in exactly one of running, pending,
+// failed and succeeded. Warning counts resources with warning events independently of their state.
+type ResourceStatus struct {
+	// Number of resources that are running, i.e. all their desired pods are running.
+	Running int `json:"running"`
+
+	// Number of resources that are waiting for some of their pods.
+	Pending int `json:"pending"`
+
+	// Number of resources that have failed pods.
+	Failed int `json:"failed"`
+
+	// Number of resources that have completed, i.e. all their desired pods have succeeded.
+	Succeeded int `json:"succeeded"`
+
+	// Number of resources that have warning events.
+	Warning int `json:"warning"`
+}
+
+// AddPodInfo counts resource with given aggregate information about its pods in the summary.
+func (self *ResourceStatus) AddPodInfo(podInfo PodInfo) {
+	switch {
+	case podInfo.Failed > 0:
+		self.Failed++
+	case podInfo.Desired > 0 && podInfo.Succeeded >= podInfo.Desired:
+		self.Succeeded++
+	case podInfo.Pending > 0 || podInfo.Running+podInfo.Succeeded < podInfo.Desired:
+		self.Pending++
+	default:
+		self.Running++
+	}
+
+	if len(podInfo.Warnings) > 0 {
+		self.Warning++
+	}
+}
+
+// AddPod counts given pod with given warning events in the summary. Pods in unknown phase are
+// not counted in any state.
+func (self *ResourceStatus) AddPod(pod *api.Pod, warnings []Event) {
+	switch pod.Status.Phase {
+	case api.PodRunning:
+		self.Running++
+	case api.PodPending:
+		self.Pending++
+	case api.PodFailed:
+		self.Failed++
+	case api.PodSucceeded:
+		self.Succeeded++
+	}
+
+	if len(warnings) > 0 {
+		self.Warning++
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	api "k8s.io/client-go/pkg/api/v1"
+)
+
+func TestResourceStatusAddPodInfo(t *testing.T) {
+	cases := []struct {
+		podInfos []PodInfo
+		expected ResourceStatus
+	}{
+		{nil, ResourceStatus{}},
+		{
+			[]PodInfo{
+				{Desired: 2, Running: 2},
+				{Desired: 2, Running: 1},
+				{Desired: 2, Running: 1, Pending: 1},
+				{Desired: 2, Running: 1, Failed: 1, Warnings: []Event{{Message: "BackOff"}}},
+				{Desired: 3, Succeeded: 3},
+				{Desired: 3, Running: 1, Succeeded: 2},
+			},
+			ResourceStatus{Running: 2, Pending: 2, Failed: 1, Succeeded: 1, Warning: 1},
+		},
+	}
+	for _, c := range cases {
+		actual := ResourceStatus{}
+		for _, podInfo := range c.podInfos {
+			actual.AddPodInfo(podInfo)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("AddPodInfo(%#v) == \ngot: %#v, \nexpected %#v", c.podInfos, actual, c.expected)
+		}
+	}
+}
+
+func TestResourceStatusAddPod(t *testing.T) {
+	actual := ResourceStatus{}
+	for _, phase := range []api.PodPhase{api.PodRunning, api.PodPending, api.PodFailed, api.PodSucceeded,
+		api.PodUnknown} {
+		actual.AddPod(&api.Pod{Status: api.PodStatus{Phase: phase}}, []Event{})
+	}
+	actual.AddPod(&api.Pod{Status: api.PodStatus{Phase: api.PodRunning}}, []Event{{Message: "Unhealthy"}})
+
+	expected := ResourceStatus{Running: 2, Pending: 1, Failed: 1, Succeeded: 1, Warning: 1}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("AddPod() == \ngot: %#v, \nexpected %#v", actual, expected)
+	}
+}
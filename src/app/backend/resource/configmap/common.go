@@ -31,6 +31,8 @@ func (self ConfigMapCell) GetProperty(name dataselect.PropertyName) dataselect.C
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.UIDProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.UID)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
@@ -45,8 +45,9 @@ type ConfigMap struct {
 func GetConfigMapList(client *client.Clientset, nsQuery *common.NamespaceQuery,
 	dsQuery *dataselect.DataSelectQuery) (*ConfigMapList, error) {
 	log.Printf("Getting list config maps in the namespace %s", nsQuery.ToRequestParam())
+	options := dsQuery.ListOptions()
 	channels := &common.ResourceChannels{
-		ConfigMapList: common.GetConfigMapListChannel(client, nsQuery, 1),
+		ConfigMapList: common.GetConfigMapListChannelWithOptions(client, nsQuery, options, 1),
 	}
 
 	return GetConfigMapListFromChannels(channels, dsQuery)
@@ -76,7 +77,7 @@ func getConfigMapList(configMaps []v1.ConfigMap, dsQuery *dataselect.DataSelectQ
 
 	configMapCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(configMaps), dsQuery)
 	configMaps = fromCells(configMapCells)
-	result.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+	result.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, result.ListMeta.TotalItems)
 
 	for _, item := range configMaps {
 		result.Items = append(result.Items,
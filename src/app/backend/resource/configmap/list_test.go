@@ -35,7 +35,7 @@ func TestGetConfigMapList(t *testing.T) {
 				{Data: map[string]string{"app": "my-name"}, ObjectMeta: metaV1.ObjectMeta{Name: "foo"}},
 			},
 			&ConfigMapList{
-				ListMeta: api.ListMeta{TotalItems: 1},
+				ListMeta: api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
 				Items: []ConfigMap{{
 					TypeMeta:   api.TypeMeta{Kind: "configmap"},
 					ObjectMeta: api.ObjectMeta{Name: "foo"},
@@ -0,0 +1,116 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"io"
+	"log"
+
+	remotecommandconsts "k8s.io/apimachinery/pkg/util/remotecommand"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// DefaultShellCommand is the command executed in containers when no command is given. It starts
+// bash if the image has it, and falls back to sh.
+var DefaultShellCommand = []string{"/bin/sh", "-c",
+	"command -v bash >/dev/null && exec bash || exec sh"}
+
+// AttachStreams are streams connected to the process running in a container. Stdin and Sizes
+// are optional. Stderr is not used when TTY is allocated, as the terminal merges it with stdout.
+type AttachStreams struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	TTY    bool
+	// Sizes delivers terminal resize events, used only when TTY is allocated.
+	Sizes remotecommand.TerminalSizeQueue
+}
+
+// AttachToContainer attaches given streams to the main process of the container, which must
+// already be running. It blocks until the process exits or one of the streams is closed.
+func AttachToContainer(client *client.Clientset, cfg *rest.Config, namespace, podID, container string,
+	streams AttachStreams) error {
+	log.Printf("Attaching to %s container of %s pod in %s namespace", container, podID, namespace)
+
+	req := client.CoreV1().RESTClient().Post().
+		Namespace(namespace).
+		Resource("pods").
+		Name(podID).
+		SubResource("attach").
+		VersionedParams(&v1.PodAttachOptions{
+			Container: container,
+			Stdin:     streams.Stdin != nil,
+			Stdout:    true,
+			Stderr:    !streams.TTY,
+			TTY:       streams.TTY,
+		}, scheme.ParameterCodec)
+
+	return stream(cfg, req, streams)
+}
+
+// ExecInContainer runs given command in the container with given streams connected to it. If no
+// command is given, an interactive shell is started. It blocks until the command exits or one of
+// the streams is closed.
+func ExecInContainer(client *client.Clientset, cfg *rest.Config, namespace, podID, container string,
+	command []string, streams AttachStreams) error {
+	log.Printf("Executing %v in %s container of %s pod in %s namespace", command, container, podID,
+		namespace)
+
+	if len(command) == 0 {
+		command = DefaultShellCommand
+	}
+
+	req := client.CoreV1().RESTClient().Post().
+		Namespace(namespace).
+		Resource("pods").
+		Name(podID).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     streams.Stdin != nil,
+			Stdout:    true,
+			Stderr:    !streams.TTY,
+			TTY:       streams.TTY,
+		}, scheme.ParameterCodec)
+
+	return stream(cfg, req, streams)
+}
+
+// stream runs remote command of given attach or exec request with given streams connected to it.
+func stream(cfg *rest.Config, req *rest.Request, streams AttachStreams) error {
+	executor, err := remotecommand.NewExecutor(cfg, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+
+	options := remotecommand.StreamOptions{
+		SupportedProtocols: remotecommandconsts.SupportedStreamingProtocols,
+		Stdin:              streams.Stdin,
+		Stdout:             streams.Stdout,
+		Tty:                streams.TTY,
+	}
+	if streams.TTY {
+		options.TerminalSizeQueue = streams.Sizes
+	} else {
+		options.Stderr = streams.Stderr
+	}
+
+	return executor.Stream(options)
+}
@@ -0,0 +1,71 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestExecInContainer(t *testing.T) {
+	cases := []struct {
+		command       []string
+		streams       AttachStreams
+		expectedQuery url.Values
+	}{
+		{
+			nil, AttachStreams{Stdin: strings.NewReader(""), TTY: true},
+			url.Values{"container": {"bar"}, "command": DefaultShellCommand, "stdin": {"true"},
+				"stdout": {"true"}, "tty": {"true"}},
+		},
+		{
+			[]string{"ls", "-l"}, AttachStreams{},
+			url.Values{"container": {"bar"}, "command": {"ls", "-l"}, "stdout": {"true"},
+				"stderr": {"true"}},
+		},
+	}
+	for _, c := range cases {
+		var path string
+		var query url.Values
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path, query = r.URL.Path, r.URL.Query()
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		cfg := &rest.Config{Host: server.URL}
+		k8sClient, err := client.NewForConfig(cfg)
+		if err != nil {
+			t.Fatalf("Cannot create client: %v", err)
+		}
+
+		err = ExecInContainer(k8sClient, cfg, "default", "foo", "bar", c.command, c.streams)
+		server.Close()
+
+		if err == nil {
+			t.Errorf("ExecInContainer(%#v) returns no error when exec is forbidden", c.command)
+		}
+		if path != "/api/v1/namespaces/default/pods/foo/exec" ||
+			!reflect.DeepEqual(query, c.expectedQuery) {
+			t.Errorf("ExecInContainer(%#v) requests %s?%#v, expected exec with query %#v",
+				c.command, path, query, c.expectedQuery)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+// DefaultContainerAnnotation is the annotation of a pod naming the container that should be used
+// by default when no container is specified, same as in kubectl.
+const DefaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+// sidecarContainers are names of containers commonly injected into pods next to the main
+// container. They are skipped when the default container is picked by convention.
+var sidecarContainers = map[string]bool{
+	"istio-proxy":     true,
+	"linkerd-proxy":   true,
+	"envoy":           true,
+	"vault-agent":     true,
+	"cloudsql-proxy":  true,
+	"fluentd":         true,
+	"fluent-bit":      true,
+	"oauth2-proxy":    true,
+	"kube-rbac-proxy": true,
+	"daprd":           true,
+}
+
+// GetDefaultContainer returns name of the container of given pod that should be used by default,
+// e.g. for logs. The container named in DefaultContainerAnnotation wins, if it exists. Otherwise
+// the first container that is not a well known sidecar is returned, falling back to the first one.
+func GetDefaultContainer(pod *v1.Pod) string {
+	if len(pod.Spec.Containers) == 0 {
+		return ""
+	}
+
+	if name, ok := pod.Annotations[DefaultContainerAnnotation]; ok {
+		for _, container := range pod.Spec.Containers {
+			if container.Name == name {
+				return name
+			}
+		}
+	}
+
+	for _, container := range pod.Spec.Containers {
+		if !sidecarContainers[container.Name] {
+			return container.Name
+		}
+	}
+
+	return pod.Spec.Containers[0].Name
+}
@@ -0,0 +1,77 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"reflect"
+	"testing"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+func podWithContainers(annotations map[string]string, names ...string) *v1.Pod {
+	pod := &v1.Pod{ObjectMeta: metaV1.ObjectMeta{Annotations: annotations}}
+	for _, name := range names {
+		pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{Name: name})
+	}
+	return pod
+}
+
+func TestGetDefaultContainer(t *testing.T) {
+	cases := []struct {
+		info     string
+		pod      *v1.Pod
+		expected string
+	}{
+		{"no containers", podWithContainers(nil), ""},
+		{"single container", podWithContainers(nil, "app"), "app"},
+		{"sidecar is skipped", podWithContainers(nil, "istio-proxy", "app", "other"), "app"},
+		{"only sidecars", podWithContainers(nil, "istio-proxy", "envoy"), "istio-proxy"},
+		{
+			"annotation",
+			podWithContainers(map[string]string{DefaultContainerAnnotation: "other"}, "app", "other"),
+			"other",
+		},
+		{
+			"annotation naming unknown container",
+			podWithContainers(map[string]string{DefaultContainerAnnotation: "missing"}, "app"),
+			"app",
+		},
+	}
+	for _, c := range cases {
+		actual := GetDefaultContainer(c.pod)
+		if actual != c.expected {
+			t.Errorf("Test Case: %s. GetDefaultContainer(%#v) == \ngot: %#v, \nexpected %#v",
+				c.info, c.pod, actual, c.expected)
+		}
+	}
+}
+
+func TestToPodContainerList(t *testing.T) {
+	pod := podWithContainers(nil, "envoy", "app")
+	pod.Spec.InitContainers = []v1.Container{{Name: "init"}}
+	expected := &PodContainerList{
+		Containers:          []string{"envoy", "app"},
+		InitContainers:      []string{"init"},
+		EphemeralContainers: []string{},
+		DefaultContainer:    "app",
+	}
+
+	actual := toPodContainerList(pod)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toPodContainerList(%#v) == \ngot: %#v, \nexpected %#v", pod, actual, expected)
+	}
+}
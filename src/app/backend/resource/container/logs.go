@@ -15,47 +15,124 @@
 package container
 
 import (
+	"bufio"
+	"encoding/json"
+	"io"
 	"io/ioutil"
+	"log"
+	"time"
 
+	"github.com/kubernetes/dashboard/src/app/backend/integration/logsource"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/logs"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	client "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/pkg/api/v1"
 )
 
+// Maximum number of historical log lines read from the log source.
+const historicalLogLinesLimit = 5000
+
+// Period of time historical logs of deleted pods are searched for.
+const historicalLogsPeriod = 7 * 24 * time.Hour
+
 // PodContainerList is a list of containers of a pod.
 type PodContainerList struct {
+	// Names of app containers, in the order of pod spec.
 	Containers []string `json:"containers"`
+
+	// Names of init containers.
+	InitContainers []string `json:"initContainers"`
+
+	// Names of ephemeral (debug) containers.
+	EphemeralContainers []string `json:"ephemeralContainers"`
+
+	// Name of the container that should be used by default, e.g. for logs.
+	DefaultContainer string `json:"defaultContainer"`
 }
 
-// GetPodContainers returns containers that a pod has.
-func GetPodContainers(client *client.Clientset, namespace, podID string) (*PodContainerList, error) {
-	pod, err := client.Pods(namespace).Get(podID, metaV1.GetOptions{})
+// ephemeralContainersSpec is a subset of pod spec not known to the API version used by the client.
+type ephemeralContainersSpec struct {
+	Spec struct {
+		EphemeralContainers []struct {
+			Name string `json:"name"`
+		} `json:"ephemeralContainers"`
+	} `json:"spec"`
+}
+
+// GetPodContainers returns containers that a pod has. Pod is read as raw JSON, so that ephemeral
+// containers are included even though they are not a part of the client's pod spec.
+func GetPodContainers(client client.Interface, namespace, podID string) (*PodContainerList, error) {
+	raw, err := common.JSONRequest(client.CoreV1().RESTClient().Get().
+		Namespace(namespace).
+		Resource("pods").
+		Name(podID)).
+		DoRaw()
 	if err != nil {
 		return nil, err
 	}
 
-	containers := &PodContainerList{Containers: make([]string, 0)}
+	pod := &v1.Pod{}
+	if err := json.Unmarshal(raw, pod); err != nil {
+		return nil, err
+	}
+	ephemeral := &ephemeralContainersSpec{}
+	if err := json.Unmarshal(raw, ephemeral); err != nil {
+		return nil, err
+	}
+
+	containers := toPodContainerList(pod)
+	for _, container := range ephemeral.Spec.EphemeralContainers {
+		containers.EphemeralContainers = append(containers.EphemeralContainers, container.Name)
+	}
+
+	return containers, nil
+}
+
+func toPodContainerList(pod *v1.Pod) *PodContainerList {
+	containers := &PodContainerList{
+		Containers:          make([]string, 0),
+		InitContainers:      make([]string, 0),
+		EphemeralContainers: make([]string, 0),
+		DefaultContainer:    GetDefaultContainer(pod),
+	}
 
 	for _, container := range pod.Spec.Containers {
 		containers.Containers = append(containers.Containers, container.Name)
 	}
 
-	return containers, nil
+	for _, container := range pod.Spec.InitContainers {
+		containers.InitContainers = append(containers.InitContainers, container.Name)
+	}
+
+	return containers
 }
 
 // GetPodLogs returns logs for particular pod and container. When container
-// is null, logs for the first one are returned.
-func GetPodLogs(client *client.Clientset, namespace, podID string, container string,
-	logSelector *logs.Selection) (*logs.LogDetails, error) {
+// is null, logs for the default one are returned (see GetDefaultContainer). When log source is configured, logs of previous
+// container instances are read from it and prepended to the live logs. Logs of deleted pods
+// are then also available, but only when container is specified. Log lines not matching
+// logFilter are dropped before the selection is applied.
+func GetPodLogs(client *client.Clientset, logSource logsource.LogSource, namespace, podID string,
+	container string, logSelector *logs.Selection, logFilter *logs.LogFilter) (*logs.LogDetails, error) {
 	pod, err := client.Pods(namespace).Get(podID, metaV1.GetOptions{})
 	if err != nil {
-		return nil, err
+		if !k8serrors.IsNotFound(err) || logSource == nil || len(container) == 0 {
+			return nil, err
+		}
+
+		historical, err := logSource.GetLogs(namespace, podID, container,
+			time.Now().Add(-historicalLogsPeriod), historicalLogLinesLimit)
+		if err != nil {
+			return nil, err
+		}
+		return constructLogsFromLines(podID, historical.Filter(logFilter), container, logSelector), nil
 	}
 
 	if len(container) == 0 {
-		container = pod.Spec.Containers[0].Name
+		container = GetDefaultContainer(pod)
 	}
 
 	logOptions := &v1.PodLogOptions{
@@ -70,7 +147,42 @@ func GetPodLogs(client *client.Clientset, namespace, podID string, container str
 		return nil, err
 	}
 
-	return ConstructLogs(podID, rawLogs, container, logSelector), nil
+	logLines := logs.ToLogLines(rawLogs)
+	if logSource != nil {
+		historical, err := logSource.GetLogs(namespace, podID, container, pod.CreationTimestamp.Time,
+			historicalLogLinesLimit)
+		if err != nil {
+			log.Printf("Couldn't get historical logs of %s pod: %s", podID, err)
+		} else {
+			logLines = mergeLogLines(historical, logLines)
+		}
+	}
+
+	return constructLogsFromLines(podID, logLines.Filter(logFilter), container, logSelector), nil
+}
+
+// mergeLogLines prepends historical log lines that are older than the first live log line to
+// the live log lines. Historical lines that overlap with live ones are dropped.
+func mergeLogLines(historical, live logs.LogLines) logs.LogLines {
+	if len(live) == 0 {
+		return historical
+	}
+
+	firstLive, err := time.Parse(time.RFC3339Nano, string(live[0].Timestamp))
+	if err != nil {
+		return live
+	}
+
+	merged := logs.LogLines{}
+	for _, line := range historical {
+		timestamp, err := time.Parse(time.RFC3339Nano, string(line.Timestamp))
+		if err != nil || !timestamp.Before(firstLive) {
+			continue
+		}
+		merged = append(merged, line)
+	}
+
+	return append(merged, live...)
 }
 
 // Construct a request for getting the logs for a pod and retrieves the logs.
@@ -98,9 +210,99 @@ func getRawPodLogs(client *client.Clientset, namespace, podID string, logOptions
 	return string(result), nil
 }
 
+// Maximum length of a single log line read when following logs. Longer lines end the stream.
+const maxFollowedLogLineLength = 1024 * 1024
+
+// FollowPodLogs follows log of given container, starting with its last tailLines lines. Every new
+// log line matching logFilter is passed to send, until the container stops, send returns an error
+// or stop is closed.
+func FollowPodLogs(client *client.Clientset, namespace, podID, container string, tailLines int64,
+	logFilter *logs.LogFilter, stop <-chan struct{}, send func(logs.LogLine) error) error {
+	log.Printf("Following logs of %s container of %s pod in %s namespace", container, podID,
+		namespace)
+
+	stream, err := openPodLogStream(client, namespace, podID, &v1.PodLogOptions{
+		Container:  container,
+		Follow:     true,
+		Timestamps: true,
+		TailLines:  &tailLines,
+	})
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	// Closing the stream is the only way to interrupt a blocked read.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-stop:
+			stream.Close()
+		case <-done:
+		}
+	}()
+
+	err = readLogLines(stream, logFilter, send)
+	select {
+	case <-stop:
+		return nil
+	default:
+		return err
+	}
+}
+
+// GetPodLogFile returns the whole log of given container as a stream of raw log, which has to be
+// closed after reading. With previous set, log of the previous instance of the container is
+// returned. If sinceTime is not nil, only lines logged after it are returned.
+func GetPodLogFile(client *client.Clientset, namespace, podID, container string, previous bool,
+	sinceTime *metaV1.Time) (io.ReadCloser, error) {
+	log.Printf("Getting log file of %s container of %s pod in %s namespace", container, podID,
+		namespace)
+
+	return openPodLogStream(client, namespace, podID, &v1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+		SinceTime: sinceTime,
+	})
+}
+
+// openPodLogStream opens stream of given pod log.
+func openPodLogStream(client *client.Clientset, namespace, podID string,
+	logOptions *v1.PodLogOptions) (io.ReadCloser, error) {
+	return client.Core().RESTClient().Get().
+		Namespace(namespace).
+		Name(podID).
+		Resource("pods").
+		SubResource("log").
+		VersionedParams(logOptions, scheme.ParameterCodec).
+		Stream()
+}
+
+// readLogLines passes lines of given raw log matching logFilter to send, until reader ends or send
+// returns an error.
+func readLogLines(reader io.Reader, logFilter *logs.LogFilter, send func(logs.LogLine) error) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFollowedLogLineLength)
+	for scanner.Scan() {
+		for _, line := range logs.ToLogLines(scanner.Text()).Filter(logFilter) {
+			if err := send(line); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
+
 // Build logs structure for given parameters.
 func ConstructLogs(podID string, rawLogs string, container string, logSelector *logs.Selection) *logs.LogDetails {
-	logLines, fromDate, toDate, logSelection := logs.ToLogLines(rawLogs).SelectLogs(logSelector)
+	return constructLogsFromLines(podID, logs.ToLogLines(rawLogs), container, logSelector)
+}
+
+func constructLogsFromLines(podID string, logLines logs.LogLines, container string,
+	logSelector *logs.Selection) *logs.LogDetails {
+	selectedLines, fromDate, toDate, logSelection := logLines.SelectLogs(logSelector)
+	previousPageToken, nextPageToken := logLines.PageTokens(logSelection)
 	info := logs.LogInfo{
 		PodName:       podID,
 		ContainerName: container,
@@ -108,8 +310,10 @@ func ConstructLogs(podID string, rawLogs string, container string, logSelector *
 		ToDate:        toDate,
 	}
 	return &logs.LogDetails{
-		Info:      info,
-		Selection: logSelection,
-		LogLines:  logLines,
+		Info:              info,
+		Selection:         logSelection,
+		LogLines:          selectedLines,
+		PreviousPageToken: previousPageToken,
+		NextPageToken:     nextPageToken,
 	}
 }
@@ -15,10 +15,19 @@
 package container
 
 import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/kubernetes/dashboard/src/app/backend/resource/logs"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 var log1 = logs.LogLine{
@@ -276,8 +285,128 @@ func TestGetLogs(t *testing.T) {
 	}
 	for _, c := range cases {
 		actual := ConstructLogs(c.podId, c.rawLogs, c.container, c.logSelector)
+		// Page tokens are opaque and covered by tests of logs package, only check presence of the
+		// next one. The previous one is missing for pages starting at the first line.
+		if hasNext := actual.NextPageToken != ""; hasNext != (len(actual.LogLines) > 0) {
+			t.Errorf("Test Case: %s.\nReceived page tokens: %#v, %#v", c.info, actual.PreviousPageToken,
+				actual.NextPageToken)
+		}
+		actual.PreviousPageToken, actual.NextPageToken = "", ""
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Test Case: %s.\nReceived: %#v \nExpected: %#v\n\n", c.info, actual, c.expected)
+		}
+	}
+}
+
+func TestMergeLogLines(t *testing.T) {
+	cases := []struct {
+		info             string
+		historical, live logs.LogLines
+		expected         logs.LogLines
+	}{
+		{
+			"no live logs",
+			logs.LogLines{{Timestamp: "2017-05-05T10:00:00Z", Content: "old"}},
+			logs.LogLines{},
+			logs.LogLines{{Timestamp: "2017-05-05T10:00:00Z", Content: "old"}},
+		},
+		{
+			"overlapping historical logs are dropped",
+			logs.LogLines{
+				{Timestamp: "2017-05-05T10:00:00Z", Content: "previous container"},
+				{Timestamp: "2017-05-05T10:00:01.5Z", Content: "current container"},
+			},
+			logs.LogLines{{Timestamp: "2017-05-05T10:00:01.5Z", Content: "current container"}},
+			logs.LogLines{
+				{Timestamp: "2017-05-05T10:00:00Z", Content: "previous container"},
+				{Timestamp: "2017-05-05T10:00:01.5Z", Content: "current container"},
+			},
+		},
+		{
+			"live logs without timestamps",
+			logs.LogLines{{Timestamp: "2017-05-05T10:00:00Z", Content: "old"}},
+			logs.LogLines{{Timestamp: "invalid", Content: "line"}},
+			logs.LogLines{{Timestamp: "invalid", Content: "line"}},
+		},
+	}
+	for _, c := range cases {
+		actual := mergeLogLines(c.historical, c.live)
 		if !reflect.DeepEqual(actual, c.expected) {
 			t.Errorf("Test Case: %s.\nReceived: %#v \nExpected: %#v\n\n", c.info, actual, c.expected)
 		}
 	}
 }
+
+func TestReadLogLines(t *testing.T) {
+	rawLogs := "2017-05-05T10:00:00Z started\n2017-05-05T10:00:01Z request failed\n" +
+		"2017-05-05T10:00:02Z request served\n"
+	cases := []struct {
+		filter   *logs.LogFilter
+		expected logs.LogLines
+	}{
+		{
+			&logs.LogFilter{},
+			logs.LogLines{
+				{Timestamp: "2017-05-05T10:00:00Z", Content: "started"},
+				{Timestamp: "2017-05-05T10:00:01Z", Content: "request failed"},
+				{Timestamp: "2017-05-05T10:00:02Z", Content: "request served"},
+			},
+		},
+		{
+			&logs.LogFilter{Search: "request"},
+			logs.LogLines{
+				{Timestamp: "2017-05-05T10:00:01Z", Content: "request failed"},
+				{Timestamp: "2017-05-05T10:00:02Z", Content: "request served"},
+			},
+		},
+	}
+	for _, c := range cases {
+		actual := logs.LogLines{}
+		err := readLogLines(strings.NewReader(rawLogs), c.filter, func(line logs.LogLine) error {
+			actual = append(actual, line)
+			return nil
+		})
+		if err != nil || !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("readLogLines(%#v) == \ngot: %#v, %v \nexpected %#v", c.filter, actual, err,
+				c.expected)
+		}
+	}
+}
+
+func TestGetPodLogFile(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/namespaces/default/pods/foo/log" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		query = r.URL.Query()
+		w.Write([]byte("started\nstopped\n"))
+	}))
+	defer server.Close()
+	k8sClient, err := client.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("Cannot create client: %v", err)
+	}
+	since := metaV1.NewTime(time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC))
+
+	logFile, err := GetPodLogFile(k8sClient, "default", "foo", "bar", true, &since)
+	if err != nil {
+		t.Fatalf("GetPodLogFile() returns error: %v", err)
+	}
+	defer logFile.Close()
+	raw, err := ioutil.ReadAll(logFile)
+
+	if err != nil || string(raw) != "started\nstopped\n" {
+		t.Errorf("GetPodLogFile() streams %#v, %v, expected the whole log", string(raw), err)
+	}
+	expectedQuery := url.Values{"container": {"bar"}, "previous": {"true"},
+		"sinceTime": {"2017-05-05T10:00:00Z"}}
+	if !reflect.DeepEqual(query, expectedQuery) {
+		t.Errorf("GetPodLogFile() requests log with query %#v, expected %#v", query, expectedQuery)
+	}
+
+	if _, err := GetPodLogFile(k8sClient, "default", "missing", "bar", false, nil); err == nil {
+		t.Error("GetPodLogFile() returns no error for missing pod")
+	}
+}
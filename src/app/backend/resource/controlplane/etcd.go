@@ -0,0 +1,129 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+// etcdComponentPrefix is the prefix of component statuses reported by API server for etcd members.
+const etcdComponentPrefix = "etcd-"
+
+// etcdAlarmPrefix is the prefix of etcd health reason reported when an alarm is raised, e.g.
+// "ALARM NOSPACE".
+const etcdAlarmPrefix = "ALARM "
+
+// EtcdStatus is health of etcd members as probed by API server.
+type EtcdStatus struct {
+	// False when API server doesn't report etcd health, e.g. as component statuses were removed
+	// or are forbidden.
+	Reachable bool `json:"reachable"`
+
+	Members []EtcdMember `json:"members"`
+
+	// Alarms raised on any of the members, e.g. NOSPACE or CORRUPT.
+	Alarms []string `json:"alarms"`
+}
+
+// EtcdMember is health of a single etcd member.
+type EtcdMember struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+
+	// Reason reported by etcd or error of the probe.
+	Reason string `json:"reason,omitempty"`
+
+	// Alarms raised on the member.
+	Alarms []string `json:"alarms"`
+}
+
+// etcdHealth is the response of etcd health endpoint, passed through by API server as message of
+// component status.
+type etcdHealth struct {
+	Health string `json:"health"`
+	Reason string `json:"reason"`
+}
+
+// getEtcdStatus returns health of etcd members from component statuses.
+func getEtcdStatus(client client.Interface) (EtcdStatus, error) {
+	status := EtcdStatus{Members: make([]EtcdMember, 0), Alarms: make([]string, 0)}
+
+	components, err := client.CoreV1().ComponentStatuses().List(metaV1.ListOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) || k8serrors.IsForbidden(err) {
+			return status, nil
+		}
+		return status, err
+	}
+
+	return toEtcdStatus(components.Items), nil
+}
+
+func toEtcdStatus(components []v1.ComponentStatus) EtcdStatus {
+	status := EtcdStatus{Members: make([]EtcdMember, 0), Alarms: make([]string, 0)}
+
+	alarms := make(map[string]bool)
+	for _, component := range components {
+		if !strings.HasPrefix(component.Name, etcdComponentPrefix) {
+			continue
+		}
+		status.Reachable = true
+
+		member := toEtcdMember(component)
+		for _, alarm := range member.Alarms {
+			alarms[alarm] = true
+		}
+		status.Members = append(status.Members, member)
+	}
+
+	for alarm := range alarms {
+		status.Alarms = append(status.Alarms, alarm)
+	}
+	sort.Strings(status.Alarms)
+	return status
+}
+
+func toEtcdMember(component v1.ComponentStatus) EtcdMember {
+	member := EtcdMember{Name: component.Name, Alarms: make([]string, 0)}
+
+	for _, condition := range component.Conditions {
+		if condition.Type != v1.ComponentHealthy {
+			continue
+		}
+		member.Healthy = condition.Status == v1.ConditionTrue
+		member.Reason = condition.Error
+
+		health := etcdHealth{}
+		if err := json.Unmarshal([]byte(condition.Message), &health); err != nil || health.Reason == "" {
+			continue
+		}
+		member.Reason = health.Reason
+		for _, reason := range strings.Split(health.Reason, ",") {
+			reason = strings.TrimSpace(reason)
+			if strings.HasPrefix(reason, etcdAlarmPrefix) {
+				member.Alarms = append(member.Alarms, strings.TrimPrefix(reason, etcdAlarmPrefix))
+			}
+		}
+	}
+
+	return member
+}
@@ -0,0 +1,85 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"reflect"
+	"testing"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+func TestToEtcdStatus(t *testing.T) {
+	cases := []struct {
+		components []v1.ComponentStatus
+		expected   EtcdStatus
+	}{
+		{
+			[]v1.ComponentStatus{{ObjectMeta: metaV1.ObjectMeta{Name: "scheduler"}}},
+			EtcdStatus{Members: []EtcdMember{}, Alarms: []string{}},
+		},
+		{
+			[]v1.ComponentStatus{
+				{
+					ObjectMeta: metaV1.ObjectMeta{Name: "etcd-0"},
+					Conditions: []v1.ComponentCondition{{Type: v1.ComponentHealthy,
+						Status: v1.ConditionTrue, Message: `{"health":"true"}`}},
+				},
+				{
+					ObjectMeta: metaV1.ObjectMeta{Name: "etcd-1"},
+					Conditions: []v1.ComponentCondition{{Type: v1.ComponentHealthy,
+						Status: v1.ConditionFalse, Message: `{"health":"false","reason":"ALARM NOSPACE"}`}},
+				},
+				{
+					ObjectMeta: metaV1.ObjectMeta{Name: "etcd-2"},
+					Conditions: []v1.ComponentCondition{{Type: v1.ComponentHealthy,
+						Status: v1.ConditionUnknown, Error: "connection refused"}},
+				},
+			},
+			EtcdStatus{
+				Reachable: true,
+				Members: []EtcdMember{
+					{Name: "etcd-0", Healthy: true, Alarms: []string{}},
+					{Name: "etcd-1", Reason: "ALARM NOSPACE", Alarms: []string{"NOSPACE"}},
+					{Name: "etcd-2", Reason: "connection refused", Alarms: []string{}},
+				},
+				Alarms: []string{"NOSPACE"},
+			},
+		},
+	}
+	for _, c := range cases {
+		actual := toEtcdStatus(c.components)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("toEtcdStatus(%#v) == \ngot %#v, \nexpected %#v", c.components, actual, c.expected)
+		}
+	}
+}
+
+func TestGetEtcdStatus(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(&v1.ComponentStatusList{Items: []v1.ComponentStatus{
+		{ObjectMeta: metaV1.ObjectMeta{Name: "scheduler"}},
+	}})
+
+	actual, err := getEtcdStatus(fakeClient)
+	if err != nil {
+		t.Fatalf("getEtcdStatus(client) == \ngot err %#v", err)
+	}
+	expected := EtcdStatus{Members: []EtcdMember{}, Alarms: []string{}}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getEtcdStatus(client) == \ngot %#v, \nexpected %#v", actual, expected)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"bufio"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	client "k8s.io/client-go/kubernetes"
+)
+
+// Health endpoints of the API server. Readyz replaced healthz in newer versions, which is used on
+// older clusters.
+const (
+	readyzPath  = "/readyz"
+	healthzPath = "/healthz"
+)
+
+// HealthCheck is a single check reported by API server health endpoint, e.g. ping, etcd or
+// poststarthook/start-informers.
+type HealthCheck struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+
+	// Reason of the failure as reported by API server, usually withheld for security reasons.
+	Reason string `json:"reason,omitempty"`
+}
+
+// getHealthChecks returns verbose checks of API server readiness endpoint, falling back to health
+// endpoint on older clusters. Returns path of the endpoint that was used together with checks.
+func getHealthChecks(client client.Interface) (string, []HealthCheck, error) {
+	path := readyzPath
+	raw, err := getVerbose(client, path)
+	if k8serrors.IsNotFound(err) {
+		path = healthzPath
+		raw, err = getVerbose(client, path)
+	}
+
+	// Failing checks make the endpoint respond with an error, checks are still listed in the body.
+	checks := parseHealthChecks(string(raw))
+	if err != nil && len(checks) == 0 {
+		return path, checks, err
+	}
+	return path, checks, nil
+}
+
+func getVerbose(client client.Interface, path string) ([]byte, error) {
+	return client.CoreV1().RESTClient().Get().AbsPath(path).Param("verbose", "").DoRaw()
+}
+
+// parseHealthChecks parses verbose output of health endpoints, with one check per line, e.g.
+// "[+]ping ok" or "[-]etcd failed: reason withheld". Lines that are not checks are ignored.
+func parseHealthChecks(output string) []HealthCheck {
+	checks := make([]HealthCheck, 0)
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) < 3 || line[0] != '[' || line[2] != ']' || (line[1] != '+' && line[1] != '-') {
+			continue
+		}
+
+		check := HealthCheck{Healthy: line[1] == '+'}
+		fields := strings.SplitN(line[3:], " ", 2)
+		check.Name = fields[0]
+		if !check.Healthy && len(fields) > 1 {
+			check.Reason = strings.TrimSpace(strings.TrimPrefix(fields[1], "failed:"))
+		}
+		checks = append(checks, check)
+	}
+
+	return checks
+}
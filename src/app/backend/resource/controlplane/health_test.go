@@ -0,0 +1,45 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseHealthChecks(t *testing.T) {
+	cases := []struct {
+		output   string
+		expected []HealthCheck
+	}{
+		{"", []HealthCheck{}},
+		{"ok", []HealthCheck{}},
+		{
+			"[+]ping ok\n[-]etcd failed: reason withheld\n[+]poststarthook/start-informers ok\n" +
+				"readyz check failed\n",
+			[]HealthCheck{
+				{Name: "ping", Healthy: true},
+				{Name: "etcd", Reason: "reason withheld"},
+				{Name: "poststarthook/start-informers", Healthy: true},
+			},
+		},
+	}
+	for _, c := range cases {
+		actual := parseHealthChecks(c.output)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("parseHealthChecks(%#v) == \ngot %#v, \nexpected %#v", c.output, actual, c.expected)
+		}
+	}
+}
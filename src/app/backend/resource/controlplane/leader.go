@@ -0,0 +1,189 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+)
+
+const (
+	// leaderNamespace is the namespace where control-plane components keep leader election locks.
+	leaderNamespace = "kube-system"
+
+	// leaderAnnotationKey is the annotation holding leader election record on endpoints and config
+	// map locks used before leases.
+	leaderAnnotationKey = "control-plane.alpha.kubernetes.io/leader"
+)
+
+// Sources of leader election records.
+const (
+	LeaderSourceLease     = "lease"
+	LeaderSourceEndpoints = "endpoints"
+	LeaderSourceConfigMap = "configmap"
+)
+
+// leaderComponents are control-plane components that run leader election, by name of their lock.
+var leaderComponents = []string{"kube-scheduler", "kube-controller-manager"}
+
+// ComponentLeader is the current leader of a control-plane component running leader election.
+type ComponentLeader struct {
+	Component string `json:"component"`
+
+	// False when no leader election lock was found, e.g. on managed clusters hiding control plane.
+	Found bool `json:"found"`
+
+	// Source of the record, one of lease, endpoints or configmap.
+	Source string `json:"source,omitempty"`
+
+	// Identity of the holder, usually host name of the instance followed by a unique id.
+	Holder string `json:"holder"`
+
+	LeaseDurationSeconds int32        `json:"leaseDurationSeconds"`
+	AcquireTime          *metaV1.Time `json:"acquireTime"`
+	RenewTime            *metaV1.Time `json:"renewTime"`
+	Transitions          int32        `json:"transitions"`
+
+	// True when the lease was not renewed within its duration, i.e. there might be no active
+	// leader.
+	Expired bool `json:"expired"`
+}
+
+// leaderRecord is the leader election record kept in annotation of endpoints and config maps.
+type leaderRecord struct {
+	HolderIdentity       string       `json:"holderIdentity"`
+	LeaseDurationSeconds int32        `json:"leaseDurationSeconds"`
+	AcquireTime          *metaV1.Time `json:"acquireTime"`
+	RenewTime            *metaV1.Time `json:"renewTime"`
+	LeaderTransitions    int32        `json:"leaderTransitions"`
+}
+
+// rawLease is a lease of coordination.k8s.io group, which is not known to the client.
+type rawLease struct {
+	Spec struct {
+		HolderIdentity       *string      `json:"holderIdentity"`
+		LeaseDurationSeconds *int32       `json:"leaseDurationSeconds"`
+		AcquireTime          *metaV1.Time `json:"acquireTime"`
+		RenewTime            *metaV1.Time `json:"renewTime"`
+		LeaseTransitions     *int32       `json:"leaseTransitions"`
+	} `json:"spec"`
+}
+
+// getLeaders returns leaders of control-plane components.
+func getLeaders(client client.Interface, now time.Time) ([]ComponentLeader, error) {
+	leaders := make([]ComponentLeader, 0)
+	for _, component := range leaderComponents {
+		leader, err := getLeader(client, component, now)
+		if err != nil {
+			return nil, err
+		}
+		leaders = append(leaders, *leader)
+	}
+	return leaders, nil
+}
+
+// getLeader returns leader of given component from its lease. Clusters not using leases keep the
+// record in annotation of endpoints or config map of the same name. Locks the user cannot read are
+// treated as missing, the same way etcd status is.
+func getLeader(client client.Interface, component string, now time.Time) (*ComponentLeader, error) {
+	leader := &ComponentLeader{Component: component}
+
+	raw, err := common.JSONRequest(client.CoreV1().RESTClient().Get().
+		AbsPath(fmt.Sprintf("/apis/coordination.k8s.io/v1/namespaces/%s/leases/%s", leaderNamespace,
+			component))).
+		DoRaw()
+	if err == nil {
+		lease := &rawLease{}
+		if err := json.Unmarshal(raw, lease); err != nil {
+			return nil, err
+		}
+		leader.Source = LeaderSourceLease
+		setLeaderRecord(leader, toLeaderRecord(lease), now)
+		return leader, nil
+	}
+	if !isLockMissing(err) {
+		return nil, err
+	}
+
+	endpoints, err := client.CoreV1().Endpoints(leaderNamespace).Get(component, metaV1.GetOptions{})
+	if err != nil && !isLockMissing(err) {
+		return nil, err
+	}
+	if err == nil && endpoints.Annotations[leaderAnnotationKey] != "" {
+		leader.Source = LeaderSourceEndpoints
+		return leader, parseLeaderAnnotation(leader, endpoints.Annotations[leaderAnnotationKey], now)
+	}
+
+	configMap, err := client.CoreV1().ConfigMaps(leaderNamespace).Get(component, metaV1.GetOptions{})
+	if err != nil && !isLockMissing(err) {
+		return nil, err
+	}
+	if err == nil && configMap.Annotations[leaderAnnotationKey] != "" {
+		leader.Source = LeaderSourceConfigMap
+		return leader, parseLeaderAnnotation(leader, configMap.Annotations[leaderAnnotationKey], now)
+	}
+
+	return leader, nil
+}
+
+// isLockMissing returns true when leader election lock does not exist or cannot be read.
+func isLockMissing(err error) bool {
+	return k8serrors.IsNotFound(err) || k8serrors.IsForbidden(err)
+}
+
+func parseLeaderAnnotation(leader *ComponentLeader, annotation string, now time.Time) error {
+	record := &leaderRecord{}
+	if err := json.Unmarshal([]byte(annotation), record); err != nil {
+		return err
+	}
+	setLeaderRecord(leader, record, now)
+	return nil
+}
+
+func toLeaderRecord(lease *rawLease) *leaderRecord {
+	record := &leaderRecord{
+		AcquireTime: lease.Spec.AcquireTime,
+		RenewTime:   lease.Spec.RenewTime,
+	}
+	if lease.Spec.HolderIdentity != nil {
+		record.HolderIdentity = *lease.Spec.HolderIdentity
+	}
+	if lease.Spec.LeaseDurationSeconds != nil {
+		record.LeaseDurationSeconds = *lease.Spec.LeaseDurationSeconds
+	}
+	if lease.Spec.LeaseTransitions != nil {
+		record.LeaderTransitions = *lease.Spec.LeaseTransitions
+	}
+	return record
+}
+
+func setLeaderRecord(leader *ComponentLeader, record *leaderRecord, now time.Time) {
+	leader.Found = true
+	leader.Holder = record.HolderIdentity
+	leader.LeaseDurationSeconds = record.LeaseDurationSeconds
+	leader.AcquireTime = record.AcquireTime
+	leader.RenewTime = record.RenewTime
+	leader.Transitions = record.LeaderTransitions
+
+	// Released leases have no holder, those are expired too.
+	leader.Expired = record.HolderIdentity == "" || record.RenewTime == nil ||
+		record.RenewTime.Add(time.Duration(record.LeaseDurationSeconds)*time.Second).Before(now)
+}
@@ -0,0 +1,118 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestParseLeaderAnnotation(t *testing.T) {
+	renewTime := metaV1.NewTime(time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC).Local())
+	cases := []struct {
+		annotation  string
+		now         time.Time
+		expected    *ComponentLeader
+		expectError bool
+	}{
+		{
+			`{"holderIdentity":"master-1","leaseDurationSeconds":15,` +
+				`"renewTime":"2017-05-05T10:00:00Z","leaderTransitions":2}`,
+			time.Date(2017, 5, 5, 10, 0, 10, 0, time.UTC),
+			&ComponentLeader{Component: "kube-scheduler", Found: true, Holder: "master-1",
+				LeaseDurationSeconds: 15, RenewTime: &renewTime, Transitions: 2},
+			false,
+		},
+		{
+			`{"holderIdentity":"master-1","leaseDurationSeconds":15,"renewTime":"2017-05-05T10:00:00Z"}`,
+			time.Date(2017, 5, 5, 10, 1, 0, 0, time.UTC),
+			&ComponentLeader{Component: "kube-scheduler", Found: true, Holder: "master-1",
+				LeaseDurationSeconds: 15, RenewTime: &renewTime, Expired: true},
+			false,
+		},
+		{"{", time.Time{}, &ComponentLeader{Component: "kube-scheduler"}, true},
+	}
+	for _, c := range cases {
+		actual := &ComponentLeader{Component: "kube-scheduler"}
+		err := parseLeaderAnnotation(actual, c.annotation, c.now)
+		if (err != nil) != c.expectError {
+			t.Errorf("parseLeaderAnnotation(%#v) returns error %v, expected error: %v", c.annotation, err,
+				c.expectError)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("parseLeaderAnnotation(%#v) == \ngot %#v, \nexpected %#v", c.annotation, actual,
+				c.expected)
+		}
+	}
+}
+
+func TestToLeaderRecord(t *testing.T) {
+	holder := "master-2"
+	duration := int32(15)
+	lease := &rawLease{}
+	lease.Spec.HolderIdentity = &holder
+	lease.Spec.LeaseDurationSeconds = &duration
+
+	expected := &leaderRecord{HolderIdentity: "master-2", LeaseDurationSeconds: 15}
+	actual := toLeaderRecord(lease)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toLeaderRecord(%#v) == \ngot %#v, \nexpected %#v", lease, actual, expected)
+	}
+}
+
+func TestGetLeaderForbidden(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/namespaces/kube-system/configmaps/kube-scheduler" {
+			w.Write([]byte(`{"kind": "ConfigMap", "apiVersion": "v1", "metadata": {"name": "kube-scheduler",
+				"annotations": {"control-plane.alpha.kubernetes.io/leader": "{\"holderIdentity\": \"master-1\"}"}}}`))
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"kind": "Status", "apiVersion": "v1", "status": "Failure", "reason": "Forbidden",
+			"code": 403}`))
+	}))
+	defer server.Close()
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("Cannot create client: %v", err)
+	}
+
+	cases := []struct {
+		component      string
+		expectedFound  bool
+		expectedSource string
+	}{
+		{"kube-scheduler", true, LeaderSourceConfigMap},
+		{"kube-controller-manager", false, ""},
+	}
+	for _, c := range cases {
+		leader, err := getLeader(client, c.component, time.Now())
+		if err != nil {
+			t.Fatalf("getLeader(%s) with forbidden locks returned error: %s", c.component, err)
+		}
+		if leader.Found != c.expectedFound || leader.Source != c.expectedSource {
+			t.Errorf("getLeader(%s) == %#v, expected found %t from %s", c.component, leader,
+				c.expectedFound, c.expectedSource)
+		}
+	}
+}
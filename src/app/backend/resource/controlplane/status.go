@@ -0,0 +1,88 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controlplane
+
+import (
+	"log"
+	"time"
+
+	client "k8s.io/client-go/kubernetes"
+)
+
+// ControlPlaneStatus aggregates health of control-plane components. Most useful on self-managed
+// clusters, managed ones usually hide parts of the control plane.
+type ControlPlaneStatus struct {
+	// True when all API server checks pass, all leaders are active and etcd members are healthy.
+	Healthy bool `json:"healthy"`
+
+	// Path of API server endpoint the checks come from, /readyz or /healthz on older clusters.
+	HealthEndpoint string `json:"healthEndpoint"`
+
+	// Error of reading API server checks, if any.
+	HealthError string `json:"healthError,omitempty"`
+
+	Checks  []HealthCheck     `json:"checks"`
+	Leaders []ComponentLeader `json:"leaders"`
+	Etcd    EtcdStatus        `json:"etcd"`
+}
+
+// GetControlPlaneStatus returns health checks of API server, leaders of scheduler and controller
+// manager and health of etcd members when reported by API server.
+func GetControlPlaneStatus(client client.Interface) (*ControlPlaneStatus, error) {
+	log.Print("Getting control plane status")
+
+	status := &ControlPlaneStatus{}
+
+	var err error
+	status.HealthEndpoint, status.Checks, err = getHealthChecks(client)
+	if err != nil {
+		status.HealthError = err.Error()
+	}
+
+	status.Leaders, err = getLeaders(client, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	status.Etcd, err = getEtcdStatus(client)
+	if err != nil {
+		return nil, err
+	}
+
+	status.Healthy = isHealthy(status)
+	return status, nil
+}
+
+func isHealthy(status *ControlPlaneStatus) bool {
+	if status.HealthError != "" || len(status.Etcd.Alarms) > 0 {
+		return false
+	}
+	for _, check := range status.Checks {
+		if !check.Healthy {
+			return false
+		}
+	}
+	for _, leader := range status.Leaders {
+		if leader.Found && leader.Expired {
+			return false
+		}
+	}
+	for _, member := range status.Etcd.Members {
+		if !member.Healthy {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,226 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cronjob
+
+import (
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/link"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sClient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	batch "k8s.io/client-go/pkg/apis/batch/v1"
+	batch2 "k8s.io/client-go/pkg/apis/batch/v2alpha1"
+)
+
+// Maximum number of missed runs counted, same limit as used by cron job controller.
+const maxMissedRuns = 100
+
+// Statuses of a cron job run.
+const (
+	RunStatusRunning   = "Running"
+	RunStatusSucceeded = "Succeeded"
+	RunStatusFailed    = "Failed"
+)
+
+// CronJobDetail is a presentation layer view of Kubernetes CronJob resource, with history of its
+// runs computed from owned jobs.
+type CronJobDetail struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	// Links to external tools configured in settings and in the links annotation.
+	Links []link.Link `json:"links,omitempty"`
+
+	Schedule                string `json:"schedule"`
+	Suspend                 bool   `json:"suspend"`
+	ConcurrencyPolicy       string `json:"concurrencyPolicy"`
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds"`
+
+	// Number of currently running jobs.
+	Active int `json:"active"`
+
+	// Last time a job was scheduled.
+	LastScheduleTime *metaV1.Time `json:"lastScheduleTime"`
+
+	// Next time a job will be scheduled. Nil when suspended or schedule can't be parsed.
+	NextScheduleTime *metaV1.Time `json:"nextScheduleTime"`
+
+	// Error of schedule parsing, if any.
+	ScheduleError string `json:"scheduleError,omitempty"`
+
+	// Number of runs that were not started within startingDeadlineSeconds since the last
+	// scheduled run, capped at 100. Always 0 when no deadline is set.
+	MissedRuns int `json:"missedRuns"`
+
+	// Most recent missed run.
+	LastMissedTime *metaV1.Time `json:"lastMissedTime"`
+
+	// Runs of the cron job that are still kept as jobs, newest first.
+	Runs []CronJobRun `json:"runs"`
+}
+
+// CronJobRun is a single run of a cron job.
+type CronJobRun struct {
+	JobName        string       `json:"jobName"`
+	Status         string       `json:"status"`
+	StartTime      *metaV1.Time `json:"startTime"`
+	CompletionTime *metaV1.Time `json:"completionTime"`
+
+	// Run duration in seconds, up to now for running jobs.
+	DurationSeconds int64 `json:"durationSeconds"`
+}
+
+// GetCronJobDetail returns cron job with its run history.
+func GetCronJobDetail(client k8sClient.Interface, namespace, name string) (*CronJobDetail, error) {
+	log.Printf("Getting details of %s cron job in %s namespace", name, namespace)
+
+	cronJob, err := client.BatchV2alpha1().CronJobs(namespace).Get(name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	channels := &common.ResourceChannels{
+		JobList: common.GetJobListChannel(client, common.NewSameNamespaceQuery(namespace), 1),
+	}
+
+	jobs := <-channels.JobList.List
+	if err := <-channels.JobList.Error; err != nil {
+		return nil, err
+	}
+
+	return toCronJobDetail(cronJob, jobs.Items, time.Now()), nil
+}
+
+func toCronJobDetail(cronJob *batch2.CronJob, jobs []batch.Job, now time.Time) *CronJobDetail {
+	detail := &CronJobDetail{
+		ObjectMeta:              api.NewObjectMeta(cronJob.ObjectMeta),
+		TypeMeta:                api.NewTypeMeta(api.ResourceKindCronJob),
+		Schedule:                cronJob.Spec.Schedule,
+		Suspend:                 cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend,
+		ConcurrencyPolicy:       string(cronJob.Spec.ConcurrencyPolicy),
+		StartingDeadlineSeconds: cronJob.Spec.StartingDeadlineSeconds,
+		Active:                  len(cronJob.Status.Active),
+		LastScheduleTime:        cronJob.Status.LastScheduleTime,
+		Runs:                    make([]CronJobRun, 0),
+	}
+
+	for _, job := range jobs {
+		if isOwnedBy(&job, cronJob) {
+			detail.Runs = append(detail.Runs, toCronJobRun(&job, now))
+		}
+	}
+	sort.Sort(runsByStartTime(detail.Runs))
+
+	schedule, err := parseSchedule(cronJob.Spec.Schedule)
+	if err != nil {
+		detail.ScheduleError = err.Error()
+		return detail
+	}
+
+	if !detail.Suspend {
+		if next := schedule.next(now); !next.IsZero() {
+			nextTime := metaV1.NewTime(next)
+			detail.NextScheduleTime = &nextTime
+		}
+	}
+
+	if cronJob.Spec.StartingDeadlineSeconds != nil && !detail.Suspend {
+		since := cronJob.CreationTimestamp.Time
+		if cronJob.Status.LastScheduleTime != nil {
+			since = cronJob.Status.LastScheduleTime.Time
+		}
+		deadline := now.Add(-time.Duration(*cronJob.Spec.StartingDeadlineSeconds) * time.Second)
+
+		for t := schedule.next(since); !t.IsZero() && t.Before(deadline); t = schedule.next(t) {
+			missedTime := metaV1.NewTime(t)
+			detail.LastMissedTime = &missedTime
+			detail.MissedRuns++
+			if detail.MissedRuns >= maxMissedRuns {
+				break
+			}
+		}
+	}
+
+	return detail
+}
+
+// isOwnedBy checks whether job was created by cron job. Jobs created by older controllers have no
+// owner reference, those are recognized by name, which is the cron job name followed by hash of
+// the scheduled time.
+func isOwnedBy(job *batch.Job, cronJob *batch2.CronJob) bool {
+	for _, ref := range job.OwnerReferences {
+		if ref.UID == cronJob.UID {
+			return true
+		}
+	}
+	if len(job.OwnerReferences) > 0 || !strings.HasPrefix(job.Name, cronJob.Name+"-") {
+		return false
+	}
+	_, err := strconv.ParseUint(strings.TrimPrefix(job.Name, cronJob.Name+"-"), 10, 64)
+	return err == nil
+}
+
+func toCronJobRun(job *batch.Job, now time.Time) CronJobRun {
+	run := CronJobRun{
+		JobName:        job.Name,
+		Status:         RunStatusRunning,
+		StartTime:      job.Status.StartTime,
+		CompletionTime: job.Status.CompletionTime,
+	}
+
+	end := now
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != v1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batch.JobComplete:
+			run.Status = RunStatusSucceeded
+		case batch.JobFailed:
+			run.Status = RunStatusFailed
+			if run.CompletionTime == nil {
+				// Copy the time, the loop variable is reused by the next iteration.
+				t := condition.LastTransitionTime
+				run.CompletionTime = &t
+			}
+		}
+	}
+	if run.CompletionTime != nil {
+		end = run.CompletionTime.Time
+	}
+	if run.StartTime != nil {
+		run.DurationSeconds = int64(end.Sub(run.StartTime.Time) / time.Second)
+	}
+
+	return run
+}
+
+type runsByStartTime []CronJobRun
+
+func (self runsByStartTime) Len() int      { return len(self) }
+func (self runsByStartTime) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+func (self runsByStartTime) Less(i, j int) bool {
+	if self[i].StartTime == nil || self[j].StartTime == nil {
+		return self[j].StartTime == nil && self[i].StartTime != nil
+	}
+	return self[j].StartTime.Before(*self[i].StartTime)
+}
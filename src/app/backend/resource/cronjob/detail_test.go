@@ -0,0 +1,130 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cronjob
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/pkg/api/v1"
+	batch "k8s.io/client-go/pkg/apis/batch/v1"
+	batch2 "k8s.io/client-go/pkg/apis/batch/v2alpha1"
+)
+
+func metaTime(t time.Time) *metaV1.Time {
+	result := metaV1.NewTime(t)
+	return &result
+}
+
+func TestToCronJobDetail(t *testing.T) {
+	now := time.Date(2017, 5, 5, 10, 30, 0, 0, time.UTC)
+	deadline := int64(300)
+	cronJob := &batch2.CronJob{
+		ObjectMeta: metaV1.ObjectMeta{Name: "backup", Namespace: "default", UID: types.UID("uid")},
+		Spec: batch2.CronJobSpec{
+			Schedule:                "*/10 * * * *",
+			StartingDeadlineSeconds: &deadline,
+		},
+		Status: batch2.CronJobStatus{
+			LastScheduleTime: metaTime(now.Add(-30 * time.Minute)),
+		},
+	}
+	jobs := []batch.Job{
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "backup-1", OwnerReferences: []metaV1.OwnerReference{{UID: "uid"}}},
+			Status: batch.JobStatus{
+				StartTime:      metaTime(now.Add(-90 * time.Minute)),
+				CompletionTime: metaTime(now.Add(-88 * time.Minute)),
+				Conditions:     []batch.JobCondition{{Type: batch.JobComplete, Status: v1.ConditionTrue}},
+			},
+		},
+		{
+			// Created by controller not setting owner references.
+			ObjectMeta: metaV1.ObjectMeta{Name: "backup-2"},
+			Status: batch.JobStatus{
+				StartTime: metaTime(now.Add(-30 * time.Minute)),
+				Conditions: []batch.JobCondition{{Type: batch.JobFailed, Status: v1.ConditionTrue,
+					LastTransitionTime: metaV1.NewTime(now.Add(-25 * time.Minute))}},
+			},
+		},
+		{ObjectMeta: metaV1.ObjectMeta{Name: "backup-other"}},
+		{ObjectMeta: metaV1.ObjectMeta{Name: "backup-3", OwnerReferences: []metaV1.OwnerReference{{UID: "other"}}}},
+	}
+
+	expected := &CronJobDetail{
+		ObjectMeta:              api.ObjectMeta{Name: "backup", Namespace: "default"},
+		TypeMeta:                api.TypeMeta{Kind: api.ResourceKindCronJob},
+		Schedule:                "*/10 * * * *",
+		StartingDeadlineSeconds: &deadline,
+		LastScheduleTime:        metaTime(now.Add(-30 * time.Minute)),
+		NextScheduleTime:        metaTime(now.Add(10 * time.Minute)),
+		// Runs at 10:10 and 10:20 were not started, 10:30 is still within the deadline.
+		MissedRuns:     2,
+		LastMissedTime: metaTime(now.Add(-10 * time.Minute)),
+		Runs: []CronJobRun{
+			{
+				JobName:         "backup-2",
+				Status:          RunStatusFailed,
+				StartTime:       metaTime(now.Add(-30 * time.Minute)),
+				CompletionTime:  metaTime(now.Add(-25 * time.Minute)),
+				DurationSeconds: 300,
+			},
+			{
+				JobName:         "backup-1",
+				Status:          RunStatusSucceeded,
+				StartTime:       metaTime(now.Add(-90 * time.Minute)),
+				CompletionTime:  metaTime(now.Add(-88 * time.Minute)),
+				DurationSeconds: 120,
+			},
+		},
+	}
+
+	actual := toCronJobDetail(cronJob, jobs, now)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toCronJobDetail() == \ngot: %#v, \nexpected %#v", actual, expected)
+	}
+}
+
+func TestToCronJobRunFailedWithLaterConditions(t *testing.T) {
+	now := time.Date(2017, 5, 5, 10, 30, 0, 0, time.UTC)
+	job := &batch.Job{
+		ObjectMeta: metaV1.ObjectMeta{Name: "backup-1"},
+		Status: batch.JobStatus{
+			StartTime: metaTime(now.Add(-30 * time.Minute)),
+			Conditions: []batch.JobCondition{
+				{Type: batch.JobFailed, Status: v1.ConditionTrue,
+					LastTransitionTime: metaV1.NewTime(now.Add(-25 * time.Minute))},
+				{Type: batch.JobComplete, Status: v1.ConditionFalse,
+					LastTransitionTime: metaV1.NewTime(now.Add(-20 * time.Minute))},
+			},
+		},
+	}
+	expected := CronJobRun{
+		JobName:         "backup-1",
+		Status:          RunStatusFailed,
+		StartTime:       metaTime(now.Add(-30 * time.Minute)),
+		CompletionTime:  metaTime(now.Add(-25 * time.Minute)),
+		DurationSeconds: 300,
+	}
+
+	actual := toCronJobRun(job, now)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toCronJobRun() == \ngot: %#v, \nexpected %#v", actual, expected)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cronjob
+
+import (
+	"log"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sClient "k8s.io/client-go/kubernetes"
+	batch2 "k8s.io/client-go/pkg/apis/batch/v2alpha1"
+)
+
+// CronJobList contains a list of cron jobs in the cluster.
+type CronJobList struct {
+	ListMeta api.ListMeta `json:"listMeta"`
+
+	// Unordered list of Cron Jobs.
+	CronJobs []CronJob `json:"cronJobs"`
+}
+
+// CronJob is a presentation layer view of Kubernetes CronJob resource.
+type CronJob struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	Schedule string `json:"schedule"`
+	Suspend  bool   `json:"suspend"`
+
+	// Number of currently running jobs.
+	Active int `json:"active"`
+
+	// Last time a job was scheduled.
+	LastScheduleTime *metaV1.Time `json:"lastScheduleTime"`
+}
+
+// GetCronJobList returns a list of all cron jobs in the cluster.
+func GetCronJobList(client k8sClient.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*CronJobList, error) {
+	log.Print("Getting list of all cron jobs in the cluster")
+
+	channels := &common.ResourceChannels{
+		CronJobList: common.GetCronJobListChannel(client, nsQuery, 1),
+	}
+
+	cronJobs := <-channels.CronJobList.List
+	if err := <-channels.CronJobList.Error; err != nil {
+		return nil, err
+	}
+
+	return toCronJobList(cronJobs.Items, dsQuery), nil
+}
+
+func toCronJobList(cronJobs []batch2.CronJob, dsQuery *dataselect.DataSelectQuery) *CronJobList {
+	cronJobList := &CronJobList{
+		CronJobs: make([]CronJob, 0),
+		ListMeta: api.ListMeta{TotalItems: len(cronJobs)},
+	}
+
+	cronJobCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(cronJobs), dsQuery)
+	cronJobs = fromCells(cronJobCells)
+	cronJobList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, cronJobList.ListMeta.TotalItems)
+
+	for _, cronJob := range cronJobs {
+		cronJobList.CronJobs = append(cronJobList.CronJobs, toCronJob(&cronJob))
+	}
+
+	return cronJobList
+}
+
+func toCronJob(cronJob *batch2.CronJob) CronJob {
+	return CronJob{
+		ObjectMeta:       api.NewObjectMeta(cronJob.ObjectMeta),
+		TypeMeta:         api.NewTypeMeta(api.ResourceKindCronJob),
+		Schedule:         cronJob.Spec.Schedule,
+		Suspend:          cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend,
+		Active:           len(cronJob.Status.Active),
+		LastScheduleTime: cronJob.Status.LastScheduleTime,
+	}
+}
+
+// The code below allows to perform complex data section on []batch2.CronJob
+
+type CronJobCell batch2.CronJob
+
+func (self CronJobCell) GetProperty(name dataselect.PropertyName) dataselect.ComparableValue {
+	switch name {
+	case dataselect.NameProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Name)
+	case dataselect.CreationTimestampProperty:
+		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
+	case dataselect.NamespaceProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	default:
+		// if name is not supported then just return a constant dummy value, sort will have no effect.
+		return nil
+	}
+}
+
+func toCells(std []batch2.CronJob) []dataselect.DataCell {
+	cells := make([]dataselect.DataCell, len(std))
+	for i := range std {
+		cells[i] = CronJobCell(std[i])
+	}
+	return cells
+}
+
+func fromCells(cells []dataselect.DataCell) []batch2.CronJob {
+	std := make([]batch2.CronJob, len(cells))
+	for i := range std {
+		std[i] = batch2.CronJob(cells[i].(CronJobCell))
+	}
+	return std
+}
@@ -0,0 +1,80 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cronjob
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/pkg/api/v1"
+	batch2 "k8s.io/client-go/pkg/apis/batch/v2alpha1"
+)
+
+func TestGetCronJobList(t *testing.T) {
+	suspend := true
+	cases := []struct {
+		cronJobList *batch2.CronJobList
+		expected    *CronJobList
+	}{
+		{
+			&batch2.CronJobList{Items: []batch2.CronJob{
+				{
+					ObjectMeta: metaV1.ObjectMeta{Name: "cron-1", Namespace: "ns-1"},
+					Spec:       batch2.CronJobSpec{Schedule: "*/5 * * * *"},
+					Status:     batch2.CronJobStatus{Active: []v1.ObjectReference{{Name: "cron-1-1"}}},
+				},
+				{
+					ObjectMeta: metaV1.ObjectMeta{Name: "cron-2", Namespace: "ns-1"},
+					Spec:       batch2.CronJobSpec{Schedule: "@daily", Suspend: &suspend},
+				},
+			}},
+			&CronJobList{
+				ListMeta: api.ListMeta{TotalItems: 2, TotalBeforeFilter: 2},
+				CronJobs: []CronJob{
+					{
+						ObjectMeta: api.ObjectMeta{Name: "cron-1", Namespace: "ns-1"},
+						TypeMeta:   api.TypeMeta{Kind: api.ResourceKindCronJob},
+						Schedule:   "*/5 * * * *",
+						Active:     1,
+					},
+					{
+						ObjectMeta: api.ObjectMeta{Name: "cron-2", Namespace: "ns-1"},
+						TypeMeta:   api.TypeMeta{Kind: api.ResourceKindCronJob},
+						Schedule:   "@daily",
+						Suspend:    true,
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		fakeClient := fake.NewSimpleClientset(c.cronJobList)
+
+		actual, err := GetCronJobList(fakeClient, common.NewNamespaceQuery(nil), dataselect.NoDataSelect)
+		if err != nil {
+			t.Errorf("GetCronJobList(%#v) == \ngot err %#v", c.cronJobList, err)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetCronJobList(%#v) == \ngot %#v, \nexpected %#v", c.cronJobList, actual,
+				c.expected)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cronjob
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// schedule is a parsed standard cron schedule, e.g. "*/15 0-6 * * MON-FRI". Each field is a bit
+// set of matching values.
+type schedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+	// Whether day of month or day of week field is "*". When neither is, a day matches if any of
+	// them matches, as in cron.
+	dayOfMonthStar, dayOfWeekStar bool
+}
+
+// Maximum period searched for the next run, schedules like "0 0 30 2 *" never run.
+const maxScheduleSearchPeriod = 5 * 366 * 24 * time.Hour
+
+type scheduleField struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField     = scheduleField{min: 0, max: 59}
+	hourField       = scheduleField{min: 0, max: 23}
+	dayOfMonthField = scheduleField{min: 1, max: 31}
+	monthField      = scheduleField{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Both 0 and 7 are Sunday.
+	dayOfWeekField = scheduleField{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var scheduleDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// parseSchedule parses standard five field cron schedule or one of the @ descriptors.
+func parseSchedule(spec string) (*schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if descriptor, ok := scheduleDescriptors[strings.ToLower(spec)]; ok {
+		spec = descriptor
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("Expected 5 fields in schedule %q, found %d", spec, len(fields))
+	}
+
+	result := &schedule{}
+	var err error
+	if result.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if result.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if result.dayOfMonth, err = dayOfMonthField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if result.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if result.dayOfWeek, err = dayOfWeekField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	// Sunday can be given as 7.
+	if result.dayOfWeek&(1<<7) != 0 {
+		result.dayOfWeek |= 1
+	}
+	result.dayOfMonthStar = isStar(fields[2])
+	result.dayOfWeekStar = isStar(fields[4])
+
+	return result, nil
+}
+
+func isStar(field string) bool {
+	return field == "*" || field == "?"
+}
+
+// parse parses comma separated list of values, ranges and steps, e.g. "1,10-20/2,*/15".
+func (self scheduleField) parse(field string) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rangePart, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			var err error
+			rangePart = item[:i]
+			if step, err = strconv.Atoi(item[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("Invalid step in schedule field %q", field)
+			}
+		}
+
+		from, to := self.min, self.max
+		if !isStar(rangePart) {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if from, err = self.parseValue(bounds[0]); err != nil {
+				return 0, err
+			}
+			to = from
+			if len(bounds) == 2 {
+				if to, err = self.parseValue(bounds[1]); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				// "a/step" means from a to maximum.
+				to = self.max
+			}
+		}
+		if from > to {
+			return 0, fmt.Errorf("Invalid range in schedule field %q", field)
+		}
+
+		for value := from; value <= to; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+func (self scheduleField) parseValue(value string) (int, error) {
+	if number, ok := self.names[strings.ToLower(value)]; ok {
+		return number, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < self.min || number > self.max {
+		return 0, fmt.Errorf("Invalid value %q in schedule, expected %d-%d", value, self.min, self.max)
+	}
+	return number, nil
+}
+
+// next returns the first time matching the schedule strictly after given time, or zero time if
+// the schedule does not match anything in the near future.
+func (self *schedule) next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxScheduleSearchPeriod)
+
+	for t.Before(limit) {
+		if self.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !self.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if self.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if self.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (self *schedule) matchesDay(t time.Time) bool {
+	dayOfMonth := self.dayOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := self.dayOfWeek&(1<<uint(t.Weekday())) != 0
+	if self.dayOfMonthStar || self.dayOfWeekStar {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
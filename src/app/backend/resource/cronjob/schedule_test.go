@@ -0,0 +1,60 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cronjob
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScheduleNext(t *testing.T) {
+	// Friday.
+	after := time.Date(2017, 5, 5, 10, 7, 30, 0, time.UTC)
+	cases := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2017, 5, 5, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2017, 5, 5, 10, 15, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2017, 5, 5, 13, 0, 0, 0, time.UTC)},
+		{"30 2 * * MON-FRI", time.Date(2017, 5, 8, 2, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2017, 5, 7, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2017, 5, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 * 1", time.Date(2017, 5, 8, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, c := range cases {
+		schedule, err := parseSchedule(c.spec)
+		if err != nil {
+			t.Errorf("parseSchedule(%#v) returns error %v", c.spec, err)
+			continue
+		}
+		actual := schedule.next(after)
+		if !actual.Equal(c.expected) {
+			t.Errorf("parseSchedule(%#v).next(%s) == \ngot: %s, \nexpected %s",
+				c.spec, after, actual, c.expected)
+		}
+	}
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "0 0 * foo *"} {
+		if _, err := parseSchedule(spec); err == nil {
+			t.Errorf("parseSchedule(%#v) expected to return error", spec)
+		}
+	}
+}
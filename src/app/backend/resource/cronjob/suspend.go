@@ -0,0 +1,44 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cronjob
+
+import (
+	"fmt"
+	"log"
+
+	"k8s.io/apimachinery/pkg/types"
+	k8sClient "k8s.io/client-go/kubernetes"
+)
+
+// CronJobSuspendStatus is the result of suspending or resuming a cron job.
+type CronJobSuspendStatus struct {
+	Suspended bool `json:"suspended"`
+}
+
+// SuspendCronJob sets suspend field of cron job spec. Suspended cron jobs don't schedule new
+// runs, runs already started are left running.
+func SuspendCronJob(client k8sClient.Interface, namespace, name string, suspend bool) (
+	*CronJobSuspendStatus, error) {
+	log.Printf("Setting suspend of %s cron job in %s namespace to %t", name, namespace, suspend)
+
+	patch := fmt.Sprintf(`{"spec":{"suspend":%t}}`, suspend)
+	cronJob, err := client.BatchV2alpha1().CronJobs(namespace).Patch(name, types.MergePatchType,
+		[]byte(patch))
+	if err != nil {
+		return nil, err
+	}
+
+	return &CronJobSuspendStatus{Suspended: cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend}, nil
+}
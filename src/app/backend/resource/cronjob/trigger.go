@@ -0,0 +1,96 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cronjob
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sClient "k8s.io/client-go/kubernetes"
+	batch "k8s.io/client-go/pkg/apis/batch/v1"
+	batch2 "k8s.io/client-go/pkg/apis/batch/v2alpha1"
+)
+
+// InstantiateAnnotationKey is set on jobs created manually from cron job, same as the one set by
+// kubectl create job --from.
+const InstantiateAnnotationKey = "cronjob.kubernetes.io/instantiate"
+
+// maxJobNameLength is the maximum length of job name. Job name is the value of the job-name label
+// of its pods, so it's limited to the length of label values.
+const maxJobNameLength = 63
+
+// TriggerCronJob runs cron job right away by creating a job from its job template. The job is
+// owned by the cron job, so it is listed in its runs and cleaned up with its history.
+func TriggerCronJob(client k8sClient.Interface, namespace, name string) (*CronJobRun, error) {
+	log.Printf("Triggering %s cron job in %s namespace", name, namespace)
+
+	cronJob, err := client.BatchV2alpha1().CronJobs(namespace).Get(name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job, err := client.BatchV1().Jobs(namespace).Create(newManualJob(cronJob, now))
+	if err != nil {
+		return nil, err
+	}
+
+	run := toCronJobRun(job, now)
+	return &run, nil
+}
+
+func newManualJob(cronJob *batch2.CronJob, now time.Time) *batch.Job {
+	template := cronJob.Spec.JobTemplate
+
+	annotations := map[string]string{InstantiateAnnotationKey: "manual"}
+	for key, value := range template.Annotations {
+		annotations[key] = value
+	}
+
+	labels := make(map[string]string, len(template.Labels))
+	for key, value := range template.Labels {
+		labels[key] = value
+	}
+
+	controller := true
+	return &batch.Job{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:        getManualJobName(cronJob.Name, now),
+			Namespace:   cronJob.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+			OwnerReferences: []metaV1.OwnerReference{{
+				APIVersion: batch2.SchemeGroupVersion.String(),
+				Kind:       "CronJob",
+				Name:       cronJob.Name,
+				UID:        cronJob.UID,
+				Controller: &controller,
+			}},
+		},
+		Spec: template.Spec,
+	}
+}
+
+// getManualJobName returns name of the job created manually from cron job with given name. Long
+// cron job names are truncated, as the cron job controller does, so that the suffix fits.
+func getManualJobName(cronJobName string, now time.Time) string {
+	suffix := fmt.Sprintf("-manual-%d", now.Unix())
+	if len(cronJobName)+len(suffix) > maxJobNameLength {
+		cronJobName = cronJobName[:maxJobNameLength-len(suffix)]
+	}
+	return cronJobName + suffix
+}
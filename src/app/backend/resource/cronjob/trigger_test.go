@@ -0,0 +1,106 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cronjob
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	batch "k8s.io/client-go/pkg/apis/batch/v1"
+	batch2 "k8s.io/client-go/pkg/apis/batch/v2alpha1"
+)
+
+func TestNewManualJob(t *testing.T) {
+	now := time.Unix(1494000000, 0)
+	parallelism := int32(2)
+	controller := true
+	cronJob := &batch2.CronJob{
+		ObjectMeta: metaV1.ObjectMeta{Name: "cron", Namespace: "ns", UID: types.UID("uid")},
+		Spec: batch2.CronJobSpec{
+			JobTemplate: batch2.JobTemplateSpec{
+				ObjectMeta: metaV1.ObjectMeta{
+					Labels:      map[string]string{"app": "cron"},
+					Annotations: map[string]string{"owner": "ops"},
+				},
+				Spec: batch.JobSpec{Parallelism: &parallelism},
+			},
+		},
+	}
+	expected := &batch.Job{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:        "cron-manual-1494000000",
+			Namespace:   "ns",
+			Labels:      map[string]string{"app": "cron"},
+			Annotations: map[string]string{"owner": "ops", InstantiateAnnotationKey: "manual"},
+			OwnerReferences: []metaV1.OwnerReference{{
+				APIVersion: "batch/v2alpha1",
+				Kind:       "CronJob",
+				Name:       "cron",
+				UID:        types.UID("uid"),
+				Controller: &controller,
+			}},
+		},
+		Spec: batch.JobSpec{Parallelism: &parallelism},
+	}
+
+	actual := newManualJob(cronJob, now)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("newManualJob(%#v, %v) == \ngot %#v, \nexpected %#v", cronJob, now, actual, expected)
+	}
+	if !isOwnedBy(actual, cronJob) {
+		t.Errorf("isOwnedBy(%#v, %#v) == false, expected true", actual, cronJob)
+	}
+}
+
+func TestTriggerCronJob(t *testing.T) {
+	cronJob := &batch2.CronJob{ObjectMeta: metaV1.ObjectMeta{Name: "cron", Namespace: "ns"}}
+	fakeClient := fake.NewSimpleClientset(cronJob)
+
+	run, err := TriggerCronJob(fakeClient, "ns", "cron")
+	if err != nil {
+		t.Fatalf("TriggerCronJob(client, ns, cron) == \ngot err %#v", err)
+	}
+	if run.Status != RunStatusRunning {
+		t.Errorf("TriggerCronJob(client, ns, cron) == \ngot status %s, expected %s", run.Status,
+			RunStatusRunning)
+	}
+
+	if _, err := fakeClient.BatchV1().Jobs("ns").Get(run.JobName, metaV1.GetOptions{}); err != nil {
+		t.Errorf("TriggerCronJob(client, ns, cron) did not create job %s: %v", run.JobName, err)
+	}
+}
+
+func TestGetManualJobName(t *testing.T) {
+	now := time.Unix(1494000000, 0)
+	cases := []struct {
+		cronJobName string
+		expected    string
+	}{
+		{"cron", "cron-manual-1494000000"},
+		{strings.Repeat("a", 52), strings.Repeat("a", 45) + "-manual-1494000000"},
+	}
+	for _, c := range cases {
+		actual := getManualJobName(c.cronJobName, now)
+		if actual != c.expected || len(actual) > maxJobNameLength {
+			t.Errorf("getManualJobName(%#v, %v) == %#v, expected %#v", c.cronJobName, now, actual,
+				c.expected)
+		}
+	}
+}
@@ -18,7 +18,8 @@ import (
 	"log"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	"github.com/kubernetes/dashboard/src/app/backend/link"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/pod"
@@ -33,6 +34,9 @@ type DaemonSetDetail struct {
 	ObjectMeta api.ObjectMeta `json:"objectMeta"`
 	TypeMeta   api.TypeMeta   `json:"typeMeta"`
 
+	// Links to external tools configured in settings and in the links annotation.
+	Links []link.Link `json:"links,omitempty"`
+
 	// Label selector of the Daemon Set.
 	LabelSelector *v1.LabelSelector `json:"labelSelector,omitempty"`
 
@@ -53,10 +57,13 @@ type DaemonSetDetail struct {
 
 	// List of events related to this daemon set
 	EventList common.EventList `json:"eventList"`
+
+	// Scheduling of this daemon set on every node, including nodes excluded from it.
+	Nodes []DaemonSetNode `json:"nodes"`
 }
 
 // Returns detailed information about the given daemon set in the given namespace.
-func GetDaemonSetDetail(client k8sClient.Interface, heapsterClient heapster.HeapsterClient,
+func GetDaemonSetDetail(client k8sClient.Interface, heapsterClient metricapi.MetricClient,
 	namespace, name string) (*DaemonSetDetail, error) {
 	log.Printf("Getting details of %s daemon set in %s namespace", name, namespace)
 
@@ -85,6 +92,11 @@ func GetDaemonSetDetail(client k8sClient.Interface, heapsterClient heapster.Heap
 		return nil, err
 	}
 
+	nodes, err := getDaemonSetNodes(client, daemonSet)
+	if err != nil {
+		return nil, err
+	}
+
 	daemonSetDetail := &DaemonSetDetail{
 		ObjectMeta:    api.NewObjectMeta(daemonSet.ObjectMeta),
 		TypeMeta:      api.NewTypeMeta(api.ResourceKindDaemonSet),
@@ -93,6 +105,7 @@ func GetDaemonSetDetail(client k8sClient.Interface, heapsterClient heapster.Heap
 		PodList:       *podList,
 		ServiceList:   *serviceList,
 		EventList:     *eventList,
+		Nodes:         nodes,
 	}
 
 	for _, container := range daemonSet.Spec.Template.Spec.Containers {
@@ -54,7 +54,7 @@ func TestGetDaemonSetEvents(t *testing.T) {
 			&ds,
 			[]string{"list", "get", "list", "list"},
 			&common.EventList{
-				ListMeta: api.ListMeta{TotalItems: 1},
+				ListMeta: api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
 				Events: []common.Event{{
 					TypeMeta:   api.TypeMeta{Kind: api.ResourceKindEvent},
 					ObjectMeta: api.ObjectMeta{Name: "ev-1", Namespace: "test-namespace"},
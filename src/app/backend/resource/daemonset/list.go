@@ -18,7 +18,8 @@ import (
 	"log"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	"github.com/kubernetes/dashboard/src/app/backend/integration/alertmanager"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
@@ -32,6 +33,9 @@ import (
 type DaemonSetList struct {
 	ListMeta api.ListMeta `json:"listMeta"`
 
+	// Status summary of the returned items.
+	Status common.ResourceStatus `json:"status"`
+
 	// Unordered list of Daemon Sets
 	DaemonSets        []DaemonSet     `json:"daemonSets"`
 	CumulativeMetrics []metric.Metric `json:"cumulativeMetrics"`
@@ -48,17 +52,21 @@ type DaemonSet struct {
 
 	// Container images of the Daemon Set.
 	ContainerImages []string `json:"containerImages"`
+
+	// Active alerts of the Daemon Set, nil if there are none.
+	Alerts *alertmanager.AlertBadge `json:"alerts,omitempty"`
 }
 
 // GetDaemonSetList returns a list of all Daemon Set in the cluster.
 func GetDaemonSetList(client *client.Clientset, nsQuery *common.NamespaceQuery,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*DaemonSetList, error) {
+	dsQuery *dataselect.DataSelectQuery, heapsterClient *metricapi.MetricClient) (*DaemonSetList, error) {
 	log.Print("Getting list of all daemon sets in the cluster")
+	options := dsQuery.ListOptions()
 	channels := &common.ResourceChannels{
-		DaemonSetList: common.GetDaemonSetListChannel(client, nsQuery, 1),
+		DaemonSetList: common.GetDaemonSetListChannelWithOptions(client, nsQuery, options, 1),
 		ServiceList:   common.GetServiceListChannel(client, nsQuery, 1),
-		PodList:       common.GetPodListChannel(client, nsQuery, 1),
-		EventList:     common.GetEventListChannel(client, nsQuery, 1),
+		PodList:       common.GetFanOutPodListChannel(client, nsQuery, 1),
+		EventList:     common.GetWarningEventListChannel(client, nsQuery, 1),
 	}
 
 	return GetDaemonSetListFromChannels(channels, dsQuery, heapsterClient)
@@ -67,7 +75,7 @@ func GetDaemonSetList(client *client.Clientset, nsQuery *common.NamespaceQuery,
 // GetDaemonSetListFromChannels returns a list of all Daemon Seet in the cluster
 // reading required resource list once from the channels.
 func GetDaemonSetListFromChannels(channels *common.ResourceChannels,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*DaemonSetList, error) {
+	dsQuery *dataselect.DataSelectQuery, heapsterClient *metricapi.MetricClient) (*DaemonSetList, error) {
 
 	daemonSets := <-channels.DaemonSetList.List
 	if err := <-channels.DaemonSetList.Error; err != nil {
@@ -85,31 +93,33 @@ func GetDaemonSetListFromChannels(channels *common.ResourceChannels,
 	}
 
 	result := CreateDaemonSetList(daemonSets.Items, pods.Items, events.Items, dsQuery, heapsterClient)
+	result.ListMeta.Warnings = common.GetListBudgetWarnings(pods, events)
 	return result, nil
 }
 
 // CreateDaemonSetList returns a list of all Daemon Set model objects in the cluster, based on all
 // Kubernetes Daemon Set API objects.
 func CreateDaemonSetList(daemonSets []extensions.DaemonSet, pods []v1.Pod,
-	events []v1.Event, dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) *DaemonSetList {
+	events []v1.Event, dsQuery *dataselect.DataSelectQuery, heapsterClient *metricapi.MetricClient) *DaemonSetList {
 
 	daemonSetList := &DaemonSetList{
 		DaemonSets: make([]DaemonSet, 0),
 		ListMeta:   api.ListMeta{TotalItems: len(daemonSets)},
 	}
 
+	for _, daemonSet := range FromCells(dataselect.GenericDataFilter(ToCells(daemonSets), dsQuery)) {
+		daemonSetList.Status.AddPodInfo(getPodInfo(daemonSet, pods, events))
+	}
+
 	cachedResources := &dataselect.CachedResources{
 		Pods: pods,
 	}
 	dsCells, metricPromises, filteredTotal := dataselect.GenericDataSelectWithFilterAndMetrics(ToCells(daemonSets), dsQuery, cachedResources, heapsterClient)
 	daemonSets = FromCells(dsCells)
-	daemonSetList.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+	daemonSetList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, daemonSetList.ListMeta.TotalItems)
 
 	for _, daemonSet := range daemonSets {
-		matchingPods := common.FilterPodsByOwnerReference(daemonSet.Namespace, daemonSet.UID, pods)
-		podInfo := common.GetPodInfo(daemonSet.Status.CurrentNumberScheduled,
-			daemonSet.Status.DesiredNumberScheduled, matchingPods)
-		podInfo.Warnings = event.GetPodsEventWarnings(events, matchingPods)
+		podInfo := getPodInfo(daemonSet, pods, events)
 
 		daemonSetList.DaemonSets = append(daemonSetList.DaemonSets,
 			DaemonSet{
@@ -128,3 +138,12 @@ func CreateDaemonSetList(daemonSets []extensions.DaemonSet, pods []v1.Pod,
 
 	return daemonSetList
 }
+
+// getPodInfo returns aggregate information about pods of given daemon set.
+func getPodInfo(daemonSet extensions.DaemonSet, pods []v1.Pod, events []v1.Event) common.PodInfo {
+	matchingPods := common.FilterPodsByOwnerReference(daemonSet.Namespace, daemonSet.UID, pods)
+	podInfo := common.GetPodInfo(daemonSet.Status.CurrentNumberScheduled,
+		daemonSet.Status.DesiredNumberScheduled, matchingPods)
+	podInfo.Warnings = event.GetPodsEventWarnings(events, matchingPods)
+	return podInfo
+}
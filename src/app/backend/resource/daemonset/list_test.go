@@ -167,7 +167,8 @@ func TestGetDaemonSetList(t *testing.T) {
 			},
 			},
 			&DaemonSetList{
-				ListMeta:          api.ListMeta{TotalItems: 2},
+				ListMeta:          api.ListMeta{TotalItems: 2, TotalBeforeFilter: 2},
+				Status:            common.ResourceStatus{Running: 1, Failed: 1},
 				CumulativeMetrics: make([]metric.Metric, 0),
 				DaemonSets: []DaemonSet{
 					{
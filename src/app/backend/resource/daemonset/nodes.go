@@ -0,0 +1,237 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daemonset
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	k8sClient "k8s.io/client-go/kubernetes"
+	api "k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+// daemonSetTolerations are tolerations daemon set controller adds to pods of all daemon sets, so
+// that daemon pods run on nodes with these taints.
+var daemonSetTolerations = []api.Toleration{
+	{Key: "node.kubernetes.io/not-ready", Operator: api.TolerationOpExists, Effect: api.TaintEffectNoExecute},
+	{Key: "node.kubernetes.io/unreachable", Operator: api.TolerationOpExists, Effect: api.TaintEffectNoExecute},
+	{Key: "node.kubernetes.io/disk-pressure", Operator: api.TolerationOpExists, Effect: api.TaintEffectNoSchedule},
+	{Key: "node.kubernetes.io/memory-pressure", Operator: api.TolerationOpExists, Effect: api.TaintEffectNoSchedule},
+	{Key: "node.kubernetes.io/pid-pressure", Operator: api.TolerationOpExists, Effect: api.TaintEffectNoSchedule},
+	{Key: "node.kubernetes.io/unschedulable", Operator: api.TolerationOpExists, Effect: api.TaintEffectNoSchedule},
+}
+
+// DaemonSetNode is scheduling of a daemon set on a single node.
+type DaemonSetNode struct {
+	NodeName string `json:"nodeName"`
+
+	// Number of daemon pods that should run on the node, 1 unless the node is excluded.
+	Desired int `json:"desired"`
+
+	// Number of daemon pods scheduled on the node and the number of those that are ready.
+	Current int `json:"current"`
+	Ready   int `json:"ready"`
+
+	// True when daemon pods should not run on the node.
+	Excluded bool `json:"excluded"`
+
+	// Reasons of exclusion, e.g. node selector mismatch or taints that are not tolerated.
+	Reasons []string `json:"reasons"`
+}
+
+// getDaemonSetNodes returns scheduling of given daemon set on every node of the cluster. Nothing is
+// returned when the user cannot list nodes, e.g. when permissions are limited to namespaces.
+func getDaemonSetNodes(client k8sClient.Interface, daemonSet *extensions.DaemonSet) ([]DaemonSetNode,
+	error) {
+	channels := &common.ResourceChannels{
+		NodeList: common.GetNodeListChannel(client, 1),
+		PodList:  common.GetPodListChannel(client, common.NewSameNamespaceQuery(daemonSet.Namespace), 1),
+	}
+
+	nodes := <-channels.NodeList.List
+	nodesErr := <-channels.NodeList.Error
+	pods := <-channels.PodList.List
+	podsErr := <-channels.PodList.Error
+	if k8serrors.IsForbidden(nodesErr) {
+		return make([]DaemonSetNode, 0), nil
+	}
+	if nodesErr != nil {
+		return nil, nodesErr
+	}
+	if podsErr != nil {
+		return nil, podsErr
+	}
+
+	return toDaemonSetNodes(daemonSet, nodes.Items,
+		common.FilterPodsByOwnerReference(daemonSet.Namespace, daemonSet.UID, pods.Items)), nil
+}
+
+func toDaemonSetNodes(daemonSet *extensions.DaemonSet, nodes []api.Node, pods []api.Pod) []DaemonSetNode {
+	podsByNode := make(map[string][]api.Pod)
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" {
+			podsByNode[pod.Spec.NodeName] = append(podsByNode[pod.Spec.NodeName], pod)
+		}
+	}
+
+	result := make([]DaemonSetNode, 0, len(nodes))
+	for _, node := range nodes {
+		reasons := getExclusionReasons(&daemonSet.Spec.Template.Spec, &node)
+		daemonSetNode := DaemonSetNode{
+			NodeName: node.Name,
+			Excluded: len(reasons) > 0,
+			Reasons:  reasons,
+		}
+		if !daemonSetNode.Excluded {
+			daemonSetNode.Desired = 1
+		}
+
+		for _, pod := range podsByNode[node.Name] {
+			if pod.DeletionTimestamp != nil {
+				continue
+			}
+			daemonSetNode.Current++
+			if common.IsPodReady(&pod) {
+				daemonSetNode.Ready++
+			}
+		}
+
+		result = append(result, daemonSetNode)
+	}
+
+	sort.Sort(daemonSetNodesByName(result))
+	return result
+}
+
+// getExclusionReasons returns reasons why pods with given spec should not run on given node, the
+// same way daemon set controller decides it.
+func getExclusionReasons(spec *api.PodSpec, node *api.Node) []string {
+	reasons := make([]string, 0)
+
+	if spec.NodeName != "" && spec.NodeName != node.Name {
+		reasons = append(reasons, fmt.Sprintf("Pod template targets node %s", spec.NodeName))
+	}
+
+	keys := make([]string, 0, len(spec.NodeSelector))
+	for key := range spec.NodeSelector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if value, ok := node.Labels[key]; !ok || value != spec.NodeSelector[key] {
+			reasons = append(reasons, fmt.Sprintf("Node selector %s=%s does not match", key,
+				spec.NodeSelector[key]))
+		}
+	}
+
+	if spec.Affinity != nil && spec.Affinity.NodeAffinity != nil {
+		required := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+		if required != nil && !matchesNodeSelectorTerms(required.NodeSelectorTerms, node.Labels) {
+			reasons = append(reasons, "Required node affinity does not match")
+		}
+	}
+
+	tolerations := append(append([]api.Toleration{}, daemonSetTolerations...), spec.Tolerations...)
+	for _, taint := range node.Spec.Taints {
+		// Daemon set controller ignores taints which are only preferred to be avoided.
+		if taint.Effect == api.TaintEffectPreferNoSchedule {
+			continue
+		}
+		if !toleratesTaint(tolerations, &taint) {
+			reasons = append(reasons, fmt.Sprintf("Taint %s is not tolerated", taint.ToString()))
+		}
+	}
+
+	return reasons
+}
+
+func toleratesTaint(tolerations []api.Toleration, taint *api.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesNodeSelectorTerms returns true if labels match any of the terms. Requirements of a term
+// must all be met.
+func matchesNodeSelectorTerms(terms []api.NodeSelectorTerm, labels map[string]string) bool {
+	for _, term := range terms {
+		matches := true
+		for _, requirement := range term.MatchExpressions {
+			if !matchesNodeSelectorRequirement(requirement, labels) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesNodeSelectorRequirement(requirement api.NodeSelectorRequirement,
+	labels map[string]string) bool {
+	value, ok := labels[requirement.Key]
+	switch requirement.Operator {
+	case api.NodeSelectorOpIn:
+		return ok && containsString(requirement.Values, value)
+	case api.NodeSelectorOpNotIn:
+		return !ok || !containsString(requirement.Values, value)
+	case api.NodeSelectorOpExists:
+		return ok
+	case api.NodeSelectorOpDoesNotExist:
+		return !ok
+	case api.NodeSelectorOpGt, api.NodeSelectorOpLt:
+		if !ok || len(requirement.Values) != 1 {
+			return false
+		}
+		actual, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		expected, err := strconv.ParseInt(requirement.Values[0], 10, 64)
+		if err != nil {
+			return false
+		}
+		if requirement.Operator == api.NodeSelectorOpGt {
+			return actual > expected
+		}
+		return actual < expected
+	default:
+		return false
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+type daemonSetNodesByName []DaemonSetNode
+
+func (self daemonSetNodesByName) Len() int           { return len(self) }
+func (self daemonSetNodesByName) Swap(i, j int)      { self[i], self[j] = self[j], self[i] }
+func (self daemonSetNodesByName) Less(i, j int) bool { return self[i].NodeName < self[j].NodeName }
@@ -0,0 +1,111 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daemonset
+
+import (
+	"reflect"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	api "k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+	core "k8s.io/client-go/testing"
+)
+
+func TestToDaemonSetNodes(t *testing.T) {
+	daemonSet := &extensions.DaemonSet{
+		Spec: extensions.DaemonSetSpec{
+			Template: api.PodTemplateSpec{
+				Spec: api.PodSpec{
+					NodeSelector: map[string]string{"role": "worker"},
+					Tolerations: []api.Toleration{
+						{Key: "dedicated", Operator: api.TolerationOpEqual, Value: "gpu",
+							Effect: api.TaintEffectNoSchedule},
+					},
+					Affinity: &api.Affinity{NodeAffinity: &api.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &api.NodeSelector{
+							NodeSelectorTerms: []api.NodeSelectorTerm{{
+								MatchExpressions: []api.NodeSelectorRequirement{
+									{Key: "zone", Operator: api.NodeSelectorOpNotIn, Values: []string{"c"}},
+								},
+							}},
+						},
+					}},
+				},
+			},
+		},
+	}
+	nodes := []api.Node{
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "node-b", Labels: map[string]string{"role": "worker"}},
+			Spec: api.NodeSpec{Taints: []api.Taint{
+				{Key: "dedicated", Value: "gpu", Effect: api.TaintEffectNoSchedule},
+				{Key: "node.kubernetes.io/unschedulable", Effect: api.TaintEffectNoSchedule},
+				{Key: "spot", Effect: api.TaintEffectPreferNoSchedule},
+			}},
+		},
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "node-a",
+				Labels: map[string]string{"role": "master", "zone": "c"}},
+			Spec: api.NodeSpec{Taints: []api.Taint{
+				{Key: "node-role.kubernetes.io/master", Effect: api.TaintEffectNoSchedule},
+			}},
+		},
+	}
+	pods := []api.Pod{
+		{
+			Spec: api.PodSpec{NodeName: "node-b"},
+			Status: api.PodStatus{Conditions: []api.PodCondition{
+				{Type: api.PodReady, Status: api.ConditionTrue}}},
+		},
+		{Spec: api.PodSpec{NodeName: "node-a"}},
+	}
+	expected := []DaemonSetNode{
+		{
+			NodeName: "node-a",
+			Current:  1,
+			Excluded: true,
+			Reasons: []string{
+				"Node selector role=worker does not match",
+				"Required node affinity does not match",
+				"Taint node-role.kubernetes.io/master:NoSchedule is not tolerated",
+			},
+		},
+		{NodeName: "node-b", Desired: 1, Current: 1, Ready: 1, Reasons: []string{}},
+	}
+
+	actual := toDaemonSetNodes(daemonSet, nodes, pods)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toDaemonSetNodes(daemonSet, nodes, pods) == \ngot %#v, \nexpected %#v", actual, expected)
+	}
+}
+
+func TestGetDaemonSetNodesForbidden(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	fakeClient.PrependReactor("list", "nodes", func(action core.Action) (bool, runtime.Object, error) {
+		return true, nil, k8serrors.NewForbidden(schema.GroupResource{Resource: "nodes"}, "", nil)
+	})
+	daemonSet := &extensions.DaemonSet{ObjectMeta: metaV1.ObjectMeta{Name: "ds", Namespace: "default"}}
+
+	actual, err := getDaemonSetNodes(fakeClient, daemonSet)
+	if err != nil || !reflect.DeepEqual(actual, []DaemonSetNode{}) {
+		t.Errorf("getDaemonSetNodes() without permission to list nodes == \ngot %#v, %v, \nexpected "+
+			"no nodes", actual, err)
+	}
+}
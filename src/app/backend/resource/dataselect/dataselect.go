@@ -17,8 +17,10 @@ package dataselect
 import (
 	"fmt"
 	"sort"
+	"time"
 
-	client "github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
 	"k8s.io/client-go/pkg/api/v1"
 )
@@ -75,6 +77,11 @@ type DataSelector struct {
 	// CumulativeMetricsPromises is a list of promises holding aggregated metrics for resources in GenericDataList.
 	// The metrics will be calculated after calling GetCumulativeMetrics method.
 	CumulativeMetricsPromises metric.MetricPromises
+	// ItemMetricsPromises holds metrics of every resource in GenericDataList, one list of promises per resource.
+	// The metrics will be calculated after calling GetItemMetrics method.
+	ItemMetricsPromises []metric.MetricPromises
+	// sortByList is the effective sort, set by Sort method.
+	sortByList []SortBy
 }
 
 // Implementation of sort.Interface so that we can use built-in sort function (sort.Sort) for sorting SelectableData
@@ -89,14 +96,20 @@ func (self DataSelector) Swap(i, j int) {
 
 // Less compares 2 indices inside SelectableData and returns true if first index is larger.
 func (self DataSelector) Less(i, j int) bool {
-	for _, sortBy := range self.DataSelectQuery.SortQuery.SortByList {
+	for _, sortBy := range self.sortByList {
 		a := self.GenericDataList[i].GetProperty(sortBy.Property)
 		b := self.GenericDataList[j].GetProperty(sortBy.Property)
-		// ignore sort completely if property name not found
-		if a == nil || b == nil {
-			break
+		var cmp int
+		switch {
+		case a == nil && b == nil:
+			cmp = 0
+		case a == nil: // missing values are smaller than any other value
+			cmp = -1
+		case b == nil:
+			cmp = 1
+		default:
+			cmp = a.Compare(b)
 		}
-		cmp := a.Compare(b)
 		if cmp == 0 { // values are the same. Just continue to next sortBy
 			continue
 		} else { // values different
@@ -107,8 +120,104 @@ func (self DataSelector) Less(i, j int) bool {
 }
 
 // Sort sorts the data inside as instructed by DataSelectQuery and returns itself to allow method chaining.
+// Items are compared by every requested property in order, ties are broken by namespace, name and UID,
+// so the order is deterministic. Sort is ignored completely when data cells do not support any of
+// requested properties and tie-breakers they do not support, e.g. namespace of nodes, are skipped.
 func (self *DataSelector) Sort() *DataSelector {
-	sort.Sort(*self)
+	self.sortByList = []SortBy{}
+	if len(self.GenericDataList) == 0 {
+		return self
+	}
+
+	requested := len(self.DataSelectQuery.SortQuery.normalized())
+	for i, sortBy := range self.DataSelectQuery.SortQuery.Effective() {
+		if self.isPropertySupported(sortBy.Property) {
+			self.sortByList = append(self.sortByList, sortBy)
+		} else if i < requested {
+			self.sortByList = []SortBy{}
+			return self
+		}
+	}
+	sort.Stable(*self)
+	return self
+}
+
+// isPropertySupported returns true if cells of the list support given property. Cells return nil
+// for properties their type does not support, but also for supported properties without value,
+// so a property is supported if any of the cells has a value of it. Cells without value are
+// sorted first.
+func (self *DataSelector) isPropertySupported(property PropertyName) bool {
+	for _, cell := range self.GenericDataList {
+		if cell.GetProperty(property) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// usageProperties maps properties of current usage to names of their metrics.
+var usageProperties = map[PropertyName]string{
+	CpuUsageProperty:    common.CpuUsage,
+	MemoryUsageProperty: common.MemoryUsage,
+}
+
+// usageCell is a data cell with its current usage, so that it can be sorted by it. Usage is
+// the latest data point of the metric, -1 if unknown.
+type usageCell struct {
+	DataCell
+	usage map[PropertyName]int64
+}
+
+// GetProperty returns current usage for usage properties and the property of the data cell
+// otherwise.
+func (self usageCell) GetProperty(name PropertyName) ComparableValue {
+	if value, ok := self.usage[name]; ok {
+		return StdComparableInt64(value)
+	}
+	return self.DataCell.GetProperty(name)
+}
+
+// SortWithMetrics works like Sort, but also supports sorting by current usage, e.g. by
+// CpuUsageProperty. Usage metrics are downloaded for all data cells, so only when the sort
+// requires them.
+func (self *DataSelector) SortWithMetrics(heapsterClient *metricapi.MetricClient) *DataSelector {
+	metricNames := map[PropertyName]string{}
+	for _, sortBy := range self.DataSelectQuery.SortQuery.SortByList {
+		if metricName, ok := usageProperties[sortBy.Property]; ok {
+			metricNames[sortBy.Property] = metricName
+		}
+	}
+	if len(metricNames) == 0 || heapsterClient == nil || *heapsterClient == nil ||
+		self.CachedResources == nil {
+		return self.Sort()
+	}
+
+	cells := make([]DataCell, len(self.GenericDataList))
+	for i, dataCell := range self.GenericDataList {
+		if _, ok := dataCell.(MetricDataCell); !ok {
+			return self.Sort()
+		}
+		cells[i] = usageCell{DataCell: dataCell, usage: map[PropertyName]int64{}}
+	}
+
+	heapsterSelectors := self.getHeapsterSelectors()
+	for property, metricName := range metricNames {
+		promises := heapsterSelectors.DownloadMetric(*heapsterClient, metricName)
+		for i, promise := range promises {
+			value := int64(-1)
+			if usage, err := promise.GetMetric(); err == nil && usage != nil &&
+				len(usage.DataPoints) > 0 {
+				value = usage.DataPoints[len(usage.DataPoints)-1].Y
+			}
+			cells[i].(usageCell).usage[property] = value
+		}
+	}
+
+	self.GenericDataList = cells
+	self.Sort()
+	for i, cell := range self.GenericDataList {
+		self.GenericDataList[i] = cell.(usageCell).DataCell
+	}
 	return self
 }
 
@@ -119,14 +228,9 @@ func (self *DataSelector) Filter() *DataSelector {
 	for _, c := range self.GenericDataList {
 		matches := true
 		for _, filterBy := range self.DataSelectQuery.FilterQuery.FilterByList {
-			v := c.GetProperty(filterBy.Property)
-			if v == nil {
-				matches = false
-				continue
-			}
-			if !v.Contains(filterBy.Value) {
+			if !filterBy.Matches(c.GetProperty(filterBy.Property)) {
 				matches = false
-				continue
+				break
 			}
 		}
 		if matches {
@@ -140,13 +244,56 @@ func (self *DataSelector) Filter() *DataSelector {
 
 // GetCumulativeMetrics downloads and aggregates metrics for data cells currently present in self.GenericDataList as instructed
 // by MetricQuery and inserts resulting MetricPromises to self.CumulativeMetricsPromises.
-func (self *DataSelector) GetCumulativeMetrics(heapsterClient *client.HeapsterClient) *DataSelector {
+func (self *DataSelector) GetCumulativeMetrics(heapsterClient *metricapi.MetricClient) *DataSelector {
 	metricNames := self.DataSelectQuery.MetricQuery.MetricNames
 	if metricNames == nil {
 		// Don't download any metrics
 		return self
 	}
 	aggregations := self.DataSelectQuery.MetricQuery.Aggregations
+	heapsterSelectors := self.getHeapsterSelectors()
+	if aggregations == nil {
+		aggregations = metric.OnlyDefaultAggregation
+	}
+	// panic if someone tries to download metrics without providing heapster client.
+	if heapsterClient == nil {
+		panic("Tried to download metrics without providing heapster client. Use dataselect.NoMetrics or provide heapster!")
+	}
+	self.CumulativeMetricsPromises = self.downsample(
+		heapsterSelectors.DownloadAndAggregate(*heapsterClient, metricNames, aggregations))
+	return self
+}
+
+// GetItemMetrics downloads metrics for every data cell currently present in self.GenericDataList separately and
+// inserts resulting MetricPromises to self.ItemMetricsPromises. Metrics are downloaded only if requested by
+// ItemMetrics, call it after Paginate to limit the cost.
+func (self *DataSelector) GetItemMetrics(heapsterClient *metricapi.MetricClient) *DataSelector {
+	metricNames := self.DataSelectQuery.MetricQuery.MetricNames
+	if metricNames == nil || !self.DataSelectQuery.ItemMetrics {
+		return self
+	}
+	if heapsterClient == nil {
+		panic("Tried to download metrics without providing heapster client. Use dataselect.NoMetrics or provide heapster!")
+	}
+	heapsterSelectors := self.getHeapsterSelectors()
+	self.ItemMetricsPromises = make([]metric.MetricPromises, len(heapsterSelectors))
+	for _, metricName := range metricNames {
+		// One download for all items, result has one promise per item.
+		promises := self.downsample(heapsterSelectors.DownloadMetric(*heapsterClient, metricName))
+		for i, promise := range promises {
+			self.ItemMetricsPromises[i] = append(self.ItemMetricsPromises[i], promise)
+		}
+	}
+	return self
+}
+
+// getHeapsterSelectors returns heapster selectors of data cells currently present in self.GenericDataList,
+// limited to the metric window if it is requested.
+func (self *DataSelector) getHeapsterSelectors() metric.HeapsterSelectors {
+	var since time.Time
+	if windowQuery := self.DataSelectQuery.MetricWindowQuery; windowQuery != nil && windowQuery.Window > 0 {
+		since = time.Now().Add(-windowQuery.Window)
+	}
 	heapsterSelectors := make(metric.HeapsterSelectors, len(self.GenericDataList))
 	// get all heapster queries
 	for i, dataCell := range self.GenericDataList {
@@ -159,17 +306,20 @@ func (self *DataSelector) GetCumulativeMetrics(heapsterClient *client.HeapsterCl
 			// Programming error. Notify immediately.
 			panic(fmt.Sprintf(`Failed to create heapster selector for resource "%s". Error: %s`, metricDataCell.GetResourceSelector().ResourceType, err))
 		}
+		heapsterSelector.Since = since
+		heapsterSelector.Rollup = self.DataSelectQuery.MetricRollup
 		heapsterSelectors[i] = heapsterSelector
 	}
-	if aggregations == nil {
-		aggregations = metric.OnlyDefaultAggregation
-	}
-	// panic if someone tries to download metrics without providing heapster client.
-	if heapsterClient == nil {
-		panic("Tried to download metrics without providing heapster client. Use dataselect.NoMetrics or provide heapster!")
+	return heapsterSelectors
+}
+
+// downsample downsamples metrics as instructed by MetricWindowQuery.
+func (self *DataSelector) downsample(promises metric.MetricPromises) metric.MetricPromises {
+	windowQuery := self.DataSelectQuery.MetricWindowQuery
+	if windowQuery == nil {
+		return promises
 	}
-	self.CumulativeMetricsPromises = heapsterSelectors.DownloadAndAggregate(*heapsterClient, metricNames, aggregations)
-	return self
+	return metric.DownsampleMetricPromises(promises, windowQuery.MaxDataPoints, windowQuery.Downsampling)
 }
 
 // Paginates the data inside as instructed by DataSelectQuery and returns itself to allow method chaining.
@@ -201,6 +351,16 @@ func GenericDataSelect(dataList []DataCell, dsQuery *DataSelectQuery) []DataCell
 	return SelectableData.Sort().Paginate().GenericDataList
 }
 
+// GenericDataFilter takes a list of GenericDataCells and DataSelectQuery and returns data matching the filter of
+// dsQuery, e.g. to summarize all filtered items of a list and not only the selected page.
+func GenericDataFilter(dataList []DataCell, dsQuery *DataSelectQuery) []DataCell {
+	SelectableData := DataSelector{
+		GenericDataList: dataList,
+		DataSelectQuery: dsQuery,
+	}
+	return SelectableData.Filter().GenericDataList
+}
+
 // GenericDataSelectWithFilter takes a list of GenericDataCells and DataSelectQuery and returns selected data as instructed by dsQuery.
 func GenericDataSelectWithFilter(dataList []DataCell, dsQuery *DataSelectQuery) ([]DataCell, int) {
 	SelectableData := DataSelector{
@@ -216,19 +376,20 @@ func GenericDataSelectWithFilter(dataList []DataCell, dsQuery *DataSelectQuery)
 
 // GenericDataSelect takes a list of GenericDataCells and DataSelectQuery and returns selected data as instructed by dsQuery.
 func GenericDataSelectWithMetrics(dataList []DataCell, dsQuery *DataSelectQuery,
-	cachedResources *CachedResources, heapsterClient *client.HeapsterClient) ([]DataCell, metric.MetricPromises) {
+	cachedResources *CachedResources, heapsterClient *metricapi.MetricClient) ([]DataCell, metric.MetricPromises) {
 	SelectableData := DataSelector{
 		GenericDataList: dataList,
 		DataSelectQuery: dsQuery,
 		CachedResources: cachedResources,
 	}
 	// Pipeline is Filter -> Sort -> CollectMetrics -> Paginate
-	processed := SelectableData.Sort().GetCumulativeMetrics(heapsterClient).Paginate()
+	processed := SelectableData.SortWithMetrics(heapsterClient).GetCumulativeMetrics(heapsterClient).
+		Paginate()
 	return processed.GenericDataList, processed.CumulativeMetricsPromises
 }
 
 func GenericDataSelectWithFilterAndMetrics(dataList []DataCell, dsQuery *DataSelectQuery,
-	cachedResources *CachedResources, heapsterClient *client.HeapsterClient) ([]DataCell, metric.MetricPromises, int) {
+	cachedResources *CachedResources, heapsterClient *metricapi.MetricClient) ([]DataCell, metric.MetricPromises, int) {
 	SelectableData := DataSelector{
 		GenericDataList: dataList,
 		DataSelectQuery: dsQuery,
@@ -237,7 +398,28 @@ func GenericDataSelectWithFilterAndMetrics(dataList []DataCell, dsQuery *DataSel
 	// Pipeline is Filter -> Sort -> CollectMetrics -> Paginate
 	filtered := SelectableData.Filter()
 	filteredTotal := len(filtered.GenericDataList)
-	processed := filtered.Sort().GetCumulativeMetrics(heapsterClient).Paginate()
+	processed := filtered.SortWithMetrics(heapsterClient).GetCumulativeMetrics(heapsterClient).
+		Paginate()
 	return processed.GenericDataList, processed.CumulativeMetricsPromises, filteredTotal
 
 }
+
+// GenericDataSelectWithFilterAndItemMetrics works like GenericDataSelectWithFilterAndMetrics, but additionally
+// returns metrics of every selected item if they are requested by ItemMetrics. Item metrics of the i-th selected
+// item are at i-th index.
+func GenericDataSelectWithFilterAndItemMetrics(dataList []DataCell, dsQuery *DataSelectQuery,
+	cachedResources *CachedResources, heapsterClient *metricapi.MetricClient) ([]DataCell, metric.MetricPromises,
+	[]metric.MetricPromises, int) {
+	SelectableData := DataSelector{
+		GenericDataList: dataList,
+		DataSelectQuery: dsQuery,
+		CachedResources: cachedResources,
+	}
+	// Pipeline is Filter -> Sort -> CollectMetrics -> Paginate -> CollectItemMetrics
+	filtered := SelectableData.Filter()
+	filteredTotal := len(filtered.GenericDataList)
+	processed := filtered.SortWithMetrics(heapsterClient).GetCumulativeMetrics(heapsterClient).Paginate().
+		GetItemMetrics(heapsterClient)
+	return processed.GenericDataList, processed.CumulativeMetricsPromises, processed.ItemMetricsPromises,
+		filteredTotal
+}
@@ -15,8 +15,18 @@
 package dataselect
 
 import (
+	"encoding/json"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	heapster "k8s.io/heapster/metrics/api/v1/types"
 )
 
 type PaginationTestCase struct {
@@ -42,6 +52,8 @@ func (self TestDataCell) GetProperty(name PropertyName) ComparableValue {
 		return StdComparableString(self.Name)
 	case CreationTimestampProperty:
 		return StdComparableInt(self.Id)
+	case NamespaceProperty, UIDProperty:
+		return StdComparableString("")
 	default:
 		return nil
 	}
@@ -108,6 +120,11 @@ func TestSort(t *testing.T) {
 			NewSortQuery([]string{"a", "name", "d", "creationTimestamp"}),
 			[]int{10, 3, 2, 1, 5, 4, 6, 7, 8, 9},
 		},
+		{
+			"sort by the same property twice - only the first order is used",
+			NewSortQuery([]string{"a", "name", "d", "name", "d", "creationTimestamp"}),
+			[]int{10, 3, 2, 1, 5, 4, 6, 7, 8, 9},
+		},
 		{
 			"empty sort list - no sort",
 			NewSortQuery([]string{}),
@@ -155,6 +172,200 @@ func TestSort(t *testing.T) {
 
 }
 
+func TestSortQueryString(t *testing.T) {
+	cases := []struct {
+		sortQuery *SortQuery
+		expected  string
+	}{
+		{NoSort, ""},
+		{nil, ""},
+		{NewSortQuery([]string{"d", "creationTimestamp"}), "d,creationTimestamp,a,namespace,a,name,a,uid"},
+		{NewSortQuery([]string{"d", "name", "a", "namespace"}), "d,name,a,namespace,a,uid"},
+		{NewSortQuery([]string{"d", "name", "a", "name"}), "d,name,a,namespace,a,uid"},
+	}
+	for _, c := range cases {
+		actual := c.sortQuery.String()
+		if actual != c.expected {
+			t.Errorf("String() of %#v == %#v, expected %#v", c.sortQuery, actual, c.expected)
+		}
+	}
+}
+
+func TestSortTieBreakers(t *testing.T) {
+	cells := toCells([]TestDataCell{{"b", 1}, {"a", 1}, {"c", 2}, {"a", 2}})
+	selectableData := DataSelector{
+		GenericDataList: cells,
+		DataSelectQuery: &DataSelectQuery{SortQuery: NewSortQuery([]string{"a", "creationTimestamp"})},
+	}
+	sorted := fromCells(selectableData.Sort().GenericDataList)
+	expected := []TestDataCell{{"a", 1}, {"b", 1}, {"a", 2}, {"c", 2}}
+	if !reflect.DeepEqual(sorted, expected) {
+		t.Errorf("Sort() with ties == %v, expected %v", sorted, expected)
+	}
+}
+
+// testNamespacedCell is a data cell of a namespaced resource that does not support UID property.
+type testNamespacedCell struct {
+	Namespace string
+	Name      string
+	Id        int
+}
+
+func (self testNamespacedCell) GetProperty(name PropertyName) ComparableValue {
+	switch name {
+	case NamespaceProperty:
+		return StdComparableString(self.Namespace)
+	case NameProperty:
+		return StdComparableString(self.Name)
+	case CreationTimestampProperty:
+		return StdComparableInt(self.Id)
+	default:
+		return nil
+	}
+}
+
+func TestSortMultipleNamespaces(t *testing.T) {
+	cases := []struct {
+		sortQuery *SortQuery
+		expected  []int
+	}{
+		{NewSortQuery([]string{"a", "namespace", "a", "name"}), []int{3, 1, 4, 2, 5}},
+		{NewSortQuery([]string{"d", "namespace", "d", "creationTimestamp"}), []int{5, 4, 2, 3, 1}},
+		// Ties are broken by namespace and name, unsupported UID tie-breaker is skipped.
+		{NewSortQuery([]string{"a", "name"}), []int{3, 4, 1, 2, 5}},
+		{NewSortQuery([]string{"a", "uid", "a", "name"}), []int{1, 2, 3, 4, 5}},
+	}
+	for _, c := range cases {
+		selectableData := DataSelector{
+			GenericDataList: []DataCell{
+				testNamespacedCell{"default", "b", 1},
+				testNamespacedCell{"kube-system", "b", 2},
+				testNamespacedCell{"default", "a", 3},
+				testNamespacedCell{"kube-system", "a", 4},
+				testNamespacedCell{"kube-system", "c", 5},
+			},
+			DataSelectQuery: &DataSelectQuery{SortQuery: c.sortQuery},
+		}
+		actual := []int{}
+		for _, cell := range selectableData.Sort().GenericDataList {
+			actual = append(actual, cell.(testNamespacedCell).Id)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Sort() with %s == \ngot: %#v, \nexpected %#v", c.sortQuery, actual, c.expected)
+		}
+	}
+}
+
+// testOptionalCell is a data cell whose status property may have no value.
+type testOptionalCell struct {
+	Name   string
+	Status *string
+}
+
+func (self testOptionalCell) GetProperty(name PropertyName) ComparableValue {
+	switch name {
+	case NameProperty:
+		return StdComparableString(self.Name)
+	case StatusProperty:
+		if self.Status == nil {
+			return nil
+		}
+		return StdComparableString(*self.Status)
+	default:
+		return nil
+	}
+}
+
+func TestSortMissingValues(t *testing.T) {
+	running, failed := "Running", "Failed"
+	selectableData := DataSelector{
+		// The first cell without value must not drop the sort, cells without value are the smallest.
+		GenericDataList: []DataCell{
+			testOptionalCell{"a", nil},
+			testOptionalCell{"c", &running},
+			testOptionalCell{"b", &failed},
+		},
+		DataSelectQuery: &DataSelectQuery{SortQuery: NewSortQuery([]string{"d", "status"})},
+	}
+
+	actual := []string{}
+	for _, cell := range selectableData.Sort().GenericDataList {
+		actual = append(actual, cell.(testOptionalCell).Name)
+	}
+	expected := []string{"c", "b", "a"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Sort() with missing values == \ngot: %#v, \nexpected %#v", actual, expected)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	cases := []struct {
+		filterQuery *FilterQuery
+		expected    []int
+	}{
+		{NoFilter, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+		{NewFilterQuery([]string{"name", "c"}), []int{4, 5}},
+		{mustParseFilterExpression(t, `name~"^a"`), []int{1, 2, 3, 4, 5, 6, 10}},
+		{mustParseFilterExpression(t, `name~a$`), []int{7, 8, 9, 10}},
+		{mustParseFilterExpression(t, `name!~"^a"`), []int{7, 8, 9}},
+		{mustParseFilterExpression(t, `name!=b`), []int{4, 5, 6, 8, 9, 10}},
+		{mustParseFilterExpression(t, `name~"^a", name!=b`), []int{4, 5, 6, 10}},
+		// Values of other types than strings are compared by their string representation.
+		{mustParseFilterExpression(t, `creationTimestamp=1`), []int{1, 10}},
+		{mustParseFilterExpression(t, `status~.`), []int{}},
+	}
+	for _, c := range cases {
+		selectableData := DataSelector{
+			GenericDataList: getDataCellList(),
+			DataSelectQuery: &DataSelectQuery{FilterQuery: c.filterQuery},
+		}
+		actual := getOrder(fromCells(selectableData.Filter().GenericDataList))
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Filter() with %s == \ngot: %#v, \nexpected %#v", c.filterQuery, actual, c.expected)
+		}
+	}
+}
+
+func mustParseFilterExpression(t *testing.T, expression string) *FilterQuery {
+	filterQuery, err := ParseFilterExpression(expression)
+	if err != nil {
+		t.Fatalf("ParseFilterExpression(%#v) returns error %v", expression, err)
+	}
+	return filterQuery
+}
+
+func TestParseFilterExpression(t *testing.T) {
+	cases := []struct {
+		expression  string
+		expected    string
+		expectError bool
+	}{
+		{"", "", false},
+		{" , ", "", false},
+		{"name=foo namespace=default", "name,foo,namespace,default", false},
+		{`name~"^api-",status=Failed`, `name~^api-,status=Failed`, false},
+		{`name~"\\d+ \"x\""`, `name~"\\d+ \"x\""`, false},
+		{`name~"api\.v1"`, `name~"api\\.v1"`, false},
+		{`name!="a b"`, `name!="a b"`, false},
+		{"name", "", true},
+		{"=foo", "", true},
+		{"name!foo", "", true},
+		{`name="foo`, "", true},
+		{`name~"("`, "", true},
+	}
+	for _, c := range cases {
+		actual, err := ParseFilterExpression(c.expression)
+		if (err != nil) != c.expectError {
+			t.Errorf("ParseFilterExpression(%#v) returns error %v, expected error: %v", c.expression, err,
+				c.expectError)
+		}
+		if err == nil && actual.String() != c.expected {
+			t.Errorf("ParseFilterExpression(%#v) == %#v, expected %#v", c.expression, actual.String(),
+				c.expected)
+		}
+	}
+}
+
 func TestPagination(t *testing.T) {
 	testCases := []PaginationTestCase{
 		{
@@ -222,3 +433,112 @@ func TestPagination(t *testing.T) {
 	}
 
 }
+
+func TestIsValidMetricRollup(t *testing.T) {
+	cases := []struct {
+		rollup   metric.AggregationName
+		expected bool
+	}{
+		{"", true},
+		{metric.SumAggregation, true},
+		{metric.P95Aggregation, true},
+		{"p99", false},
+	}
+	for _, c := range cases {
+		actual := IsValidMetricRollup(c.rollup)
+		if actual != c.expected {
+			t.Errorf("IsValidMetricRollup(%#v) == %#v, expected %#v", c.rollup, actual, c.expected)
+		}
+	}
+}
+
+func TestListOptions(t *testing.T) {
+	cases := []struct {
+		dsQuery  *DataSelectQuery
+		expected metaV1.ListOptions
+	}{
+		{nil, metaV1.ListOptions{}},
+		{NoDataSelect, metaV1.ListOptions{}},
+		{
+			&DataSelectQuery{LabelSelector: "app=foo,tier!=db"},
+			metaV1.ListOptions{LabelSelector: "app=foo,tier!=db"},
+		},
+	}
+	for _, c := range cases {
+		actual := c.dsQuery.ListOptions()
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("ListOptions() of %#v == %#v, expected %#v", c.dsQuery, actual, c.expected)
+		}
+	}
+}
+
+type TestMetricDataCell struct {
+	TestDataCell
+}
+
+func (self TestMetricDataCell) GetResourceSelector() *metric.ResourceSelector {
+	return &metric.ResourceSelector{ResourceType: api.ResourceKindNode, ResourceName: self.Name}
+}
+
+// fakeUsageHeapster returns a single data point for every known node, its value is the usage for the
+// node name. Unknown nodes have no data points.
+type fakeUsageHeapster map[string]uint64
+
+type fakeUsageRequest struct {
+	usage uint64
+	found bool
+}
+
+func (self fakeUsageHeapster) Get(path string) metricapi.RequestInterface {
+	name := strings.Split(strings.TrimPrefix(path, "/model/nodes/"), "/")[0]
+	usage, found := self[name]
+	if strings.Contains(path, common.MemoryUsage) {
+		usage = 1000 - usage
+	}
+	return fakeUsageRequest{usage: usage, found: found}
+}
+
+func (self fakeUsageRequest) DoRaw() ([]byte, error) {
+	if !self.found {
+		return json.Marshal(heapster.MetricResult{})
+	}
+	return json.Marshal(heapster.MetricResult{Metrics: []heapster.MetricPoint{
+		{Timestamp: time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC), Value: self.usage},
+	}})
+}
+
+func TestSortWithMetrics(t *testing.T) {
+	var client metricapi.MetricClient = fakeUsageHeapster{"a": 300, "b": 100, "c": 200}
+	cases := []struct {
+		sortQuery *SortQuery
+		client    *metricapi.MetricClient
+		expected  []string
+	}{
+		{NewSortQuery([]string{"d", CpuUsageProperty}), &client, []string{"a", "c", "b", "d"}},
+		{NewSortQuery([]string{"a", CpuUsageProperty}), &client, []string{"d", "b", "c", "a"}},
+		{NewSortQuery([]string{"d", MemoryUsageProperty}), &client, []string{"b", "c", "a", "d"}},
+		// Without metrics, sort by usage is ignored.
+		{NewSortQuery([]string{"d", CpuUsageProperty}), nil, []string{"b", "d", "a", "c"}},
+		{NewSortQuery([]string{"d", NameProperty}), &client, []string{"d", "c", "b", "a"}},
+	}
+	for _, c := range cases {
+		cells := []DataCell{}
+		for i, name := range []string{"b", "d", "a", "c"} {
+			cells = append(cells, TestMetricDataCell{TestDataCell{Name: name, Id: i}})
+		}
+		selector := DataSelector{
+			GenericDataList: cells,
+			DataSelectQuery: &DataSelectQuery{SortQuery: c.sortQuery},
+			CachedResources: NoResourceCache,
+		}
+
+		actual := []string{}
+		for _, cell := range selector.SortWithMetrics(c.client).GenericDataList {
+			actual = append(actual, cell.(TestMetricDataCell).Name)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("SortWithMetrics() with %s == \ngot: %#v, \nexpected %#v", c.sortQuery, actual,
+				c.expected)
+		}
+	}
+}
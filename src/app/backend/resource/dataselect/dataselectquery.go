@@ -15,8 +15,17 @@
 package dataselect
 
 import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // Options for GenericDataSelect which takes []GenericDataCell and returns selected data.
@@ -27,6 +36,30 @@ type DataSelectQuery struct {
 	SortQuery       *SortQuery
 	FilterQuery     *FilterQuery
 	MetricQuery     *MetricQuery
+	// MetricWindowQuery sets window and resolution of downloaded metrics. Nil means Heapster
+	// default window without downsampling.
+	MetricWindowQuery *MetricWindowQuery
+	// ItemMetrics enables download of metrics for every listed item separately, not only cumulative
+	// metrics of the whole list. Supported only by some lists, as it is expensive.
+	ItemMetrics bool
+	// MetricRollup is the aggregation used to roll metrics of pods up to their controllers, e.g.
+	// deployments. Pod metrics are summed if empty.
+	MetricRollup metric.AggregationName
+	// LabelSelector is the label selector of listed items. It is passed to the API server, so that
+	// only matching items are fetched. Empty means everything.
+	LabelSelector string
+}
+
+// ListOptions returns options of the API server list request of the selected items.
+func (self *DataSelectQuery) ListOptions() metaV1.ListOptions {
+	options := metaV1.ListOptions{
+		LabelSelector: labels.Everything().String(),
+		FieldSelector: fields.Everything().String(),
+	}
+	if self != nil && self.LabelSelector != "" {
+		options.LabelSelector = self.LabelSelector
+	}
+	return options
 }
 
 var NoMetrics = NewMetricQuery(nil, nil)
@@ -56,6 +89,47 @@ func NewMetricQuery(metricNames []string, aggregations metric.AggregationNames)
 	}
 }
 
+// MetricWindowQuery holds options for the time window of downloaded metrics. It is separate from
+// MetricQuery, so that it is kept when handlers override requested metrics.
+// Query has this format metricWindow=24h&maxDataPoints=100&downsampling=max
+type MetricWindowQuery struct {
+	// Window is the duration of the metric window ending now. Heapster default window is used if
+	// zero.
+	Window time.Duration
+	// MaxDataPoints is the maximum number of data points returned for every metric. Longer series
+	// are downsampled. Zero means no downsampling.
+	MaxDataPoints int
+	// Downsampling is the aggregation used to compute value of data points merged by downsampling,
+	// e.g. max or average.
+	Downsampling metric.AggregationName
+}
+
+// NewMetricWindowQuery returns a metric window query from provided settings. Zero values mean
+// defaults, unknown downsampling is replaced by max.
+func NewMetricWindowQuery(window time.Duration, maxDataPoints int,
+	downsampling metric.AggregationName) *MetricWindowQuery {
+	if window < 0 {
+		window = 0
+	}
+	if maxDataPoints < 0 {
+		maxDataPoints = 0
+	}
+	if _, ok := metric.AggregatingFunctions[downsampling]; !ok {
+		downsampling = metric.MaxAggregation
+	}
+	return &MetricWindowQuery{Window: window, MaxDataPoints: maxDataPoints, Downsampling: downsampling}
+}
+
+// IsValidMetricRollup returns true if given aggregation can be used to roll pod metrics up to
+// controllers. Empty rollup is valid and means default one.
+func IsValidMetricRollup(rollup metric.AggregationName) bool {
+	if rollup == "" {
+		return true
+	}
+	_, ok := metric.AggregatingFunctions[rollup]
+	return ok
+}
+
 // SortQuery holds options for sort functionality of data select.
 type SortQuery struct {
 	SortByList []SortBy
@@ -72,6 +146,69 @@ var NoSort = &SortQuery{
 	SortByList: []SortBy{},
 }
 
+// TieBreakerSortByList is appended to every requested sort, so that items equal in requested
+// properties keep the same order across requests and pages don't shuffle them.
+var TieBreakerSortByList = []SortBy{
+	{Property: NamespaceProperty, Ascending: true},
+	{Property: NameProperty, Ascending: true},
+	{Property: UIDProperty, Ascending: true},
+}
+
+// Effective returns requested sort followed by tie-breakers on properties not sorted by yet. It is
+// empty when no sort was requested, in which case original order of items is kept.
+func (self *SortQuery) Effective() []SortBy {
+	effective := self.normalized()
+	if len(effective) == 0 {
+		return effective
+	}
+
+	for _, tieBreaker := range TieBreakerSortByList {
+		if !containsProperty(effective, tieBreaker.Property) {
+			effective = append(effective, tieBreaker)
+		}
+	}
+	return effective
+}
+
+// normalized returns requested sort without repeated properties, only the first order requested
+// for a property is used.
+func (self *SortQuery) normalized() []SortBy {
+	normalized := []SortBy{}
+	if self == nil {
+		return normalized
+	}
+
+	for _, sortBy := range self.SortByList {
+		if !containsProperty(normalized, sortBy.Property) {
+			normalized = append(normalized, sortBy)
+		}
+	}
+	return normalized
+}
+
+func containsProperty(sortByList []SortBy, property PropertyName) bool {
+	for _, sortBy := range sortByList {
+		if sortBy.Property == property {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns effective sort in the format of sortBy query parameter, e.g.
+// "d,creationTimestamp,a,namespace,a,name,a,uid".
+func (self *SortQuery) String() string {
+	parts := []string{}
+	for _, sortBy := range self.Effective() {
+		order := "d"
+		if sortBy.Ascending {
+			order = "a"
+		}
+		parts = append(parts, order, string(sortBy.Property))
+	}
+	return strings.Join(parts, ",")
+}
+
 type FilterQuery struct {
 	FilterByList []FilterBy
 }
@@ -79,12 +216,98 @@ type FilterQuery struct {
 type FilterBy struct {
 	Property PropertyName
 	Value    ComparableValue
+	// Operator tells how property values are matched against the value. Empty operator is the same
+	// as FilterOperatorContains.
+	Operator FilterOperator
+	// pattern is the compiled value of regular expression operators.
+	pattern *regexp.Regexp
+}
+
+// FilterOperator is an operator of a filter expression, e.g. ~ of name~"^api-".
+type FilterOperator string
+
+// List of all filter operators.
+const (
+	// FilterOperatorContains keeps items with property containing the value anywhere.
+	FilterOperatorContains FilterOperator = "="
+	// FilterOperatorNotContains keeps items with property not containing the value.
+	FilterOperatorNotContains FilterOperator = "!="
+	// FilterOperatorMatches keeps items with property matching the regular expression.
+	FilterOperatorMatches FilterOperator = "~"
+	// FilterOperatorNotMatches keeps items with property not matching the regular expression.
+	FilterOperatorNotMatches FilterOperator = "!~"
+)
+
+// Matches checks whether given property value of an item passes the filter. Items without the
+// property never pass.
+func (self FilterBy) Matches(value ComparableValue) bool {
+	if value == nil {
+		return false
+	}
+
+	switch self.Operator {
+	case FilterOperatorMatches:
+		return self.pattern.MatchString(filterString(value))
+	case FilterOperatorNotMatches:
+		return !self.pattern.MatchString(filterString(value))
+	case FilterOperatorNotContains:
+		return !contains(value, self.Value)
+	default:
+		return contains(value, self.Value)
+	}
+}
+
+// contains checks whether value contains other value. Values of different types, e.g. creation
+// timestamp and a filter string, are compared as strings.
+func contains(value, other ComparableValue) bool {
+	if reflect.TypeOf(value) == reflect.TypeOf(other) {
+		return value.Contains(other)
+	}
+	return strings.Contains(filterString(value), filterString(other))
+}
+
+// filterString returns string representation of the value filters are matched against.
+func filterString(value ComparableValue) string {
+	if timestamp, ok := value.(StdComparableTime); ok {
+		return time.Time(timestamp).UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprint(value)
 }
 
 var NoFilter = &FilterQuery{
 	FilterByList: []FilterBy{},
 }
 
+// String returns applied filters in the format of filterBy query parameter, e.g.
+// "name,foo,namespace,default". Filters with other operators than contains can't be written that
+// way, the format of filter expressions is used for them, e.g. name~"^api-",status="Failed".
+func (self *FilterQuery) String() string {
+	if self == nil {
+		return ""
+	}
+	parts := []string{}
+	for _, filterBy := range self.FilterByList {
+		if filterBy.Operator != "" && filterBy.Operator != FilterOperatorContains {
+			return self.expression()
+		}
+		parts = append(parts, string(filterBy.Property), fmt.Sprint(filterBy.Value))
+	}
+	return strings.Join(parts, ",")
+}
+
+// expression returns applied filters in the format of filter expressions.
+func (self *FilterQuery) expression() string {
+	parts := []string{}
+	for _, filterBy := range self.FilterByList {
+		operator := filterBy.Operator
+		if operator == "" {
+			operator = FilterOperatorContains
+		}
+		parts = append(parts, string(filterBy.Property)+string(operator)+quoteFilterValue(fmt.Sprint(filterBy.Value)))
+	}
+	return strings.Join(parts, ",")
+}
+
 // NoDataSelect is an option for no data select (same data will be returned).
 var NoDataSelect = NewDataSelectQuery(NoPagination, NoSort, NoFilter, NoMetrics)
 
@@ -0,0 +1,131 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataselect
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// filterOperators lists all filter operators. Longer operators go first so that != is not read as
+// contains operator.
+var filterOperators = []FilterOperator{FilterOperatorNotContains, FilterOperatorNotMatches,
+	FilterOperatorContains, FilterOperatorMatches}
+
+// ParseFilterExpression parses filter expression and returns FilterQuery object. Expression is a list
+// of terms in form property operator value separated by commas or whitespace, for example:
+// name~"^api-",status=Failed. Values containing commas, whitespace or quotes have to be quoted,
+// inside of quotes \" and \\ are the only escape sequences so that regular expressions can be
+// written as they are. All terms have to match for an item to pass the filter.
+func ParseFilterExpression(expression string) (*FilterQuery, error) {
+	filterByList := []FilterBy{}
+	rest := strings.TrimLeftFunc(expression, isFilterSeparator)
+	for len(rest) > 0 {
+		filterBy, remaining, err := parseFilterTerm(rest)
+		if err != nil {
+			return nil, err
+		}
+		filterByList = append(filterByList, *filterBy)
+		rest = strings.TrimLeftFunc(remaining, isFilterSeparator)
+	}
+
+	if len(filterByList) == 0 {
+		return NoFilter, nil
+	}
+	return &FilterQuery{FilterByList: filterByList}, nil
+}
+
+// parseFilterTerm parses the first term of given expression and returns it with the rest of the
+// expression.
+func parseFilterTerm(expression string) (*FilterBy, string, error) {
+	end := strings.IndexAny(expression, "=!~")
+	if end < 0 {
+		return nil, "", fmt.Errorf("filter term %q has no operator", expression)
+	}
+	property := strings.TrimSpace(expression[:end])
+	if len(property) == 0 {
+		return nil, "", fmt.Errorf("filter term %q has no property", expression)
+	}
+
+	rest := expression[end:]
+	var operator FilterOperator
+	for _, candidate := range filterOperators {
+		if strings.HasPrefix(rest, string(candidate)) {
+			operator = candidate
+			break
+		}
+	}
+	if len(operator) == 0 {
+		return nil, "", fmt.Errorf("filter term %q has invalid operator", expression)
+	}
+
+	value, rest, err := parseFilterValue(rest[len(operator):])
+	if err != nil {
+		return nil, "", err
+	}
+
+	filterBy := &FilterBy{
+		Property: PropertyName(property),
+		Value:    StdComparableString(value),
+		Operator: operator,
+	}
+	if operator == FilterOperatorMatches || operator == FilterOperatorNotMatches {
+		filterBy.pattern, err = regexp.Compile(value)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid regular expression of %s filter: %s", property, err)
+		}
+	}
+	return filterBy, rest, nil
+}
+
+// parseFilterValue parses bare or quoted value at the start of given expression and returns it with
+// the rest of the expression.
+func parseFilterValue(expression string) (string, string, error) {
+	if !strings.HasPrefix(expression, `"`) {
+		end := strings.IndexFunc(expression, isFilterSeparator)
+		if end < 0 {
+			end = len(expression)
+		}
+		return expression[:end], expression[end:], nil
+	}
+
+	value := []byte{}
+	for i := 1; i < len(expression); i++ {
+		switch {
+		case expression[i] == '"':
+			return string(value), expression[i+1:], nil
+		case expression[i] == '\\' && i+1 < len(expression) &&
+			(expression[i+1] == '"' || expression[i+1] == '\\'):
+			i++
+		}
+		value = append(value, expression[i])
+	}
+	return "", "", fmt.Errorf("filter value %s has no closing quote", expression)
+}
+
+// quoteFilterValue returns value in the form it can be written to filter expression.
+func quoteFilterValue(value string) string {
+	if len(value) > 0 && !strings.ContainsAny(value, `"\`) && strings.IndexFunc(value, isFilterSeparator) < 0 {
+		return value
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// isFilterSeparator checks whether given rune separates terms of filter expression.
+func isFilterSeparator(r rune) bool {
+	return r == ',' || unicode.IsSpace(r)
+}
@@ -0,0 +1,39 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataselect
+
+import "github.com/kubernetes/dashboard/src/app/backend/api"
+
+// NewListMeta returns list meta of a list selected with given data select query. It echoes the
+// applied filters, sort and page, so that clients can render pagination controls and summaries
+// such as "showing 10 of 25 filtered from 100". filteredTotal is the number of items left after
+// filtering and total is the number of items before filtering.
+func NewListMeta(dsQuery *DataSelectQuery, filteredTotal, total int) api.ListMeta {
+	listMeta := api.ListMeta{
+		TotalItems:        filteredTotal,
+		TotalBeforeFilter: total,
+	}
+	if dsQuery == nil {
+		return listMeta
+	}
+
+	listMeta.Filter = dsQuery.FilterQuery.String()
+	listMeta.Sort = dsQuery.SortQuery.String()
+	if pagination := dsQuery.PaginationQuery; pagination != nil && pagination.IsValidPagination() {
+		listMeta.Page = pagination.Page + 1
+		listMeta.ItemsPerPage = pagination.ItemsPerPage
+	}
+	return listMeta
+}
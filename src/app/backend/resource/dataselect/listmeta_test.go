@@ -0,0 +1,53 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataselect
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+)
+
+func TestNewListMeta(t *testing.T) {
+	cases := []struct {
+		dsQuery              *DataSelectQuery
+		filteredTotal, total int
+		expected             api.ListMeta
+	}{
+		{nil, 3, 3, api.ListMeta{TotalItems: 3, TotalBeforeFilter: 3}},
+		{NoDataSelect, 3, 3, api.ListMeta{TotalItems: 3, TotalBeforeFilter: 3}},
+		{
+			NewDataSelectQuery(NewPaginationQuery(10, 1), NewSortQuery([]string{"d", "name"}),
+				NewFilterQuery([]string{"name", "foo"}), NoMetrics),
+			25, 100,
+			api.ListMeta{
+				TotalItems:        25,
+				TotalBeforeFilter: 100,
+				Filter:            "name,foo",
+				Sort:              "d,name,a,namespace,a,uid",
+				Page:              2,
+				ItemsPerPage:      10,
+			},
+		},
+	}
+	for _, c := range cases {
+		actual := NewListMeta(c.dsQuery, c.filteredTotal, c.total)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("NewListMeta(%#v, %d, %d) == \ngot: %#v, \nexpected %#v", c.dsQuery, c.filteredTotal,
+				c.total, actual, c.expected)
+		}
+	}
+}
@@ -23,6 +23,18 @@ var EmptyPagination = NewPaginationQuery(0, 0)
 // Returns 10 items from page 1
 var DefaultPagination = NewPaginationQuery(10, 0)
 
+// DefaultPaginationLimits are pagination limits used when no limits are configured.
+var DefaultPaginationLimits = PaginationLimits{DefaultItemsPerPage: 10, MaxItemsPerPage: 100}
+
+// PaginationLimits restrict pagination requested by clients, so that they can't request
+// unbounded pages.
+type PaginationLimits struct {
+	// Number of items per page used when client doesn't specify valid one.
+	DefaultItemsPerPage int
+	// Maximum number of items per page. Larger pages are clamped to this size.
+	MaxItemsPerPage int
+}
+
 // PaginationQuery structure represents pagination settings
 type PaginationQuery struct {
 	// How many items per page should be returned
@@ -58,3 +70,22 @@ func (p *PaginationQuery) GetPaginationSettings(itemsCount int) (startIndex int,
 
 	return startIndex, endIndex
 }
+
+// Limit returns pagination query with number of items per page within given limits. Negative
+// number of items per page would disable pagination, default number of items is used instead.
+func (p *PaginationQuery) Limit(limits PaginationLimits) *PaginationQuery {
+	itemsPerPage := p.ItemsPerPage
+	if itemsPerPage < 0 {
+		itemsPerPage = limits.DefaultItemsPerPage
+	}
+	if itemsPerPage > limits.MaxItemsPerPage {
+		itemsPerPage = limits.MaxItemsPerPage
+	}
+
+	page := p.Page
+	if page < 0 {
+		page = 0
+	}
+
+	return NewPaginationQuery(itemsPerPage, page)
+}
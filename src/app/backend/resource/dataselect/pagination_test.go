@@ -78,3 +78,24 @@ func TestGetPaginationSettings(t *testing.T) {
 		}
 	}
 }
+
+func TestLimit(t *testing.T) {
+	limits := PaginationLimits{DefaultItemsPerPage: 10, MaxItemsPerPage: 50}
+	cases := []struct {
+		pQuery   *PaginationQuery
+		expected *PaginationQuery
+	}{
+		{&PaginationQuery{20, 1}, &PaginationQuery{20, 1}},
+		{&PaginationQuery{0, 0}, &PaginationQuery{0, 0}},
+		{&PaginationQuery{1000000, 2}, &PaginationQuery{50, 2}},
+		{&PaginationQuery{-1, 0}, &PaginationQuery{10, 0}},
+		{&PaginationQuery{10, -1}, &PaginationQuery{10, 0}},
+	}
+
+	for _, c := range cases {
+		actual := c.pQuery.Limit(limits)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Limit(%+v) == %+v, expected %+v", c.pQuery, actual, c.expected)
+		}
+	}
+}
@@ -24,4 +24,10 @@ const (
 	CreationTimestampProperty = "creationTimestamp"
 	NamespaceProperty         = "namespace"
 	StatusProperty            = "status"
+	UIDProperty               = "uid"
+	QOSClassProperty          = "qosClass"
+	PreemptibleProperty       = "preemptible"
+	// Current usage, supported only by data cells with metrics.
+	CpuUsageProperty    = "cpuUsage"
+	MemoryUsageProperty = "memoryUsage"
 )
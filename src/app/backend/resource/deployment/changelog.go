@@ -0,0 +1,247 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+
+	"github.com/kubernetes/dashboard/src/app/backend/settings"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	client "k8s.io/client-go/kubernetes"
+	api "k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+const (
+	// RevisionAnnotationKey is annotation key of replica sets for revision of their deployment.
+	RevisionAnnotationKey = "deployment.kubernetes.io/revision"
+
+	// ChangeCauseAnnotationKey is annotation key for the cause of a change, e.g. set by kubectl
+	// --record.
+	ChangeCauseAnnotationKey = "kubernetes.io/change-cause"
+)
+
+// Kinds of changelog entries.
+const (
+	// ChangeKindRevision is a change rolled out as a new revision of the deployment.
+	ChangeKindRevision = "revision"
+
+	// ChangeKindWebhook is a change triggered by a dashboard webhook.
+	ChangeKindWebhook = "webhook"
+)
+
+// Change is a single entry of deployment changelog.
+type Change struct {
+	// Time of the change.
+	Time metaV1.Time `json:"time"`
+
+	// Kind of the change, e.g. revision.
+	Kind string `json:"kind"`
+
+	// Revision of the deployment. Set only for changes of revision kind.
+	Revision int64 `json:"revision,omitempty"`
+
+	// Human readable summary of the change.
+	Description string `json:"description"`
+
+	// Details of the change, e.g. changed images.
+	Details []string `json:"details"`
+
+	// Who made the change, if known.
+	Author string `json:"author,omitempty"`
+}
+
+// Changelog is a list of changes of a deployment, from the most recent.
+type Changelog struct {
+	Changes []Change `json:"changes"`
+}
+
+// GetDeploymentChangelog assembles changelog of given deployment from revisions of its replica sets
+// and given audit records of webhook calls.
+func GetDeploymentChangelog(client client.Interface, records []settings.WebhookRecord,
+	namespace, name string) (*Changelog, error) {
+	log.Printf("Getting changelog of %s deployment in %s namespace", name, namespace)
+
+	_, revisions, err := getDeploymentWithRevisions(client, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := getRevisionChanges(revisions)
+	for _, record := range records {
+		if record.Namespace == namespace && record.Deployment == name {
+			changes = append(changes, getWebhookChange(record))
+		}
+	}
+
+	sort.Stable(changesByTime(changes))
+	return &Changelog{Changes: changes}, nil
+}
+
+// getRevisionChanges returns a change for every given revision. Images of each revision are
+// compared with the previous one.
+func getRevisionChanges(revisions []extensions.ReplicaSet) []Change {
+	changes := make([]Change, 0, len(revisions))
+	for i, replicaSet := range revisions {
+		revision := getRevision(replicaSet)
+		change := Change{
+			Time:        replicaSet.CreationTimestamp,
+			Kind:        ChangeKindRevision,
+			Revision:    revision,
+			Description: fmt.Sprintf("Rolled out revision %d", revision),
+		}
+		if cause := replicaSet.Annotations[ChangeCauseAnnotationKey]; cause != "" {
+			change.Description = cause
+		}
+
+		if i == 0 {
+			change.Details = getImageDetails(nil, replicaSet.Spec.Template)
+		} else {
+			change.Details = getImageDetails(&revisions[i-1].Spec.Template, replicaSet.Spec.Template)
+			if len(change.Details) == 0 {
+				change.Details = append(change.Details, "Pod template changed")
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// getImageDetails describes changes of container images between given pod templates. All images
+// are listed as new if there is no previous template.
+func getImageDetails(previous *api.PodTemplateSpec, current api.PodTemplateSpec) []string {
+	previousImages := make(map[string]string)
+	if previous != nil {
+		for _, container := range previous.Spec.Containers {
+			previousImages[container.Name] = container.Image
+		}
+	}
+
+	details := make([]string, 0)
+	for _, container := range current.Spec.Containers {
+		previousImage, ok := previousImages[container.Name]
+		if !ok {
+			details = append(details, fmt.Sprintf("Container %s uses image %s", container.Name,
+				container.Image))
+		} else if previousImage != container.Image {
+			details = append(details, fmt.Sprintf("Image of container %s changed from %s to %s",
+				container.Name, previousImage, container.Image))
+		}
+		delete(previousImages, container.Name)
+	}
+
+	removed := make([]string, 0, len(previousImages))
+	for name := range previousImages {
+		removed = append(removed, name)
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		details = append(details, fmt.Sprintf("Container %s removed", name))
+	}
+	return details
+}
+
+func getWebhookChange(record settings.WebhookRecord) Change {
+	change := Change{
+		Time:        record.Time,
+		Kind:        ChangeKindWebhook,
+		Description: fmt.Sprintf("Webhook %s triggered %s", record.Webhook, record.Action),
+		Details:     make([]string, 0),
+		Author:      record.Webhook,
+	}
+	if record.Image != "" {
+		change.Details = append(change.Details, fmt.Sprintf("Requested image %s", record.Image))
+	}
+	if record.Error != "" {
+		change.Details = append(change.Details, fmt.Sprintf("Failed: %s", record.Error))
+	}
+	return change
+}
+
+// getDeploymentWithRevisions returns deployment with given name and replica sets keeping its
+// revisions, from the oldest revision.
+func getDeploymentWithRevisions(client client.Interface, namespace, name string) (
+	*extensions.Deployment, []extensions.ReplicaSet, error) {
+	deployment, err := client.ExtensionsV1beta1().Deployments(namespace).Get(name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	selector, err := metaV1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, nil, err
+	}
+	replicaSets, err := client.ExtensionsV1beta1().ReplicaSets(namespace).List(
+		metaV1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return deployment, getDeploymentRevisions(deployment, replicaSets.Items), nil
+}
+
+// getDeploymentRevisions returns replica sets of given deployment that keep its revisions, from the
+// oldest revision.
+func getDeploymentRevisions(deployment *extensions.Deployment,
+	replicaSets []extensions.ReplicaSet) []extensions.ReplicaSet {
+	revisions := make([]extensions.ReplicaSet, 0)
+	for _, replicaSet := range replicaSets {
+		if isOwnedBy(replicaSet.ObjectMeta, deployment.UID) && getRevision(replicaSet) > 0 {
+			revisions = append(revisions, replicaSet)
+		}
+	}
+	sort.Sort(replicaSetsByRevision(revisions))
+	return revisions
+}
+
+// isOwnedBy returns true if object with given meta is controlled by object with given UID. Objects
+// without controller are considered owned, as older clusters do not set owner references.
+func isOwnedBy(meta metaV1.ObjectMeta, uid types.UID) bool {
+	for _, reference := range meta.OwnerReferences {
+		if reference.Controller != nil && *reference.Controller {
+			return reference.UID == uid
+		}
+	}
+	return true
+}
+
+func getRevision(replicaSet extensions.ReplicaSet) int64 {
+	revision, err := strconv.ParseInt(replicaSet.Annotations[RevisionAnnotationKey], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return revision
+}
+
+// replicaSetsByRevision sorts replica sets from the oldest revision.
+type replicaSetsByRevision []extensions.ReplicaSet
+
+func (self replicaSetsByRevision) Len() int      { return len(self) }
+func (self replicaSetsByRevision) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+func (self replicaSetsByRevision) Less(i, j int) bool {
+	return getRevision(self[i]) < getRevision(self[j])
+}
+
+// changesByTime sorts changes from the most recent.
+type changesByTime []Change
+
+func (self changesByTime) Len() int      { return len(self) }
+func (self changesByTime) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+func (self changesByTime) Less(i, j int) bool {
+	return self[j].Time.Before(self[i].Time)
+}
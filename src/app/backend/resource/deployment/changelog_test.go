@@ -0,0 +1,97 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/settings"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	api "k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+func getTestRevision(revision, cause string, created time.Time, images ...string) *extensions.ReplicaSet {
+	replicaSet := &extensions.ReplicaSet{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:              "app-" + revision,
+			Namespace:         "prod",
+			Labels:            map[string]string{"app": "app"},
+			Annotations:       map[string]string{RevisionAnnotationKey: revision},
+			CreationTimestamp: metaV1.NewTime(created),
+		},
+	}
+	if cause != "" {
+		replicaSet.Annotations[ChangeCauseAnnotationKey] = cause
+	}
+	for i, image := range images {
+		replicaSet.Spec.Template.Spec.Containers = append(replicaSet.Spec.Template.Spec.Containers,
+			api.Container{Name: []string{"app", "proxy"}[i], Image: image})
+	}
+	return replicaSet
+}
+
+func TestGetDeploymentChangelog(t *testing.T) {
+	start := time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+	deployment := &extensions.Deployment{
+		ObjectMeta: metaV1.ObjectMeta{Name: "app", Namespace: "prod"},
+		Spec: extensions.DeploymentSpec{
+			Selector: &metaV1.LabelSelector{MatchLabels: map[string]string{"app": "app"}},
+		},
+	}
+	testClient := fake.NewSimpleClientset(deployment,
+		getTestRevision("2", "kubectl set image deployment/app app=app:v2", start.Add(time.Hour),
+			"app:v2", "proxy:v1"),
+		getTestRevision("1", "", start, "app:v1", "proxy:v1"),
+		getTestRevision("3", "", start.Add(3*time.Hour), "app:v2"))
+	records := []settings.WebhookRecord{
+		{Webhook: "ci", Namespace: "prod", Deployment: "app", Action: settings.WebhookActionSetImage,
+			Image: "app:v3", Time: metaV1.NewTime(start.Add(2 * time.Hour)), Error: "conflict"},
+		{Webhook: "ci", Namespace: "prod", Deployment: "other", Action: settings.WebhookActionRestart,
+			Time: metaV1.NewTime(start)},
+	}
+	expected := &Changelog{Changes: []Change{
+		{
+			Time: metaV1.NewTime(start.Add(3 * time.Hour)), Kind: ChangeKindRevision, Revision: 3,
+			Description: "Rolled out revision 3", Details: []string{"Container proxy removed"},
+		},
+		{
+			Time: metaV1.NewTime(start.Add(2 * time.Hour)), Kind: ChangeKindWebhook,
+			Description: "Webhook ci triggered setImage", Author: "ci",
+			Details: []string{"Requested image app:v3", "Failed: conflict"},
+		},
+		{
+			Time: metaV1.NewTime(start.Add(time.Hour)), Kind: ChangeKindRevision, Revision: 2,
+			Description: "kubectl set image deployment/app app=app:v2",
+			Details:     []string{"Image of container app changed from app:v1 to app:v2"},
+		},
+		{
+			Time: metaV1.NewTime(start), Kind: ChangeKindRevision, Revision: 1,
+			Description: "Rolled out revision 1",
+			Details:     []string{"Container app uses image app:v1", "Container proxy uses image proxy:v1"},
+		},
+	}}
+
+	actual, err := GetDeploymentChangelog(testClient, records, "prod", "app")
+	if err != nil {
+		t.Fatalf("GetDeploymentChangelog() returned error: %s", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GetDeploymentChangelog() == \ngot: %#v, \nexpected %#v", actual, expected)
+	}
+}
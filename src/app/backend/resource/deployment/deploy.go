@@ -20,11 +20,15 @@ import (
 	"strings"
 
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	client "k8s.io/client-go/kubernetes"
 	api "k8s.io/client-go/pkg/api/v1"
+	apps "k8s.io/client-go/pkg/apis/apps/v1beta1"
 	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
 	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
 	kubectlResource "k8s.io/kubernetes/pkg/kubectl/resource"
@@ -35,6 +39,22 @@ const (
 	DescriptionAnnotationKey = "description"
 )
 
+// Kinds of workloads that can be deployed from AppDeploymentSpec.
+const (
+	AppKindDeployment  = "deployment"
+	AppKindStatefulSet = "statefulset"
+	AppKindDaemonSet   = "daemonset"
+)
+
+// Pod management policies of stateful sets.
+const (
+	// OrderedReadyPodManagement creates pods one by one, in order, waiting for each to be ready.
+	OrderedReadyPodManagement = "OrderedReady"
+
+	// ParallelPodManagement creates all pods at once.
+	ParallelPodManagement = "Parallel"
+)
+
 // AppDeploymentSpec is a specification for an app deployment.
 type AppDeploymentSpec struct {
 	// Name of the application.
@@ -83,6 +103,43 @@ type AppDeploymentSpec struct {
 
 	// Whether to run the container as privileged user (essentially equivalent to root on the host).
 	RunAsPrivileged bool `json:"runAsPrivileged"`
+
+	// Node selector of the pods, e.g. a node pool label of system agents.
+	NodeSelector map[string]string `json:"nodeSelector"`
+
+	// Tolerations of the pods, allowing them to run on tainted nodes.
+	Tolerations []api.Toleration `json:"tolerations"`
+
+	// Kind of the deployed workload, deployment, statefulset or daemonset. Deployment is created if
+	// empty.
+	Kind string `json:"kind"`
+
+	// Templates of persistent volume claims created for every pod of a stateful set. Ignored for
+	// other kinds.
+	VolumeClaimTemplates []VolumeClaimTemplate `json:"volumeClaimTemplates"`
+
+	// Pod management policy of a stateful set, OrderedReady or Parallel. OrderedReady is used if
+	// empty. Ignored for other kinds.
+	PodManagementPolicy string `json:"podManagementPolicy"`
+}
+
+// VolumeClaimTemplate is a specification of persistent volume claim created for every pod of a
+// stateful set and mounted into its container.
+type VolumeClaimTemplate struct {
+	// Name of the claim template, also used as the name of the volume.
+	Name string `json:"name"`
+
+	// Path in the container the volume is mounted at.
+	MountPath string `json:"mountPath"`
+
+	// Requested size of the volume.
+	Size resource.Quantity `json:"size"`
+
+	// Name of the storage class of the volume. Default storage class is used if not specified.
+	StorageClass *string `json:"storageClass"`
+
+	// Access mode of the volume, e.g. ReadWriteOnce. ReadWriteOnce is used if empty.
+	AccessMode api.PersistentVolumeAccessMode `json:"accessMode"`
 }
 
 // AppDeploymentFromFileSpec is a specification for deployment from file
@@ -98,6 +155,19 @@ type AppDeploymentFromFileSpec struct {
 
 	// Whether validate content before creation or not
 	Validate bool `json:"validate"`
+
+	// Kustomization directory rendered into file content. Content is ignored if it is set.
+	Kustomization *KustomizationSpec `json:"kustomization,omitempty"`
+
+	// Values of parameters referenced in file content as ${NAME}, e.g. image tag or replica count
+	// of an environment.
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// HTTPS or S3 URL of file fetched by the backend. Content is ignored if it is set.
+	URL string `json:"url,omitempty"`
+
+	// SHA256 checksum in hex of file fetched from URL. Required if URL is set.
+	SHA256 string `json:"sha256,omitempty"`
 }
 
 // AppDeploymentFromFileResponse is a specification for deployment from file
@@ -149,9 +219,9 @@ type Protocols struct {
 }
 
 // DeployApp deploys an app based on the given configuration. The app is deployed using the given
-// client. App deployment consists of a deployment and an optional service. Both of them
-// share common labels.
-func DeployApp(spec *AppDeploymentSpec, client client.Interface) error {
+// client. App deployment consists of a deployment or a stateful set and an optional service. All of
+// them share common labels. Created objects are returned as stored by the API server.
+func DeployApp(spec *AppDeploymentSpec, client client.Interface) ([]runtime.Object, error) {
 	log.Printf("Deploying %s application into %s namespace", spec.Name, spec.Namespace)
 
 	annotations := map[string]string{}
@@ -191,7 +261,9 @@ func DeployApp(spec *AppDeploymentSpec, client client.Interface) error {
 		containerSpec.Resources.Requests[api.ResourceMemory] = *spec.MemoryRequirement
 	}
 	podSpec := api.PodSpec{
-		Containers: []api.Container{containerSpec},
+		Containers:   []api.Container{containerSpec},
+		NodeSelector: spec.NodeSelector,
+		Tolerations:  spec.Tolerations,
 	}
 	if spec.ImagePullSecret != nil {
 		podSpec.ImagePullSecrets = []api.LocalObjectReference{{Name: *spec.ImagePullSecret}}
@@ -202,6 +274,21 @@ func DeployApp(spec *AppDeploymentSpec, client client.Interface) error {
 		Spec:       podSpec,
 	}
 
+	switch spec.Kind {
+	case "", AppKindDeployment:
+		return deployDeployment(spec, client, objectMeta, podTemplate)
+	case AppKindStatefulSet:
+		return deployStatefulSet(spec, client, objectMeta, podTemplate)
+	case AppKindDaemonSet:
+		return deployDaemonSet(spec, client, objectMeta, podTemplate)
+	default:
+		return nil, k8serrors.NewBadRequest(fmt.Sprintf("Unsupported application kind: %s", spec.Kind))
+	}
+}
+
+// deployDeployment creates a deployment of given pod template and an optional service.
+func deployDeployment(spec *AppDeploymentSpec, client client.Interface, objectMeta metaV1.ObjectMeta,
+	podTemplate api.PodTemplateSpec) ([]runtime.Object, error) {
 	deployment := &extensions.Deployment{
 		ObjectMeta: objectMeta,
 		Spec: extensions.DeploymentSpec{
@@ -209,48 +296,174 @@ func DeployApp(spec *AppDeploymentSpec, client client.Interface) error {
 			Template: podTemplate,
 		},
 	}
-	_, err := client.ExtensionsV1beta1().Deployments(spec.Namespace).Create(deployment)
+	created, err := client.ExtensionsV1beta1().Deployments(spec.Namespace).Create(deployment)
 
 	if err != nil {
 		// TODO(bryk): Roll back created resources in case of error.
-		return err
+		return nil, err
+	}
+
+	return createService(spec, client, objectMeta, []runtime.Object{created})
+}
+
+// deployDaemonSet creates a daemon set of given pod template and an optional service.
+func deployDaemonSet(spec *AppDeploymentSpec, client client.Interface, objectMeta metaV1.ObjectMeta,
+	podTemplate api.PodTemplateSpec) ([]runtime.Object, error) {
+	daemonSet := &extensions.DaemonSet{
+		ObjectMeta: objectMeta,
+		Spec: extensions.DaemonSetSpec{
+			Template: podTemplate,
+		},
+	}
+	created, err := client.ExtensionsV1beta1().DaemonSets(spec.Namespace).Create(daemonSet)
+	if err != nil {
+		return nil, err
+	}
+
+	return createService(spec, client, objectMeta, []runtime.Object{created})
+}
+
+// createService creates a service exposing pods of the app and appends it to already created
+// objects. The service is created only if there is at least one port mapping.
+func createService(spec *AppDeploymentSpec, client client.Interface, objectMeta metaV1.ObjectMeta,
+	objects []runtime.Object) ([]runtime.Object, error) {
+	if len(spec.PortMappings) == 0 {
+		return objects, nil
+	}
+
+	service := &api.Service{
+		ObjectMeta: objectMeta,
+		Spec: api.ServiceSpec{
+			Selector: objectMeta.Labels,
+			Ports:    getServicePorts(spec.PortMappings),
+		},
+	}
+
+	if spec.IsExternal {
+		service.Spec.Type = api.ServiceTypeLoadBalancer
+	} else {
+		service.Spec.Type = api.ServiceTypeClusterIP
+	}
+
+	created, err := client.CoreV1().Services(spec.Namespace).Create(service)
+
+	// TODO(bryk): Roll back created resources in case of error.
+	if err != nil {
+		return nil, err
+	}
+	return append(objects, created), nil
+}
+
+// deployStatefulSet creates a headless service governing the stateful set, the stateful set of
+// given pod template with volume claim templates and an optional external service.
+func deployStatefulSet(spec *AppDeploymentSpec, client client.Interface, objectMeta metaV1.ObjectMeta,
+	podTemplate api.PodTemplateSpec) ([]runtime.Object, error) {
+	switch spec.PodManagementPolicy {
+	case "", OrderedReadyPodManagement:
+	case ParallelPodManagement:
+		// Stateful sets of the supported API version always create pods in order.
+		return nil, k8serrors.NewBadRequest("Parallel pod management is not supported by the cluster API")
+	default:
+		return nil, k8serrors.NewBadRequest(fmt.Sprintf("Unsupported pod management policy: %s",
+			spec.PodManagementPolicy))
+	}
+
+	claimTemplates := make([]api.PersistentVolumeClaim, 0)
+	container := &podTemplate.Spec.Containers[0]
+	for _, template := range spec.VolumeClaimTemplates {
+		claimTemplates = append(claimTemplates, getPersistentVolumeClaim(template))
+		container.VolumeMounts = append(container.VolumeMounts,
+			api.VolumeMount{Name: template.Name, MountPath: template.MountPath})
+	}
+
+	// Governing service has to exist before pods of the stateful set are created, so that they get
+	// their network identities.
+	service := &api.Service{
+		ObjectMeta: objectMeta,
+		Spec: api.ServiceSpec{
+			Selector:  objectMeta.Labels,
+			ClusterIP: api.ClusterIPNone,
+			Ports:     getServicePorts(spec.PortMappings),
+		},
+	}
+	createdService, err := client.CoreV1().Services(spec.Namespace).Create(service)
+	if err != nil {
+		return nil, err
+	}
+
+	statefulSet := &apps.StatefulSet{
+		ObjectMeta: objectMeta,
+		Spec: apps.StatefulSetSpec{
+			Replicas:             &spec.Replicas,
+			Template:             podTemplate,
+			VolumeClaimTemplates: claimTemplates,
+			ServiceName:          spec.Name,
+		},
 	}
+	createdStatefulSet, err := client.AppsV1beta1().StatefulSets(spec.Namespace).Create(statefulSet)
+	if err != nil {
+		// TODO(bryk): Roll back created resources in case of error.
+		return nil, err
+	}
+	objects := []runtime.Object{createdService, createdStatefulSet}
 
-	if len(spec.PortMappings) > 0 {
-		service := &api.Service{
-			ObjectMeta: objectMeta,
+	if spec.IsExternal && len(spec.PortMappings) > 0 {
+		externalMeta := objectMeta
+		externalMeta.Name = spec.Name + "-external"
+		external := &api.Service{
+			ObjectMeta: externalMeta,
 			Spec: api.ServiceSpec{
-				Selector: labels,
+				Selector: objectMeta.Labels,
+				Type:     api.ServiceTypeLoadBalancer,
+				Ports:    getServicePorts(spec.PortMappings),
 			},
 		}
-
-		if spec.IsExternal {
-			service.Spec.Type = api.ServiceTypeLoadBalancer
-		} else {
-			service.Spec.Type = api.ServiceTypeClusterIP
-		}
-
-		for _, portMapping := range spec.PortMappings {
-			servicePort :=
-				api.ServicePort{
-					Protocol: portMapping.Protocol,
-					Port:     portMapping.Port,
-					Name:     generatePortMappingName(portMapping),
-					TargetPort: intstr.IntOrString{
-						Type:   intstr.Int,
-						IntVal: portMapping.TargetPort,
-					},
-				}
-			service.Spec.Ports = append(service.Spec.Ports, servicePort)
+		createdExternal, err := client.CoreV1().Services(spec.Namespace).Create(external)
+		if err != nil {
+			// TODO(bryk): Roll back created resources in case of error.
+			return nil, err
 		}
+		objects = append(objects, createdExternal)
+	}
 
-		_, err = client.CoreV1().Services(spec.Namespace).Create(service)
+	return objects, nil
+}
 
-		// TODO(bryk): Roll back created resources in case of error.
-		return err
+// getPersistentVolumeClaim returns persistent volume claim of given template.
+func getPersistentVolumeClaim(template VolumeClaimTemplate) api.PersistentVolumeClaim {
+	accessMode := template.AccessMode
+	if accessMode == "" {
+		accessMode = api.ReadWriteOnce
+	}
+	return api.PersistentVolumeClaim{
+		ObjectMeta: metaV1.ObjectMeta{Name: template.Name},
+		Spec: api.PersistentVolumeClaimSpec{
+			AccessModes: []api.PersistentVolumeAccessMode{accessMode},
+			Resources: api.ResourceRequirements{
+				Requests: api.ResourceList{api.ResourceStorage: template.Size},
+			},
+			StorageClassName: template.StorageClass,
+		},
 	}
+}
 
-	return nil
+// getServicePorts returns service ports of given port mappings.
+func getServicePorts(portMappings []PortMapping) []api.ServicePort {
+	var result []api.ServicePort
+	for _, portMapping := range portMappings {
+		servicePort :=
+			api.ServicePort{
+				Protocol: portMapping.Protocol,
+				Port:     portMapping.Port,
+				Name:     generatePortMappingName(portMapping),
+				TargetPort: intstr.IntOrString{
+					Type:   intstr.Int,
+					IntVal: portMapping.TargetPort,
+				},
+			}
+		result = append(result, servicePort)
+	}
+	return result
 }
 
 // GetAvailableProtocols returns list of available protocols. Currently it is TCP and UDP.
@@ -292,28 +505,68 @@ func CreateObjectFromInfoFn(info *kubectlResource.Info) (bool, error) {
 	return createdResource != nil, err
 }
 
-// DeployAppFromFile deploys an app based on the given yaml or json file.
+// DeployAppFromFile deploys an app based on the given yaml or json file. Documents of the file are
+// deployed in order of their dependencies: namespaces, custom resource definitions, RBAC, configs
+// and workloads, followed by custom resources once their definitions are established.
 func DeployAppFromFile(spec *AppDeploymentFromFileSpec,
 	createObjectFromInfoFn createObjectFromInfo) (bool, error) {
-	const emptyCacheDir = ""
-	validate := spec.Validate
-
-	factory := cmdutil.NewFactory(nil)
-	schema, err := factory.Validator(validate, emptyCacheDir)
+	content, err := RenderDeploymentFile(spec)
 	if err != nil {
 		return false, err
 	}
-
-	mapper, typer := factory.Object()
-	reader := strings.NewReader(spec.Content)
+	spec.Content = content
 
 	fmt.Printf("Namespace for deploy from file: %s\n", spec.Namespace)
 
-	builder := kubectlResource.NewBuilder(mapper, kubectlResource.LegacyCategoryExpander, typer,
-		kubectlResource.ClientMapperFunc(factory.ClientForMapping), factory.Decoder(true)).
-		Schema(schema).
+	deployedResourcesCount := 0
+	for _, phase := range getManifestPhases(spec.Content) {
+		infos, err := deployManifestPhase(spec, phase, createObjectFromInfoFn)
+		deployedResourcesCount += len(infos)
+		if err != nil {
+			return deployedResourcesCount > 0, common.LocalizeError(err)
+		}
+
+		for _, info := range infos {
+			if err := waitForEstablished(info); err != nil {
+				return true, err
+			}
+		}
+	}
+
+	return deployedResourcesCount > 0, nil
+}
+
+// deployManifestPhase deploys manifests of given phase and returns infos of deployed objects.
+// Objects of unstructured phases are decoded with discovery of the cluster, so that custom
+// resources of definitions deployed in previous phases are known.
+func deployManifestPhase(spec *AppDeploymentFromFileSpec, phase manifestPhase,
+	createObjectFromInfoFn createObjectFromInfo) ([]*kubectlResource.Info, error) {
+	const emptyCacheDir = ""
+
+	factory := cmdutil.NewFactory(nil)
+	var builder *kubectlResource.Builder
+	if phase.unstructured {
+		mapper, typer, err := factory.UnstructuredObject()
+		if err != nil {
+			return nil, err
+		}
+		builder = kubectlResource.NewBuilder(mapper, kubectlResource.LegacyCategoryExpander, typer,
+			kubectlResource.ClientMapperFunc(factory.UnstructuredClientForMapping),
+			unstructured.UnstructuredJSONScheme)
+	} else {
+		schema, err := factory.Validator(spec.Validate, emptyCacheDir)
+		if err != nil {
+			return nil, err
+		}
+		mapper, typer := factory.Object()
+		builder = kubectlResource.NewBuilder(mapper, kubectlResource.LegacyCategoryExpander, typer,
+			kubectlResource.ClientMapperFunc(factory.ClientForMapping), factory.Decoder(true)).
+			Schema(schema)
+	}
+
+	builder = builder.
 		NamespaceParam(spec.Namespace).
-		Stream(reader, spec.Name).
+		Stream(strings.NewReader(phase.content), spec.Name).
 		Flatten()
 
 	if strings.Compare(spec.Namespace, "_all") != 0 {
@@ -322,17 +575,17 @@ func DeployAppFromFile(spec *AppDeploymentFromFileSpec,
 
 	r := builder.Do()
 
-	deployedResourcesCount := 0
-
-	err = r.Visit(func(info *kubectlResource.Info, err error) error {
+	infos := make([]*kubectlResource.Info, 0)
+	err := r.Visit(func(info *kubectlResource.Info, err error) error {
+		if err != nil {
+			return err
+		}
 		isDeployed, err := createObjectFromInfoFn(info)
 		if isDeployed {
-			deployedResourcesCount++
+			infos = append(infos, info)
 			log.Printf("%s is deployed", info.Name)
 		}
 		return err
 	})
-
-	err = common.LocalizeError(err)
-	return deployedResourcesCount > 0, err
+	return infos, err
 }
@@ -19,10 +19,12 @@ import (
 	"regexp"
 	"testing"
 
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 	api "k8s.io/client-go/pkg/api/v1"
+	apps "k8s.io/client-go/pkg/apis/apps/v1beta1"
 	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
 	core "k8s.io/client-go/testing"
 )
@@ -174,6 +176,115 @@ func TestDeployWithResourceRequirements(t *testing.T) {
 	}
 }
 
+func TestDeployStatefulSet(t *testing.T) {
+	storageClass := "fast"
+	size := resource.MustParse("1Gi")
+	spec := &AppDeploymentSpec{
+		Namespace:    "foo-namespace",
+		Name:         "foo-name",
+		Kind:         AppKindStatefulSet,
+		Replicas:     3,
+		PortMappings: []PortMapping{{Port: 80, TargetPort: 8080, Protocol: api.ProtocolTCP}},
+		IsExternal:   true,
+		VolumeClaimTemplates: []VolumeClaimTemplate{
+			{Name: "data", MountPath: "/data", Size: size, StorageClass: &storageClass},
+		},
+	}
+	testClient := fake.NewSimpleClientset()
+
+	objects, err := DeployApp(spec, testClient)
+	if err != nil {
+		t.Fatalf("DeployApp() returned error: %s", err)
+	}
+	if len(objects) != 3 {
+		t.Errorf("Expected DeployApp() to return 3 created objects but got %#v", objects)
+	}
+
+	actions := testClient.Actions()
+	if len(actions) != 3 {
+		t.Fatalf("Expected headless service, stateful set and external service to be created but got %#v",
+			actions)
+	}
+
+	headless := actions[0].(core.CreateActionImpl).GetObject().(*api.Service)
+	if headless.Name != "foo-name" || headless.Spec.ClusterIP != api.ClusterIPNone {
+		t.Errorf("Expected headless service foo-name to be created first but got %#v", headless)
+	}
+
+	statefulSet := actions[1].(core.CreateActionImpl).GetObject().(*apps.StatefulSet)
+	if statefulSet.Spec.ServiceName != "foo-name" || *statefulSet.Spec.Replicas != 3 {
+		t.Errorf("Expected stateful set governed by foo-name service with 3 replicas but got %#v",
+			statefulSet.Spec)
+	}
+	expectedClaims := []api.PersistentVolumeClaim{{
+		ObjectMeta: metaV1.ObjectMeta{Name: "data"},
+		Spec: api.PersistentVolumeClaimSpec{
+			AccessModes:      []api.PersistentVolumeAccessMode{api.ReadWriteOnce},
+			Resources:        api.ResourceRequirements{Requests: api.ResourceList{api.ResourceStorage: size}},
+			StorageClassName: &storageClass,
+		},
+	}}
+	if !reflect.DeepEqual(statefulSet.Spec.VolumeClaimTemplates, expectedClaims) {
+		t.Errorf("Expected volume claim templates to be %#v but got %#v", expectedClaims,
+			statefulSet.Spec.VolumeClaimTemplates)
+	}
+	expectedMounts := []api.VolumeMount{{Name: "data", MountPath: "/data"}}
+	if mounts := statefulSet.Spec.Template.Spec.Containers[0].VolumeMounts; !reflect.DeepEqual(mounts, expectedMounts) {
+		t.Errorf("Expected volume mounts to be %#v but got %#v", expectedMounts, mounts)
+	}
+
+	external := actions[2].(core.CreateActionImpl).GetObject().(*api.Service)
+	if external.Name != "foo-name-external" || external.Spec.Type != api.ServiceTypeLoadBalancer {
+		t.Errorf("Expected external service foo-name-external to be created but got %#v", external)
+	}
+}
+
+func TestDeployDaemonSet(t *testing.T) {
+	spec := &AppDeploymentSpec{
+		Namespace:    "kube-system",
+		Name:         "foo-agent",
+		Kind:         AppKindDaemonSet,
+		NodeSelector: map[string]string{"pool": "system"},
+		Tolerations: []api.Toleration{
+			{Key: "dedicated", Operator: api.TolerationOpExists, Effect: api.TaintEffectNoSchedule},
+		},
+	}
+	testClient := fake.NewSimpleClientset()
+
+	if _, err := DeployApp(spec, testClient); err != nil {
+		t.Fatalf("DeployApp() returned error: %s", err)
+	}
+	if len(testClient.Actions()) != 1 {
+		t.Fatalf("Expected one create action but got %#v", testClient.Actions())
+	}
+
+	daemonSet := testClient.Actions()[0].(core.CreateActionImpl).GetObject().(*extensions.DaemonSet)
+	podSpec := daemonSet.Spec.Template.Spec
+	if !reflect.DeepEqual(podSpec.NodeSelector, spec.NodeSelector) ||
+		!reflect.DeepEqual(podSpec.Tolerations, spec.Tolerations) {
+		t.Errorf("Expected daemon set pods with node selector %#v and tolerations %#v but got %#v",
+			spec.NodeSelector, spec.Tolerations, podSpec)
+	}
+}
+
+func TestDeployAppInvalidKind(t *testing.T) {
+	cases := []*AppDeploymentSpec{
+		{Name: "foo-name", Kind: "job"},
+		{Name: "foo-name", Kind: AppKindStatefulSet, PodManagementPolicy: ParallelPodManagement},
+		{Name: "foo-name", Kind: AppKindStatefulSet, PodManagementPolicy: "Random"},
+	}
+	for _, spec := range cases {
+		testClient := fake.NewSimpleClientset()
+		_, err := DeployApp(spec, testClient)
+		if !k8serrors.IsBadRequest(err) {
+			t.Errorf("DeployApp(%#v) should return bad request but got %v", spec, err)
+		}
+		if len(testClient.Actions()) != 0 {
+			t.Errorf("DeployApp(%#v) should not create anything but got %#v", spec, testClient.Actions())
+		}
+	}
+}
+
 func TestGetAvailableProtocols(t *testing.T) {
 	expected := &Protocols{Protocols: []api.Protocol{"TCP", "UDP"}}
 
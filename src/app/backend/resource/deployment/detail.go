@@ -18,7 +18,8 @@ import (
 	"log"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	"github.com/kubernetes/dashboard/src/app/backend/link"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/horizontalpodautoscaler"
@@ -56,6 +57,9 @@ type DeploymentDetail struct {
 	ObjectMeta api.ObjectMeta `json:"objectMeta"`
 	TypeMeta   api.TypeMeta   `json:"typeMeta"`
 
+	// Links to external tools configured in settings and in the links annotation.
+	Links []link.Link `json:"links,omitempty"`
+
 	// Detailed information about Pods belonging to this Deployment.
 	PodList pod.PodList `json:"podList"`
 
@@ -69,9 +73,19 @@ type DeploymentDetail struct {
 	// Valid options: Recreate, RollingUpdate
 	Strategy extensions.DeploymentStrategyType `json:"strategy"`
 
+	// Whether rollout of the deployment is paused.
+	Paused bool `json:"paused"`
+
 	// Min ready seconds
 	MinReadySeconds int32 `json:"minReadySeconds"`
 
+	// Seconds after which a rollout without progress is reported as failed in the Progressing
+	// condition.
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds"`
+
+	// Rollout conditions of the deployment, e.g. Available and Progressing.
+	Conditions []common.Condition `json:"conditions"`
+
 	// Rolling update strategy containing maxSurge and maxUnavailable
 	RollingUpdateStrategy *RollingUpdateStrategy `json:"rollingUpdateStrategy,omitempty"`
 
@@ -89,10 +103,13 @@ type DeploymentDetail struct {
 
 	// List of Horizontal Pod AutoScalers targeting this Deployment
 	HorizontalPodAutoscalerList horizontalpodautoscaler.HorizontalPodAutoscalerList `json:"horizontalPodAutoscalerList"`
+
+	// Revisions of the deployment that it can be rolled back to, from the newest revision.
+	Revisions []Revision `json:"revisions"`
 }
 
 // GetDeploymentDetail returns model object of deployment and error, if any.
-func GetDeploymentDetail(client client.Interface, heapsterClient heapster.HeapsterClient, namespace string,
+func GetDeploymentDetail(client client.Interface, heapsterClient metricapi.MetricClient, namespace string,
 	deploymentName string) (*DeploymentDetail, error) {
 
 	log.Printf("Getting details of %s deployment in %s namespace", deploymentName, namespace)
@@ -178,13 +195,17 @@ func GetDeploymentDetail(client client.Interface, heapsterClient heapster.Heapst
 		Selector:                    deployment.Spec.Selector.MatchLabels,
 		StatusInfo:                  GetStatusInfo(&deployment.Status),
 		Strategy:                    deployment.Spec.Strategy.Type,
+		Paused:                      deployment.Spec.Paused,
 		MinReadySeconds:             deployment.Spec.MinReadySeconds,
+		ProgressDeadlineSeconds:     deployment.Spec.ProgressDeadlineSeconds,
+		Conditions:                  getDeploymentConditions(deployment),
 		RollingUpdateStrategy:       rollingUpdateStrategy,
 		OldReplicaSetList:           *oldReplicaSetList,
 		NewReplicaSet:               newReplicaSet,
 		RevisionHistoryLimit:        deployment.Spec.RevisionHistoryLimit,
 		EventList:                   *eventList,
 		HorizontalPodAutoscalerList: *hpas,
+		Revisions:                   getRevisionList(deployment, rawRs.Items),
 	}, nil
 
 }
@@ -197,3 +218,19 @@ func GetStatusInfo(deploymentStatus *extensions.DeploymentStatus) StatusInfo {
 		Unavailable: deploymentStatus.UnavailableReplicas,
 	}
 }
+
+// getDeploymentConditions returns rollout conditions of given deployment.
+func getDeploymentConditions(deployment *extensions.Deployment) []common.Condition {
+	conditions := make([]common.Condition, 0)
+	for _, condition := range deployment.Status.Conditions {
+		conditions = append(conditions, common.Condition{
+			Type:               string(condition.Type),
+			Status:             condition.Status,
+			LastProbeTime:      condition.LastUpdateTime,
+			LastTransitionTime: condition.LastTransitionTime,
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+		})
+	}
+	return conditions
+}
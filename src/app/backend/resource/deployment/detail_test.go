@@ -67,6 +67,12 @@ func TestGetDeploymentDetail(t *testing.T) {
 
 	deployment := createDeployment("dp-1", "ns-1", "pod-1", map[string]string{"track": "beta"},
 		map[string]string{"foo": "bar"})
+	deployment.Status.Conditions = []extensions.DeploymentCondition{{
+		Type:    extensions.DeploymentProgressing,
+		Status:  v1.ConditionFalse,
+		Reason:  "ProgressDeadlineExceeded",
+		Message: "ReplicaSet \"rs-1\" has timed out progressing.",
+	}}
 
 	podTemplateSpec := GetNewReplicaSetTemplate(deployment)
 
@@ -102,6 +108,7 @@ func TestGetDeploymentDetail(t *testing.T) {
 				},
 				TypeMeta: api.TypeMeta{Kind: api.ResourceKindDeployment},
 				PodList: pod.PodList{
+					ListMeta:          api.ListMeta{Page: 1, ItemsPerPage: 10},
 					Pods:              []pod.Pod{},
 					CumulativeMetrics: make([]metric.Metric, 0),
 				},
@@ -114,11 +121,18 @@ func TestGetDeploymentDetail(t *testing.T) {
 				},
 				Strategy:        "RollingUpdate",
 				MinReadySeconds: 5,
+				Conditions: []common.Condition{{
+					Type:    "Progressing",
+					Status:  v1.ConditionFalse,
+					Reason:  "ProgressDeadlineExceeded",
+					Message: "ReplicaSet \"rs-1\" has timed out progressing.",
+				}},
 				RollingUpdateStrategy: &RollingUpdateStrategy{
 					MaxSurge:       &maxSurge,
 					MaxUnavailable: &maxUnavailable,
 				},
 				OldReplicaSetList: replicaset.ReplicaSetList{
+					ListMeta:          api.ListMeta{Page: 1, ItemsPerPage: 10},
 					ReplicaSets:       []replicaset.ReplicaSet{},
 					CumulativeMetrics: make([]metric.Metric, 0),
 				},
@@ -128,9 +142,11 @@ func TestGetDeploymentDetail(t *testing.T) {
 					Pods:       common.PodInfo{Warnings: []common.Event{}},
 				},
 				EventList: common.EventList{
-					Events: []common.Event{},
+					ListMeta: api.ListMeta{Page: 1, ItemsPerPage: 10},
+					Events:   []common.Event{},
 				},
 				HorizontalPodAutoscalerList: horizontalpodautoscaler.HorizontalPodAutoscalerList{HorizontalPodAutoscalers: []horizontalpodautoscaler.HorizontalPodAutoscaler{}},
+				Revisions:                   []Revision{},
 			},
 		},
 	}
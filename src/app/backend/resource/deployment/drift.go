@@ -0,0 +1,162 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"log"
+	"sort"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	client "k8s.io/client-go/kubernetes"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+// ImageDrift is a comparison of images of same-named deployments in two namespaces, e.g. to verify
+// that images were promoted from staging to production.
+type ImageDrift struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+
+	// Number of deployments with different images in the namespaces.
+	Drifted int `json:"drifted"`
+
+	// Deployments present in both namespaces, sorted by name.
+	Deployments []DeploymentImageDrift `json:"deployments"`
+
+	// Names of deployments present only in source or only in target namespace.
+	OnlyInSource []string `json:"onlyInSource"`
+	OnlyInTarget []string `json:"onlyInTarget"`
+}
+
+// DeploymentImageDrift is a comparison of container images of a deployment in two namespaces.
+type DeploymentImageDrift struct {
+	Name string `json:"name"`
+
+	// True if image of any container differs.
+	Drifted bool `json:"drifted"`
+
+	Containers []ContainerImageDrift `json:"containers"`
+}
+
+// ContainerImageDrift is a comparison of images of a container. Image is empty in the namespace
+// where deployment has no container of the name.
+type ContainerImageDrift struct {
+	Name        string `json:"name"`
+	SourceImage string `json:"sourceImage"`
+	TargetImage string `json:"targetImage"`
+	Drifted     bool   `json:"drifted"`
+}
+
+// GetImageDrift compares images of deployments with the same name in source and target
+// namespaces. Containers are matched by name.
+func GetImageDrift(client client.Interface, source, target string) (*ImageDrift, error) {
+	log.Printf("Comparing images of deployments in %s and %s namespaces", source, target)
+
+	sourceChannel := common.GetDeploymentListChannel(client, common.NewSameNamespaceQuery(source), 1)
+	targetChannel := common.GetDeploymentListChannel(client, common.NewSameNamespaceQuery(target), 1)
+
+	sourceDeployments := <-sourceChannel.List
+	if err := <-sourceChannel.Error; err != nil {
+		return nil, err
+	}
+	targetDeployments := <-targetChannel.List
+	if err := <-targetChannel.Error; err != nil {
+		return nil, err
+	}
+
+	return getImageDrift(source, target, sourceDeployments.Items, targetDeployments.Items), nil
+}
+
+func getImageDrift(source, target string, sourceDeployments,
+	targetDeployments []extensions.Deployment) *ImageDrift {
+	drift := &ImageDrift{
+		Source:       source,
+		Target:       target,
+		Deployments:  make([]DeploymentImageDrift, 0),
+		OnlyInSource: make([]string, 0),
+		OnlyInTarget: make([]string, 0),
+	}
+
+	targets := make(map[string]extensions.Deployment)
+	for _, deployment := range targetDeployments {
+		targets[deployment.Name] = deployment
+	}
+
+	for _, sourceDeployment := range sourceDeployments {
+		targetDeployment, ok := targets[sourceDeployment.Name]
+		if !ok {
+			drift.OnlyInSource = append(drift.OnlyInSource, sourceDeployment.Name)
+			continue
+		}
+		delete(targets, sourceDeployment.Name)
+
+		deploymentDrift := compareImages(sourceDeployment, targetDeployment)
+		if deploymentDrift.Drifted {
+			drift.Drifted++
+		}
+		drift.Deployments = append(drift.Deployments, deploymentDrift)
+	}
+	for name := range targets {
+		drift.OnlyInTarget = append(drift.OnlyInTarget, name)
+	}
+
+	sort.Sort(imageDriftsByName(drift.Deployments))
+	sort.Strings(drift.OnlyInSource)
+	sort.Strings(drift.OnlyInTarget)
+	return drift
+}
+
+// compareImages compares images of containers of given deployments, in order of source containers
+// followed by containers present only in target.
+func compareImages(source, target extensions.Deployment) DeploymentImageDrift {
+	result := DeploymentImageDrift{
+		Name:       source.Name,
+		Containers: make([]ContainerImageDrift, 0),
+	}
+
+	targetImages := make(map[string]string)
+	for _, container := range target.Spec.Template.Spec.Containers {
+		targetImages[container.Name] = container.Image
+	}
+
+	add := func(name, sourceImage, targetImage string) {
+		drifted := sourceImage != targetImage
+		result.Containers = append(result.Containers, ContainerImageDrift{
+			Name:        name,
+			SourceImage: sourceImage,
+			TargetImage: targetImage,
+			Drifted:     drifted,
+		})
+		result.Drifted = result.Drifted || drifted
+	}
+
+	sourceNames := make(map[string]bool)
+	for _, container := range source.Spec.Template.Spec.Containers {
+		sourceNames[container.Name] = true
+		add(container.Name, container.Image, targetImages[container.Name])
+	}
+	for _, container := range target.Spec.Template.Spec.Containers {
+		if !sourceNames[container.Name] {
+			add(container.Name, "", container.Image)
+		}
+	}
+	return result
+}
+
+type imageDriftsByName []DeploymentImageDrift
+
+func (self imageDriftsByName) Len() int           { return len(self) }
+func (self imageDriftsByName) Swap(i, j int)      { self[i], self[j] = self[j], self[i] }
+func (self imageDriftsByName) Less(i, j int) bool { return self[i].Name < self[j].Name }
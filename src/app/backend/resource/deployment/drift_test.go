@@ -0,0 +1,80 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"reflect"
+	"testing"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+func newImageDeployment(namespace, name string, containers ...v1.Container) *extensions.Deployment {
+	return &extensions.Deployment{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: extensions.DeploymentSpec{Template: v1.PodTemplateSpec{
+			Spec: v1.PodSpec{Containers: containers},
+		}},
+	}
+}
+
+func TestGetImageDrift(t *testing.T) {
+	app := v1.Container{Name: "app", Image: "app:1.2"}
+	fakeClient := fake.NewSimpleClientset(
+		newImageDeployment("staging", "web", app, v1.Container{Name: "proxy", Image: "proxy:2"}),
+		newImageDeployment("prod", "web", v1.Container{Name: "app", Image: "app:1.1"},
+			v1.Container{Name: "agent", Image: "agent:1"}),
+		newImageDeployment("staging", "api", app),
+		newImageDeployment("prod", "api", app),
+		newImageDeployment("staging", "worker", app),
+		newImageDeployment("prod", "legacy", app),
+	)
+
+	actual, err := GetImageDrift(fakeClient, "staging", "prod")
+	if err != nil {
+		t.Fatalf("GetImageDrift() == \ngot err %#v", err)
+	}
+
+	expected := &ImageDrift{
+		Source:  "staging",
+		Target:  "prod",
+		Drifted: 1,
+		Deployments: []DeploymentImageDrift{
+			{
+				Name: "api",
+				Containers: []ContainerImageDrift{
+					{Name: "app", SourceImage: "app:1.2", TargetImage: "app:1.2"},
+				},
+			},
+			{
+				Name:    "web",
+				Drifted: true,
+				Containers: []ContainerImageDrift{
+					{Name: "app", SourceImage: "app:1.2", TargetImage: "app:1.1", Drifted: true},
+					{Name: "proxy", SourceImage: "proxy:2", Drifted: true},
+					{Name: "agent", TargetImage: "agent:1", Drifted: true},
+				},
+			},
+		},
+		OnlyInSource: []string{"worker"},
+		OnlyInTarget: []string{"legacy"},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GetImageDrift() == \ngot %#v, \nexpected %#v", actual, expected)
+	}
+}
@@ -32,7 +32,7 @@ func TestGetDeploymentEvents(t *testing.T) {
 				map[string]string{"app": "test"}),
 			[]string{"list"},
 			&common.EventList{
-				ListMeta: api.ListMeta{TotalItems: 1},
+				ListMeta: api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
 				Events: []common.Event{{
 					TypeMeta: api.TypeMeta{Kind: api.ResourceKindEvent},
 					ObjectMeta: api.ObjectMeta{Name: "ev-1", Namespace: "ns-1",
@@ -0,0 +1,178 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/kubernetes/dashboard/src/app/backend/integration/gitsource"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+)
+
+const (
+	// GitCommitAnnotationKey is annotation key for the commit SHA objects were deployed from.
+	GitCommitAnnotationKey = "dashboard.alpha.kubernetes.io/git-commit"
+
+	// GitSourceAnnotationKey is annotation key for the repository URL objects were deployed from.
+	GitSourceAnnotationKey = "dashboard.alpha.kubernetes.io/git-source"
+)
+
+// AppDeploymentFromGitSpec is a specification for deployment from Git repository.
+type AppDeploymentFromGitSpec struct {
+	// Namespace that objects should be deployed in
+	Namespace string `json:"namespace"`
+
+	// Location of manifests in the repository
+	Source gitsource.Source `json:"source"`
+
+	// Name of a secret in the namespace with username and password keys, e.g. of
+	// kubernetes.io/basic-auth type, used to fetch private repositories.
+	SecretName string `json:"secretName,omitempty"`
+
+	// Whether validate manifests before creation or not
+	Validate bool `json:"validate"`
+
+	// Values of parameters referenced in manifests as ${NAME}
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// AppDeploymentFromGitResponse is a response of deployment from Git repository.
+type AppDeploymentFromGitResponse struct {
+	// SHA of the deployed commit
+	Commit string `json:"commit"`
+
+	// Rendered manifests
+	Content string `json:"content"`
+
+	// Error after create resource
+	Error string `json:"error"`
+}
+
+// RenderGitSource fetches manifests of given spec and renders them, annotated with their Git
+// source. A kustomization in the root of the source path is rendered, otherwise all manifests
+// are deployed in the order of their paths.
+func RenderGitSource(spec *AppDeploymentFromGitSpec, client client.Interface,
+	fetcher gitsource.Fetcher) (*AppDeploymentFromFileSpec, string, error) {
+	credentials, err := getGitCredentials(spec, client)
+	if err != nil {
+		return nil, "", err
+	}
+
+	manifests, err := fetcher.Fetch(spec.Source, credentials)
+	if err != nil {
+		return nil, "", k8serrors.NewBadRequest(err.Error())
+	}
+
+	fileSpec := &AppDeploymentFromFileSpec{
+		Name:       spec.Source.URL,
+		Namespace:  spec.Namespace,
+		Validate:   spec.Validate,
+		Parameters: spec.Parameters,
+	}
+	if isKustomizationDir(manifests.Files, ".") {
+		fileSpec.Kustomization = &KustomizationSpec{Files: manifests.Files}
+	} else {
+		paths := make([]string, 0, len(manifests.Files))
+		for path := range manifests.Files {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		documents := make([]string, 0, len(paths))
+		for _, path := range paths {
+			documents = append(documents, manifests.Files[path])
+		}
+		fileSpec.Content = strings.Join(documents, "\n---\n")
+	}
+
+	content, err := RenderDeploymentFile(fileSpec)
+	if err != nil {
+		return nil, "", err
+	}
+	content, err = annotateManifests(content, map[string]string{
+		GitCommitAnnotationKey: manifests.Commit,
+		GitSourceAnnotationKey: spec.Source.URL,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Content is already rendered and substituted.
+	fileSpec.Content = content
+	fileSpec.Kustomization = nil
+	fileSpec.Parameters = nil
+	return fileSpec, manifests.Commit, nil
+}
+
+// DeployAppFromGit deploys manifests fetched from a Git repository.
+func DeployAppFromGit(spec *AppDeploymentFromGitSpec, client client.Interface,
+	fetcher gitsource.Fetcher, createObjectFromInfoFn createObjectFromInfo) (
+	*AppDeploymentFromGitResponse, error) {
+	fileSpec, commit, err := RenderGitSource(spec, client, fetcher)
+	if err != nil {
+		return nil, err
+	}
+
+	isDeployed, err := DeployAppFromFile(fileSpec, createObjectFromInfoFn)
+	if !isDeployed {
+		return nil, err
+	}
+
+	errorMessage := ""
+	if err != nil {
+		errorMessage = err.Error()
+	}
+	return &AppDeploymentFromGitResponse{Commit: commit, Content: fileSpec.Content,
+		Error: errorMessage}, nil
+}
+
+func getGitCredentials(spec *AppDeploymentFromGitSpec, client client.Interface) (
+	*gitsource.Credentials, error) {
+	if spec.SecretName == "" {
+		return nil, nil
+	}
+
+	secret, err := client.CoreV1().Secrets(spec.Namespace).Get(spec.SecretName, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return &gitsource.Credentials{
+		Username: string(secret.Data["username"]),
+		Password: string(secret.Data["password"]),
+	}, nil
+}
+
+// annotateManifests adds given annotations to metadata of all objects of given manifests. Pod
+// templates are not annotated, so that new commits roll out only pods which changed.
+func annotateManifests(content string, annotations map[string]string) (string, error) {
+	objects, err := parseManifests(content)
+	if err != nil {
+		return "", k8serrors.NewBadRequest(err.Error())
+	}
+
+	documents := make([]string, 0, len(objects))
+	for _, object := range objects {
+		addToMap(getMap(object, "metadata"), "annotations", annotations)
+		document, err := yaml.Marshal(object)
+		if err != nil {
+			return "", err
+		}
+		documents = append(documents, string(document))
+	}
+	return strings.Join(documents, "---\n"), nil
+}
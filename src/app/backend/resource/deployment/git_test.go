@@ -0,0 +1,86 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/integration/gitsource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	api "k8s.io/client-go/pkg/api/v1"
+)
+
+type fakeGitFetcher struct {
+	manifests   *gitsource.Manifests
+	credentials *gitsource.Credentials
+}
+
+func (self *fakeGitFetcher) Fetch(source gitsource.Source, credentials *gitsource.Credentials) (
+	*gitsource.Manifests, error) {
+	self.credentials = credentials
+	return self.manifests, nil
+}
+
+func TestRenderGitSource(t *testing.T) {
+	fetcher := &fakeGitFetcher{manifests: &gitsource.Manifests{
+		Commit: "0123abc",
+		Files: map[string]string{
+			"b.yaml": "kind: Service\nmetadata:\n  name: app\n",
+			"a.yaml": "kind: Deployment\nmetadata:\n  name: app\nspec:\n  replicas: ${REPLICAS}\n",
+		},
+	}}
+	testClient := fake.NewSimpleClientset(&api.Secret{
+		ObjectMeta: metaV1.ObjectMeta{Name: "git", Namespace: "prod"},
+		Data:       map[string][]byte{"username": []byte("bot"), "password": []byte("token")},
+	})
+	spec := &AppDeploymentFromGitSpec{
+		Namespace:  "prod",
+		Source:     gitsource.Source{URL: "https://example.com/app.git"},
+		SecretName: "git",
+		Parameters: map[string]string{"REPLICAS": "2"},
+	}
+	expected := `kind: Deployment
+metadata:
+  annotations:
+    dashboard.alpha.kubernetes.io/git-commit: 0123abc
+    dashboard.alpha.kubernetes.io/git-source: https://example.com/app.git
+  name: app
+spec:
+  replicas: 2
+---
+kind: Service
+metadata:
+  annotations:
+    dashboard.alpha.kubernetes.io/git-commit: 0123abc
+    dashboard.alpha.kubernetes.io/git-source: https://example.com/app.git
+  name: app
+`
+
+	fileSpec, commit, err := RenderGitSource(spec, testClient, fetcher)
+	if err != nil {
+		t.Fatalf("RenderGitSource() returned error: %s", err)
+	}
+	if commit != "0123abc" || fileSpec.Content != expected || fileSpec.Namespace != "prod" {
+		t.Errorf("RenderGitSource(%#v) == \ngot: %s, %s, \nexpected %s", spec, commit, fileSpec.Content,
+			expected)
+	}
+	expectedCredentials := &gitsource.Credentials{Username: "bot", Password: "token"}
+	if !reflect.DeepEqual(fetcher.credentials, expectedCredentials) {
+		t.Errorf("Expected credentials %#v to be used but got %#v", expectedCredentials,
+			fetcher.credentials)
+	}
+}
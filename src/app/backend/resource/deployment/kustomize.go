@@ -0,0 +1,358 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// MaxKustomizationSize is the maximum size in bytes of all files of a kustomization.
+const MaxKustomizationSize = 1 << 20
+
+// maxKustomizationDepth is the maximum depth of nested bases. It stops rendering of cyclic bases.
+const maxKustomizationDepth = 10
+
+// Limits of rendered kustomization. Each resource and base is rendered once, so rendered objects
+// are bounded by the input, but transformations still make them larger than it.
+var (
+	// maxKustomizationObjects is the maximum number of rendered objects.
+	maxKustomizationObjects = 1000
+	// maxRenderedKustomizationSize is the maximum size in bytes of rendered manifests.
+	maxRenderedKustomizationSize = 4 * MaxKustomizationSize
+)
+
+// kustomizationFileNames are names of kustomization files looked up in a directory, in order.
+var kustomizationFileNames = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
+// KustomizationSpec is a kustomization directory uploaded for deploy from file. Either files or
+// tarball have to be given.
+type KustomizationSpec struct {
+	// Contents of files of the directory keyed by their path relative to the directory.
+	Files map[string]string `json:"files,omitempty"`
+
+	// Base64 encoded, gzipped tarball of the directory.
+	Tarball string `json:"tarball,omitempty"`
+}
+
+// kustomization is the subset of kustomization file supported by the dashboard.
+type kustomization struct {
+	Resources         []string          `json:"resources"`
+	Bases             []string          `json:"bases"`
+	Namespace         string            `json:"namespace"`
+	NamePrefix        string            `json:"namePrefix"`
+	NameSuffix        string            `json:"nameSuffix"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+}
+
+// supportedKustomizationFields are fields of kustomization file that can be rendered. Other fields,
+// e.g. patches or generators, are rejected rather than silently ignored.
+var supportedKustomizationFields = map[string]bool{
+	"apiVersion": true, "kind": true, "resources": true, "bases": true, "namespace": true,
+	"namePrefix": true, "nameSuffix": true, "commonLabels": true, "commonAnnotations": true,
+}
+
+// RenderKustomization renders manifests of given kustomization directory. Manifests are returned
+// as a multi-document YAML, which can be reviewed and deployed from file.
+func RenderKustomization(spec *KustomizationSpec) (string, error) {
+	files, err := getKustomizationFiles(spec)
+	if err != nil {
+		return "", k8serrors.NewBadRequest(err.Error())
+	}
+
+	renderer := &kustomizationRenderer{files: files, rendered: make(map[string]bool)}
+	objects, err := renderer.renderDir(".", 0)
+	if err != nil {
+		return "", k8serrors.NewBadRequest(err.Error())
+	}
+
+	documents := make([]string, 0, len(objects))
+	size := 0
+	for _, object := range objects {
+		document, err := yaml.Marshal(object)
+		if err != nil {
+			return "", err
+		}
+		size += len(document)
+		if size > maxRenderedKustomizationSize {
+			return "", k8serrors.NewBadRequest(fmt.Sprintf(
+				"Rendered kustomization is larger than %d bytes", maxRenderedKustomizationSize))
+		}
+		documents = append(documents, string(document))
+	}
+	return strings.Join(documents, "---\n"), nil
+}
+
+// getKustomizationFiles returns files of given kustomization keyed by their cleaned path.
+func getKustomizationFiles(spec *KustomizationSpec) (map[string]string, error) {
+	files := make(map[string]string)
+	if len(spec.Files) > 0 {
+		size := 0
+		for name, content := range spec.Files {
+			size += len(content)
+			if size > MaxKustomizationSize {
+				return nil, fmt.Errorf("Kustomization is larger than %d bytes", MaxKustomizationSize)
+			}
+			files[path.Clean(name)] = content
+		}
+		return files, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(spec.Tarball)
+	if err != nil || len(data) == 0 {
+		return nil, fmt.Errorf("Kustomization has to contain files or base64 encoded tarball")
+	}
+	gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid kustomization tarball: %s", err.Error())
+	}
+	// Read one byte over the limit to detect too large tarballs.
+	reader := tar.NewReader(io.LimitReader(gzipReader, MaxKustomizationSize+1))
+	size := 0
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Invalid kustomization tarball: %s", err.Error())
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid kustomization tarball: %s", err.Error())
+		}
+		size += len(content)
+		if size > MaxKustomizationSize {
+			return nil, fmt.Errorf("Kustomization is larger than %d bytes", MaxKustomizationSize)
+		}
+		files[path.Clean(header.Name)] = string(content)
+	}
+	return files, nil
+}
+
+// kustomizationRenderer renders kustomization of given files.
+type kustomizationRenderer struct {
+	files map[string]string
+
+	// Paths of resources and bases already rendered. Like kustomize, which rejects objects with
+	// duplicate IDs, a resource or base can't be included twice, e.g. through two other bases.
+	rendered map[string]bool
+
+	// Number of objects rendered so far.
+	objects int
+}
+
+// renderDir renders objects of kustomization in given directory of files.
+func (self *kustomizationRenderer) renderDir(dir string, depth int) ([]map[string]interface{},
+	error) {
+	if depth > maxKustomizationDepth {
+		return nil, fmt.Errorf("Bases of kustomization are nested deeper than %d levels",
+			maxKustomizationDepth)
+	}
+	if self.rendered[dir] {
+		return nil, fmt.Errorf("Base %s is included in kustomization more than once", dir)
+	}
+	self.rendered[dir] = true
+
+	k, err := getKustomization(self.files, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]map[string]interface{}, 0)
+	for _, resource := range append(k.Bases, k.Resources...) {
+		resourcePath := path.Join(dir, resource)
+		if strings.Contains(resource, "://") || resourcePath == ".." ||
+			strings.HasPrefix(resourcePath, "../") {
+			return nil, fmt.Errorf("Resource %s is outside of kustomization", resource)
+		}
+
+		if content, ok := self.files[resourcePath]; ok {
+			if self.rendered[resourcePath] {
+				return nil, fmt.Errorf("Resource %s is included in kustomization more than once",
+					resourcePath)
+			}
+			self.rendered[resourcePath] = true
+
+			documents, err := parseManifests(content)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid resource %s: %s", resourcePath, err.Error())
+			}
+			self.objects += len(documents)
+			if self.objects > maxKustomizationObjects {
+				return nil, fmt.Errorf("Kustomization renders more than %d objects",
+					maxKustomizationObjects)
+			}
+			objects = append(objects, documents...)
+		} else if isKustomizationDir(self.files, resourcePath) {
+			baseObjects, err := self.renderDir(resourcePath, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, baseObjects...)
+		} else {
+			return nil, fmt.Errorf("Resource %s of kustomization not found", resourcePath)
+		}
+	}
+
+	for _, object := range objects {
+		k.transform(object)
+	}
+	return objects, nil
+}
+
+// getKustomization parses kustomization file of given directory.
+func getKustomization(files map[string]string, dir string) (*kustomization, error) {
+	for _, name := range kustomizationFileNames {
+		content, ok := files[path.Join(dir, name)]
+		if !ok {
+			continue
+		}
+
+		fields := make(map[string]interface{})
+		if err := yaml.Unmarshal([]byte(content), &fields); err != nil {
+			return nil, fmt.Errorf("Invalid kustomization %s: %s", path.Join(dir, name), err.Error())
+		}
+		unsupported := make([]string, 0)
+		for field := range fields {
+			if !supportedKustomizationFields[field] {
+				unsupported = append(unsupported, field)
+			}
+		}
+		if len(unsupported) > 0 {
+			sort.Strings(unsupported)
+			return nil, fmt.Errorf("Unsupported fields of kustomization %s: %s", path.Join(dir, name),
+				strings.Join(unsupported, ", "))
+		}
+
+		k := new(kustomization)
+		if err := yaml.Unmarshal([]byte(content), k); err != nil {
+			return nil, fmt.Errorf("Invalid kustomization %s: %s", path.Join(dir, name), err.Error())
+		}
+		return k, nil
+	}
+	return nil, fmt.Errorf("No kustomization file found in %s", dir)
+}
+
+func isKustomizationDir(files map[string]string, dir string) bool {
+	for _, name := range kustomizationFileNames {
+		if _, ok := files[path.Join(dir, name)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseManifests parses all non-empty documents of a multi-document YAML.
+func parseManifests(content string) ([]map[string]interface{}, error) {
+	objects := make([]map[string]interface{}, 0)
+	for _, document := range strings.Split("\n"+content, "\n---") {
+		object := make(map[string]interface{})
+		if err := yaml.Unmarshal([]byte(document), &object); err != nil {
+			return nil, err
+		}
+		if len(object) > 0 {
+			objects = append(objects, object)
+		}
+	}
+	return objects, nil
+}
+
+// transform applies namespace, name affixes, common labels and common annotations to given object.
+// Common labels are also added to selectors and pod templates, so that they keep matching.
+func (self *kustomization) transform(object map[string]interface{}) {
+	metadata := getMap(object, "metadata")
+	kind, _ := object["kind"].(string)
+
+	if self.Namespace != "" && isNamespacedKind(kind) {
+		metadata["namespace"] = self.Namespace
+	}
+	if name, ok := metadata["name"].(string); ok && !unaffixedKinds[kind] {
+		metadata["name"] = self.NamePrefix + name + self.NameSuffix
+	}
+
+	addToMap(metadata, "labels", self.CommonLabels)
+	addToMap(metadata, "annotations", self.CommonAnnotations)
+
+	spec, ok := object["spec"].(map[string]interface{})
+	if !ok || len(self.CommonLabels) == 0 && len(self.CommonAnnotations) == 0 {
+		return
+	}
+	if kind == "Service" {
+		if _, ok := spec["selector"]; ok {
+			addToMap(spec, "selector", self.CommonLabels)
+		}
+	} else if selector, ok := spec["selector"].(map[string]interface{}); ok {
+		addToMap(selector, "matchLabels", self.CommonLabels)
+	}
+	if template, ok := spec["template"].(map[string]interface{}); ok {
+		templateMetadata := getMap(template, "metadata")
+		addToMap(templateMetadata, "labels", self.CommonLabels)
+		addToMap(templateMetadata, "annotations", self.CommonAnnotations)
+	}
+}
+
+// unaffixedKinds are kinds whose names kustomize keeps without name prefix and suffix, since other
+// resources refer to them by name or their name is given by their content.
+var unaffixedKinds = map[string]bool{
+	"Namespace":                true,
+	"CustomResourceDefinition": true,
+	"APIService":               true,
+}
+
+// isNamespacedKind returns false for kinds known to be cluster scoped.
+func isNamespacedKind(kind string) bool {
+	if resource, ok := api.KindToAPIMapping[strings.ToLower(kind)]; ok {
+		return resource.Namespaced
+	}
+	return !strings.HasPrefix(kind, "Cluster") && kind != "CustomResourceDefinition"
+}
+
+// getMap returns map stored under given key, creating it if it does not exist.
+func getMap(object map[string]interface{}, key string) map[string]interface{} {
+	value, ok := object[key].(map[string]interface{})
+	if !ok {
+		value = make(map[string]interface{})
+		object[key] = value
+	}
+	return value
+}
+
+// addToMap adds given values to map stored under given key.
+func addToMap(object map[string]interface{}, key string, values map[string]string) {
+	if len(values) == 0 {
+		return
+	}
+	target := getMap(object, key)
+	for name, value := range values {
+		target[name] = value
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const baseDeployment = `apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  selector:
+    matchLabels:
+      app: foo
+  template:
+    metadata:
+      labels:
+        app: foo
+`
+
+const baseService = `apiVersion: v1
+kind: Service
+metadata:
+  name: app
+spec:
+  selector:
+    app: foo
+`
+
+func TestRenderKustomization(t *testing.T) {
+	files := map[string]string{
+		"kustomization.yaml": "bases:\n- base\nnamespace: prod\nnamePrefix: prod-\n" +
+			"commonLabels:\n  env: prod\n",
+		"base/kustomization.yaml": "resources:\n- app.yaml\n- ns.yaml\nnameSuffix: -v1\n",
+		"base/app.yaml":           baseDeployment + "---\n" + baseService,
+		"base/ns.yaml":            "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: prod\n",
+	}
+	expected := `apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  labels:
+    env: prod
+  name: prod-app-v1
+  namespace: prod
+spec:
+  selector:
+    matchLabels:
+      app: foo
+      env: prod
+  template:
+    metadata:
+      labels:
+        app: foo
+        env: prod
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    env: prod
+  name: prod-app-v1
+  namespace: prod
+spec:
+  selector:
+    app: foo
+    env: prod
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  labels:
+    env: prod
+  name: prod
+`
+
+	actual, err := RenderKustomization(&KustomizationSpec{Files: files})
+	if err != nil {
+		t.Fatalf("RenderKustomization() returned error: %s", err)
+	}
+	if actual != expected {
+		t.Errorf("RenderKustomization() == \ngot: %s, \nexpected %s", actual, expected)
+	}
+}
+
+func TestRenderKustomizationTarball(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	gzipWriter := gzip.NewWriter(buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, file := range []struct{ name, content string }{
+		{"./kustomization.yaml", "resources:\n- service.yaml\n"},
+		{"./service.yaml", baseService},
+	} {
+		tarWriter.WriteHeader(&tar.Header{Name: file.name, Mode: 0644, Size: int64(len(file.content)),
+			Typeflag: tar.TypeReg})
+		tarWriter.Write([]byte(file.content))
+	}
+	tarWriter.Close()
+	gzipWriter.Close()
+
+	actual, err := RenderKustomization(&KustomizationSpec{
+		Tarball: base64.StdEncoding.EncodeToString(buffer.Bytes()),
+	})
+	if err != nil {
+		t.Fatalf("RenderKustomization() returned error: %s", err)
+	}
+	if !strings.Contains(actual, "kind: Service") {
+		t.Errorf("RenderKustomization() should render service from tarball but got %s", actual)
+	}
+}
+
+func TestRenderKustomizationInvalid(t *testing.T) {
+	cases := []map[string]string{
+		{},
+		{"app.yaml": baseService},
+		{"kustomization.yaml": "resources:\n- missing.yaml\n"},
+		{"kustomization.yaml": "resources:\n- ../secret.yaml\n"},
+		{"kustomization.yaml": "resources:\n- app.yaml\npatches:\n- patch.yaml\n", "app.yaml": baseService},
+		{"kustomization.yaml": "bases:\n- .\n"},
+		{"kustomization.yaml": "resources: [app.yaml]\n", "app.yaml": "kind: [\n"},
+		// Resource and base listed twice.
+		{"kustomization.yaml": "resources: [app.yaml, app.yaml]\n", "app.yaml": baseService},
+		{"kustomization.yaml": "bases: [base, base]\n", "base/kustomization.yaml": "resources: []\n"},
+		// Diamond of bases including the same base.
+		{
+			"kustomization.yaml":       "bases: [left, right]\n",
+			"left/kustomization.yaml":  "bases: [../base]\n",
+			"right/kustomization.yaml": "bases: [../base]\n",
+			"base/kustomization.yaml":  "resources: [app.yaml]\n",
+			"base/app.yaml":            baseService,
+		},
+	}
+	for _, files := range cases {
+		_, err := RenderKustomization(&KustomizationSpec{Files: files})
+		if !k8serrors.IsBadRequest(err) {
+			t.Errorf("RenderKustomization(%#v) should return bad request but got %v", files, err)
+		}
+	}
+}
+
+func TestRenderKustomizationLimits(t *testing.T) {
+	defer func(objects, size int) {
+		maxKustomizationObjects, maxRenderedKustomizationSize = objects, size
+	}(maxKustomizationObjects, maxRenderedKustomizationSize)
+	files := map[string]string{
+		"kustomization.yaml": "resources: [app.yaml]\n",
+		"app.yaml":           baseService + "---\n" + baseService,
+	}
+
+	maxKustomizationObjects = 1
+	if _, err := RenderKustomization(&KustomizationSpec{Files: files}); !k8serrors.IsBadRequest(err) {
+		t.Errorf("RenderKustomization() above object limit should return bad request but got %v", err)
+	}
+
+	maxKustomizationObjects, maxRenderedKustomizationSize = 2, 64
+	if _, err := RenderKustomization(&KustomizationSpec{Files: files}); !k8serrors.IsBadRequest(err) {
+		t.Errorf("RenderKustomization() above size limit should return bad request but got %v", err)
+	}
+}
@@ -18,7 +18,8 @@ import (
 	"log"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	"github.com/kubernetes/dashboard/src/app/backend/integration/alertmanager"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
@@ -33,6 +34,9 @@ import (
 type DeploymentList struct {
 	ListMeta api.ListMeta `json:"listMeta"`
 
+	// Status summary of the returned items.
+	Status common.ResourceStatus `json:"status"`
+
 	// Unordered list of Deployments.
 	Deployments       []Deployment    `json:"deployments"`
 	CumulativeMetrics []metric.Metric `json:"cumulativeMetrics"`
@@ -50,17 +54,27 @@ type Deployment struct {
 
 	// Container images of the Deployment.
 	ContainerImages []string `json:"containerImages"`
+
+	// Whether rollout of the Deployment is paused.
+	Paused bool `json:"paused"`
+
+	// Active alerts of the Deployment, nil if there are none.
+	Alerts *alertmanager.AlertBadge `json:"alerts,omitempty"`
+
+	// Metrics of the Deployment, set only if item metrics are requested.
+	Metrics []metric.Metric `json:"metrics,omitempty"`
 }
 
 // GetDeploymentList returns a list of all Deployments in the cluster.
 func GetDeploymentList(client client.Interface, nsQuery *common.NamespaceQuery,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*DeploymentList, error) {
+	dsQuery *dataselect.DataSelectQuery, heapsterClient *metricapi.MetricClient) (*DeploymentList, error) {
 	log.Print("Getting list of all deployments in the cluster")
 
+	options := dsQuery.ListOptions()
 	channels := &common.ResourceChannels{
-		DeploymentList: common.GetDeploymentListChannel(client, nsQuery, 1),
-		PodList:        common.GetPodListChannel(client, nsQuery, 1),
-		EventList:      common.GetEventListChannel(client, nsQuery, 1),
+		DeploymentList: common.GetDeploymentListChannelWithOptions(client, nsQuery, options, 1),
+		PodList:        common.GetFanOutPodListChannel(client, nsQuery, 1),
+		EventList:      common.GetWarningEventListChannel(client, nsQuery, 1),
 		ReplicaSetList: common.GetReplicaSetListChannel(client, nsQuery, 1),
 	}
 
@@ -70,7 +84,7 @@ func GetDeploymentList(client client.Interface, nsQuery *common.NamespaceQuery,
 // GetDeploymentList returns a list of all Deployments in the cluster
 // reading required resource list once from the channels.
 func GetDeploymentListFromChannels(channels *common.ResourceChannels,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*DeploymentList, error) {
+	dsQuery *dataselect.DataSelectQuery, heapsterClient *metricapi.MetricClient) (*DeploymentList, error) {
 
 	deployments := <-channels.DeploymentList.List
 	if err := <-channels.DeploymentList.Error; err != nil {
@@ -101,39 +115,45 @@ func GetDeploymentListFromChannels(channels *common.ResourceChannels,
 		return nil, err
 	}
 
-	return CreateDeploymentList(deployments.Items, pods.Items, events.Items, rs.Items, dsQuery, heapsterClient), nil
+	result := CreateDeploymentList(deployments.Items, pods.Items, events.Items, rs.Items, dsQuery, heapsterClient)
+	result.ListMeta.Warnings = common.GetListBudgetWarnings(pods, events)
+	return result, nil
 }
 
 // CreateDeploymentList returns a list of all Deployment model objects in the cluster, based on all
 // Kubernetes Deployment API objects.
 func CreateDeploymentList(deployments []extensions.Deployment, pods []v1.Pod, events []v1.Event,
 	rs []extensions.ReplicaSet, dsQuery *dataselect.DataSelectQuery,
-	heapsterClient *heapster.HeapsterClient) *DeploymentList {
+	heapsterClient *metricapi.MetricClient) *DeploymentList {
 
 	deploymentList := &DeploymentList{
 		Deployments: make([]Deployment, 0),
 		ListMeta:    api.ListMeta{TotalItems: len(deployments)},
 	}
 
+	for _, deployment := range fromCells(dataselect.GenericDataFilter(toCells(deployments), dsQuery)) {
+		deploymentList.Status.AddPodInfo(getPodInfo(deployment, rs, pods, events))
+	}
+
 	cachedResources := &dataselect.CachedResources{
 		Pods: pods,
 	}
-	deploymentCells, metricPromises, filteredTotal := dataselect.GenericDataSelectWithFilterAndMetrics(toCells(deployments), dsQuery, cachedResources, heapsterClient)
+	deploymentCells, metricPromises, itemMetricPromises, filteredTotal :=
+		dataselect.GenericDataSelectWithFilterAndItemMetrics(toCells(deployments), dsQuery, cachedResources, heapsterClient)
 	deployments = fromCells(deploymentCells)
-	deploymentList.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+	deploymentList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, deploymentList.ListMeta.TotalItems)
 
-	for _, deployment := range deployments {
-		matchingPods := common.FilterDeploymentPodsByOwnerReference(deployment, rs, pods)
-		podInfo := common.GetPodInfo(deployment.Status.Replicas, *deployment.Spec.Replicas,
-			matchingPods)
-		podInfo.Warnings = event.GetPodsEventWarnings(events, matchingPods)
+	for i, deployment := range deployments {
+		podInfo := getPodInfo(deployment, rs, pods, events)
 
 		deploymentList.Deployments = append(deploymentList.Deployments,
 			Deployment{
 				ObjectMeta:      api.NewObjectMeta(deployment.ObjectMeta),
 				TypeMeta:        api.NewTypeMeta(api.ResourceKindDeployment),
 				ContainerImages: common.GetContainerImages(&deployment.Spec.Template.Spec),
+				Paused:          deployment.Spec.Paused,
 				Pods:            podInfo,
+				Metrics:         metric.GetItemMetrics(itemMetricPromises, i),
 			})
 	}
 
@@ -145,3 +165,12 @@ func CreateDeploymentList(deployments []extensions.Deployment, pods []v1.Pod, ev
 
 	return deploymentList
 }
+
+// getPodInfo returns aggregate information about pods of given deployment.
+func getPodInfo(deployment extensions.Deployment, rs []extensions.ReplicaSet, pods []v1.Pod,
+	events []v1.Event) common.PodInfo {
+	matchingPods := common.FilterDeploymentPodsByOwnerReference(deployment, rs, pods)
+	podInfo := common.GetPodInfo(deployment.Status.Replicas, *deployment.Spec.Replicas, matchingPods)
+	podInfo.Warnings = event.GetPodsEventWarnings(events, matchingPods)
+	return podInfo
+}
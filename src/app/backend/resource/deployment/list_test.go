@@ -110,7 +110,8 @@ func TestGetDeploymentListFromChannels(t *testing.T) {
 			nil,
 			&v1.PodList{},
 			&DeploymentList{
-				ListMeta:          api.ListMeta{TotalItems: 1},
+				ListMeta:          api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
+				Status:            common.ResourceStatus{Pending: 1},
 				CumulativeMetrics: make([]metric.Metric, 0),
 				Deployments: []Deployment{{
 					ObjectMeta: api.ObjectMeta{
@@ -187,3 +188,36 @@ func TestGetDeploymentListFromChannels(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateDeploymentListStatus(t *testing.T) {
+	deployments := []extensions.Deployment{
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "a", Namespace: "default"},
+			Spec:       extensions.DeploymentSpec{Replicas: getReplicasPointer(0)},
+		},
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "b", Namespace: "default"},
+			Spec:       extensions.DeploymentSpec{Replicas: getReplicasPointer(2)},
+		},
+	}
+	cases := []struct {
+		filterQuery *dataselect.FilterQuery
+		expected    common.ResourceStatus
+	}{
+		{dataselect.NoFilter, common.ResourceStatus{Running: 1, Pending: 1}},
+		{dataselect.NewFilterQuery([]string{"name", "b"}), common.ResourceStatus{Pending: 1}},
+	}
+	for _, c := range cases {
+		dsQuery := dataselect.NewDataSelectQuery(dataselect.NewPaginationQuery(1, 0), dataselect.NoSort,
+			c.filterQuery, dataselect.NoMetrics)
+
+		actual := CreateDeploymentList(deployments, nil, nil, nil, dsQuery, nil)
+		if len(actual.Deployments) != 1 {
+			t.Fatalf("CreateDeploymentList() returned %d deployments, expected 1", len(actual.Deployments))
+		}
+		if !reflect.DeepEqual(actual.Status, c.expected) {
+			t.Errorf("CreateDeploymentList() with filter %s status == %#v, expected %#v of all filtered "+
+				"deployments", c.filterQuery, actual.Status, c.expected)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubectlResource "k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+// CRDEstablishTimeout is how long deployment from file waits for created custom resource
+// definitions to be established, before custom resources are created.
+const CRDEstablishTimeout = 30 * time.Second
+
+// crdEstablishInterval is the interval of checks whether custom resource definitions are established.
+const crdEstablishInterval = time.Second
+
+// Stages of deployment from file. Manifests are applied in order of their stages, so that objects
+// they depend on, e.g. their namespace, exist first.
+const (
+	stageNamespaces = iota
+	stageCustomResourceDefinitions
+	stageRBAC
+	stageConfigs
+	stageWorkloads
+	stageCustomResources
+)
+
+// kindStages maps kinds of built-in objects to their stages. Other built-in objects are workloads.
+var kindStages = map[string]int{
+	"Namespace":                stageNamespaces,
+	"CustomResourceDefinition": stageCustomResourceDefinitions,
+	"ThirdPartyResource":       stageCustomResourceDefinitions,
+	"ServiceAccount":           stageRBAC,
+	"Role":                     stageRBAC,
+	"ClusterRole":              stageRBAC,
+	"RoleBinding":              stageRBAC,
+	"ClusterRoleBinding":       stageRBAC,
+	"PodSecurityPolicy":        stageRBAC,
+	"ConfigMap":                stageConfigs,
+	"Secret":                   stageConfigs,
+	"StorageClass":             stageConfigs,
+	"PersistentVolume":         stageConfigs,
+	"PersistentVolumeClaim":    stageConfigs,
+	"LimitRange":               stageConfigs,
+	"ResourceQuota":            stageConfigs,
+}
+
+// manifest is a single document of deployment file.
+type manifest struct {
+	stage   int
+	content string
+}
+
+// manifestPhase is a group of manifests deployed together. Unstructured phases contain objects that
+// are not known to the dashboard, i.e. custom resource definitions and custom resources.
+type manifestPhase struct {
+	unstructured bool
+	content      string
+}
+
+// getManifestPhases splits content of deployment file into phases deployed one after another. When
+// content cannot be parsed, it is deployed in a single phase, so that the error is reported as
+// without ordering.
+func getManifestPhases(content string) []manifestPhase {
+	manifests, err := orderManifests(content)
+	if err != nil {
+		return []manifestPhase{{content: content}}
+	}
+
+	phases := make([]manifestPhase, 0)
+	for _, manifest := range manifests {
+		unstructured := manifest.stage == stageCustomResourceDefinitions ||
+			manifest.stage == stageCustomResources
+		if len(phases) == 0 || phases[len(phases)-1].unstructured != unstructured {
+			phases = append(phases, manifestPhase{unstructured: unstructured})
+		}
+		phase := &phases[len(phases)-1]
+		if phase.content != "" {
+			phase.content += "\n---\n"
+		}
+		phase.content += manifest.content
+	}
+	return phases
+}
+
+// orderManifests splits content of deployment file into documents ordered by their stages.
+// Documents of the same stage keep their order.
+func orderManifests(content string) ([]manifest, error) {
+	manifests := make([]manifest, 0)
+	for _, document := range strings.Split("\n"+content, "\n---") {
+		object := make(map[string]interface{})
+		if err := yaml.Unmarshal([]byte(document), &object); err != nil {
+			return nil, err
+		}
+		if len(object) > 0 {
+			manifests = append(manifests, manifest{stage: getManifestStage(object),
+				content: strings.Trim(document, "\n")})
+		}
+	}
+	sort.Stable(manifestsByStage(manifests))
+	return manifests, nil
+}
+
+// getManifestStage returns stage of given object. Objects outside of built-in API groups are
+// custom resources.
+func getManifestStage(object map[string]interface{}) int {
+	kind, _ := object["kind"].(string)
+	apiVersion, _ := object["apiVersion"].(string)
+	if !isBuiltInAPIVersion(apiVersion) {
+		return stageCustomResources
+	}
+	if stage, ok := kindStages[kind]; ok {
+		return stage
+	}
+	return stageWorkloads
+}
+
+// isBuiltInAPIVersion returns true if given API version belongs to the core group, a group without
+// domain, e.g. apps, or a *.k8s.io group.
+func isBuiltInAPIVersion(apiVersion string) bool {
+	if !strings.Contains(apiVersion, "/") {
+		return true
+	}
+	group := apiVersion[:strings.Index(apiVersion, "/")]
+	return !strings.Contains(group, ".") || strings.HasSuffix(group, ".k8s.io")
+}
+
+// waitForEstablished waits until custom resource definition of given info is established, so that
+// its custom resources can be created. Third party resources have no such condition and are not
+// waited for.
+func waitForEstablished(info *kubectlResource.Info) error {
+	if info.Mapping.GroupVersionKind.Kind != "CustomResourceDefinition" {
+		return nil
+	}
+
+	helper := kubectlResource.NewHelper(info.Client, info.Mapping)
+	err := wait.PollImmediate(crdEstablishInterval, CRDEstablishTimeout, func() (bool, error) {
+		object, err := helper.Get(info.Namespace, info.Name, false)
+		if err != nil {
+			return false, err
+		}
+		return isEstablished(object), nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return k8serrors.NewTimeoutError(fmt.Sprintf(
+			"custom resource definition %s is not established", info.Name), 0)
+	}
+	return err
+}
+
+// isEstablished returns true if given custom resource definition has Established condition.
+func isEstablished(object runtime.Object) bool {
+	definition, ok := object.(*unstructured.Unstructured)
+	if !ok {
+		return false
+	}
+	status, _ := definition.Object["status"].(map[string]interface{})
+	conditions, _ := status["conditions"].([]interface{})
+	for _, item := range conditions {
+		condition, _ := item.(map[string]interface{})
+		if condition["type"] == "Established" && condition["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
+
+type manifestsByStage []manifest
+
+func (self manifestsByStage) Len() int           { return len(self) }
+func (self manifestsByStage) Swap(i, j int)      { self[i], self[j] = self[j], self[i] }
+func (self manifestsByStage) Less(i, j int) bool { return self[i].stage < self[j].stage }
@@ -0,0 +1,96 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	api "k8s.io/client-go/pkg/api/v1"
+)
+
+func TestGetManifestPhases(t *testing.T) {
+	cases := []struct {
+		content  string
+		expected []manifestPhase
+	}{
+		{
+			"apiVersion: extensions/v1beta1\nkind: Deployment\n---\n" +
+				"apiVersion: v1\nkind: ConfigMap\n---\n" +
+				"apiVersion: rbac.authorization.k8s.io/v1beta1\nkind: Role\n---\n" +
+				"apiVersion: v1\nkind: Namespace\n",
+			[]manifestPhase{
+				{content: "apiVersion: v1\nkind: Namespace\n---\n" +
+					"apiVersion: rbac.authorization.k8s.io/v1beta1\nkind: Role\n---\n" +
+					"apiVersion: v1\nkind: ConfigMap\n---\n" +
+					"apiVersion: extensions/v1beta1\nkind: Deployment"},
+			},
+		},
+		{
+			"apiVersion: example.com/v1\nkind: Foo\n---\n" +
+				"apiVersion: v1\nkind: Service\n---\n" +
+				"apiVersion: apiextensions.k8s.io/v1beta1\nkind: CustomResourceDefinition\n---\n" +
+				"apiVersion: v1\nkind: Namespace\n",
+			[]manifestPhase{
+				{content: "apiVersion: v1\nkind: Namespace"},
+				{unstructured: true,
+					content: "apiVersion: apiextensions.k8s.io/v1beta1\nkind: CustomResourceDefinition"},
+				{content: "apiVersion: v1\nkind: Service"},
+				{unstructured: true, content: "apiVersion: example.com/v1\nkind: Foo"},
+			},
+		},
+		{
+			"{\"apiVersion\": \"v1\", \"kind\": \"Pod\"}",
+			[]manifestPhase{{content: "{\"apiVersion\": \"v1\", \"kind\": \"Pod\"}"}},
+		},
+		{
+			"kind: [Pod",
+			[]manifestPhase{{content: "kind: [Pod"}},
+		},
+	}
+	for _, c := range cases {
+		actual := getManifestPhases(c.content)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("getManifestPhases(%#v) == \ngot: %#v, \nexpected %#v", c.content, actual, c.expected)
+		}
+	}
+}
+
+func TestIsEstablished(t *testing.T) {
+	newDefinition := func(conditions ...interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"conditions": conditions},
+		}}
+	}
+	cases := []struct {
+		object   runtime.Object
+		expected bool
+	}{
+		{newDefinition(), false},
+		{newDefinition(map[string]interface{}{"type": "NamesAccepted", "status": "True"},
+			map[string]interface{}{"type": "Established", "status": "True"}), true},
+		{newDefinition(map[string]interface{}{"type": "Established", "status": "False"}), false},
+		{&unstructured.Unstructured{Object: map[string]interface{}{}}, false},
+		{&api.Pod{}, false},
+	}
+	for _, c := range cases {
+		actual := isEstablished(c.object)
+		if actual != c.expected {
+			t.Errorf("isEstablished(%#v) == %#v, expected %#v", c.object, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// NamespaceParameter is the name of parameter that defaults to the namespace of deploy from file.
+const NamespaceParameter = "NAMESPACE"
+
+// parameterPattern matches references to parameters in manifests, e.g. ${IMAGE_TAG}.
+var parameterPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// RenderDeploymentFile returns content of given deploy from file spec as it is deployed. Content is
+// rendered from kustomization or fetched from URL, if set, and parameters are substituted in it,
+// if given.
+func RenderDeploymentFile(spec *AppDeploymentFromFileSpec) (string, error) {
+	content := spec.Content
+	var err error
+	if spec.Kustomization != nil {
+		content, err = RenderKustomization(spec.Kustomization)
+	} else if spec.URL != "" {
+		content, err = fetchRemoteManifest(spec.URL, spec.SHA256)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// Files deployed without parameters may contain ${...} for other purposes, e.g. in scripts.
+	if spec.Parameters == nil {
+		return content, nil
+	}
+
+	parameters := make(map[string]string)
+	if spec.Namespace != "" && spec.Namespace != "_all" {
+		parameters[NamespaceParameter] = spec.Namespace
+	}
+	for name, value := range spec.Parameters {
+		parameters[name] = value
+	}
+	return substituteParameters(content, parameters)
+}
+
+// substituteParameters replaces references to parameters in given content with their values. All
+// referenced parameters have to be defined, so that no manifest is deployed half substituted.
+func substituteParameters(content string, parameters map[string]string) (string, error) {
+	undefined := make(map[string]bool)
+	result := parameterPattern.ReplaceAllStringFunc(content, func(reference string) string {
+		name := parameterPattern.FindStringSubmatch(reference)[1]
+		value, ok := parameters[name]
+		if !ok {
+			undefined[name] = true
+			return reference
+		}
+		return value
+	})
+
+	if len(undefined) > 0 {
+		names := make([]string, 0, len(undefined))
+		for name := range undefined {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", k8serrors.NewBadRequest(fmt.Sprintf("Undefined parameters: %s",
+			strings.Join(names, ", ")))
+	}
+	return result, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func TestRenderDeploymentFile(t *testing.T) {
+	content := "metadata:\n  name: ${NAME}\n  namespace: ${NAMESPACE}\nspec:\n  replicas: ${REPLICAS}\n" +
+		"  image: app:${IMAGE_TAG}\n"
+	cases := []struct {
+		spec     *AppDeploymentFromFileSpec
+		expected string
+	}{
+		{
+			&AppDeploymentFromFileSpec{Namespace: "staging", Content: content, Parameters: map[string]string{
+				"NAME": "app", "REPLICAS": "3", "IMAGE_TAG": "v1.2",
+			}},
+			"metadata:\n  name: app\n  namespace: staging\nspec:\n  replicas: 3\n  image: app:v1.2\n",
+		},
+		{
+			&AppDeploymentFromFileSpec{Namespace: "staging", Content: content, Parameters: map[string]string{
+				"NAME": "app", "NAMESPACE": "prod", "REPLICAS": "5", "IMAGE_TAG": "v1.1",
+			}},
+			"metadata:\n  name: app\n  namespace: prod\nspec:\n  replicas: 5\n  image: app:v1.1\n",
+		},
+		{&AppDeploymentFromFileSpec{Namespace: "staging", Content: content}, content},
+	}
+	for _, c := range cases {
+		actual, err := RenderDeploymentFile(c.spec)
+		if err != nil || actual != c.expected {
+			t.Errorf("RenderDeploymentFile(%#v) == \ngot: %#v, %v, \nexpected %#v", c.spec, actual, err,
+				c.expected)
+		}
+	}
+}
+
+func TestRenderDeploymentFileUndefinedParameters(t *testing.T) {
+	spec := &AppDeploymentFromFileSpec{
+		Namespace:  "_all",
+		Content:    "name: ${NAME}\nnamespace: ${NAMESPACE}\n",
+		Parameters: map[string]string{},
+	}
+
+	_, err := RenderDeploymentFile(spec)
+	if !k8serrors.IsBadRequest(err) || err.Error() != "Undefined parameters: NAME, NAMESPACE" {
+		t.Errorf("RenderDeploymentFile(%#v) should return bad request but got %v", spec, err)
+	}
+}
@@ -15,7 +15,7 @@
 package deployment
 
 import (
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/pod"
@@ -25,7 +25,7 @@ import (
 )
 
 // GetDeploymentPods returns list of pods targeting deployment.
-func GetDeploymentPods(client client.Interface, heapsterClient heapster.HeapsterClient,
+func GetDeploymentPods(client client.Interface, heapsterClient metricapi.MetricClient,
 	dsQuery *dataselect.DataSelectQuery, namespace, deploymentName string) (*pod.PodList, error) {
 
 	deployment, err := client.ExtensionsV1beta1().Deployments(namespace).Get(deploymentName, metaV1.GetOptions{})
@@ -0,0 +1,245 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/operation"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	client "k8s.io/client-go/kubernetes"
+	api "k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+// MaxWaitReadyTimeout is the maximum time deployment endpoints wait for created deployments to
+// become ready.
+const MaxWaitReadyTimeout = 10 * time.Minute
+
+// waitReadyInterval is the interval of checks whether deployment is ready.
+var waitReadyInterval = 2 * time.Second
+
+// UnreadyPod is a pod of a deployment that is not ready, with reasons why.
+type UnreadyPod struct {
+	// Name of the pod.
+	Name string `json:"name"`
+
+	// Phase of the pod, e.g. Pending.
+	Phase api.PodPhase `json:"phase"`
+
+	// Reasons of the pod not being ready, e.g. waiting containers or failed scheduling.
+	Reasons []string `json:"reasons"`
+}
+
+// DeploymentReadiness describes whether a deployment became ready in time. When it did not,
+// diagnostics of the failure are filled in.
+type DeploymentReadiness struct {
+	// True when all replicas of the deployment are updated and available.
+	Ready bool `json:"ready"`
+
+	// Conditions of the deployment that are not met, e.g. failed creation of replicas.
+	Conditions []string `json:"conditions"`
+
+	// Pods of the deployment that are not ready.
+	UnreadyPods []UnreadyPod `json:"unreadyPods"`
+
+	// Warning events of the deployment, its replica sets and unready pods.
+	Events []common.Event `json:"events"`
+}
+
+// WaitForDeploymentReady waits until given deployment reports that all its replicas are updated
+// and available. When the deployment does not become ready within given timeout, diagnostics
+// of the failure are returned. Progress is reported to given tracker of an asynchronous operation,
+// which can also cancel the waiting. Tracker is nil when waiting synchronously.
+func WaitForDeploymentReady(client client.Interface, namespace, name string,
+	timeout time.Duration, tracker operation.Tracker) (*DeploymentReadiness, error) {
+	log.Printf("Waiting %s for %s deployment in %s namespace to be ready", timeout, name, namespace)
+
+	var cancelled <-chan struct{}
+	if tracker != nil {
+		cancelled = tracker.Cancelled()
+	}
+	var deployment *extensions.Deployment
+	lastProgress := -1
+	err := wait.PollImmediate(waitReadyInterval, timeout, func() (bool, error) {
+		select {
+		case <-cancelled:
+			return false, fmt.Errorf("Waiting for %s deployment to be ready was cancelled", name)
+		default:
+		}
+
+		var err error
+		deployment, err = client.ExtensionsV1beta1().Deployments(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if progress, message := getReadinessProgress(deployment); tracker != nil && progress != lastProgress {
+			tracker.SetProgress(progress)
+			tracker.Logf("%s", message)
+			lastProgress = progress
+		}
+		return isDeploymentReady(deployment), nil
+	})
+
+	readiness := &DeploymentReadiness{
+		Ready:       err == nil,
+		Conditions:  make([]string, 0),
+		UnreadyPods: make([]UnreadyPod, 0),
+		Events:      make([]common.Event, 0),
+	}
+	if err == nil {
+		return readiness, nil
+	}
+	if err != wait.ErrWaitTimeout {
+		return nil, err
+	}
+
+	log.Printf("Deployment %s in %s namespace is not ready after %s", name, namespace, timeout)
+	return readiness, getReadinessDiagnostics(client, deployment, readiness)
+}
+
+// getReadinessProgress returns percentage of available replicas of given deployment and
+// a message describing it.
+func getReadinessProgress(deployment *extensions.Deployment) (int, string) {
+	replicas := getDesiredReplicas(deployment)
+	available := deployment.Status.AvailableReplicas
+	message := fmt.Sprintf("%d of %d replicas are available", available, replicas)
+	if replicas == 0 || available >= replicas {
+		return 100, message
+	}
+	return int(available * 100 / replicas), message
+}
+
+func getDesiredReplicas(deployment *extensions.Deployment) int32 {
+	if deployment.Spec.Replicas != nil {
+		return *deployment.Spec.Replicas
+	}
+	return 1
+}
+
+// isDeploymentReady returns true if controller observed the latest spec of given deployment and it
+// reports Available condition with all its replicas updated and available.
+func isDeploymentReady(deployment *extensions.Deployment) bool {
+	replicas := getDesiredReplicas(deployment)
+	status := deployment.Status
+	if status.ObservedGeneration < deployment.Generation || status.UpdatedReplicas < replicas ||
+		status.AvailableReplicas < replicas {
+		return false
+	}
+	for _, condition := range status.Conditions {
+		if condition.Type == extensions.DeploymentAvailable {
+			return condition.Status == api.ConditionTrue
+		}
+	}
+	return false
+}
+
+// getReadinessDiagnostics fills in unmet conditions, unready pods and warning events of given
+// deployment.
+func getReadinessDiagnostics(client client.Interface, deployment *extensions.Deployment,
+	readiness *DeploymentReadiness) error {
+	for _, condition := range deployment.Status.Conditions {
+		failed := condition.Status != api.ConditionTrue
+		if condition.Type == extensions.DeploymentReplicaFailure {
+			failed = condition.Status == api.ConditionTrue
+		}
+		if failed {
+			readiness.Conditions = append(readiness.Conditions,
+				fmt.Sprintf("%s: %s", condition.Type, condition.Message))
+		}
+	}
+
+	selector, err := metaV1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return err
+	}
+	pods, err := client.CoreV1().Pods(deployment.Namespace).List(metaV1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods.Items {
+		if reasons := getUnreadyReasons(pod); len(reasons) > 0 {
+			readiness.UnreadyPods = append(readiness.UnreadyPods,
+				UnreadyPod{Name: pod.Name, Phase: pod.Status.Phase, Reasons: reasons})
+		}
+	}
+
+	replicaSets, err := client.ExtensionsV1beta1().ReplicaSets(deployment.Namespace).List(
+		metaV1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return err
+	}
+	owners := map[string]bool{deployment.Name: true}
+	for _, replicaSet := range replicaSets.Items {
+		if isOwnedBy(replicaSet.ObjectMeta, deployment.UID) {
+			owners[replicaSet.Name] = true
+		}
+	}
+
+	events, err := client.CoreV1().Events(deployment.Namespace).List(metaV1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	podEvents := make([]api.Event, 0)
+	for _, item := range events.Items {
+		switch item.InvolvedObject.Kind {
+		case "Pod":
+			podEvents = append(podEvents, item)
+		case "Deployment", "ReplicaSet":
+			if item.Type == api.EventTypeWarning && owners[item.InvolvedObject.Name] {
+				readiness.Events = append(readiness.Events, event.ToEvent(item))
+			}
+		}
+	}
+	readiness.Events = append(readiness.Events, event.GetPodsEventWarnings(podEvents, pods.Items)...)
+	return nil
+}
+
+// getUnreadyReasons returns reasons of given pod not being ready. Empty if the pod is ready.
+func getUnreadyReasons(pod api.Pod) []string {
+	reasons := make([]string, 0)
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == api.PodReady && condition.Status == api.ConditionTrue {
+			return make([]string, 0)
+		}
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Status != api.ConditionTrue && condition.Message != "" {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", condition.Reason, condition.Message))
+		}
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if waiting := status.State.Waiting; waiting != nil {
+			reasons = append(reasons, strings.TrimSpace(fmt.Sprintf("Container %s is waiting: %s %s",
+				status.Name, waiting.Reason, waiting.Message)))
+		}
+		if terminated := status.State.Terminated; terminated != nil {
+			reasons = append(reasons, strings.TrimSpace(fmt.Sprintf("Container %s is terminated: %s %s",
+				status.Name, terminated.Reason, terminated.Message)))
+		}
+	}
+	if len(reasons) == 0 {
+		reasons = append(reasons, fmt.Sprintf("Pod is %s", pod.Status.Phase))
+	}
+	return reasons
+}
@@ -0,0 +1,116 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	api "k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+func TestWaitForDeploymentReady(t *testing.T) {
+	waitReadyInterval = time.Millisecond
+	replicas := int32(2)
+	selector := &metaV1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	newDeployment := func(name string, status extensions.DeploymentStatus) *extensions.Deployment {
+		return &extensions.Deployment{
+			ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "prod"},
+			Spec:       extensions.DeploymentSpec{Replicas: &replicas, Selector: selector},
+			Status:     status,
+		}
+	}
+	available := extensions.DeploymentCondition{Type: extensions.DeploymentAvailable,
+		Status: api.ConditionTrue}
+	fakeClient := fake.NewSimpleClientset(
+		newDeployment("ready", extensions.DeploymentStatus{UpdatedReplicas: 2, AvailableReplicas: 2,
+			Conditions: []extensions.DeploymentCondition{available}}),
+		newDeployment("web", extensions.DeploymentStatus{UpdatedReplicas: 2, AvailableReplicas: 1,
+			Conditions: []extensions.DeploymentCondition{available, {
+				Type: extensions.DeploymentReplicaFailure, Status: api.ConditionTrue,
+				Message: "exceeded quota"}}}),
+		&api.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web-1", Namespace: "prod", Labels: selector.MatchLabels},
+			Status: api.PodStatus{Phase: api.PodRunning, Conditions: []api.PodCondition{
+				{Type: api.PodReady, Status: api.ConditionTrue}}},
+		},
+		&api.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web-2", Namespace: "prod", Labels: selector.MatchLabels},
+			Status: api.PodStatus{Phase: api.PodPending, ContainerStatuses: []api.ContainerStatus{{
+				Name:  "web",
+				State: api.ContainerState{Waiting: &api.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+			}}},
+		},
+		&api.Event{
+			ObjectMeta:     metaV1.ObjectMeta{Name: "event-1", Namespace: "prod"},
+			InvolvedObject: api.ObjectReference{Kind: "Deployment", Name: "web"},
+			Type:           api.EventTypeWarning,
+			Message:        "quota exceeded",
+		},
+		&api.Event{
+			ObjectMeta:     metaV1.ObjectMeta{Name: "event-2", Namespace: "prod"},
+			InvolvedObject: api.ObjectReference{Kind: "Deployment", Name: "web"},
+			Type:           api.EventTypeNormal,
+		},
+	)
+
+	cases := []struct {
+		name     string
+		expected *DeploymentReadiness
+	}{
+		{
+			"ready",
+			&DeploymentReadiness{Ready: true, Conditions: []string{}, UnreadyPods: []UnreadyPod{},
+				Events: []common.Event{}},
+		},
+		{
+			"web",
+			&DeploymentReadiness{
+				Ready:      false,
+				Conditions: []string{"ReplicaFailure: exceeded quota"},
+				UnreadyPods: []UnreadyPod{{Name: "web-2", Phase: api.PodPending,
+					Reasons: []string{"Container web is waiting: ImagePullBackOff"}}},
+			},
+		},
+	}
+	for _, c := range cases {
+		actual, err := WaitForDeploymentReady(fakeClient, "prod", c.name, 10*time.Millisecond, nil)
+		if err != nil {
+			t.Errorf("WaitForDeploymentReady(client, prod, %#v) returns error %v", c.name, err)
+			continue
+		}
+		if c.name == "web" {
+			// events are converted with their object meta, compare only their messages
+			if len(actual.Events) != 1 || actual.Events[0].Message != "quota exceeded" {
+				t.Errorf("WaitForDeploymentReady(client, prod, %#v) returns events %#v, expected "+
+					"the warning of the deployment", c.name, actual.Events)
+			}
+			actual.Events = nil
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("WaitForDeploymentReady(client, prod, %#v) == \ngot: %#v, \nexpected %#v",
+				c.name, actual, c.expected)
+		}
+	}
+
+	if _, err := WaitForDeploymentReady(fakeClient, "prod", "missing", time.Millisecond, nil); err == nil {
+		t.Error("WaitForDeploymentReady(client, prod, missing) returns no error, expected not found")
+	}
+}
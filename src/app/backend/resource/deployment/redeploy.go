@@ -0,0 +1,80 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"fmt"
+	"log"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+)
+
+// PauseDeployment pauses rollout of given deployment. Changes of its pod template are not rolled
+// out until the deployment is resumed.
+func PauseDeployment(client client.Interface, namespace, name string) error {
+	log.Printf("Pausing %s deployment in %s namespace", name, namespace)
+	return setDeploymentPaused(client, namespace, name, true)
+}
+
+// ResumeDeployment resumes paused rollout of given deployment.
+func ResumeDeployment(client client.Interface, namespace, name string) error {
+	log.Printf("Resuming %s deployment in %s namespace", name, namespace)
+	return setDeploymentPaused(client, namespace, name, false)
+}
+
+func setDeploymentPaused(client client.Interface, namespace, name string, paused bool) error {
+	deployment, err := client.ExtensionsV1beta1().Deployments(namespace).Get(name, metaV1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if deployment.Spec.Paused == paused {
+		return nil
+	}
+	deployment.Spec.Paused = paused
+	_, err = client.ExtensionsV1beta1().Deployments(namespace).Update(deployment)
+	return err
+}
+
+// SetDeploymentImage sets image of given container of given deployment. Container name can be
+// empty for deployments with a single container.
+func SetDeploymentImage(client client.Interface, namespace, name, container, image string) error {
+	log.Printf("Setting image of %s deployment in %s namespace to %s", name, namespace, image)
+	if image == "" {
+		return k8serrors.NewBadRequest("Image is required")
+	}
+
+	deployment, err := client.ExtensionsV1beta1().Deployments(namespace).Get(name, metaV1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	containers := deployment.Spec.Template.Spec.Containers
+	index := -1
+	for i := range containers {
+		if containers[i].Name == container || (container == "" && len(containers) == 1) {
+			index = i
+		}
+	}
+	if index < 0 {
+		return k8serrors.NewBadRequest(fmt.Sprintf("Deployment %s has no container %q", name, container))
+	}
+
+	containers[index].Image = image
+	_, err = client.ExtensionsV1beta1().Deployments(namespace).Update(deployment)
+	return err
+}
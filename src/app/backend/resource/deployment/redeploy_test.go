@@ -0,0 +1,93 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	api "k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+func getTestDeployment(containers ...string) *extensions.Deployment {
+	deployment := &extensions.Deployment{
+		ObjectMeta: metaV1.ObjectMeta{Name: "app", Namespace: "prod"},
+	}
+	for _, name := range containers {
+		deployment.Spec.Template.Spec.Containers = append(deployment.Spec.Template.Spec.Containers,
+			api.Container{Name: name, Image: name + ":v1"})
+	}
+	return deployment
+}
+
+func TestPauseAndResumeDeployment(t *testing.T) {
+	testClient := fake.NewSimpleClientset(getTestDeployment("app"))
+
+	if err := PauseDeployment(testClient, "prod", "app"); err != nil {
+		t.Fatalf("PauseDeployment() returned error: %s", err)
+	}
+	actual, _ := testClient.ExtensionsV1beta1().Deployments("prod").Get("app", metaV1.GetOptions{})
+	if !actual.Spec.Paused {
+		t.Error("PauseDeployment() should pause deployment")
+	}
+
+	if err := ResumeDeployment(testClient, "prod", "app"); err != nil {
+		t.Fatalf("ResumeDeployment() returned error: %s", err)
+	}
+	actual, _ = testClient.ExtensionsV1beta1().Deployments("prod").Get("app", metaV1.GetOptions{})
+	if actual.Spec.Paused {
+		t.Error("ResumeDeployment() should resume deployment")
+	}
+
+	if err := PauseDeployment(testClient, "prod", "other"); !k8serrors.IsNotFound(err) {
+		t.Errorf("PauseDeployment() of missing deployment should return not found, got: %v", err)
+	}
+}
+
+func TestSetDeploymentImage(t *testing.T) {
+	cases := []struct {
+		containers       []string
+		container, image string
+		expectedIndex    int
+		expectBadRequest bool
+	}{
+		{[]string{"app"}, "", "app:v2", 0, false},
+		{[]string{"app", "proxy"}, "proxy", "proxy:v2", 1, false},
+		{[]string{"app", "proxy"}, "", "app:v2", 0, true},
+		{[]string{"app"}, "other", "app:v2", 0, true},
+		{[]string{"app"}, "app", "", 0, true},
+	}
+	for _, c := range cases {
+		testClient := fake.NewSimpleClientset(getTestDeployment(c.containers...))
+
+		err := SetDeploymentImage(testClient, "prod", "app", c.container, c.image)
+		if c.expectBadRequest {
+			if !k8serrors.IsBadRequest(err) {
+				t.Errorf("SetDeploymentImage(%#v, %#v) should return bad request, got: %v", c.container,
+					c.image, err)
+			}
+			continue
+		}
+
+		actual, _ := testClient.ExtensionsV1beta1().Deployments("prod").Get("app", metaV1.GetOptions{})
+		if err != nil || actual.Spec.Template.Spec.Containers[c.expectedIndex].Image != c.image {
+			t.Errorf("SetDeploymentImage(%#v, %#v) should set image, got: %#v, %v", c.container,
+				c.image, actual.Spec.Template.Spec.Containers, err)
+		}
+	}
+}
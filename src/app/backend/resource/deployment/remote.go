@@ -0,0 +1,123 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// MaxRemoteManifestSize is the maximum size in bytes of a manifest fetched from a remote URL.
+const MaxRemoteManifestSize = 1 << 20
+
+// Maximum number of redirects followed when fetching a remote manifest.
+const maxRemoteManifestRedirects = 10
+
+// remoteManifestClient is used to fetch remote manifests. Proxy is taken from HTTPS_PROXY and
+// NO_PROXY environment variables of the dashboard.
+var remoteManifestClient = &http.Client{
+	Timeout:       30 * time.Second,
+	Transport:     &http.Transport{Proxy: http.ProxyFromEnvironment},
+	CheckRedirect: checkRemoteManifestRedirect,
+}
+
+// checkRemoteManifestRedirect only follows redirects to HTTPS URLs, so that redirects cannot
+// downgrade the connection or point the dashboard at other schemes.
+func checkRemoteManifestRedirect(request *http.Request, via []*http.Request) error {
+	if len(via) >= maxRemoteManifestRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRemoteManifestRedirects)
+	}
+	if request.URL.Scheme != "https" {
+		return fmt.Errorf("redirect to non-HTTPS URL %s refused", request.URL)
+	}
+	return nil
+}
+
+// getRemoteManifestURL returns HTTPS URL of given manifest URL. Objects in S3 can be given as
+// s3://bucket/key, they have to be readable without signing, e.g. public ones. Presigned S3 URLs
+// are HTTPS URLs.
+func getRemoteManifestURL(manifestURL string) (string, error) {
+	parsed, err := url.Parse(manifestURL)
+	if err != nil {
+		return "", fmt.Errorf("Invalid manifest URL: %s", manifestURL)
+	}
+
+	switch parsed.Scheme {
+	case "https":
+		if parsed.Host == "" {
+			return "", fmt.Errorf("Invalid manifest URL: %s", manifestURL)
+		}
+		return parsed.String(), nil
+	case "s3":
+		key := strings.TrimPrefix(parsed.Path, "/")
+		if parsed.Host == "" || key == "" {
+			return "", fmt.Errorf("S3 manifest URL has to be in s3://bucket/key format: %s", manifestURL)
+		}
+		return (&url.URL{Scheme: "https", Host: parsed.Host + ".s3.amazonaws.com", Path: "/" + key}).
+			String(), nil
+	default:
+		return "", fmt.Errorf("Manifest URL has to be an HTTPS or S3 URL: %s", manifestURL)
+	}
+}
+
+// fetchRemoteManifest downloads manifest from given URL and checks that it has expected SHA256
+// checksum, so that only the reviewed artifact is deployed.
+func fetchRemoteManifest(manifestURL, expectedSHA256 string) (string, error) {
+	if expectedSHA256 == "" {
+		return "", k8serrors.NewBadRequest("SHA256 checksum of remote manifest is required")
+	}
+	remoteURL, err := getRemoteManifestURL(manifestURL)
+	if err != nil {
+		return "", k8serrors.NewBadRequest(err.Error())
+	}
+
+	response, err := remoteManifestClient.Get(remoteURL)
+	if err != nil {
+		return "", k8serrors.NewBadRequest(fmt.Sprintf("Cannot fetch manifest %s: %s", manifestURL,
+			err.Error()))
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", k8serrors.NewBadRequest(fmt.Sprintf("Cannot fetch manifest %s: server responded with %d",
+			manifestURL, response.StatusCode))
+	}
+
+	// Read one byte over the limit to detect too large manifests.
+	content, err := ioutil.ReadAll(io.LimitReader(response.Body, MaxRemoteManifestSize+1))
+	if err != nil {
+		return "", k8serrors.NewBadRequest(fmt.Sprintf("Cannot fetch manifest %s: %s", manifestURL,
+			err.Error()))
+	}
+	if len(content) > MaxRemoteManifestSize {
+		return "", k8serrors.NewBadRequest(fmt.Sprintf("Manifest %s is larger than %d bytes",
+			manifestURL, MaxRemoteManifestSize))
+	}
+
+	checksum := sha256.Sum256(content)
+	if hex.EncodeToString(checksum[:]) != strings.ToLower(expectedSHA256) {
+		return "", k8serrors.NewBadRequest(fmt.Sprintf("SHA256 checksum of manifest %s does not match",
+			manifestURL))
+	}
+	return string(content), nil
+}
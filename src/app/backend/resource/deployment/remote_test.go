@@ -0,0 +1,118 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func TestGetRemoteManifestURL(t *testing.T) {
+	cases := []struct {
+		url, expected string
+	}{
+		{"https://example.com/app.yaml?token=x", "https://example.com/app.yaml?token=x"},
+		{"s3://artifacts/builds/42/app.yaml", "https://artifacts.s3.amazonaws.com/builds/42/app.yaml"},
+		{"http://example.com/app.yaml", ""},
+		{"file:///etc/passwd", ""},
+		{"s3://artifacts", ""},
+	}
+	for _, c := range cases {
+		actual, err := getRemoteManifestURL(c.url)
+		if actual != c.expected || (err != nil) != (c.expected == "") {
+			t.Errorf("getRemoteManifestURL(%#v) == %#v, %v, expected %#v", c.url, actual, err, c.expected)
+		}
+	}
+}
+
+func TestFetchRemoteManifest(t *testing.T) {
+	manifest := "kind: Service\nmetadata:\n  name: app\n"
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/app.yaml":
+			w.Write([]byte(manifest))
+		case "/redirect.yaml":
+			http.Redirect(w, r, "/app.yaml", http.StatusFound)
+		case "/large.yaml":
+			w.Write([]byte(strings.Repeat("a", MaxRemoteManifestSize+1)))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	defaultClient := remoteManifestClient
+	remoteManifestClient = server.Client()
+	remoteManifestClient.CheckRedirect = checkRemoteManifestRedirect
+	defer func() { remoteManifestClient = defaultClient }()
+
+	checksum := sha256.Sum256([]byte(manifest))
+	expectedSHA256 := hex.EncodeToString(checksum[:])
+
+	for _, path := range []string{"/app.yaml", "/redirect.yaml"} {
+		actual, err := fetchRemoteManifest(server.URL+path, strings.ToUpper(expectedSHA256))
+		if err != nil || actual != manifest {
+			t.Errorf("fetchRemoteManifest(%#v) == %#v, %v, expected %#v", path, actual, err, manifest)
+		}
+	}
+
+	cases := []struct {
+		path, sha256 string
+	}{
+		{"/app.yaml", ""},
+		{"/app.yaml", strings.Repeat("0", 64)},
+		{"/large.yaml", expectedSHA256},
+		{"/missing.yaml", expectedSHA256},
+	}
+	for _, c := range cases {
+		_, err := fetchRemoteManifest(server.URL+c.path, c.sha256)
+		if !k8serrors.IsBadRequest(err) {
+			t.Errorf("fetchRemoteManifest(%#v, %#v) should return bad request but got %v", c.path,
+				c.sha256, err)
+		}
+	}
+
+	_, err := fetchRemoteManifest(server.URL+"/app.yaml", strings.Repeat("0", 64))
+	if err == nil || strings.Contains(err.Error(), expectedSHA256) {
+		t.Errorf("fetchRemoteManifest() with wrong checksum returns %v, expected error without "+
+			"actual checksum", err)
+	}
+}
+
+func TestCheckRemoteManifestRedirect(t *testing.T) {
+	cases := []struct {
+		url         string
+		via         int
+		expectError bool
+	}{
+		{"https://example.com/app.yaml", 1, false},
+		{"http://example.com/app.yaml", 1, true},
+		{"file:///etc/passwd", 1, true},
+		{"https://example.com/app.yaml", maxRemoteManifestRedirects, true},
+	}
+	for _, c := range cases {
+		request, _ := http.NewRequest("GET", c.url, nil)
+		err := checkRemoteManifestRedirect(request, make([]*http.Request, c.via))
+		if (err != nil) != c.expectError {
+			t.Errorf("checkRemoteManifestRedirect(%#v) returns error %v, expected error: %v", c.url,
+				err, c.expectError)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"log"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/replicahistory"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+)
+
+// GetDeploymentReplicasHistory returns history of desired and ready replica counts of given
+// deployment, ending with current counts. Only current counts are returned when recorder is nil.
+func GetDeploymentReplicasHistory(client client.Interface, recorder replicahistory.Recorder,
+	namespace, name string) (*replicahistory.ReplicasHistory, error) {
+	log.Printf("Getting replicas history of %s deployment in %s namespace", name, namespace)
+
+	// Also makes sure the user is allowed to see the deployment, as history is recorded with
+	// the dashboard's own credentials.
+	deployment, err := client.ExtensionsV1beta1().Deployments(namespace).Get(name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	history := &replicahistory.ReplicasHistory{Points: make([]replicahistory.ReplicasPoint, 0)}
+	if recorder != nil {
+		history.Points = recorder.GetHistory(namespace, name)
+	}
+
+	current := replicahistory.ReplicasPoint{
+		Timestamp: metaV1.NewTime(time.Now()),
+		Ready:     deployment.Status.ReadyReplicas,
+	}
+	if deployment.Spec.Replicas != nil {
+		current.Desired = *deployment.Spec.Replicas
+	}
+	history.Points = append(history.Points, current)
+
+	return history, nil
+}
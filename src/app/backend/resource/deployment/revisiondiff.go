@@ -0,0 +1,244 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	client "k8s.io/client-go/kubernetes"
+	api "k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+// LiveRevision is the revision number used for the live pod template of a deployment.
+const LiveRevision = 0
+
+// Types of field differences.
+const (
+	FieldAdded   = "added"
+	FieldRemoved = "removed"
+	FieldChanged = "changed"
+)
+
+// revisionResource is the resource name used in errors about revisions.
+var revisionResource = schema.GroupResource{Group: "extensions", Resource: "revisions"}
+
+// FieldDiff is a difference of a single field of pod templates.
+type FieldDiff struct {
+	// Path of the field. Elements of lists of named objects, e.g. of containers, are identified by
+	// their name, e.g. spec.containers[app].image.
+	Path string `json:"path"`
+
+	// Type of the difference, e.g. changed.
+	Type string `json:"type"`
+
+	// Values of the field in compared pod templates. From is empty for added fields and To for
+	// removed ones.
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// RevisionDiff is a field level difference of pod templates of two deployment revisions.
+type RevisionDiff struct {
+	// Compared revisions. LiveRevision means the live pod template of the deployment.
+	FromRevision int64 `json:"fromRevision"`
+	ToRevision   int64 `json:"toRevision"`
+
+	// Differences in order of the fields.
+	Fields []FieldDiff `json:"fields"`
+}
+
+// GetDeploymentRevisionDiff returns differences of pod templates of given revisions of given
+// deployment. LiveRevision can be used to compare with the live pod template.
+func GetDeploymentRevisionDiff(client client.Interface, namespace, name string, fromRevision,
+	toRevision int64) (*RevisionDiff, error) {
+	log.Printf("Getting diff of revisions %d and %d of %s deployment in %s namespace", fromRevision,
+		toRevision, name, namespace)
+
+	deployment, revisions, err := getDeploymentWithRevisions(client, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := getRevisionTemplate(deployment, revisions, fromRevision)
+	if err != nil {
+		return nil, err
+	}
+	to, err := getRevisionTemplate(deployment, revisions, toRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := diffPodTemplates(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &RevisionDiff{FromRevision: fromRevision, ToRevision: toRevision, Fields: fields}, nil
+}
+
+// getRevisionTemplate returns pod template of given revision without the pod template hash label,
+// which differs for every revision.
+func getRevisionTemplate(deployment *extensions.Deployment, revisions []extensions.ReplicaSet,
+	revision int64) (api.PodTemplateSpec, error) {
+	var template *api.PodTemplateSpec
+	if revision == LiveRevision {
+		template = &deployment.Spec.Template
+	}
+	for i := range revisions {
+		if getRevision(revisions[i]) == revision {
+			template = &revisions[i].Spec.Template
+		}
+	}
+	if template == nil {
+		return api.PodTemplateSpec{}, k8serrors.NewNotFound(revisionResource,
+			fmt.Sprintf("%s/%d", deployment.Name, revision))
+	}
+
+	result := *template
+	result.Labels = make(map[string]string)
+	for key, value := range template.Labels {
+		if key != extensions.DefaultDeploymentUniqueLabelKey {
+			result.Labels[key] = value
+		}
+	}
+	return result, nil
+}
+
+// diffPodTemplates returns differences of all fields of given pod templates.
+func diffPodTemplates(from, to api.PodTemplateSpec) ([]FieldDiff, error) {
+	fromValue, err := toJSONValue(from)
+	if err != nil {
+		return nil, err
+	}
+	toValue, err := toJSONValue(to)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]FieldDiff, 0)
+	diffValues("", fromValue, toValue, &fields)
+	return fields, nil
+}
+
+// toJSONValue returns generic JSON representation of given object, i.e. maps, lists and scalars.
+func toJSONValue(object interface{}) (interface{}, error) {
+	raw, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	err = json.Unmarshal(raw, &value)
+	return value, err
+}
+
+// diffValues appends differences of given JSON values at given path to fields.
+func diffValues(path string, from, to interface{}, fields *[]FieldDiff) {
+	if reflect.DeepEqual(from, to) {
+		return
+	}
+
+	fromMap, fromIsMap := from.(map[string]interface{})
+	toMap, toIsMap := to.(map[string]interface{})
+	if fromIsMap && toIsMap {
+		keys := make(map[string]bool)
+		for key := range fromMap {
+			keys[key] = true
+		}
+		for key := range toMap {
+			keys[key] = true
+		}
+		sortedKeys := make([]string, 0, len(keys))
+		for key := range keys {
+			sortedKeys = append(sortedKeys, key)
+		}
+		sort.Strings(sortedKeys)
+
+		for _, key := range sortedKeys {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			diffValues(childPath, fromMap[key], toMap[key], fields)
+		}
+		return
+	}
+
+	fromNamed, fromIsNamed := toNamedList(from)
+	toNamed, toIsNamed := toNamedList(to)
+	if fromIsNamed && toIsNamed {
+		for _, name := range getNames(from, to) {
+			diffValues(fmt.Sprintf("%s[%s]", path, name), fromNamed[name], toNamed[name], fields)
+		}
+		return
+	}
+
+	switch {
+	case from == nil:
+		*fields = append(*fields, FieldDiff{Path: path, Type: FieldAdded, To: to})
+	case to == nil:
+		*fields = append(*fields, FieldDiff{Path: path, Type: FieldRemoved, From: from})
+	default:
+		*fields = append(*fields, FieldDiff{Path: path, Type: FieldChanged, From: from, To: to})
+	}
+}
+
+// toNamedList returns elements of given list keyed by their names, if all elements are objects
+// with unique names, e.g. containers, env variables or volumes. Missing lists are empty.
+func toNamedList(value interface{}) (map[string]interface{}, bool) {
+	result := make(map[string]interface{})
+	if value == nil {
+		return result, true
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	for _, item := range list {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		name, ok := object["name"].(string)
+		if _, duplicate := result[name]; !ok || duplicate {
+			return nil, false
+		}
+		result[name] = item
+	}
+	return result, true
+}
+
+// getNames returns names of elements of given named lists in order of their appearance.
+func getNames(lists ...interface{}) []string {
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, list := range lists {
+		items, _ := list.([]interface{})
+		for _, item := range items {
+			name := item.(map[string]interface{})["name"].(string)
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,101 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"reflect"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	api "k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+func TestGetDeploymentRevisionDiff(t *testing.T) {
+	first := api.PodTemplateSpec{
+		ObjectMeta: metaV1.ObjectMeta{
+			Labels: map[string]string{"app": "app", extensions.DefaultDeploymentUniqueLabelKey: "1"},
+		},
+		Spec: api.PodSpec{
+			Containers: []api.Container{{
+				Name:  "app",
+				Image: "app:v1",
+				Env:   []api.EnvVar{{Name: "MODE", Value: "slow"}, {Name: "DEBUG", Value: "1"}},
+			}},
+			Volumes: []api.Volume{{Name: "cache", VolumeSource: api.VolumeSource{
+				EmptyDir: &api.EmptyDirVolumeSource{}}}},
+		},
+	}
+	second := api.PodTemplateSpec{
+		ObjectMeta: metaV1.ObjectMeta{
+			Labels: map[string]string{"app": "app", extensions.DefaultDeploymentUniqueLabelKey: "2"},
+		},
+		Spec: api.PodSpec{
+			Containers: []api.Container{{
+				Name:  "app",
+				Image: "app:v2",
+				Env:   []api.EnvVar{{Name: "MODE", Value: "fast"}},
+				Resources: api.ResourceRequirements{
+					Limits: api.ResourceList{api.ResourceCPU: resource.MustParse("500m")},
+				},
+			}},
+		},
+	}
+	deployment := &extensions.Deployment{
+		ObjectMeta: metaV1.ObjectMeta{Name: "app", Namespace: "prod"},
+		Spec: extensions.DeploymentSpec{
+			Selector: &metaV1.LabelSelector{MatchLabels: map[string]string{"app": "app"}},
+			Template: second,
+		},
+	}
+	revision := func(number string, template api.PodTemplateSpec) *extensions.ReplicaSet {
+		return &extensions.ReplicaSet{
+			ObjectMeta: metaV1.ObjectMeta{Name: "app-" + number, Namespace: "prod",
+				Labels:      map[string]string{"app": "app"},
+				Annotations: map[string]string{RevisionAnnotationKey: number}},
+			Spec: extensions.ReplicaSetSpec{Template: template},
+		}
+	}
+	testClient := fake.NewSimpleClientset(deployment, revision("1", first), revision("2", second))
+
+	expected := &RevisionDiff{FromRevision: 1, ToRevision: 2, Fields: []FieldDiff{
+		{Path: "spec.containers[app].env[MODE].value", Type: FieldChanged, From: "slow", To: "fast"},
+		{Path: "spec.containers[app].env[DEBUG]", Type: FieldRemoved,
+			From: map[string]interface{}{"name": "DEBUG", "value": "1"}},
+		{Path: "spec.containers[app].image", Type: FieldChanged, From: "app:v1", To: "app:v2"},
+		{Path: "spec.containers[app].resources.limits", Type: FieldAdded,
+			To: map[string]interface{}{"cpu": "500m"}},
+		{Path: "spec.volumes[cache]", Type: FieldRemoved,
+			From: map[string]interface{}{"name": "cache", "emptyDir": map[string]interface{}{}}},
+	}}
+
+	actual, err := GetDeploymentRevisionDiff(testClient, "prod", "app", 1, 2)
+	if err != nil || !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GetDeploymentRevisionDiff(1, 2) == \ngot: %#v, %v \nexpected %#v", actual, err, expected)
+	}
+
+	live, err := GetDeploymentRevisionDiff(testClient, "prod", "app", 2, LiveRevision)
+	if err != nil || len(live.Fields) != 0 {
+		t.Errorf("GetDeploymentRevisionDiff(2, live) should return no differences, got: %#v, %v", live,
+			err)
+	}
+
+	if _, err := GetDeploymentRevisionDiff(testClient, "prod", "app", 3, 1); !k8serrors.IsNotFound(err) {
+		t.Errorf("GetDeploymentRevisionDiff(3, 1) should return not found, got: %v", err)
+	}
+}
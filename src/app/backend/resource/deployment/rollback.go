@@ -0,0 +1,120 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"fmt"
+	"log"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+// PreviousRevision is the revision number used to roll a deployment back to its previous revision.
+const PreviousRevision = 0
+
+// Revision is a revision of a deployment kept by one of its replica sets.
+type Revision struct {
+	// Revision number.
+	Revision int64 `json:"revision"`
+
+	// Name of the replica set keeping the revision.
+	ReplicaSetName string `json:"replicaSetName"`
+
+	// Time the revision was rolled out first.
+	CreationTimestamp metaV1.Time `json:"creationTimestamp"`
+
+	// Container images of the revision.
+	Images []string `json:"images"`
+
+	// Cause of the change which created the revision, from the change-cause annotation.
+	ChangeCause string `json:"changeCause"`
+
+	// Desired and ready replicas of the replica set keeping the revision.
+	Replicas      int32 `json:"replicas"`
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// Whether this is the current revision of the deployment.
+	Current bool `json:"current"`
+}
+
+// RollbackSpec is the body of a deployment rollback request.
+type RollbackSpec struct {
+	// Revision to roll back to. PreviousRevision means the revision before the current one.
+	Revision int64 `json:"revision"`
+}
+
+// RollbackDeployment rolls given deployment back to given revision using the rollback
+// subresource. The revision has to be kept by one of replica sets of the deployment.
+func RollbackDeployment(client client.Interface, namespace, name string, revision int64) error {
+	log.Printf("Rolling back %s deployment in %s namespace to revision %d", name, namespace,
+		revision)
+
+	deployment, revisions, err := getDeploymentWithRevisions(client, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	if revision == PreviousRevision {
+		if len(revisions) < 2 {
+			return k8serrors.NewBadRequest(fmt.Sprintf("Deployment %s has no previous revision", name))
+		}
+	} else if _, err := getRevisionTemplate(deployment, revisions, revision); err != nil {
+		return err
+	}
+
+	return client.ExtensionsV1beta1().Deployments(namespace).Rollback(&extensions.DeploymentRollback{
+		Name:       name,
+		RollbackTo: extensions.RollbackConfig{Revision: revision},
+	})
+}
+
+// getRevisionList returns revisions of given deployment kept by given replica sets, from the
+// newest revision.
+func getRevisionList(deployment *extensions.Deployment,
+	replicaSets []extensions.ReplicaSet) []Revision {
+	current := deployment.Annotations[RevisionAnnotationKey]
+	revisions := getDeploymentRevisions(deployment, replicaSets)
+
+	list := make([]Revision, 0, len(revisions))
+	for i := len(revisions) - 1; i >= 0; i-- {
+		replicaSet := revisions[i]
+		images := make([]string, 0)
+		for _, container := range replicaSet.Spec.Template.Spec.Containers {
+			images = append(images, container.Image)
+		}
+		list = append(list, Revision{
+			Revision:          getRevision(replicaSet),
+			ReplicaSetName:    replicaSet.Name,
+			CreationTimestamp: replicaSet.CreationTimestamp,
+			Images:            images,
+			ChangeCause:       replicaSet.Annotations[ChangeCauseAnnotationKey],
+			Replicas:          getReplicaSetReplicas(replicaSet),
+			ReadyReplicas:     replicaSet.Status.ReadyReplicas,
+			Current:           replicaSet.Annotations[RevisionAnnotationKey] == current,
+		})
+	}
+	return list
+}
+
+// getReplicaSetReplicas returns desired replicas of given replica set, which default to 1.
+func getReplicaSetReplicas(replicaSet extensions.ReplicaSet) int32 {
+	if replicaSet.Spec.Replicas == nil {
+		return 1
+	}
+	return *replicaSet.Spec.Replicas
+}
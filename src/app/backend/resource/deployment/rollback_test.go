@@ -0,0 +1,115 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"reflect"
+	"testing"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	api "k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+	core "k8s.io/client-go/testing"
+)
+
+func newRevision(number, image string) *extensions.ReplicaSet {
+	return &extensions.ReplicaSet{
+		ObjectMeta: metaV1.ObjectMeta{Name: "app-" + number, Namespace: "prod",
+			Labels:      map[string]string{"app": "app"},
+			Annotations: map[string]string{RevisionAnnotationKey: number}},
+		Spec: extensions.ReplicaSetSpec{Template: api.PodTemplateSpec{
+			Spec: api.PodSpec{Containers: []api.Container{{Name: "app", Image: image}}},
+		}},
+	}
+}
+
+func TestGetRevisionList(t *testing.T) {
+	deployment := &extensions.Deployment{
+		ObjectMeta: metaV1.ObjectMeta{Name: "app", Namespace: "prod",
+			Annotations: map[string]string{RevisionAnnotationKey: "2"}},
+	}
+	unrevisioned := newRevision("", "app:v0")
+	current := newRevision("2", "app:v2")
+	current.Annotations[ChangeCauseAnnotationKey] = "kubectl set image"
+	replicas := int32(3)
+	current.Spec.Replicas = &replicas
+	current.Status.ReadyReplicas = 2
+
+	actual := getRevisionList(deployment, []extensions.ReplicaSet{*newRevision("1", "app:v1"),
+		*unrevisioned, *current})
+	expected := []Revision{
+		{Revision: 2, ReplicaSetName: "app-2", Images: []string{"app:v2"}, ChangeCause: "kubectl set image",
+			Replicas: 3, ReadyReplicas: 2, Current: true},
+		{Revision: 1, ReplicaSetName: "app-1", Images: []string{"app:v1"}, Replicas: 1},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getRevisionList() == \ngot: %#v, \nexpected %#v", actual, expected)
+	}
+}
+
+func TestRollbackDeployment(t *testing.T) {
+	deployment := &extensions.Deployment{
+		ObjectMeta: metaV1.ObjectMeta{Name: "app", Namespace: "prod"},
+		Spec: extensions.DeploymentSpec{
+			Selector: &metaV1.LabelSelector{MatchLabels: map[string]string{"app": "app"}},
+		},
+	}
+
+	cases := []struct {
+		revisions   []*extensions.ReplicaSet
+		revision    int64
+		expectError bool
+	}{
+		{[]*extensions.ReplicaSet{newRevision("1", "app:v1"), newRevision("2", "app:v2")}, 1, false},
+		{[]*extensions.ReplicaSet{newRevision("1", "app:v1"), newRevision("2", "app:v2")}, 0, false},
+		{[]*extensions.ReplicaSet{newRevision("1", "app:v1"), newRevision("2", "app:v2")}, 3, true},
+		{[]*extensions.ReplicaSet{newRevision("1", "app:v1")}, PreviousRevision, true},
+	}
+	for _, c := range cases {
+		objects := []runtime.Object{deployment}
+		for _, revision := range c.revisions {
+			objects = append(objects, revision)
+		}
+		testClient := fake.NewSimpleClientset(objects...)
+		// The fake object tracker cannot store rollbacks, which are not objects.
+		testClient.PrependReactor("create", "deployments",
+			func(action core.Action) (bool, runtime.Object, error) {
+				return action.GetSubresource() == "rollback", nil, nil
+			})
+
+		err := RollbackDeployment(testClient, "prod", "app", c.revision)
+		if (err != nil) != c.expectError {
+			t.Errorf("RollbackDeployment(%#v) returns error %v, expected error: %v", c.revision, err,
+				c.expectError)
+		}
+
+		var rollback *extensions.DeploymentRollback
+		for _, action := range testClient.Actions() {
+			if action.GetSubresource() == "rollback" {
+				rollback = action.(core.CreateAction).GetObject().(*extensions.DeploymentRollback)
+			}
+		}
+		if c.expectError {
+			if rollback != nil {
+				t.Errorf("RollbackDeployment(%#v) rolls back deployment despite error", c.revision)
+			}
+		} else if rollback == nil || rollback.Name != "app" || rollback.RollbackTo.Revision != c.revision {
+			t.Errorf("RollbackDeployment(%#v) rolls back with %#v, expected revision %d", c.revision,
+				rollback, c.revision)
+		}
+	}
+}
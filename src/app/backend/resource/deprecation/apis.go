@@ -0,0 +1,123 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deprecation
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DeprecatedAPI is a version of an API removed in a Kubernetes release.
+type DeprecatedAPI struct {
+	// Group version of the API, e.g. extensions/v1beta1.
+	GroupVersion string `json:"groupVersion"`
+
+	Kind string `json:"kind"`
+
+	// Plural name of the resource, e.g. ingresses.
+	Resource string `json:"resource"`
+
+	// Kubernetes release which no longer serves the API, e.g. 1.22.
+	RemovedIn string `json:"removedIn"`
+
+	// Group version to migrate to, empty when the API was removed without replacement.
+	Replacement string `json:"replacement"`
+}
+
+// deprecatedAPIs are APIs removed in Kubernetes releases, see
+// https://kubernetes.io/docs/reference/using-api/deprecation-guide.
+var deprecatedAPIs = []DeprecatedAPI{
+	{"extensions/v1beta1", "Deployment", "deployments", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "DaemonSet", "daemonsets", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "ReplicaSet", "replicasets", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "NetworkPolicy", "networkpolicies", "1.16", "networking.k8s.io/v1"},
+	{"extensions/v1beta1", "PodSecurityPolicy", "podsecuritypolicies", "1.16", "policy/v1beta1"},
+	{"extensions/v1beta1", "Ingress", "ingresses", "1.22", "networking.k8s.io/v1"},
+	{"apps/v1beta1", "Deployment", "deployments", "1.16", "apps/v1"},
+	{"apps/v1beta1", "StatefulSet", "statefulsets", "1.16", "apps/v1"},
+	{"apps/v1beta2", "Deployment", "deployments", "1.16", "apps/v1"},
+	{"apps/v1beta2", "DaemonSet", "daemonsets", "1.16", "apps/v1"},
+	{"apps/v1beta2", "ReplicaSet", "replicasets", "1.16", "apps/v1"},
+	{"apps/v1beta2", "StatefulSet", "statefulsets", "1.16", "apps/v1"},
+	{"batch/v2alpha1", "CronJob", "cronjobs", "1.21", "batch/v1"},
+	{"batch/v1beta1", "CronJob", "cronjobs", "1.25", "batch/v1"},
+	{"networking.k8s.io/v1beta1", "Ingress", "ingresses", "1.22", "networking.k8s.io/v1"},
+	{"networking.k8s.io/v1beta1", "IngressClass", "ingressclasses", "1.22", "networking.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1alpha1", "Role", "roles", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1alpha1", "RoleBinding", "rolebindings", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1alpha1", "ClusterRole", "clusterroles", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1alpha1", "ClusterRoleBinding", "clusterrolebindings", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "Role", "roles", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "RoleBinding", "rolebindings", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRole", "clusterroles", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRoleBinding", "clusterrolebindings", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", "MutatingWebhookConfiguration", "mutatingwebhookconfigurations", "1.22", "admissionregistration.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", "ValidatingWebhookConfiguration", "validatingwebhookconfigurations", "1.22", "admissionregistration.k8s.io/v1"},
+	{"apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", "customresourcedefinitions", "1.22", "apiextensions.k8s.io/v1"},
+	{"apiregistration.k8s.io/v1beta1", "APIService", "apiservices", "1.22", "apiregistration.k8s.io/v1"},
+	{"certificates.k8s.io/v1beta1", "CertificateSigningRequest", "certificatesigningrequests", "1.22", "certificates.k8s.io/v1"},
+	{"coordination.k8s.io/v1beta1", "Lease", "leases", "1.22", "coordination.k8s.io/v1"},
+	{"scheduling.k8s.io/v1beta1", "PriorityClass", "priorityclasses", "1.22", "scheduling.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "StorageClass", "storageclasses", "1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "CSIDriver", "csidrivers", "1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "CSINode", "csinodes", "1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "VolumeAttachment", "volumeattachments", "1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "CSIStorageCapacity", "csistoragecapacities", "1.27", "storage.k8s.io/v1"},
+	{"policy/v1beta1", "PodDisruptionBudget", "poddisruptionbudgets", "1.25", "policy/v1"},
+	{"policy/v1beta1", "PodSecurityPolicy", "podsecuritypolicies", "1.25", ""},
+	{"autoscaling/v2beta1", "HorizontalPodAutoscaler", "horizontalpodautoscalers", "1.25", "autoscaling/v2"},
+	{"autoscaling/v2beta2", "HorizontalPodAutoscaler", "horizontalpodautoscalers", "1.26", "autoscaling/v2"},
+	{"discovery.k8s.io/v1beta1", "EndpointSlice", "endpointslices", "1.25", "discovery.k8s.io/v1"},
+	{"events.k8s.io/v1beta1", "Event", "events", "1.25", "events.k8s.io/v1"},
+	{"node.k8s.io/v1beta1", "RuntimeClass", "runtimeclasses", "1.25", "node.k8s.io/v1"},
+}
+
+// IsRemovedBy returns true if the API is no longer served in given Kubernetes release, e.g. 1.22.
+func (self DeprecatedAPI) IsRemovedBy(release string) bool {
+	return compareReleases(self.RemovedIn, release) <= 0
+}
+
+// findDeprecatedAPI returns deprecated API of given group version and kind, or nil when the API is
+// not deprecated.
+func findDeprecatedAPI(groupVersion, kind string) *DeprecatedAPI {
+	for i := range deprecatedAPIs {
+		if deprecatedAPIs[i].GroupVersion == groupVersion && deprecatedAPIs[i].Kind == kind {
+			return &deprecatedAPIs[i]
+		}
+	}
+	return nil
+}
+
+// getGroup returns group of given group version, e.g. apps of apps/v1beta1.
+func getGroup(groupVersion string) string {
+	if i := strings.Index(groupVersion, "/"); i >= 0 {
+		return groupVersion[:i]
+	}
+	return ""
+}
+
+// compareReleases compares Kubernetes releases in major.minor format, returns negative number when
+// a is older than b, positive when newer, and 0 when they are the same.
+func compareReleases(a, b string) int {
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNumber, _ := strconv.Atoi(aParts[i])
+		bNumber, _ := strconv.Atoi(bParts[i])
+		if aNumber != bNumber {
+			return aNumber - bNumber
+		}
+	}
+	return len(aParts) - len(bParts)
+}
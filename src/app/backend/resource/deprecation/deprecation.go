@@ -0,0 +1,326 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deprecation
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+)
+
+// Sources of API versions objects were written with.
+const (
+	// SourceLastApplied is the API version in the last applied configuration annotation set by
+	// kubectl apply, i.e. the version used in the manifest.
+	SourceLastApplied = "last-applied"
+
+	// SourceManagedFields is the API version managers used to write fields of the object.
+	SourceManagedFields = "managed-fields"
+)
+
+const (
+	lastAppliedAnnotationKey = "kubectl.kubernetes.io/last-applied-configuration"
+	helmReleaseAnnotationKey = "meta.helm.sh/release-name"
+)
+
+// DeprecationReport lists objects written with API versions removed in newer Kubernetes releases,
+// to plan upgrades of the cluster.
+type DeprecationReport struct {
+	// Objects whose manifests or managers use deprecated APIs, removed soonest first.
+	Usages []DeprecatedAPIUsage `json:"usages"`
+
+	// Deprecated APIs still served by the cluster.
+	ServedAPIs []DeprecatedAPI `json:"servedAPIs"`
+
+	// Resources that could not be scanned, e.g. because the user cannot list them.
+	Warnings []string `json:"warnings"`
+}
+
+// DeprecatedAPIUsage is an object written with a deprecated API version.
+type DeprecatedAPIUsage struct {
+	DeprecatedAPI
+
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+
+	// Where the deprecated version was found, last-applied or managed-fields.
+	Sources []string `json:"sources"`
+
+	// Managers writing the object with the deprecated version, e.g. kubectl or helm.
+	Managers []string `json:"managers"`
+
+	// Controller of the object in Kind/Name format, if any.
+	Owner string `json:"owner,omitempty"`
+
+	// Helm release the object belongs to, if any.
+	HelmRelease string `json:"helmRelease,omitempty"`
+}
+
+// rawObjectList is a list of objects of any kind with fields needed to find API versions used to
+// write them.
+type rawObjectList struct {
+	Items []rawObject `json:"items"`
+}
+
+type rawObject struct {
+	Metadata struct {
+		Name            string                  `json:"name"`
+		Namespace       string                  `json:"namespace"`
+		Annotations     map[string]string       `json:"annotations"`
+		OwnerReferences []metaV1.OwnerReference `json:"ownerReferences"`
+		ManagedFields   []struct {
+			Manager    string `json:"manager"`
+			APIVersion string `json:"apiVersion"`
+		} `json:"managedFields"`
+	} `json:"metadata"`
+}
+
+// scannedResource is a resource with deprecated versions. Objects are stored once whatever group
+// serves them, so the resource is listed in the first of its groups still served.
+type scannedResource struct {
+	resource string
+	kind     string
+	groups   []string
+}
+
+// unscannedResources are resources with deprecated versions that are too many to list, e.g.
+// events, or that are written only by controllers.
+var unscannedResources = map[string]bool{"events": true}
+
+// GetDeprecationReport scans objects of resources with deprecated versions and returns those
+// written with deprecated versions. The cluster stores objects independently of the version used to
+// write them, so versions are read from last applied configuration and managed fields.
+func GetDeprecationReport(client client.Interface) (*DeprecationReport, error) {
+	log.Print("Getting deprecated API usage report")
+
+	groups, err := client.Discovery().ServerGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	servedVersions := make(map[string][]string)
+	for _, group := range groups.Groups {
+		// Preferred version goes first, as it's the one most likely to serve the resource.
+		servedVersions[group.Name] = []string{group.PreferredVersion.GroupVersion}
+		for _, version := range group.Versions {
+			if version.GroupVersion != group.PreferredVersion.GroupVersion {
+				servedVersions[group.Name] = append(servedVersions[group.Name], version.GroupVersion)
+			}
+		}
+	}
+
+	report := &DeprecationReport{
+		Usages:     make([]DeprecatedAPIUsage, 0),
+		ServedAPIs: make([]DeprecatedAPI, 0),
+		Warnings:   make([]string, 0),
+	}
+
+	report.ServedAPIs, err = getServedAPIs(client, servedVersions)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, resource := range getScannedResources() {
+		objects, err := listObjects(client, servedVersions, resource)
+		if k8serrors.IsForbidden(err) {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("Cannot list %s: %s",
+				resource.resource, err.Error()))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		report.Usages = append(report.Usages, findUsages(resource.kind, objects)...)
+	}
+
+	sort.Sort(usagesByRemoval(report.Usages))
+	return report, nil
+}
+
+// getScannedResources returns resources with deprecated versions, each one once with all of its
+// groups.
+func getScannedResources() []scannedResource {
+	indexes := make(map[string]int)
+	result := make([]scannedResource, 0)
+	for _, api := range deprecatedAPIs {
+		if unscannedResources[api.Resource] {
+			continue
+		}
+		key := api.Resource + "/" + api.Kind
+		i, ok := indexes[key]
+		if !ok {
+			i = len(result)
+			indexes[key] = i
+			result = append(result, scannedResource{resource: api.Resource, kind: api.Kind})
+		}
+		if group := getGroup(api.GroupVersion); !containsString(result[i].groups, group) {
+			result[i].groups = append(result[i].groups, group)
+		}
+	}
+	return result
+}
+
+// getServedAPIs returns deprecated APIs served by the cluster.
+func getServedAPIs(client client.Interface, servedVersions map[string][]string) ([]DeprecatedAPI, error) {
+	served := make(map[string]bool)
+	for _, versions := range servedVersions {
+		for _, version := range versions {
+			served[version] = true
+		}
+	}
+
+	result := make([]DeprecatedAPI, 0)
+	resources := make(map[string]map[string]bool)
+	for _, api := range deprecatedAPIs {
+		if !served[api.GroupVersion] {
+			continue
+		}
+
+		if _, ok := resources[api.GroupVersion]; !ok {
+			list, err := client.Discovery().ServerResourcesForGroupVersion(api.GroupVersion)
+			if err != nil {
+				return nil, err
+			}
+			resources[api.GroupVersion] = make(map[string]bool)
+			for _, resource := range list.APIResources {
+				resources[api.GroupVersion][resource.Name] = true
+			}
+		}
+
+		if resources[api.GroupVersion][api.Resource] {
+			result = append(result, api)
+		}
+	}
+	return result, nil
+}
+
+// listObjects lists objects of given resource in all namespaces using the first served version
+// of its groups that serves the resource. Nothing is returned when none of the versions serves it.
+func listObjects(client client.Interface, servedVersions map[string][]string,
+	resource scannedResource) ([]rawObject, error) {
+	for _, group := range resource.groups {
+		for _, version := range servedVersions[group] {
+			raw, err := common.JSONRequest(client.CoreV1().RESTClient().Get().
+				AbsPath("/apis/" + version + "/" + resource.resource)).Do().Raw()
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			list := &rawObjectList{}
+			if err := json.Unmarshal(raw, list); err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		}
+	}
+	return []rawObject{}, nil
+}
+
+// findUsages returns usages of deprecated versions of given kind by given objects.
+func findUsages(kind string, objects []rawObject) []DeprecatedAPIUsage {
+	result := make([]DeprecatedAPIUsage, 0)
+	for _, object := range objects {
+		usages := make(map[string]*DeprecatedAPIUsage)
+		versions := make([]string, 0)
+
+		addUsage := func(groupVersion, source, manager string) {
+			api := findDeprecatedAPI(groupVersion, kind)
+			if api == nil {
+				return
+			}
+			usage, ok := usages[groupVersion]
+			if !ok {
+				usage = newUsage(api, object)
+				usages[groupVersion] = usage
+				versions = append(versions, groupVersion)
+			}
+			if !containsString(usage.Sources, source) {
+				usage.Sources = append(usage.Sources, source)
+			}
+			if manager != "" && !containsString(usage.Managers, manager) {
+				usage.Managers = append(usage.Managers, manager)
+			}
+		}
+
+		if lastApplied, ok := object.Metadata.Annotations[lastAppliedAnnotationKey]; ok {
+			manifest := struct {
+				APIVersion string `json:"apiVersion"`
+			}{}
+			if err := json.Unmarshal([]byte(lastApplied), &manifest); err == nil {
+				addUsage(manifest.APIVersion, SourceLastApplied, "")
+			}
+		}
+		for _, field := range object.Metadata.ManagedFields {
+			addUsage(field.APIVersion, SourceManagedFields, field.Manager)
+		}
+
+		for _, version := range versions {
+			result = append(result, *usages[version])
+		}
+	}
+	return result
+}
+
+func newUsage(api *DeprecatedAPI, object rawObject) *DeprecatedAPIUsage {
+	usage := &DeprecatedAPIUsage{
+		DeprecatedAPI: *api,
+		Namespace:     object.Metadata.Namespace,
+		Name:          object.Metadata.Name,
+		Sources:       make([]string, 0),
+		Managers:      make([]string, 0),
+		HelmRelease:   object.Metadata.Annotations[helmReleaseAnnotationKey],
+	}
+	for _, reference := range object.Metadata.OwnerReferences {
+		if reference.Controller != nil && *reference.Controller {
+			usage.Owner = reference.Kind + "/" + reference.Name
+		}
+	}
+	return usage
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+type usagesByRemoval []DeprecatedAPIUsage
+
+func (self usagesByRemoval) Len() int      { return len(self) }
+func (self usagesByRemoval) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+func (self usagesByRemoval) Less(i, j int) bool {
+	if c := compareReleases(self[i].RemovedIn, self[j].RemovedIn); c != 0 {
+		return c < 0
+	}
+	if self[i].Kind != self[j].Kind {
+		return self[i].Kind < self[j].Kind
+	}
+	if self[i].Namespace != self[j].Namespace {
+		return self[i].Namespace < self[j].Namespace
+	}
+	return self[i].Name < self[j].Name
+}
@@ -0,0 +1,209 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deprecation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestFindUsages(t *testing.T) {
+	objects := &rawObjectList{}
+	err := json.Unmarshal([]byte(`{"items": [
+		{"metadata": {"name": "web", "namespace": "default", "annotations": {
+			"kubectl.kubernetes.io/last-applied-configuration": "{\"apiVersion\":\"extensions/v1beta1\"}",
+			"meta.helm.sh/release-name": "shop"},
+			"ownerReferences": [{"kind": "App", "name": "shop", "controller": true}],
+			"managedFields": [
+				{"manager": "kubectl", "apiVersion": "extensions/v1beta1"},
+				{"manager": "nginx", "apiVersion": "networking.k8s.io/v1"},
+				{"manager": "helm", "apiVersion": "networking.k8s.io/v1beta1"}]}},
+		{"metadata": {"name": "api", "namespace": "prod", "annotations": {
+			"kubectl.kubernetes.io/last-applied-configuration": "{\"apiVersion\":\"networking.k8s.io/v1\"}"}}}
+	]}`), objects)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := findUsages("Ingress", objects.Items)
+	expected := []DeprecatedAPIUsage{
+		{
+			DeprecatedAPI: *findDeprecatedAPI("extensions/v1beta1", "Ingress"),
+			Namespace:     "default",
+			Name:          "web",
+			Sources:       []string{SourceLastApplied, SourceManagedFields},
+			Managers:      []string{"kubectl"},
+			Owner:         "App/shop",
+			HelmRelease:   "shop",
+		},
+		{
+			DeprecatedAPI: *findDeprecatedAPI("networking.k8s.io/v1beta1", "Ingress"),
+			Namespace:     "default",
+			Name:          "web",
+			Sources:       []string{SourceManagedFields},
+			Managers:      []string{"helm"},
+			Owner:         "App/shop",
+			HelmRelease:   "shop",
+		},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("findUsages(Ingress) == \ngot %#v, \nexpected %#v", actual, expected)
+	}
+}
+
+func TestFindDeprecatedAPI(t *testing.T) {
+	cases := []struct {
+		groupVersion, kind string
+		expected           string
+	}{
+		{"extensions/v1beta1", "Ingress", "1.22"},
+		{"batch/v2alpha1", "CronJob", "1.21"},
+		{"networking.k8s.io/v1", "Ingress", ""},
+		{"extensions/v1beta1", "Foo", ""},
+	}
+	for _, c := range cases {
+		actual := ""
+		if api := findDeprecatedAPI(c.groupVersion, c.kind); api != nil {
+			actual = api.RemovedIn
+		}
+		if actual != c.expected {
+			t.Errorf("findDeprecatedAPI(%#v, %#v) == \ngot %#v, \nexpected %#v", c.groupVersion, c.kind,
+				actual, c.expected)
+		}
+	}
+}
+
+func TestCompareReleases(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.16", "1.22", -1},
+		{"1.9", "1.16", -1},
+		{"1.25", "1.22", 1},
+		{"1.22", "1.22", 0},
+	}
+	for _, c := range cases {
+		actual := compareReleases(c.a, c.b)
+		if (actual < 0 && c.expected >= 0) || (actual > 0 && c.expected <= 0) ||
+			(actual == 0 && c.expected != 0) {
+			t.Errorf("compareReleases(%#v, %#v) == \ngot %#v, \nexpected sign of %#v", c.a, c.b, actual,
+				c.expected)
+		}
+	}
+}
+
+func TestGetScannedResources(t *testing.T) {
+	resources := getScannedResources()
+	seen := make(map[string]scannedResource)
+	for _, resource := range resources {
+		if _, ok := seen[resource.resource]; ok {
+			t.Errorf("getScannedResources() == \ngot duplicate %#v", resource)
+		}
+		seen[resource.resource] = resource
+	}
+
+	expected := scannedResource{resource: "ingresses", kind: "Ingress",
+		groups: []string{"extensions", "networking.k8s.io"}}
+	if !reflect.DeepEqual(seen["ingresses"], expected) {
+		t.Errorf("getScannedResources() == \ngot %#v, \nexpected to contain %#v", resources, expected)
+	}
+	if _, ok := seen["events"]; ok {
+		t.Errorf("getScannedResources() == \ngot %#v, \nexpected events not to be scanned", resources)
+	}
+}
+
+func TestIsRemovedBy(t *testing.T) {
+	api := DeprecatedAPI{GroupVersion: "extensions/v1beta1", Kind: "Ingress", RemovedIn: "1.22"}
+	cases := []struct {
+		release  string
+		expected bool
+	}{
+		{"1.21", false},
+		{"1.22", true},
+		{"1.25", true},
+	}
+	for _, c := range cases {
+		actual := api.IsRemovedBy(c.release)
+		if actual != c.expected {
+			t.Errorf("IsRemovedBy(%#v) == \ngot %#v, \nexpected %#v", c.release, actual, c.expected)
+		}
+	}
+}
+
+func TestGetDeprecationReport(t *testing.T) {
+	responses := map[string]string{
+		"/api": `{"kind": "APIVersions", "versions": ["v1"]}`,
+		"/apis": `{"kind": "APIGroupList", "groups": [
+			{"name": "extensions", "versions": [{"groupVersion": "extensions/v1beta1", "version": "v1beta1"}],
+				"preferredVersion": {"groupVersion": "extensions/v1beta1", "version": "v1beta1"}},
+			{"name": "apps", "versions": [{"groupVersion": "apps/v1", "version": "v1"}],
+				"preferredVersion": {"groupVersion": "apps/v1", "version": "v1"}},
+			{"name": "rbac.authorization.k8s.io",
+				"versions": [{"groupVersion": "rbac.authorization.k8s.io/v1", "version": "v1"}],
+				"preferredVersion": {"groupVersion": "rbac.authorization.k8s.io/v1", "version": "v1"}}]}`,
+		"/apis/extensions/v1beta1": `{"kind": "APIResourceList", "groupVersion": "extensions/v1beta1",
+			"resources": [{"name": "deployments", "namespaced": true, "kind": "Deployment"}]}`,
+		"/apis/extensions/v1beta1/deployments": `{"items": [{"metadata": {"name": "web",
+			"namespace": "default", "annotations": {"kubectl.kubernetes.io/last-applied-configuration":
+			"{\"apiVersion\": \"extensions/v1beta1\"}"}}}]}`,
+		"/apis/apps/v1/deployments": `{"items": [{"metadata": {"name": "web",
+			"namespace": "default", "annotations": {"kubectl.kubernetes.io/last-applied-configuration":
+			"{\"apiVersion\": \"extensions/v1beta1\"}"}}}]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/apis/rbac.authorization.k8s.io/v1/roles" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"kind": "Status", "apiVersion": "v1", "status": "Failure",
+				"reason": "Forbidden", "code": 403}`)
+			return
+		}
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, response)
+	}))
+	defer server.Close()
+
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("Cannot create client: %s", err)
+	}
+	report, err := GetDeprecationReport(client)
+	if err != nil {
+		t.Fatalf("GetDeprecationReport() returns error: %s", err)
+	}
+
+	// Deployments are served by both groups, but they are listed once.
+	if len(report.Usages) != 1 || report.Usages[0].Name != "web" ||
+		report.Usages[0].GroupVersion != "extensions/v1beta1" {
+		t.Errorf("GetDeprecationReport() == \ngot usages %#v, \nexpected one of web deployment",
+			report.Usages)
+	}
+	if len(report.Warnings) != 1 {
+		t.Errorf("GetDeprecationReport() == \ngot warnings %#v, \nexpected one of roles", report.Warnings)
+	}
+}
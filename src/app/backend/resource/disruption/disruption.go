@@ -0,0 +1,288 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disruption
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+// DisruptionType is a type of disruption of pods caused by the platform.
+type DisruptionType string
+
+// List of all disruption types.
+const (
+	// Pod was evicted, e.g. by the kubelet because of node pressure or by the node controller.
+	DisruptionEviction DisruptionType = "Eviction"
+
+	// Pod was preempted to make room for other pods.
+	DisruptionPreemption DisruptionType = "Preemption"
+
+	// Container was killed because it exceeded its memory limit.
+	DisruptionOOMKill DisruptionType = "OOMKill"
+)
+
+// Reason of pods evicted by the kubelet and of their events.
+const evictedReason = "Evicted"
+
+// Reason of containers killed because of exceeded memory limit.
+const oomKilledReason = "OOMKilled"
+
+// eventDisruptionTypes maps reasons of events to disruptions they report.
+var eventDisruptionTypes = map[string]DisruptionType{
+	evictedReason:          DisruptionEviction,
+	"TaintManagerEviction": DisruptionEviction,
+	"Preempted":            DisruptionPreemption,
+	"Preempting":           DisruptionPreemption,
+}
+
+// Disruption is a disruption of a pod or one of its containers.
+type Disruption struct {
+	Type DisruptionType `json:"type"`
+
+	// Reason of the event or of the pod status reporting the disruption.
+	Reason string `json:"reason"`
+
+	PodName       string `json:"podName"`
+	ContainerName string `json:"containerName,omitempty"`
+	NodeName      string `json:"nodeName,omitempty"`
+	Message       string `json:"message"`
+
+	// Number of times the disruption was reported.
+	Count int32 `json:"count"`
+
+	FirstSeen metaV1.Time `json:"firstSeen"`
+	LastSeen  metaV1.Time `json:"lastSeen"`
+}
+
+// DisruptionList is a list of disruptions, the most recent first.
+type DisruptionList struct {
+	ListMeta    api.ListMeta `json:"listMeta"`
+	Disruptions []Disruption `json:"disruptions"`
+}
+
+// GetNamespaceDisruptions returns recent disruptions of pods in given namespace. Disruptions are
+// found in events and statuses of pods, so only those still kept by the API server are returned.
+func GetNamespaceDisruptions(client client.Interface, namespace string) (*DisruptionList, error) {
+	log.Printf("Getting disruptions of pods in %s namespace", namespace)
+	return getDisruptions(client, namespace, labels.Everything(), nil)
+}
+
+// GetWorkloadDisruptions returns recent disruptions of pods of given workload. Existing pods are
+// matched by selector of the workload. Pods evicted or preempted are often already deleted, so
+// their events are matched by names the workload gives to its pods.
+func GetWorkloadDisruptions(client client.Interface, kind, namespace, name string) (
+	*DisruptionList, error) {
+	log.Printf("Getting disruptions of pods of %s %s in %s namespace", kind, name, namespace)
+
+	selector, err := getWorkloadSelector(client, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return getDisruptions(client, namespace, selector, getPodNamePattern(kind, name))
+}
+
+func getDisruptions(client client.Interface, namespace string, selector labels.Selector,
+	podNamePattern *regexp.Regexp) (*DisruptionList, error) {
+	nsQuery := common.NewSameNamespaceQuery(namespace)
+	channels := &common.ResourceChannels{
+		PodList: common.GetPodListChannelWithOptions(client, nsQuery,
+			metaV1.ListOptions{LabelSelector: selector.String()}, 1),
+		EventList: common.GetEventListChannel(client, nsQuery, 1),
+	}
+
+	pods := <-channels.PodList.List
+	if err := <-channels.PodList.Error; err != nil {
+		return nil, err
+	}
+
+	events := <-channels.EventList.List
+	if err := <-channels.EventList.Error; err != nil {
+		return nil, err
+	}
+
+	disruptions := toDisruptions(pods.Items, events.Items, podNamePattern)
+	return &DisruptionList{
+		ListMeta:    api.ListMeta{TotalItems: len(disruptions)},
+		Disruptions: disruptions,
+	}, nil
+}
+
+// toDisruptions returns disruptions reported by given events of pods and by statuses of given pods.
+// Events of pods other than the given ones are included only if their names match given pattern,
+// or if the pattern is nil.
+func toDisruptions(pods []v1.Pod, events []v1.Event, podNamePattern *regexp.Regexp) []Disruption {
+	disruptions := make([]Disruption, 0)
+	podNames := make(map[string]bool)
+	for _, pod := range pods {
+		podNames[pod.Name] = true
+	}
+
+	evicted := make(map[string]bool)
+	for _, event := range events {
+		disruptionType, ok := eventDisruptionTypes[event.Reason]
+		if !ok || event.InvolvedObject.Kind != "Pod" {
+			continue
+		}
+		if podNamePattern != nil && !podNames[event.InvolvedObject.Name] &&
+			!podNamePattern.MatchString(event.InvolvedObject.Name) {
+			continue
+		}
+		if disruptionType == DisruptionEviction {
+			evicted[event.InvolvedObject.Name] = true
+		}
+		disruptions = append(disruptions, Disruption{
+			Type:      disruptionType,
+			Reason:    event.Reason,
+			PodName:   event.InvolvedObject.Name,
+			NodeName:  event.Source.Host,
+			Message:   event.Message,
+			Count:     event.Count,
+			FirstSeen: event.FirstTimestamp,
+			LastSeen:  event.LastTimestamp,
+		})
+	}
+
+	for _, pod := range pods {
+		if pod.Status.Reason == evictedReason && !evicted[pod.Name] {
+			disruptions = append(disruptions, Disruption{
+				Type:      DisruptionEviction,
+				Reason:    pod.Status.Reason,
+				PodName:   pod.Name,
+				NodeName:  pod.Spec.NodeName,
+				Message:   pod.Status.Message,
+				Count:     1,
+				FirstSeen: getEvictionTime(pod),
+				LastSeen:  getEvictionTime(pod),
+			})
+		}
+
+		for _, status := range pod.Status.ContainerStatuses {
+			for _, terminated := range []*v1.ContainerStateTerminated{status.State.Terminated,
+				status.LastTerminationState.Terminated} {
+				if terminated == nil || terminated.Reason != oomKilledReason {
+					continue
+				}
+				disruptions = append(disruptions, Disruption{
+					Type:          DisruptionOOMKill,
+					Reason:        terminated.Reason,
+					PodName:       pod.Name,
+					ContainerName: status.Name,
+					NodeName:      pod.Spec.NodeName,
+					Message: fmt.Sprintf("Container exceeded its memory limit and was killed, "+
+						"restarted %d times", status.RestartCount),
+					Count:     1,
+					FirstSeen: terminated.FinishedAt,
+					LastSeen:  terminated.FinishedAt,
+				})
+			}
+		}
+	}
+
+	sort.Stable(disruptionsByLastSeen(disruptions))
+	return disruptions
+}
+
+// disruptionsByLastSeen sorts disruptions from the most recent one.
+type disruptionsByLastSeen []Disruption
+
+func (self disruptionsByLastSeen) Len() int      { return len(self) }
+func (self disruptionsByLastSeen) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+func (self disruptionsByLastSeen) Less(i, j int) bool {
+	return self[j].LastSeen.Before(self[i].LastSeen)
+}
+
+// getEvictionTime returns time when given evicted pod stopped, which is the latest termination
+// time of its containers.
+func getEvictionTime(pod v1.Pod) metaV1.Time {
+	var latest metaV1.Time
+	for _, status := range pod.Status.ContainerStatuses {
+		if terminated := status.State.Terminated; terminated != nil &&
+			latest.Before(terminated.FinishedAt) {
+			latest = terminated.FinishedAt
+		}
+	}
+	return latest
+}
+
+// getPodNamePattern returns pattern of names of pods created by given workload. Stateful set pods
+// are named by ordinal, deployment pods by hashes of replica set and pod, other pods by a random
+// suffix.
+func getPodNamePattern(kind, name string) *regexp.Regexp {
+	suffix := `-[a-z0-9]{5}$`
+	switch api.ResourceKind(kind) {
+	case api.ResourceKindStatefulSet:
+		suffix = `-[0-9]+$`
+	case api.ResourceKindDeployment:
+		suffix = `-[a-z0-9]+-[a-z0-9]{5}$`
+	}
+	return regexp.MustCompile("^" + regexp.QuoteMeta(name) + suffix)
+}
+
+// getWorkloadSelector returns selector of pods of given workload.
+func getWorkloadSelector(client client.Interface, kind, namespace, name string) (labels.Selector,
+	error) {
+	var selector *metaV1.LabelSelector
+	switch api.ResourceKind(kind) {
+	case api.ResourceKindDeployment:
+		deployment, err := client.ExtensionsV1beta1().Deployments(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = deployment.Spec.Selector
+	case api.ResourceKindReplicaSet:
+		replicaSet, err := client.ExtensionsV1beta1().ReplicaSets(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = replicaSet.Spec.Selector
+	case api.ResourceKindStatefulSet:
+		statefulSet, err := client.AppsV1beta1().StatefulSets(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = statefulSet.Spec.Selector
+	case api.ResourceKindDaemonSet:
+		daemonSet, err := client.ExtensionsV1beta1().DaemonSets(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = daemonSet.Spec.Selector
+	case api.ResourceKindJob:
+		job, err := client.BatchV1().Jobs(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = job.Spec.Selector
+	default:
+		return nil, k8serrors.NewBadRequest(fmt.Sprintf("Disruptions are not supported for %s resources",
+			kind))
+	}
+
+	if selector == nil {
+		return nil, k8serrors.NewBadRequest(fmt.Sprintf("%s %s has no pod selector", kind, name))
+	}
+	return metaV1.LabelSelectorAsSelector(selector)
+}
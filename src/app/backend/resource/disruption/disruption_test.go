@@ -0,0 +1,128 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disruption
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+func TestGetWorkloadDisruptions(t *testing.T) {
+	at := func(minute int) metaV1.Time {
+		return metaV1.NewTime(time.Date(2017, 5, 5, 10, minute, 0, 0, time.UTC))
+	}
+	labels := map[string]string{"app": "web"}
+	podEvent := func(name, pod, reason string, minute int) *v1.Event {
+		return &v1.Event{
+			ObjectMeta:     metaV1.ObjectMeta{Name: name, Namespace: "prod"},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: pod},
+			Reason:         reason,
+			Message:        reason + " " + pod,
+			Source:         v1.EventSource{Host: "node-1"},
+			Count:          1,
+			FirstTimestamp: at(minute),
+			LastTimestamp:  at(minute),
+		}
+	}
+
+	testClient := fake.NewSimpleClientset(
+		&extensions.Deployment{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "prod"},
+			Spec: extensions.DeploymentSpec{
+				Selector: &metaV1.LabelSelector{MatchLabels: labels},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web-1", Namespace: "prod", Labels: labels},
+			Spec:       v1.PodSpec{NodeName: "node-1"},
+			Status: v1.PodStatus{
+				Phase:   v1.PodFailed,
+				Reason:  "Evicted",
+				Message: "The node was low on resource: memory.",
+				ContainerStatuses: []v1.ContainerStatus{{Name: "web", State: v1.ContainerState{
+					Terminated: &v1.ContainerStateTerminated{FinishedAt: at(5)},
+				}}},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web-2", Namespace: "prod", Labels: labels},
+			Spec:       v1.PodSpec{NodeName: "node-2"},
+			Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{
+				Name:         "web",
+				RestartCount: 3,
+				LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+					Reason: "OOMKilled", FinishedAt: at(10),
+				}},
+			}}},
+		},
+		&v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web-3", Namespace: "prod", Labels: labels},
+		},
+		&v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "db-1", Namespace: "prod",
+				Labels: map[string]string{"app": "db"}},
+		},
+		podEvent("event-1", "web-3", "Preempted", 20),
+		podEvent("event-2", "db-1", "Evicted", 30),
+		podEvent("event-3", "web-3", "Killing", 40),
+		// Pod already deleted after it was evicted, and pod of another deployment.
+		podEvent("event-4", "web-2725431914-x8k2p", "Evicted", 50),
+		podEvent("event-5", "web-api-2725431914-x8k2p", "Evicted", 60),
+	)
+
+	expected := &DisruptionList{
+		ListMeta: api.ListMeta{TotalItems: 4},
+		Disruptions: []Disruption{
+			{Type: DisruptionEviction, Reason: "Evicted", PodName: "web-2725431914-x8k2p",
+				NodeName: "node-1", Message: "Evicted web-2725431914-x8k2p", Count: 1, FirstSeen: at(50),
+				LastSeen: at(50)},
+			{Type: DisruptionPreemption, Reason: "Preempted", PodName: "web-3", NodeName: "node-1",
+				Message: "Preempted web-3", Count: 1, FirstSeen: at(20), LastSeen: at(20)},
+			{Type: DisruptionOOMKill, Reason: "OOMKilled", PodName: "web-2", ContainerName: "web",
+				NodeName: "node-2",
+				Message:  "Container exceeded its memory limit and was killed, restarted 3 times",
+				Count:    1, FirstSeen: at(10), LastSeen: at(10)},
+			{Type: DisruptionEviction, Reason: "Evicted", PodName: "web-1", NodeName: "node-1",
+				Message: "The node was low on resource: memory.", Count: 1, FirstSeen: at(5),
+				LastSeen: at(5)},
+		},
+	}
+
+	actual, err := GetWorkloadDisruptions(testClient, api.ResourceKindDeployment, "prod", "web")
+	if err != nil || !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GetWorkloadDisruptions(deployment, prod, web) == \ngot: %#v, %v \nexpected %#v",
+			actual, err, expected)
+	}
+
+	namespaced, err := GetNamespaceDisruptions(testClient, "prod")
+	if err != nil || len(namespaced.Disruptions) != 6 ||
+		namespaced.Disruptions[0].PodName != "web-api-2725431914-x8k2p" {
+		t.Errorf("GetNamespaceDisruptions(prod) should include disruptions of all pods, got: %#v, %v",
+			namespaced, err)
+	}
+
+	_, err = GetWorkloadDisruptions(testClient, api.ResourceKindService, "prod", "web")
+	if !k8serrors.IsBadRequest(err) {
+		t.Errorf("GetWorkloadDisruptions(service) should return bad request, got: %v", err)
+	}
+}
@@ -27,7 +27,8 @@ import (
 	"k8s.io/client-go/pkg/api/v1"
 )
 
-// GetEvents gets events associated to resource with given name.
+// GetEvents gets events associated to resource with given name. Events of cluster scoped
+// resources, e.g. nodes, are recorded in any namespace, so they are got with v1.NamespaceAll.
 func GetEvents(client client.Interface, namespace, resourceName string) ([]v1.Event, error) {
 
 	fieldSelector, err := fields.ParseSelector("involvedObject.name=" + resourceName)
@@ -36,10 +37,14 @@ func GetEvents(client client.Interface, namespace, resourceName string) ([]v1.Ev
 		return nil, err
 	}
 
+	nsQuery := common.NewSameNamespaceQuery(namespace)
+	if namespace == v1.NamespaceAll {
+		nsQuery = common.NewNamespaceQuery(nil)
+	}
 	channels := &common.ResourceChannels{
 		EventList: common.GetEventListChannelWithOptions(
 			client,
-			common.NewSameNamespaceQuery(namespace),
+			nsQuery,
 			metaV1.ListOptions{
 				LabelSelector: labels.Everything().String(),
 				FieldSelector: fieldSelector.String(),
@@ -208,7 +213,9 @@ func CreateEventList(events []v1.Event, dsQuery *dataselect.DataSelectQuery) com
 		ListMeta: api.ListMeta{TotalItems: len(events)},
 	}
 
-	events = fromCells(dataselect.GenericDataSelect(toCells(events), dsQuery))
+	eventCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(events), dsQuery)
+	events = fromCells(eventCells)
+	eventList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, eventList.ListMeta.TotalItems)
 
 	for _, event := range events {
 		eventDetail := ToEvent(event)
@@ -230,6 +237,8 @@ func (self EventCell) GetProperty(name dataselect.PropertyName) dataselect.Compa
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.UIDProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.UID)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
@@ -154,7 +154,7 @@ func TestToEventList(t *testing.T) {
 			},
 			"namespace-1",
 			common.EventList{
-				ListMeta: api.ListMeta{TotalItems: 2},
+				ListMeta: api.ListMeta{TotalItems: 2, TotalBeforeFilter: 2},
 				Events: []common.Event{
 					{
 						ObjectMeta: api.ObjectMeta{Name: "event-1"},
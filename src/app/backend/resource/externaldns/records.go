@@ -0,0 +1,375 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package externaldns
+
+import (
+	"log"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/integration/dnsresolver"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+// Annotations external-dns reads from services and ingresses.
+const (
+	hostnameAnnotation              = "external-dns.alpha.kubernetes.io/hostname"
+	internalHostnameAnnotation      = "external-dns.alpha.kubernetes.io/internal-hostname"
+	targetAnnotation                = "external-dns.alpha.kubernetes.io/target"
+	ttlAnnotation                   = "external-dns.alpha.kubernetes.io/ttl"
+	controllerAnnotation            = "external-dns.alpha.kubernetes.io/controller"
+	ingressHostnameSourceAnnotation = "external-dns.alpha.kubernetes.io/ingress-hostname-source"
+)
+
+// controllerValue is the value of controller annotation of resources external-dns manages. Resources
+// annotated with other value are ignored by external-dns.
+const controllerValue = "dns-controller"
+
+// maxConcurrentLookups limits number of records resolved at the same time.
+const maxConcurrentLookups = 10
+
+// RecordStatus tells whether a DNS record resolves to its targets.
+type RecordStatus string
+
+const (
+	// RecordStatusResolved means that the host resolves to addresses of the targets.
+	RecordStatusResolved RecordStatus = "Resolved"
+	// RecordStatusMismatch means that the host resolves, but to other addresses than the targets.
+	RecordStatusMismatch RecordStatus = "Mismatch"
+	// RecordStatusUnresolved means that the host does not resolve.
+	RecordStatusUnresolved RecordStatus = "Unresolved"
+	// RecordStatusPending means that the record has no targets yet, e.g. load balancer of the
+	// service was not provisioned, so external-dns does not create it.
+	RecordStatusPending RecordStatus = "Pending"
+	// RecordStatusUnknown means that records are not resolved, because resolver is not configured.
+	RecordStatusUnknown RecordStatus = "Unknown"
+)
+
+// Source is a service or an ingress external-dns creates DNS records for.
+type Source struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+}
+
+// Record is a DNS record external-dns manages for a service or an ingress.
+type Record struct {
+	Hostname string `json:"hostname"`
+
+	// Type of the record, A, AAAA or CNAME. Empty when targets are not known.
+	Type string `json:"type"`
+
+	// Addresses or host names the record points to. Empty when external-dns chooses them, e.g. node
+	// addresses of node port services.
+	Targets []string `json:"targets"`
+
+	// TTL of the record in seconds, 0 when default TTL of the provider is used.
+	TTL int64 `json:"ttl,omitempty"`
+
+	Source Source `json:"source"`
+
+	// Addresses the host resolves to.
+	Addresses []string `json:"addresses"`
+
+	Status RecordStatus `json:"status"`
+
+	// Human readable details of the status, e.g. why the host does not resolve.
+	Message string `json:"message,omitempty"`
+}
+
+// RecordList contains DNS records managed by external-dns sorted by host name.
+type RecordList struct {
+	ListMeta api.ListMeta `json:"listMeta"`
+
+	// Whether records are resolved to verify them.
+	ResolverEnabled bool `json:"resolverEnabled"`
+
+	Records []Record `json:"records"`
+}
+
+// GetRecordList returns DNS records external-dns manages for services and ingresses in given
+// namespaces. Records are resolved with given resolver unless it is nil.
+func GetRecordList(client client.Interface, resolver dnsresolver.Resolver,
+	nsQuery *common.NamespaceQuery) (*RecordList, error) {
+	log.Print("Getting list of DNS records managed by external-dns")
+
+	channels := &common.ResourceChannels{
+		ServiceList: common.GetServiceListChannel(client, nsQuery, 1),
+		IngressList: common.GetIngressListChannel(client, nsQuery, 1),
+	}
+
+	services := <-channels.ServiceList.List
+	if err := <-channels.ServiceList.Error; err != nil {
+		return nil, err
+	}
+
+	ingresses := <-channels.IngressList.List
+	if err := <-channels.IngressList.Error; err != nil {
+		return nil, err
+	}
+
+	records := append(getServiceRecords(services.Items), getIngressRecords(ingresses.Items)...)
+	verifyRecords(records, resolver)
+	sort.Sort(recordsByHostname(records))
+
+	return &RecordList{
+		ListMeta:        api.ListMeta{TotalItems: len(records)},
+		ResolverEnabled: resolver != nil,
+		Records:         records,
+	}, nil
+}
+
+func getServiceRecords(services []v1.Service) []Record {
+	records := make([]Record, 0)
+	for _, service := range services {
+		if !isManaged(service.ObjectMeta) {
+			continue
+		}
+
+		source := Source{
+			ObjectMeta: api.NewObjectMeta(service.ObjectMeta),
+			TypeMeta:   api.NewTypeMeta(api.ResourceKindService),
+		}
+		targets, pending := getTargets(service.ObjectMeta, nil, false)
+		if service.Spec.Type == v1.ServiceTypeLoadBalancer {
+			targets, pending = getTargets(service.ObjectMeta, service.Status.LoadBalancer.Ingress, true)
+		}
+		for _, hostname := range splitHostnames(service.ObjectMeta.Annotations[hostnameAnnotation]) {
+			records = append(records, newRecord(hostname, targets, pending, source))
+		}
+
+		// Internal host names point to the cluster IP.
+		internalTargets := []string{}
+		if ip := service.Spec.ClusterIP; ip != "" && ip != v1.ClusterIPNone {
+			internalTargets = append(internalTargets, ip)
+		}
+		for _, hostname := range splitHostnames(service.ObjectMeta.Annotations[internalHostnameAnnotation]) {
+			records = append(records, newRecord(hostname, internalTargets, false, source))
+		}
+	}
+	return records
+}
+
+func getIngressRecords(ingresses []extensions.Ingress) []Record {
+	records := make([]Record, 0)
+	for _, ingress := range ingresses {
+		if !isManaged(ingress.ObjectMeta) {
+			continue
+		}
+
+		source := Source{
+			ObjectMeta: api.NewObjectMeta(ingress.ObjectMeta),
+			TypeMeta:   api.NewTypeMeta(api.ResourceKindIngress),
+		}
+		hostnameSource := ingress.ObjectMeta.Annotations[ingressHostnameSourceAnnotation]
+		hostnames := []string{}
+		if hostnameSource != "annotation-only" {
+			for _, rule := range ingress.Spec.Rules {
+				if rule.Host != "" {
+					hostnames = append(hostnames, rule.Host)
+				}
+			}
+		}
+		if hostnameSource != "defined-hosts-only" {
+			hostnames = append(hostnames, splitHostnames(ingress.ObjectMeta.Annotations[hostnameAnnotation])...)
+		}
+
+		targets, pending := getTargets(ingress.ObjectMeta, ingress.Status.LoadBalancer.Ingress, true)
+		seen := map[string]bool{}
+		for _, hostname := range hostnames {
+			if !seen[hostname] {
+				seen[hostname] = true
+				records = append(records, newRecord(hostname, targets, pending, source))
+			}
+		}
+	}
+	return records
+}
+
+// isManaged checks whether external-dns handles the resource, resources annotated for other DNS
+// controllers are ignored.
+func isManaged(meta metaV1.ObjectMeta) bool {
+	controller, ok := meta.Annotations[controllerAnnotation]
+	return !ok || controller == controllerValue
+}
+
+// getTargets returns targets of records of the resource. Target annotation overrides addresses of
+// the load balancer. Second value is true when the resource needs a load balancer address, but it
+// does not have one yet.
+func getTargets(meta metaV1.ObjectMeta, loadBalancer []v1.LoadBalancerIngress,
+	needsLoadBalancer bool) ([]string, bool) {
+	if targets := splitHostnames(meta.Annotations[targetAnnotation]); len(targets) > 0 {
+		return targets, false
+	}
+
+	targets := []string{}
+	for _, ingress := range loadBalancer {
+		if ingress.IP != "" {
+			targets = append(targets, ingress.IP)
+		} else if ingress.Hostname != "" {
+			targets = append(targets, ingress.Hostname)
+		}
+	}
+	return targets, needsLoadBalancer && len(targets) == 0
+}
+
+func newRecord(hostname string, targets []string, pending bool, source Source) Record {
+	record := Record{
+		Hostname:  hostname,
+		Type:      getRecordType(targets),
+		Targets:   targets,
+		TTL:       parseTTL(source.ObjectMeta.Annotations[ttlAnnotation]),
+		Source:    source,
+		Addresses: []string{},
+	}
+	if pending {
+		record.Status = RecordStatusPending
+		record.Message = "Load balancer has no address yet."
+	}
+	return record
+}
+
+// getRecordType returns type of the record pointing to given targets.
+func getRecordType(targets []string) string {
+	if len(targets) == 0 {
+		return ""
+	}
+	ip := net.ParseIP(targets[0])
+	switch {
+	case ip == nil:
+		return "CNAME"
+	case ip.To4() == nil:
+		return "AAAA"
+	default:
+		return "A"
+	}
+}
+
+// parseTTL parses TTL annotation, which is either a number of seconds or a duration, e.g. 1m.
+func parseTTL(value string) int64 {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds > 0 {
+		return seconds
+	}
+	if duration, err := time.ParseDuration(value); err == nil && duration > 0 {
+		return int64(duration.Seconds())
+	}
+	return 0
+}
+
+// splitHostnames splits comma separated list of host names or targets.
+func splitHostnames(value string) []string {
+	hostnames := []string{}
+	for _, hostname := range strings.Split(value, ",") {
+		if hostname = strings.TrimSuffix(strings.TrimSpace(hostname), "."); hostname != "" {
+			hostnames = append(hostnames, hostname)
+		}
+	}
+	return hostnames
+}
+
+// verifyRecords resolves records concurrently and sets their status.
+func verifyRecords(records []Record, resolver dnsresolver.Resolver) {
+	semaphore := make(chan struct{}, maxConcurrentLookups)
+	var wg sync.WaitGroup
+	for i := range records {
+		if records[i].Status != "" {
+			continue
+		}
+		if resolver == nil {
+			records[i].Status = RecordStatusUnknown
+			continue
+		}
+
+		wg.Add(1)
+		go func(record *Record) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			verifyRecord(record, resolver)
+		}(&records[i])
+	}
+	wg.Wait()
+}
+
+// verifyRecord resolves the host of the record and checks that it points to the targets. Host name
+// targets are resolved too, as providers often create alias A records for them instead of CNAMEs.
+func verifyRecord(record *Record, resolver dnsresolver.Resolver) {
+	addresses, err := resolver.LookupHost(record.Hostname)
+	if err != nil {
+		record.Status = RecordStatusUnresolved
+		record.Message = err.Error()
+		return
+	}
+	sort.Strings(addresses)
+	record.Addresses = addresses
+
+	if len(record.Targets) == 0 {
+		record.Status = RecordStatusResolved
+		return
+	}
+
+	expected := map[string]bool{}
+	for _, target := range record.Targets {
+		if net.ParseIP(target) != nil {
+			expected[target] = true
+			continue
+		}
+		if targetAddresses, err := resolver.LookupHost(target); err == nil {
+			for _, address := range targetAddresses {
+				expected[address] = true
+			}
+		}
+	}
+
+	for _, address := range addresses {
+		if expected[address] {
+			record.Status = RecordStatusResolved
+			return
+		}
+	}
+	record.Status = RecordStatusMismatch
+	record.Message = "Host does not resolve to addresses of the targets. The record may not be " +
+		"updated yet or it is managed by someone else."
+}
+
+// recordsByHostname sorts records by host name and then by their sources.
+type recordsByHostname []Record
+
+func (self recordsByHostname) Len() int      { return len(self) }
+func (self recordsByHostname) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+func (self recordsByHostname) Less(i, j int) bool {
+	if self[i].Hostname != self[j].Hostname {
+		return self[i].Hostname < self[j].Hostname
+	}
+	left, right := self[i].Source, self[j].Source
+	if left.TypeMeta.Kind != right.TypeMeta.Kind {
+		return left.TypeMeta.Kind < right.TypeMeta.Kind
+	}
+	if left.ObjectMeta.Namespace != right.ObjectMeta.Namespace {
+		return left.ObjectMeta.Namespace < right.ObjectMeta.Namespace
+	}
+	return left.ObjectMeta.Name < right.ObjectMeta.Name
+}
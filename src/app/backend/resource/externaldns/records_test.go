@@ -0,0 +1,143 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package externaldns
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+// fakeResolver resolves hosts to addresses from the map.
+type fakeResolver map[string][]string
+
+func (self fakeResolver) LookupHost(host string) ([]string, error) {
+	if addresses, ok := self[host]; ok {
+		return addresses, nil
+	}
+	return nil, errors.New("no such host")
+}
+
+func TestGetRecordList(t *testing.T) {
+	services := &v1.ServiceList{Items: []v1.Service{
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default", Annotations: map[string]string{
+				hostnameAnnotation: "web.foo.com., www.foo.com",
+				ttlAnnotation:      "1m",
+			}},
+			Spec: v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer, ClusterIP: "10.0.0.1"},
+			Status: v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{
+				Ingress: []v1.LoadBalancerIngress{{IP: "1.2.3.4"}},
+			}},
+		},
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "db", Namespace: "default", Annotations: map[string]string{
+				hostnameAnnotation:         "db.foo.com",
+				internalHostnameAnnotation: "db.internal.foo.com",
+			}},
+			Spec: v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer, ClusterIP: "10.0.0.2"},
+		},
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "other", Namespace: "default", Annotations: map[string]string{
+				hostnameAnnotation:   "other.foo.com",
+				controllerAnnotation: "other-controller",
+			}},
+		},
+	}}
+	ingresses := &extensions.IngressList{Items: []extensions.Ingress{{
+		ObjectMeta: metaV1.ObjectMeta{Name: "api", Namespace: "default", Annotations: map[string]string{
+			hostnameAnnotation: "api.foo.com",
+		}},
+		Spec: extensions.IngressSpec{Rules: []extensions.IngressRule{
+			{Host: "api.foo.com"},
+			{Host: "old.foo.com"},
+		}},
+		Status: extensions.IngressStatus{LoadBalancer: v1.LoadBalancerStatus{
+			Ingress: []v1.LoadBalancerIngress{{Hostname: "lb.aws.com"}},
+		}},
+	}}}
+	resolver := fakeResolver{
+		"web.foo.com":         {"1.2.3.4"},
+		"www.foo.com":         {"5.6.7.8"},
+		"db.internal.foo.com": {"10.0.0.2"},
+		"api.foo.com":         {"9.9.9.9"},
+		"lb.aws.com":          {"9.9.9.9"},
+	}
+
+	type result struct {
+		Hostname, Type string
+		TTL            int64
+		Status         RecordStatus
+	}
+	cases := []struct {
+		resolver fakeResolver
+		expected []result
+	}{
+		{
+			resolver,
+			[]result{
+				{"api.foo.com", "CNAME", 0, RecordStatusResolved},
+				{"db.foo.com", "", 0, RecordStatusPending},
+				{"db.internal.foo.com", "A", 0, RecordStatusResolved},
+				{"old.foo.com", "CNAME", 0, RecordStatusUnresolved},
+				{"web.foo.com", "A", 60, RecordStatusResolved},
+				{"www.foo.com", "A", 60, RecordStatusMismatch},
+			},
+		},
+		{
+			nil,
+			[]result{
+				{"api.foo.com", "CNAME", 0, RecordStatusUnknown},
+				{"db.foo.com", "", 0, RecordStatusPending},
+				{"db.internal.foo.com", "A", 0, RecordStatusUnknown},
+				{"old.foo.com", "CNAME", 0, RecordStatusUnknown},
+				{"web.foo.com", "A", 60, RecordStatusUnknown},
+				{"www.foo.com", "A", 60, RecordStatusUnknown},
+			},
+		},
+	}
+	for _, c := range cases {
+		fakeClient := fake.NewSimpleClientset(services, ingresses)
+		var list *RecordList
+		var err error
+		// Nil map is not a nil resolver, so it is not passed as one.
+		if c.resolver == nil {
+			list, err = GetRecordList(fakeClient, nil, common.NewNamespaceQuery(nil))
+		} else {
+			list, err = GetRecordList(fakeClient, c.resolver, common.NewNamespaceQuery(nil))
+		}
+		if err != nil {
+			t.Fatalf("GetRecordList() returned error: %s", err)
+		}
+
+		actual := []result{}
+		for _, record := range list.Records {
+			actual = append(actual, result{record.Hostname, record.Type, record.TTL, record.Status})
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetRecordList() == \ngot: %#v, \nexpected %#v", actual, c.expected)
+		}
+		if list.ResolverEnabled != (c.resolver != nil) {
+			t.Errorf("GetRecordList() resolver enabled == %v, expected %v", list.ResolverEnabled,
+				c.resolver != nil)
+		}
+	}
+}
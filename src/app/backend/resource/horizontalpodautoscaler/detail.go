@@ -18,8 +18,12 @@ import (
 	"log"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	client "k8s.io/client-go/kubernetes"
+	apiV1 "k8s.io/client-go/pkg/api/v1"
 	autoscaling "k8s.io/client-go/pkg/apis/autoscaling/v1"
 )
 
@@ -41,6 +45,9 @@ type HorizontalPodAutoscalerDetail struct {
 	DesiredReplicas int32 `json:"desiredReplicas"`
 
 	LastScaleTime *v1.Time `json:"lastScaleTime"`
+
+	// List of events related to this Horizontal Pod Autoscaler, e.g. its scaling decisions.
+	EventList common.EventList `json:"eventList"`
 }
 
 // GetHorizontalPodAutoscalerDetail returns detailed information about a horizontal pod autoscaler
@@ -53,10 +60,33 @@ func GetHorizontalPodAutoscalerDetail(client client.Interface, namespace string,
 		return nil, err
 	}
 
-	return getHorizontalPodAutoscalerDetail(rawHorizontalPodAutoscaler), nil
+	events, err := event.GetEvents(client, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Autoscalers are usually named after their targets, so keep their own events only.
+	events = filterEventsByKind(events, "HorizontalPodAutoscaler")
+	if !event.IsTypeFilled(events) {
+		events = event.FillEventsType(events)
+	}
+	eventList := event.CreateEventList(events, dataselect.DefaultDataSelect)
+
+	return getHorizontalPodAutoscalerDetail(rawHorizontalPodAutoscaler, eventList), nil
 }
 
-func getHorizontalPodAutoscalerDetail(horizontalPodAutoscaler *autoscaling.HorizontalPodAutoscaler) *HorizontalPodAutoscalerDetail {
+func filterEventsByKind(events []apiV1.Event, kind string) []apiV1.Event {
+	result := make([]apiV1.Event, 0)
+	for _, e := range events {
+		if e.InvolvedObject.Kind == kind {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func getHorizontalPodAutoscalerDetail(horizontalPodAutoscaler *autoscaling.HorizontalPodAutoscaler,
+	eventList common.EventList) *HorizontalPodAutoscalerDetail {
 
 	return &HorizontalPodAutoscalerDetail{
 		ObjectMeta: api.NewObjectMeta(horizontalPodAutoscaler.ObjectMeta),
@@ -76,5 +106,7 @@ func getHorizontalPodAutoscalerDetail(horizontalPodAutoscaler *autoscaling.Horiz
 		DesiredReplicas: horizontalPodAutoscaler.Status.DesiredReplicas,
 
 		LastScaleTime: horizontalPodAutoscaler.Status.LastScaleTime,
+
+		EventList: eventList,
 	}
 }
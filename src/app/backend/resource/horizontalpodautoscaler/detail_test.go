@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 	autoscaling "k8s.io/client-go/pkg/apis/autoscaling/v1"
@@ -35,7 +36,7 @@ func TestGetHorizontalPodAutoscalerDetail(t *testing.T) {
 	}{
 		{
 			"test-ns", "test-name",
-			[]string{"get"},
+			[]string{"get", "list"},
 			&autoscaling.HorizontalPodAutoscaler{
 				ObjectMeta: metaV1.ObjectMeta{Name: "test-name", Namespace: "test-ns"},
 				Spec: autoscaling.HorizontalPodAutoscalerSpec{
@@ -60,6 +61,7 @@ func TestGetHorizontalPodAutoscalerDetail(t *testing.T) {
 				MaxReplicas:     3,
 				CurrentReplicas: 1,
 				DesiredReplicas: 2,
+				EventList:       common.EventList{ListMeta: api.ListMeta{Page: 1, ItemsPerPage: 10}, Events: []common.Event{}},
 			},
 		},
 	}
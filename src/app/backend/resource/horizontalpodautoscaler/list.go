@@ -17,6 +17,7 @@ package horizontalpodautoscaler
 import (
 	"github.com/kubernetes/dashboard/src/app/backend/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	k8sClient "k8s.io/client-go/kubernetes"
 	autoscaling "k8s.io/client-go/pkg/apis/autoscaling/v1"
 )
@@ -40,9 +41,15 @@ type HorizontalPodAutoscaler struct {
 
 	CurrentCPUUtilizationPercentage *int32 `json:"currentCPUUtilizationPercentage"`
 	TargetCPUUtilizationPercentage  *int32 `json:"targetCPUUtilizationPercentage"`
+
+	CurrentReplicas int32 `json:"currentReplicas"`
+	DesiredReplicas int32 `json:"desiredReplicas"`
 }
 
-func GetHorizontalPodAutoscalerList(client k8sClient.Interface, nsQuery *common.NamespaceQuery) (*HorizontalPodAutoscalerList, error) {
+// GetHorizontalPodAutoscalerList returns a list of all Horizontal Pod Autoscalers in the given
+// namespaces.
+func GetHorizontalPodAutoscalerList(client k8sClient.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*HorizontalPodAutoscalerList, error) {
 
 	channel := common.GetHorizontalPodAutoscalerListChannel(client, nsQuery, 1)
 	hpaList := <-channel.List
@@ -50,10 +57,20 @@ func GetHorizontalPodAutoscalerList(client k8sClient.Interface, nsQuery *common.
 		return nil, err
 	}
 
-	return createHorizontalPodAutoscalerList(hpaList.Items), nil
+	return createHorizontalPodAutoscalerList(hpaList.Items, dsQuery), nil
 }
 
+// GetHorizontalPodAutoscalerListForResource returns a list of Horizontal Pod Autoscalers
+// targeting resource of given kind and name.
 func GetHorizontalPodAutoscalerListForResource(client k8sClient.Interface, namespace, kind, name string) (*HorizontalPodAutoscalerList, error) {
+	return GetHorizontalPodAutoscalerListForTargets(client, namespace,
+		[]ScaleTargetRef{{Kind: kind, Name: name}})
+}
+
+// GetHorizontalPodAutoscalerListForTargets returns a list of Horizontal Pod Autoscalers targeting
+// any of given resources, e.g. a Replica Set and the Deployment controlling it.
+func GetHorizontalPodAutoscalerListForTargets(client k8sClient.Interface, namespace string,
+	targets []ScaleTargetRef) (*HorizontalPodAutoscalerList, error) {
 
 	nsQuery := common.NewSameNamespaceQuery(namespace)
 
@@ -65,20 +82,35 @@ func GetHorizontalPodAutoscalerListForResource(client k8sClient.Interface, names
 
 	filteredHpaList := make([]autoscaling.HorizontalPodAutoscaler, 0)
 	for _, hpa := range hpaList.Items {
-		if hpa.Spec.ScaleTargetRef.Kind == kind && hpa.Spec.ScaleTargetRef.Name == name {
+		if isTargeting(hpa, targets) {
 			filteredHpaList = append(filteredHpaList, hpa)
 		}
 	}
 
-	return createHorizontalPodAutoscalerList(filteredHpaList), nil
+	return createHorizontalPodAutoscalerList(filteredHpaList, dataselect.NoDataSelect), nil
 }
 
-func createHorizontalPodAutoscalerList(hpas []autoscaling.HorizontalPodAutoscaler) *HorizontalPodAutoscalerList {
+// isTargeting returns true if given Horizontal Pod Autoscaler scales any of given resources.
+func isTargeting(hpa autoscaling.HorizontalPodAutoscaler, targets []ScaleTargetRef) bool {
+	for _, target := range targets {
+		if hpa.Spec.ScaleTargetRef.Kind == target.Kind && hpa.Spec.ScaleTargetRef.Name == target.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func createHorizontalPodAutoscalerList(hpas []autoscaling.HorizontalPodAutoscaler,
+	dsQuery *dataselect.DataSelectQuery) *HorizontalPodAutoscalerList {
 	hpaList := &HorizontalPodAutoscalerList{
 		HorizontalPodAutoscalers: make([]HorizontalPodAutoscaler, 0),
 		ListMeta:                 api.ListMeta{TotalItems: len(hpas)},
 	}
 
+	hpaCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(hpas), dsQuery)
+	hpas = fromCells(hpaCells)
+	hpaList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, hpaList.ListMeta.TotalItems)
+
 	for _, hpa := range hpas {
 		horizontalPodAutoscaler := toHorizontalPodAutoScaler(&hpa)
 		hpaList.HorizontalPodAutoscalers = append(hpaList.HorizontalPodAutoscalers, horizontalPodAutoscaler)
@@ -100,6 +132,9 @@ func toHorizontalPodAutoScaler(hpa *autoscaling.HorizontalPodAutoscaler) Horizon
 		MaxReplicas:                     hpa.Spec.MaxReplicas,
 		CurrentCPUUtilizationPercentage: hpa.Status.CurrentCPUUtilizationPercentage,
 		TargetCPUUtilizationPercentage:  hpa.Spec.TargetCPUUtilizationPercentage,
+
+		CurrentReplicas: hpa.Status.CurrentReplicas,
+		DesiredReplicas: hpa.Status.DesiredReplicas,
 	}
 
 }
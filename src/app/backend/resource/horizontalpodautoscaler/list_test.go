@@ -20,6 +20,7 @@ import (
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 	autoscaling "k8s.io/client-go/pkg/apis/autoscaling/v1"
@@ -89,7 +90,9 @@ var (
 				Kind: "test-kind1",
 				Name: "test-name1",
 			},
-			MaxReplicas: 3,
+			MaxReplicas:     3,
+			CurrentReplicas: 1,
+			DesiredReplicas: 2,
 		}, {
 			ObjectMeta: api.ObjectMeta{Name: "test-hpa2", Namespace: "test-ns"},
 			TypeMeta:   api.TypeMeta{Kind: api.ResourceKindHorizontalPodAutoscaler},
@@ -97,7 +100,9 @@ var (
 				Kind: "test-kind2",
 				Name: "test-name2",
 			},
-			MaxReplicas: 3,
+			MaxReplicas:     3,
+			CurrentReplicas: 1,
+			DesiredReplicas: 2,
 		}, {
 			ObjectMeta: api.ObjectMeta{Name: "test-hpa3", Namespace: "test-ns"},
 			TypeMeta:   api.TypeMeta{Kind: api.ResourceKindHorizontalPodAutoscaler},
@@ -105,7 +110,9 @@ var (
 				Kind: "test-kind2",
 				Name: "test-name2",
 			},
-			MaxReplicas: 3,
+			MaxReplicas:     3,
+			CurrentReplicas: 1,
+			DesiredReplicas: 2,
 		}, {
 			ObjectMeta: api.ObjectMeta{Name: "test-hpa4", Namespace: "test-ns"},
 			TypeMeta:   api.TypeMeta{Kind: api.ResourceKindHorizontalPodAutoscaler},
@@ -113,12 +120,14 @@ var (
 				Kind: "test-kind2",
 				Name: "test-name3",
 			},
-			MaxReplicas: 3,
+			MaxReplicas:     3,
+			CurrentReplicas: 1,
+			DesiredReplicas: 2,
 		},
 	}
 )
 
-//func GetHorizontalPodAutoscalerList(client k8sClient.Interface, nsQuery *api.NamespaceQuery) (*HorizontalPodAutoscalerList, error) {
+//func GetHorizontalPodAutoscalerList(client k8sClient.Interface, nsQuery *api.NamespaceQuery, dsQuery *dataselect.DataSelectQuery) (*HorizontalPodAutoscalerList, error) {
 func TestGetHorizontalPodAutoscalerList(t *testing.T) {
 	cases := []struct {
 		expectedActions []string
@@ -131,7 +140,7 @@ func TestGetHorizontalPodAutoscalerList(t *testing.T) {
 				Items: apiHpaList,
 			},
 			&HorizontalPodAutoscalerList{
-				ListMeta:                 api.ListMeta{TotalItems: 4},
+				ListMeta:                 api.ListMeta{TotalItems: 4, TotalBeforeFilter: 4},
 				HorizontalPodAutoscalers: ourHpaList,
 			},
 		},
@@ -140,7 +149,7 @@ func TestGetHorizontalPodAutoscalerList(t *testing.T) {
 	for _, c := range cases {
 		fakeClient := fake.NewSimpleClientset(c.hpaList)
 
-		actual, _ := GetHorizontalPodAutoscalerList(fakeClient, &common.NamespaceQuery{})
+		actual, _ := GetHorizontalPodAutoscalerList(fakeClient, &common.NamespaceQuery{}, dataselect.NoDataSelect)
 
 		actions := fakeClient.Actions()
 		if len(actions) != len(c.expectedActions) {
@@ -178,7 +187,7 @@ func TestGetHorizontalPodAutoscalerListForResource(t *testing.T) {
 				Items: apiHpaList,
 			},
 			&HorizontalPodAutoscalerList{
-				ListMeta:                 api.ListMeta{TotalItems: 1},
+				ListMeta:                 api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
 				HorizontalPodAutoscalers: []HorizontalPodAutoscaler{ourHpaList[0]},
 			},
 		}, {
@@ -188,7 +197,7 @@ func TestGetHorizontalPodAutoscalerListForResource(t *testing.T) {
 				Items: apiHpaList,
 			},
 			&HorizontalPodAutoscalerList{
-				ListMeta:                 api.ListMeta{TotalItems: 2},
+				ListMeta:                 api.ListMeta{TotalItems: 2, TotalBeforeFilter: 2},
 				HorizontalPodAutoscalers: []HorizontalPodAutoscaler{ourHpaList[1], ourHpaList[2]},
 			},
 		}, {
@@ -198,7 +207,7 @@ func TestGetHorizontalPodAutoscalerListForResource(t *testing.T) {
 				Items: apiHpaList,
 			},
 			&HorizontalPodAutoscalerList{
-				ListMeta:                 api.ListMeta{TotalItems: 1},
+				ListMeta:                 api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
 				HorizontalPodAutoscalers: []HorizontalPodAutoscaler{ourHpaList[3]},
 			},
 		}, {
@@ -208,7 +217,7 @@ func TestGetHorizontalPodAutoscalerListForResource(t *testing.T) {
 				Items: apiHpaList,
 			},
 			&HorizontalPodAutoscalerList{
-				ListMeta:                 api.ListMeta{TotalItems: 0},
+				ListMeta:                 api.ListMeta{TotalItems: 0, TotalBeforeFilter: 0},
 				HorizontalPodAutoscalers: []HorizontalPodAutoscaler{},
 			},
 		},
@@ -240,3 +249,37 @@ func TestGetHorizontalPodAutoscalerListForResource(t *testing.T) {
 	}
 
 }
+
+func TestGetHorizontalPodAutoscalerListForTargets(t *testing.T) {
+	cases := []struct {
+		targets  []ScaleTargetRef
+		expected *HorizontalPodAutoscalerList
+	}{
+		{
+			[]ScaleTargetRef{{Kind: "test-kind1", Name: "test-name1"}, {Kind: "test-kind2", Name: "test-name3"}},
+			&HorizontalPodAutoscalerList{
+				ListMeta:                 api.ListMeta{TotalItems: 2, TotalBeforeFilter: 2},
+				HorizontalPodAutoscalers: []HorizontalPodAutoscaler{ourHpaList[0], ourHpaList[3]},
+			},
+		}, {
+			[]ScaleTargetRef{},
+			&HorizontalPodAutoscalerList{
+				ListMeta:                 api.ListMeta{TotalItems: 0},
+				HorizontalPodAutoscalers: []HorizontalPodAutoscaler{},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		fakeClient := fake.NewSimpleClientset(&autoscaling.HorizontalPodAutoscalerList{Items: apiHpaList})
+
+		actual, err := GetHorizontalPodAutoscalerListForTargets(fakeClient, "", c.targets)
+		if err != nil {
+			t.Errorf("GetHorizontalPodAutoscalerListForTargets(client, %#v) == \ngot err %#v", c.targets, err)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetHorizontalPodAutoscalerListForTargets(client, %#v) == \ngot: %#v, \nexpected %#v",
+				c.targets, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingress
+
+import (
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+// IngressClassAnnotationKey is the annotation that selects the class of an ingress, i.e. the
+// controllers that reconcile it.
+const IngressClassAnnotationKey = "kubernetes.io/ingress.class"
+
+// Class of ingresses reconciled by the load balancer controller of Google Cloud, which runs on the
+// hosted master and can't be found among pods.
+const gceIngressClass = "gce"
+
+// knownIngressController is an ingress controller implementation recognized by container image.
+type knownIngressController struct {
+	// Substring of the container image.
+	image string
+
+	// Name of the implementation, e.g. nginx.
+	implementation string
+
+	// Class served when not overridden by one of arguments.
+	defaultClass string
+
+	// Prefixes of arguments that override the served class, e.g. --ingress-class=.
+	classArgs []string
+}
+
+var knownIngressControllers = []knownIngressController{
+	{"nginx-ingress-controller", "nginx", "nginx", []string{"--ingress-class="}},
+	{"glbc", "gce", gceIngressClass, []string{"--ingress-class="}},
+	{"traefik", "traefik", "traefik", []string{"--kubernetes.ingressclass="}},
+	{"haproxy-ingress", "haproxy", "haproxy", []string{"--ingress-class="}},
+	{"contour", "contour", "contour", []string{"--ingress-class-name="}},
+	{"kong-ingress-controller", "kong", "kong", []string{"--ingress-class="}},
+	{"voyager", "voyager", "voyager", []string{"--ingress-class="}},
+}
+
+// IngressController is an ingress controller running in the cluster.
+type IngressController struct {
+	// Meta of the object running the controller, usually a deployment or daemon set, or of the pod
+	// if it has no controller.
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	// Name of the implementation, e.g. nginx.
+	Implementation string `json:"implementation"`
+
+	// Class of ingresses reconciled by the controller.
+	Class string `json:"class"`
+
+	// Whether the controller also reconciles ingresses without class. True when it serves its
+	// default class.
+	DefaultClass bool `json:"defaultClass"`
+
+	// Numbers of pods and ready pods of the controller.
+	Pods      int `json:"pods"`
+	ReadyPods int `json:"readyPods"`
+}
+
+// IngressClass is the class of an ingress together with the controllers that reconcile it.
+type IngressClass struct {
+	// Name of the class, empty for ingress without class.
+	Name string `json:"name"`
+
+	// Controllers that reconcile the ingress.
+	Controllers []IngressController `json:"controllers"`
+
+	// Whether controllers were looked up. They are looked up only for ingress detail, since it
+	// requires listing pods in all namespaces.
+	ControllersKnown bool `json:"controllersKnown"`
+
+	// Whether no running controller reconciles the ingress. Set only when controllers are known.
+	Unreconciled bool `json:"unreconciled"`
+}
+
+// getIngressClass returns class of given ingress, without controllers.
+func getIngressClass(ingress *extensions.Ingress) IngressClass {
+	return IngressClass{
+		Name:        ingress.Annotations[IngressClassAnnotationKey],
+		Controllers: make([]IngressController, 0),
+	}
+}
+
+// GetIngressControllers returns ingress controllers found among pods of all namespaces. False is
+// returned if pods can't be listed, e.g. because of missing permissions.
+func GetIngressControllers(client client.Interface) ([]IngressController, bool) {
+	channels := &common.ResourceChannels{
+		PodList: common.GetPodListChannel(client, common.NewNamespaceQuery(nil), 1),
+	}
+	pods := <-channels.PodList.List
+	if err := <-channels.PodList.Error; err != nil {
+		log.Printf("Couldn't look up ingress controllers: %s", err)
+		return nil, false
+	}
+
+	return getIngressControllers(pods.Items), true
+}
+
+func getIngressControllers(pods []v1.Pod) []IngressController {
+	controllers := make([]IngressController, 0)
+	indexes := make(map[string]int)
+	for _, pod := range pods {
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+
+		for _, container := range pod.Spec.Containers {
+			known, ok := findKnownIngressController(container.Image)
+			if !ok {
+				continue
+			}
+
+			class := known.defaultClass
+			for _, arg := range append(append([]string{}, container.Command...), container.Args...) {
+				for _, prefix := range known.classArgs {
+					if strings.HasPrefix(arg, prefix) {
+						class = strings.TrimPrefix(arg, prefix)
+					}
+				}
+			}
+
+			kind, name := api.ResourceKind(api.ResourceKindPod), pod.Name
+			for _, reference := range pod.OwnerReferences {
+				if reference.Controller != nil && *reference.Controller {
+					kind, name = api.ResourceKind(strings.ToLower(reference.Kind)), reference.Name
+				}
+			}
+
+			key := strings.Join([]string{pod.Namespace, string(kind), name, known.implementation,
+				class}, "/")
+			index, ok := indexes[key]
+			if !ok {
+				index = len(controllers)
+				indexes[key] = index
+				controllers = append(controllers, IngressController{
+					ObjectMeta:     api.ObjectMeta{Name: name, Namespace: pod.Namespace},
+					TypeMeta:       api.NewTypeMeta(kind),
+					Implementation: known.implementation,
+					Class:          class,
+					DefaultClass:   class == known.defaultClass,
+				})
+			}
+			controllers[index].Pods++
+			if common.IsPodReady(&pod) {
+				controllers[index].ReadyPods++
+			}
+			break
+		}
+	}
+
+	sort.Sort(controllersByName(controllers))
+	return controllers
+}
+
+// findKnownIngressController returns the known ingress controller running given image.
+func findKnownIngressController(image string) (knownIngressController, bool) {
+	for _, known := range knownIngressControllers {
+		if strings.Contains(image, known.image) {
+			return known, true
+		}
+	}
+	return knownIngressController{}, false
+}
+
+// resolveIngressClass returns given ingress class with controllers reconciling it, chosen from
+// given controllers. Controllers are not resolved if not known.
+func resolveIngressClass(name string, controllers []IngressController, known bool) IngressClass {
+	class := IngressClass{
+		Name:             name,
+		Controllers:      make([]IngressController, 0),
+		ControllersKnown: known,
+	}
+	if !known {
+		return class
+	}
+
+	running := false
+	for _, controller := range controllers {
+		if controller.Class == class.Name || (class.Name == "" && controller.DefaultClass) {
+			class.Controllers = append(class.Controllers, controller)
+			running = running || controller.ReadyPods > 0
+		}
+	}
+	class.Unreconciled = !running && class.Name != gceIngressClass
+	return class
+}
+
+// controllersByName sorts ingress controllers by namespace and name.
+type controllersByName []IngressController
+
+func (c controllersByName) Len() int      { return len(c) }
+func (c controllersByName) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c controllersByName) Less(i, j int) bool {
+	if c[i].ObjectMeta.Namespace != c[j].ObjectMeta.Namespace {
+		return c[i].ObjectMeta.Namespace < c[j].ObjectMeta.Namespace
+	}
+	return c[i].ObjectMeta.Name < c[j].ObjectMeta.Name
+}
@@ -0,0 +1,131 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingress
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+func TestGetIngressControllers(t *testing.T) {
+	isController := true
+	pod := func(name, image string, ready bool, args ...string) v1.Pod {
+		status := v1.ConditionFalse
+		if ready {
+			status = v1.ConditionTrue
+		}
+		return v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "ingress",
+				OwnerReferences: []metaV1.OwnerReference{
+					{Kind: "DaemonSet", Name: "controller-" + image, Controller: &isController},
+				}},
+			Spec: v1.PodSpec{Containers: []v1.Container{
+				{Image: "example/sidecar"},
+				{Image: "example/" + image + ":0.9", Args: args},
+			}},
+			Status: v1.PodStatus{Phase: v1.PodRunning, Conditions: []v1.PodCondition{
+				{Type: v1.PodReady, Status: status},
+			}},
+		}
+	}
+	pods := []v1.Pod{
+		pod("nginx-1", "nginx-ingress-controller", true),
+		pod("nginx-2", "nginx-ingress-controller", false),
+		pod("traefik-1", "traefik", false, "--kubernetes.ingressclass=internal"),
+		pod("other", "other", true),
+	}
+
+	expected := []IngressController{
+		{
+			ObjectMeta:     api.ObjectMeta{Name: "controller-nginx-ingress-controller", Namespace: "ingress"},
+			TypeMeta:       api.TypeMeta{Kind: api.ResourceKindDaemonSet},
+			Implementation: "nginx",
+			Class:          "nginx",
+			DefaultClass:   true,
+			Pods:           2,
+			ReadyPods:      1,
+		},
+		{
+			ObjectMeta:     api.ObjectMeta{Name: "controller-traefik", Namespace: "ingress"},
+			TypeMeta:       api.TypeMeta{Kind: api.ResourceKindDaemonSet},
+			Implementation: "traefik",
+			Class:          "internal",
+			Pods:           1,
+		},
+	}
+	actual := getIngressControllers(pods)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getIngressControllers() == \ngot: %#v, \nexpected %#v", actual, expected)
+	}
+}
+
+func TestResolveIngressClass(t *testing.T) {
+	nginx := IngressController{Implementation: "nginx", Class: "nginx", DefaultClass: true,
+		Pods: 1, ReadyPods: 1}
+	internal := IngressController{Implementation: "traefik", Class: "internal", Pods: 1}
+	controllers := []IngressController{nginx, internal}
+
+	cases := []struct {
+		name     string
+		known    bool
+		expected IngressClass
+	}{
+		{"", true, IngressClass{Controllers: []IngressController{nginx}, ControllersKnown: true}},
+		{"nginx", true, IngressClass{Name: "nginx", Controllers: []IngressController{nginx},
+			ControllersKnown: true}},
+		{"internal", true, IngressClass{Name: "internal", Controllers: []IngressController{internal},
+			ControllersKnown: true, Unreconciled: true}},
+		{"missing", true, IngressClass{Name: "missing", Controllers: []IngressController{},
+			ControllersKnown: true, Unreconciled: true}},
+		{"gce", true, IngressClass{Name: "gce", Controllers: []IngressController{},
+			ControllersKnown: true}},
+		{"missing", false, IngressClass{Name: "missing", Controllers: []IngressController{}}},
+	}
+	for _, c := range cases {
+		actual := resolveIngressClass(c.name, controllers, c.known)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("resolveIngressClass(%#v, %v) == \ngot: %#v, \nexpected %#v", c.name, c.known,
+				actual, c.expected)
+		}
+	}
+}
+
+func TestGetIngressListDoesNotListPods(t *testing.T) {
+	client := fake.NewSimpleClientset(&extensions.Ingress{
+		ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default",
+			Annotations: map[string]string{IngressClassAnnotationKey: "nginx"}},
+	})
+
+	list, err := GetIngressList(client, common.NewNamespaceQuery(nil), dataselect.NoDataSelect)
+	if err != nil {
+		t.Fatalf("GetIngressList() returned error: %s", err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Class.Name != "nginx" || list.Items[0].Class.ControllersKnown {
+		t.Errorf("GetIngressList() == %#v, expected ingress of nginx class without controllers", list.Items)
+	}
+	for _, action := range client.Actions() {
+		if action.GetResource().Resource == "pods" {
+			t.Errorf("GetIngressList() should not list pods, got action %#v", action)
+		}
+	}
+}
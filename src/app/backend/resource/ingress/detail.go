@@ -18,6 +18,7 @@ import (
 	"log"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/certmanager"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	client "k8s.io/client-go/kubernetes"
 	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
@@ -35,6 +36,12 @@ type IngressDetail struct {
 
 	// Status is the current state of the Ingress.
 	Status extensions.IngressStatus `json:"status"`
+
+	// Class of the Ingress with controllers that reconcile it.
+	Class IngressClass `json:"class"`
+
+	// Names of cert-manager certificates issued for TLS secrets of the Ingress.
+	CertManagerCertificates []string `json:"certManagerCertificates"`
 }
 
 // GetIngressDetail returns returns detailed information about an ingress
@@ -47,14 +54,19 @@ func GetIngressDetail(client client.Interface, namespace, name string) (*Ingress
 		return nil, err
 	}
 
-	return getIngressDetail(rawIngress), nil
+	detail := getIngressDetail(rawIngress)
+	controllers, known := GetIngressControllers(client)
+	detail.Class = resolveIngressClass(detail.Class.Name, controllers, known)
+	return detail, nil
 }
 
 func getIngressDetail(rawIngress *extensions.Ingress) *IngressDetail {
 	return &IngressDetail{
-		ObjectMeta: api.NewObjectMeta(rawIngress.ObjectMeta),
-		TypeMeta:   api.NewTypeMeta(api.ResourceKindIngress),
-		Spec:       rawIngress.Spec,
-		Status:     rawIngress.Status,
+		ObjectMeta:              api.NewObjectMeta(rawIngress.ObjectMeta),
+		TypeMeta:                api.NewTypeMeta(api.ResourceKindIngress),
+		Spec:                    rawIngress.Spec,
+		Status:                  rawIngress.Status,
+		Class:                   getIngressClass(rawIngress),
+		CertManagerCertificates: certmanager.GetIngressCertificates(rawIngress),
 	}
 }
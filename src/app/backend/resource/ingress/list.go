@@ -32,6 +32,10 @@ type Ingress struct {
 
 	// External endpoints of this ingress.
 	Endpoints []common.Endpoint `json:"endpoints"`
+
+	// Class of this ingress. Controllers are resolved only in the ingress detail, looking them up
+	// requires listing pods in all namespaces.
+	Class IngressClass `json:"class"`
 }
 
 // IngressList - response structure for a queried ingress list.
@@ -52,7 +56,8 @@ func GetIngressList(client client.Interface, namespace *common.NamespaceQuery,
 	if err != nil {
 		return nil, err
 	}
-	return NewIngressList(ingressList.Items, dsQuery), err
+
+	return NewIngressList(ingressList.Items, dsQuery), nil
 }
 
 // GetIngressListFromChannels - return all ingresses in the given namespace.
@@ -72,6 +77,7 @@ func NewIngress(ingress *extensions.Ingress) *Ingress {
 		ObjectMeta: api.NewObjectMeta(ingress.ObjectMeta),
 		TypeMeta:   api.NewTypeMeta(api.ResourceKindIngress),
 		Endpoints:  getEndpoints(ingress),
+		Class:      getIngressClass(ingress),
 	}
 
 	return modelIngress
@@ -97,7 +103,7 @@ func NewIngressList(ingresses []extensions.Ingress, dsQuery *dataselect.DataSele
 
 	ingresCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(ingresses), dsQuery)
 	ingresses = fromCells(ingresCells)
-	newIngressList.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+	newIngressList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, newIngressList.ListMeta.TotalItems)
 
 	for _, ingress := range ingresses {
 		newIngressList.Items = append(newIngressList.Items, *NewIngress(&ingress))
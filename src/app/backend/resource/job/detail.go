@@ -16,7 +16,8 @@ package job
 
 import (
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	"github.com/kubernetes/dashboard/src/app/backend/link"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/pod"
@@ -32,6 +33,9 @@ type JobDetail struct {
 	ObjectMeta api.ObjectMeta `json:"objectMeta"`
 	TypeMeta   api.TypeMeta   `json:"typeMeta"`
 
+	// Links to external tools configured in settings and in the links annotation.
+	Links []link.Link `json:"links,omitempty"`
+
 	// Aggregate information about pods belonging to this Job.
 	PodInfo common.PodInfo `json:"podInfo"`
 
@@ -51,10 +55,13 @@ type JobDetail struct {
 	// Completions specifies the desired number of successfully finished pods the job should be
 	// run with.
 	Completions *int32 `json:"completions"`
+
+	// Whether the job is suspended.
+	Suspended bool `json:"suspended"`
 }
 
 // GetJobDetail gets job details.
-func GetJobDetail(client k8sClient.Interface, heapsterClient heapster.HeapsterClient,
+func GetJobDetail(client k8sClient.Interface, heapsterClient metricapi.MetricClient,
 	namespace, name string) (*JobDetail, error) {
 
 	// TODO(floreks): Use channels.
@@ -82,7 +89,7 @@ func GetJobDetail(client k8sClient.Interface, heapsterClient heapster.HeapsterCl
 	return &job, nil
 }
 
-func getJobDetail(job *batch.Job, heapsterClient heapster.HeapsterClient,
+func getJobDetail(job *batch.Job, heapsterClient metricapi.MetricClient,
 	eventList common.EventList, podList pod.PodList, podInfo common.PodInfo) JobDetail {
 	return JobDetail{
 		ObjectMeta:      api.NewObjectMeta(job.ObjectMeta),
@@ -93,5 +100,6 @@ func getJobDetail(job *batch.Job, heapsterClient heapster.HeapsterClient,
 		EventList:       eventList,
 		Parallelism:     job.Spec.Parallelism,
 		Completions:     job.Spec.Completions,
+		Suspended:       isSuspended(job),
 	}
 }
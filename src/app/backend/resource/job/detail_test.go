@@ -19,7 +19,7 @@ import (
 	"testing"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
@@ -33,7 +33,7 @@ import (
 type FakeHeapsterClient struct {
 }
 
-func (c FakeHeapsterClient) Get(path string) heapster.RequestInterface {
+func (c FakeHeapsterClient) Get(path string) metricapi.RequestInterface {
 	return &restclient.Request{}
 }
 
@@ -73,10 +73,11 @@ func TestGetJobDetail(t *testing.T) {
 				TypeMeta: api.TypeMeta{Kind: api.ResourceKindJob},
 				PodInfo:  common.PodInfo{Warnings: []common.Event{}},
 				PodList: pod.PodList{
+					ListMeta:          api.ListMeta{Page: 1, ItemsPerPage: 10},
 					Pods:              []pod.Pod{},
 					CumulativeMetrics: make([]metric.Metric, 0),
 				},
-				EventList:   common.EventList{Events: []common.Event{}},
+				EventList:   common.EventList{ListMeta: api.ListMeta{Page: 1, ItemsPerPage: 10}, Events: []common.Event{}},
 				Parallelism: &jobCompletions,
 				Completions: &parallelism,
 			},
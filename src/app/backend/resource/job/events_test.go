@@ -49,7 +49,7 @@ func TestGetJobEvents(t *testing.T) {
 			createJob("job-1", "ns-1", map[string]string{"app": "test"}),
 			[]string{"list", "get", "list", "list"},
 			&common.EventList{
-				ListMeta: api.ListMeta{TotalItems: 1},
+				ListMeta: api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
 				Events: []common.Event{{
 					TypeMeta: api.TypeMeta{Kind: api.ResourceKindEvent},
 					ObjectMeta: api.ObjectMeta{Name: "ev-1", Namespace: "ns-1",
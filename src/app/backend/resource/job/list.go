@@ -18,7 +18,7 @@ import (
 	"log"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
@@ -33,6 +33,9 @@ import (
 type JobList struct {
 	ListMeta api.ListMeta `json:"listMeta"`
 
+	// Status summary of the returned items.
+	Status common.ResourceStatus `json:"status"`
+
 	// Unordered list of Jobs.
 	Jobs              []Job           `json:"jobs"`
 	CumulativeMetrics []metric.Metric `json:"cumulativeMetrics"`
@@ -52,24 +55,28 @@ type Job struct {
 
 	// number of parallel jobs defined.
 	Parallelism *int32 `json:"parallelism"`
+
+	// Whether the job is suspended.
+	Suspended bool `json:"suspended"`
 }
 
 // GetJobList returns a list of all Jobs in the cluster.
 func GetJobList(client client.Interface, nsQuery *common.NamespaceQuery,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*JobList, error) {
+	dsQuery *dataselect.DataSelectQuery, heapsterClient *metricapi.MetricClient) (*JobList, error) {
 	log.Print("Getting list of all jobs in the cluster")
 
+	options := dsQuery.ListOptions()
 	channels := &common.ResourceChannels{
-		JobList:   common.GetJobListChannel(client, nsQuery, 1),
-		PodList:   common.GetPodListChannel(client, nsQuery, 1),
-		EventList: common.GetEventListChannel(client, nsQuery, 1),
+		JobList:   common.GetJobListChannelWithOptions(client, nsQuery, options, 1),
+		PodList:   common.GetFanOutPodListChannel(client, nsQuery, 1),
+		EventList: common.GetWarningEventListChannel(client, nsQuery, 1),
 	}
 
 	return GetJobListFromChannels(channels, dsQuery, heapsterClient)
 }
 
 // GetJobListFromChannels returns a list of all Jobs in the cluster reading required resource list once from the channels.
-func GetJobListFromChannels(channels *common.ResourceChannels, dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (
+func GetJobListFromChannels(channels *common.ResourceChannels, dsQuery *dataselect.DataSelectQuery, heapsterClient *metricapi.MetricClient) (
 	*JobList, error) {
 
 	jobs := <-channels.JobList.List
@@ -96,34 +103,34 @@ func GetJobListFromChannels(channels *common.ResourceChannels, dsQuery *datasele
 		return nil, err
 	}
 
-	return CreateJobList(jobs.Items, pods.Items, events.Items, dsQuery, heapsterClient), nil
+	result := CreateJobList(jobs.Items, pods.Items, events.Items, dsQuery, heapsterClient)
+	result.ListMeta.Warnings = common.GetListBudgetWarnings(pods, events)
+	return result, nil
 }
 
 // CreateJobList returns a list of all Job model objects in the cluster, based on all
 // Kubernetes Job API objects.
 func CreateJobList(jobs []batch.Job, pods []v1.Pod, events []v1.Event,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) *JobList {
+	dsQuery *dataselect.DataSelectQuery, heapsterClient *metricapi.MetricClient) *JobList {
 
 	jobList := &JobList{
 		Jobs:     make([]Job, 0),
 		ListMeta: api.ListMeta{TotalItems: len(jobs)},
 	}
 
+	for _, job := range FromCells(dataselect.GenericDataFilter(ToCells(jobs), dsQuery)) {
+		jobList.Status.AddPodInfo(getPodInfo(job, pods, events))
+	}
+
 	cachedResources := &dataselect.CachedResources{
 		Pods: pods,
 	}
 	jobCells, metricPromises, filteredTotal := dataselect.GenericDataSelectWithFilterAndMetrics(ToCells(jobs), dsQuery, cachedResources, heapsterClient)
 	jobs = FromCells(jobCells)
-	jobList.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+	jobList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, jobList.ListMeta.TotalItems)
 
 	for _, job := range jobs {
-		var completions int32
-		matchingPods := common.FilterPodsByOwnerReference(job.Namespace, job.UID, pods)
-		if job.Spec.Completions != nil {
-			completions = *job.Spec.Completions
-		}
-		podInfo := common.GetPodInfo(job.Status.Active, completions, matchingPods)
-		podInfo.Warnings = event.GetPodsEventWarnings(events, matchingPods)
+		podInfo := getPodInfo(job, pods, events)
 		jobList.Jobs = append(jobList.Jobs, ToJob(&job, &podInfo))
 	}
 
@@ -143,5 +150,18 @@ func ToJob(job *batch.Job, podInfo *common.PodInfo) Job {
 		ContainerImages: common.GetContainerImages(&job.Spec.Template.Spec),
 		Pods:            *podInfo,
 		Parallelism:     job.Spec.Parallelism,
+		Suspended:       isSuspended(job),
+	}
+}
+
+// getPodInfo returns aggregate information about pods of given job.
+func getPodInfo(job batch.Job, pods []v1.Pod, events []v1.Event) common.PodInfo {
+	var completions int32
+	matchingPods := common.FilterPodsByOwnerReference(job.Namespace, job.UID, pods)
+	if job.Spec.Completions != nil {
+		completions = *job.Spec.Completions
 	}
+	podInfo := common.GetPodInfo(job.Status.Active, completions, matchingPods)
+	podInfo.Warnings = event.GetPodsEventWarnings(events, matchingPods)
+	return podInfo
 }
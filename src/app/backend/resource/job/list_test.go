@@ -153,7 +153,8 @@ func TestGetJobListFromChannels(t *testing.T) {
 				},
 			},
 			&JobList{
-				ListMeta:          api.ListMeta{TotalItems: 2},
+				ListMeta:          api.ListMeta{TotalItems: 2, TotalBeforeFilter: 2},
+				Status:            common.ResourceStatus{Failed: 2},
 				CumulativeMetrics: make([]metric.Metric, 0),
 				Jobs: []Job{{
 					ObjectMeta: api.ObjectMeta{
@@ -0,0 +1,221 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"log"
+	"sort"
+	"strconv"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/container"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/logs"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sClient "k8s.io/client-go/kubernetes"
+	api "k8s.io/client-go/pkg/api/v1"
+)
+
+// JobCompletionIndexAnnotation is the annotation set by job controller on pods of indexed jobs.
+const JobCompletionIndexAnnotation = "batch.kubernetes.io/job-completion-index"
+
+// Statuses of a completion index of indexed job.
+const (
+	CompletionIndexPending   = "Pending"
+	CompletionIndexRunning   = "Running"
+	CompletionIndexSucceeded = "Succeeded"
+	CompletionIndexFailed    = "Failed"
+)
+
+// JobIndexedLogs are logs of an indexed job grouped per completion index.
+type JobIndexedLogs struct {
+	// Number of completion indexes in given status.
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Running   int `json:"running"`
+	Pending   int `json:"pending"`
+
+	// Completion indexes, ordered by index.
+	Indexes []JobCompletionIndex `json:"indexes"`
+}
+
+// JobCompletionIndex aggregates pods and logs of a single completion index.
+type JobCompletionIndex struct {
+	Index int `json:"index"`
+
+	// Status of the index. Index succeeded when any of its pods succeeded.
+	Status string `json:"status"`
+
+	// Pods created for the index, one per attempt, oldest first.
+	Pods []JobIndexPod `json:"pods"`
+}
+
+// JobIndexPod is a single attempt of a completion index.
+type JobIndexPod struct {
+	Name  string       `json:"name"`
+	Phase api.PodPhase `json:"phase"`
+
+	// Container that logs are for.
+	Container string `json:"container"`
+
+	// Last lines of the container log.
+	Logs logs.LogLines `json:"logs"`
+
+	// Error of log retrieval, e.g. when the pod has not started yet.
+	LogError string `json:"logError,omitempty"`
+}
+
+// GetJobIndexedLogs returns last tailLines log lines of every pod of given indexed job, grouped
+// per completion index.
+func GetJobIndexedLogs(client k8sClient.Interface, namespace, name string, tailLines int) (
+	*JobIndexedLogs, error) {
+	log.Printf("Getting logs of %s job in %s namespace per completion index", name, namespace)
+
+	job, err := client.BatchV1().Jobs(namespace).Get(name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := getRawJobPods(client, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	completions := 0
+	if job.Spec.Completions != nil {
+		completions = int(*job.Spec.Completions)
+	}
+
+	result, err := toJobIndexedLogs(pods, completions)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result.Indexes {
+		for j := range result.Indexes[i].Pods {
+			indexPod := &result.Indexes[i].Pods[j]
+			lines, err := getPodLogTail(client, namespace, indexPod.Name, indexPod.Container, tailLines)
+			if err != nil {
+				indexPod.LogError = err.Error()
+				continue
+			}
+			indexPod.Logs = lines
+		}
+	}
+
+	return result, nil
+}
+
+func getPodLogTail(client k8sClient.Interface, namespace, podName, container string,
+	tailLines int) (logs.LogLines, error) {
+	lines := int64(tailLines)
+	raw, err := client.CoreV1().Pods(namespace).GetLogs(podName, &api.PodLogOptions{
+		Container:  container,
+		Timestamps: true,
+		TailLines:  &lines,
+	}).DoRaw()
+	if err != nil {
+		return nil, err
+	}
+	return logs.ToLogLines(string(raw)), nil
+}
+
+// toJobIndexedLogs groups pods by completion index, without logs. Indexes without pods are
+// included up to given number of completions.
+func toJobIndexedLogs(pods []api.Pod, completions int) (*JobIndexedLogs, error) {
+	sort.Sort(podsByCreation(pods))
+
+	indexes := make(map[int]*JobCompletionIndex)
+	for _, pod := range pods {
+		value, ok := pod.Annotations[JobCompletionIndexAnnotation]
+		if !ok {
+			return nil, k8serrors.NewBadRequest("Job is not indexed, pod " + pod.Name +
+				" has no completion index")
+		}
+		index, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, k8serrors.NewBadRequest("Invalid completion index of pod " + pod.Name)
+		}
+
+		if _, ok := indexes[index]; !ok {
+			indexes[index] = &JobCompletionIndex{Index: index, Pods: make([]JobIndexPod, 0)}
+		}
+		indexes[index].Pods = append(indexes[index].Pods, JobIndexPod{
+			Name:      pod.Name,
+			Phase:     pod.Status.Phase,
+			Container: container.GetDefaultContainer(&pod),
+			Logs:      logs.LogLines{},
+		})
+	}
+
+	for index := 0; index < completions; index++ {
+		if _, ok := indexes[index]; !ok {
+			indexes[index] = &JobCompletionIndex{Index: index, Pods: make([]JobIndexPod, 0)}
+		}
+	}
+
+	result := &JobIndexedLogs{Indexes: make([]JobCompletionIndex, 0)}
+	for _, index := range indexes {
+		index.Status = getCompletionIndexStatus(index.Pods)
+		switch index.Status {
+		case CompletionIndexSucceeded:
+			result.Succeeded++
+		case CompletionIndexFailed:
+			result.Failed++
+		case CompletionIndexRunning:
+			result.Running++
+		default:
+			result.Pending++
+		}
+		result.Indexes = append(result.Indexes, *index)
+	}
+	sort.Sort(completionIndexesByIndex(result.Indexes))
+
+	return result, nil
+}
+
+func getCompletionIndexStatus(pods []JobIndexPod) string {
+	if len(pods) == 0 {
+		return CompletionIndexPending
+	}
+
+	status := CompletionIndexFailed
+	for _, pod := range pods {
+		switch pod.Phase {
+		case api.PodSucceeded:
+			return CompletionIndexSucceeded
+		case api.PodRunning:
+			status = CompletionIndexRunning
+		case api.PodPending, api.PodUnknown:
+			if status == CompletionIndexFailed {
+				status = CompletionIndexPending
+			}
+		}
+	}
+	return status
+}
+
+type podsByCreation []api.Pod
+
+func (self podsByCreation) Len() int      { return len(self) }
+func (self podsByCreation) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+func (self podsByCreation) Less(i, j int) bool {
+	return self[i].CreationTimestamp.Before(self[j].CreationTimestamp)
+}
+
+type completionIndexesByIndex []JobCompletionIndex
+
+func (self completionIndexesByIndex) Len() int           { return len(self) }
+func (self completionIndexesByIndex) Swap(i, j int)      { self[i], self[j] = self[j], self[i] }
+func (self completionIndexesByIndex) Less(i, j int) bool { return self[i].Index < self[j].Index }
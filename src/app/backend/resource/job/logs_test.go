@@ -0,0 +1,90 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/logs"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	api "k8s.io/client-go/pkg/api/v1"
+)
+
+func indexedPod(name, index string, phase api.PodPhase, created int64) api.Pod {
+	return api.Pod{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:              name,
+			Annotations:       map[string]string{JobCompletionIndexAnnotation: index},
+			CreationTimestamp: metaV1.Unix(created, 0),
+		},
+		Spec:   api.PodSpec{Containers: []api.Container{{Name: "main"}}},
+		Status: api.PodStatus{Phase: phase},
+	}
+}
+
+func indexPod(name string, phase api.PodPhase) JobIndexPod {
+	return JobIndexPod{Name: name, Phase: phase, Container: "main", Logs: logs.LogLines{}}
+}
+
+func TestToJobIndexedLogs(t *testing.T) {
+	cases := []struct {
+		info        string
+		pods        []api.Pod
+		completions int
+		expected    *JobIndexedLogs
+		expectError bool
+	}{
+		{
+			"pods grouped per index",
+			[]api.Pod{
+				indexedPod("job-1-retry", "1", api.PodSucceeded, 20),
+				indexedPod("job-1", "1", api.PodFailed, 10),
+				indexedPod("job-0", "0", api.PodRunning, 10),
+				indexedPod("job-3", "3", api.PodFailed, 10),
+			},
+			4,
+			&JobIndexedLogs{
+				Succeeded: 1, Failed: 1, Running: 1, Pending: 1,
+				Indexes: []JobCompletionIndex{
+					{Index: 0, Status: CompletionIndexRunning,
+						Pods: []JobIndexPod{indexPod("job-0", api.PodRunning)}},
+					{Index: 1, Status: CompletionIndexSucceeded, Pods: []JobIndexPod{
+						indexPod("job-1", api.PodFailed), indexPod("job-1-retry", api.PodSucceeded)}},
+					{Index: 2, Status: CompletionIndexPending, Pods: []JobIndexPod{}},
+					{Index: 3, Status: CompletionIndexFailed,
+						Pods: []JobIndexPod{indexPod("job-3", api.PodFailed)}},
+				},
+			},
+			false,
+		},
+		{
+			"not indexed job",
+			[]api.Pod{{ObjectMeta: metaV1.ObjectMeta{Name: "job-abc"}}},
+			1, nil, true,
+		},
+	}
+	for _, c := range cases {
+		actual, err := toJobIndexedLogs(c.pods, c.completions)
+		if (err != nil) != c.expectError {
+			t.Errorf("Test Case: %s. toJobIndexedLogs() returns error %v, expected error: %v",
+				c.info, err, c.expectError)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Test Case: %s. toJobIndexedLogs() == \ngot: %#v, \nexpected %#v",
+				c.info, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	k8sClient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	batch "k8s.io/client-go/pkg/apis/batch/v1"
+)
+
+// JobSuspended is the type of job condition set by job controller while the job is suspended.
+// Suspend field of job spec is not known to the API version used by the client, so the condition
+// is used to tell suspended jobs.
+const JobSuspended batch.JobConditionType = "Suspended"
+
+// JobSuspendStatus is the result of suspending or resuming a job.
+type JobSuspendStatus struct {
+	Suspended bool `json:"suspended"`
+}
+
+// SuspendJob sets suspend field of job spec. Suspended jobs have their active pods terminated
+// and no new pods are created until the job is resumed. Clusters not supporting job suspension
+// drop the field, in that case an error is returned.
+func SuspendJob(client k8sClient.Interface, namespace, name string, suspend bool) (*JobSuspendStatus, error) {
+	log.Printf("Setting suspend of %s job in %s namespace to %t", name, namespace, suspend)
+
+	patch := fmt.Sprintf(`{"spec":{"suspend":%t}}`, suspend)
+	raw, err := common.JSONRequest(client.BatchV1().RESTClient().Patch(types.MergePatchType).
+		Namespace(namespace).
+		Resource("jobs").
+		Name(name).
+		Body([]byte(patch))).
+		DoRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	return getSuspendStatus(raw)
+}
+
+func getSuspendStatus(raw []byte) (*JobSuspendStatus, error) {
+	job := struct {
+		Spec struct {
+			Suspend *bool `json:"suspend"`
+		} `json:"spec"`
+	}{}
+	if err := json.Unmarshal(raw, &job); err != nil {
+		return nil, err
+	}
+
+	if job.Spec.Suspend == nil {
+		return nil, k8serrors.NewBadRequest("Suspending jobs is not supported by the cluster")
+	}
+	return &JobSuspendStatus{Suspended: *job.Spec.Suspend}, nil
+}
+
+// isSuspended checks whether job controller reported the job as suspended.
+func isSuspended(job *batch.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == JobSuspended {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
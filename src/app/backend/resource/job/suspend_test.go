@@ -0,0 +1,62 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/client-go/pkg/api/v1"
+	batch "k8s.io/client-go/pkg/apis/batch/v1"
+)
+
+func TestGetSuspendStatus(t *testing.T) {
+	cases := []struct {
+		raw         string
+		expected    *JobSuspendStatus
+		expectError bool
+	}{
+		{`{"spec":{"suspend":true}}`, &JobSuspendStatus{Suspended: true}, false},
+		{`{"spec":{"suspend":false}}`, &JobSuspendStatus{Suspended: false}, false},
+		{`{"spec":{"parallelism":1}}`, nil, true},
+	}
+	for _, c := range cases {
+		actual, err := getSuspendStatus([]byte(c.raw))
+		if (err != nil) != c.expectError {
+			t.Errorf("getSuspendStatus(%s) returns error %v, expected error: %v", c.raw, err, c.expectError)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("getSuspendStatus(%s) == \ngot: %#v, \nexpected %#v", c.raw, actual, c.expected)
+		}
+	}
+}
+
+func TestIsSuspended(t *testing.T) {
+	cases := []struct {
+		conditions []batch.JobCondition
+		expected   bool
+	}{
+		{nil, false},
+		{[]batch.JobCondition{{Type: JobSuspended, Status: v1.ConditionTrue}}, true},
+		{[]batch.JobCondition{{Type: JobSuspended, Status: v1.ConditionFalse}}, false},
+		{[]batch.JobCondition{{Type: batch.JobComplete, Status: v1.ConditionTrue}}, false},
+	}
+	for _, c := range cases {
+		job := &batch.Job{Status: batch.JobStatus{Conditions: c.conditions}}
+		if actual := isSuspended(job); actual != c.expected {
+			t.Errorf("isSuspended(%#v) == %v, expected %v", c.conditions, actual, c.expected)
+		}
+	}
+}
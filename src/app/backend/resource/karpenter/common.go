@@ -0,0 +1,152 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package karpenter
+
+import (
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KarpenterGroup is the API group of Karpenter custom resources.
+const KarpenterGroup = "karpenter.sh"
+
+// nodeClassRef is a reference to the cloud provider specific node class.
+type nodeClassRef struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+func (ref nodeClassRef) String() string {
+	if ref.Kind == "" {
+		return ref.Name
+	}
+	return ref.Kind + "/" + ref.Name
+}
+
+// rawCondition is a condition of Karpenter resource.
+type rawCondition struct {
+	Type   string `json:"type"`
+	Status string `json:"status"`
+}
+
+// rawNodePool is a subset of the NodePool custom resource used by the dashboard.
+type rawNodePool struct {
+	ObjectMeta metaV1.ObjectMeta `json:"metadata"`
+	Spec       struct {
+		Template struct {
+			Spec struct {
+				NodeClassRef nodeClassRef `json:"nodeClassRef"`
+			} `json:"spec"`
+		} `json:"template"`
+		Limits     map[string]string `json:"limits"`
+		Disruption Disruption        `json:"disruption"`
+	} `json:"spec"`
+	Status struct {
+		Resources map[string]string `json:"resources"`
+	} `json:"status"`
+}
+
+type rawNodePoolList struct {
+	Items []rawNodePool `json:"items"`
+}
+
+// rawNodeClaim is a subset of the NodeClaim custom resource used by the dashboard.
+type rawNodeClaim struct {
+	ObjectMeta metaV1.ObjectMeta `json:"metadata"`
+	Spec       struct {
+		NodeClassRef nodeClassRef `json:"nodeClassRef"`
+	} `json:"spec"`
+	Status struct {
+		NodeName   string         `json:"nodeName"`
+		ProviderID string         `json:"providerID"`
+		Conditions []rawCondition `json:"conditions"`
+	} `json:"status"`
+}
+
+type rawNodeClaimList struct {
+	Items []rawNodeClaim `json:"items"`
+}
+
+// The code below allows to perform complex data section on []rawNodePool.
+
+type NodePoolCell rawNodePool
+
+func (self NodePoolCell) GetProperty(name dataselect.PropertyName) dataselect.ComparableValue {
+	switch name {
+	case dataselect.NameProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Name)
+	case dataselect.CreationTimestampProperty:
+		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
+	case dataselect.NamespaceProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.UIDProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.UID)
+	default:
+		// if name is not supported then just return a constant dummy value, sort will have no effect.
+		return nil
+	}
+}
+
+func toNodePoolCells(std []rawNodePool) []dataselect.DataCell {
+	cells := make([]dataselect.DataCell, len(std))
+	for i := range std {
+		cells[i] = NodePoolCell(std[i])
+	}
+	return cells
+}
+
+func fromNodePoolCells(cells []dataselect.DataCell) []rawNodePool {
+	std := make([]rawNodePool, len(cells))
+	for i := range std {
+		std[i] = rawNodePool(cells[i].(NodePoolCell))
+	}
+	return std
+}
+
+// The code below allows to perform complex data section on []rawNodeClaim.
+
+type NodeClaimCell rawNodeClaim
+
+func (self NodeClaimCell) GetProperty(name dataselect.PropertyName) dataselect.ComparableValue {
+	switch name {
+	case dataselect.NameProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Name)
+	case dataselect.CreationTimestampProperty:
+		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
+	case dataselect.NamespaceProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.UIDProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.UID)
+	default:
+		// if name is not supported then just return a constant dummy value, sort will have no effect.
+		return nil
+	}
+}
+
+func toNodeClaimCells(std []rawNodeClaim) []dataselect.DataCell {
+	cells := make([]dataselect.DataCell, len(std))
+	for i := range std {
+		cells[i] = NodeClaimCell(std[i])
+	}
+	return cells
+}
+
+func fromNodeClaimCells(cells []dataselect.DataCell) []rawNodeClaim {
+	std := make([]rawNodeClaim, len(cells))
+	for i := range std {
+		std[i] = rawNodeClaim(cells[i].(NodeClaimCell))
+	}
+	return std
+}
@@ -0,0 +1,181 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package karpenter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/rest"
+)
+
+func TestToNodePoolList(t *testing.T) {
+	cases := []struct {
+		raw      string
+		expected *NodePoolList
+	}{
+		{
+			`{"items": []}`,
+			&NodePoolList{NodePools: []NodePool{}},
+		},
+		{
+			`{"items": [{
+				"metadata": {"name": "default"},
+				"spec": {
+					"template": {"spec": {"nodeClassRef": {"kind": "EC2NodeClass", "name": "default"}}},
+					"limits": {"cpu": "1000"},
+					"disruption": {
+						"consolidationPolicy": "WhenUnderutilized",
+						"budgets": [{"nodes": "10%"}, {"nodes": "0", "schedule": "@daily", "duration": "10m"}]
+					}
+				},
+				"status": {"resources": {"cpu": "16"}}
+			}]}`,
+			&NodePoolList{
+				ListMeta: api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
+				NodePools: []NodePool{{
+					ObjectMeta:   api.ObjectMeta{Name: "default"},
+					TypeMeta:     api.TypeMeta{Kind: api.ResourceKindNodePool},
+					NodeClassRef: "EC2NodeClass/default",
+					Limits:       map[string]string{"cpu": "1000"},
+					Resources:    map[string]string{"cpu": "16"},
+					Disruption: Disruption{
+						ConsolidationPolicy: "WhenUnderutilized",
+						Budgets: []DisruptionBudget{
+							{Nodes: "10%"},
+							{Nodes: "0", Schedule: "@daily", Duration: "10m"},
+						},
+					},
+				}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		var list rawNodePoolList
+		if err := json.Unmarshal([]byte(c.raw), &list); err != nil {
+			t.Fatalf("json.Unmarshal(%#v) returned error: %s", c.raw, err)
+		}
+
+		actual := toNodePoolList(list.Items, dataselect.NoDataSelect)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("toNodePoolList(%#v) == \ngot: %#v, \nexpected %#v", list.Items, actual, c.expected)
+		}
+	}
+}
+
+func TestGetNodeClaims(t *testing.T) {
+	raw := `{"items": [
+		{
+			"metadata": {"name": "default-abc", "labels": {
+				"karpenter.sh/nodepool": "default",
+				"karpenter.sh/capacity-type": "spot",
+				"node.kubernetes.io/instance-type": "m5.large"
+			}},
+			"status": {"nodeName": "ip-10-0-0-1", "providerID": "aws:///i-1",
+				"conditions": [{"type": "Launched", "status": "True"}, {"type": "Ready", "status": "True"}]}
+		},
+		{
+			"metadata": {"name": "gpu-def", "labels": {"karpenter.sh/nodepool": "gpu"}}
+		}
+	]}`
+
+	var list rawNodeClaimList
+	if err := json.Unmarshal([]byte(raw), &list); err != nil {
+		t.Fatalf("json.Unmarshal(%#v) returned error: %s", raw, err)
+	}
+
+	cases := []struct {
+		nodePool string
+		expected *NodeClaimList
+	}{
+		{
+			"default",
+			&NodeClaimList{
+				ListMeta: api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
+				NodeClaims: []NodeClaim{{
+					ObjectMeta: api.ObjectMeta{Name: "default-abc", Labels: map[string]string{
+						"karpenter.sh/nodepool":            "default",
+						"karpenter.sh/capacity-type":       "spot",
+						"node.kubernetes.io/instance-type": "m5.large",
+					}},
+					TypeMeta:     api.TypeMeta{Kind: api.ResourceKindNodeClaim},
+					NodePool:     "default",
+					NodeName:     "ip-10-0-0-1",
+					ProviderID:   "aws:///i-1",
+					InstanceType: "m5.large",
+					CapacityType: "spot",
+					Ready:        v1.ConditionTrue,
+				}},
+			},
+		},
+		{
+			"gpu",
+			&NodeClaimList{
+				ListMeta: api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
+				NodeClaims: []NodeClaim{{
+					ObjectMeta: api.ObjectMeta{Name: "gpu-def",
+						Labels: map[string]string{"karpenter.sh/nodepool": "gpu"}},
+					TypeMeta: api.TypeMeta{Kind: api.ResourceKindNodeClaim},
+					NodePool: "gpu",
+					Ready:    v1.ConditionUnknown,
+				}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := toNodeClaimList(filterNodeClaimsByNodePool(list.Items, c.nodePool), dataselect.NoDataSelect)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("toNodeClaimList(%#v) == \ngot: %#v, \nexpected %#v", c.nodePool, actual, c.expected)
+		}
+	}
+}
+
+func TestGetNodePoolDetailNotInstalled(t *testing.T) {
+	requested := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		if r.URL.Path == "/apis" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"kind": "APIGroupList", "groups": []}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("Cannot create client: %v", err)
+	}
+
+	_, err = GetNodePoolDetail(client, "default")
+	if !k8serrors.IsNotFound(err) {
+		t.Errorf("GetNodePoolDetail() without Karpenter returns %v, expected not found", err)
+	}
+	for _, path := range requested {
+		if path != "/api" && path != "/apis" {
+			t.Errorf("GetNodePoolDetail() without Karpenter requests %s", path)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package karpenter
+
+import (
+	"log"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+const (
+	nodePoolLabel     = "karpenter.sh/nodepool"
+	capacityTypeLabel = "karpenter.sh/capacity-type"
+	instanceTypeLabel = "node.kubernetes.io/instance-type"
+)
+
+// NodeClaim is a presentation layer view of Karpenter NodeClaim resource, i.e. a request for
+// a single node.
+type NodeClaim struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	// Name of the node pool that created the node claim.
+	NodePool string `json:"nodePool"`
+
+	// Name of the node registered for the node claim. Empty until the node joins the cluster.
+	NodeName string `json:"nodeName"`
+
+	ProviderID   string `json:"providerID"`
+	InstanceType string `json:"instanceType"`
+	CapacityType string `json:"capacityType"`
+
+	// Status of the Ready condition of the node claim.
+	Ready v1.ConditionStatus `json:"ready"`
+}
+
+// NodeClaimList contains a list of Karpenter node claims.
+type NodeClaimList struct {
+	ListMeta   api.ListMeta `json:"listMeta"`
+	NodeClaims []NodeClaim  `json:"nodeClaims"`
+}
+
+// GetNodeClaimList returns a list of Karpenter node claims. When nodePool is not empty only node
+// claims created by given node pool are returned.
+func GetNodeClaimList(client client.Interface, dsQuery *dataselect.DataSelectQuery,
+	nodePool string) (*NodeClaimList, error) {
+	log.Print("Getting list of Karpenter node claims")
+
+	nodeClaimList := &NodeClaimList{NodeClaims: make([]NodeClaim, 0)}
+
+	groupVersion, ok, err := common.GetGroupVersion(client, KarpenterGroup)
+	if err != nil || !ok {
+		return nodeClaimList, err
+	}
+
+	var list rawNodeClaimList
+	if err := common.GetCustomResource(client, groupVersion, "", "nodeclaims", "",
+		&list); err != nil {
+		return nil, err
+	}
+
+	return toNodeClaimList(filterNodeClaimsByNodePool(list.Items, nodePool), dsQuery), nil
+}
+
+func filterNodeClaimsByNodePool(nodeClaims []rawNodeClaim, nodePool string) []rawNodeClaim {
+	if nodePool == "" {
+		return nodeClaims
+	}
+
+	result := make([]rawNodeClaim, 0)
+	for _, nodeClaim := range nodeClaims {
+		if nodeClaim.ObjectMeta.Labels[nodePoolLabel] == nodePool {
+			result = append(result, nodeClaim)
+		}
+	}
+	return result
+}
+
+func toNodeClaimList(nodeClaims []rawNodeClaim, dsQuery *dataselect.DataSelectQuery) *NodeClaimList {
+	nodeClaimList := &NodeClaimList{
+		NodeClaims: make([]NodeClaim, 0),
+		ListMeta:   api.ListMeta{TotalItems: len(nodeClaims)},
+	}
+
+	nodeClaimCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toNodeClaimCells(nodeClaims), dsQuery)
+	nodeClaims = fromNodeClaimCells(nodeClaimCells)
+	nodeClaimList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, nodeClaimList.ListMeta.TotalItems)
+
+	for _, nodeClaim := range nodeClaims {
+		nodeClaimList.NodeClaims = append(nodeClaimList.NodeClaims, toNodeClaim(nodeClaim))
+	}
+
+	return nodeClaimList
+}
+
+func toNodeClaim(nodeClaim rawNodeClaim) NodeClaim {
+	ready := v1.ConditionUnknown
+	for _, condition := range nodeClaim.Status.Conditions {
+		if condition.Type == "Ready" {
+			ready = v1.ConditionStatus(condition.Status)
+		}
+	}
+
+	return NodeClaim{
+		ObjectMeta:   api.NewObjectMeta(nodeClaim.ObjectMeta),
+		TypeMeta:     api.NewTypeMeta(api.ResourceKindNodeClaim),
+		NodePool:     nodeClaim.ObjectMeta.Labels[nodePoolLabel],
+		NodeName:     nodeClaim.Status.NodeName,
+		ProviderID:   nodeClaim.Status.ProviderID,
+		InstanceType: nodeClaim.ObjectMeta.Labels[instanceTypeLabel],
+		CapacityType: nodeClaim.ObjectMeta.Labels[capacityTypeLabel],
+		Ready:        ready,
+	}
+}
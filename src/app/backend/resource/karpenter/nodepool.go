@@ -0,0 +1,156 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package karpenter
+
+import (
+	"log"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	client "k8s.io/client-go/kubernetes"
+)
+
+// DisruptionBudget limits how many nodes of a node pool can be disrupted at the same time.
+type DisruptionBudget struct {
+	// Number or percentage of nodes that can be disrupted, e.g. 10%.
+	Nodes string `json:"nodes"`
+
+	// Cron schedule when the budget becomes active. Budget is always active when empty.
+	Schedule string `json:"schedule,omitempty"`
+
+	// Duration of the budget after it becomes active.
+	Duration string `json:"duration,omitempty"`
+
+	// Disruption reasons the budget applies to, e.g. Underutilized. Applies to all when empty.
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// Disruption describes how Karpenter disrupts nodes of a node pool.
+type Disruption struct {
+	ConsolidationPolicy string             `json:"consolidationPolicy,omitempty"`
+	ConsolidateAfter    string             `json:"consolidateAfter,omitempty"`
+	Budgets             []DisruptionBudget `json:"budgets"`
+}
+
+// NodePool is a presentation layer view of Karpenter NodePool resource.
+type NodePool struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	// Cloud provider specific node class used by the node pool, e.g. EC2NodeClass/default.
+	NodeClassRef string `json:"nodeClassRef"`
+
+	// Maximum amount of resources the node pool can provision.
+	Limits map[string]string `json:"limits"`
+
+	// Amount of resources currently provisioned by the node pool.
+	Resources map[string]string `json:"resources"`
+
+	Disruption Disruption `json:"disruption"`
+}
+
+// NodePoolList contains a list of Karpenter node pools.
+type NodePoolList struct {
+	ListMeta  api.ListMeta `json:"listMeta"`
+	NodePools []NodePool   `json:"nodePools"`
+}
+
+// NodePoolDetail contains node pool and node claims created from it.
+type NodePoolDetail struct {
+	NodePool
+	NodeClaimList NodeClaimList `json:"nodeClaimList"`
+}
+
+// GetNodePoolList returns a list of all Karpenter node pools in the cluster. Returns empty list when
+// Karpenter is not installed.
+func GetNodePoolList(client client.Interface, dsQuery *dataselect.DataSelectQuery) (*NodePoolList, error) {
+	log.Print("Getting list of all Karpenter node pools in the cluster")
+
+	nodePoolList := &NodePoolList{NodePools: make([]NodePool, 0)}
+
+	groupVersion, ok, err := common.GetGroupVersion(client, KarpenterGroup)
+	if err != nil || !ok {
+		return nodePoolList, err
+	}
+
+	var list rawNodePoolList
+	if err := common.GetCustomResource(client, groupVersion, "", "nodepools", "",
+		&list); err != nil {
+		return nil, err
+	}
+
+	return toNodePoolList(list.Items, dsQuery), nil
+}
+
+// GetNodePoolDetail returns Karpenter node pool with given name along with its node claims.
+// Returns not found error when Karpenter is not installed.
+func GetNodePoolDetail(client client.Interface, name string) (*NodePoolDetail, error) {
+	log.Printf("Getting details of %s Karpenter node pool", name)
+
+	groupVersion, err := common.GetInstalledGroupVersion(client, "nodepools", name, KarpenterGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodePool rawNodePool
+	if err := common.GetCustomResource(client, groupVersion, "", "nodepools", name,
+		&nodePool); err != nil {
+		return nil, err
+	}
+
+	nodeClaimList, err := GetNodeClaimList(client, dataselect.DefaultDataSelect, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NodePoolDetail{
+		NodePool:      toNodePool(nodePool),
+		NodeClaimList: *nodeClaimList,
+	}, nil
+}
+
+func toNodePoolList(nodePools []rawNodePool, dsQuery *dataselect.DataSelectQuery) *NodePoolList {
+	nodePoolList := &NodePoolList{
+		NodePools: make([]NodePool, 0),
+		ListMeta:  api.ListMeta{TotalItems: len(nodePools)},
+	}
+
+	nodePoolCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toNodePoolCells(nodePools), dsQuery)
+	nodePools = fromNodePoolCells(nodePoolCells)
+	nodePoolList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, nodePoolList.ListMeta.TotalItems)
+
+	for _, nodePool := range nodePools {
+		nodePoolList.NodePools = append(nodePoolList.NodePools, toNodePool(nodePool))
+	}
+
+	return nodePoolList
+}
+
+func toNodePool(nodePool rawNodePool) NodePool {
+	disruption := nodePool.Spec.Disruption
+	if disruption.Budgets == nil {
+		disruption.Budgets = make([]DisruptionBudget, 0)
+	}
+
+	return NodePool{
+		ObjectMeta:   api.NewObjectMeta(nodePool.ObjectMeta),
+		TypeMeta:     api.NewTypeMeta(api.ResourceKindNodePool),
+		NodeClassRef: nodePool.Spec.Template.Spec.NodeClassRef.String(),
+		Limits:       nodePool.Spec.Limits,
+		Resources:    nodePool.Status.Resources,
+		Disruption:   disruption,
+	}
+}
@@ -0,0 +1,338 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package labelselector
+
+import (
+	"log"
+	"sort"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+	policy "k8s.io/client-go/pkg/apis/policy/v1beta1"
+)
+
+// Resource is a resource matched by label selector.
+type Resource struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+}
+
+// LabelSelectorResult describes what given label selector selects across resource kinds.
+type LabelSelectorResult struct {
+	// Label selector in canonical form.
+	Selector string `json:"selector"`
+
+	// Resources labeled with labels matching the selector, sorted by kind, namespace and name.
+	Resources []Resource `json:"resources"`
+
+	// Services, network policies and pod disruption budgets whose selectors select at least one
+	// of pods matching the selector.
+	SelectedBy []Resource `json:"selectedBy"`
+
+	// Kinds that user is not allowed to list. They are missing in the result.
+	SkippedKinds []api.ResourceKind `json:"skippedKinds"`
+}
+
+// GetLabelSelectorResult returns every resource matching given label selector, in namespaces
+// given by namespace query, together with resources that select matching pods.
+func GetLabelSelectorResult(client client.Interface, nsQuery *common.NamespaceQuery,
+	rawSelector string) (*LabelSelectorResult, error) {
+
+	if len(rawSelector) == 0 {
+		return nil, errors.NewBadRequest("Label selector is required")
+	}
+	selector, err := labels.Parse(rawSelector)
+	if err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+	log.Printf("Getting resources matching label selector %s", selector.String())
+
+	// Kinds that can select pods are listed in full, other kinds are filtered by the API server.
+	options := metaV1.ListOptions{
+		LabelSelector: selector.String(),
+		FieldSelector: fields.Everything().String(),
+	}
+	channels := &common.ResourceChannels{
+		PodList:        common.GetPodListChannelWithOptions(client, nsQuery, options, 1),
+		ServiceList:    common.GetServiceListChannel(client, nsQuery, 1),
+		DeploymentList: common.GetDeploymentListChannelWithOptions(client, nsQuery, options, 1),
+		ReplicaSetList: common.GetReplicaSetListChannelWithOptions(client, nsQuery, options, 1),
+		ReplicationControllerList: common.GetReplicationControllerListChannelWithOptions(client,
+			nsQuery, options, 1),
+		DaemonSetList:   common.GetDaemonSetListChannelWithOptions(client, nsQuery, options, 1),
+		StatefulSetList: common.GetStatefulSetListChannelWithOptions(client, nsQuery, options, 1),
+		JobList:         common.GetJobListChannelWithOptions(client, nsQuery, options, 1),
+		ConfigMapList:   common.GetConfigMapListChannelWithOptions(client, nsQuery, options, 1),
+		SecretList:      common.GetSecretListChannelWithOptions(client, nsQuery, options, 1),
+		PersistentVolumeClaimList: common.GetPersistentVolumeClaimListChannelWithOptions(client,
+			nsQuery, options, 1),
+		NetworkPolicyList:       common.GetNetworkPolicyListChannel(client, nsQuery, 1),
+		PodDisruptionBudgetList: common.GetPodDisruptionBudgetListChannel(client, nsQuery, 1),
+	}
+
+	result := &LabelSelectorResult{
+		Selector:     selector.String(),
+		Resources:    make([]Resource, 0),
+		SelectedBy:   make([]Resource, 0),
+		SkippedKinds: make([]api.ResourceKind, 0),
+	}
+	// Matches items of kinds listed in full.
+	matches := func(meta metaV1.ObjectMeta) bool {
+		return nsQuery.Matches(meta.Namespace) && selector.Matches(labels.Set(meta.Labels))
+	}
+
+	pods := <-channels.PodList.List
+	matchingPods := make([]v1.Pod, 0)
+	if visible, err := result.visible(api.ResourceKindPod, <-channels.PodList.Error); err != nil {
+		return nil, err
+	} else if visible {
+		matchingPods = pods.Items
+		for _, item := range pods.Items {
+			result.add(api.ResourceKindPod, item.ObjectMeta)
+		}
+	}
+
+	services := <-channels.ServiceList.List
+	if visible, err := result.visible(api.ResourceKindService, <-channels.ServiceList.Error); err != nil {
+		return nil, err
+	} else if visible {
+		for _, item := range services.Items {
+			if matches(item.ObjectMeta) {
+				result.add(api.ResourceKindService, item.ObjectMeta)
+			}
+		}
+		result.SelectedBy = append(result.SelectedBy, getSelectingServices(services.Items, matchingPods)...)
+	}
+
+	deployments := <-channels.DeploymentList.List
+	if visible, err := result.visible(api.ResourceKindDeployment, <-channels.DeploymentList.Error); err != nil {
+		return nil, err
+	} else if visible {
+		for _, item := range deployments.Items {
+			result.add(api.ResourceKindDeployment, item.ObjectMeta)
+		}
+	}
+
+	replicaSets := <-channels.ReplicaSetList.List
+	if visible, err := result.visible(api.ResourceKindReplicaSet, <-channels.ReplicaSetList.Error); err != nil {
+		return nil, err
+	} else if visible {
+		for _, item := range replicaSets.Items {
+			result.add(api.ResourceKindReplicaSet, item.ObjectMeta)
+		}
+	}
+
+	rcs := <-channels.ReplicationControllerList.List
+	if visible, err := result.visible(api.ResourceKindReplicationController,
+		<-channels.ReplicationControllerList.Error); err != nil {
+		return nil, err
+	} else if visible {
+		for _, item := range rcs.Items {
+			result.add(api.ResourceKindReplicationController, item.ObjectMeta)
+		}
+	}
+
+	daemonSets := <-channels.DaemonSetList.List
+	if visible, err := result.visible(api.ResourceKindDaemonSet, <-channels.DaemonSetList.Error); err != nil {
+		return nil, err
+	} else if visible {
+		for _, item := range daemonSets.Items {
+			result.add(api.ResourceKindDaemonSet, item.ObjectMeta)
+		}
+	}
+
+	statefulSets := <-channels.StatefulSetList.List
+	if visible, err := result.visible(api.ResourceKindStatefulSet, <-channels.StatefulSetList.Error); err != nil {
+		return nil, err
+	} else if visible {
+		for _, item := range statefulSets.Items {
+			result.add(api.ResourceKindStatefulSet, item.ObjectMeta)
+		}
+	}
+
+	jobs := <-channels.JobList.List
+	if visible, err := result.visible(api.ResourceKindJob, <-channels.JobList.Error); err != nil {
+		return nil, err
+	} else if visible {
+		for _, item := range jobs.Items {
+			result.add(api.ResourceKindJob, item.ObjectMeta)
+		}
+	}
+
+	configMaps := <-channels.ConfigMapList.List
+	if visible, err := result.visible(api.ResourceKindConfigMap, <-channels.ConfigMapList.Error); err != nil {
+		return nil, err
+	} else if visible {
+		for _, item := range configMaps.Items {
+			result.add(api.ResourceKindConfigMap, item.ObjectMeta)
+		}
+	}
+
+	secrets := <-channels.SecretList.List
+	if visible, err := result.visible(api.ResourceKindSecret, <-channels.SecretList.Error); err != nil {
+		return nil, err
+	} else if visible {
+		for _, item := range secrets.Items {
+			result.add(api.ResourceKindSecret, item.ObjectMeta)
+		}
+	}
+
+	pvcs := <-channels.PersistentVolumeClaimList.List
+	if visible, err := result.visible(api.ResourceKindPersistentVolumeClaim,
+		<-channels.PersistentVolumeClaimList.Error); err != nil {
+		return nil, err
+	} else if visible {
+		for _, item := range pvcs.Items {
+			result.add(api.ResourceKindPersistentVolumeClaim, item.ObjectMeta)
+		}
+	}
+
+	networkPolicies := <-channels.NetworkPolicyList.List
+	if visible, err := result.visible(api.ResourceKindNetworkPolicy,
+		<-channels.NetworkPolicyList.Error); err != nil {
+		return nil, err
+	} else if visible {
+		for _, item := range networkPolicies.Items {
+			if matches(item.ObjectMeta) {
+				result.add(api.ResourceKindNetworkPolicy, item.ObjectMeta)
+			}
+		}
+		result.SelectedBy = append(result.SelectedBy,
+			getSelectingNetworkPolicies(networkPolicies.Items, matchingPods)...)
+	}
+
+	pdbs := <-channels.PodDisruptionBudgetList.List
+	if visible, err := result.visible(api.ResourceKindPodDisruptionBudget,
+		<-channels.PodDisruptionBudgetList.Error); err != nil {
+		return nil, err
+	} else if visible {
+		for _, item := range pdbs.Items {
+			if matches(item.ObjectMeta) {
+				result.add(api.ResourceKindPodDisruptionBudget, item.ObjectMeta)
+			}
+		}
+		result.SelectedBy = append(result.SelectedBy,
+			getSelectingPodDisruptionBudgets(pdbs.Items, matchingPods)...)
+	}
+
+	sort.Sort(resourcesByKindAndName(result.Resources))
+	sort.Sort(resourcesByKindAndName(result.SelectedBy))
+	return result, nil
+}
+
+// visible returns true when list of given kind was read without error. Lists that user is not
+// allowed to read are recorded as skipped, other errors are returned.
+func (self *LabelSelectorResult) visible(kind api.ResourceKind, err error) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+	if errors.IsForbidden(err) || errors.IsNotFound(err) {
+		// Not found is returned by clusters that don't serve the kind, e.g. network policies.
+		self.SkippedKinds = append(self.SkippedKinds, kind)
+		return false, nil
+	}
+	return false, err
+}
+
+func (self *LabelSelectorResult) add(kind api.ResourceKind, meta metaV1.ObjectMeta) {
+	self.Resources = append(self.Resources, toResource(kind, meta))
+}
+
+func toResource(kind api.ResourceKind, meta metaV1.ObjectMeta) Resource {
+	return Resource{
+		ObjectMeta: api.NewObjectMeta(meta),
+		TypeMeta:   api.NewTypeMeta(kind),
+	}
+}
+
+// getSelectingServices returns services that select at least one of given pods. Services
+// without selector don't select pods.
+func getSelectingServices(services []v1.Service, pods []v1.Pod) []Resource {
+	result := make([]Resource, 0)
+	for _, service := range services {
+		if len(service.Spec.Selector) == 0 {
+			continue
+		}
+		if selectsAny(service.Namespace, labels.SelectorFromSet(service.Spec.Selector), pods) {
+			result = append(result, toResource(api.ResourceKindService, service.ObjectMeta))
+		}
+	}
+	return result
+}
+
+// getSelectingNetworkPolicies returns network policies that select at least one of given pods.
+func getSelectingNetworkPolicies(policies []extensions.NetworkPolicy, pods []v1.Pod) []Resource {
+	result := make([]Resource, 0)
+	for _, networkPolicy := range policies {
+		selector, err := metaV1.LabelSelectorAsSelector(&networkPolicy.Spec.PodSelector)
+		if err != nil {
+			continue
+		}
+		if selectsAny(networkPolicy.Namespace, selector, pods) {
+			result = append(result, toResource(api.ResourceKindNetworkPolicy, networkPolicy.ObjectMeta))
+		}
+	}
+	return result
+}
+
+// getSelectingPodDisruptionBudgets returns pod disruption budgets that select at least one of
+// given pods.
+func getSelectingPodDisruptionBudgets(pdbs []policy.PodDisruptionBudget, pods []v1.Pod) []Resource {
+	result := make([]Resource, 0)
+	for _, pdb := range pdbs {
+		if pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metaV1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			continue
+		}
+		if selectsAny(pdb.Namespace, selector, pods) {
+			result = append(result, toResource(api.ResourceKindPodDisruptionBudget, pdb.ObjectMeta))
+		}
+	}
+	return result
+}
+
+// selectsAny returns true if selector selects at least one of given pods in given namespace.
+func selectsAny(namespace string, selector labels.Selector, pods []v1.Pod) bool {
+	for _, pod := range pods {
+		if pod.Namespace == namespace && selector.Matches(labels.Set(pod.Labels)) {
+			return true
+		}
+	}
+	return false
+}
+
+type resourcesByKindAndName []Resource
+
+func (self resourcesByKindAndName) Len() int      { return len(self) }
+func (self resourcesByKindAndName) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+func (self resourcesByKindAndName) Less(i, j int) bool {
+	if self[i].TypeMeta.Kind != self[j].TypeMeta.Kind {
+		return self[i].TypeMeta.Kind < self[j].TypeMeta.Kind
+	}
+	if self[i].ObjectMeta.Namespace != self[j].ObjectMeta.Namespace {
+		return self[i].ObjectMeta.Namespace < self[j].ObjectMeta.Namespace
+	}
+	return self[i].ObjectMeta.Name < self[j].ObjectMeta.Name
+}
@@ -0,0 +1,143 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package labelselector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+	policy "k8s.io/client-go/pkg/apis/policy/v1beta1"
+	"k8s.io/client-go/rest"
+)
+
+var testPods = []v1.Pod{
+	{ObjectMeta: metaV1.ObjectMeta{Name: "foo-1", Namespace: "ns-1", Labels: map[string]string{"app": "foo", "tier": "web"}}},
+	{ObjectMeta: metaV1.ObjectMeta{Name: "foo-2", Namespace: "ns-2", Labels: map[string]string{"app": "foo"}}},
+}
+
+func TestGetSelectingServices(t *testing.T) {
+	services := []v1.Service{
+		{ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "ns-1"},
+			Spec: v1.ServiceSpec{Selector: map[string]string{"tier": "web"}}},
+		{ObjectMeta: metaV1.ObjectMeta{Name: "other-ns", Namespace: "ns-3"},
+			Spec: v1.ServiceSpec{Selector: map[string]string{"app": "foo"}}},
+		{ObjectMeta: metaV1.ObjectMeta{Name: "no-selector", Namespace: "ns-1"}},
+	}
+	expected := []Resource{toResource(api.ResourceKindService, services[0].ObjectMeta)}
+
+	actual := getSelectingServices(services, testPods)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getSelectingServices() == \ngot: %#v, \nexpected %#v", actual, expected)
+	}
+}
+
+func TestGetSelectingNetworkPolicies(t *testing.T) {
+	policies := []extensions.NetworkPolicy{
+		{ObjectMeta: metaV1.ObjectMeta{Name: "all", Namespace: "ns-2"}},
+		{ObjectMeta: metaV1.ObjectMeta{Name: "db", Namespace: "ns-1"},
+			Spec: extensions.NetworkPolicySpec{
+				PodSelector: metaV1.LabelSelector{MatchLabels: map[string]string{"tier": "db"}}}},
+	}
+	expected := []Resource{toResource(api.ResourceKindNetworkPolicy, policies[0].ObjectMeta)}
+
+	actual := getSelectingNetworkPolicies(policies, testPods)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getSelectingNetworkPolicies() == \ngot: %#v, \nexpected %#v", actual, expected)
+	}
+}
+
+func TestGetSelectingPodDisruptionBudgets(t *testing.T) {
+	pdbs := []policy.PodDisruptionBudget{
+		{ObjectMeta: metaV1.ObjectMeta{Name: "no-selector", Namespace: "ns-1"}},
+		{ObjectMeta: metaV1.ObjectMeta{Name: "foo", Namespace: "ns-1"},
+			Spec: policy.PodDisruptionBudgetSpec{Selector: &metaV1.LabelSelector{
+				MatchExpressions: []metaV1.LabelSelectorRequirement{{
+					Key: "app", Operator: metaV1.LabelSelectorOpIn, Values: []string{"foo", "bar"}}}}}},
+	}
+	expected := []Resource{toResource(api.ResourceKindPodDisruptionBudget, pdbs[1].ObjectMeta)}
+
+	actual := getSelectingPodDisruptionBudgets(pdbs, testPods)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getSelectingPodDisruptionBudgets() == \ngot: %#v, \nexpected %#v", actual, expected)
+	}
+}
+
+func TestResourcesByKindAndName(t *testing.T) {
+	resources := []Resource{
+		toResource(api.ResourceKindService, metaV1.ObjectMeta{Name: "b", Namespace: "ns-1"}),
+		toResource(api.ResourceKindPod, metaV1.ObjectMeta{Name: "a", Namespace: "ns-2"}),
+		toResource(api.ResourceKindService, metaV1.ObjectMeta{Name: "a", Namespace: "ns-1"}),
+		toResource(api.ResourceKindPod, metaV1.ObjectMeta{Name: "b", Namespace: "ns-1"}),
+	}
+	expected := []Resource{resources[3], resources[1], resources[2], resources[0]}
+
+	sort.Sort(resourcesByKindAndName(resources))
+	if !reflect.DeepEqual(resources, expected) {
+		t.Errorf("sort.Sort(resourcesByKindAndName) == \ngot: %#v, \nexpected %#v", resources, expected)
+	}
+}
+
+func TestGetLabelSelectorResultListOptions(t *testing.T) {
+	selectors := make(map[string]string)
+	var lock sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		selectors[path.Base(r.URL.Path)] = r.URL.Query().Get("labelSelector")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"metadata": {}, "items": []}`))
+	}))
+	defer server.Close()
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("Cannot create client: %v", err)
+	}
+
+	if _, err := GetLabelSelectorResult(client, common.NewNamespaceQuery(nil), "app=foo"); err != nil {
+		t.Fatalf("GetLabelSelectorResult() returns error: %v", err)
+	}
+
+	// Only kinds that can select pods are listed in full.
+	expected := map[string]string{
+		"pods":                   "app=foo",
+		"services":               "",
+		"deployments":            "app=foo",
+		"replicasets":            "app=foo",
+		"replicationcontrollers": "app=foo",
+		"daemonsets":             "app=foo",
+		"statefulsets":           "app=foo",
+		"jobs":                   "app=foo",
+		"configmaps":             "app=foo",
+		"secrets":                "app=foo",
+		"persistentvolumeclaims": "app=foo",
+		"networkpolicies":        "",
+		"poddisruptionbudgets":   "",
+	}
+	if !reflect.DeepEqual(selectors, expected) {
+		t.Errorf("GetLabelSelectorResult() lists with label selectors \ngot: %#v, \nexpected %#v",
+			selectors, expected)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Keys of JSON log entries recognized as severity level, in order of precedence.
+var levelFieldNames = []string{"level", "lvl", "severity"}
+
+// LogFilter describes server side filtering of log lines.
+type LogFilter struct {
+	// Parse JSON log lines into fields and level.
+	Structured bool
+
+	// Only lines with one of the given levels are returned. Matched case insensitively.
+	Levels []string
+
+	// Only lines having all of the given field values are returned.
+	Fields map[string]string
+
+	// Only lines containing given text are returned. Matched case insensitively.
+	Search string
+
+	// Only lines written in given time range are returned. Zero value means unbounded.
+	Since time.Time
+	Until time.Time
+}
+
+// IsEmpty returns true if the filter does not change log lines.
+func (self *LogFilter) IsEmpty() bool {
+	return self == nil || (!self.Structured && len(self.Levels) == 0 && len(self.Fields) == 0 &&
+		self.Search == "" && self.Since.IsZero() && self.Until.IsZero())
+}
+
+// Filter returns log lines matching given filter. When filter requires structured logs, JSON log
+// lines are parsed and their level and fields are filled in.
+func (self LogLines) Filter(filter *LogFilter) LogLines {
+	if filter.IsEmpty() {
+		return self
+	}
+
+	structured := filter.Structured || len(filter.Levels) > 0 || len(filter.Fields) > 0
+	search := strings.ToLower(filter.Search)
+
+	result := LogLines{}
+	for _, line := range self {
+		if structured {
+			line = line.parseStructured()
+		}
+		if filter.matches(line, search) {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+func (self *LogFilter) matches(line LogLine, search string) bool {
+	if search != "" && !strings.Contains(strings.ToLower(line.Content), search) {
+		return false
+	}
+
+	if !self.Since.IsZero() || !self.Until.IsZero() {
+		timestamp, err := time.Parse(time.RFC3339Nano, string(line.Timestamp))
+		if err != nil {
+			return false
+		}
+		if !self.Since.IsZero() && timestamp.Before(self.Since) {
+			return false
+		}
+		if !self.Until.IsZero() && timestamp.After(self.Until) {
+			return false
+		}
+	}
+
+	if len(self.Levels) > 0 {
+		matched := false
+		for _, level := range self.Levels {
+			if strings.EqualFold(level, line.Level) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	for key, value := range self.Fields {
+		fieldValue, ok := line.Fields[key]
+		if !ok || fmt.Sprint(fieldValue) != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseStructured fills level and fields of the line if its content is a JSON object.
+func (self LogLine) parseStructured() LogLine {
+	content := strings.TrimSpace(self.Content)
+	if !strings.HasPrefix(content, "{") {
+		return self
+	}
+
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(content), &fields); err != nil {
+		return self
+	}
+
+	self.Fields = fields
+	for _, name := range levelFieldNames {
+		if level, ok := fields[name].(string); ok {
+			self.Level = strings.ToLower(level)
+			break
+		}
+	}
+	return self
+}
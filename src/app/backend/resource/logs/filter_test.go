@@ -0,0 +1,85 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFilter(t *testing.T) {
+	lines := LogLines{
+		{Timestamp: "2017-05-05T10:00:00Z", Content: `{"level": "INFO", "msg": "started", "port": 8080}`},
+		{Timestamp: "2017-05-05T10:00:01Z", Content: `{"level": "error", "msg": "connection refused"}`},
+		{Timestamp: "2017-05-05T10:00:02Z", Content: "plain text Connection reset"},
+	}
+
+	cases := []struct {
+		info     string
+		filter   *LogFilter
+		expected LogLines
+	}{
+		{"no filter", nil, lines},
+		{"empty filter", &LogFilter{}, lines},
+		{
+			"structured",
+			&LogFilter{Structured: true},
+			LogLines{
+				{Timestamp: "2017-05-05T10:00:00Z", Content: lines[0].Content, Level: "info",
+					Fields: map[string]interface{}{"level": "INFO", "msg": "started", "port": float64(8080)}},
+				{Timestamp: "2017-05-05T10:00:01Z", Content: lines[1].Content, Level: "error",
+					Fields: map[string]interface{}{"level": "error", "msg": "connection refused"}},
+				lines[2],
+			},
+		},
+		{
+			"level",
+			&LogFilter{Levels: []string{"ERROR"}},
+			LogLines{
+				{Timestamp: "2017-05-05T10:00:01Z", Content: lines[1].Content, Level: "error",
+					Fields: map[string]interface{}{"level": "error", "msg": "connection refused"}},
+			},
+		},
+		{
+			"field",
+			&LogFilter{Fields: map[string]string{"port": "8080"}},
+			LogLines{
+				{Timestamp: "2017-05-05T10:00:00Z", Content: lines[0].Content, Level: "info",
+					Fields: map[string]interface{}{"level": "INFO", "msg": "started", "port": float64(8080)}},
+			},
+		},
+		{
+			"search",
+			&LogFilter{Search: "connection"},
+			LogLines{lines[1], lines[2]},
+		},
+		{
+			"time range",
+			&LogFilter{
+				Since: time.Date(2017, 5, 5, 10, 0, 1, 0, time.UTC),
+				Until: time.Date(2017, 5, 5, 10, 0, 1, 0, time.UTC),
+			},
+			LogLines{lines[1]},
+		},
+	}
+
+	for _, c := range cases {
+		actual := lines.Filter(c.filter)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Test Case: %s.\nReceived: %#v \nExpected: %#v\n\n", c.info, actual, c.expected)
+		}
+	}
+}
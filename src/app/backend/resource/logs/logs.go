@@ -16,6 +16,7 @@ package logs
 
 import (
 	"strings"
+	"time"
 )
 
 // LINE_INDEX_NOT_FOUND is returned if requested line could not be found
@@ -24,6 +25,9 @@ var LINE_INDEX_NOT_FOUND = -1
 // Default number of lines that should be returned in case of invalid request.
 var DefaultDisplayNumLogLines = 100
 
+// MaxTailLines is the maximum number of last log lines that can be requested.
+var MaxTailLines = 10000
+
 // MaxLogLines is a number that will be certainly bigger than any number of logs. Here 2 billion logs is certainly much larger
 // number of log lines than we can handle.
 var MaxLogLines int = 2000000000
@@ -69,6 +73,12 @@ type LogDetails struct {
 
 	// Actual log lines of this page
 	LogLines `json:"logs"`
+
+	// Token of the page of logs preceding this one. Empty if there are no logs.
+	PreviousPageToken string `json:"previousPageToken,omitempty"`
+
+	// Token of the page of logs following this one. Empty if there are no logs.
+	NextPageToken string `json:"nextPageToken,omitempty"`
 }
 
 // Meta information about the selected log lines
@@ -99,6 +109,9 @@ type Selection struct {
 	OffsetFrom int `json:"offsetFrom"`
 	// Last index of the slice relatively to the reference line (this one will not be included).
 	OffsetTo int `json:"offsetTo"`
+	// Exact disables shifting of the slice when it reaches the beginning or the end of logs. Slice is
+	// truncated instead, so that pages selected with page tokens never overlap.
+	Exact bool `json:"-"`
 }
 
 // LogLineId uniquely identifies a line in logs - immune to log addition/deletion.
@@ -127,6 +140,12 @@ type LogLines []LogLine
 type LogLine struct {
 	Timestamp LogTimestamp `json:"timestamp"`
 	Content   string       `json:"content"`
+
+	// Severity level of structured log line, e.g. error. Empty if unknown.
+	Level string `json:"level,omitempty"`
+
+	// Fields of structured (JSON) log line. Filled only when structured logs are requested.
+	Fields map[string]interface{} `json:"fields,omitempty"`
 }
 
 // LogTimestamp is a timestamp that appears on the beginning of each log line.
@@ -143,7 +162,17 @@ func (self LogLines) SelectLogs(logSelection *Selection) (LogLines, LogTimestamp
 	}
 	fromIndex := referenceLineIndex + logSelection.OffsetFrom
 	toIndex := referenceLineIndex + logSelection.OffsetTo
-	if requestedNumItems > len(self) {
+	if logSelection.Exact {
+		if fromIndex < 0 {
+			fromIndex = 0
+		}
+		if toIndex > len(self) {
+			toIndex = len(self)
+		}
+		if fromIndex >= toIndex {
+			return LogLines{}, "", "", Selection{}
+		}
+	} else if requestedNumItems > len(self) {
 		fromIndex = 0
 		toIndex = len(self)
 	} else if toIndex > len(self) {
@@ -171,6 +200,10 @@ func (self LogLines) getLineIndex(logLineId *LogLineId) int {
 		return 0
 	}
 	logTimestamp := logLineId.LogTimestamp
+	if logLineId.LineNum == 0 {
+		// No particular line requested, use the first line logged at or after given timestamp.
+		return self.getLineIndexAfter(logTimestamp)
+	}
 	linesMatched := 0
 	matchingStartedAt := 0
 	for idx := range self { // todo use binary search to speedup log search (compare timestamps).
@@ -196,6 +229,21 @@ func (self LogLines) getLineIndex(logLineId *LogLineId) int {
 	}
 }
 
+// getLineIndexAfter returns the index of the first line with timestamp equal to or later than provided one.
+func (self LogLines) getLineIndexAfter(logTimestamp LogTimestamp) int {
+	reference, err := time.Parse(time.RFC3339Nano, string(logTimestamp))
+	if err != nil {
+		return LINE_INDEX_NOT_FOUND
+	}
+	for idx := range self {
+		timestamp, err := time.Parse(time.RFC3339Nano, string(self[idx].Timestamp))
+		if err == nil && !timestamp.Before(reference) {
+			return idx
+		}
+	}
+	return LINE_INDEX_NOT_FOUND
+}
+
 // CreateLogLineId returns ID of the line with provided lineIndex.
 func (self LogLines) createLogLineId(lineIndex int) *LogLineId {
 	logTimestamp := self[lineIndex].Timestamp
@@ -0,0 +1,108 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// pageToken is a serialized form of page reference. Pages are referenced by ID of the boundary line
+// of the current page rather than by offset, so tokens stay valid as lines are appended or removed.
+type pageToken struct {
+	// ID of the last line of current page for next page tokens and of the first line for previous
+	// page tokens.
+	ReferencePoint LogLineId `json:"r"`
+	// Whether the token points to the page following the reference line.
+	Next bool `json:"n"`
+	// Number of lines on the page.
+	Size int `json:"s"`
+}
+
+// NewContextSelection returns selection of context lines preceding and following the first line
+// logged at or after given timestamp.
+func NewContextSelection(timestamp LogTimestamp, context int) *Selection {
+	return &Selection{
+		ReferencePoint: LogLineId{LogTimestamp: timestamp},
+		OffsetFrom:     -context,
+		OffsetTo:       context + 1,
+		Exact:          true,
+	}
+}
+
+// ParsePageToken converts page token returned with log details back to a selection.
+func ParsePageToken(token string) (*Selection, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.New("Invalid page token")
+	}
+
+	page := pageToken{}
+	if err := json.Unmarshal(raw, &page); err != nil || page.Size <= 0 ||
+		page.ReferencePoint.LogTimestamp == "" {
+		return nil, errors.New("Invalid page token")
+	}
+
+	if page.Next {
+		return &Selection{
+			ReferencePoint: page.ReferencePoint,
+			OffsetFrom:     1,
+			OffsetTo:       page.Size + 1,
+			Exact:          true,
+		}, nil
+	}
+	return &Selection{
+		ReferencePoint: page.ReferencePoint,
+		OffsetFrom:     -page.Size,
+		OffsetTo:       0,
+		Exact:          true,
+	}, nil
+}
+
+// PageTokens returns tokens of pages preceding and following the page described by selection, as
+// returned by SelectLogs. Both are empty if selection is empty. Previous is empty if selection
+// starts at the first line, as there are no preceding lines.
+func (self LogLines) PageTokens(selection Selection) (previous string, next string) {
+	size := selection.OffsetTo - selection.OffsetFrom
+	referenceLineIndex := self.getLineIndex(&selection.ReferencePoint)
+	if size <= 0 || referenceLineIndex == LINE_INDEX_NOT_FOUND {
+		return "", ""
+	}
+
+	fromIndex := referenceLineIndex + selection.OffsetFrom
+	toIndex := referenceLineIndex + selection.OffsetTo
+	if fromIndex < 0 || toIndex > len(self) {
+		return "", ""
+	}
+
+	if fromIndex > 0 {
+		previous = encodePageToken(pageToken{
+			ReferencePoint: *self.createLogLineId(fromIndex),
+			Size:           size,
+		})
+	}
+	next = encodePageToken(pageToken{
+		ReferencePoint: *self.createLogLineId(toIndex - 1),
+		Next:           true,
+		Size:           size,
+	})
+	return previous, next
+}
+
+func encodePageToken(page pageToken) string {
+	raw, _ := json.Marshal(page)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
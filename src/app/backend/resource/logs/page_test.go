@@ -0,0 +1,109 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"reflect"
+	"testing"
+)
+
+var pageTestLines = LogLines{
+	{Timestamp: "2017-05-05T10:00:00Z", Content: "a"},
+	{Timestamp: "2017-05-05T10:00:01Z", Content: "b"},
+	{Timestamp: "2017-05-05T10:00:01Z", Content: "c"},
+	{Timestamp: "2017-05-05T10:00:02.5Z", Content: "d"},
+	{Timestamp: "2017-05-05T10:00:03Z", Content: "e"},
+	{Timestamp: "2017-05-05T10:00:04Z", Content: "f"},
+}
+
+func contents(lines LogLines) []string {
+	result := []string{}
+	for _, line := range lines {
+		result = append(result, line.Content)
+	}
+	return result
+}
+
+func TestNewContextSelection(t *testing.T) {
+	cases := []struct {
+		timestamp LogTimestamp
+		context   int
+		expected  []string
+	}{
+		{"2017-05-05T10:00:02Z", 1, []string{"c", "d", "e"}},
+		{"2017-05-05T10:00:01Z", 0, []string{"b"}},
+		{"2017-05-05T09:00:00Z", 2, []string{"a", "b", "c"}},
+		{"2017-05-05T10:00:04Z", 2, []string{"d", "e", "f"}},
+		{"2017-05-05T11:00:00Z", 2, []string{}},
+		{"invalid", 2, []string{}},
+	}
+	for _, c := range cases {
+		actual, _, _, _ := pageTestLines.SelectLogs(NewContextSelection(c.timestamp, c.context))
+		if !reflect.DeepEqual(contents(actual), c.expected) {
+			t.Errorf("SelectLogs(NewContextSelection(%#v, %#v)) == \ngot: %#v, \nexpected %#v",
+				c.timestamp, c.context, contents(actual), c.expected)
+		}
+	}
+}
+
+func TestPageTokens(t *testing.T) {
+	_, _, _, selection := pageTestLines.SelectLogs(&Selection{
+		ReferencePoint: LogLineId{LogTimestamp: "2017-05-05T10:00:01Z", LineNum: 2},
+		OffsetFrom:     0,
+		OffsetTo:       2,
+	})
+	previous, next := pageTestLines.PageTokens(selection)
+
+	// Lines appended after the first request must not affect pages.
+	appended := append(append(LogLines{}, pageTestLines...),
+		LogLine{Timestamp: "2017-05-05T10:00:05Z", Content: "g"})
+
+	cases := []struct {
+		token    string
+		expected []string
+	}{
+		{previous, []string{"a", "b"}},
+		{next, []string{"e", "f"}},
+	}
+	for _, c := range cases {
+		selector, err := ParsePageToken(c.token)
+		if err != nil {
+			t.Fatalf("ParsePageToken(%#v) returns error %v", c.token, err)
+		}
+		actual, _, _, _ := appended.SelectLogs(selector)
+		if !reflect.DeepEqual(contents(actual), c.expected) {
+			t.Errorf("SelectLogs(ParsePageToken(%#v)) == \ngot: %#v, \nexpected %#v",
+				c.token, contents(actual), c.expected)
+		}
+	}
+
+	// Pages following the newest line are empty rather than overlapping the current page. There is
+	// no page preceding the oldest line.
+	_, _, _, selection = pageTestLines.SelectLogs(DefaultSelection)
+	previous, next = pageTestLines.PageTokens(selection)
+	if previous != "" {
+		t.Errorf("PageTokens() of selection starting at the first line returns previous %#v, "+
+			"expected none", previous)
+	}
+	selector, _ := ParsePageToken(next)
+	actual, _, _, _ := pageTestLines.SelectLogs(selector)
+	if len(actual) != 0 {
+		t.Errorf("SelectLogs(ParsePageToken(%#v)) == \ngot: %#v, \nexpected no lines", next, actual)
+	}
+
+	if _, err := ParsePageToken("invalid"); err == nil {
+		t.Errorf("ParsePageToken(%#v) expected to return error", "invalid")
+	}
+}
@@ -24,6 +24,8 @@ const (
 	SumAggregation     = "sum"
 	MaxAggregation     = "max"
 	MinAggregation     = "min"
+	AverageAggregation = "average"
+	P95Aggregation     = "p95"
 	DefaultAggregation = "sum"
 )
 
@@ -33,9 +35,11 @@ var OnlySumAggregation = AggregationNames{SumAggregation}
 var OnlyDefaultAggregation = AggregationNames{DefaultAggregation}
 
 var AggregatingFunctions = map[AggregationName]func([]int64) int64{
-	SumAggregation: SumAggregate,
-	MaxAggregation: MaxAggregate,
-	MinAggregation: MinAggregate,
+	SumAggregation:     SumAggregate,
+	MaxAggregation:     MaxAggregate,
+	MinAggregation:     MinAggregate,
+	AverageAggregation: AverageAggregate,
+	P95Aggregation:     P95Aggregate,
 }
 
 // SortableInt64 implements sort.Interface for []int64. This allows to use built in sort with int64.
@@ -127,3 +131,17 @@ func MinAggregate(values []int64) int64 {
 	}
 	return result
 }
+
+func AverageAggregate(values []int64) int64 {
+	return SumAggregate(values) / int64(len(values))
+}
+
+// P95Aggregate returns the 95th percentile of values, using the nearest-rank method.
+func P95Aggregate(values []int64) int64 {
+	sorted := make(SortableInt64, len(values))
+	copy(sorted, values)
+	sort.Sort(sorted)
+	// nearest rank ceil(0.95 * n) is indexed from 1
+	rank := (95*len(sorted) + 99) / 100
+	return sorted[rank-1]
+}
@@ -0,0 +1,34 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import "testing"
+
+func TestP95Aggregate(t *testing.T) {
+	cases := []struct {
+		values   []int64
+		expected int64
+	}{
+		{[]int64{7}, 7},
+		{[]int64{3, 1, 2}, 3},
+		{[]int64{20, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19}, 19},
+	}
+	for _, c := range cases {
+		actual := P95Aggregate(c.values)
+		if actual != c.expected {
+			t.Errorf("P95Aggregate(%#v) == %#v, expected %#v", c.values, actual, c.expected)
+		}
+	}
+}
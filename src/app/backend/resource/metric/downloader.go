@@ -17,9 +17,10 @@ package metric
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	client "github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	heapster "k8s.io/heapster/metrics/api/v1/types"
 )
 
@@ -41,6 +42,19 @@ func (self MetricPromises) GetMetrics() ([]Metric, error) {
 	return result, nil
 }
 
+// GetItemMetrics returns metrics of i-th item from the list of per-item MetricPromises. Returns nil if
+// there are no metrics for the item or their download failed.
+func GetItemMetrics(itemPromises []MetricPromises, i int) []Metric {
+	if i >= len(itemPromises) {
+		return nil
+	}
+	metrics, err := itemPromises[i].GetMetrics()
+	if err != nil {
+		return nil
+	}
+	return metrics
+}
+
 // PutMetrics forwards provided list of metrics to all channels. If provided err is not nil, error will be forwarded.
 func (self MetricPromises) PutMetrics(metrics []Metric, err error) {
 	for i, metricPromise := range self {
@@ -122,7 +136,7 @@ func (self Label) AddMetricLabel(other Label) Label {
 // the result as MetricPromises - one promise for each HeapsterSelector. If HeapsterSelector consists of many native resources
 // (eg. for example deployments can consist of hundreds of pods) then the sum for all its native resources is calculated.
 // HeapsterSelectors are compressed before download process so that the smallest number of heapster requests is used.
-func (self HeapsterSelectors) DownloadMetric(client client.HeapsterClient,
+func (self HeapsterSelectors) DownloadMetric(client metricapi.MetricClient,
 	metricName string) MetricPromises {
 	// Downloads metric in the fastest possible way by first compressing HeapsterSelectors and later unpacking the result to separate boxes.
 	compressedSelectors, reverseMapping := self.compress()
@@ -163,7 +177,7 @@ func (self HeapsterSelectors) DownloadMetric(client client.HeapsterClient,
 				}
 				// aggregate the data for this resource
 
-				aggregatedMetric := AggregateData(requestedResources, metricName, "sum")
+				aggregatedMetric := AggregateData(requestedResources, metricName, self[originalMappingIndex].Rollup)
 				aggregatedMetric.Label = self[originalMappingIndex].Label
 				result[originalMappingIndex].Metric <- &aggregatedMetric
 				result[originalMappingIndex].Error <- nil
@@ -179,7 +193,7 @@ func (self HeapsterSelectors) DownloadMetric(client client.HeapsterClient,
 // So for example, we have 2 HeapsterSelectors each one consisting of many pods. If aggregation MIN is specified, then
 // first for each HeapsterSelector the sum of metrics of all its pods is calculated and afterwards the min is taken.
 // This function downloads the data using smallest possible number of requests to Heapster and returns the result as MetricPromises.
-func (self HeapsterSelectors) DownloadAndAggregate(client client.HeapsterClient, metricNames []string, aggregations AggregationNames) MetricPromises {
+func (self HeapsterSelectors) DownloadAndAggregate(client metricapi.MetricClient, metricNames []string, aggregations AggregationNames) MetricPromises {
 	result := MetricPromises{}
 	for _, metricName := range metricNames {
 		collectedMetrics := self.DownloadMetric(client, metricName)
@@ -212,6 +226,7 @@ func (self HeapsterSelectors) compress() (HeapsterSelectors, map[string][]int) {
 	resourceTypeMap := map[string]api.ResourceKind{}
 	resourceMap := map[string][]string{}
 	labelMap := map[string]Label{}
+	sinceMap := map[string]time.Time{}
 	for i, selector := range self {
 		entry := selector.Path
 		resources, doesEntryExist := resourceMap[selector.Path]
@@ -221,6 +236,7 @@ func (self HeapsterSelectors) compress() (HeapsterSelectors, map[string][]int) {
 		if !doesEntryExist {
 			resourceTypeMap[entry] = selector.TargetResourceType // this will be the same for all entries
 			labelMap[entry] = Label{}
+			sinceMap[entry] = selector.Since // this will be the same for all entries too
 		}
 		labelMap[entry].AddMetricLabel(selector.Label)
 		reverseMapping[entry] = append(reverseMapping[entry], i)
@@ -233,6 +249,7 @@ func (self HeapsterSelectors) compress() (HeapsterSelectors, map[string][]int) {
 			Resources:          removeDuplicates(resourceMap[entry]), // remove duplicate resources so that they are not downloaded twice.
 			Label:              labelMap[entry],
 			TargetResourceType: resourceType,
+			Since:              sinceMap[entry],
 		}
 		compressed = append(compressed, newSelector)
 	}
@@ -296,18 +313,33 @@ type HeapsterSelector struct {
 	Path               string
 	Resources          []string
 	Label
+	// Since is the beginning of the metric window. Heapster default window is used if zero.
+	Since time.Time
+	// Rollup is the aggregation used to combine metrics of all resources of this selector, e.g. pods
+	// of a deployment. Default aggregation is used if empty.
+	Rollup AggregationName
+}
+
+// metricsPath returns path of given metric of given comma separated resources.
+func (self HeapsterSelector) metricsPath(resources string, metricName string) string {
+	path := self.Path + resources + "/metrics/" + metricName
+	if !self.Since.IsZero() {
+		path += "?start=" + self.Since.UTC().Format(time.RFC3339)
+	}
+	return path
 }
 
 // DownloadMetric downloads one metric for this drill from heapster and returns it as a DataPromise
 // Note, if you want to download data for multiple selectors make sure to pack them into HeapsterSelectors object.
 // HeapsterSelectors uses smart download process in order to perform smallest number of heapster requests.
-func (self HeapsterSelector) DownloadMetric(client client.HeapsterClient, metricName string) MetricPromise {
-	return aggregateMetricPromises(self.downloadMetricForEachTargetResource(client, metricName), metricName, OnlySumAggregation, self.Label)[0]
+func (self HeapsterSelector) DownloadMetric(client metricapi.MetricClient, metricName string) MetricPromise {
+	return aggregateMetricPromises(self.downloadMetricForEachTargetResource(client, metricName), metricName,
+		AggregationNames{self.Rollup}, self.Label)[0]
 }
 
 // downloadMetricForEachTargetResource downloads requested metric for each resource present in HeapsterSelector
 // and returns the result as a list of promises - one promise for each resource. Order of promises returned is the same as order in self.Resources.
-func (self HeapsterSelector) downloadMetricForEachTargetResource(client client.HeapsterClient, metricName string) MetricPromises {
+func (self HeapsterSelector) downloadMetricForEachTargetResource(client metricapi.MetricClient, metricName string) MetricPromises {
 	var notAggregatedMetrics MetricPromises
 	if HeapsterAllInOneDownloadConfig[self.TargetResourceType] {
 		notAggregatedMetrics = self.allInOneDownload(client, metricName)
@@ -322,11 +354,11 @@ func (self HeapsterSelector) downloadMetricForEachTargetResource(client client.H
 
 // ithResourceDownload downloads metric for ith resource in self.Resources. Use only in case all in 1 download is not supported
 // for this resource type.
-func (self HeapsterSelector) ithResourceDownload(client client.HeapsterClient, metricName string, i int) MetricPromise {
+func (self HeapsterSelector) ithResourceDownload(client metricapi.MetricClient, metricName string, i int) MetricPromise {
 	result := NewMetricPromise()
 	go func() {
 		rawResult := heapster.MetricResult{}
-		err := HeapsterUnmarshalType(client, self.Path+self.Resources[i]+"/metrics/"+metricName, &rawResult)
+		err := HeapsterUnmarshalType(client, self.metricsPath(self.Resources[i], metricName), &rawResult)
 		if err != nil {
 			result.Metric <- nil
 			result.Error <- err
@@ -347,39 +379,50 @@ func (self HeapsterSelector) ithResourceDownload(client client.HeapsterClient, m
 	return result
 }
 
-// allInOneDownload downloads metrics for all resources present in self.Resources in one request.
+// MaxResourcesPerHeapsterRequest is a maximum number of resources metrics are downloaded for in a
+// single Heapster request. Larger selectors are split into parallel requests to keep URLs short.
+var MaxResourcesPerHeapsterRequest = 100
+
+// allInOneDownload downloads metrics for all resources present in self.Resources in as few requests
+// as possible, see MaxResourcesPerHeapsterRequest.
 // returns a list of metric promises - one promise for each resource. Order of self.Resources is preserved.
-func (self HeapsterSelector) allInOneDownload(client client.HeapsterClient, metricName string) MetricPromises {
+func (self HeapsterSelector) allInOneDownload(client metricapi.MetricClient, metricName string) MetricPromises {
 	result := NewMetricPromises(len(self.Resources))
-	go func() {
-		if len(self.Resources) == 0 {
-			return
-		}
-		rawResults := heapster.MetricResultList{}
-		err := HeapsterUnmarshalType(client, self.Path+strings.Join(self.Resources, ",")+"/metrics/"+metricName, &rawResults)
-		if err != nil {
-			result.PutMetrics(nil, err)
-			return
+	for start := 0; start < len(self.Resources); start += MaxResourcesPerHeapsterRequest {
+		end := start + MaxResourcesPerHeapsterRequest
+		if end > len(self.Resources) {
+			end = len(self.Resources)
 		}
-		if len(result) != len(rawResults.Items) {
-			result.PutMetrics(nil, fmt.Errorf(`Received invalid number of resources from heapster. Expected %d received %d`, len(result), len(rawResults.Items)))
-			return
-		}
-
-		for i, rawResult := range rawResults.Items {
-			dataPoints := DataPointsFromMetricJSONFormat(rawResult)
+		go self.batchDownload(client, metricName, self.Resources[start:end], result[start:end])
+	}
+	return result
+}
 
-			result[i].Metric <- &Metric{
-				DataPoints: dataPoints,
-				MetricName: metricName,
-				Label: Label{
-					self.TargetResourceType: []string{self.Resources[i]},
-				},
-			}
-			result[i].Error <- nil
-		}
+// batchDownload downloads metrics for given resources in one request and puts them into given
+// promises, one promise for each resource.
+func (self HeapsterSelector) batchDownload(client metricapi.MetricClient, metricName string,
+	resources []string, result MetricPromises) {
+	rawResults := heapster.MetricResultList{}
+	err := HeapsterUnmarshalType(client, self.metricsPath(strings.Join(resources, ","), metricName), &rawResults)
+	if err != nil {
+		result.PutMetrics(nil, err)
 		return
+	}
+	if len(result) != len(rawResults.Items) {
+		result.PutMetrics(nil, fmt.Errorf(`Received invalid number of resources from heapster. Expected %d received %d`, len(result), len(rawResults.Items)))
+		return
+	}
 
-	}()
-	return result
+	for i, rawResult := range rawResults.Items {
+		dataPoints := DataPointsFromMetricJSONFormat(rawResult)
+
+		result[i].Metric <- &Metric{
+			DataPoints: dataPoints,
+			MetricName: metricName,
+			Label: Label{
+				self.TargetResourceType: []string{resources[i]},
+			},
+		}
+		result[i].Error <- nil
+	}
 }
@@ -2,6 +2,7 @@ package metric
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"reflect"
@@ -12,7 +13,7 @@ import (
 	"time"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	types "k8s.io/heapster/metrics/api/v1/types"
 )
 
@@ -47,7 +48,7 @@ type FakeRequest struct {
 type PodData map[string][]types.MetricPoint
 type NodeData map[string][]types.MetricPoint
 
-func (self FakeHeapster) Get(path string) heapster.RequestInterface {
+func (self FakeHeapster) Get(path string) metricapi.RequestInterface {
 	return FakeRequest{self.PodData, self.NodeData, path}
 }
 
@@ -235,6 +236,27 @@ func TestHeapsterSelector(t *testing.T) {
 	}
 }
 
+func TestHeapsterSelectorBatches(t *testing.T) {
+	defer func(max int) { MaxResourcesPerHeapsterRequest = max }(MaxResourcesPerHeapsterRequest)
+	MaxResourcesPerHeapsterRequest = 2
+
+	selector := fakeHeapsterSelector(api.ResourceKindPod, "a", []string{"P1", "P2", "P3"})
+	metric, err := selector.DownloadMetric(fakeHeapsterClient, "").GetMetric()
+	numRequests := fakeHeapsterClient.GetNumberOfRequestsMade()
+	if err != nil {
+		t.Fatalf("Failed to get metrics - %s", err)
+	}
+
+	expected := newDps([]int64{45, 60, 75}, 0)
+	if !reflect.DeepEqual(metric.DataPoints, expected) {
+		t.Errorf("Received incorrect data points. Got %v, expected %v.", metric.DataPoints, expected)
+	}
+	if numRequests != 2 {
+		t.Errorf("Selector performed unexpected number of requests to the heapster server. "+
+			"Performed %d, expected %d", numRequests, 2)
+	}
+}
+
 var selectorPool = HeapsterSelectors{
 	fakeHeapsterSelector(api.ResourceKindPod, "a", []string{"P1"}),
 	fakeHeapsterSelector(api.ResourceKindPod, "a", []string{"P2", "P3", "P4"}),
@@ -352,3 +374,56 @@ func TestHeapsterSelectors(t *testing.T) {
 		}
 	}
 }
+
+func TestGetItemMetrics(t *testing.T) {
+	newPromises := func(metrics []Metric, err error) MetricPromises {
+		promises := NewMetricPromises(1)
+		promises.PutMetrics(metrics, err)
+		return promises
+	}
+	itemPromises := []MetricPromises{
+		newPromises([]Metric{{MetricName: "cpu/usage_rate", DataPoints: DataPoints{{X: 1, Y: 2}}}}, nil),
+		newPromises(nil, errors.New("heapster unavailable")),
+	}
+	cases := []struct {
+		index    int
+		expected []Metric
+	}{
+		{0, []Metric{{MetricName: "cpu/usage_rate", DataPoints: DataPoints{{X: 1, Y: 2}}}}},
+		{1, nil},
+		{2, nil},
+	}
+	for _, c := range cases {
+		actual := GetItemMetrics(itemPromises, c.index)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetItemMetrics(%#v) == \ngot: %#v, \nexpected %#v", c.index, actual, c.expected)
+		}
+	}
+}
+
+func TestHeapsterSelectorsRollup(t *testing.T) {
+	cases := []struct {
+		rollup   AggregationName
+		expected DataPoints
+	}{
+		{"", newDps([]int64{45, 60, 75}, 0)},
+		{SumAggregation, newDps([]int64{45, 60, 75}, 0)},
+		{MaxAggregation, newDps([]int64{30, 35, 40}, 0)},
+		{AverageAggregation, newDps([]int64{15, 20, 25}, 0)},
+		{P95Aggregation, newDps([]int64{30, 35, 40}, 0)},
+	}
+	for _, c := range cases {
+		selector := fakeHeapsterSelector(api.ResourceKindPod, "a", []string{"P1", "P2", "P3"})
+		selector.Rollup = c.rollup
+
+		metrics, err := HeapsterSelectors{selector}.DownloadMetric(fakeHeapsterClient, "Dummy/Metric").GetMetrics()
+		if err != nil {
+			t.Fatalf("Failed to get metrics - %s", err)
+		}
+		if !reflect.DeepEqual(metrics[0].DataPoints, c.expected) {
+			t.Errorf("DownloadMetric() with rollup %#v == \ngot: %#v, \nexpected %#v", c.rollup,
+				metrics[0].DataPoints, c.expected)
+		}
+	}
+	fakeHeapsterClient.GetNumberOfRequestsMade()
+}
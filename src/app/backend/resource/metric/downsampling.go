@@ -0,0 +1,67 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+// Downsample returns given data points reduced to at most maxDataPoints points. Consecutive points
+// are grouped into buckets of equal size, each bucket is replaced by a single point at the time of
+// its first point with the value aggregated using given aggregation, e.g. max or average. Data
+// points are returned unchanged if there are not more of them than requested.
+func Downsample(dataPoints DataPoints, maxDataPoints int, aggregation AggregationName) DataPoints {
+	if maxDataPoints <= 0 || len(dataPoints) <= maxDataPoints {
+		return dataPoints
+	}
+
+	aggregate, ok := AggregatingFunctions[aggregation]
+	if !ok {
+		aggregate = MaxAggregate
+	}
+
+	bucketSize := (len(dataPoints) + maxDataPoints - 1) / maxDataPoints
+	result := make(DataPoints, 0, maxDataPoints)
+	for start := 0; start < len(dataPoints); start += bucketSize {
+		end := start + bucketSize
+		if end > len(dataPoints) {
+			end = len(dataPoints)
+		}
+
+		values := make([]int64, 0, end-start)
+		for _, dataPoint := range dataPoints[start:end] {
+			values = append(values, dataPoint.Y)
+		}
+		result = append(result, DataPoint{X: dataPoints[start].X, Y: aggregate(values)})
+	}
+	return result
+}
+
+// DownsampleMetricPromises returns promises of given metrics downsampled to at most maxDataPoints
+// points, see Downsample.
+func DownsampleMetricPromises(metricPromises MetricPromises, maxDataPoints int,
+	aggregation AggregationName) MetricPromises {
+	if maxDataPoints <= 0 {
+		return metricPromises
+	}
+
+	result := NewMetricPromises(len(metricPromises))
+	go func() {
+		metrics, err := metricPromises.GetMetrics()
+		if err == nil {
+			for i := range metrics {
+				metrics[i].DataPoints = Downsample(metrics[i].DataPoints, maxDataPoints, aggregation)
+			}
+		}
+		result.PutMetrics(metrics, err)
+	}()
+	return result
+}
@@ -0,0 +1,58 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDownsample(t *testing.T) {
+	dataPoints := DataPoints{{0, 1}, {60, 5}, {120, 3}, {180, 4}, {240, 2}}
+	cases := []struct {
+		maxDataPoints int
+		aggregation   AggregationName
+		expected      DataPoints
+	}{
+		{0, MaxAggregation, dataPoints},
+		{5, MaxAggregation, dataPoints},
+		{2, MaxAggregation, DataPoints{{0, 5}, {180, 4}}},
+		{2, AverageAggregation, DataPoints{{0, 3}, {180, 3}}},
+		{1, "unknown", DataPoints{{0, 5}}},
+	}
+	for _, c := range cases {
+		actual := Downsample(dataPoints, c.maxDataPoints, c.aggregation)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Downsample(%#v, %d, %s) == \ngot: %#v, \nexpected %#v", dataPoints,
+				c.maxDataPoints, c.aggregation, actual, c.expected)
+		}
+	}
+}
+
+func TestDownsampleMetricPromises(t *testing.T) {
+	promises := NewMetricPromises(1)
+	promises.PutMetrics([]Metric{{DataPoints: DataPoints{{0, 1}, {60, 5}, {120, 3}}}}, nil)
+
+	metrics, err := DownsampleMetricPromises(promises, 2, MaxAggregation).GetMetrics()
+	if err != nil {
+		t.Fatalf("DownsampleMetricPromises() returns error: %v", err)
+	}
+
+	expected := DataPoints{{0, 5}, {120, 3}}
+	if !reflect.DeepEqual(metrics[0].DataPoints, expected) {
+		t.Errorf("DownsampleMetricPromises() == \ngot: %#v, \nexpected %#v", metrics[0].DataPoints,
+			expected)
+	}
+}
@@ -18,7 +18,7 @@ import (
 	"encoding/json"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	types "k8s.io/heapster/metrics/api/v1/types"
 )
 
@@ -49,7 +49,7 @@ func DataPointsFromMetricJSONFormat(raw types.MetricResult) (dp DataPoints) {
 
 // HeapsterUnmarshalType performs heapster GET request to the specifies path and transfers
 // the data to the interface provided.
-func HeapsterUnmarshalType(client heapster.HeapsterClient, path string, v interface{}) error {
+func HeapsterUnmarshalType(client metricapi.MetricClient, path string, v interface{}) error {
 	rawData, err := client.Get("/model/" + path).DoRaw()
 	if err != nil {
 		return err
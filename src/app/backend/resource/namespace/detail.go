@@ -18,7 +18,7 @@ import (
 	"log"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
@@ -40,6 +40,12 @@ type NamespaceDetail struct {
 	// Phase is the current lifecycle phase of the namespace.
 	Phase v1.NamespacePhase `json:"phase"`
 
+	// Maintenance of the namespace. Nil if the namespace is not in maintenance.
+	Maintenance *common.NamespaceMaintenance `json:"maintenance,omitempty"`
+
+	// Default scheduling of pods enforced at admission. Nil if the namespace has none.
+	Scheduling *common.NamespaceScheduling `json:"scheduling,omitempty"`
+
 	// Events is list of events associated to the namespace.
 	EventList common.EventList `json:"eventList"`
 
@@ -51,7 +57,7 @@ type NamespaceDetail struct {
 }
 
 // GetNamespaceDetail gets namespace details.
-func GetNamespaceDetail(client k8sClient.Interface, heapsterClient heapster.HeapsterClient,
+func GetNamespaceDetail(client k8sClient.Interface, heapsterClient metricapi.MetricClient,
 	name string) (
 	*NamespaceDetail, error) {
 	log.Printf("Getting details of %s namespace", name)
@@ -87,6 +93,8 @@ func toNamespaceDetail(namespace v1.Namespace, events common.EventList, resource
 		ObjectMeta:        api.NewObjectMeta(namespace.ObjectMeta),
 		TypeMeta:          api.NewTypeMeta(api.ResourceKindNamespace),
 		Phase:             namespace.Status.Phase,
+		Maintenance:       common.GetNamespaceMaintenance(namespace.Annotations),
+		Scheduling:        common.GetNamespaceScheduling(namespace.Annotations),
 		EventList:         events,
 		ResourceQuotaList: resourceQuotaList,
 		ResourceLimits:    resourceLimits,
@@ -83,7 +83,7 @@ func toNamespaceList(namespaces []v1.Namespace, dsQuery *dataselect.DataSelectQu
 
 	namespaceCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(namespaces), dsQuery)
 	namespaces = fromCells(namespaceCells)
-	namespaceList.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+	namespaceList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, namespaceList.ListMeta.TotalItems)
 
 	for _, namespace := range namespaces {
 		namespaceList.Namespaces = append(namespaceList.Namespaces, toNamespace(namespace))
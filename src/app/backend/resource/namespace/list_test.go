@@ -35,7 +35,7 @@ func TestGetNamespaceList(t *testing.T) {
 				{ObjectMeta: metaV1.ObjectMeta{Name: "foo"}},
 			},
 			&NamespaceList{
-				ListMeta: api.ListMeta{TotalItems: 1},
+				ListMeta: api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
 				Namespaces: []Namespace{{
 					TypeMeta:   api.TypeMeta{Kind: "namespace"},
 					ObjectMeta: api.ObjectMeta{Name: "foo"},
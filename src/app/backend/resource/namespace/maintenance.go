@@ -0,0 +1,113 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+	authorizationApi "k8s.io/client-go/pkg/apis/authorization/v1"
+)
+
+// SetNamespaceMaintenance puts given namespace in maintenance. Time the maintenance started at is
+// kept if the namespace already is in maintenance.
+func SetNamespaceMaintenance(client client.Interface, name string,
+	maintenance common.NamespaceMaintenance) (*common.NamespaceMaintenance, error) {
+	log.Printf("Putting %s namespace in maintenance", name)
+
+	namespace, err := client.CoreV1().Namespaces().Get(name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	maintenance.Since = metaV1.Now()
+	if current := common.GetNamespaceMaintenance(namespace.Annotations); current != nil &&
+		!current.Since.IsZero() {
+		maintenance.Since = current.Since
+	}
+
+	raw, err := json.Marshal(maintenance)
+	if err != nil {
+		return nil, err
+	}
+
+	if namespace.Annotations == nil {
+		namespace.Annotations = make(map[string]string)
+	}
+	namespace.Annotations[common.NamespaceMaintenanceAnnotationKey] = string(raw)
+	if _, err := client.CoreV1().Namespaces().Update(namespace); err != nil {
+		return nil, err
+	}
+
+	return &maintenance, nil
+}
+
+// DeleteNamespaceMaintenance ends maintenance of given namespace.
+func DeleteNamespaceMaintenance(client client.Interface, name string) error {
+	log.Printf("Ending maintenance of %s namespace", name)
+
+	namespace, err := client.CoreV1().Namespaces().Get(name, metaV1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if _, ok := namespace.Annotations[common.NamespaceMaintenanceAnnotationKey]; !ok {
+		return nil
+	}
+
+	delete(namespace.Annotations, common.NamespaceMaintenanceAnnotationKey)
+	_, err = client.CoreV1().Namespaces().Update(namespace)
+	return err
+}
+
+// IsFrozen checks if changes to given namespace are blocked for the user of given user client.
+// Changes are blocked during maintenance with freeze, unless the user is allowed to update the
+// namespace. Maintenance is read with given dashboard client, as users allowed to change objects in
+// the namespace are not necessarily allowed to get the namespace itself. Namespaces that do not
+// exist are not frozen.
+func IsFrozen(dashboardClient, userClient client.Interface, name string) (bool, error) {
+	namespace, err := dashboardClient.CoreV1().Namespaces().Get(name, metaV1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	maintenance := common.GetNamespaceMaintenance(namespace.Annotations)
+	if maintenance == nil || !maintenance.Freeze {
+		return false, nil
+	}
+
+	review, err := userClient.AuthorizationV1().SelfSubjectAccessReviews().Create(
+		&authorizationApi.SelfSubjectAccessReview{
+			Spec: authorizationApi.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationApi.ResourceAttributes{
+					Verb:     "update",
+					Resource: "namespaces",
+					Name:     name,
+				},
+			},
+		})
+	if err != nil {
+		return false, err
+	}
+
+	return !review.Status.Allowed, nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	api "k8s.io/client-go/pkg/api/v1"
+	authorizationApi "k8s.io/client-go/pkg/apis/authorization/v1"
+	core "k8s.io/client-go/testing"
+)
+
+func TestSetNamespaceMaintenance(t *testing.T) {
+	since := metaV1.NewTime(time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC).Local())
+	namespace := &api.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "foo", Annotations: map[string]string{
+		common.NamespaceMaintenanceAnnotationKey: `{"freeze":false,"since":"2017-05-05T10:00:00Z"}`}}}
+	client := fake.NewSimpleClientset(namespace)
+
+	actual, err := SetNamespaceMaintenance(client, "foo",
+		common.NamespaceMaintenance{Reason: "upgrade", Freeze: true})
+	if err != nil {
+		t.Fatalf("SetNamespaceMaintenance() returns error: %v", err)
+	}
+
+	expected := &common.NamespaceMaintenance{Reason: "upgrade", Freeze: true, Since: since}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("SetNamespaceMaintenance() == \ngot: %#v, \nexpected %#v", actual, expected)
+	}
+
+	updated, _ := client.CoreV1().Namespaces().Get("foo", metaV1.GetOptions{})
+	if stored := common.GetNamespaceMaintenance(updated.Annotations); !reflect.DeepEqual(stored, expected) {
+		t.Errorf("Stored maintenance == \ngot: %#v, \nexpected %#v", stored, expected)
+	}
+
+	if err := DeleteNamespaceMaintenance(client, "foo"); err != nil {
+		t.Fatalf("DeleteNamespaceMaintenance() returns error: %v", err)
+	}
+
+	updated, _ = client.CoreV1().Namespaces().Get("foo", metaV1.GetOptions{})
+	if stored := common.GetNamespaceMaintenance(updated.Annotations); stored != nil {
+		t.Errorf("Maintenance was not removed, got: %#v", stored)
+	}
+}
+
+func TestIsFrozen(t *testing.T) {
+	cases := []struct {
+		annotation string
+		expected   bool
+	}{
+		{"", false},
+		{`{"freeze":false}`, false},
+		{`{"freeze":true}`, true},
+	}
+	for _, c := range cases {
+		namespace := &api.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "foo"}}
+		if c.annotation != "" {
+			namespace.Annotations = map[string]string{common.NamespaceMaintenanceAnnotationKey: c.annotation}
+		}
+
+		// Fake access reviews are never allowed.
+		actual, err := IsFrozen(fake.NewSimpleClientset(namespace), fake.NewSimpleClientset(), "foo")
+		if err != nil {
+			t.Fatalf("IsFrozen() returns error: %v", err)
+		}
+		if actual != c.expected {
+			t.Errorf("IsFrozen() with annotation %#v returns %#v, expected %#v", c.annotation, actual,
+				c.expected)
+		}
+	}
+}
+
+func TestIsFrozenReadsMaintenanceWithDashboardClient(t *testing.T) {
+	namespace := &api.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "foo", Annotations: map[string]string{
+		common.NamespaceMaintenanceAnnotationKey: `{"freeze":true}`}}}
+
+	// User cannot get the namespace, but the dashboard can.
+	actual, err := IsFrozen(fake.NewSimpleClientset(namespace), fake.NewSimpleClientset(), "foo")
+	if err != nil {
+		t.Fatalf("IsFrozen() returns error: %v", err)
+	}
+	if !actual {
+		t.Error("IsFrozen() returns false, expected true")
+	}
+
+	actual, err = IsFrozen(fake.NewSimpleClientset(), fake.NewSimpleClientset(namespace), "foo")
+	if err != nil {
+		t.Fatalf("IsFrozen() of missing namespace returns error: %v", err)
+	}
+	if actual {
+		t.Error("IsFrozen() of missing namespace returns true, expected false")
+	}
+}
+
+func TestIsFrozenReviewError(t *testing.T) {
+	namespace := &api.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "foo", Annotations: map[string]string{
+		common.NamespaceMaintenanceAnnotationKey: `{"freeze":true}`}}}
+	userClient := fake.NewSimpleClientset()
+	userClient.PrependReactor("create", "selfsubjectaccessreviews",
+		func(action core.Action) (bool, runtime.Object, error) {
+			return true, &authorizationApi.SelfSubjectAccessReview{}, fmt.Errorf("connection refused")
+		})
+
+	if _, err := IsFrozen(fake.NewSimpleClientset(namespace), userClient, "foo"); err == nil {
+		t.Error("IsFrozen() returns no error, expected error")
+	}
+}
@@ -22,6 +22,68 @@ import (
 	"k8s.io/client-go/pkg/api/v1"
 )
 
+const (
+	// Labels and annotations set by Karpenter on nodes it provisioned.
+	karpenterNodePoolLabel          = "karpenter.sh/nodepool"
+	karpenterProvisionerNameLabel   = "karpenter.sh/provisioner-name"
+	karpenterCapacityTypeLabel      = "karpenter.sh/capacity-type"
+	karpenterDoNotDisruptAnnotation = "karpenter.sh/do-not-disrupt"
+
+	// Annotations set by Cluster API on nodes of its machines.
+	clusterAPIMachineAnnotation          = "cluster.x-k8s.io/machine"
+	clusterAPIClusterNamespaceAnnotation = "cluster.x-k8s.io/cluster-namespace"
+	clusterAPIOwnerNameAnnotation        = "cluster.x-k8s.io/owner-name"
+)
+
+// NodeProvisioner describes the node provisioner (e.g. Karpenter or Cluster API) that created a
+// node.
+type NodeProvisioner struct {
+	// Name of the provisioner, e.g. karpenter.
+	Name string `json:"name"`
+
+	// Name of the node pool (or provisioner for older Karpenter versions) the node belongs to. For
+	// Cluster API it is the owner of the machine, usually a machine set.
+	NodePool string `json:"nodePool"`
+
+	// Capacity type of the node, e.g. spot or on-demand.
+	CapacityType string `json:"capacityType,omitempty"`
+
+	// True when the node is excluded from voluntary disruption, e.g. consolidation.
+	DoNotDisrupt bool `json:"doNotDisrupt"`
+
+	// Name and namespace of the Cluster API machine of the node. Empty for other provisioners.
+	Machine          string `json:"machine,omitempty"`
+	MachineNamespace string `json:"machineNamespace,omitempty"`
+}
+
+// getNodeProvisioner returns provisioner of the node based on its labels or nil if the node was not
+// created by a known provisioner.
+func getNodeProvisioner(node v1.Node) *NodeProvisioner {
+	if machine, ok := node.ObjectMeta.Annotations[clusterAPIMachineAnnotation]; ok {
+		return &NodeProvisioner{
+			Name:             "cluster-api",
+			NodePool:         node.ObjectMeta.Annotations[clusterAPIOwnerNameAnnotation],
+			Machine:          machine,
+			MachineNamespace: node.ObjectMeta.Annotations[clusterAPIClusterNamespaceAnnotation],
+		}
+	}
+
+	nodePool, ok := node.ObjectMeta.Labels[karpenterNodePoolLabel]
+	if !ok {
+		nodePool, ok = node.ObjectMeta.Labels[karpenterProvisionerNameLabel]
+	}
+	if !ok {
+		return nil
+	}
+
+	return &NodeProvisioner{
+		Name:         "karpenter",
+		NodePool:     nodePool,
+		CapacityType: node.ObjectMeta.Labels[karpenterCapacityTypeLabel],
+		DoNotDisrupt: node.ObjectMeta.Annotations[karpenterDoNotDisruptAnnotation] == "true",
+	}
+}
+
 //getContainerImages returns container image strings from the given node.
 func getContainerImages(node v1.Node) []string {
 	var containerImages []string
@@ -45,6 +107,8 @@ func (self NodeCell) GetProperty(name dataselect.PropertyName) dataselect.Compar
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.UIDProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.UID)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
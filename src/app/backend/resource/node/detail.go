@@ -16,9 +16,11 @@ package node
 
 import (
 	"log"
+	"sort"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	"github.com/kubernetes/dashboard/src/app/backend/link"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
@@ -76,18 +78,55 @@ type NodeAllocatedResources struct {
 	PodFraction float64 `json:"podFraction"`
 }
 
+// NodeResource compares capacity, allocatable amount and amounts requested and limited by pods of
+// a resource of a node, e.g. cpu or memory.
+type NodeResource struct {
+	Name v1.ResourceName `json:"name"`
+
+	Capacity resource.Quantity `json:"capacity"`
+
+	// Amount available to pods, which is capacity minus amount reserved for system daemons.
+	Allocatable resource.Quantity `json:"allocatable"`
+
+	Requests resource.Quantity `json:"requests"`
+	Limits   resource.Quantity `json:"limits"`
+
+	// Requests and limits in percent of allocatable amount. Limits can be over 100%, i.e.
+	// overcommitted.
+	RequestsFraction float64 `json:"requestsFraction"`
+	LimitsFraction   float64 `json:"limitsFraction"`
+}
+
+// NodeImage is a container image cached on a node.
+type NodeImage struct {
+	// Names by which the image is known, e.g. tags and digests.
+	Names []string `json:"names"`
+
+	SizeBytes int64 `json:"sizeBytes"`
+}
+
 // NodeDetail is a presentation layer view of Kubernetes Node resource. This means it is Node plus
 // additional augmented data we can get from other sources.
 type NodeDetail struct {
 	ObjectMeta api.ObjectMeta `json:"objectMeta"`
 	TypeMeta   api.TypeMeta   `json:"typeMeta"`
 
+	// Links to external tools configured in settings and in the links annotation.
+	Links []link.Link `json:"links,omitempty"`
+
 	// NodePhase is the current lifecycle phase of the node.
 	Phase v1.NodePhase `json:"phase"`
 
 	// Resources allocated by node.
 	AllocatedResources NodeAllocatedResources `json:"allocatedResources"`
 
+	// Capacity, allocatable, requested and limited amounts of all resources of the node, sorted by
+	// name.
+	Resources []NodeResource `json:"resources"`
+
+	// Taints of the node, which repel pods that don't tolerate them.
+	Taints []v1.Taint `json:"taints"`
+
 	// External ID of the node assigned by some machine database (e.g. a cloud provider).
 	ExternalID string `json:"externalID"`
 
@@ -106,9 +145,18 @@ type NodeDetail struct {
 	// Conditions is an array of current node conditions.
 	Conditions []common.Condition `json:"conditions"`
 
+	// History of condition transitions of the node.
+	ConditionTimeline NodeConditionTimeline `json:"conditionTimeline"`
+
 	// Container images of the node.
 	ContainerImages []string `json:"containerImages"`
 
+	// Container images cached on the node with their sizes.
+	Images []NodeImage `json:"images"`
+
+	// Provisioner that created the node, nil if the node was not created by a known provisioner.
+	Provisioner *NodeProvisioner `json:"provisioner,omitempty"`
+
 	// PodList contains information about pods belonging to this node.
 	PodList pod.PodList `json:"podList"`
 
@@ -120,7 +168,7 @@ type NodeDetail struct {
 }
 
 // GetNodeDetail gets node details.
-func GetNodeDetail(client k8sClient.Interface, heapsterClient heapster.HeapsterClient,
+func GetNodeDetail(client k8sClient.Interface, heapsterClient metricapi.MetricClient,
 	name string) (*NodeDetail, error) {
 	log.Printf("Getting details of %s node", name)
 
@@ -140,30 +188,50 @@ func GetNodeDetail(client k8sClient.Interface, heapsterClient heapster.HeapsterC
 		return nil, err
 	}
 
-	podList, err := GetNodePods(client, heapsterClient, dataselect.DefaultDataSelect, name)
+	podList := pod.CreatePodList(pods.Items, []v1.Event{}, dataselect.DefaultDataSelect,
+		heapsterClient)
 
-	eventList, err := event.GetNodeEvents(client, dataselect.DefaultDataSelect, node.Name)
+	// Events are listed once for both the event list and the condition timeline.
+	rawEvents, err := event.GetEvents(client, v1.NamespaceAll, node.Name)
 	if err != nil {
 		return nil, err
 	}
+	eventList := event.CreateEventList(getNodeEvents(rawEvents), dataselect.DefaultDataSelect)
 
-	allocatedResources, err := getNodeAllocatedResources(*node, pods)
+	requests, limits, err := getPodRequestsAndLimits(pods)
 	if err != nil {
 		return nil, err
 	}
+	allocatedResources := getNodeAllocatedResources(*node, len(pods.Items), requests, limits)
 
 	metrics, _ := metricPromises.GetMetrics()
-	nodeDetails := toNodeDetail(*node, podList, eventList, allocatedResources, metrics)
+	nodeDetails := toNodeDetail(*node, &podList, &eventList, allocatedResources, metrics)
+	nodeDetails.Resources = getNodeResources(*node, requests, limits)
+	nodeDetails.ConditionTimeline = *toNodeConditionTimeline(*node, rawEvents)
 	return &nodeDetails, nil
 }
 
-func getNodeAllocatedResources(node v1.Node, podList *v1.PodList) (NodeAllocatedResources, error) {
+// getNodeEvents returns events of nodes among given events, which may also include events of other
+// objects with the same name.
+func getNodeEvents(events []v1.Event) []v1.Event {
+	result := make([]v1.Event, 0, len(events))
+	for _, e := range events {
+		if e.InvolvedObject.Kind == "Node" {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// getPodRequestsAndLimits returns sums of resource requests and limits of given pods.
+func getPodRequestsAndLimits(podList *v1.PodList) (map[v1.ResourceName]resource.Quantity,
+	map[v1.ResourceName]resource.Quantity, error) {
 	reqs, limits := map[v1.ResourceName]resource.Quantity{}, map[v1.ResourceName]resource.Quantity{}
 
 	for _, pod := range podList.Items {
 		podReqs, podLimits, err := helper.PodRequestsAndLimits(&pod)
 		if err != nil {
-			return NodeAllocatedResources{}, err
+			return nil, nil, err
 		}
 		for podReqName, podReqValue := range podReqs {
 			if value, ok := reqs[podReqName]; !ok {
@@ -182,7 +250,11 @@ func getNodeAllocatedResources(node v1.Node, podList *v1.PodList) (NodeAllocated
 			}
 		}
 	}
+	return reqs, limits, nil
+}
 
+func getNodeAllocatedResources(node v1.Node, allocatedPods int, reqs,
+	limits map[v1.ResourceName]resource.Quantity) NodeAllocatedResources {
 	cpuRequests, cpuLimits, memoryRequests, memoryLimits := reqs[v1.ResourceCPU],
 		limits[v1.ResourceCPU], reqs[v1.ResourceMemory], limits[v1.ResourceMemory]
 
@@ -201,7 +273,7 @@ func getNodeAllocatedResources(node v1.Node, podList *v1.PodList) (NodeAllocated
 	var podFraction float64 = 0
 	var podCapacity int64 = node.Status.Capacity.Pods().Value()
 	if podCapacity > 0 {
-		podFraction = float64(len(podList.Items)) / float64(podCapacity) * 100
+		podFraction = float64(allocatedPods) / float64(podCapacity) * 100
 	}
 
 	return NodeAllocatedResources{
@@ -215,14 +287,67 @@ func getNodeAllocatedResources(node v1.Node, podList *v1.PodList) (NodeAllocated
 		MemoryLimits:           memoryLimits.Value(),
 		MemoryLimitsFraction:   memoryLimitsFraction,
 		MemoryCapacity:         node.Status.Capacity.Memory().Value(),
-		AllocatedPods:          len(podList.Items),
+		AllocatedPods:          allocatedPods,
 		PodCapacity:            podCapacity,
 		PodFraction:            podFraction,
-	}, nil
+	}
+}
+
+// getNodeResources returns capacity, allocatable, requested and limited amounts of all resources
+// of given node, sorted by name.
+func getNodeResources(node v1.Node, reqs,
+	limits map[v1.ResourceName]resource.Quantity) []NodeResource {
+	names := make([]string, 0)
+	seen := make(map[v1.ResourceName]bool)
+	for _, resourceList := range []map[v1.ResourceName]resource.Quantity{
+		node.Status.Capacity, node.Status.Allocatable, reqs, limits} {
+		for name := range resourceList {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, string(name))
+			}
+		}
+	}
+	sort.Strings(names)
+
+	resources := make([]NodeResource, 0)
+	for _, name := range names {
+		resourceName := v1.ResourceName(name)
+		nodeResource := NodeResource{
+			Name:        resourceName,
+			Capacity:    node.Status.Capacity[resourceName],
+			Allocatable: node.Status.Allocatable[resourceName],
+			Requests:    reqs[resourceName],
+			Limits:      limits[resourceName],
+		}
+		if allocatable := float64(nodeResource.Allocatable.MilliValue()); allocatable > 0 {
+			nodeResource.RequestsFraction = float64(nodeResource.Requests.MilliValue()) /
+				allocatable * 100
+			nodeResource.LimitsFraction = float64(nodeResource.Limits.MilliValue()) /
+				allocatable * 100
+		}
+		resources = append(resources, nodeResource)
+	}
+	return resources
+}
+
+// getNodeImages returns container images cached on given node.
+func getNodeImages(node v1.Node) []NodeImage {
+	images := make([]NodeImage, 0)
+	for _, image := range node.Status.Images {
+		images = append(images, NodeImage{Names: image.Names, SizeBytes: image.SizeBytes})
+	}
+	return images
+}
+
+// getNodeTaints returns taints of given node.
+func getNodeTaints(node v1.Node) []v1.Taint {
+	taints := make([]v1.Taint, 0)
+	return append(taints, node.Spec.Taints...)
 }
 
 // GetNodePods return pods list in given named node
-func GetNodePods(client k8sClient.Interface, heapsterClient heapster.HeapsterClient,
+func GetNodePods(client k8sClient.Interface, heapsterClient metricapi.MetricClient,
 	dsQuery *dataselect.DataSelectQuery, name string) (*pod.PodList, error) {
 	node, err := client.CoreV1().Nodes().Get(name, metaV1.GetOptions{})
 	if err != nil {
@@ -267,6 +392,9 @@ func toNodeDetail(node v1.Node, pods *pod.PodList, eventList *common.EventList,
 		NodeInfo:           node.Status.NodeInfo,
 		Conditions:         getNodeConditions(node),
 		ContainerImages:    getContainerImages(node),
+		Images:             getNodeImages(node),
+		Taints:             getNodeTaints(node),
+		Provisioner:        getNodeProvisioner(node),
 		PodList:            *pods,
 		EventList:          *eventList,
 		AllocatedResources: allocatedResources,
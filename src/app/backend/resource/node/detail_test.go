@@ -19,11 +19,12 @@ import (
 	"testing"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/pod"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/pkg/api/v1"
@@ -31,10 +32,10 @@ import (
 )
 
 type FakeHeapsterClient struct {
-	client fake.Clientset
+	client *fake.Clientset
 }
 
-func (c FakeHeapsterClient) Get(path string) heapster.RequestInterface {
+func (c FakeHeapsterClient) Get(path string) metricapi.RequestInterface {
 	return &restclient.Request{}
 }
 
@@ -63,11 +64,13 @@ func TestGetNodeDetail(t *testing.T) {
 				ProviderID:    "ID-1",
 				Unschedulable: true,
 				PodList: pod.PodList{
+					ListMeta:          api.ListMeta{Page: 1, ItemsPerPage: 10},
 					Pods:              []pod.Pod{},
 					CumulativeMetrics: make([]metric.Metric, 0),
 				},
 				EventList: common.EventList{
-					Events: make([]common.Event, 0),
+					ListMeta: api.ListMeta{Page: 1, ItemsPerPage: 10},
+					Events:   make([]common.Event, 0),
 				},
 				AllocatedResources: NodeAllocatedResources{
 					CPURequests:            0,
@@ -84,6 +87,13 @@ func TestGetNodeDetail(t *testing.T) {
 					PodCapacity:            0,
 					PodFraction:            0,
 				},
+				Resources: []NodeResource{},
+				Taints:    []v1.Taint{},
+				ConditionTimeline: NodeConditionTimeline{
+					NodeName:    "test-node",
+					Transitions: []NodeConditionTransition{},
+				},
+				Images:  []NodeImage{},
 				Metrics: make([]metric.Metric, 0),
 			},
 		},
@@ -91,7 +101,7 @@ func TestGetNodeDetail(t *testing.T) {
 
 	for _, c := range cases {
 		fakeClient := fake.NewSimpleClientset(c.node)
-		fakeHeapsterClient := FakeHeapsterClient{client: *fake.NewSimpleClientset()}
+		fakeHeapsterClient := FakeHeapsterClient{client: fake.NewSimpleClientset()}
 
 		dataselect.StdMetricsDataSelect.MetricQuery = dataselect.NoMetrics
 		actual, _ := GetNodeDetail(fakeClient, fakeHeapsterClient, c.name)
@@ -102,3 +112,90 @@ func TestGetNodeDetail(t *testing.T) {
 		}
 	}
 }
+
+func TestGetNodeDetailEvents(t *testing.T) {
+	newEvent := func(name, kind, reason string) *v1.Event {
+		return &v1.Event{
+			ObjectMeta:     metaV1.ObjectMeta{Name: name, Namespace: "default"},
+			InvolvedObject: v1.ObjectReference{Kind: kind, Name: "test-node"},
+			Reason:         reason,
+		}
+	}
+	fakeClient := fake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "test-node"}},
+		newEvent("node-event", "Node", "NodeNotReady"),
+		newEvent("pod-event", "Pod", "Killing"),
+	)
+	fakeHeapsterClient := FakeHeapsterClient{client: fake.NewSimpleClientset()}
+	dataselect.StdMetricsDataSelect.MetricQuery = dataselect.NoMetrics
+
+	actual, err := GetNodeDetail(fakeClient, fakeHeapsterClient, "test-node")
+	if err != nil {
+		t.Fatalf("GetNodeDetail() returns error: %v", err)
+	}
+
+	if len(actual.EventList.Events) != 1 || actual.EventList.Events[0].ObjectMeta.Name != "node-event" {
+		t.Errorf("GetNodeDetail() returns events %#v, expected only node-event", actual.EventList)
+	}
+	if len(actual.ConditionTimeline.Transitions) != 1 ||
+		actual.ConditionTimeline.Transitions[0].Reason != "NodeNotReady" {
+		t.Errorf("GetNodeDetail() returns condition timeline %#v, expected NodeNotReady transition",
+			actual.ConditionTimeline)
+	}
+	eventLists := 0
+	for _, action := range fakeClient.Actions() {
+		if action.GetVerb() == "list" && action.GetResource().Resource == "events" {
+			eventLists++
+		}
+	}
+	if eventLists != 1 {
+		t.Errorf("GetNodeDetail() lists events %d times, expected once", eventLists)
+	}
+}
+
+func TestGetNodeResources(t *testing.T) {
+	node := v1.Node{
+		Status: v1.NodeStatus{
+			Capacity: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("4"),
+				v1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+			Allocatable: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+		},
+	}
+	reqs := map[v1.ResourceName]resource.Quantity{
+		v1.ResourceCPU:    resource.MustParse("500m"),
+		v1.ResourceMemory: resource.MustParse("2Gi"),
+	}
+	limits := map[v1.ResourceName]resource.Quantity{
+		v1.ResourceCPU: resource.MustParse("3"),
+	}
+
+	expected := []NodeResource{
+		{
+			Name:             v1.ResourceCPU,
+			Capacity:         resource.MustParse("4"),
+			Allocatable:      resource.MustParse("2"),
+			Requests:         resource.MustParse("500m"),
+			Limits:           resource.MustParse("3"),
+			RequestsFraction: 25,
+			LimitsFraction:   150,
+		},
+		{
+			Name:             v1.ResourceMemory,
+			Capacity:         resource.MustParse("8Gi"),
+			Allocatable:      resource.MustParse("8Gi"),
+			Requests:         resource.MustParse("2Gi"),
+			RequestsFraction: 25,
+		},
+	}
+
+	actual := getNodeResources(node, reqs, limits)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getNodeResources(%#v, %#v, %#v) == \ngot %#v, \nexpected %#v", node, reqs,
+			limits, actual, expected)
+	}
+}
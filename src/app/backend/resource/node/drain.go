@@ -0,0 +1,295 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/operation"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sClient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	policy "k8s.io/client-go/pkg/apis/policy/v1beta1"
+)
+
+// MirrorPodAnnotationKey is annotation key marking mirror pods of static pods run by kubelet. They
+// cannot be evicted through the API server.
+const MirrorPodAnnotationKey = "kubernetes.io/config.mirror"
+
+// DefaultDrainTimeout is the time after which a drain gives up waiting for pods to be evicted.
+const DefaultDrainTimeout = 5 * time.Minute
+
+// drainRetryInterval is the interval of retrying evictions refused by disruption budgets and of
+// checking that evicted pods are gone.
+var drainRetryInterval = 5 * time.Second
+
+// DrainOptions are options of a node drain.
+type DrainOptions struct {
+	// Grace period of evicted pods, nil to use grace periods of the pods.
+	GracePeriodSeconds *int64
+
+	// Skip pods of daemon sets, which would be recreated on the node anyway. Drain fails when there
+	// are such pods and this is false.
+	IgnoreDaemonSets bool
+
+	// Evict pods without a controller, which are not recreated elsewhere. Drain fails when there
+	// are such pods and this is false.
+	Force bool
+
+	// How long to wait for all pods to be evicted.
+	Timeout time.Duration
+}
+
+// DrainEventType is a type of drain progress event.
+type DrainEventType string
+
+const (
+	DrainEventCordoned  DrainEventType = "Cordoned"
+	DrainEventSkipped   DrainEventType = "Skipped"
+	DrainEventEvicting  DrainEventType = "Evicting"
+	DrainEventRetrying  DrainEventType = "Retrying"
+	DrainEventDeleted   DrainEventType = "Deleted"
+	DrainEventCompleted DrainEventType = "Completed"
+)
+
+// DrainEvent is a progress event of a node drain.
+type DrainEvent struct {
+	Type DrainEventType `json:"type"`
+
+	// Pod the event is about, empty for events about the node.
+	Namespace string `json:"namespace,omitempty"`
+	Pod       string `json:"pod,omitempty"`
+
+	Message string `json:"message"`
+
+	Timestamp metaV1.Time `json:"timestamp"`
+}
+
+// CordonNode marks given node as unschedulable, or schedulable again when unschedulable is false.
+// Pods running on the node are not affected.
+func CordonNode(client k8sClient.Interface, name string, unschedulable bool) error {
+	log.Printf("Setting unschedulable of %s node to %t", name, unschedulable)
+
+	patch := fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, unschedulable)
+	_, err := client.CoreV1().Nodes().Patch(name, types.MergePatchType, []byte(patch))
+	return err
+}
+
+// DrainNode cordons given node and evicts its pods, respecting pod disruption budgets. Evictions
+// refused by budgets are retried until the timeout. Progress is reported to given tracker and
+// drain stops when the operation is cancelled. Events of the drain are returned.
+func DrainNode(client k8sClient.Interface, name string, options DrainOptions,
+	tracker operation.Tracker) ([]DrainEvent, error) {
+	log.Printf("Draining %s node", name)
+
+	node, err := client.CoreV1().Nodes().Get(name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pods, err := getNodePods(client, *node)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pods are checked before cordoning, so that nothing is changed when drain cannot be done.
+	toEvict, skipped, err := getPodsToEvict(name, pods.Items, options)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]DrainEvent, 0)
+	deleted := 0
+	send := func(event DrainEvent) {
+		events = append(events, event)
+		if event.Pod != "" {
+			tracker.Logf("%s %s/%s: %s", event.Type, event.Namespace, event.Pod, event.Message)
+		} else {
+			tracker.Logf("%s: %s", event.Type, event.Message)
+		}
+		if event.Type == DrainEventDeleted {
+			deleted++
+			tracker.SetProgress(deleted * 100 / len(toEvict))
+		}
+	}
+
+	if err := CordonNode(client, name, true); err != nil {
+		return events, err
+	}
+	send(newDrainEvent(DrainEventCordoned, nil, "Node marked as unschedulable"))
+	for _, pod := range skipped {
+		send(newDrainEvent(DrainEventSkipped, &pod.pod, pod.reason))
+	}
+
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = DefaultDrainTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	for i := range toEvict {
+		if err := evictPod(client, &toEvict[i], options, deadline, tracker.Cancelled(), send); err != nil {
+			return events, err
+		}
+	}
+	if err := waitForDeletion(client, toEvict, deadline, tracker.Cancelled(), send); err != nil {
+		return events, err
+	}
+
+	send(newDrainEvent(DrainEventCompleted, nil,
+		fmt.Sprintf("Evicted %d pods, skipped %d pods", len(toEvict), len(skipped))))
+	return events, nil
+}
+
+// skippedPod is a pod that is not evicted by a drain.
+type skippedPod struct {
+	pod    v1.Pod
+	reason string
+}
+
+// getPodsToEvict returns pods of given node to evict and pods to skip. Error is returned when
+// there are pods that can be evicted only with options that are not set.
+func getPodsToEvict(nodeName string, pods []v1.Pod, options DrainOptions) ([]v1.Pod, []skippedPod,
+	error) {
+	toEvict := make([]v1.Pod, 0)
+	skipped := make([]skippedPod, 0)
+	daemonSetPods := make([]string, 0)
+	unmanagedPods := make([]string, 0)
+
+	for _, pod := range pods {
+		if pod.Spec.NodeName != nodeName {
+			continue
+		}
+		controller := getController(pod)
+		switch {
+		case pod.Annotations[MirrorPodAnnotationKey] != "":
+			skipped = append(skipped, skippedPod{pod: pod, reason: "Mirror pod of a static pod"})
+		case controller != nil && controller.Kind == "DaemonSet":
+			if options.IgnoreDaemonSets {
+				skipped = append(skipped, skippedPod{pod: pod,
+					reason: "Managed by daemon set " + controller.Name})
+			} else {
+				daemonSetPods = append(daemonSetPods, pod.Namespace+"/"+pod.Name)
+			}
+		case controller == nil && !options.Force && !isPodCompleted(pod):
+			unmanagedPods = append(unmanagedPods, pod.Namespace+"/"+pod.Name)
+		default:
+			toEvict = append(toEvict, pod)
+		}
+	}
+
+	if len(daemonSetPods) > 0 {
+		return nil, nil, k8serrors.NewBadRequest("Pods managed by daemon sets, set ignoreDaemonSets " +
+			"to skip them: " + strings.Join(daemonSetPods, ", "))
+	}
+	if len(unmanagedPods) > 0 {
+		return nil, nil, k8serrors.NewBadRequest("Pods not managed by a controller, set force to " +
+			"evict them: " + strings.Join(unmanagedPods, ", "))
+	}
+	return toEvict, skipped, nil
+}
+
+// isPodCompleted returns true if given pod has succeeded or failed. Like kubectl, such pods are
+// evicted without force even if they are not managed by a controller, as no running work is lost.
+func isPodCompleted(pod v1.Pod) bool {
+	return pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed
+}
+
+func getController(pod v1.Pod) *metaV1.OwnerReference {
+	for i, reference := range pod.OwnerReferences {
+		if reference.Controller != nil && *reference.Controller {
+			return &pod.OwnerReferences[i]
+		}
+	}
+	return nil
+}
+
+// evictPod evicts given pod, retrying while disruption budgets refuse the eviction.
+func evictPod(client k8sClient.Interface, pod *v1.Pod, options DrainOptions, deadline time.Time,
+	stop <-chan struct{}, send func(DrainEvent)) error {
+	send(newDrainEvent(DrainEventEvicting, pod, "Evicting pod"))
+
+	eviction := &policy.Eviction{
+		ObjectMeta: metaV1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+	}
+	if options.GracePeriodSeconds != nil {
+		eviction.DeleteOptions = &metaV1.DeleteOptions{GracePeriodSeconds: options.GracePeriodSeconds}
+	}
+
+	for {
+		err := client.CoreV1().Pods(pod.Namespace).Evict(eviction)
+		if err == nil || k8serrors.IsNotFound(err) {
+			return nil
+		}
+		// Too many requests is returned when evicting the pod would violate a disruption budget.
+		if !k8serrors.IsTooManyRequests(err) {
+			return err
+		}
+		send(newDrainEvent(DrainEventRetrying, pod, err.Error()))
+		if err := waitForRetry(pod, deadline, stop); err != nil {
+			return err
+		}
+	}
+}
+
+// waitForDeletion waits until given evicted pods are deleted. Pods recreated with the same name are
+// told apart by UID.
+func waitForDeletion(client k8sClient.Interface, pods []v1.Pod, deadline time.Time,
+	stop <-chan struct{}, send func(DrainEvent)) error {
+	for i := range pods {
+		pod := &pods[i]
+		for {
+			current, err := client.CoreV1().Pods(pod.Namespace).Get(pod.Name, metaV1.GetOptions{})
+			if k8serrors.IsNotFound(err) || (err == nil && current.UID != pod.UID) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if err := waitForRetry(pod, deadline, stop); err != nil {
+				return err
+			}
+		}
+		send(newDrainEvent(DrainEventDeleted, pod, "Pod deleted"))
+	}
+	return nil
+}
+
+// waitForRetry waits for the retry interval and returns error when the deadline passes before it
+// or when the drain is cancelled.
+func waitForRetry(pod *v1.Pod, deadline time.Time, stop <-chan struct{}) error {
+	if time.Now().Add(drainRetryInterval).After(deadline) {
+		return fmt.Errorf("Timed out draining node at pod %s/%s", pod.Namespace, pod.Name)
+	}
+	select {
+	case <-stop:
+		return fmt.Errorf("Drain was cancelled at pod %s/%s", pod.Namespace, pod.Name)
+	case <-time.After(drainRetryInterval):
+		return nil
+	}
+}
+
+func newDrainEvent(eventType DrainEventType, pod *v1.Pod, message string) DrainEvent {
+	event := DrainEvent{Type: eventType, Message: message, Timestamp: metaV1.Now()}
+	if pod != nil {
+		event.Namespace = pod.Namespace
+		event.Pod = pod.Name
+	}
+	return event
+}
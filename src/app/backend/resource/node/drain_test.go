@@ -0,0 +1,202 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/pkg/api/v1"
+	core "k8s.io/client-go/testing"
+)
+
+func newNodePod(name, controllerKind string, annotations map[string]string) v1.Pod {
+	pod := v1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+		Spec:       v1.PodSpec{NodeName: "node-1"},
+	}
+	if controllerKind != "" {
+		controller := true
+		pod.OwnerReferences = []metaV1.OwnerReference{{Kind: controllerKind, Name: name + "-owner",
+			Controller: &controller}}
+	}
+	return pod
+}
+
+func TestGetPodsToEvict(t *testing.T) {
+	pods := []v1.Pod{
+		newNodePod("web", "ReplicaSet", nil),
+		newNodePod("agent", "DaemonSet", nil),
+		newNodePod("static", "", map[string]string{MirrorPodAnnotationKey: "hash"}),
+		newNodePod("bare", "", nil),
+	}
+
+	cases := []struct {
+		options         DrainOptions
+		expectedEvicted []string
+		expectedSkipped []string
+		expectError     bool
+	}{
+		{DrainOptions{}, nil, nil, true},
+		{DrainOptions{IgnoreDaemonSets: true}, nil, nil, true},
+		{DrainOptions{Force: true}, nil, nil, true},
+		{
+			DrainOptions{IgnoreDaemonSets: true, Force: true},
+			[]string{"web", "bare"},
+			[]string{"agent", "static"},
+			false,
+		},
+	}
+	for _, c := range cases {
+		toEvict, skipped, err := getPodsToEvict("node-1", pods, c.options)
+		if (err != nil) != c.expectError {
+			t.Errorf("getPodsToEvict(%#v) returns error %v, expected error: %v", c.options, err,
+				c.expectError)
+		}
+		if c.expectError {
+			continue
+		}
+
+		evicted := make([]string, 0)
+		for _, pod := range toEvict {
+			evicted = append(evicted, pod.Name)
+		}
+		skippedNames := make([]string, 0)
+		for _, pod := range skipped {
+			skippedNames = append(skippedNames, pod.pod.Name)
+		}
+		if !reflect.DeepEqual(evicted, c.expectedEvicted) ||
+			!reflect.DeepEqual(skippedNames, c.expectedSkipped) {
+			t.Errorf("getPodsToEvict(%#v) == \ngot %#v and skipped %#v, \nexpected %#v and skipped %#v",
+				c.options, evicted, skippedNames, c.expectedEvicted, c.expectedSkipped)
+		}
+	}
+}
+
+func TestGetPodsToEvictCompletedPods(t *testing.T) {
+	succeeded := newNodePod("succeeded", "", nil)
+	succeeded.Status.Phase = v1.PodSucceeded
+	failed := newNodePod("failed", "", nil)
+	failed.Status.Phase = v1.PodFailed
+	pods := []v1.Pod{newNodePod("web", "ReplicaSet", nil), succeeded, failed}
+
+	toEvict, _, err := getPodsToEvict("node-1", pods, DrainOptions{})
+	if err != nil {
+		t.Fatalf("getPodsToEvict() of completed pods not managed by a controller returns error: %v",
+			err)
+	}
+	evicted := make([]string, 0)
+	for _, pod := range toEvict {
+		evicted = append(evicted, pod.Name)
+	}
+	if expected := []string{"web", "succeeded", "failed"}; !reflect.DeepEqual(evicted, expected) {
+		t.Errorf("getPodsToEvict() == \ngot %#v, \nexpected %#v", evicted, expected)
+	}
+}
+
+func TestCordonNode(t *testing.T) {
+	node := &v1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "node-1"}}
+	fakeClient := fake.NewSimpleClientset(node)
+	var patch string
+	fakeClient.PrependReactor("patch", "nodes", func(action core.Action) (bool, runtime.Object, error) {
+		patch = string(action.(core.PatchActionImpl).GetPatch())
+		return true, node, nil
+	})
+
+	if err := CordonNode(fakeClient, "node-1", true); err != nil {
+		t.Fatalf("CordonNode() == \ngot err %#v", err)
+	}
+	if expected := `{"spec":{"unschedulable":true}}`; patch != expected {
+		t.Errorf("CordonNode() == \ngot patch %s, \nexpected %s", patch, expected)
+	}
+}
+
+func TestDrainNode(t *testing.T) {
+	drainRetryInterval = time.Millisecond
+	defer func() { drainRetryInterval = 5 * time.Second }()
+
+	node := &v1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "node-1"}}
+	web := newNodePod("web", "ReplicaSet", nil)
+	agent := newNodePod("agent", "DaemonSet", nil)
+	fakeClient := fake.NewSimpleClientset(node, &v1.PodList{Items: []v1.Pod{web, agent}})
+
+	fakeClient.PrependReactor("patch", "nodes", func(action core.Action) (bool, runtime.Object, error) {
+		return true, node, nil
+	})
+	evictions := 0
+	fakeClient.PrependReactor("create", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		evictions++
+		if evictions == 1 {
+			// The first eviction is refused by a disruption budget.
+			return true, nil, &k8serrors.StatusError{ErrStatus: metaV1.Status{
+				Code: http.StatusTooManyRequests, Message: "Cannot evict pod"}}
+		}
+		return true, nil, nil
+	})
+	fakeClient.PrependReactor("get", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		return true, nil, k8serrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "web")
+	})
+
+	tracker := &fakeTracker{cancelled: make(chan struct{})}
+	result, err := DrainNode(fakeClient, "node-1", DrainOptions{IgnoreDaemonSets: true,
+		Timeout: time.Minute}, tracker)
+	if err != nil {
+		t.Fatalf("DrainNode() == \ngot err %#v", err)
+	}
+
+	events := make([]DrainEventType, 0)
+	for _, event := range result {
+		events = append(events, event.Type)
+	}
+
+	expected := []DrainEventType{DrainEventCordoned, DrainEventSkipped, DrainEventEvicting,
+		DrainEventRetrying, DrainEventDeleted, DrainEventCompleted}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("DrainNode() returns \ngot %#v, \nexpected %#v", events, expected)
+	}
+	if len(tracker.logs) != len(expected) || tracker.progress != 100 {
+		t.Errorf("DrainNode() reports %d log lines and progress %d, expected %d log lines and "+
+			"progress 100", len(tracker.logs), tracker.progress, len(expected))
+	}
+}
+
+type fakeTracker struct {
+	logs      []string
+	progress  int
+	cancelled chan struct{}
+}
+
+func (self *fakeTracker) Logf(format string, args ...interface{}) {
+	self.logs = append(self.logs, fmt.Sprintf(format, args...))
+}
+
+func (self *fakeTracker) SetProgress(percent int) {
+	self.progress = percent
+}
+
+func (self *fakeTracker) Cancelled() <-chan struct{} {
+	return self.cancelled
+}
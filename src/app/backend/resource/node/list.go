@@ -18,7 +18,7 @@ import (
 	"log"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
@@ -43,11 +43,12 @@ type Node struct {
 	TypeMeta           api.TypeMeta           `json:"typeMeta"`
 	Ready              v1.ConditionStatus     `json:"ready"`
 	AllocatedResources NodeAllocatedResources `json:"allocatedResources"`
+	Provisioner        *NodeProvisioner       `json:"provisioner,omitempty"`
 }
 
 // GetNodeListFromChannels returns a list of all Nodes in the cluster.
 func GetNodeListFromChannels(client client.Interface, channels *common.ResourceChannels, dsQuery *dataselect.DataSelectQuery,
-	heapsterClient heapster.HeapsterClient) (*NodeList, error) {
+	heapsterClient metricapi.MetricClient) (*NodeList, error) {
 	log.Print("Getting node list")
 
 	nodes := <-channels.NodeList.List
@@ -59,7 +60,7 @@ func GetNodeListFromChannels(client client.Interface, channels *common.ResourceC
 }
 
 // GetNodeList returns a list of all Nodes in the cluster.
-func GetNodeList(client client.Interface, dsQuery *dataselect.DataSelectQuery, heapsterClient heapster.HeapsterClient) (*NodeList, error) {
+func GetNodeList(client client.Interface, dsQuery *dataselect.DataSelectQuery, heapsterClient metricapi.MetricClient) (*NodeList, error) {
 	log.Print("Getting list of all nodes in the cluster")
 
 	nodes, err := client.CoreV1().Nodes().List(metaV1.ListOptions{
@@ -75,7 +76,7 @@ func GetNodeList(client client.Interface, dsQuery *dataselect.DataSelectQuery, h
 }
 
 func toNodeList(client client.Interface, nodes []v1.Node, dsQuery *dataselect.DataSelectQuery,
-	heapsterClient heapster.HeapsterClient) *NodeList {
+	heapsterClient metricapi.MetricClient) *NodeList {
 	nodeList := &NodeList{
 		Nodes:    make([]Node, 0),
 		ListMeta: api.ListMeta{TotalItems: len(nodes)},
@@ -84,7 +85,7 @@ func toNodeList(client client.Interface, nodes []v1.Node, dsQuery *dataselect.Da
 	nodeCells, metricPromises, filteredTotal := dataselect.GenericDataSelectWithFilterAndMetrics(toCells(nodes),
 		dsQuery, dataselect.NoResourceCache, &heapsterClient)
 	nodes = fromCells(nodeCells)
-	nodeList.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+	nodeList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, nodeList.ListMeta.TotalItems)
 
 	for _, node := range nodes {
 		pods, err := getNodePods(client, node)
@@ -107,10 +108,13 @@ func toNodeList(client client.Interface, nodes []v1.Node, dsQuery *dataselect.Da
 }
 
 func toNode(node v1.Node, pods *v1.PodList) Node {
-	allocatedResources, err := getNodeAllocatedResources(node, pods)
+	var allocatedResources NodeAllocatedResources
+	requests, limits, err := getPodRequestsAndLimits(pods)
 	if err != nil {
 		log.Printf("Couldn't get allocated resources of %s node\n", node.Name)
 		log.Println(err)
+	} else {
+		allocatedResources = getNodeAllocatedResources(node, len(pods.Items), requests, limits)
 	}
 
 	return Node{
@@ -118,6 +122,7 @@ func toNode(node v1.Node, pods *v1.PodList) Node {
 		TypeMeta:           api.NewTypeMeta(api.ResourceKindNode),
 		Ready:              getNodeConditionStatus(node, v1.NodeReady),
 		AllocatedResources: allocatedResources,
+		Provisioner:        getNodeProvisioner(node),
 	}
 }
 
@@ -40,7 +40,8 @@ func TestGetNodeList(t *testing.T) {
 			},
 			&NodeList{
 				ListMeta: api.ListMeta{
-					TotalItems: 1,
+					TotalItems:        1,
+					TotalBeforeFilter: 1,
 				},
 				CumulativeMetrics: make([]metric.Metric, 0),
 				Nodes: []Node{{
@@ -70,7 +71,7 @@ func TestGetNodeList(t *testing.T) {
 
 	for _, c := range cases {
 		fakeClient := fake.NewSimpleClientset(c.node)
-		fakeHeapsterClient := FakeHeapsterClient{client: *fake.NewSimpleClientset()}
+		fakeHeapsterClient := FakeHeapsterClient{client: fake.NewSimpleClientset()}
 		actual, _ := GetNodeList(fakeClient, dataselect.NoDataSelect, fakeHeapsterClient)
 		if !reflect.DeepEqual(actual, c.expected) {
 			t.Errorf("GetNodeList() == \ngot: %#v, \nexpected %#v", actual, c.expected)
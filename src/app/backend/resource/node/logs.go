@@ -0,0 +1,204 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	k8sClient "k8s.io/client-go/kubernetes"
+)
+
+// kubeletLogsPath is the kubelet endpoint that serves files from the /var/log directory of a node.
+const kubeletLogsPath = "logs/"
+
+// Limits of node logs held in memory. Kubelet serves whole files, which can be hundreds of
+// megabytes, so they are streamed and only the last lines are kept.
+const (
+	// Maximum length of a log line, longer lines are truncated.
+	maxNodeLogLineLength = 64 * 1024
+
+	// Maximum number of bytes of log lines held, older lines are dropped above it.
+	maxNodeLogBytes = 10 * 1024 * 1024
+)
+
+// logFileHrefRegexp matches entries of the directory listing served by kubelet.
+var logFileHrefRegexp = regexp.MustCompile(`<a href="([^"]+)">`)
+
+// NodeLogFileList contains names of log files available on a node.
+type NodeLogFileList struct {
+	NodeName string   `json:"nodeName"`
+	Files    []string `json:"files"`
+}
+
+// NodeLog contains log lines read from a node through the kubelet.
+type NodeLog struct {
+	NodeName string `json:"nodeName"`
+
+	// Path of the file relative to /var/log on the node or journal unit name.
+	Path string `json:"path"`
+
+	// Last lines of the log.
+	Lines []string `json:"lines"`
+}
+
+// GetNodeLogFiles returns list of log files on the node with given name. Files are read through
+// the node proxy subresource, so the request is authorized against nodes/proxy permission of the
+// user.
+func GetNodeLogFiles(client k8sClient.Interface, name string) (*NodeLogFileList, error) {
+	log.Printf("Getting log files of %s node", name)
+
+	raw, err := client.CoreV1().RESTClient().Get().
+		Resource("nodes").
+		Name(name).
+		SubResource("proxy").
+		Suffix(kubeletLogsPath).
+		DoRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	return &NodeLogFileList{NodeName: name, Files: parseLogFileList(string(raw))}, nil
+}
+
+// GetNodeLog returns last tailLines lines of the log file with given path relative to /var/log on
+// the node, e.g. kubelet.log or pods/.
+func GetNodeLog(client k8sClient.Interface, name, path string, tailLines int) (*NodeLog, error) {
+	log.Printf("Getting %s log of %s node", path, name)
+
+	if err := validateLogPath(path); err != nil {
+		return nil, err
+	}
+
+	stream, err := client.CoreV1().RESTClient().Get().
+		Resource("nodes").
+		Name(name).
+		SubResource("proxy").
+		Suffix(kubeletLogsPath + path).
+		Stream()
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	lines, err := tailLogLines(stream, tailLines)
+	if err != nil {
+		return nil, err
+	}
+	return &NodeLog{NodeName: name, Path: path, Lines: lines}, nil
+}
+
+// GetNodeJournal returns last tailLines lines of the journal of given service unit on the node,
+// e.g. kubelet. Requires kubelet with node log query support enabled.
+func GetNodeJournal(client k8sClient.Interface, name, unit string, tailLines int) (*NodeLog, error) {
+	log.Printf("Getting %s journal of %s node", unit, name)
+
+	if err := validateLogPath(unit); err != nil {
+		return nil, err
+	}
+
+	stream, err := client.CoreV1().RESTClient().Get().
+		Resource("nodes").
+		Name(name).
+		SubResource("proxy").
+		Suffix(kubeletLogsPath).
+		Param("query", unit).
+		Param("tailLines", strconv.Itoa(tailLines)).
+		Stream()
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	lines, err := tailLogLines(stream, tailLines)
+	if err != nil {
+		return nil, err
+	}
+	return &NodeLog{NodeName: name, Path: unit, Lines: lines}, nil
+}
+
+// validateLogPath rejects paths that try to escape /var/log directory of the node.
+func validateLogPath(path string) error {
+	if path == "" || strings.HasPrefix(path, "/") {
+		return errors.New("Log path has to be relative to /var/log directory")
+	}
+
+	for _, part := range strings.Split(path, "/") {
+		if part == ".." {
+			return errors.New("Log path cannot contain '..'")
+		}
+	}
+
+	return nil
+}
+
+// parseLogFileList returns file names from the HTML directory listing served by kubelet.
+func parseLogFileList(listing string) []string {
+	files := make([]string, 0)
+	for _, matches := range logFileHrefRegexp.FindAllStringSubmatch(listing, -1) {
+		files = append(files, matches[1])
+	}
+	return files
+}
+
+// tailLogLines reads log lines from given reader and returns last tailLines of them, or all of
+// them when tailLines is not positive. At most tailLines lines and maxNodeLogBytes bytes are held
+// while reading, so fewer lines are returned when they don't fit in maxNodeLogBytes.
+func tailLogLines(reader io.Reader, tailLines int) ([]string, error) {
+	buffered := bufio.NewReader(reader)
+	lines := make([]string, 0)
+	size := 0
+	for {
+		line, err := readLogLine(buffered)
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		lines = append(lines, line)
+		size += len(line) + 1
+		for (tailLines > 0 && len(lines) > tailLines) || size > maxNodeLogBytes {
+			size -= len(lines[0]) + 1
+			lines = lines[1:]
+		}
+	}
+}
+
+// readLogLine reads next line from given reader, truncated to maxNodeLogLineLength.
+func readLogLine(reader *bufio.Reader) (string, error) {
+	var line []byte
+	for {
+		fragment, isPrefix, err := reader.ReadLine()
+		if err != nil {
+			return "", err
+		}
+		if room := maxNodeLogLineLength - len(line); room > 0 {
+			if len(fragment) > room {
+				fragment = fragment[:room]
+			}
+			line = append(line, fragment...)
+		}
+		if !isPrefix {
+			return string(line), nil
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLogFileList(t *testing.T) {
+	cases := []struct {
+		listing  string
+		expected []string
+	}{
+		{"", []string{}},
+		{
+			"<pre>\n<a href=\"kubelet.log\">kubelet.log</a>\n<a href=\"pods/\">pods/</a>\n</pre>\n",
+			[]string{"kubelet.log", "pods/"},
+		},
+	}
+
+	for _, c := range cases {
+		actual := parseLogFileList(c.listing)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("parseLogFileList(%#v) == \ngot: %#v, \nexpected %#v", c.listing, actual, c.expected)
+		}
+	}
+}
+
+func TestTailLogLines(t *testing.T) {
+	cases := []struct {
+		raw       string
+		tailLines int
+		expected  []string
+	}{
+		{"", 10, []string{}},
+		{"a\nb\nc\n", 0, []string{"a", "b", "c"}},
+		{"a\nb\nc\n", 2, []string{"b", "c"}},
+		{"a\nb\nc", 5, []string{"a", "b", "c"}},
+		{"a\n\nb\n", 2, []string{"", "b"}},
+		{strings.Repeat("a", maxNodeLogLineLength+10) + "\nb\n", 2,
+			[]string{strings.Repeat("a", maxNodeLogLineLength), "b"}},
+	}
+
+	for _, c := range cases {
+		actual, err := tailLogLines(strings.NewReader(c.raw), c.tailLines)
+		if err != nil {
+			t.Fatalf("tailLogLines(%#v, %#v) returns error: %v", c.raw, c.tailLines, err)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("tailLogLines(%#v, %#v) == \ngot: %#v, \nexpected %#v",
+				c.raw, c.tailLines, actual, c.expected)
+		}
+	}
+}
+
+func TestValidateLogPath(t *testing.T) {
+	cases := []struct {
+		path     string
+		expected bool
+	}{
+		{"kubelet.log", true},
+		{"pods/default_foo/bar/0.log", true},
+		{"", false},
+		{"/etc/shadow", false},
+		{"../etc/shadow", false},
+		{"pods/../../etc", false},
+	}
+
+	for _, c := range cases {
+		actual := validateLogPath(c.path) == nil
+		if actual != c.expected {
+			t.Errorf("validateLogPath(%#v) == %#v, expected %#v", c.path, actual, c.expected)
+		}
+	}
+}
+
+func TestTailLogLinesLimitsAllLines(t *testing.T) {
+	line := strings.Repeat("a", 1023) + "\n"
+	raw := strings.Repeat(line, 2*maxNodeLogBytes/len(line))
+
+	for _, tailLines := range []int{0, 1 << 30} {
+		actual, err := tailLogLines(strings.NewReader(raw), tailLines)
+		if err != nil {
+			t.Fatalf("tailLogLines() returns error: %v", err)
+		}
+		if expected := maxNodeLogBytes / len(line); len(actual) != expected {
+			t.Errorf("tailLogLines(%d) of %d bytes returns %d lines, expected %d", tailLines, len(raw),
+				len(actual), expected)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"log"
+	"sort"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sClient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+// FlappingReadyTransitionsThreshold is the number of Ready condition transitions after which the node
+// is considered flapping.
+const FlappingReadyTransitionsThreshold = 3
+
+// Condition type used in the timeline for node schedulability changes.
+const nodeSchedulableConditionType = "Schedulable"
+
+// Sources of node condition transitions.
+const (
+	TransitionSourceEvent  = "event"
+	TransitionSourceStatus = "status"
+)
+
+// conditionTransition maps kubelet event reason to the condition change it reports.
+type conditionTransition struct {
+	conditionType string
+	status        v1.ConditionStatus
+}
+
+// nodeEventReasons maps reasons of events recorded by kubelet and node controller to condition
+// transitions.
+var nodeEventReasons = map[string]conditionTransition{
+	"NodeReady":                 {string(v1.NodeReady), v1.ConditionTrue},
+	"NodeNotReady":              {string(v1.NodeReady), v1.ConditionFalse},
+	"NodeHasSufficientMemory":   {string(v1.NodeMemoryPressure), v1.ConditionFalse},
+	"NodeHasInsufficientMemory": {string(v1.NodeMemoryPressure), v1.ConditionTrue},
+	"NodeHasNoDiskPressure":     {string(v1.NodeDiskPressure), v1.ConditionFalse},
+	"NodeHasDiskPressure":       {string(v1.NodeDiskPressure), v1.ConditionTrue},
+	"NodeHasSufficientDisk":     {string(v1.NodeOutOfDisk), v1.ConditionFalse},
+	"NodeOutOfDisk":             {string(v1.NodeOutOfDisk), v1.ConditionTrue},
+	"NodeHasSufficientPID":      {"PIDPressure", v1.ConditionFalse},
+	"NodeHasInsufficientPID":    {"PIDPressure", v1.ConditionTrue},
+	"NodeSchedulable":           {nodeSchedulableConditionType, v1.ConditionTrue},
+	"NodeNotSchedulable":        {nodeSchedulableConditionType, v1.ConditionFalse},
+}
+
+// NodeConditionTransition is a single change of node condition.
+type NodeConditionTransition struct {
+	// Type of the condition, e.g. Ready or MemoryPressure.
+	Type string `json:"type"`
+
+	// Status of the condition after the transition.
+	Status v1.ConditionStatus `json:"status"`
+
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+
+	// Time of the last occurrence of the transition.
+	Time metaV1.Time `json:"time"`
+
+	// Number of times the transition was observed.
+	Count int32 `json:"count"`
+
+	// Where the transition comes from, i.e. event or current node status.
+	Source string `json:"source"`
+}
+
+// NodeConditionTimeline is a list of condition transitions of a node ordered by time.
+type NodeConditionTimeline struct {
+	NodeName    string                    `json:"nodeName"`
+	Transitions []NodeConditionTransition `json:"transitions"`
+
+	// Number of observed Ready condition transitions.
+	ReadyTransitions int32 `json:"readyTransitions"`
+
+	// True when the Ready condition changed at least FlappingReadyTransitionsThreshold times.
+	Flapping bool `json:"flapping"`
+}
+
+// GetNodeConditionTimeline returns timeline of condition transitions of the node with given name
+// built from node events and current node status.
+func GetNodeConditionTimeline(client k8sClient.Interface, name string) (*NodeConditionTimeline, error) {
+	log.Printf("Getting condition timeline of %s node", name)
+
+	node, err := client.CoreV1().Nodes().Get(name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := event.GetEvents(client, v1.NamespaceAll, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return toNodeConditionTimeline(*node, events), nil
+}
+
+func toNodeConditionTimeline(node v1.Node, events []v1.Event) *NodeConditionTimeline {
+	timeline := &NodeConditionTimeline{
+		NodeName:    node.Name,
+		Transitions: make([]NodeConditionTransition, 0),
+	}
+
+	// Latest transition time of each condition type seen in events. Used to skip status
+	// transitions that are already covered by events.
+	latest := make(map[string]metaV1.Time)
+
+	for _, e := range events {
+		if e.InvolvedObject.Kind != "Node" {
+			continue
+		}
+
+		transition, ok := nodeEventReasons[e.Reason]
+		if !ok {
+			continue
+		}
+
+		count := e.Count
+		if count < 1 {
+			count = 1
+		}
+
+		timeline.Transitions = append(timeline.Transitions, NodeConditionTransition{
+			Type:    transition.conditionType,
+			Status:  transition.status,
+			Reason:  e.Reason,
+			Message: e.Message,
+			Time:    e.LastTimestamp,
+			Count:   count,
+			Source:  TransitionSourceEvent,
+		})
+
+		if previous, ok := latest[transition.conditionType]; !ok || previous.Before(e.LastTimestamp) {
+			latest[transition.conditionType] = e.LastTimestamp
+		}
+	}
+
+	for _, condition := range node.Status.Conditions {
+		if condition.LastTransitionTime.IsZero() {
+			continue
+		}
+		if previous, ok := latest[string(condition.Type)]; ok &&
+			!previous.Before(condition.LastTransitionTime) {
+			continue
+		}
+
+		timeline.Transitions = append(timeline.Transitions, NodeConditionTransition{
+			Type:    string(condition.Type),
+			Status:  condition.Status,
+			Reason:  condition.Reason,
+			Message: condition.Message,
+			Time:    condition.LastTransitionTime,
+			Count:   1,
+			Source:  TransitionSourceStatus,
+		})
+	}
+
+	sort.Stable(transitionsByTime(timeline.Transitions))
+
+	for _, transition := range timeline.Transitions {
+		if transition.Type == string(v1.NodeReady) {
+			timeline.ReadyTransitions += transition.Count
+		}
+	}
+	timeline.Flapping = timeline.ReadyTransitions >= FlappingReadyTransitionsThreshold
+
+	return timeline
+}
+
+// transitionsByTime sorts node condition transitions from the oldest to the newest.
+type transitionsByTime []NodeConditionTransition
+
+func (self transitionsByTime) Len() int      { return len(self) }
+func (self transitionsByTime) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+func (self transitionsByTime) Less(i, j int) bool {
+	return self[i].Time.Before(self[j].Time)
+}
@@ -0,0 +1,82 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+func TestToNodeConditionTimeline(t *testing.T) {
+	t1 := metaV1.NewTime(time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC))
+	t2 := metaV1.NewTime(time.Date(2017, 5, 5, 10, 5, 0, 0, time.UTC))
+	t3 := metaV1.NewTime(time.Date(2017, 5, 5, 10, 10, 0, 0, time.UTC))
+	nodeRef := v1.ObjectReference{Kind: "Node", Name: "test-node"}
+
+	cases := []struct {
+		node     v1.Node
+		events   []v1.Event
+		expected *NodeConditionTimeline
+	}{
+		{
+			v1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "test-node"}},
+			nil,
+			&NodeConditionTimeline{NodeName: "test-node", Transitions: []NodeConditionTransition{}},
+		},
+		{
+			v1.Node{
+				ObjectMeta: metaV1.ObjectMeta{Name: "test-node"},
+				Status: v1.NodeStatus{Conditions: []v1.NodeCondition{
+					{Type: v1.NodeReady, Status: v1.ConditionTrue, Reason: "KubeletReady",
+						LastTransitionTime: t2},
+					{Type: v1.NodeDiskPressure, Status: v1.ConditionTrue, Reason: "KubeletHasDiskPressure",
+						LastTransitionTime: t1},
+				}},
+			},
+			[]v1.Event{
+				{InvolvedObject: nodeRef, Reason: "NodeNotReady", Count: 2, LastTimestamp: t1},
+				{InvolvedObject: nodeRef, Reason: "NodeReady", Count: 2, LastTimestamp: t3},
+				{InvolvedObject: nodeRef, Reason: "Starting", LastTimestamp: t1},
+				{InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "test-node"},
+					Reason: "NodeNotReady", LastTimestamp: t1},
+			},
+			&NodeConditionTimeline{
+				NodeName: "test-node",
+				Transitions: []NodeConditionTransition{
+					{Type: "Ready", Status: v1.ConditionFalse, Reason: "NodeNotReady", Time: t1, Count: 2,
+						Source: TransitionSourceEvent},
+					{Type: "DiskPressure", Status: v1.ConditionTrue, Reason: "KubeletHasDiskPressure",
+						Time: t1, Count: 1, Source: TransitionSourceStatus},
+					{Type: "Ready", Status: v1.ConditionTrue, Reason: "NodeReady", Time: t3, Count: 2,
+						Source: TransitionSourceEvent},
+				},
+				ReadyTransitions: 4,
+				Flapping:         true,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := toNodeConditionTimeline(c.node, c.events)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("toNodeConditionTimeline(%#v, %#v) == \ngot: %#v, \nexpected %#v",
+				c.node, c.events, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package overview
+
+import (
+	"log"
+	"sort"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	helper "k8s.io/client-go/pkg/api/v1/resource"
+)
+
+// DefaultTopConsumers is a default number of top consumers returned for each resource.
+const DefaultTopConsumers = 5
+
+// Overview is a cluster-level summary of node capacity and its use by pods.
+type Overview struct {
+	Nodes NodeSummary `json:"nodes"`
+
+	// Sums of capacity and allocatable resources of all nodes.
+	Capacity    ResourceSummary `json:"capacity"`
+	Allocatable ResourceSummary `json:"allocatable"`
+
+	// Sums of requests and limits of all pods, that are not terminated. Pods field is a number of
+	// these pods.
+	Requests ResourceSummary `json:"requests"`
+	Limits   ResourceSummary `json:"limits"`
+
+	Pods PodSummary `json:"pods"`
+
+	// Pods with the highest cpu and memory requests, in descending order.
+	TopCPUConsumers    []Consumer `json:"topCpuConsumers"`
+	TopMemoryConsumers []Consumer `json:"topMemoryConsumers"`
+}
+
+// NodeSummary contains numbers of nodes in the cluster.
+type NodeSummary struct {
+	Total         int `json:"total"`
+	Ready         int `json:"ready"`
+	Unschedulable int `json:"unschedulable"`
+}
+
+// ResourceSummary contains amounts of cpu, memory and pods.
+type ResourceSummary struct {
+	// Number of milicores.
+	CPU int64 `json:"cpu"`
+
+	// Number of bytes.
+	Memory int64 `json:"memory"`
+
+	Pods int64 `json:"pods"`
+}
+
+// PodSummary contains numbers of pods in the cluster.
+type PodSummary struct {
+	Total int `json:"total"`
+
+	// Numbers of pods by phase, e.g. Running.
+	Phases map[v1.PodPhase]int `json:"phases"`
+}
+
+// Consumer is a pod with its resource requests.
+type Consumer struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// Number of requested milicores.
+	CPURequests int64 `json:"cpuRequests"`
+
+	// Number of requested bytes.
+	MemoryRequests int64 `json:"memoryRequests"`
+}
+
+// GetOverview returns a summary of capacity of the cluster and its use, with given number of top
+// consumers of each resource.
+func GetOverview(client client.Interface, top int) (*Overview, error) {
+	log.Print("Getting cluster overview")
+	channels := &common.ResourceChannels{
+		NodeList: common.GetNodeListChannel(client, 1),
+		PodList:  common.GetPodListChannel(client, common.NewNamespaceQuery(nil), 1),
+	}
+
+	return GetOverviewFromChannels(channels, top)
+}
+
+// GetOverviewFromChannels returns a summary of capacity of the cluster and its use, from the
+// channel sources.
+func GetOverviewFromChannels(channels *common.ResourceChannels, top int) (*Overview, error) {
+	nodes := <-channels.NodeList.List
+	if err := <-channels.NodeList.Error; err != nil {
+		return nil, err
+	}
+
+	pods := <-channels.PodList.List
+	if err := <-channels.PodList.Error; err != nil {
+		return nil, err
+	}
+
+	return toOverview(nodes.Items, pods.Items, top)
+}
+
+func toOverview(nodes []v1.Node, pods []v1.Pod, top int) (*Overview, error) {
+	overview := &Overview{
+		Pods:               PodSummary{Phases: make(map[v1.PodPhase]int)},
+		TopCPUConsumers:    make([]Consumer, 0),
+		TopMemoryConsumers: make([]Consumer, 0),
+	}
+
+	for _, node := range nodes {
+		overview.Nodes.Total++
+		if isNodeReady(node) {
+			overview.Nodes.Ready++
+		}
+		if node.Spec.Unschedulable {
+			overview.Nodes.Unschedulable++
+		}
+		addResources(&overview.Capacity, node.Status.Capacity)
+		addResources(&overview.Allocatable, node.Status.Allocatable)
+	}
+
+	consumers := make([]Consumer, 0)
+	for _, pod := range pods {
+		overview.Pods.Total++
+		overview.Pods.Phases[pod.Status.Phase]++
+		if isTerminated(pod) {
+			continue
+		}
+
+		reqs, limits, err := helper.PodRequestsAndLimits(&pod)
+		if err != nil {
+			return nil, err
+		}
+		addResources(&overview.Requests, reqs)
+		addResources(&overview.Limits, limits)
+		overview.Requests.Pods++
+		overview.Limits.Pods++
+
+		cpu, memory := reqs[v1.ResourceCPU], reqs[v1.ResourceMemory]
+		consumers = append(consumers, Consumer{
+			Namespace:      pod.Namespace,
+			Name:           pod.Name,
+			CPURequests:    cpu.MilliValue(),
+			MemoryRequests: memory.Value(),
+		})
+	}
+
+	sort.Sort(consumersByCPU(consumers))
+	overview.TopCPUConsumers = getTopConsumers(consumers, top,
+		func(consumer Consumer) int64 { return consumer.CPURequests })
+	sort.Sort(consumersByMemory(consumers))
+	overview.TopMemoryConsumers = getTopConsumers(consumers, top,
+		func(consumer Consumer) int64 { return consumer.MemoryRequests })
+
+	return overview, nil
+}
+
+// addResources adds cpu and memory of given resource list to the summary. Pods are added only
+// when the list has them, i.e. for node capacity.
+func addResources(summary *ResourceSummary, resources v1.ResourceList) {
+	cpu, memory := resources[v1.ResourceCPU], resources[v1.ResourceMemory]
+	summary.CPU += cpu.MilliValue()
+	summary.Memory += memory.Value()
+	if pods, ok := resources[v1.ResourcePods]; ok {
+		summary.Pods += pods.Value()
+	}
+}
+
+// getTopConsumers returns at most top of given sorted consumers, skipping the ones that don't
+// request the resource at all.
+func getTopConsumers(consumers []Consumer, top int, requests func(Consumer) int64) []Consumer {
+	result := make([]Consumer, 0)
+	for _, consumer := range consumers {
+		if len(result) >= top || requests(consumer) == 0 {
+			break
+		}
+		result = append(result, consumer)
+	}
+	return result
+}
+
+func isNodeReady(node v1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// isTerminated returns true if the pod doesn't use node resources anymore.
+func isTerminated(pod v1.Pod) bool {
+	return pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed
+}
+
+type consumersByCPU []Consumer
+
+func (self consumersByCPU) Len() int      { return len(self) }
+func (self consumersByCPU) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+func (self consumersByCPU) Less(i, j int) bool {
+	if self[i].CPURequests != self[j].CPURequests {
+		return self[i].CPURequests > self[j].CPURequests
+	}
+	return self[i].Namespace+"/"+self[i].Name < self[j].Namespace+"/"+self[j].Name
+}
+
+type consumersByMemory []Consumer
+
+func (self consumersByMemory) Len() int      { return len(self) }
+func (self consumersByMemory) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+func (self consumersByMemory) Less(i, j int) bool {
+	if self[i].MemoryRequests != self[j].MemoryRequests {
+		return self[i].MemoryRequests > self[j].MemoryRequests
+	}
+	return self[i].Namespace+"/"+self[i].Name < self[j].Namespace+"/"+self[j].Name
+}
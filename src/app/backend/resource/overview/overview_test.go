@@ -0,0 +1,121 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package overview
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+func newPod(name string, phase v1.PodPhase, cpu, memory string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: v1.PodSpec{Containers: []v1.Container{{
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse(cpu),
+					v1.ResourceMemory: resource.MustParse(memory),
+				},
+				Limits: v1.ResourceList{
+					v1.ResourceCPU: resource.MustParse(cpu),
+				},
+			},
+		}}},
+		Status: v1.PodStatus{Phase: phase},
+	}
+}
+
+func TestGetOverview(t *testing.T) {
+	nodes := &v1.NodeList{Items: []v1.Node{
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "node-1"},
+			Status: v1.NodeStatus{
+				Capacity: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("4"),
+					v1.ResourceMemory: resource.MustParse("4Gi"),
+					v1.ResourcePods:   resource.MustParse("110"),
+				},
+				Allocatable: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("3"),
+					v1.ResourceMemory: resource.MustParse("3Gi"),
+					v1.ResourcePods:   resource.MustParse("110"),
+				},
+				Conditions: []v1.NodeCondition{
+					{Type: v1.NodeReady, Status: v1.ConditionTrue},
+				},
+			},
+		},
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "node-2"},
+			Spec:       v1.NodeSpec{Unschedulable: true},
+			Status: v1.NodeStatus{
+				Capacity: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("2"),
+					v1.ResourceMemory: resource.MustParse("2Gi"),
+					v1.ResourcePods:   resource.MustParse("110"),
+				},
+				Conditions: []v1.NodeCondition{
+					{Type: v1.NodeReady, Status: v1.ConditionUnknown},
+				},
+			},
+		},
+	}}
+	pods := &v1.PodList{Items: []v1.Pod{
+		*newPod("small", v1.PodRunning, "100m", "1Gi"),
+		*newPod("big", v1.PodRunning, "1", "512Mi"),
+		*newPod("pending", v1.PodPending, "200m", "256Mi"),
+		*newPod("done", v1.PodSucceeded, "2", "2Gi"),
+	}}
+
+	expected := &Overview{
+		Nodes:       NodeSummary{Total: 2, Ready: 1, Unschedulable: 1},
+		Capacity:    ResourceSummary{CPU: 6000, Memory: 6 * 1024 * 1024 * 1024, Pods: 220},
+		Allocatable: ResourceSummary{CPU: 3000, Memory: 3 * 1024 * 1024 * 1024, Pods: 110},
+		Requests: ResourceSummary{CPU: 1300,
+			Memory: (1024 + 512 + 256) * 1024 * 1024, Pods: 3},
+		Limits: ResourceSummary{CPU: 1300, Pods: 3},
+		Pods: PodSummary{Total: 4, Phases: map[v1.PodPhase]int{
+			v1.PodRunning: 2, v1.PodPending: 1, v1.PodSucceeded: 1}},
+		TopCPUConsumers: []Consumer{
+			{Namespace: "default", Name: "big", CPURequests: 1000, MemoryRequests: 512 * 1024 * 1024},
+			{Namespace: "default", Name: "pending", CPURequests: 200,
+				MemoryRequests: 256 * 1024 * 1024},
+		},
+		TopMemoryConsumers: []Consumer{
+			{Namespace: "default", Name: "small", CPURequests: 100, MemoryRequests: 1024 * 1024 * 1024},
+			{Namespace: "default", Name: "big", CPURequests: 1000, MemoryRequests: 512 * 1024 * 1024},
+		},
+	}
+
+	fakeClient := fake.NewSimpleClientset(nodes, pods)
+	channels := &common.ResourceChannels{
+		NodeList: common.GetNodeListChannel(fakeClient, 1),
+		PodList:  common.GetPodListChannel(fakeClient, common.NewNamespaceQuery(nil), 1),
+	}
+
+	actual, err := GetOverviewFromChannels(channels, 2)
+	if err != nil {
+		t.Fatalf("GetOverviewFromChannels() == \ngot err %#v", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GetOverviewFromChannels() == \ngot %#v, \nexpected %#v", actual, expected)
+	}
+}
@@ -78,7 +78,7 @@ func getPersistentVolumeList(persistentVolumes []v1.PersistentVolume, dsQuery *d
 
 	pvCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(persistentVolumes), dsQuery)
 	persistentVolumes = fromCells(pvCells)
-	result.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+	result.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, result.ListMeta.TotalItems)
 
 	for _, item := range persistentVolumes {
 		result.Items = append(result.Items,
@@ -50,7 +50,7 @@ func TestGetPersistentVolumeList(t *testing.T) {
 				},
 			},
 			&PersistentVolumeList{
-				ListMeta: api.ListMeta{TotalItems: 1},
+				ListMeta: api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
 				Items: []PersistentVolume{{
 					TypeMeta:    api.TypeMeta{Kind: "persistentvolume"},
 					ObjectMeta:  api.ObjectMeta{Name: "foo"},
@@ -82,7 +82,7 @@ func getPersistentVolumeClaimList(persistentVolumeClaims []v1.PersistentVolumeCl
 
 	pvcCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(persistentVolumeClaims), dsQuery)
 	persistentVolumeClaims = fromCells(pvcCells)
-	result.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+	result.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, result.ListMeta.TotalItems)
 
 	for _, item := range persistentVolumeClaims {
 		result.Items = append(result.Items,
@@ -24,7 +24,7 @@ func TestGetPersistentVolumeClaimList(t *testing.T) {
 			},
 			},
 			&PersistentVolumeClaimList{
-				ListMeta: api.ListMeta{TotalItems: 1},
+				ListMeta: api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
 				Items: []PersistentVolumeClaim{{
 					TypeMeta:   api.TypeMeta{Kind: "persistentvolumeclaim"},
 					ObjectMeta: api.ObjectMeta{Name: "foo"},
@@ -15,13 +15,20 @@
 package pod
 
 import (
+	"strconv"
+
 	"github.com/kubernetes/dashboard/src/app/backend/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/pkg/api/v1"
 )
 
+// CriticalPodAnnotationKey is annotation key marking pods in kube-system namespace as critical.
+// Critical pods are not preempted by the rescheduler.
+const CriticalPodAnnotationKey = "scheduler.alpha.kubernetes.io/critical-pod"
+
 // Gets restart count of given pod (total number of its containers restarts).
 func getRestartCount(pod v1.Pod) int32 {
 	var restartCount int32 = 0
@@ -89,6 +96,8 @@ func ToPod(pod *v1.Pod, metrics *common.MetricsByPod, warnings []common.Event) P
 		TypeMeta:     api.NewTypeMeta(api.ResourceKindPod),
 		PodStatus:    getPodStatus(*pod, warnings),
 		RestartCount: getRestartCount(*pod),
+		QOSClass:     getPodQOSClass(*pod),
+		Preemptible:  isPreemptible(*pod),
 	}
 
 	if metrics != nil && metrics.MetricsMap[pod.Namespace] != nil {
@@ -99,6 +108,50 @@ func ToPod(pod *v1.Pod, metrics *common.MetricsByPod, warnings []common.Event) P
 	return podDetail
 }
 
+// getPodQOSClass returns quality of service class of given pod. It is computed from resources of
+// the pod containers if the kubelet has not set it yet.
+func getPodQOSClass(pod v1.Pod) v1.PodQOSClass {
+	if pod.Status.QOSClass != "" {
+		return pod.Status.QOSClass
+	}
+
+	containers := make([]v1.Container, 0, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+	containers = append(append(containers, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	bestEffort, guaranteed := true, true
+	for _, container := range containers {
+		requests, limits := container.Resources.Requests, container.Resources.Limits
+		if len(requests) > 0 || len(limits) > 0 {
+			bestEffort = false
+		}
+		for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+			limit, ok := limits[name]
+			if !ok {
+				guaranteed = false
+				continue
+			}
+			if request, ok := requests[name]; ok && request.Cmp(limit) != 0 {
+				guaranteed = false
+			}
+		}
+	}
+
+	switch {
+	case bestEffort:
+		return v1.PodQOSBestEffort
+	case guaranteed:
+		return v1.PodQOSGuaranteed
+	default:
+		return v1.PodQOSBurstable
+	}
+}
+
+// isPreemptible returns true if given pod is not a critical pod, which means it can be evicted to
+// make room for critical pods. Only pods in kube-system namespace can be critical.
+func isPreemptible(pod v1.Pod) bool {
+	_, critical := pod.Annotations[CriticalPodAnnotationKey]
+	return !critical || pod.Namespace != metaV1.NamespaceSystem
+}
+
 // GetContainerImages returns container image strings from the given pod spec.
 func GetContainerImages(podTemplate *v1.PodSpec) []string {
 	var containerImages []string
@@ -120,8 +173,14 @@ func (self PodCell) GetProperty(name dataselect.PropertyName) dataselect.Compara
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.UIDProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.UID)
 	case dataselect.StatusProperty:
 		return dataselect.StdComparableString(self.Status.Phase)
+	case dataselect.QOSClassProperty:
+		return dataselect.StdComparableString(getPodQOSClass(v1.Pod(self)))
+	case dataselect.PreemptibleProperty:
+		return dataselect.StdComparableString(strconv.FormatBool(isPreemptible(v1.Pod(self))))
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
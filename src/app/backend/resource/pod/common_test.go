@@ -20,6 +20,7 @@ import (
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/pkg/api/v1"
 )
@@ -39,7 +40,9 @@ func TestToPodPodStatusFailed(t *testing.T) {
 	}
 
 	expected := Pod{
-		TypeMeta: api.TypeMeta{Kind: api.ResourceKindPod},
+		TypeMeta:    api.TypeMeta{Kind: api.ResourceKindPod},
+		QOSClass:    v1.PodQOSBestEffort,
+		Preemptible: true,
 		PodStatus: PodStatus{
 			Status:   "failed",
 			PodPhase: v1.PodFailed,
@@ -68,7 +71,9 @@ func TestToPodPodStatusSucceeded(t *testing.T) {
 	}
 
 	expected := Pod{
-		TypeMeta: api.TypeMeta{Kind: api.ResourceKindPod},
+		TypeMeta:    api.TypeMeta{Kind: api.ResourceKindPod},
+		QOSClass:    v1.PodQOSBestEffort,
+		Preemptible: true,
 		PodStatus: PodStatus{
 			Status:   "success",
 			PodPhase: v1.PodSucceeded,
@@ -101,7 +106,9 @@ func TestToPodPodStatusRunning(t *testing.T) {
 	}
 
 	expected := Pod{
-		TypeMeta: api.TypeMeta{Kind: api.ResourceKindPod},
+		TypeMeta:    api.TypeMeta{Kind: api.ResourceKindPod},
+		QOSClass:    v1.PodQOSBestEffort,
+		Preemptible: true,
 		PodStatus: PodStatus{
 			Status:   "success",
 			PodPhase: v1.PodRunning,
@@ -130,7 +137,9 @@ func TestToPodPodStatusPending(t *testing.T) {
 	}
 
 	expected := Pod{
-		TypeMeta: api.TypeMeta{Kind: api.ResourceKindPod},
+		TypeMeta:    api.TypeMeta{Kind: api.ResourceKindPod},
+		QOSClass:    v1.PodQOSBestEffort,
+		Preemptible: true,
 		PodStatus: PodStatus{
 			Status:   "pending",
 			PodPhase: v1.PodPending,
@@ -169,7 +178,9 @@ func TestToPodContainerStates(t *testing.T) {
 	}
 
 	expected := Pod{
-		TypeMeta: api.TypeMeta{Kind: api.ResourceKindPod},
+		TypeMeta:    api.TypeMeta{Kind: api.ResourceKindPod},
+		QOSClass:    v1.PodQOSBestEffort,
+		Preemptible: true,
 		PodStatus: PodStatus{
 			PodPhase: v1.PodRunning,
 			Status:   "pending",
@@ -205,7 +216,9 @@ func TestToPod(t *testing.T) {
 		{
 			pod: &v1.Pod{}, metrics: &common.MetricsByPod{},
 			expected: Pod{
-				TypeMeta: api.TypeMeta{Kind: api.ResourceKindPod},
+				TypeMeta:    api.TypeMeta{Kind: api.ResourceKindPod},
+				QOSClass:    v1.PodQOSBestEffort,
+				Preemptible: true,
 				PodStatus: PodStatus{
 					Status: "pending",
 				},
@@ -217,7 +230,9 @@ func TestToPod(t *testing.T) {
 				}},
 			metrics: &common.MetricsByPod{},
 			expected: Pod{
-				TypeMeta: api.TypeMeta{Kind: api.ResourceKindPod},
+				TypeMeta:    api.TypeMeta{Kind: api.ResourceKindPod},
+				QOSClass:    v1.PodQOSBestEffort,
+				Preemptible: true,
 				ObjectMeta: api.ObjectMeta{
 					Name:      "test-pod",
 					Namespace: "test-namespace",
@@ -238,3 +253,59 @@ func TestToPod(t *testing.T) {
 		}
 	}
 }
+
+func TestGetPodQOSClass(t *testing.T) {
+	resources := func(requests, limits v1.ResourceList) v1.PodSpec {
+		return v1.PodSpec{Containers: []v1.Container{{
+			Resources: v1.ResourceRequirements{Requests: requests, Limits: limits},
+		}}}
+	}
+	cpuAndMemory := func(cpu, memory string) v1.ResourceList {
+		return v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse(cpu),
+			v1.ResourceMemory: resource.MustParse(memory),
+		}
+	}
+
+	cases := []struct {
+		pod      v1.Pod
+		expected v1.PodQOSClass
+	}{
+		{v1.Pod{}, v1.PodQOSBestEffort},
+		{v1.Pod{Status: v1.PodStatus{QOSClass: v1.PodQOSGuaranteed}}, v1.PodQOSGuaranteed},
+		{v1.Pod{Spec: resources(nil, cpuAndMemory("1", "1Gi"))}, v1.PodQOSGuaranteed},
+		{v1.Pod{Spec: resources(cpuAndMemory("1", "1Gi"), cpuAndMemory("1", "1Gi"))},
+			v1.PodQOSGuaranteed},
+		{v1.Pod{Spec: resources(cpuAndMemory("500m", "1Gi"), cpuAndMemory("1", "1Gi"))},
+			v1.PodQOSBurstable},
+		{v1.Pod{Spec: resources(cpuAndMemory("1", "1Gi"), nil)}, v1.PodQOSBurstable},
+	}
+	for _, c := range cases {
+		actual := getPodQOSClass(c.pod)
+		if actual != c.expected {
+			t.Errorf("getPodQOSClass(%#v) == %#v, expected %#v", c.pod, actual, c.expected)
+		}
+	}
+}
+
+func TestIsPreemptible(t *testing.T) {
+	critical := map[string]string{CriticalPodAnnotationKey: ""}
+	cases := []struct {
+		namespace   string
+		annotations map[string]string
+		expected    bool
+	}{
+		{"default", nil, true},
+		{"default", critical, true},
+		{metaV1.NamespaceSystem, nil, true},
+		{metaV1.NamespaceSystem, critical, false},
+	}
+	for _, c := range cases {
+		pod := v1.Pod{ObjectMeta: metaV1.ObjectMeta{Namespace: c.namespace, Annotations: c.annotations}}
+		actual := isPreemptible(pod)
+		if actual != c.expected {
+			t.Errorf("isPreemptible(%#v, %#v) == %#v, expected %#v", c.namespace, c.annotations,
+				actual, c.expected)
+		}
+	}
+}
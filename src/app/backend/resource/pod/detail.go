@@ -23,9 +23,11 @@ import (
 	"strconv"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	"github.com/kubernetes/dashboard/src/app/backend/link"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/owner"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -44,6 +46,9 @@ type PodDetail struct {
 	ObjectMeta api.ObjectMeta `json:"objectMeta"`
 	TypeMeta   api.TypeMeta   `json:"typeMeta"`
 
+	// Links to external tools configured in settings and in the links annotation.
+	Links []link.Link `json:"links,omitempty"`
+
 	// Status of the Pod. See Kubernetes API for reference.
 	PodPhase v1.PodPhase `json:"podPhase"`
 
@@ -65,12 +70,18 @@ type PodDetail struct {
 	// List of initContainer of this pod.
 	InitContainers []Container `json:"initContainers"`
 
+	// Volumes of this pod with objects backing them.
+	Volumes []Volume `json:"volumes"`
+
 	// Metrics collected for this resource
 	Metrics []metric.Metric `json:"metrics"`
 
 	// Conditions of this pod.
 	Conditions []common.Condition `json:"conditions"`
 
+	// Readiness explains why the pod is or is not ready.
+	Readiness PodReadiness `json:"readiness"`
+
 	// Events is list of events associated with a pod.
 	EventList common.EventList `json:"eventList"`
 }
@@ -109,7 +120,7 @@ type EnvVar struct {
 
 // GetPodDetail returns the details (PodDetail) of a named Pod from a particular namespace.
 // TODO(maciaszczykm): Owner reference should be used instead of created by annotation.
-func GetPodDetail(client kubernetes.Interface, heapsterClient heapster.HeapsterClient, namespace,
+func GetPodDetail(client kubernetes.Interface, heapsterClient metricapi.MetricClient, namespace,
 	name string) (*PodDetail, error) {
 
 	log.Printf("Getting details of %s pod in %s namespace", name, namespace)
@@ -119,6 +130,8 @@ func GetPodDetail(client kubernetes.Interface, heapsterClient heapster.HeapsterC
 			common.NewSameNamespaceQuery(namespace), 1),
 		SecretList: common.GetSecretListChannel(client,
 			common.NewSameNamespaceQuery(namespace), 1),
+		PersistentVolumeClaimList: common.GetPersistentVolumeClaimListChannel(client,
+			common.NewSameNamespaceQuery(namespace), 1),
 		PodMetrics: common.GetPodMetricsChannel(heapsterClient, name, namespace),
 	}
 
@@ -153,13 +166,20 @@ func GetPodDetail(client kubernetes.Interface, heapsterClient heapster.HeapsterC
 	}
 	secretList := <-channels.SecretList.List
 
-	eventList, err := GetEventsForPod(client, dataselect.DefaultDataSelect, pod.Namespace,
-		pod.Name)
+	if err = <-channels.PersistentVolumeClaimList.Error; err != nil {
+		return nil, err
+	}
+	claimList := <-channels.PersistentVolumeClaimList.List
+
+	podEvents, err := getPodEvents(client, pod.Namespace, pod.Name)
 	if err != nil {
 		return nil, err
 	}
+	eventList := event.CreateEventList(podEvents, dataselect.DefaultDataSelect)
 
-	podDetail := toPodDetail(pod, metrics, configMapList, secretList, controller, eventList)
+	podDetail := toPodDetail(pod, metrics, configMapList, secretList, controller, &eventList)
+	podDetail.Readiness = getPodReadiness(pod, podEvents)
+	podDetail.Volumes = getPodVolumes(pod, configMapList, secretList, claimList)
 	return &podDetail, nil
 }
 
@@ -274,15 +294,7 @@ func evalValueFrom(src *v1.EnvVarSource, container *v1.Container, pod *v1.Pod,
 			}
 		}
 	case src.ResourceFieldRef != nil:
-		valueFrom, err := extractContainerResourceValue(src.ResourceFieldRef, container)
-		if err != nil {
-			valueFrom = ""
-		}
-		resource := src.ResourceFieldRef.Resource
-		if valueFrom == "0" && (resource == "limits.cpu" || resource == "limits.memory") {
-			valueFrom = "node allocatable"
-		}
-		return valueFrom
+		return evalResourceFieldRef(src.ResourceFieldRef, container)
 	case src.FieldRef != nil:
 		internalFieldPath, _, err := kubeapi.Scheme.ConvertFieldLabel(src.FieldRef.APIVersion,
 			"Pod", src.FieldRef.FieldPath, "")
@@ -300,6 +312,19 @@ func evalValueFrom(src *v1.EnvVarSource, container *v1.Container, pod *v1.Pod,
 	return ""
 }
 
+// evalResourceFieldRef evaluates value of given resource of given container. Limits that are not
+// set default to node allocatable resources.
+func evalResourceFieldRef(fs *v1.ResourceFieldSelector, container *v1.Container) string {
+	valueFrom, err := extractContainerResourceValue(fs, container)
+	if err != nil {
+		valueFrom = ""
+	}
+	if valueFrom == "0" && (fs.Resource == "limits.cpu" || fs.Resource == "limits.memory") {
+		valueFrom = "node allocatable"
+	}
+	return valueFrom
+}
+
 // extractContainerResourceValue extracts the value of a resource in an already known container.
 // TODO(maciaszczykm): Replace this method with call to fieldpath.ExtractContainerResourceValue().
 // To do it update to new client-go and convert arguments to Kubernetes API.
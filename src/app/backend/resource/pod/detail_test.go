@@ -24,7 +24,7 @@ import (
 	"errors"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/owner"
@@ -42,7 +42,7 @@ func (f clientFunc) Do(req *http.Request) (*http.Response, error) {
 	return f(req)
 }
 
-func (c FakeHeapsterClient) Get(path string) heapster.RequestInterface {
+func (c FakeHeapsterClient) Get(path string) metricapi.RequestInterface {
 	return restclient.NewRequest(clientFunc(func(req *http.Request) (*http.Response, error) {
 		return nil, errors.New("fake error")
 	}), "GET", nil, "/api/v1", restclient.ContentConfig{}, restclient.Serializers{}, nil, nil)
@@ -69,7 +69,12 @@ func TestGetPodDetail(t *testing.T) {
 				Controller:     owner.ResourceOwner{},
 				Containers:     []Container{},
 				InitContainers: []Container{},
-				EventList:      common.EventList{Events: []common.Event{}},
+				Volumes:        []Volume{},
+				Readiness: PodReadiness{
+					ReadinessGates:    []ReadinessGate{},
+					UnreadyContainers: []UnreadyContainer{},
+				},
+				EventList: common.EventList{ListMeta: api.ListMeta{Page: 1, ItemsPerPage: 10}, Events: []common.Event{}},
 			},
 		},
 	}
@@ -7,21 +7,18 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
 	client "k8s.io/client-go/kubernetes"
+	api "k8s.io/client-go/pkg/api/v1"
 )
 
 // GetEventsForPod gets events that are associated with this pod.
 func GetEventsForPod(client client.Interface, dsQuery *dataselect.DataSelectQuery, namespace,
 	podName string) (*common.EventList, error) {
 
-	podEvents, err := event.GetPodEvents(client, namespace, podName)
+	podEvents, err := getPodEvents(client, namespace, podName)
 	if err != nil {
 		return nil, err
 	}
 
-	if !event.IsTypeFilled(podEvents) {
-		podEvents = event.FillEventsType(podEvents)
-	}
-
 	events := event.CreateEventList(podEvents, dsQuery)
 
 	log.Printf("Found %d events related to %s pod in %s namespace", len(events.Events), podName,
@@ -29,3 +26,16 @@ func GetEventsForPod(client client.Interface, dsQuery *dataselect.DataSelectQuer
 
 	return &events, nil
 }
+
+// getPodEvents returns all events of given pod with their type filled.
+func getPodEvents(client client.Interface, namespace, podName string) ([]api.Event, error) {
+	podEvents, err := event.GetPodEvents(client, namespace, podName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !event.IsTypeFilled(podEvents) {
+		podEvents = event.FillEventsType(podEvents)
+	}
+	return podEvents, nil
+}
@@ -38,7 +38,7 @@ func TestGetPodEvents(t *testing.T) {
 				}},
 			}},
 			&common.EventList{
-				ListMeta: api.ListMeta{TotalItems: 1},
+				ListMeta: api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
 				Events: []common.Event{{
 					TypeMeta: api.TypeMeta{Kind: api.ResourceKindEvent},
 					ObjectMeta: api.ObjectMeta{Name: "ev-1", Namespace: "ns-1",
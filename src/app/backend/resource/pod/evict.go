@@ -0,0 +1,119 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"log"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	policy "k8s.io/client-go/pkg/apis/policy/v1beta1"
+)
+
+// EvictionResult is a result of pod eviction.
+type EvictionResult struct {
+	// True if the eviction was accepted and the pod is being deleted.
+	Evicted bool `json:"evicted"`
+
+	// Reason the eviction was refused, e.g. the message of the API server.
+	Message string `json:"message,omitempty"`
+
+	// Pod disruption budgets of the pod that allow no disruptions, which refused the eviction.
+	BlockingBudgets []BlockingBudget `json:"blockingBudgets"`
+}
+
+// BlockingBudget is a pod disruption budget that refused pod eviction.
+type BlockingBudget struct {
+	Name string `json:"name"`
+
+	MinAvailable intstr.IntOrString `json:"minAvailable"`
+
+	// Healthy pods selected by the budget and number of healthy pods it requires.
+	CurrentHealthy int32 `json:"currentHealthy"`
+	DesiredHealthy int32 `json:"desiredHealthy"`
+
+	ExpectedPods int32 `json:"expectedPods"`
+}
+
+// EvictPod evicts given pod using the eviction subresource, which respects pod disruption budgets.
+// Eviction refused by budgets is not an error, budgets that refused it are returned in the result.
+func EvictPod(client client.Interface, namespace, name string) (*EvictionResult, error) {
+	log.Printf("Evicting %s pod in %s namespace", name, namespace)
+
+	pod, err := client.CoreV1().Pods(namespace).Get(name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = client.CoreV1().Pods(namespace).Evict(&policy.Eviction{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: namespace},
+	})
+	if err == nil {
+		return &EvictionResult{Evicted: true, BlockingBudgets: make([]BlockingBudget, 0)}, nil
+	}
+	// Too many requests is returned when evicting the pod would violate a disruption budget.
+	if !k8serrors.IsTooManyRequests(err) {
+		return nil, err
+	}
+
+	result := &EvictionResult{Message: err.Error(), BlockingBudgets: make([]BlockingBudget, 0)}
+	pdbs, err := client.PolicyV1beta1().PodDisruptionBudgets(namespace).List(metaV1.ListOptions{})
+	if k8serrors.IsForbidden(err) {
+		// User may evict pods without being allowed to see the budgets.
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.BlockingBudgets = getBlockingBudgets(pod, pdbs.Items)
+	return result, nil
+}
+
+// getBlockingBudgets returns budgets selecting given pod that allow no disruptions.
+func getBlockingBudgets(pod *v1.Pod, pdbs []policy.PodDisruptionBudget) []BlockingBudget {
+	result := make([]BlockingBudget, 0)
+	for _, pdb := range pdbs {
+		if pdb.Namespace != pod.Namespace || pdb.Spec.Selector == nil ||
+			pdb.Status.PodDisruptionsAllowed > 0 {
+			continue
+		}
+		selector, err := metaV1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		result = append(result, BlockingBudget{
+			Name:           pdb.Name,
+			MinAvailable:   pdb.Spec.MinAvailable,
+			CurrentHealthy: pdb.Status.CurrentHealthy,
+			DesiredHealthy: pdb.Status.DesiredHealthy,
+			ExpectedPods:   pdb.Status.ExpectedPods,
+		})
+	}
+	return result
+}
+
+// ForceDeletePod deletes given pod immediately with grace period 0, without waiting for its
+// containers to terminate. Use it for pods stuck in terminating state, e.g. on unreachable nodes.
+func ForceDeletePod(client client.Interface, namespace, name string) error {
+	log.Printf("Force deleting %s pod in %s namespace", name, namespace)
+
+	gracePeriod := int64(0)
+	return client.CoreV1().Pods(namespace).Delete(name,
+		&metaV1.DeleteOptions{GracePeriodSeconds: &gracePeriod})
+}
@@ -0,0 +1,99 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/pkg/api/v1"
+	policy "k8s.io/client-go/pkg/apis/policy/v1beta1"
+	core "k8s.io/client-go/testing"
+)
+
+func newDisruptionBudget(name string, selector map[string]string,
+	allowed int32) *policy.PodDisruptionBudget {
+	return &policy.PodDisruptionBudget{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: policy.PodDisruptionBudgetSpec{
+			MinAvailable: intstr.FromInt(2),
+			Selector:     &metaV1.LabelSelector{MatchLabels: selector},
+		},
+		Status: policy.PodDisruptionBudgetStatus{PodDisruptionsAllowed: allowed, CurrentHealthy: 2,
+			DesiredHealthy: 2, ExpectedPods: 2},
+	}
+}
+
+func TestEvictPod(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: "web-1", Namespace: "default",
+		Labels: map[string]string{"app": "web"}}}
+	pdbList := &policy.PodDisruptionBudgetList{Items: []policy.PodDisruptionBudget{
+		*newDisruptionBudget("web", map[string]string{"app": "web"}, 0),
+		*newDisruptionBudget("api", map[string]string{"app": "api"}, 0),
+		*newDisruptionBudget("all", map[string]string{}, 1),
+	}}
+
+	cases := []struct {
+		evictErr error
+		expected *EvictionResult
+	}{
+		{nil, &EvictionResult{Evicted: true, BlockingBudgets: []BlockingBudget{}}},
+		{
+			&k8serrors.StatusError{ErrStatus: metaV1.Status{Code: http.StatusTooManyRequests,
+				Message: "Cannot evict pod"}},
+			&EvictionResult{
+				Message: "Cannot evict pod",
+				BlockingBudgets: []BlockingBudget{{Name: "web", MinAvailable: intstr.FromInt(2),
+					CurrentHealthy: 2, DesiredHealthy: 2, ExpectedPods: 2}},
+			},
+		},
+	}
+	for _, c := range cases {
+		fakeClient := fake.NewSimpleClientset(pod, pdbList)
+		evictErr := c.evictErr
+		fakeClient.PrependReactor("create", "pods",
+			func(action core.Action) (bool, runtime.Object, error) {
+				return action.GetSubresource() == "eviction", nil, evictErr
+			})
+
+		actual, err := EvictPod(fakeClient, "default", "web-1")
+		if err != nil {
+			t.Errorf("EvictPod() == \ngot err %#v", err)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("EvictPod() == \ngot %#v, \nexpected %#v", actual, c.expected)
+		}
+	}
+}
+
+func TestForceDeletePod(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(&v1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: "web-1", Namespace: "default"}})
+
+	if err := ForceDeletePod(fakeClient, "default", "web-1"); err != nil {
+		t.Fatalf("ForceDeletePod() == \ngot err %#v", err)
+	}
+
+	actions := fakeClient.Actions()
+	if len(actions) != 1 || actions[0].GetVerb() != "delete" {
+		t.Errorf("ForceDeletePod() == \ngot actions %#v, \nexpected single delete", actions)
+	}
+}
@@ -18,12 +18,12 @@ import (
 	"log"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	"github.com/kubernetes/dashboard/src/app/backend/integration/alertmanager"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
-	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sClient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/pkg/api/v1"
 )
@@ -32,6 +32,9 @@ import (
 type PodList struct {
 	ListMeta api.ListMeta `json:"listMeta"`
 
+	// Status summary of the returned items.
+	Status common.ResourceStatus `json:"status"`
+
 	// Unordered list of Pods.
 	Pods              []Pod           `json:"pods"`
 	CumulativeMetrics []metric.Metric `json:"cumulativeMetrics"`
@@ -57,21 +60,30 @@ type Pod struct {
 	// Count of containers restarts.
 	RestartCount int32 `json:"restartCount"`
 
+	// Quality of service class of the pod. BestEffort pods are evicted first on node pressure.
+	QOSClass v1.PodQOSClass `json:"qosClass"`
+
+	// Whether the pod can be preempted to make room for critical pods.
+	Preemptible bool `json:"preemptible"`
+
 	// Pod metrics.
 	Metrics *common.PodMetrics `json:"metrics"`
 
 	// Pod warning events
 	Warnings []common.Event `json:"warnings"`
+
+	// Active alerts of the pod, nil if there are none.
+	Alerts *alertmanager.AlertBadge `json:"alerts,omitempty"`
 }
 
 // GetPodList returns a list of all Pods in the cluster.
-func GetPodList(client k8sClient.Interface, heapsterClient heapster.HeapsterClient,
+func GetPodList(client k8sClient.Interface, heapsterClient metricapi.MetricClient,
 	nsQuery *common.NamespaceQuery, dsQuery *dataselect.DataSelectQuery) (*PodList, error) {
 	log.Print("Getting list of all pods in the cluster")
 
 	channels := &common.ResourceChannels{
-		PodList:   common.GetPodListChannelWithOptions(client, nsQuery, metaV1.ListOptions{}, 1),
-		EventList: common.GetEventListChannel(client, nsQuery, 1),
+		PodList:   common.GetFanOutPodListChannelWithOptions(client, nsQuery, dsQuery.ListOptions(), 1),
+		EventList: common.GetWarningEventListChannel(client, nsQuery, 1),
 	}
 
 	return GetPodListFromChannels(channels, dsQuery, heapsterClient)
@@ -80,7 +92,7 @@ func GetPodList(client k8sClient.Interface, heapsterClient heapster.HeapsterClie
 // GetPodListFromChannels returns a list of all Pods in the cluster
 // reading required resource list once from the channels.
 func GetPodListFromChannels(channels *common.ResourceChannels, dsQuery *dataselect.DataSelectQuery,
-	heapsterClient heapster.HeapsterClient) (*PodList, error) {
+	heapsterClient metricapi.MetricClient) (*PodList, error) {
 
 	pods := <-channels.PodList.List
 	if err := <-channels.PodList.Error; err != nil {
@@ -93,11 +105,12 @@ func GetPodListFromChannels(channels *common.ResourceChannels, dsQuery *datasele
 	}
 
 	podList := CreatePodList(pods.Items, eventList.Items, dsQuery, heapsterClient)
+	podList.ListMeta.Warnings = common.GetListBudgetWarnings(pods, eventList)
 	return &podList, nil
 }
 
 func CreatePodList(pods []v1.Pod, events []v1.Event, dsQuery *dataselect.DataSelectQuery,
-	heapsterClient heapster.HeapsterClient) PodList {
+	heapsterClient metricapi.MetricClient) PodList {
 
 	channels := &common.ResourceChannels{
 		PodMetrics: common.GetPodListMetricsChannel(heapsterClient, pods, 1),
@@ -109,7 +122,12 @@ func CreatePodList(pods []v1.Pod, events []v1.Event, dsQuery *dataselect.DataSel
 	metrics := <-channels.PodMetrics.MetricsByPod
 
 	podList := PodList{
-		Pods: make([]Pod, 0),
+		Pods:     make([]Pod, 0),
+		ListMeta: api.ListMeta{TotalItems: len(pods)},
+	}
+
+	for _, pod := range fromCells(dataselect.GenericDataFilter(toCells(pods), dsQuery)) {
+		podList.Status.AddPod(&pod, event.GetPodsEventWarnings(events, []v1.Pod{pod}))
 	}
 
 	cache := &dataselect.CachedResources{Pods: pods}
@@ -117,7 +135,7 @@ func CreatePodList(pods []v1.Pod, events []v1.Event, dsQuery *dataselect.DataSel
 	podCells, cumulativeMetricsPromises, filteredTotal := dataselect.GenericDataSelectWithFilterAndMetrics(toCells(pods), dsQuery,
 		cache, &heapsterClient)
 	pods = fromCells(podCells)
-	podList.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+	podList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, podList.ListMeta.TotalItems)
 
 	for _, pod := range pods {
 		warnings := event.GetPodsEventWarnings(events, []v1.Pod{pod})
@@ -0,0 +1,153 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"regexp"
+	"strings"
+
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+// Reason of events the kubelet records when a probe of a container fails.
+const unhealthyEventReason = "Unhealthy"
+
+// builtInConditionTypes are types of pod conditions set by Kubernetes itself. Other conditions are
+// set by controllers for readiness gates of the pod.
+var builtInConditionTypes = map[v1.PodConditionType]bool{
+	v1.PodScheduled:             true,
+	v1.PodReady:                 true,
+	v1.PodInitialized:           true,
+	"ContainersReady":           true,
+	"PodReadyToStartContainers": true,
+	"PodHasNetwork":             true,
+	"DisruptionTarget":          true,
+}
+
+// readinessGatePattern matches readiness gates named in the message of the Ready condition, e.g.
+// corresponding condition of pod readiness gate "example.com/feature" does not exist.
+var readinessGatePattern = regexp.MustCompile(`readiness gate "([^"]+)"`)
+
+// PodReadiness explains why a pod is or is not ready.
+type PodReadiness struct {
+	// Whether the pod is ready.
+	Ready bool `json:"ready"`
+
+	// Reason and message of the Ready condition of the pod.
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+
+	// Readiness gates of the pod with the status of their conditions.
+	ReadinessGates []ReadinessGate `json:"readinessGates"`
+
+	// Containers that are not ready with explanation why.
+	UnreadyContainers []UnreadyContainer `json:"unreadyContainers"`
+}
+
+// ReadinessGate is a condition that must be true for a pod to be ready, in addition to readiness
+// of its containers.
+type ReadinessGate struct {
+	// ConditionType is the type of the pod condition of the gate.
+	ConditionType string `json:"conditionType"`
+
+	// Status of the condition. Empty if the condition is not set yet.
+	Status v1.ConditionStatus `json:"status"`
+}
+
+// UnreadyContainer is a container that is not ready.
+type UnreadyContainer struct {
+	Name string `json:"name"`
+
+	// Reason of the container not being ready, e.g. CrashLoopBackOff or Unhealthy for containers
+	// that fail their readiness probe.
+	Reason string `json:"reason"`
+
+	// Message explaining the reason, e.g. the output of the failed readiness probe.
+	Message string `json:"message"`
+}
+
+// getPodReadiness explains readiness of given pod using its conditions, container statuses and
+// given events of the pod. The vendored API predates readiness gates, so they are recognized by
+// their conditions and by the message of the Ready condition.
+func getPodReadiness(pod *v1.Pod, events []v1.Event) PodReadiness {
+	readiness := PodReadiness{
+		ReadinessGates:    make([]ReadinessGate, 0),
+		UnreadyContainers: make([]UnreadyContainer, 0),
+	}
+
+	gates := make(map[string]bool)
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			readiness.Ready = condition.Status == v1.ConditionTrue
+			readiness.Reason = condition.Reason
+			readiness.Message = condition.Message
+		} else if !builtInConditionTypes[condition.Type] {
+			gates[string(condition.Type)] = true
+			readiness.ReadinessGates = append(readiness.ReadinessGates, ReadinessGate{
+				ConditionType: string(condition.Type),
+				Status:        condition.Status,
+			})
+		}
+	}
+	for _, match := range readinessGatePattern.FindAllStringSubmatch(readiness.Message, -1) {
+		if !gates[match[1]] {
+			gates[match[1]] = true
+			readiness.ReadinessGates = append(readiness.ReadinessGates,
+				ReadinessGate{ConditionType: match[1]})
+		}
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Ready {
+			continue
+		}
+		container := UnreadyContainer{Name: status.Name}
+		switch {
+		case status.State.Waiting != nil:
+			container.Reason = status.State.Waiting.Reason
+			container.Message = status.State.Waiting.Message
+		case status.State.Terminated != nil:
+			container.Reason = status.State.Terminated.Reason
+			container.Message = status.State.Terminated.Message
+		default:
+			container.Reason = unhealthyEventReason
+			container.Message = getReadinessProbeFailure(events, status.Name)
+			if container.Message == "" {
+				container.Reason = "Running"
+				container.Message = "Readiness probe has not succeeded yet"
+			}
+		}
+		readiness.UnreadyContainers = append(readiness.UnreadyContainers, container)
+	}
+	return readiness
+}
+
+// getReadinessProbeFailure returns message of the latest readiness probe failure of given
+// container, found in given events of its pod.
+func getReadinessProbeFailure(events []v1.Event, container string) string {
+	fieldPath := "spec.containers{" + container + "}"
+	var latest *v1.Event
+	for i, event := range events {
+		if event.Reason == unhealthyEventReason && event.InvolvedObject.FieldPath == fieldPath &&
+			strings.HasPrefix(event.Message, "Readiness probe") &&
+			(latest == nil || latest.LastTimestamp.Before(event.LastTimestamp)) {
+			latest = &events[i]
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	return latest.Message
+}
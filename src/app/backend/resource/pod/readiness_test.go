@@ -0,0 +1,120 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+func TestGetPodReadiness(t *testing.T) {
+	probeFailure := func(container, message string, minute int) v1.Event {
+		return v1.Event{
+			Reason:  "Unhealthy",
+			Message: message,
+			InvolvedObject: v1.ObjectReference{
+				FieldPath: "spec.containers{" + container + "}",
+			},
+			LastTimestamp: metaV1.NewTime(time.Date(2017, 5, 5, 10, minute, 0, 0, time.UTC)),
+		}
+	}
+
+	cases := []struct {
+		pod      *v1.Pod
+		events   []v1.Event
+		expected PodReadiness
+	}{
+		{
+			&v1.Pod{Status: v1.PodStatus{
+				Conditions: []v1.PodCondition{
+					{Type: v1.PodScheduled, Status: v1.ConditionTrue},
+					{Type: v1.PodReady, Status: v1.ConditionTrue},
+				},
+				ContainerStatuses: []v1.ContainerStatus{{Name: "app", Ready: true}},
+			}},
+			nil,
+			PodReadiness{
+				Ready:             true,
+				ReadinessGates:    []ReadinessGate{},
+				UnreadyContainers: []UnreadyContainer{},
+			},
+		},
+		{
+			&v1.Pod{Status: v1.PodStatus{
+				Conditions: []v1.PodCondition{
+					{Type: v1.PodReady, Status: v1.ConditionFalse, Reason: "ContainersNotReady",
+						Message: "containers with unready status: [app sidecar cache]"},
+				},
+				ContainerStatuses: []v1.ContainerStatus{
+					{Name: "app", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+					{Name: "sidecar", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{
+						Reason: "CrashLoopBackOff", Message: "Back-off restarting failed container"}}},
+					{Name: "cache", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+				},
+			}},
+			[]v1.Event{
+				probeFailure("app", "Readiness probe failed: HTTP probe failed with statuscode: 500", 1),
+				probeFailure("app", "Readiness probe failed: HTTP probe failed with statuscode: 503", 2),
+				probeFailure("app", "Liveness probe failed: connection refused", 3),
+			},
+			PodReadiness{
+				Reason:         "ContainersNotReady",
+				Message:        "containers with unready status: [app sidecar cache]",
+				ReadinessGates: []ReadinessGate{},
+				UnreadyContainers: []UnreadyContainer{
+					{Name: "app", Reason: "Unhealthy",
+						Message: "Readiness probe failed: HTTP probe failed with statuscode: 503"},
+					{Name: "sidecar", Reason: "CrashLoopBackOff",
+						Message: "Back-off restarting failed container"},
+					{Name: "cache", Reason: "Running", Message: "Readiness probe has not succeeded yet"},
+				},
+			},
+		},
+		{
+			&v1.Pod{Status: v1.PodStatus{
+				Conditions: []v1.PodCondition{
+					{Type: v1.PodReady, Status: v1.ConditionFalse, Reason: "ReadinessGatesNotReady",
+						Message: `corresponding condition of pod readiness gate "example.com/lb" ` +
+							`does not exist.`},
+					{Type: "example.com/dns", Status: v1.ConditionTrue},
+				},
+				ContainerStatuses: []v1.ContainerStatus{{Name: "app", Ready: true}},
+			}},
+			nil,
+			PodReadiness{
+				Reason: "ReadinessGatesNotReady",
+				Message: `corresponding condition of pod readiness gate "example.com/lb" ` +
+					`does not exist.`,
+				ReadinessGates: []ReadinessGate{
+					{ConditionType: "example.com/dns", Status: v1.ConditionTrue},
+					{ConditionType: "example.com/lb"},
+				},
+				UnreadyContainers: []UnreadyContainer{},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := getPodReadiness(c.pod, c.events)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("getPodReadiness(%#v, %#v) == \ngot: %#v, \nexpected %#v", c.pod, c.events,
+				actual, c.expected)
+		}
+	}
+}
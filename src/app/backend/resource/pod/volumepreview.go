@@ -0,0 +1,346 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/kubernetes/pkg/fieldpath"
+)
+
+// VolumePreview is the content a downward API, projected, config map or secret volume presents
+// inside containers of a pod.
+type VolumePreview struct {
+	Name string `json:"name"`
+
+	// Type of the volume source, e.g. Projected or DownwardAPI.
+	Type string `json:"type"`
+
+	// Files of the volume, in the order they are defined.
+	Files []VolumeFile `json:"files"`
+
+	// Problems preventing the volume from being mounted, e.g. missing config maps or secrets.
+	Errors []string `json:"errors"`
+}
+
+// VolumeFile is a single file of a volume.
+type VolumeFile struct {
+	// Path of the file relative to the mount point of the volume.
+	Path string `json:"path"`
+
+	// Source of the file content, e.g. fieldRef:metadata.labels or configMap:app/app.conf.
+	Source string `json:"source"`
+
+	// Content of the file. Not set for secret files, which are described by size and, for service
+	// account tokens, by their claims.
+	Content string `json:"content,omitempty"`
+
+	// Size of the content in bytes.
+	Size int `json:"size"`
+
+	// Claims of the token, set for token files of service account token secrets.
+	Token *TokenClaims `json:"token,omitempty"`
+
+	// Why the content cannot be computed, e.g. because of missing key.
+	Error string `json:"error,omitempty"`
+}
+
+// TokenClaims are claims of a service account token that identify the workload.
+type TokenClaims struct {
+	Issuer    string   `json:"issuer"`
+	Subject   string   `json:"subject"`
+	Audiences []string `json:"audiences"`
+
+	// Expiration time of the token, nil for tokens that never expire.
+	ExpiresAt *metaV1.Time `json:"expiresAt,omitempty"`
+
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+// GetPodVolumePreview returns content given volume of given pod presents inside its containers.
+func GetPodVolumePreview(client kubernetes.Interface, namespace, podName, volumeName string) (
+	*VolumePreview, error) {
+	log.Printf("Getting preview of %s volume of %s pod in %s namespace", volumeName, podName,
+		namespace)
+
+	pod, err := client.CoreV1().Pods(namespace).Get(podName, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var volume *v1.Volume
+	for i := range pod.Spec.Volumes {
+		if pod.Spec.Volumes[i].Name == volumeName {
+			volume = &pod.Spec.Volumes[i]
+		}
+	}
+	if volume == nil {
+		return nil, k8serrors.NewNotFound(schema.GroupResource{Resource: "volumes"}, volumeName)
+	}
+
+	preview := &volumePreviewBuilder{
+		client: client,
+		pod:    pod,
+		preview: VolumePreview{
+			Name:   volume.Name,
+			Type:   getVolumeType(volume.VolumeSource),
+			Files:  make([]VolumeFile, 0),
+			Errors: make([]string, 0),
+		},
+	}
+
+	source := volume.VolumeSource
+	switch {
+	case source.DownwardAPI != nil:
+		preview.addDownwardAPIFiles(source.DownwardAPI.Items)
+	case source.ConfigMap != nil:
+		err = preview.addConfigMapFiles(source.ConfigMap.Name, source.ConfigMap.Items,
+			source.ConfigMap.Optional)
+	case source.Secret != nil:
+		err = preview.addSecretFiles(source.Secret.SecretName, source.Secret.Items,
+			source.Secret.Optional)
+	case source.Projected != nil:
+		for _, projection := range source.Projected.Sources {
+			switch {
+			case projection.DownwardAPI != nil:
+				preview.addDownwardAPIFiles(projection.DownwardAPI.Items)
+			case projection.ConfigMap != nil:
+				err = preview.addConfigMapFiles(projection.ConfigMap.Name, projection.ConfigMap.Items,
+					projection.ConfigMap.Optional)
+			case projection.Secret != nil:
+				err = preview.addSecretFiles(projection.Secret.Name, projection.Secret.Items,
+					projection.Secret.Optional)
+			}
+			if err != nil {
+				break
+			}
+		}
+	default:
+		return nil, k8serrors.NewBadRequest(fmt.Sprintf("Preview is not supported for %s volumes",
+			preview.preview.Type))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &preview.preview, nil
+}
+
+// volumePreviewBuilder collects files of volume sources of a pod.
+type volumePreviewBuilder struct {
+	client  kubernetes.Interface
+	pod     *v1.Pod
+	preview VolumePreview
+}
+
+func (self *volumePreviewBuilder) addDownwardAPIFiles(items []v1.DownwardAPIVolumeFile) {
+	for _, item := range items {
+		file := VolumeFile{Path: item.Path}
+		switch {
+		case item.FieldRef != nil:
+			file.Source = "fieldRef:" + item.FieldRef.FieldPath
+			content, err := extractVolumeFieldPath(self.pod, item.FieldRef.FieldPath)
+			if err != nil {
+				file.Error = err.Error()
+			}
+			file.Content = content
+		case item.ResourceFieldRef != nil:
+			file.Source = "resourceFieldRef:" + item.ResourceFieldRef.ContainerName + "/" +
+				item.ResourceFieldRef.Resource
+			container := self.getContainer(item.ResourceFieldRef.ContainerName)
+			if container == nil {
+				file.Error = fmt.Sprintf("Container %q does not exist",
+					item.ResourceFieldRef.ContainerName)
+			} else {
+				file.Content = evalResourceFieldRef(item.ResourceFieldRef, container)
+			}
+		}
+		file.Size = len(file.Content)
+		self.preview.Files = append(self.preview.Files, file)
+	}
+}
+
+func (self *volumePreviewBuilder) addConfigMapFiles(name string, items []v1.KeyToPath,
+	optional *bool) error {
+	configMap, err := self.client.CoreV1().ConfigMaps(self.pod.Namespace).Get(name,
+		metaV1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		self.addMissingObject("Config map", name, optional)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, item := range getKeysToPaths(configMap.Data, items) {
+		file := VolumeFile{Path: item.Path, Source: "configMap:" + name + "/" + item.Key}
+		if content, ok := configMap.Data[item.Key]; ok {
+			file.Content = content
+			file.Size = len(content)
+		} else {
+			file.Error = fmt.Sprintf("Config map %s has no key %s", name, item.Key)
+		}
+		self.preview.Files = append(self.preview.Files, file)
+	}
+	return nil
+}
+
+func (self *volumePreviewBuilder) addSecretFiles(name string, items []v1.KeyToPath,
+	optional *bool) error {
+	secret, err := self.client.CoreV1().Secrets(self.pod.Namespace).Get(name, metaV1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		self.addMissingObject("Secret", name, optional)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]string)
+	for key := range secret.Data {
+		keys[key] = ""
+	}
+	for _, item := range getKeysToPaths(keys, items) {
+		file := VolumeFile{Path: item.Path, Source: "secret:" + name + "/" + item.Key}
+		if data, ok := secret.Data[item.Key]; ok {
+			file.Size = len(data)
+			if secret.Type == v1.SecretTypeServiceAccountToken && item.Key == v1.ServiceAccountTokenKey {
+				file.Token, err = parseTokenClaims(string(data))
+				if err != nil {
+					file.Error = err.Error()
+				}
+			}
+		} else {
+			file.Error = fmt.Sprintf("Secret %s has no key %s", name, item.Key)
+		}
+		self.preview.Files = append(self.preview.Files, file)
+	}
+	return nil
+}
+
+// extractVolumeFieldPath returns content of downward API file with given field of given pod. Maps
+// are formatted the same way as by fieldpath.FormatMap, but sorted, so that the content is stable.
+func extractVolumeFieldPath(pod *v1.Pod, fieldPath string) (string, error) {
+	var values map[string]string
+	switch fieldPath {
+	case "metadata.labels":
+		values = pod.Labels
+	case "metadata.annotations":
+		values = pod.Annotations
+	default:
+		return fieldpath.ExtractFieldPathAsString(pod, fieldPath)
+	}
+
+	lines := make([]string, 0, len(values))
+	for key, value := range values {
+		lines = append(lines, fmt.Sprintf("%s=%q", key, value))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n"), nil
+}
+
+// addMissingObject reports missing object referenced by the volume, unless it is optional.
+func (self *volumePreviewBuilder) addMissingObject(kind, name string, optional *bool) {
+	if optional != nil && *optional {
+		return
+	}
+	self.preview.Errors = append(self.preview.Errors, fmt.Sprintf("%s %s does not exist", kind, name))
+}
+
+func (self *volumePreviewBuilder) getContainer(name string) *v1.Container {
+	containers := self.pod.Spec.Containers
+	for i := range containers {
+		if containers[i].Name == name || (name == "" && len(containers) == 1) {
+			return &containers[i]
+		}
+	}
+	return nil
+}
+
+// getKeysToPaths returns given items, or if there are none, all keys of given data projected to
+// files of the same names, sorted by key.
+func getKeysToPaths(data map[string]string, items []v1.KeyToPath) []v1.KeyToPath {
+	if len(items) > 0 {
+		return items
+	}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]v1.KeyToPath, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, v1.KeyToPath{Key: key, Path: key})
+	}
+	return result
+}
+
+// parseTokenClaims returns claims of given JWT token. The signature is not verified, claims are
+// only decoded for display.
+func parseTokenClaims(token string) (*TokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("Token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("Cannot decode token claims: %s", err)
+	}
+
+	raw := struct {
+		Issuer             string          `json:"iss"`
+		Subject            string          `json:"sub"`
+		Audience           json.RawMessage `json:"aud"`
+		ExpiresAt          int64           `json:"exp"`
+		ServiceAccountName string          `json:"kubernetes.io/serviceaccount/service-account.name"`
+	}{}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("Cannot decode token claims: %s", err)
+	}
+
+	claims := &TokenClaims{
+		Issuer:             raw.Issuer,
+		Subject:            raw.Subject,
+		Audiences:          make([]string, 0),
+		ServiceAccountName: raw.ServiceAccountName,
+	}
+	// Audience is either a single string or a list of strings.
+	if len(raw.Audience) > 0 {
+		var audience string
+		if err := json.Unmarshal(raw.Audience, &audience); err == nil {
+			claims.Audiences = append(claims.Audiences, audience)
+		} else if err := json.Unmarshal(raw.Audience, &claims.Audiences); err != nil {
+			return nil, fmt.Errorf("Cannot decode token audience: %s", err)
+		}
+	}
+	if raw.ExpiresAt > 0 {
+		expiresAt := metaV1.NewTime(time.Unix(raw.ExpiresAt, 0).UTC())
+		claims.ExpiresAt = &expiresAt
+	}
+	return claims, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+func TestGetPodVolumePreview(t *testing.T) {
+	optional := true
+	token := "header." + base64.RawURLEncoding.EncodeToString([]byte(
+		`{"iss":"kubernetes/serviceaccount","sub":"system:serviceaccount:prod:app",`+
+			`"aud":"vault","exp":1493978400,`+
+			`"kubernetes.io/serviceaccount/service-account.name":"app"}`)) + ".signature"
+
+	pod := &v1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: "app-1", Namespace: "prod",
+			Labels: map[string]string{"app": "app", "tier": "web"}},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: "app", Resources: v1.ResourceRequirements{
+				Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("64Mi")},
+			}}},
+			Volumes: []v1.Volume{
+				{Name: "identity", VolumeSource: v1.VolumeSource{Projected: &v1.ProjectedVolumeSource{
+					Sources: []v1.VolumeProjection{
+						{DownwardAPI: &v1.DownwardAPIProjection{Items: []v1.DownwardAPIVolumeFile{
+							{Path: "labels", FieldRef: &v1.ObjectFieldSelector{FieldPath: "metadata.labels"}},
+							{Path: "memory", ResourceFieldRef: &v1.ResourceFieldSelector{
+								ContainerName: "app", Resource: "limits.memory",
+								Divisor: resource.MustParse("1Mi")}},
+						}}},
+						{Secret: &v1.SecretProjection{
+							LocalObjectReference: v1.LocalObjectReference{Name: "app-token"}}},
+						{ConfigMap: &v1.ConfigMapProjection{
+							LocalObjectReference: v1.LocalObjectReference{Name: "settings"},
+							Items:                []v1.KeyToPath{{Key: "mode", Path: "config/mode"}}}},
+						{ConfigMap: &v1.ConfigMapProjection{
+							LocalObjectReference: v1.LocalObjectReference{Name: "extra"},
+							Optional:             &optional}},
+						{Secret: &v1.SecretProjection{
+							LocalObjectReference: v1.LocalObjectReference{Name: "certs"}}},
+					}}}},
+				{Name: "tmp", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}},
+			},
+		},
+	}
+	testClient := fake.NewSimpleClientset(pod,
+		&v1.Secret{
+			ObjectMeta: metaV1.ObjectMeta{Name: "app-token", Namespace: "prod"},
+			Type:       v1.SecretTypeServiceAccountToken,
+			Data:       map[string][]byte{"token": []byte(token), "namespace": []byte("prod")},
+		},
+		&v1.ConfigMap{
+			ObjectMeta: metaV1.ObjectMeta{Name: "settings", Namespace: "prod"},
+			Data:       map[string]string{"level": "debug"},
+		},
+	)
+
+	expiresAt := metaV1.NewTime(time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC))
+	expected := &VolumePreview{
+		Name: "identity",
+		Type: "Projected",
+		Files: []VolumeFile{
+			{Path: "labels", Source: "fieldRef:metadata.labels", Content: "app=\"app\"\ntier=\"web\"",
+				Size: 20},
+			{Path: "memory", Source: "resourceFieldRef:app/limits.memory", Content: "64", Size: 2},
+			{Path: "namespace", Source: "secret:app-token/namespace", Size: 4},
+			{Path: "token", Source: "secret:app-token/token", Size: len(token), Token: &TokenClaims{
+				Issuer:             "kubernetes/serviceaccount",
+				Subject:            "system:serviceaccount:prod:app",
+				Audiences:          []string{"vault"},
+				ExpiresAt:          &expiresAt,
+				ServiceAccountName: "app",
+			}},
+			{Path: "config/mode", Source: "configMap:settings/mode",
+				Error: "Config map settings has no key mode"},
+		},
+		Errors: []string{"Secret certs does not exist"},
+	}
+
+	actual, err := GetPodVolumePreview(testClient, "prod", "app-1", "identity")
+	if err != nil || !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GetPodVolumePreview(identity) == \ngot: %#v, %v \nexpected %#v", actual, err,
+			expected)
+	}
+
+	if _, err := GetPodVolumePreview(testClient, "prod", "app-1", "tmp"); !k8serrors.IsBadRequest(err) {
+		t.Errorf("GetPodVolumePreview(tmp) should return bad request, got: %v", err)
+	}
+	if _, err := GetPodVolumePreview(testClient, "prod", "app-1", "data"); !k8serrors.IsNotFound(err) {
+		t.Errorf("GetPodVolumePreview(data) should return not found, got: %v", err)
+	}
+}
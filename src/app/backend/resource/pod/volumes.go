@@ -0,0 +1,171 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"reflect"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+// Statuses of objects backing pod volumes.
+const (
+	// Object exists and is ready to be used.
+	VolumeObjectExists = "Exists"
+
+	// Object does not exist, or for persistent volume claims, their volume was lost.
+	VolumeObjectMissing = "Missing"
+
+	// Persistent volume claim is not bound to a volume yet.
+	VolumeObjectPending = "Pending"
+)
+
+// Volume is a volume of a pod with objects its source references.
+type Volume struct {
+	Name string `json:"name"`
+
+	// Type of the volume source, e.g. ConfigMap, Secret, PersistentVolumeClaim or EmptyDir.
+	Type string `json:"type"`
+
+	// Objects backing the volume. Empty for volumes not backed by API objects.
+	Objects []VolumeObject `json:"objects"`
+
+	// Whether any of the required objects backing the volume is missing or pending.
+	Unavailable bool `json:"unavailable"`
+}
+
+// VolumeObject is an object referenced by a volume source. Its kind, namespace and name can be
+// used to link to its detail.
+type VolumeObject struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	// Status of the object, i.e. Exists, Missing or Pending.
+	Status string `json:"status"`
+
+	// Whether the pod can start without the object.
+	Optional bool `json:"optional"`
+
+	// Name of the persistent volume bound to the claim. Set only for persistent volume claims.
+	VolumeName string `json:"volumeName,omitempty"`
+}
+
+// getPodVolumes resolves volumes of given pod to the config maps, secrets and persistent volume
+// claims from its namespace they reference.
+func getPodVolumes(pod *v1.Pod, configMaps *v1.ConfigMapList, secrets *v1.SecretList,
+	claims *v1.PersistentVolumeClaimList) []Volume {
+	volumes := make([]Volume, 0)
+	for _, volume := range pod.Spec.Volumes {
+		result := Volume{
+			Name:    volume.Name,
+			Type:    getVolumeType(volume.VolumeSource),
+			Objects: make([]VolumeObject, 0),
+		}
+
+		source := volume.VolumeSource
+		switch {
+		case source.ConfigMap != nil:
+			result.Objects = append(result.Objects, getConfigMapObject(pod.Namespace,
+				source.ConfigMap.Name, source.ConfigMap.Optional, configMaps))
+		case source.Secret != nil:
+			result.Objects = append(result.Objects, getSecretObject(pod.Namespace,
+				source.Secret.SecretName, source.Secret.Optional, secrets))
+		case source.PersistentVolumeClaim != nil:
+			result.Objects = append(result.Objects, getClaimObject(pod.Namespace,
+				source.PersistentVolumeClaim.ClaimName, claims))
+		case source.Projected != nil:
+			for _, projection := range source.Projected.Sources {
+				if projection.ConfigMap != nil {
+					result.Objects = append(result.Objects, getConfigMapObject(pod.Namespace,
+						projection.ConfigMap.Name, projection.ConfigMap.Optional, configMaps))
+				}
+				if projection.Secret != nil {
+					result.Objects = append(result.Objects, getSecretObject(pod.Namespace,
+						projection.Secret.Name, projection.Secret.Optional, secrets))
+				}
+			}
+		}
+
+		for _, object := range result.Objects {
+			if !object.Optional && object.Status != VolumeObjectExists {
+				result.Unavailable = true
+			}
+		}
+		volumes = append(volumes, result)
+	}
+	return volumes
+}
+
+// getVolumeType returns name of the volume source set in given volume, e.g. ConfigMap.
+func getVolumeType(source v1.VolumeSource) string {
+	value := reflect.ValueOf(source)
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.Kind() == reflect.Ptr && !field.IsNil() {
+			return value.Type().Field(i).Name
+		}
+	}
+	return ""
+}
+
+func getConfigMapObject(namespace, name string, optional *bool,
+	configMaps *v1.ConfigMapList) VolumeObject {
+	status := VolumeObjectMissing
+	for _, configMap := range configMaps.Items {
+		if configMap.Name == name {
+			status = VolumeObjectExists
+		}
+	}
+	return newVolumeObject(api.ResourceKindConfigMap, namespace, name, status, optional)
+}
+
+func getSecretObject(namespace, name string, optional *bool, secrets *v1.SecretList) VolumeObject {
+	status := VolumeObjectMissing
+	for _, secret := range secrets.Items {
+		if secret.Name == name {
+			status = VolumeObjectExists
+		}
+	}
+	return newVolumeObject(api.ResourceKindSecret, namespace, name, status, optional)
+}
+
+func getClaimObject(namespace, name string, claims *v1.PersistentVolumeClaimList) VolumeObject {
+	object := newVolumeObject(api.ResourceKindPersistentVolumeClaim, namespace, name,
+		VolumeObjectMissing, nil)
+	for _, claim := range claims.Items {
+		if claim.Name != name {
+			continue
+		}
+		switch claim.Status.Phase {
+		case v1.ClaimBound:
+			object.Status = VolumeObjectExists
+			object.VolumeName = claim.Spec.VolumeName
+		case v1.ClaimPending:
+			object.Status = VolumeObjectPending
+		}
+	}
+	return object
+}
+
+func newVolumeObject(kind api.ResourceKind, namespace, name, status string,
+	optional *bool) VolumeObject {
+	return VolumeObject{
+		ObjectMeta: api.ObjectMeta{Name: name, Namespace: namespace},
+		TypeMeta:   api.NewTypeMeta(kind),
+		Status:     status,
+		Optional:   optional != nil && *optional,
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+func TestGetPodVolumes(t *testing.T) {
+	optional := true
+	configMaps := &v1.ConfigMapList{Items: []v1.ConfigMap{
+		{ObjectMeta: metaV1.ObjectMeta{Name: "config"}},
+	}}
+	secrets := &v1.SecretList{Items: []v1.Secret{
+		{ObjectMeta: metaV1.ObjectMeta{Name: "token"}},
+	}}
+	claims := &v1.PersistentVolumeClaimList{Items: []v1.PersistentVolumeClaim{
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "data"},
+			Spec:       v1.PersistentVolumeClaimSpec{VolumeName: "pv-1"},
+			Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimBound},
+		},
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "cache"},
+			Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimPending},
+		},
+	}}
+	object := func(kind api.ResourceKind, name, status string) VolumeObject {
+		return VolumeObject{
+			ObjectMeta: api.ObjectMeta{Name: name, Namespace: "prod"},
+			TypeMeta:   api.NewTypeMeta(kind),
+			Status:     status,
+		}
+	}
+
+	cases := []struct {
+		volume   v1.Volume
+		expected Volume
+	}{
+		{
+			v1.Volume{Name: "tmp", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}},
+			Volume{Name: "tmp", Type: "EmptyDir", Objects: []VolumeObject{}},
+		},
+		{
+			v1.Volume{Name: "config", VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{
+				LocalObjectReference: v1.LocalObjectReference{Name: "config"}}}},
+			Volume{Name: "config", Type: "ConfigMap", Objects: []VolumeObject{
+				object(api.ResourceKindConfigMap, "config", VolumeObjectExists)}},
+		},
+		{
+			v1.Volume{Name: "certs", VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{
+				SecretName: "certs"}}},
+			Volume{Name: "certs", Type: "Secret", Unavailable: true, Objects: []VolumeObject{
+				object(api.ResourceKindSecret, "certs", VolumeObjectMissing)}},
+		},
+		{
+			v1.Volume{Name: "data", VolumeSource: v1.VolumeSource{
+				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}},
+			Volume{Name: "data", Type: "PersistentVolumeClaim", Objects: []VolumeObject{{
+				ObjectMeta: api.ObjectMeta{Name: "data", Namespace: "prod"},
+				TypeMeta:   api.NewTypeMeta(api.ResourceKindPersistentVolumeClaim),
+				Status:     VolumeObjectExists,
+				VolumeName: "pv-1",
+			}}},
+		},
+		{
+			v1.Volume{Name: "cache", VolumeSource: v1.VolumeSource{
+				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "cache"}}},
+			Volume{Name: "cache", Type: "PersistentVolumeClaim", Unavailable: true,
+				Objects: []VolumeObject{
+					object(api.ResourceKindPersistentVolumeClaim, "cache", VolumeObjectPending)}},
+		},
+		{
+			v1.Volume{Name: "all", VolumeSource: v1.VolumeSource{Projected: &v1.ProjectedVolumeSource{
+				Sources: []v1.VolumeProjection{
+					{Secret: &v1.SecretProjection{
+						LocalObjectReference: v1.LocalObjectReference{Name: "token"}}},
+					{ConfigMap: &v1.ConfigMapProjection{
+						LocalObjectReference: v1.LocalObjectReference{Name: "extra"},
+						Optional:             &optional}},
+				}}}},
+			Volume{Name: "all", Type: "Projected", Objects: []VolumeObject{
+				object(api.ResourceKindSecret, "token", VolumeObjectExists),
+				{
+					ObjectMeta: api.ObjectMeta{Name: "extra", Namespace: "prod"},
+					TypeMeta:   api.NewTypeMeta(api.ResourceKindConfigMap),
+					Status:     VolumeObjectMissing,
+					Optional:   true,
+				},
+			}},
+		},
+	}
+
+	for _, c := range cases {
+		pod := &v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Namespace: "prod"},
+			Spec:       v1.PodSpec{Volumes: []v1.Volume{c.volume}},
+		}
+		actual := getPodVolumes(pod, configMaps, secrets, claims)
+		if !reflect.DeepEqual(actual, []Volume{c.expected}) {
+			t.Errorf("getPodVolumes(%#v) == \ngot: %#v, \nexpected %#v", c.volume, actual,
+				[]Volume{c.expected})
+		}
+	}
+}
@@ -100,10 +100,10 @@ func SimplifyRbacRoleBindingLists(roleBindings []rbac.RoleBinding, clusterRoleBi
 				Subjects:   item.Subjects,
 			})
 	}
-	selectedItems := fromCells(dataselect.GenericDataSelect(toCells(items), dsQuery))
+	selectedCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(items), dsQuery)
 	result := &RbacRoleBindingList{
-		Items:    selectedItems,
-		ListMeta: api.ListMeta{TotalItems: len(roleBindings) + len(clusterRoleBindings)},
+		Items:    fromCells(selectedCells),
+		ListMeta: dataselect.NewListMeta(dsQuery, filteredTotal, len(items)),
 	}
 	return result
 }
@@ -68,7 +68,7 @@ func TestGetRbacRoleBindingList(t *testing.T) {
 				},
 			},
 			&RbacRoleBindingList{
-				ListMeta: api.ListMeta{TotalItems: 2},
+				ListMeta: api.ListMeta{TotalItems: 2, TotalBeforeFilter: 2},
 				Items: []RbacRoleBinding{{
 					ObjectMeta: api.ObjectMeta{Name: "RoleBinding", Namespace: "Testing"},
 					TypeMeta:   api.NewTypeMeta(api.ResourceKindRbacRoleBinding),
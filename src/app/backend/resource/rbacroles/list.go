@@ -95,7 +95,7 @@ func SimplifyRbacRoleLists(roles []rbac.Role, clusterRoles []rbac.ClusterRole, d
 	roleCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(items), dsQuery)
 	items = fromCells(roleCells)
 
-	result.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+	result.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, result.ListMeta.TotalItems)
 	result.Items = items
 
 	return result
@@ -51,7 +51,7 @@ func TestGetRbacRoleList(t *testing.T) {
 				},
 			},
 			&RbacRoleList{
-				ListMeta: api.ListMeta{TotalItems: 2},
+				ListMeta: api.ListMeta{TotalItems: 2, TotalBeforeFilter: 2},
 				Items: []RbacRole{{
 					ObjectMeta: api.ObjectMeta{Name: "Role", Namespace: "Testing"},
 					TypeMeta:   api.TypeMeta{Kind: api.ResourceKindRbacRole},
@@ -18,7 +18,8 @@ import (
 	"log"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	"github.com/kubernetes/dashboard/src/app/backend/link"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/horizontalpodautoscaler"
@@ -36,6 +37,9 @@ type ReplicaSetDetail struct {
 	ObjectMeta api.ObjectMeta `json:"objectMeta"`
 	TypeMeta   api.TypeMeta   `json:"typeMeta"`
 
+	// Links to external tools configured in settings and in the links annotation.
+	Links []link.Link `json:"links,omitempty"`
+
 	// Aggregate information about pods belonging to this Replica Set.
 	PodInfo common.PodInfo `json:"podInfo"`
 
@@ -54,12 +58,12 @@ type ReplicaSetDetail struct {
 	// Selector of this replica set.
 	Selector *metaV1.LabelSelector `json:"selector"`
 
-	// List of Horizontal Pod Autoscalers targeting this Replica Set.
+	// List of Horizontal Pod Autoscalers targeting this Replica Set or the Deployment controlling it.
 	HorizontalPodAutoscalerList horizontalpodautoscaler.HorizontalPodAutoscalerList `json:"horizontalPodAutoscalerList"`
 }
 
 // GetReplicaSetDetail gets replica set details.
-func GetReplicaSetDetail(client k8sClient.Interface, heapsterClient heapster.HeapsterClient,
+func GetReplicaSetDetail(client k8sClient.Interface, heapsterClient metricapi.MetricClient,
 	namespace, name string) (*ReplicaSetDetail, error) {
 	log.Printf("Getting details of %s service in %s namespace", name, namespace)
 
@@ -89,7 +93,8 @@ func GetReplicaSetDetail(client k8sClient.Interface, heapsterClient heapster.Hea
 		return nil, err
 	}
 
-	hpas, err := horizontalpodautoscaler.GetHorizontalPodAutoscalerListForResource(client, namespace, "ReplicaSet", name)
+	hpas, err := horizontalpodautoscaler.GetHorizontalPodAutoscalerListForTargets(client, namespace,
+		getScaleTargets(replicaSetData))
 	if err != nil {
 		return nil, err
 	}
@@ -98,6 +103,19 @@ func GetReplicaSetDetail(client k8sClient.Interface, heapsterClient heapster.Hea
 	return &replicaSet, nil
 }
 
+// getScaleTargets returns resources that scale given replica set, i.e. the replica set itself and
+// the deployment controlling it, as autoscalers of the deployment scale its replica sets too.
+func getScaleTargets(replicaSet *extensions.ReplicaSet) []horizontalpodautoscaler.ScaleTargetRef {
+	targets := []horizontalpodautoscaler.ScaleTargetRef{{Kind: "ReplicaSet", Name: replicaSet.Name}}
+	for _, reference := range replicaSet.OwnerReferences {
+		if reference.Controller != nil && *reference.Controller && reference.Kind == "Deployment" {
+			targets = append(targets, horizontalpodautoscaler.ScaleTargetRef{Kind: reference.Kind,
+				Name: reference.Name})
+		}
+	}
+	return targets
+}
+
 // ToReplicaSetDetail converts replica set api object to replica set detail model object.
 func ToReplicaSetDetail(replicaSet *extensions.ReplicaSet, eventList common.EventList,
 	podList pod.PodList, podInfo common.PodInfo, serviceList resourceService.ServiceList, hpas horizontalpodautoscaler.HorizontalPodAutoscalerList) ReplicaSetDetail {
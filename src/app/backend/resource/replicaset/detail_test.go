@@ -19,7 +19,7 @@ import (
 	"testing"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/horizontalpodautoscaler"
@@ -36,7 +36,7 @@ type FakeHeapsterClient struct {
 	client fake.Clientset
 }
 
-func (c FakeHeapsterClient) Get(path string) heapster.RequestInterface {
+func (c FakeHeapsterClient) Get(path string) metricapi.RequestInterface {
 	return &restclient.Request{}
 }
 
@@ -67,14 +67,18 @@ func TestGetReplicaSetDetail(t *testing.T) {
 				TypeMeta: api.TypeMeta{Kind: api.ResourceKindReplicaSet},
 				PodInfo:  common.PodInfo{Warnings: []common.Event{}},
 				PodList: pod.PodList{
+					ListMeta:          api.ListMeta{Page: 1, ItemsPerPage: 10},
 					Pods:              []pod.Pod{},
 					CumulativeMetrics: make([]metric.Metric, 0),
 				},
 				Selector: &metaV1.LabelSelector{
 					MatchLabels: map[string]string{"app": "test"},
 				},
-				ServiceList:                 service.ServiceList{Services: []service.Service{}},
-				EventList:                   common.EventList{Events: []common.Event{}},
+				ServiceList: service.ServiceList{
+					ListMeta: api.ListMeta{Page: 1, ItemsPerPage: 10},
+					Services: []service.Service{},
+				},
+				EventList:                   common.EventList{ListMeta: api.ListMeta{Page: 1, ItemsPerPage: 10}, Events: []common.Event{}},
 				HorizontalPodAutoscalerList: horizontalpodautoscaler.HorizontalPodAutoscalerList{HorizontalPodAutoscalers: []horizontalpodautoscaler.HorizontalPodAutoscaler{}},
 			},
 		},
@@ -169,3 +173,36 @@ func TestToReplicaSetDetail(t *testing.T) {
 		}
 	}
 }
+
+func TestGetScaleTargets(t *testing.T) {
+	controller := true
+	cases := []struct {
+		replicaSet *extensions.ReplicaSet
+		expected   []horizontalpodautoscaler.ScaleTargetRef
+	}{
+		{
+			&extensions.ReplicaSet{ObjectMeta: metaV1.ObjectMeta{Name: "rs-1"}},
+			[]horizontalpodautoscaler.ScaleTargetRef{{Kind: "ReplicaSet", Name: "rs-1"}},
+		},
+		{
+			&extensions.ReplicaSet{ObjectMeta: metaV1.ObjectMeta{
+				Name: "rs-1",
+				OwnerReferences: []metaV1.OwnerReference{
+					{Kind: "Deployment", Name: "deployment-1", Controller: &controller},
+					{Kind: "Deployment", Name: "deployment-2"},
+				},
+			}},
+			[]horizontalpodautoscaler.ScaleTargetRef{
+				{Kind: "ReplicaSet", Name: "rs-1"},
+				{Kind: "Deployment", Name: "deployment-1"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		actual := getScaleTargets(c.replicaSet)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("getScaleTargets(%#v) == \ngot %#v, \nexpected %#v", c.replicaSet, actual, c.expected)
+		}
+	}
+}
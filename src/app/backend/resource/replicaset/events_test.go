@@ -55,7 +55,7 @@ func TestGetReplicaSetEvents(t *testing.T) {
 					}}},
 			[]string{"list", "get", "list", "list"},
 			&common.EventList{
-				ListMeta: api.ListMeta{TotalItems: 1},
+				ListMeta: api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
 				Events: []common.Event{{
 					TypeMeta: api.TypeMeta{Kind: api.ResourceKindEvent},
 					ObjectMeta: api.ObjectMeta{
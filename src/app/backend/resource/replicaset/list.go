@@ -18,7 +18,7 @@ import (
 	"log"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
@@ -33,6 +33,9 @@ import (
 type ReplicaSetList struct {
 	ListMeta api.ListMeta `json:"listMeta"`
 
+	// Status summary of the returned items.
+	Status common.ResourceStatus `json:"status"`
+
 	// Unordered list of Replica Sets.
 	ReplicaSets       []ReplicaSet    `json:"replicaSets"`
 	CumulativeMetrics []metric.Metric `json:"cumulativeMetrics"`
@@ -40,13 +43,14 @@ type ReplicaSetList struct {
 
 // GetReplicaSetList returns a list of all Replica Sets in the cluster.
 func GetReplicaSetList(client client.Interface, nsQuery *common.NamespaceQuery,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*ReplicaSetList, error) {
+	dsQuery *dataselect.DataSelectQuery, heapsterClient *metricapi.MetricClient) (*ReplicaSetList, error) {
 	log.Print("Getting list of all replica sets in the cluster")
 
+	options := dsQuery.ListOptions()
 	channels := &common.ResourceChannels{
-		ReplicaSetList: common.GetReplicaSetListChannel(client, nsQuery, 1),
-		PodList:        common.GetPodListChannel(client, nsQuery, 1),
-		EventList:      common.GetEventListChannel(client, nsQuery, 1),
+		ReplicaSetList: common.GetReplicaSetListChannelWithOptions(client, nsQuery, options, 1),
+		PodList:        common.GetFanOutPodListChannel(client, nsQuery, 1),
+		EventList:      common.GetWarningEventListChannel(client, nsQuery, 1),
 	}
 
 	return GetReplicaSetListFromChannels(channels, dsQuery, heapsterClient)
@@ -55,7 +59,7 @@ func GetReplicaSetList(client client.Interface, nsQuery *common.NamespaceQuery,
 // GetReplicaSetListFromChannels returns a list of all Replica Sets in the cluster
 // reading required resource list once from the channels.
 func GetReplicaSetListFromChannels(channels *common.ResourceChannels,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*ReplicaSetList, error) {
+	dsQuery *dataselect.DataSelectQuery, heapsterClient *metricapi.MetricClient) (*ReplicaSetList, error) {
 
 	replicaSets := <-channels.ReplicaSetList.List
 	if err := <-channels.ReplicaSetList.Error; err != nil {
@@ -80,32 +84,34 @@ func GetReplicaSetListFromChannels(channels *common.ResourceChannels,
 	if err := <-channels.EventList.Error; err != nil {
 		return nil, err
 	}
-	return CreateReplicaSetList(replicaSets.Items, pods.Items, events.Items, dsQuery, heapsterClient), nil
+	result := CreateReplicaSetList(replicaSets.Items, pods.Items, events.Items, dsQuery, heapsterClient)
+	result.ListMeta.Warnings = common.GetListBudgetWarnings(pods, events)
+	return result, nil
 }
 
 // CreateReplicaSetList creates paginated list of Replica Set model
 // objects based on Kubernetes Replica Set objects array and related resources arrays.
 func CreateReplicaSetList(replicaSets []extensions.ReplicaSet, pods []v1.Pod, events []v1.Event,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) *ReplicaSetList {
+	dsQuery *dataselect.DataSelectQuery, heapsterClient *metricapi.MetricClient) *ReplicaSetList {
 
 	replicaSetList := &ReplicaSetList{
 		ReplicaSets: make([]ReplicaSet, 0),
 		ListMeta:    api.ListMeta{TotalItems: len(replicaSets)},
 	}
 
+	for _, replicaSet := range FromCells(dataselect.GenericDataFilter(ToCells(replicaSets), dsQuery)) {
+		replicaSetList.Status.AddPodInfo(getPodInfo(replicaSet, pods, events))
+	}
+
 	cachedResources := &dataselect.CachedResources{
 		Pods: pods,
 	}
 	rsCells, metricPromises, filteredTotal := dataselect.GenericDataSelectWithFilterAndMetrics(ToCells(replicaSets), dsQuery, cachedResources, heapsterClient)
 	replicaSets = FromCells(rsCells)
-	replicaSetList.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+	replicaSetList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, replicaSetList.ListMeta.TotalItems)
 
 	for _, replicaSet := range replicaSets {
-		matchingPods := common.FilterPodsByOwnerReference(replicaSet.Namespace,
-			replicaSet.UID, pods)
-		podInfo := common.GetPodInfo(replicaSet.Status.Replicas, *replicaSet.Spec.Replicas,
-			matchingPods)
-		podInfo.Warnings = event.GetPodsEventWarnings(events, matchingPods)
+		podInfo := getPodInfo(replicaSet, pods, events)
 		replicaSetList.ReplicaSets = append(replicaSetList.ReplicaSets,
 			ToReplicaSet(&replicaSet, &podInfo))
 	}
@@ -118,3 +124,12 @@ func CreateReplicaSetList(replicaSets []extensions.ReplicaSet, pods []v1.Pod, ev
 
 	return replicaSetList
 }
+
+// getPodInfo returns aggregate information about pods of given replica set.
+func getPodInfo(replicaSet extensions.ReplicaSet, pods []v1.Pod, events []v1.Event) common.PodInfo {
+	matchingPods := common.FilterPodsByOwnerReference(replicaSet.Namespace, replicaSet.UID, pods)
+	podInfo := common.GetPodInfo(replicaSet.Status.Replicas, *replicaSet.Spec.Replicas,
+		matchingPods)
+	podInfo.Warnings = event.GetPodsEventWarnings(events, matchingPods)
+	return podInfo
+}
@@ -140,7 +140,8 @@ func TestGetReplicaSetListFromChannels(t *testing.T) {
 				},
 			},
 			&ReplicaSetList{
-				ListMeta:          api.ListMeta{TotalItems: 1},
+				ListMeta:          api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
+				Status:            common.ResourceStatus{Failed: 1},
 				CumulativeMetrics: make([]metric.Metric, 0),
 				ReplicaSets: []ReplicaSet{{
 					ObjectMeta: api.ObjectMeta{
@@ -233,7 +234,8 @@ func TestCreateReplicaSetList(t *testing.T) {
 			[]v1.Pod{},
 			[]v1.Event{},
 			&ReplicaSetList{
-				ListMeta:          api.ListMeta{TotalItems: 1},
+				ListMeta:          api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
+				Status:            common.ResourceStatus{Running: 1},
 				CumulativeMetrics: make([]metric.Metric, 0),
 				ReplicaSets: []ReplicaSet{
 					{
@@ -18,7 +18,8 @@ import (
 	"log"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	"github.com/kubernetes/dashboard/src/app/backend/link"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/horizontalpodautoscaler"
@@ -34,6 +35,9 @@ type ReplicationControllerDetail struct {
 	ObjectMeta api.ObjectMeta `json:"objectMeta"`
 	TypeMeta   api.TypeMeta   `json:"typeMeta"`
 
+	// Links to external tools configured in settings and in the links annotation.
+	Links []link.Link `json:"links,omitempty"`
+
 	// Label selector of the Replication Controller.
 	LabelSelector map[string]string `json:"labelSelector"`
 
@@ -59,16 +63,10 @@ type ReplicationControllerDetail struct {
 	HorizontalPodAutoscalerList horizontalpodautoscaler.HorizontalPodAutoscalerList `json:"horizontalPodAutoscalerList"`
 }
 
-// ReplicationControllerSpec contains information needed to update replication controller.
-type ReplicationControllerSpec struct {
-	// Replicas (pods) number in replicas set
-	Replicas int32 `json:"replicas"`
-}
-
 // GetReplicationControllerDetail returns detailed information about the given replication
 // controller in the given namespace.
 func GetReplicationControllerDetail(client k8sClient.Interface,
-	heapsterClient heapster.HeapsterClient,
+	heapsterClient metricapi.MetricClient,
 	namespace, name string) (*ReplicationControllerDetail, error) {
 	log.Printf("Getting details of %s replication controller in %s namespace", name, namespace)
 
@@ -109,31 +107,6 @@ func GetReplicationControllerDetail(client k8sClient.Interface,
 	return &replicationControllerDetail, nil
 }
 
-// UpdateReplicasCount updates number of replicas in Replication Controller based on Replication
-// Controller Spec
-func UpdateReplicasCount(client k8sClient.Interface, namespace, name string,
-	replicationControllerSpec *ReplicationControllerSpec) error {
-	log.Printf("Updating replicas count to %d for %s replication controller from %s namespace",
-		replicationControllerSpec.Replicas, name, namespace)
-
-	replicationController, err := client.CoreV1().ReplicationControllers(namespace).Get(name, metaV1.GetOptions{})
-	if err != nil {
-		return err
-	}
-
-	replicationController.Spec.Replicas = &replicationControllerSpec.Replicas
-
-	_, err = client.Core().ReplicationControllers(namespace).Update(replicationController)
-	if err != nil {
-		return err
-	}
-
-	log.Printf("Successfully updated replicas count to %d for %s replication controller from %s namespace",
-		replicationControllerSpec.Replicas, name, namespace)
-
-	return nil
-}
-
 // ToReplicationControllerDetail converts replication controller api object to replication
 // controller detail model object.
 func ToReplicationControllerDetail(replicationController *v1.ReplicationController,
@@ -18,7 +18,7 @@ import (
 	"log"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
@@ -31,6 +31,9 @@ import (
 type ReplicationControllerList struct {
 	ListMeta api.ListMeta `json:"listMeta"`
 
+	// Status summary of the returned items.
+	Status common.ResourceStatus `json:"status"`
+
 	// Unordered list of Replication Controllers.
 	ReplicationControllers []ReplicationController `json:"replicationControllers"`
 	CumulativeMetrics      []metric.Metric         `json:"cumulativeMetrics"`
@@ -38,13 +41,14 @@ type ReplicationControllerList struct {
 
 // GetReplicationControllerList returns a list of all Replication Controllers in the cluster.
 func GetReplicationControllerList(client *client.Clientset, nsQuery *common.NamespaceQuery,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*ReplicationControllerList, error) {
+	dsQuery *dataselect.DataSelectQuery, heapsterClient *metricapi.MetricClient) (*ReplicationControllerList, error) {
 	log.Print("Getting list of all replication controllers in the cluster")
 
+	options := dsQuery.ListOptions()
 	channels := &common.ResourceChannels{
-		ReplicationControllerList: common.GetReplicationControllerListChannel(client, nsQuery, 1),
-		PodList:                   common.GetPodListChannel(client, nsQuery, 1),
-		EventList:                 common.GetEventListChannel(client, nsQuery, 1),
+		ReplicationControllerList: common.GetReplicationControllerListChannelWithOptions(client, nsQuery, options, 1),
+		PodList:                   common.GetFanOutPodListChannel(client, nsQuery, 1),
+		EventList:                 common.GetWarningEventListChannel(client, nsQuery, 1),
 	}
 
 	return GetReplicationControllerListFromChannels(channels, dsQuery, heapsterClient)
@@ -53,7 +57,7 @@ func GetReplicationControllerList(client *client.Clientset, nsQuery *common.Name
 // GetReplicationControllerListFromChannels returns a list of all Replication Controllers in the cluster
 // reading required resource list once from the channels.
 func GetReplicationControllerListFromChannels(channels *common.ResourceChannels,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*ReplicationControllerList, error) {
+	dsQuery *dataselect.DataSelectQuery, heapsterClient *metricapi.MetricClient) (*ReplicationControllerList, error) {
 
 	rcList := <-channels.ReplicationControllerList.List
 	if err := <-channels.ReplicationControllerList.Error; err != nil {
@@ -70,32 +74,35 @@ func GetReplicationControllerListFromChannels(channels *common.ResourceChannels,
 		return nil, err
 	}
 
-	return CreateReplicationControllerList(rcList.Items, dsQuery, podList.Items, eventList.Items, heapsterClient), nil
+	result := CreateReplicationControllerList(rcList.Items, dsQuery, podList.Items, eventList.Items, heapsterClient)
+	result.ListMeta.Warnings = common.GetListBudgetWarnings(podList, eventList)
+	return result, nil
 }
 
 // CreateReplicationControllerList creates paginated list of Replication Controller model
 // objects based on Kubernetes Replication Controller objects array and related resources arrays.
 func CreateReplicationControllerList(replicationControllers []v1.ReplicationController,
-	dsQuery *dataselect.DataSelectQuery, pods []v1.Pod, events []v1.Event, heapsterClient *heapster.HeapsterClient) *ReplicationControllerList {
+	dsQuery *dataselect.DataSelectQuery, pods []v1.Pod, events []v1.Event, heapsterClient *metricapi.MetricClient) *ReplicationControllerList {
 
 	rcList := &ReplicationControllerList{
 		ReplicationControllers: make([]ReplicationController, 0),
 		ListMeta:               api.ListMeta{TotalItems: len(replicationControllers)},
 	}
+
+	for _, rc := range fromCells(dataselect.GenericDataFilter(toCells(replicationControllers), dsQuery)) {
+		rcList.Status.AddPodInfo(getPodInfo(rc, pods, events))
+	}
+
 	cachedResources := &dataselect.CachedResources{
 		Pods: pods,
 	}
 	rcCells, metricPromises, filteredTotal := dataselect.GenericDataSelectWithFilterAndMetrics(
 		toCells(replicationControllers), dsQuery, cachedResources, heapsterClient)
 	replicationControllers = fromCells(rcCells)
-	rcList.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+	rcList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, rcList.ListMeta.TotalItems)
 
 	for _, rc := range replicationControllers {
-		matchingPods := common.FilterPodsByOwnerReference(rc.Namespace, rc.UID, pods)
-
-		podInfo := common.GetPodInfo(rc.Status.Replicas, *rc.Spec.Replicas, matchingPods)
-		podInfo.Warnings = event.GetPodsEventWarnings(events, matchingPods)
-
+		podInfo := getPodInfo(rc, pods, events)
 		replicationController := ToReplicationController(&rc, &podInfo)
 		rcList.ReplicationControllers = append(rcList.ReplicationControllers, replicationController)
 	}
@@ -108,3 +115,11 @@ func CreateReplicationControllerList(replicationControllers []v1.ReplicationCont
 
 	return rcList
 }
+
+// getPodInfo returns aggregate information about pods of given replication controller.
+func getPodInfo(rc v1.ReplicationController, pods []v1.Pod, events []v1.Event) common.PodInfo {
+	matchingPods := common.FilterPodsByOwnerReference(rc.Namespace, rc.UID, pods)
+	podInfo := common.GetPodInfo(rc.Status.Replicas, *rc.Spec.Replicas, matchingPods)
+	podInfo.Warnings = event.GetPodsEventWarnings(events, matchingPods)
+	return podInfo
+}
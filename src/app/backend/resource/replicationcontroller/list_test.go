@@ -176,7 +176,8 @@ func TestGetReplicationControllerList(t *testing.T) {
 			},
 			},
 			&ReplicationControllerList{
-				ListMeta:          api.ListMeta{TotalItems: 2},
+				ListMeta:          api.ListMeta{TotalItems: 2, TotalBeforeFilter: 2},
+				Status:            common.ResourceStatus{Running: 1, Failed: 1},
 				CumulativeMetrics: make([]metric.Metric, 0),
 				ReplicationControllers: []ReplicationController{
 					{
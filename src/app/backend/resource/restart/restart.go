@@ -0,0 +1,108 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restart
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/statefulset"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+// RestartedAtAnnotationKey is pod template annotation key for time of the last restart. Changing
+// it rolls out new pods, the same way as kubectl rollout restart does.
+const RestartedAtAnnotationKey = "kubectl.kubernetes.io/restartedAt"
+
+// RestartResource rolls out new pods of given workload controller by annotating its pod template
+// with current time, the same way as kubectl rollout restart does. Deployments, daemon sets and
+// stateful sets are supported. Daemon sets and stateful sets using OnDelete update strategy don't
+// roll out changes of pod template, so they cannot be restarted.
+func RestartResource(client client.Interface, kind, namespace, name string) error {
+	log.Printf("Restarting %s %s in %s namespace", kind, name, namespace)
+
+	now := time.Now()
+	switch kind {
+	case api.ResourceKindDeployment:
+		deployment, err := client.ExtensionsV1beta1().Deployments(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		annotateRestart(&deployment.Spec.Template, now)
+		_, err = client.ExtensionsV1beta1().Deployments(namespace).Update(deployment)
+		return err
+	case api.ResourceKindDaemonSet:
+		daemonSet, err := client.ExtensionsV1beta1().DaemonSets(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if daemonSet.Spec.UpdateStrategy.Type == extensions.OnDeleteDaemonSetStrategyType {
+			return newOnDeleteError(kind, name)
+		}
+		annotateRestart(&daemonSet.Spec.Template, now)
+		_, err = client.ExtensionsV1beta1().DaemonSets(namespace).Update(daemonSet)
+		return err
+	case api.ResourceKindStatefulSet:
+		return restartStatefulSet(client, namespace, name, now)
+	default:
+		return k8serrors.NewBadRequest(fmt.Sprintf("Resources of kind %s cannot be restarted", kind))
+	}
+}
+
+// restartStatefulSet annotates pod template of given stateful set with merge patch. Update
+// strategy is not known to the API version used by the client, so updating whole stateful set
+// would reset it to default.
+func restartStatefulSet(client client.Interface, namespace, name string, now time.Time) error {
+	strategy, err := statefulset.GetUpdateStrategy(client, namespace, name)
+	if err != nil {
+		return err
+	}
+	switch strategy.Type {
+	case "":
+		return k8serrors.NewBadRequest("Updating stateful sets is not supported by the cluster")
+	case statefulset.UpdateStrategyOnDelete:
+		return newOnDeleteError(api.ResourceKindStatefulSet, name)
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`,
+		RestartedAtAnnotationKey, now.Format(time.RFC3339))
+	return common.JSONRequest(client.AppsV1beta1().RESTClient().Patch(types.MergePatchType).
+		Namespace(namespace).
+		Resource("statefulsets").
+		Name(name).
+		Body([]byte(patch))).
+		Do().
+		Error()
+}
+
+func newOnDeleteError(kind, name string) error {
+	return k8serrors.NewBadRequest(fmt.Sprintf("The %s %s uses OnDelete update strategy, its pods "+
+		"are replaced only when deleted", kind, name))
+}
+
+func annotateRestart(template *v1.PodTemplateSpec, now time.Time) {
+	if template.Annotations == nil {
+		template.Annotations = make(map[string]string)
+	}
+	template.Annotations[RestartedAtAnnotationKey] = now.Format(time.RFC3339)
+}
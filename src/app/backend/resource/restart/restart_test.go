@@ -0,0 +1,120 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restart
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+	"k8s.io/client-go/rest"
+)
+
+func TestRestartResource(t *testing.T) {
+	objectMeta := metaV1.ObjectMeta{Name: "app", Namespace: "prod"}
+	testClient := fake.NewSimpleClientset(
+		&extensions.Deployment{ObjectMeta: objectMeta},
+		&extensions.DaemonSet{ObjectMeta: objectMeta},
+		&extensions.DaemonSet{
+			ObjectMeta: metaV1.ObjectMeta{Name: "on-delete", Namespace: "prod"},
+			Spec: extensions.DaemonSetSpec{UpdateStrategy: extensions.DaemonSetUpdateStrategy{
+				Type: extensions.OnDeleteDaemonSetStrategyType,
+			}},
+		},
+	)
+
+	getTemplate := map[string]func() v1.PodTemplateSpec{
+		api.ResourceKindDeployment: func() v1.PodTemplateSpec {
+			d, _ := testClient.ExtensionsV1beta1().Deployments("prod").Get("app", metaV1.GetOptions{})
+			return d.Spec.Template
+		},
+		api.ResourceKindDaemonSet: func() v1.PodTemplateSpec {
+			ds, _ := testClient.ExtensionsV1beta1().DaemonSets("prod").Get("app", metaV1.GetOptions{})
+			return ds.Spec.Template
+		},
+	}
+	for kind, get := range getTemplate {
+		if err := RestartResource(testClient, kind, "prod", "app"); err != nil {
+			t.Fatalf("RestartResource(%#v) returned error: %s", kind, err)
+		}
+		if get().Annotations[RestartedAtAnnotationKey] == "" {
+			t.Errorf("RestartResource(%#v) should annotate pod template, got: %#v", kind,
+				get().Annotations)
+		}
+	}
+
+	err := RestartResource(testClient, api.ResourceKindPod, "prod", "app")
+	if !k8serrors.IsBadRequest(err) {
+		t.Errorf("RestartResource(pod) should return bad request, got: %v", err)
+	}
+	err = RestartResource(testClient, api.ResourceKindDaemonSet, "prod", "other")
+	if !k8serrors.IsNotFound(err) {
+		t.Errorf("RestartResource() of missing daemon set should return not found, got: %v", err)
+	}
+	err = RestartResource(testClient, api.ResourceKindDaemonSet, "prod", "on-delete")
+	if !k8serrors.IsBadRequest(err) {
+		t.Errorf("RestartResource() of OnDelete daemon set should return bad request, got: %v", err)
+	}
+}
+
+func TestRestartStatefulSet(t *testing.T) {
+	cases := []struct {
+		strategyType     string
+		expectPatch      bool
+		expectBadRequest bool
+	}{
+		{"RollingUpdate", true, false},
+		{"OnDelete", false, true},
+		{"", false, true},
+	}
+	for _, c := range cases {
+		patch := ""
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "PATCH" {
+				body, _ := ioutil.ReadAll(r.Body)
+				patch = string(body)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"spec":{"updateStrategy":{"type":%q}}}`, c.strategyType)
+		}))
+
+		client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+		if err != nil {
+			t.Fatalf("Cannot create client: %s", err)
+		}
+		err = RestartResource(client, api.ResourceKindStatefulSet, "prod", "app")
+		server.Close()
+
+		if k8serrors.IsBadRequest(err) != c.expectBadRequest || (!c.expectBadRequest && err != nil) {
+			t.Errorf("RestartResource() of %#v stateful set returns error %v, expected bad request: %v",
+				c.strategyType, err, c.expectBadRequest)
+		}
+		if strings.Contains(patch, RestartedAtAnnotationKey) != c.expectPatch ||
+			strings.Contains(patch, "updateStrategy") {
+			t.Errorf("RestartResource() of %#v stateful set sent patch %#v, expected annotation: %v",
+				c.strategyType, patch, c.expectPatch)
+		}
+	}
+}
@@ -15,11 +15,17 @@
 package scaling
 
 import (
-	"strconv"
+	"encoding/json"
+	"fmt"
+	"log"
 	"strings"
 
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/horizontalpodautoscaler"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	client "k8s.io/client-go/kubernetes"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
 )
 
 // ReplicaCounts provide the desired and actual number of replicas.
@@ -28,88 +34,360 @@ type ReplicaCounts struct {
 	ActualReplicas  int32 `json:"actualReplicas"`
 }
 
+// scalableResource describes resource scaled through its scale subresource.
+type scalableResource struct {
+	// Kind of the resource as used by scale target references, e.g. Deployment.
+	kind string
+
+	// Name of the resource, e.g. deployments.
+	resource string
+
+	// Group versions that can serve the scale subresource, in order of preference.
+	groupVersions []string
+}
+
+// extensionsGroupVersion is the group version of the scale subresource known to the client.
+const extensionsGroupVersion = "extensions/v1beta1"
+
+// scalableResources lists resources that can be scaled by lower case kind. Newest group versions go
+// first, older servers only serve scale subresource in extensions or beta apps group.
+var scalableResources = map[string]scalableResource{
+	"deployment": {"Deployment", "deployments",
+		[]string{"apps/v1", "apps/v1beta2", "apps/v1beta1", "extensions/v1beta1"}},
+	"replicaset": {"ReplicaSet", "replicasets",
+		[]string{"apps/v1", "apps/v1beta2", "extensions/v1beta1"}},
+	"statefulset": {"StatefulSet", "statefulsets",
+		[]string{"apps/v1", "apps/v1beta2", "apps/v1beta1"}},
+	"replicationcontroller": {"ReplicationController", "replicationcontrollers",
+		[]string{"v1", "extensions/v1beta1"}},
+}
+
+// rawScale is a subset of the scale subresource, which has the same form in all group versions.
+type rawScale struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	ObjectMeta metaV1.ObjectMeta `json:"metadata"`
+	Spec       struct {
+		Replicas int32 `json:"replicas"`
+	} `json:"spec"`
+	Status struct {
+		Replicas int32 `json:"replicas"`
+	} `json:"status"`
+}
+
 // GetScaleSpec returns a populated ReplicaCounts object with desired and actual number of replicas.
-func GetScaleSpec(client client.Interface, kind, namespace, name string) (rc *ReplicaCounts, err error) {
-	rc = new(ReplicaCounts)
-	s, err := client.ExtensionsV1beta1().Scales(namespace).Get(kind, name)
+func GetScaleSpec(client client.Interface, kind, namespace, name string) (*ReplicaCounts, error) {
+	if strings.ToLower(kind) == "job" {
+		job, err := client.BatchV1().Jobs(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		parallelism := getJobParallelism(job.Spec.Parallelism)
+		return &ReplicaCounts{DesiredReplicas: parallelism, ActualReplicas: parallelism}, nil
+	}
+
+	resource, err := getScalableResource(kind)
 	if err != nil {
 		return nil, err
 	}
-	rc.DesiredReplicas = s.Spec.Replicas
-	rc.ActualReplicas = s.Status.Replicas
 
-	return
+	groupVersion := getScaleGroupVersion(client, resource)
+	if groupVersion == "" {
+		return scaleReplicas(client, resource.kind, namespace, name, nil)
+	}
+
+	scale, err := getScale(client, resource, groupVersion, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return toReplicaCounts(scale), nil
 }
 
-// ScaleResource scales the provided resource using the client scale method in the case of Deployment,
-// ReplicaSet, Replication Controller. In the case of a job we are using the jobs resource update
-// method since the client scale method does not provide one for the job.
-func ScaleResource(client client.Interface, kind, namespace, name, count string) (rc *ReplicaCounts, err error) {
-	rc = new(ReplicaCounts)
+// ScaleResource scales the provided resource to given number of replicas. Deployments, replica
+// sets, stateful sets and replication controllers are scaled through their scale subresource, or
+// by update of their replicas if the cluster doesn't serve it. In the case of a job parallelism is
+// updated since jobs do not provide one.
+func ScaleResource(client client.Interface, kind, namespace, name string, replicas int32) (
+	*ReplicaCounts, error) {
+	log.Printf("Scaling %s %s in %s namespace to %d replicas", kind, name, namespace, replicas)
+
+	if replicas < 0 {
+		return nil, k8serrors.NewBadRequest(fmt.Sprintf("Invalid number of replicas: %d", replicas))
+	}
+
 	if strings.ToLower(kind) == "job" {
-		err = scaleJobResource(client, namespace, name, count, rc)
-	} else if strings.ToLower(kind) == "statefulset" {
-		err = scaleStatefulSetResource(client, namespace, name, count, rc)
-	} else {
-		err = scaleGenericResource(client, kind, namespace, name, count, rc)
+		return scaleJobResource(client, namespace, name, replicas)
 	}
+
+	resource, err := getScalableResource(kind)
 	if err != nil {
 		return nil, err
 	}
 
-	return
+	groupVersion := getScaleGroupVersion(client, resource)
+
+	if err := validateAutoscalerBounds(client, resource.kind, namespace, name, replicas); err != nil {
+		return nil, err
+	}
+
+	if groupVersion == "" {
+		return scaleReplicas(client, resource.kind, namespace, name, &replicas)
+	}
+
+	scale, err := getScale(client, resource, groupVersion, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	scale.Spec.Replicas = replicas
+	scale, err = updateScale(client, resource, groupVersion, scale)
+	if err != nil {
+		return nil, err
+	}
+	return toReplicaCounts(scale), nil
+}
+
+func getScalableResource(kind string) (scalableResource, error) {
+	resource, ok := scalableResources[strings.ToLower(kind)]
+	if !ok {
+		return resource, k8serrors.NewBadRequest(fmt.Sprintf("Scaling of %s is not supported", kind))
+	}
+	return resource, nil
 }
 
-//ScaleGenericResource is used for Deployment, ReplicaSet, Replication Controller scaling.
-func scaleGenericResource(client client.Interface, kind, namespace, name, count string, rc *ReplicaCounts) error {
-	s, err := client.ExtensionsV1beta1().Scales(namespace).Get(kind, name)
+// getScaleGroupVersion returns the newest group version in which the cluster serves scale
+// subresource of given resource, or empty string if it isn't served, e.g. by stateful sets in 1.6.
+// Group versions that cannot be discovered are skipped, as replicas can be updated without scale
+// subresource too.
+func getScaleGroupVersion(client client.Interface, resource scalableResource) string {
+	for _, groupVersion := range resource.groupVersions {
+		resources, err := client.Discovery().ServerResourcesForGroupVersion(groupVersion)
+		if err != nil {
+			if !k8serrors.IsNotFound(err) {
+				log.Printf("Couldn't discover resources of %s: %s", groupVersion, err)
+			}
+			continue
+		}
+
+		for _, apiResource := range resources.APIResources {
+			if apiResource.Name == resource.resource+"/scale" {
+				return groupVersion
+			}
+		}
+	}
+	return ""
+}
+
+// getScalePath returns path of the scale subresource of given resource in given group version.
+func getScalePath(resource scalableResource, groupVersion, namespace, name string) string {
+	prefix := "/apis/" + groupVersion
+	if groupVersion == "v1" {
+		prefix = "/api/v1"
+	}
+	return fmt.Sprintf("%s/namespaces/%s/%s/%s/scale", prefix, namespace, resource.resource, name)
+}
+
+// getScale reads the scale subresource with the typed client in extensions group, which is the
+// one the client knows, and as JSON in other group versions.
+func getScale(client client.Interface, resource scalableResource, groupVersion, namespace,
+	name string) (*rawScale, error) {
+	if groupVersion == extensionsGroupVersion {
+		scale, err := client.ExtensionsV1beta1().Scales(namespace).Get(resource.kind, name)
+		if err != nil {
+			return nil, err
+		}
+		return fromExtensionsScale(scale), nil
+	}
+
+	path := getScalePath(resource, groupVersion, namespace, name)
+	raw, err := common.JSONRequest(client.CoreV1().RESTClient().Get().AbsPath(path)).Do().Raw()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	c, err := strconv.Atoi(count)
+
+	scale := &rawScale{}
+	if err := json.Unmarshal(raw, scale); err != nil {
+		return nil, err
+	}
+	return scale, nil
+}
+
+// updateScale writes given scale subresource in the same way as getScale reads it.
+func updateScale(client client.Interface, resource scalableResource, groupVersion string,
+	scale *rawScale) (*rawScale, error) {
+	if groupVersion == extensionsGroupVersion {
+		updated, err := client.ExtensionsV1beta1().Scales(scale.ObjectMeta.Namespace).Update(
+			resource.kind, toExtensionsScale(scale))
+		if err != nil {
+			return nil, err
+		}
+		return fromExtensionsScale(updated), nil
+	}
+
+	body, err := json.Marshal(scale)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	s.Spec.Replicas = int32(c)
-	s, err = client.ExtensionsV1beta1().Scales(namespace).Update(kind, s)
+
+	path := getScalePath(resource, groupVersion, scale.ObjectMeta.Namespace, scale.ObjectMeta.Name)
+	raw, err := common.JSONRequest(common.JSONBody(client.CoreV1().RESTClient().Put().AbsPath(path),
+		body)).Do().Raw()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	rc.DesiredReplicas = s.Spec.Replicas
-	rc.ActualReplicas = s.Status.Replicas
 
-	return nil
+	updated := &rawScale{}
+	if err := json.Unmarshal(raw, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
 }
 
-// scaleJobResource is exclusively used for jobs as it does not increase/decrease pods but jobs parallelism attribute.
-func scaleJobResource(client client.Interface, namespace, name, count string, rc *ReplicaCounts) error {
-	j, err := client.BatchV1().Jobs(namespace).Get(name, metaV1.GetOptions{})
-	c, err := strconv.Atoi(count)
+func fromExtensionsScale(scale *extensions.Scale) *rawScale {
+	result := &rawScale{
+		APIVersion: extensionsGroupVersion,
+		Kind:       "Scale",
+		ObjectMeta: scale.ObjectMeta,
+	}
+	result.Spec.Replicas = scale.Spec.Replicas
+	result.Status.Replicas = scale.Status.Replicas
+	return result
+}
+
+func toExtensionsScale(scale *rawScale) *extensions.Scale {
+	return &extensions.Scale{
+		ObjectMeta: scale.ObjectMeta,
+		Spec:       extensions.ScaleSpec{Replicas: scale.Spec.Replicas},
+	}
+}
+
+func toReplicaCounts(scale *rawScale) *ReplicaCounts {
+	return &ReplicaCounts{
+		DesiredReplicas: scale.Spec.Replicas,
+		ActualReplicas:  scale.Status.Replicas,
+	}
+}
+
+// scaleReplicas reads replicas of given resource from the resource itself, for clusters that don't
+// serve its scale subresource. If replicas is not nil, they are updated first.
+func scaleReplicas(client client.Interface, kind, namespace, name string, replicas *int32) (
+	*ReplicaCounts, error) {
+	switch kind {
+	case "Deployment":
+		deployment, err := client.ExtensionsV1beta1().Deployments(namespace).Get(name,
+			metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if replicas != nil {
+			deployment.Spec.Replicas = replicas
+			deployment, err = client.ExtensionsV1beta1().Deployments(namespace).Update(deployment)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return newReplicaCounts(deployment.Spec.Replicas, deployment.Status.Replicas), nil
+	case "ReplicaSet":
+		replicaSet, err := client.ExtensionsV1beta1().ReplicaSets(namespace).Get(name,
+			metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if replicas != nil {
+			replicaSet.Spec.Replicas = replicas
+			replicaSet, err = client.ExtensionsV1beta1().ReplicaSets(namespace).Update(replicaSet)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return newReplicaCounts(replicaSet.Spec.Replicas, replicaSet.Status.Replicas), nil
+	case "StatefulSet":
+		statefulSet, err := client.AppsV1beta1().StatefulSets(namespace).Get(name,
+			metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if replicas != nil {
+			statefulSet.Spec.Replicas = replicas
+			statefulSet, err = client.AppsV1beta1().StatefulSets(namespace).Update(statefulSet)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return newReplicaCounts(statefulSet.Spec.Replicas, statefulSet.Status.Replicas), nil
+	case "ReplicationController":
+		rc, err := client.CoreV1().ReplicationControllers(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if replicas != nil {
+			rc.Spec.Replicas = replicas
+			rc, err = client.CoreV1().ReplicationControllers(namespace).Update(rc)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return newReplicaCounts(rc.Spec.Replicas, rc.Status.Replicas), nil
+	default:
+		return nil, k8serrors.NewBadRequest(fmt.Sprintf("Scaling of %s is not supported", kind))
+	}
+}
+
+// newReplicaCounts returns replica counts of a resource, whose desired replicas default to 1.
+func newReplicaCounts(desired *int32, actual int32) *ReplicaCounts {
+	counts := &ReplicaCounts{DesiredReplicas: 1, ActualReplicas: actual}
+	if desired != nil {
+		counts.DesiredReplicas = *desired
+	}
+	return counts
+}
+
+// validateAutoscalerBounds checks that given number of replicas is within bounds of horizontal pod
+// autoscalers targeting the resource, since they would revert scaling outside of them right away.
+func validateAutoscalerBounds(client client.Interface, kind, namespace, name string,
+	replicas int32) error {
+	hpas, err := horizontalpodautoscaler.GetHorizontalPodAutoscalerListForResource(client, namespace,
+		kind, name)
 	if err != nil {
 		return err
 	}
-	*j.Spec.Parallelism = int32(c)
-	j, err = client.BatchV1().Jobs(namespace).Update(j)
-
-	rc.DesiredReplicas = *j.Spec.Parallelism
-	rc.ActualReplicas = *j.Spec.Parallelism
 
+	for _, hpa := range hpas.HorizontalPodAutoscalers {
+		// Minimum number of replicas defaults to 1.
+		minReplicas := int32(1)
+		if hpa.MinReplicas != nil {
+			minReplicas = *hpa.MinReplicas
+		}
+		if replicas < minReplicas || replicas > hpa.MaxReplicas {
+			return k8serrors.NewBadRequest(fmt.Sprintf(
+				"Number of replicas %d is out of bounds %d-%d of horizontal pod autoscaler %s",
+				replicas, minReplicas, hpa.MaxReplicas, hpa.ObjectMeta.Name))
+		}
+	}
 	return nil
 }
 
-// scaleStatefulSet is exclusively used for statefulsets
-func scaleStatefulSetResource(client client.Interface, namespace, name, count string, rc *ReplicaCounts) error {
-	ss, err := client.AppsV1beta1().StatefulSets(namespace).Get(name, metaV1.GetOptions{})
-	c, err := strconv.Atoi(count)
+// scaleJobResource is exclusively used for jobs as it does not increase/decrease pods but jobs parallelism attribute.
+func scaleJobResource(client client.Interface, namespace, name string, replicas int32) (
+	*ReplicaCounts, error) {
+	j, err := client.BatchV1().Jobs(namespace).Get(name, metaV1.GetOptions{})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	*ss.Spec.Replicas = int32(c)
-	ss, err = client.AppsV1beta1().StatefulSets(namespace).Update(ss)
+	j.Spec.Parallelism = &replicas
+	j, err = client.BatchV1().Jobs(namespace).Update(j)
+	if err != nil {
+		return nil, err
+	}
 
-	rc.DesiredReplicas = *ss.Spec.Replicas
-	rc.ActualReplicas = ss.Status.Replicas
+	parallelism := getJobParallelism(j.Spec.Parallelism)
+	return &ReplicaCounts{DesiredReplicas: parallelism, ActualReplicas: parallelism}, nil
+}
 
-	return nil
+// getJobParallelism returns parallelism of a job, which defaults to 1.
+func getJobParallelism(parallelism *int32) int32 {
+	if parallelism == nil {
+		return 1
+	}
+	return *parallelism
 }
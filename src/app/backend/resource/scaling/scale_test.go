@@ -0,0 +1,158 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaling
+
+import (
+	"reflect"
+	"testing"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	apps "k8s.io/client-go/pkg/apis/apps/v1beta1"
+	autoscaling "k8s.io/client-go/pkg/apis/autoscaling/v1"
+	batch "k8s.io/client-go/pkg/apis/batch/v1"
+)
+
+func TestGetScaleGroupVersion(t *testing.T) {
+	resources := []*metaV1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metaV1.APIResource{{Name: "replicationcontrollers"},
+				{Name: "replicationcontrollers/scale"}},
+		},
+		{
+			GroupVersion: "apps/v1beta1",
+			APIResources: []metaV1.APIResource{{Name: "deployments/scale"}, {Name: "statefulsets"}},
+		},
+		{
+			GroupVersion: "extensions/v1beta1",
+			APIResources: []metaV1.APIResource{{Name: "deployments/scale"}, {Name: "replicasets/scale"}},
+		},
+	}
+	cases := []struct {
+		kind         string
+		expected     string
+		expectedPath string
+		expectError  bool
+	}{
+		{"deployment", "apps/v1beta1", "/apis/apps/v1beta1/namespaces/foo/deployments/bar/scale", false},
+		{"ReplicaSet", "extensions/v1beta1",
+			"/apis/extensions/v1beta1/namespaces/foo/replicasets/bar/scale", false},
+		{"replicationcontroller", "v1", "/api/v1/namespaces/foo/replicationcontrollers/bar/scale",
+			false},
+		{"statefulset", "", "", false},
+		{"daemonset", "", "", true},
+	}
+	for _, c := range cases {
+		client := fake.NewSimpleClientset()
+		client.Discovery().(*fakediscovery.FakeDiscovery).Resources = resources
+
+		resource, err := getScalableResource(c.kind)
+		if (err != nil) != c.expectError {
+			t.Errorf("getScalableResource(%#v) returns error %v, expected error: %v", c.kind, err,
+				c.expectError)
+		}
+		actual := ""
+		if err == nil {
+			actual = getScaleGroupVersion(client, resource)
+		}
+		if actual != c.expected {
+			t.Errorf("getScaleGroupVersion(%#v) == %#v, expected %#v", c.kind, actual, c.expected)
+		}
+		if actual != "" {
+			if path := getScalePath(resource, actual, "foo", "bar"); path != c.expectedPath {
+				t.Errorf("getScalePath(%#v) == %#v, expected %#v", c.kind, path, c.expectedPath)
+			}
+		}
+	}
+}
+
+func TestScaleResourceWithoutScaleSubresource(t *testing.T) {
+	replicas := int32(1)
+	statefulSet := &apps.StatefulSet{
+		ObjectMeta: metaV1.ObjectMeta{Name: "bar", Namespace: "foo"},
+		Spec:       apps.StatefulSetSpec{Replicas: &replicas},
+		Status:     apps.StatefulSetStatus{Replicas: 1},
+	}
+	client := fake.NewSimpleClientset(statefulSet)
+	client.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metaV1.APIResourceList{
+		{GroupVersion: "apps/v1beta1", APIResources: []metaV1.APIResource{{Name: "statefulsets"}}},
+	}
+
+	actual, err := ScaleResource(client, "statefulset", "foo", "bar", 3)
+	if err != nil {
+		t.Fatalf("ScaleResource() returns error %v", err)
+	}
+	expected := &ReplicaCounts{DesiredReplicas: 3, ActualReplicas: 1}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("ScaleResource() == %#v, expected %#v", actual, expected)
+	}
+
+	updated, _ := client.AppsV1beta1().StatefulSets("foo").Get("bar", metaV1.GetOptions{})
+	if *updated.Spec.Replicas != 3 {
+		t.Errorf("ScaleResource() updates replicas to %d, expected 3", *updated.Spec.Replicas)
+	}
+}
+
+func TestValidateAutoscalerBounds(t *testing.T) {
+	minReplicas := int32(2)
+	hpa := &autoscaling.HorizontalPodAutoscaler{
+		ObjectMeta: metaV1.ObjectMeta{Name: "hpa", Namespace: "foo"},
+		Spec: autoscaling.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscaling.CrossVersionObjectReference{Kind: "Deployment", Name: "bar"},
+			MinReplicas:    &minReplicas,
+			MaxReplicas:    5,
+		},
+	}
+	cases := []struct {
+		kind, name  string
+		replicas    int32
+		expectError bool
+	}{
+		{"Deployment", "bar", 3, false},
+		{"Deployment", "bar", 1, true},
+		{"Deployment", "bar", 6, true},
+		{"Deployment", "baz", 6, false},
+		{"StatefulSet", "bar", 0, false},
+	}
+	for _, c := range cases {
+		client := fake.NewSimpleClientset(hpa)
+
+		err := validateAutoscalerBounds(client, c.kind, "foo", c.name, c.replicas)
+		if (err != nil) != c.expectError {
+			t.Errorf("validateAutoscalerBounds(%#v, %#v, %d) returns error %v, expected error: %v",
+				c.kind, c.name, c.replicas, err, c.expectError)
+		}
+	}
+}
+
+func TestScaleJobResource(t *testing.T) {
+	job := &batch.Job{ObjectMeta: metaV1.ObjectMeta{Name: "bar", Namespace: "foo"}}
+	client := fake.NewSimpleClientset(job)
+
+	actual, err := ScaleResource(client, "job", "foo", "bar", 3)
+	if err != nil {
+		t.Fatalf("ScaleResource() returns error %v", err)
+	}
+	expected := &ReplicaCounts{DesiredReplicas: 3, ActualReplicas: 3}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("ScaleResource() == %#v, expected %#v", actual, expected)
+	}
+
+	if _, err := ScaleResource(client, "job", "foo", "bar", -1); err == nil {
+		t.Errorf("ScaleResource() with negative replicas returns no error")
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"log"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/certmanager"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	client "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/pkg/api/v1"
@@ -37,6 +38,10 @@ type SecretDetail struct {
 
 	// Used to facilitate programmatic handling of secret data.
 	Type v1.SecretType `json:"type"`
+
+	// Name of the cert-manager certificate stored in the secret, empty when the secret was not
+	// created by cert-manager.
+	CertManagerCertificate string `json:"certManagerCertificate,omitempty"`
 }
 
 // GetSecretDetail returns returns detailed information about a secret
@@ -54,9 +59,10 @@ func GetSecretDetail(client *client.Clientset, namespace, name string) (*SecretD
 
 func getSecretDetail(rawSecret *v1.Secret) *SecretDetail {
 	return &SecretDetail{
-		ObjectMeta: api.NewObjectMeta(rawSecret.ObjectMeta),
-		TypeMeta:   api.NewTypeMeta(api.ResourceKindSecret),
-		Data:       rawSecret.Data,
-		Type:       rawSecret.Type,
+		ObjectMeta:             api.NewObjectMeta(rawSecret.ObjectMeta),
+		TypeMeta:               api.NewTypeMeta(api.ResourceKindSecret),
+		Data:                   rawSecret.Data,
+		Type:                   rawSecret.Type,
+		CertManagerCertificate: certmanager.GetCertificateName(rawSecret.ObjectMeta),
 	}
 }
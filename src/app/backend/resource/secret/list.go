@@ -16,11 +16,10 @@ package secret
 
 import (
 	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/certmanager"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/fields"
-	"k8s.io/apimachinery/pkg/labels"
 	client "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/pkg/api/v1"
 )
@@ -65,6 +64,10 @@ func (spec *ImagePullSecretSpec) GetData() map[string][]byte {
 type Secret struct {
 	api.ObjectMeta `json:"objectMeta"`
 	api.TypeMeta   `json:"typeMeta"`
+
+	// Name of the cert-manager certificate stored in the secret, empty when the secret was not
+	// created by cert-manager.
+	CertManagerCertificate string `json:"certManagerCertificate,omitempty"`
 }
 
 // SecretsList - response structure for a queried secrets list.
@@ -78,10 +81,7 @@ type SecretList struct {
 // GetSecretList - return all secrets in the given namespace.
 func GetSecretList(client *client.Clientset, namespace *common.NamespaceQuery,
 	dsQuery *dataselect.DataSelectQuery) (*SecretList, error) {
-	secretList, err := client.Secrets(namespace.ToRequestParam()).List(metaV1.ListOptions{
-		LabelSelector: labels.Everything().String(),
-		FieldSelector: fields.Everything().String(),
-	})
+	secretList, err := client.Secrets(namespace.ToRequestParam()).List(dsQuery.ListOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -120,8 +120,11 @@ func CreateSecret(client *client.Clientset, spec SecretSpec) (*Secret, error) {
 
 // NewSecret - creates a new instance of Secret struct based on K8s Secret.
 func NewSecret(secret *v1.Secret) *Secret {
-	return &Secret{api.NewObjectMeta(secret.ObjectMeta),
-		api.NewTypeMeta(api.ResourceKindSecret)}
+	return &Secret{
+		ObjectMeta:             api.NewObjectMeta(secret.ObjectMeta),
+		TypeMeta:               api.NewTypeMeta(api.ResourceKindSecret),
+		CertManagerCertificate: certmanager.GetCertificateName(secret.ObjectMeta),
+	}
 }
 
 // NewSecret - creates a new instance of SecretList struct based on K8s Secrets array.
@@ -133,7 +136,7 @@ func NewSecretList(secrets []v1.Secret, dsQuery *dataselect.DataSelectQuery) *Se
 
 	secretCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(secrets), dsQuery)
 	secrets = fromCells(secretCells)
-	newSecretList.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+	newSecretList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, newSecretList.ListMeta.TotalItems)
 
 	for _, secret := range secrets {
 		newSecretList.Secrets = append(newSecretList.Secrets, *NewSecret(&secret))
@@ -71,7 +71,7 @@ func TestNewSecretListCreation(t *testing.T) {
 						TypeMeta: api.NewTypeMeta(api.ResourceKindSecret),
 					},
 				},
-				ListMeta: api.ListMeta{2},
+				ListMeta: api.ListMeta{TotalItems: 2, TotalBeforeFilter: 2},
 			},
 			common.NewNamespaceQuery([]string{"foo"}),
 		},
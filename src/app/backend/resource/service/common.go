@@ -57,7 +57,7 @@ func CreateServiceList(services []v1.Service, dsQuery *dataselect.DataSelectQuer
 
 	serviceCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(services), dsQuery)
 	services = fromCells(serviceCells)
-	serviceList.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+	serviceList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, serviceList.ListMeta.TotalItems)
 
 	for _, service := range services {
 		serviceList.Services = append(serviceList.Services, ToService(&service))
@@ -78,6 +78,8 @@ func (self ServiceCell) GetProperty(name dataselect.PropertyName) dataselect.Com
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.UIDProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.UID)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
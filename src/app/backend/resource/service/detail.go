@@ -18,7 +18,7 @@ import (
 	"log"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
@@ -55,10 +55,13 @@ type ServiceDetail struct {
 
 	// PodList represents list of pods targeted by same label selector as this service.
 	PodList pod.PodList `json:"podList"`
+
+	// Traits of the spec that commonly cause connection issues.
+	Warnings []ServiceWarning `json:"warnings"`
 }
 
 // GetServiceDetail gets service details.
-func GetServiceDetail(client k8sClient.Interface, heapsterClient heapster.HeapsterClient,
+func GetServiceDetail(client k8sClient.Interface, heapsterClient metricapi.MetricClient,
 	namespace, name string, dsQuery *dataselect.DataSelectQuery) (*ServiceDetail, error) {
 
 	log.Printf("Getting details of %s service in %s namespace", name, namespace)
@@ -76,12 +79,13 @@ func GetServiceDetail(client k8sClient.Interface, heapsterClient heapster.Heapst
 
 	service := ToServiceDetail(serviceData)
 	service.PodList = *podList
+	service.Warnings = getServiceWarnings(serviceData, podList.ListMeta.TotalItems)
 
 	return &service, nil
 }
 
 // GetServicePods gets list of pods targeted by given label selector in given namespace.
-func GetServicePods(client k8sClient.Interface, heapsterClient heapster.HeapsterClient, namespace,
+func GetServicePods(client k8sClient.Interface, heapsterClient metricapi.MetricClient, namespace,
 	name string, dsQuery *dataselect.DataSelectQuery) (*pod.PodList, error) {
 
 	service, err := client.CoreV1().Services(namespace).Get(name, metaV1.GetOptions{})
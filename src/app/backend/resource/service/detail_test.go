@@ -19,7 +19,7 @@ import (
 	"testing"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
@@ -39,7 +39,7 @@ func (FakeRequest) DoRaw() ([]byte, error) {
 	return nil, nil
 }
 
-func (c FakeHeapsterClient) Get(path string) heapster.RequestInterface {
+func (c FakeHeapsterClient) Get(path string) metricapi.RequestInterface {
 	return FakeRequest{}
 }
 
@@ -68,6 +68,8 @@ func TestGetServiceDetail(t *testing.T) {
 					Pods:              []pod.Pod{},
 					CumulativeMetrics: make([]metric.Metric, 0),
 				},
+				Warnings: []ServiceWarning{{Reason: NoSelectorReason, Message: "Service without " +
+					"selector forwards traffic only to endpoints managed outside of Kubernetes."}},
 			},
 		},
 		{
@@ -94,6 +96,8 @@ func TestGetServiceDetail(t *testing.T) {
 					Pods:              []pod.Pod{},
 					CumulativeMetrics: make([]metric.Metric, 0),
 				},
+				Warnings: []ServiceWarning{{Reason: NoMatchingPodsReason, Message: "Selector " +
+					"matches no pods, so the service has no endpoints and its connections are refused."}},
 			},
 		},
 	}
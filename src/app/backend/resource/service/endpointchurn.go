@@ -0,0 +1,174 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/endpointhistory"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+// AddressChurn is churn of a single endpoint address of a service.
+type AddressChurn struct {
+	IP string `json:"ip"`
+
+	// Name of the object the address belongs to, usually a pod. Empty if not known.
+	TargetName string `json:"targetName,omitempty"`
+
+	// Number of readiness transitions within the window.
+	Transitions int `json:"transitions"`
+
+	// Whether the address is currently ready. False also for addresses no longer in endpoints.
+	Ready bool `json:"ready"`
+
+	LastTransition metaV1.Time `json:"lastTransition"`
+}
+
+// EndpointChurn is churn of endpoint addresses of a service within a time window.
+type EndpointChurn struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	// Current numbers of ready and not ready addresses.
+	ReadyAddresses    int `json:"readyAddresses"`
+	NotReadyAddresses int `json:"notReadyAddresses"`
+
+	// Number of readiness transitions of all addresses within the window.
+	Transitions int `json:"transitions"`
+
+	// Average number of transitions per minute within the window.
+	TransitionsPerMinute float64 `json:"transitionsPerMinute"`
+
+	// Addresses with transitions within the window, the most flapping first.
+	Addresses []AddressChurn `json:"addresses"`
+}
+
+// EndpointChurnList contains endpoint churn of services, the most flapping first.
+type EndpointChurnList struct {
+	ListMeta api.ListMeta `json:"listMeta"`
+
+	// Start of the window.
+	Since metaV1.Time `json:"since"`
+
+	Services []EndpointChurn `json:"services"`
+}
+
+// GetEndpointChurnList returns churn of endpoints of services in given namespaces within given
+// window, joining current endpoints with transitions recorded by given recorder.
+func GetEndpointChurnList(client client.Interface, recorder endpointhistory.Recorder,
+	nsQuery *common.NamespaceQuery, window time.Duration) (*EndpointChurnList, error) {
+	log.Print("Getting endpoint churn of services")
+
+	// Also limits the result to endpoints the user is allowed to see, as transitions are
+	// recorded with the dashboard's own credentials.
+	channels := &common.ResourceChannels{
+		EndpointsList: common.GetEndpointsListChannel(client, nsQuery, 1),
+	}
+	endpoints := <-channels.EndpointsList.List
+	if err := <-channels.EndpointsList.Error; err != nil {
+		return nil, err
+	}
+
+	return getEndpointChurnList(endpoints.Items, recorder, window, time.Now()), nil
+}
+
+func getEndpointChurnList(endpoints []v1.Endpoints, recorder endpointhistory.Recorder,
+	window time.Duration, now time.Time) *EndpointChurnList {
+	since := now.Add(-window)
+	result := &EndpointChurnList{
+		ListMeta: api.ListMeta{TotalItems: len(endpoints)},
+		Since:    metaV1.NewTime(since),
+		Services: make([]EndpointChurn, 0),
+	}
+
+	for _, item := range endpoints {
+		churn := EndpointChurn{
+			ObjectMeta: api.NewObjectMeta(item.ObjectMeta),
+			TypeMeta:   api.NewTypeMeta(api.ResourceKindService),
+			Addresses:  make([]AddressChurn, 0),
+		}
+
+		ready := make(map[string]bool)
+		for _, subset := range item.Subsets {
+			churn.ReadyAddresses += len(subset.Addresses)
+			churn.NotReadyAddresses += len(subset.NotReadyAddresses)
+			for _, address := range subset.Addresses {
+				ready[address.IP] = true
+			}
+		}
+
+		addresses := make(map[string]*AddressChurn)
+		for _, transition := range recorder.GetTransitions(item.Namespace, item.Name, since) {
+			address, ok := addresses[transition.IP]
+			if !ok {
+				address = &AddressChurn{IP: transition.IP, Ready: ready[transition.IP]}
+				addresses[transition.IP] = address
+			}
+			if transition.TargetName != "" {
+				address.TargetName = transition.TargetName
+			}
+			address.Transitions++
+			address.LastTransition = transition.Timestamp
+			churn.Transitions++
+		}
+		for _, address := range addresses {
+			churn.Addresses = append(churn.Addresses, *address)
+		}
+		sort.Sort(addressesByTransitions(churn.Addresses))
+
+		if window > 0 {
+			churn.TransitionsPerMinute = float64(churn.Transitions) / window.Minutes()
+		}
+		result.Services = append(result.Services, churn)
+	}
+	sort.Sort(servicesByTransitions(result.Services))
+
+	return result
+}
+
+// addressesByTransitions sorts addresses by number of transitions, descending, then by IP.
+type addressesByTransitions []AddressChurn
+
+func (a addressesByTransitions) Len() int      { return len(a) }
+func (a addressesByTransitions) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a addressesByTransitions) Less(i, j int) bool {
+	if a[i].Transitions != a[j].Transitions {
+		return a[i].Transitions > a[j].Transitions
+	}
+	return a[i].IP < a[j].IP
+}
+
+// servicesByTransitions sorts services by number of transitions, descending, then by namespace
+// and name.
+type servicesByTransitions []EndpointChurn
+
+func (s servicesByTransitions) Len() int      { return len(s) }
+func (s servicesByTransitions) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s servicesByTransitions) Less(i, j int) bool {
+	if s[i].Transitions != s[j].Transitions {
+		return s[i].Transitions > s[j].Transitions
+	}
+	if s[i].ObjectMeta.Namespace != s[j].ObjectMeta.Namespace {
+		return s[i].ObjectMeta.Namespace < s[j].ObjectMeta.Namespace
+	}
+	return s[i].ObjectMeta.Name < s[j].ObjectMeta.Name
+}
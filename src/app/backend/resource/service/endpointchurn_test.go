@@ -0,0 +1,101 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/endpointhistory"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+type fakeEndpointRecorder map[string][]endpointhistory.Transition
+
+func (r fakeEndpointRecorder) Start(client kubernetes.Interface) {}
+
+func (r fakeEndpointRecorder) Window() time.Duration { return time.Hour }
+
+func (r fakeEndpointRecorder) GetTransitions(namespace, name string,
+	since time.Time) []endpointhistory.Transition {
+	result := make([]endpointhistory.Transition, 0)
+	for _, transition := range r[namespace+"/"+name] {
+		if !transition.Timestamp.Time.Before(since) {
+			result = append(result, transition)
+		}
+	}
+	return result
+}
+
+func TestGetEndpointChurnList(t *testing.T) {
+	now := time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+	at := func(minutesAgo int) metaV1.Time {
+		return metaV1.NewTime(now.Add(-time.Duration(minutesAgo) * time.Minute))
+	}
+	recorder := fakeEndpointRecorder{
+		"default/web": {
+			{Timestamp: at(30), IP: "10.0.0.1", TargetName: "web-1", Ready: false},
+			{Timestamp: at(8), IP: "10.0.0.1", TargetName: "web-1", Ready: true},
+			{Timestamp: at(6), IP: "10.0.0.2", TargetName: "web-2", Ready: true},
+			{Timestamp: at(4), IP: "10.0.0.1", TargetName: "web-1", Ready: false},
+			{Timestamp: at(2), IP: "10.0.0.1", TargetName: "web-1", Ready: true},
+		},
+	}
+	endpoints := []v1.Endpoints{
+		{ObjectMeta: metaV1.ObjectMeta{Name: "api", Namespace: "default"}},
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default"},
+			Subsets: []v1.EndpointSubset{{
+				Addresses:         []v1.EndpointAddress{{IP: "10.0.0.1"}},
+				NotReadyAddresses: []v1.EndpointAddress{{IP: "10.0.0.3"}},
+			}},
+		},
+	}
+
+	expected := &EndpointChurnList{
+		ListMeta: api.ListMeta{TotalItems: 2},
+		Since:    at(10),
+		Services: []EndpointChurn{
+			{
+				ObjectMeta:           api.ObjectMeta{Name: "web", Namespace: "default"},
+				TypeMeta:             api.TypeMeta{Kind: api.ResourceKindService},
+				ReadyAddresses:       1,
+				NotReadyAddresses:    1,
+				Transitions:          4,
+				TransitionsPerMinute: 0.4,
+				Addresses: []AddressChurn{
+					{IP: "10.0.0.1", TargetName: "web-1", Transitions: 3, Ready: true,
+						LastTransition: at(2)},
+					{IP: "10.0.0.2", TargetName: "web-2", Transitions: 1, Ready: false,
+						LastTransition: at(6)},
+				},
+			},
+			{
+				ObjectMeta: api.ObjectMeta{Name: "api", Namespace: "default"},
+				TypeMeta:   api.TypeMeta{Kind: api.ResourceKindService},
+				Addresses:  []AddressChurn{},
+			},
+		},
+	}
+
+	actual := getEndpointChurnList(endpoints, recorder, 10*time.Minute, now)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getEndpointChurnList() == \ngot: %#v, \nexpected %#v", actual, expected)
+	}
+}
@@ -61,8 +61,9 @@ func GetServiceList(client client.Interface, nsQuery *common.NamespaceQuery,
 	dsQuery *dataselect.DataSelectQuery) (*ServiceList, error) {
 	log.Print("Getting list of all services in the cluster")
 
+	options := dsQuery.ListOptions()
 	channels := &common.ResourceChannels{
-		ServiceList: common.GetServiceListChannel(client, nsQuery, 1),
+		ServiceList: common.GetServiceListChannelWithOptions(client, nsQuery, options, 1),
 	}
 
 	return GetServiceListFromChannels(channels, dsQuery)
@@ -24,6 +24,7 @@ import (
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/pkg/api/v1"
+	core "k8s.io/client-go/testing"
 )
 
 func TestGetServiceList(t *testing.T) {
@@ -42,7 +43,7 @@ func TestGetServiceList(t *testing.T) {
 				}},
 			expectedActions: []string{"list"},
 			expected: &ServiceList{
-				ListMeta: api.ListMeta{TotalItems: 1},
+				ListMeta: api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
 				Services: []Service{
 					{
 						ObjectMeta: api.ObjectMeta{
@@ -82,3 +83,34 @@ func TestGetServiceList(t *testing.T) {
 		}
 	}
 }
+
+func TestGetServiceListWithLabelSelector(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(&v1.ServiceList{
+		Items: []v1.Service{
+			{ObjectMeta: metaV1.ObjectMeta{Name: "svc-1", Namespace: "ns-1",
+				Labels: map[string]string{"app": "foo"}}},
+			{ObjectMeta: metaV1.ObjectMeta{Name: "svc-2", Namespace: "ns-1",
+				Labels: map[string]string{"app": "bar"}}},
+		}})
+	dsQuery := dataselect.NewDataSelectQuery(dataselect.NoPagination, dataselect.NoSort,
+		dataselect.NoFilter, dataselect.NoMetrics)
+	dsQuery.LabelSelector = "app=foo"
+
+	actual, err := GetServiceList(fakeClient, common.NewNamespaceQuery(nil), dsQuery)
+	if err != nil {
+		t.Fatalf("GetServiceList() returns error: %v", err)
+	}
+
+	actions := fakeClient.Actions()
+	if len(actions) != 1 {
+		t.Fatalf("Unexpected actions: %v, expected 1 action", actions)
+	}
+	selector := actions[0].(core.ListAction).GetListRestrictions().Labels.String()
+	if selector != dsQuery.LabelSelector {
+		t.Errorf("GetServiceList() lists services with label selector %#v, expected %#v", selector,
+			dsQuery.LabelSelector)
+	}
+	if len(actual.Services) != 1 || actual.Services[0].ObjectMeta.Name != "svc-1" {
+		t.Errorf("GetServiceList() == %#v, expected only svc-1", actual.Services)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sClient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+// LoadBalancerRetryAnnotationKey is bumped to make the service controller reconcile the load
+// balancer of the service again.
+const LoadBalancerRetryAnnotationKey = "dashboard.alpha.kubernetes.io/load-balancer-retried-at"
+
+// LoadBalancerPendingTimeout is how long a load balancer can be provisioned before it's considered
+// stuck.
+const LoadBalancerPendingTimeout = 5 * time.Minute
+
+// loadBalancerEventReasons are reasons of events recorded by the service controller while it
+// provisions load balancers.
+var loadBalancerEventReasons = map[string]bool{
+	"CreatingLoadBalancer":       true,
+	"CreatedLoadBalancer":        true,
+	"CreatingLoadBalancerFailed": true,
+	"EnsuringLoadBalancer":       true,
+	"EnsuredLoadBalancer":        true,
+	"UpdatedLoadBalancer":        true,
+	"UpdateLoadBalancerFailed":   true,
+	"LoadBalancerUpdateFailed":   true,
+	"DeletingLoadBalancer":       true,
+	"DeletedLoadBalancer":        true,
+	"DeletingLoadBalancerFailed": true,
+	"SyncLoadBalancerFailed":     true,
+}
+
+// LoadBalancerStatus is a provisioning status of the cloud load balancer of a service.
+type LoadBalancerStatus struct {
+	// IP addresses and host names assigned to the load balancer by the cloud provider.
+	Ingress []v1.LoadBalancerIngress `json:"ingress"`
+
+	// True if no address was assigned yet.
+	Pending bool `json:"pending"`
+
+	// True if the load balancer is pending for longer than LoadBalancerPendingTimeout or its
+	// provisioning failed.
+	Stuck bool `json:"stuck"`
+
+	// Why the load balancer is considered stuck.
+	StuckMessage string `json:"stuckMessage,omitempty"`
+
+	// Events of provisioning the load balancer, from the oldest.
+	Events []common.Event `json:"events"`
+
+	// Warning events among events, which are responsible for the load balancer being stuck.
+	ResponsibleEvents []common.Event `json:"responsibleEvents"`
+
+	// When the last retry was requested, empty if never.
+	RetriedAt string `json:"retriedAt,omitempty"`
+}
+
+// GetLoadBalancerStatus returns provisioning status of the load balancer of given service.
+func GetLoadBalancerStatus(client k8sClient.Interface, namespace, name string) (
+	*LoadBalancerStatus, error) {
+	log.Printf("Getting load balancer status of %s service in %s namespace", name, namespace)
+
+	service, err := client.CoreV1().Services(namespace).Get(name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if service.Spec.Type != v1.ServiceTypeLoadBalancer {
+		return nil, k8serrors.NewBadRequest(fmt.Sprintf("Service %s is not of %s type", name,
+			v1.ServiceTypeLoadBalancer))
+	}
+
+	events, err := event.GetEvents(client, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return getLoadBalancerStatus(service, events, time.Now()), nil
+}
+
+// RetryLoadBalancer bumps the retry annotation of given service, which makes the service controller
+// reconcile its load balancer again.
+func RetryLoadBalancer(client k8sClient.Interface, namespace, name string) error {
+	log.Printf("Retrying load balancer of %s service in %s namespace", name, namespace)
+
+	service, err := client.CoreV1().Services(namespace).Get(name, metaV1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if service.Spec.Type != v1.ServiceTypeLoadBalancer {
+		return k8serrors.NewBadRequest(fmt.Sprintf("Service %s is not of %s type", name,
+			v1.ServiceTypeLoadBalancer))
+	}
+
+	if service.Annotations == nil {
+		service.Annotations = make(map[string]string)
+	}
+	service.Annotations[LoadBalancerRetryAnnotationKey] = time.Now().Format(time.RFC3339)
+	_, err = client.CoreV1().Services(namespace).Update(service)
+	return err
+}
+
+func getLoadBalancerStatus(service *v1.Service, events []v1.Event, now time.Time) *LoadBalancerStatus {
+	status := &LoadBalancerStatus{
+		Ingress:           make([]v1.LoadBalancerIngress, 0),
+		Events:            make([]common.Event, 0),
+		ResponsibleEvents: make([]common.Event, 0),
+		RetriedAt:         service.Annotations[LoadBalancerRetryAnnotationKey],
+	}
+	status.Ingress = append(status.Ingress, service.Status.LoadBalancer.Ingress...)
+	status.Pending = len(status.Ingress) == 0
+
+	lbEvents := make([]v1.Event, 0)
+	for _, e := range events {
+		if e.InvolvedObject.Kind == "Service" && loadBalancerEventReasons[e.Reason] {
+			lbEvents = append(lbEvents, e)
+		}
+	}
+	sort.Sort(eventsByLastSeen(lbEvents))
+
+	for _, e := range lbEvents {
+		status.Events = append(status.Events, event.ToEvent(e))
+		if e.Type == v1.EventTypeWarning {
+			status.ResponsibleEvents = append(status.ResponsibleEvents, event.ToEvent(e))
+		}
+	}
+
+	if status.Pending {
+		pendingFor := now.Sub(service.CreationTimestamp.Time)
+		if len(lbEvents) > 0 && lbEvents[len(lbEvents)-1].Type == v1.EventTypeWarning {
+			status.Stuck = true
+			status.StuckMessage = "Provisioning failed: " + lbEvents[len(lbEvents)-1].Message
+		} else if pendingFor > LoadBalancerPendingTimeout {
+			status.Stuck = true
+			status.StuckMessage = fmt.Sprintf("No address was assigned for %s",
+				pendingFor-pendingFor%time.Second)
+		}
+	}
+
+	if !status.Stuck {
+		status.ResponsibleEvents = make([]common.Event, 0)
+	}
+	return status
+}
+
+type eventsByLastSeen []v1.Event
+
+func (self eventsByLastSeen) Len() int      { return len(self) }
+func (self eventsByLastSeen) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+func (self eventsByLastSeen) Less(i, j int) bool {
+	return self[i].LastTimestamp.Before(self[j].LastTimestamp)
+}
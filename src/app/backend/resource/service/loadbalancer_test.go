@@ -0,0 +1,112 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+func newLoadBalancerEvent(reason, eventType string, lastSeen time.Time) v1.Event {
+	return v1.Event{
+		ObjectMeta:     metaV1.ObjectMeta{Name: reason, Namespace: "default"},
+		InvolvedObject: v1.ObjectReference{Kind: "Service", Name: "web"},
+		Reason:         reason,
+		Type:           eventType,
+		Message:        reason + " message",
+		LastTimestamp:  metaV1.NewTime(lastSeen),
+	}
+}
+
+func TestGetLoadBalancerStatus(t *testing.T) {
+	now := time.Date(2017, 5, 5, 12, 0, 0, 0, time.UTC)
+	newService := func(age time.Duration, ingress ...v1.LoadBalancerIngress) *v1.Service {
+		return &v1.Service{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default",
+				CreationTimestamp: metaV1.NewTime(now.Add(-age))},
+			Spec:   v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+			Status: v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{Ingress: ingress}},
+		}
+	}
+	creating := newLoadBalancerEvent("CreatingLoadBalancer", v1.EventTypeNormal, now.Add(-3*time.Minute))
+	failed := newLoadBalancerEvent("CreatingLoadBalancerFailed", v1.EventTypeWarning,
+		now.Add(-time.Minute))
+	unrelated := newLoadBalancerEvent("FailedScheduling", v1.EventTypeWarning, now)
+
+	cases := []struct {
+		name              string
+		service           *v1.Service
+		events            []v1.Event
+		pending, stuck    bool
+		eventCount        int
+		responsibleEvents int
+	}{
+		{"provisioned", newService(time.Hour, v1.LoadBalancerIngress{IP: "10.0.0.1"}),
+			[]v1.Event{failed, creating}, false, false, 2, 0},
+		{"provisioning", newService(time.Minute), []v1.Event{creating, unrelated},
+			true, false, 1, 0},
+		{"failed", newService(time.Minute), []v1.Event{failed, creating, unrelated},
+			true, true, 2, 1},
+		{"timed out", newService(time.Hour), []v1.Event{creating}, true, true, 1, 0},
+	}
+
+	for _, c := range cases {
+		actual := getLoadBalancerStatus(c.service, c.events, now)
+		if actual.Pending != c.pending || actual.Stuck != c.stuck {
+			t.Errorf("getLoadBalancerStatus(%s) == \ngot pending %t, stuck %t, \nexpected pending %t, "+
+				"stuck %t", c.name, actual.Pending, actual.Stuck, c.pending, c.stuck)
+		}
+		if len(actual.Events) != c.eventCount || len(actual.ResponsibleEvents) != c.responsibleEvents {
+			t.Errorf("getLoadBalancerStatus(%s) == \ngot %d events, %d responsible, \nexpected %d, %d",
+				c.name, len(actual.Events), len(actual.ResponsibleEvents), c.eventCount,
+				c.responsibleEvents)
+		}
+		if c.stuck && actual.StuckMessage == "" {
+			t.Errorf("getLoadBalancerStatus(%s) == \ngot empty stuck message", c.name)
+		}
+		if len(actual.Events) > 1 && actual.Events[0].Reason != creating.Reason {
+			t.Errorf("getLoadBalancerStatus(%s) == \ngot first event %s, \nexpected %s", c.name,
+				actual.Events[0].Reason, creating.Reason)
+		}
+	}
+}
+
+func TestRetryLoadBalancer(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		&v1.Service{ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer}},
+		&v1.Service{ObjectMeta: metaV1.ObjectMeta{Name: "internal", Namespace: "default"},
+			Spec: v1.ServiceSpec{Type: v1.ServiceTypeClusterIP}},
+	)
+
+	if err := RetryLoadBalancer(fakeClient, "default", "web"); err != nil {
+		t.Fatalf("RetryLoadBalancer(web) == \ngot err %#v", err)
+	}
+	service, _ := fakeClient.CoreV1().Services("default").Get("web", metaV1.GetOptions{})
+	if service.Annotations[LoadBalancerRetryAnnotationKey] == "" {
+		t.Errorf("RetryLoadBalancer(web) == \ngot annotations %#v, \nexpected %s to be set",
+			service.Annotations, LoadBalancerRetryAnnotationKey)
+	}
+
+	err := RetryLoadBalancer(fakeClient, "default", "internal")
+	if !k8serrors.IsBadRequest(err) {
+		t.Errorf("RetryLoadBalancer(internal) == \ngot err %#v, \nexpected bad request", err)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+// Reasons of service warnings.
+const (
+	// External traffic is sent only to pods on the node it arrives at.
+	LocalExternalTrafficReason = "LocalExternalTraffic"
+
+	// External traffic policy is set on a service that isn't reachable from outside the cluster.
+	IgnoredExternalTrafficPolicyReason = "IgnoredExternalTrafficPolicy"
+
+	// Clients stick to a single pod.
+	ClientIPSessionAffinityReason = "ClientIPSessionAffinity"
+
+	// Session affinity is set on a headless service, which isn't proxied.
+	IgnoredSessionAffinityReason = "IgnoredSessionAffinity"
+
+	// Endpoints of the service aren't managed by Kubernetes.
+	NoSelectorReason = "NoSelector"
+
+	// Selector of the service matches no pods.
+	NoMatchingPodsReason = "NoMatchingPods"
+
+	// Selector is set on an external name service, which has no endpoints.
+	IgnoredSelectorReason = "IgnoredSelector"
+)
+
+// Annotation that set external traffic policy before it became a field of service spec.
+const externalTrafficAnnotationKey = "service.beta.kubernetes.io/external-traffic"
+
+// ServiceWarning is a trait of service spec that commonly causes connection issues, e.g. dropped
+// or unbalanced traffic.
+type ServiceWarning struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// getServiceWarnings returns warnings about spec of given service, which targets given number of
+// pods.
+func getServiceWarnings(service *v1.Service, podCount int) []ServiceWarning {
+	warnings := make([]ServiceWarning, 0)
+	add := func(reason, message string) {
+		warnings = append(warnings, ServiceWarning{Reason: reason, Message: message})
+	}
+
+	headless := service.Spec.ClusterIP == v1.ClusterIPNone
+	externalName := service.Spec.Type == v1.ServiceTypeExternalName
+	exposed := service.Spec.Type == v1.ServiceTypeNodePort ||
+		service.Spec.Type == v1.ServiceTypeLoadBalancer
+
+	if isExternalTrafficLocal(service) {
+		if exposed {
+			add(LocalExternalTrafficReason, "Traffic from outside the cluster is sent only to pods "+
+				"on the node it arrives at. Nodes without ready pods of the service drop it and load "+
+				"is balanced between nodes, not pods.")
+		} else {
+			add(IgnoredExternalTrafficPolicyReason, "External traffic policy has no effect on "+
+				"services that are not of NodePort or LoadBalancer type.")
+		}
+	}
+
+	if service.Spec.SessionAffinity == v1.ServiceAffinityClientIP {
+		if headless || externalName {
+			add(IgnoredSessionAffinityReason, "Session affinity has no effect on services "+
+				"without cluster IP, as they are not proxied.")
+		} else {
+			add(ClientIPSessionAffinityReason, "Connections from a client IP are sent to the same "+
+				"pod until it is idle for 3 hours. Load is not rebalanced after scaling and clients "+
+				"behind the same NAT share a single pod.")
+		}
+	}
+
+	if externalName {
+		if len(service.Spec.Selector) > 0 {
+			add(IgnoredSelectorReason, "Selector has no effect on services of ExternalName type, "+
+				"which resolve to the external name.")
+		}
+		return warnings
+	}
+
+	if len(service.Spec.Selector) == 0 {
+		if headless {
+			add(NoSelectorReason, "Headless service without selector resolves only to endpoints "+
+				"managed outside of Kubernetes.")
+		} else {
+			add(NoSelectorReason, "Service without selector forwards traffic only to endpoints "+
+				"managed outside of Kubernetes.")
+		}
+	} else if podCount == 0 {
+		add(NoMatchingPodsReason, "Selector matches no pods, so the service has no endpoints and "+
+			"its connections are refused.")
+	}
+
+	return warnings
+}
+
+// isExternalTrafficLocal checks if external traffic of given service is sent only to local pods,
+// either by spec or by the older annotation.
+func isExternalTrafficLocal(service *v1.Service) bool {
+	return service.Spec.ExternalTrafficPolicy == v1.ServiceExternalTrafficPolicyTypeLocal ||
+		service.Annotations[externalTrafficAnnotationKey] == "OnlyLocal"
+}
@@ -0,0 +1,93 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+func TestGetServiceWarnings(t *testing.T) {
+	selector := map[string]string{"app": "foo"}
+	cases := []struct {
+		service  *v1.Service
+		podCount int
+		expected []string
+	}{
+		{&v1.Service{Spec: v1.ServiceSpec{Selector: selector}}, 2, []string{}},
+		{&v1.Service{Spec: v1.ServiceSpec{Selector: selector}}, 0, []string{NoMatchingPodsReason}},
+		{&v1.Service{}, 0, []string{NoSelectorReason}},
+		{&v1.Service{Spec: v1.ServiceSpec{ClusterIP: v1.ClusterIPNone}}, 0, []string{NoSelectorReason}},
+		{
+			&v1.Service{Spec: v1.ServiceSpec{Selector: selector, Type: v1.ServiceTypeLoadBalancer,
+				ExternalTrafficPolicy: v1.ServiceExternalTrafficPolicyTypeLocal}},
+			2,
+			[]string{LocalExternalTrafficReason},
+		},
+		{
+			&v1.Service{
+				ObjectMeta: metaV1.ObjectMeta{
+					Annotations: map[string]string{externalTrafficAnnotationKey: "OnlyLocal"},
+				},
+				Spec: v1.ServiceSpec{Selector: selector, Type: v1.ServiceTypeNodePort},
+			},
+			2,
+			[]string{LocalExternalTrafficReason},
+		},
+		{
+			&v1.Service{Spec: v1.ServiceSpec{Selector: selector,
+				ExternalTrafficPolicy: v1.ServiceExternalTrafficPolicyTypeLocal}},
+			2,
+			[]string{IgnoredExternalTrafficPolicyReason},
+		},
+		{
+			&v1.Service{Spec: v1.ServiceSpec{Selector: selector,
+				SessionAffinity: v1.ServiceAffinityClientIP}},
+			2,
+			[]string{ClientIPSessionAffinityReason},
+		},
+		{
+			&v1.Service{Spec: v1.ServiceSpec{Selector: selector, ClusterIP: v1.ClusterIPNone,
+				SessionAffinity: v1.ServiceAffinityClientIP}},
+			2,
+			[]string{IgnoredSessionAffinityReason},
+		},
+		{
+			&v1.Service{Spec: v1.ServiceSpec{Selector: selector, Type: v1.ServiceTypeExternalName,
+				ExternalName: "example.com"}},
+			0,
+			[]string{IgnoredSelectorReason},
+		},
+		{
+			&v1.Service{Spec: v1.ServiceSpec{Type: v1.ServiceTypeExternalName,
+				ExternalName: "example.com"}},
+			0,
+			[]string{},
+		},
+	}
+	for _, c := range cases {
+		actual := make([]string, 0)
+		for _, warning := range getServiceWarnings(c.service, c.podCount) {
+			actual = append(actual, warning.Reason)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("getServiceWarnings(%#v, %d) == \ngot: %#v, \nexpected %#v", c.service.Spec,
+				c.podCount, actual, c.expected)
+		}
+	}
+}
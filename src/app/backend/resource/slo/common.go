@@ -0,0 +1,159 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slo
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+)
+
+// SLOAnnotationKey is the workload annotation that stores availability SLO definition.
+const SLOAnnotationKey = "dashboard.alpha.kubernetes.io/slo"
+
+// SLOSpec is a definition of a simple availability SLO of a workload.
+type SLOSpec struct {
+	// Target ratio of ready replicas to desired replicas, e.g. 0.99.
+	Target float64 `json:"target"`
+
+	// Time window the SLO is evaluated over, e.g. 24h.
+	Window string `json:"window"`
+}
+
+// validate checks if the SLO definition is correct and returns its window duration.
+func (spec SLOSpec) validate() (time.Duration, error) {
+	if spec.Target <= 0 || spec.Target >= 1 {
+		return 0, k8serrors.NewBadRequest(fmt.Sprintf("SLO target has to be between 0 and 1, got %v",
+			spec.Target))
+	}
+
+	window, err := time.ParseDuration(spec.Window)
+	if err != nil {
+		return 0, k8serrors.NewBadRequest(fmt.Sprintf("Invalid SLO window %q: %s", spec.Window, err))
+	}
+	if window <= 0 {
+		return 0, k8serrors.NewBadRequest(fmt.Sprintf("SLO window has to be positive, got %s", spec.Window))
+	}
+
+	return window, nil
+}
+
+// getSLOSpec returns SLO definition stored in given annotations or nil if there is none.
+func getSLOSpec(annotations map[string]string) (*SLOSpec, error) {
+	raw, ok := annotations[SLOAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	spec := &SLOSpec{}
+	if err := json.Unmarshal([]byte(raw), spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// workload is a common view of workloads SLOs can be defined for.
+type workload struct {
+	kind       api.ResourceKind
+	objectMeta metaV1.ObjectMeta
+	selector   *metaV1.LabelSelector
+	desired    int32
+	ready      int32
+}
+
+// getWorkload returns workload of given kind, namespace and name.
+func getWorkload(client client.Interface, kind, namespace, name string) (*workload, error) {
+	switch api.ResourceKind(kind) {
+	case api.ResourceKindDeployment:
+		deployment, err := client.ExtensionsV1beta1().Deployments(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &workload{api.ResourceKindDeployment, deployment.ObjectMeta, deployment.Spec.Selector,
+			int32Value(deployment.Spec.Replicas), deployment.Status.AvailableReplicas}, nil
+	case api.ResourceKindStatefulSet:
+		statefulSet, err := client.AppsV1beta1().StatefulSets(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &workload{api.ResourceKindStatefulSet, statefulSet.ObjectMeta, statefulSet.Spec.Selector,
+			int32Value(statefulSet.Spec.Replicas), statefulSet.Status.Replicas}, nil
+	case api.ResourceKindDaemonSet:
+		daemonSet, err := client.ExtensionsV1beta1().DaemonSets(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &workload{api.ResourceKindDaemonSet, daemonSet.ObjectMeta, daemonSet.Spec.Selector,
+			daemonSet.Status.DesiredNumberScheduled, daemonSet.Status.NumberReady}, nil
+	default:
+		return nil, k8serrors.NewBadRequest(fmt.Sprintf("SLOs are not supported for %s resources", kind))
+	}
+}
+
+// int32Value returns value of optional replica count, which defaults to 1.
+func int32Value(value *int32) int32 {
+	if value == nil {
+		return 1
+	}
+	return *value
+}
+
+// setWorkloadAnnotation sets or, when value is empty, removes SLO annotation of given workload.
+func setWorkloadAnnotation(client client.Interface, kind, namespace, name, value string) error {
+	update := func(meta *metaV1.ObjectMeta) {
+		if value == "" {
+			delete(meta.Annotations, SLOAnnotationKey)
+			return
+		}
+		if meta.Annotations == nil {
+			meta.Annotations = make(map[string]string)
+		}
+		meta.Annotations[SLOAnnotationKey] = value
+	}
+
+	switch api.ResourceKind(kind) {
+	case api.ResourceKindDeployment:
+		deployment, err := client.ExtensionsV1beta1().Deployments(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		update(&deployment.ObjectMeta)
+		_, err = client.ExtensionsV1beta1().Deployments(namespace).Update(deployment)
+		return err
+	case api.ResourceKindStatefulSet:
+		statefulSet, err := client.AppsV1beta1().StatefulSets(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		update(&statefulSet.ObjectMeta)
+		_, err = client.AppsV1beta1().StatefulSets(namespace).Update(statefulSet)
+		return err
+	case api.ResourceKindDaemonSet:
+		daemonSet, err := client.ExtensionsV1beta1().DaemonSets(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		update(&daemonSet.ObjectMeta)
+		_, err = client.ExtensionsV1beta1().DaemonSets(namespace).Update(daemonSet)
+		return err
+	default:
+		return k8serrors.NewBadRequest(fmt.Sprintf("SLOs are not supported for %s resources", kind))
+	}
+}
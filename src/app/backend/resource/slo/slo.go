@@ -0,0 +1,254 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slo
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+// SLOStatus is a result of SLO evaluation for a single workload.
+type SLOStatus struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	Spec SLOSpec `json:"spec"`
+
+	// Current ratio of ready replicas to desired replicas.
+	CurrentRatio float64 `json:"currentRatio"`
+
+	// Estimated ratio of ready replica time to desired replica time over the SLO window.
+	Availability float64 `json:"availability"`
+
+	// Rate at which the error budget is consumed. Values above 1 mean the budget will be exhausted before
+	// the end of the window.
+	BurnRate float64 `json:"burnRate"`
+
+	// Fraction of the error budget that is still left. Negative when the budget is exhausted.
+	ErrorBudgetRemaining float64 `json:"errorBudgetRemaining"`
+
+	// True when availability meets the SLO target.
+	Met bool `json:"met"`
+}
+
+// SLOList contains SLO evaluation results of workloads in a namespace.
+type SLOList struct {
+	ListMeta api.ListMeta `json:"listMeta"`
+	SLOs     []SLOStatus  `json:"slos"`
+}
+
+// GetSLOList returns evaluated SLOs of all workloads in given namespace that have one defined.
+func GetSLOList(client client.Interface, namespace string) (*SLOList, error) {
+	log.Printf("Getting list of SLOs in %s namespace", namespace)
+
+	sloList := &SLOList{SLOs: make([]SLOStatus, 0)}
+
+	workloads, err := getNamespaceWorkloads(client, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, workload := range workloads {
+		spec, err := getSLOSpec(workload.objectMeta.Annotations)
+		if err != nil {
+			log.Printf("Couldn't parse SLO of %s %s: %s", workload.kind, workload.objectMeta.Name, err)
+			continue
+		}
+		if spec == nil {
+			continue
+		}
+
+		status, err := evaluateWorkload(client, workload, *spec)
+		if err != nil {
+			return nil, err
+		}
+		sloList.SLOs = append(sloList.SLOs, *status)
+	}
+
+	sloList.ListMeta = api.ListMeta{TotalItems: len(sloList.SLOs)}
+	return sloList, nil
+}
+
+// GetSLO returns evaluated SLO of given workload. Returns nil if workload has no SLO defined.
+func GetSLO(client client.Interface, kind, namespace, name string) (*SLOStatus, error) {
+	log.Printf("Getting SLO of %s %s in %s namespace", kind, name, namespace)
+
+	workload, err := getWorkload(client, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := getSLOSpec(workload.objectMeta.Annotations)
+	if err != nil || spec == nil {
+		return nil, err
+	}
+
+	return evaluateWorkload(client, workload, *spec)
+}
+
+// SetSLO stores SLO definition on given workload and returns its evaluation.
+func SetSLO(client client.Interface, kind, namespace, name string, spec SLOSpec) (*SLOStatus, error) {
+	log.Printf("Setting SLO of %s %s in %s namespace", kind, name, namespace)
+
+	if _, err := spec.validate(); err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setWorkloadAnnotation(client, kind, namespace, name, string(raw)); err != nil {
+		return nil, err
+	}
+
+	return GetSLO(client, kind, namespace, name)
+}
+
+// DeleteSLO removes SLO definition from given workload.
+func DeleteSLO(client client.Interface, kind, namespace, name string) error {
+	log.Printf("Deleting SLO of %s %s in %s namespace", kind, name, namespace)
+	return setWorkloadAnnotation(client, kind, namespace, name, "")
+}
+
+// getNamespaceWorkloads returns all workloads in given namespace SLOs can be defined for.
+func getNamespaceWorkloads(client client.Interface, namespace string) ([]*workload, error) {
+	result := make([]*workload, 0)
+
+	deployments, err := client.ExtensionsV1beta1().Deployments(namespace).List(metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range deployments.Items {
+		result = append(result, &workload{api.ResourceKindDeployment, d.ObjectMeta, d.Spec.Selector,
+			int32Value(d.Spec.Replicas), d.Status.AvailableReplicas})
+	}
+
+	statefulSets, err := client.AppsV1beta1().StatefulSets(namespace).List(metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range statefulSets.Items {
+		result = append(result, &workload{api.ResourceKindStatefulSet, s.ObjectMeta, s.Spec.Selector,
+			int32Value(s.Spec.Replicas), s.Status.Replicas})
+	}
+
+	daemonSets, err := client.ExtensionsV1beta1().DaemonSets(namespace).List(metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range daemonSets.Items {
+		result = append(result, &workload{api.ResourceKindDaemonSet, d.ObjectMeta, d.Spec.Selector,
+			d.Status.DesiredNumberScheduled, d.Status.NumberReady})
+	}
+
+	return result, nil
+}
+
+// evaluateWorkload evaluates SLO of given workload using status history of its current pods.
+func evaluateWorkload(client client.Interface, workload *workload, spec SLOSpec) (*SLOStatus, error) {
+	selector, err := metaV1.LabelSelectorAsSelector(workload.selector)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := client.CoreV1().Pods(workload.objectMeta.Namespace).List(metaV1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	status := evaluate(workload, pods.Items, spec, time.Now())
+	return &status, nil
+}
+
+// evaluate computes SLO status at given time. Availability is estimated from Ready conditions of
+// current pods, so downtime of pods that were deleted during the window is not taken into account.
+func evaluate(workload *workload, pods []v1.Pod, spec SLOSpec, now time.Time) SLOStatus {
+	status := SLOStatus{
+		ObjectMeta: api.NewObjectMeta(workload.objectMeta),
+		TypeMeta:   api.NewTypeMeta(workload.kind),
+		Spec:       spec,
+	}
+
+	window, err := spec.validate()
+	if err != nil {
+		return status
+	}
+
+	if workload.desired == 0 {
+		// Workload scaled to zero has nothing to be unavailable.
+		status.CurrentRatio = 1
+		status.Availability = 1
+		status.ErrorBudgetRemaining = 1
+		status.Met = true
+		return status
+	}
+
+	status.CurrentRatio = float64(workload.ready) / float64(workload.desired)
+
+	// Window never starts before the workload was created.
+	windowStart := now.Add(-window)
+	if created := workload.objectMeta.CreationTimestamp.Time; created.After(windowStart) {
+		windowStart = created
+	}
+	windowLength := now.Sub(windowStart).Seconds()
+	if windowLength <= 0 {
+		status.Availability = status.CurrentRatio
+	} else {
+		readySeconds := 0.0
+		for _, pod := range pods {
+			readySeconds += getPodReadySeconds(pod, windowStart, now)
+		}
+		status.Availability = readySeconds / (float64(workload.desired) * windowLength)
+	}
+	if status.Availability > 1 {
+		status.Availability = 1
+	}
+
+	errorBudget := 1 - spec.Target
+	status.BurnRate = (1 - status.Availability) / errorBudget
+	status.ErrorBudgetRemaining = 1 - status.BurnRate
+	status.Met = status.Availability >= spec.Target
+
+	return status
+}
+
+// getPodReadySeconds returns number of seconds between windowStart and now the pod has been ready for.
+func getPodReadySeconds(pod v1.Pod, windowStart, now time.Time) float64 {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type != v1.PodReady || condition.Status != v1.ConditionTrue {
+			continue
+		}
+
+		readySince := condition.LastTransitionTime.Time
+		if readySince.Before(windowStart) {
+			readySince = windowStart
+		}
+		if readySince.After(now) {
+			return 0
+		}
+		return now.Sub(readySince).Seconds()
+	}
+	return 0
+}
@@ -0,0 +1,125 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+func readyPod(since time.Time) v1.Pod {
+	return v1.Pod{Status: v1.PodStatus{Conditions: []v1.PodCondition{{
+		Type:               v1.PodReady,
+		Status:             v1.ConditionTrue,
+		LastTransitionTime: metaV1.NewTime(since),
+	}}}}
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Date(2017, 5, 5, 12, 0, 0, 0, time.UTC)
+	created := metaV1.NewTime(now.Add(-48 * time.Hour))
+	spec := SLOSpec{Target: 0.9, Window: "10h"}
+
+	cases := []struct {
+		workload *workload
+		pods     []v1.Pod
+		expected SLOStatus
+	}{
+		{
+			&workload{kind: api.ResourceKindDeployment,
+				objectMeta: metaV1.ObjectMeta{Name: "foo", CreationTimestamp: created}, desired: 0},
+			nil,
+			SLOStatus{ObjectMeta: api.ObjectMeta{Name: "foo", CreationTimestamp: created},
+				TypeMeta: api.TypeMeta{Kind: api.ResourceKindDeployment}, Spec: spec,
+				CurrentRatio: 1, Availability: 1, ErrorBudgetRemaining: 1, Met: true},
+		},
+		{
+			// Both pods ready over the whole window.
+			&workload{kind: api.ResourceKindDeployment,
+				objectMeta: metaV1.ObjectMeta{Name: "foo", CreationTimestamp: created}, desired: 2, ready: 2},
+			[]v1.Pod{readyPod(now.Add(-20 * time.Hour)), readyPod(now.Add(-20 * time.Hour))},
+			SLOStatus{ObjectMeta: api.ObjectMeta{Name: "foo", CreationTimestamp: created},
+				TypeMeta: api.TypeMeta{Kind: api.ResourceKindDeployment}, Spec: spec,
+				CurrentRatio: 1, Availability: 1, ErrorBudgetRemaining: 1, Met: true},
+		},
+		{
+			// One of two pods ready only for the last 8 hours of 10 hour window.
+			&workload{kind: api.ResourceKindDeployment,
+				objectMeta: metaV1.ObjectMeta{Name: "foo", CreationTimestamp: created}, desired: 2, ready: 2},
+			[]v1.Pod{readyPod(now.Add(-20 * time.Hour)), readyPod(now.Add(-8 * time.Hour))},
+			SLOStatus{ObjectMeta: api.ObjectMeta{Name: "foo", CreationTimestamp: created},
+				TypeMeta: api.TypeMeta{Kind: api.ResourceKindDeployment}, Spec: spec,
+				CurrentRatio: 1, Availability: 0.9, BurnRate: 1, ErrorBudgetRemaining: 0, Met: true},
+		},
+	}
+
+	for _, c := range cases {
+		actual := evaluate(c.workload, c.pods, spec, now)
+		// Round floating point results to avoid precision issues in comparison.
+		actual.BurnRate = float64(int(actual.BurnRate*1000+0.5)) / 1000
+		actual.ErrorBudgetRemaining = float64(int(actual.ErrorBudgetRemaining*1000+0.5)) / 1000
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("evaluate(%#v, %#v) == \ngot: %#v, \nexpected %#v",
+				c.workload, c.pods, actual, c.expected)
+		}
+	}
+}
+
+func TestSetSLO(t *testing.T) {
+	replicas := int32(1)
+	deployment := &extensions.Deployment{
+		ObjectMeta: metaV1.ObjectMeta{Name: "foo", Namespace: "bar"},
+		Spec: extensions.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metaV1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+		},
+	}
+	fakeClient := fake.NewSimpleClientset(deployment)
+
+	cases := []struct {
+		spec        SLOSpec
+		expectError bool
+	}{
+		{SLOSpec{Target: 1.5, Window: "1h"}, true},
+		{SLOSpec{Target: 0.99, Window: "forever"}, true},
+		{SLOSpec{Target: 0.99, Window: "1h"}, false},
+	}
+
+	for _, c := range cases {
+		actual, err := SetSLO(fakeClient, "deployment", "bar", "foo", c.spec)
+		if (err != nil) != c.expectError {
+			t.Errorf("SetSLO(%#v) returned error %v, expected error: %v", c.spec, err, c.expectError)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(actual.Spec, c.spec) {
+			t.Errorf("SetSLO(%#v) == \ngot: %#v, \nexpected %#v", c.spec, actual.Spec, c.spec)
+		}
+	}
+
+	if err := DeleteSLO(fakeClient, "deployment", "bar", "foo"); err != nil {
+		t.Fatalf("DeleteSLO() returned error: %s", err)
+	}
+	actual, err := GetSLO(fakeClient, "deployment", "bar", "foo")
+	if err != nil || actual != nil {
+		t.Errorf("GetSLO() after DeleteSLO() == %#v, %v, expected nil", actual, err)
+	}
+}
@@ -18,7 +18,8 @@ import (
 	"log"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
+	"github.com/kubernetes/dashboard/src/app/backend/link"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/pod"
@@ -34,6 +35,9 @@ type StatefulSetDetail struct {
 	ObjectMeta api.ObjectMeta `json:"objectMeta"`
 	TypeMeta   api.TypeMeta   `json:"typeMeta"`
 
+	// Links to external tools configured in settings and in the links annotation.
+	Links []link.Link `json:"links,omitempty"`
+
 	// Aggregate information about pods belonging to this Pet Set.
 	PodInfo common.PodInfo `json:"podInfo"`
 
@@ -45,10 +49,24 @@ type StatefulSetDetail struct {
 
 	// List of events related to this Pet Set.
 	EventList common.EventList `json:"eventList"`
+
+	// Update strategy of this Stateful Set.
+	UpdateStrategy StatefulSetUpdateStrategy `json:"updateStrategy"`
+
+	// Revisions of pods before and after the update in progress, equal when no update is in
+	// progress.
+	CurrentRevision string `json:"currentRevision"`
+	UpdateRevision  string `json:"updateRevision"`
+
+	// Number of pods running update revision.
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+
+	// Pods of this Stateful Set by ordinal with their persistent volume claims.
+	Ordinals []OrdinalPod `json:"ordinals"`
 }
 
 // GetStatefulSetDetail gets pet set details.
-func GetStatefulSetDetail(client *k8sClient.Clientset, heapsterClient heapster.HeapsterClient,
+func GetStatefulSetDetail(client *k8sClient.Clientset, heapsterClient metricapi.MetricClient,
 	namespace, name string) (*StatefulSetDetail, error) {
 
 	log.Printf("Getting details of %s service in %s namespace", name, namespace)
@@ -74,11 +92,32 @@ func GetStatefulSetDetail(client *k8sClient.Clientset, heapsterClient heapster.H
 		return nil, err
 	}
 
+	rawStatefulSet, err := getRawStatefulSet(client, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := getRawStatefulSetPods(client, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := getClaims(client, statefulSetData)
+	if err != nil {
+		return nil, err
+	}
+
 	statefulSet := getStatefulSetDetail(statefulSetData, heapsterClient, *events, *podList, *podInfo)
+	statefulSet.UpdateStrategy = rawStatefulSet.updateStrategy()
+	statefulSet.CurrentRevision = rawStatefulSet.Status.CurrentRevision
+	statefulSet.UpdateRevision = rawStatefulSet.Status.UpdateRevision
+	statefulSet.UpdatedReplicas = rawStatefulSet.Status.UpdatedReplicas
+	statefulSet.Ordinals = toOrdinalPods(statefulSetData, statefulSet.UpdateStrategy,
+		statefulSet.UpdateRevision, pods, claims)
 	return &statefulSet, nil
 }
 
-func getStatefulSetDetail(statefulSet *apps.StatefulSet, heapsterClient heapster.HeapsterClient,
+func getStatefulSetDetail(statefulSet *apps.StatefulSet, heapsterClient metricapi.MetricClient,
 	eventList common.EventList, podList pod.PodList, podInfo common.PodInfo) StatefulSetDetail {
 
 	return StatefulSetDetail{
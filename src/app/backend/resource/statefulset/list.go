@@ -18,7 +18,8 @@ import (
 	"log"
 
 	"github.com/kubernetes/dashboard/src/app/backend/api"
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	"github.com/kubernetes/dashboard/src/app/backend/integration/alertmanager"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
@@ -33,6 +34,9 @@ import (
 type StatefulSetList struct {
 	ListMeta api.ListMeta `json:"listMeta"`
 
+	// Status summary of the returned items.
+	Status common.ResourceStatus `json:"status"`
+
 	// Unordered list of Pet Sets.
 	StatefulSets      []StatefulSet   `json:"statefulSets"`
 	CumulativeMetrics []metric.Metric `json:"cumulativeMetrics"`
@@ -50,17 +54,24 @@ type StatefulSet struct {
 
 	// Container images of the Stateful Set.
 	ContainerImages []string `json:"containerImages"`
+
+	// Active alerts of the Stateful Set, nil if there are none.
+	Alerts *alertmanager.AlertBadge `json:"alerts,omitempty"`
+
+	// Metrics of the Stateful Set, set only if item metrics are requested.
+	Metrics []metric.Metric `json:"metrics,omitempty"`
 }
 
 // GetStatefulSetList returns a list of all Stateful Sets in the cluster.
 func GetStatefulSetList(client *client.Clientset, nsQuery *common.NamespaceQuery,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*StatefulSetList, error) {
+	dsQuery *dataselect.DataSelectQuery, heapsterClient *metricapi.MetricClient) (*StatefulSetList, error) {
 	log.Print("Getting list of all pet sets in the cluster")
 
+	options := dsQuery.ListOptions()
 	channels := &common.ResourceChannels{
-		StatefulSetList: common.GetStatefulSetListChannel(client, nsQuery, 1),
-		PodList:         common.GetPodListChannel(client, nsQuery, 1),
-		EventList:       common.GetEventListChannel(client, nsQuery, 1),
+		StatefulSetList: common.GetStatefulSetListChannelWithOptions(client, nsQuery, options, 1),
+		PodList:         common.GetFanOutPodListChannel(client, nsQuery, 1),
+		EventList:       common.GetWarningEventListChannel(client, nsQuery, 1),
 	}
 
 	return GetStatefulSetListFromChannels(channels, dsQuery, heapsterClient)
@@ -69,7 +80,7 @@ func GetStatefulSetList(client *client.Clientset, nsQuery *common.NamespaceQuery
 // GetStatefulSetListFromChannels returns a list of all Stateful Sets in the cluster reading
 // required resource list once from the channels.
 func GetStatefulSetListFromChannels(channels *common.ResourceChannels,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*StatefulSetList, error) {
+	dsQuery *dataselect.DataSelectQuery, heapsterClient *metricapi.MetricClient) (*StatefulSetList, error) {
 
 	statefulSets := <-channels.StatefulSetList.List
 	if err := <-channels.StatefulSetList.Error; err != nil {
@@ -95,36 +106,39 @@ func GetStatefulSetListFromChannels(channels *common.ResourceChannels,
 		return nil, err
 	}
 
-	return CreateStatefulSetList(statefulSets.Items, pods.Items, events.Items, dsQuery, heapsterClient), nil
+	result := CreateStatefulSetList(statefulSets.Items, pods.Items, events.Items, dsQuery, heapsterClient)
+	result.ListMeta.Warnings = common.GetListBudgetWarnings(pods, events)
+	return result, nil
 }
 
 // CreateStatefulSetList creates paginated list of Stateful Set model objects based on Kubernetes
 // Stateful Set objects array and related resources arrays.
 func CreateStatefulSetList(statefulSets []apps.StatefulSet, pods []v1.Pod, events []v1.Event,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) *StatefulSetList {
+	dsQuery *dataselect.DataSelectQuery, heapsterClient *metricapi.MetricClient) *StatefulSetList {
 
 	statefulSetList := &StatefulSetList{
 		StatefulSets: make([]StatefulSet, 0),
 		ListMeta:     api.ListMeta{TotalItems: len(statefulSets)},
 	}
 
+	for _, statefulSet := range fromCells(dataselect.GenericDataFilter(toCells(statefulSets), dsQuery)) {
+		statefulSetList.Status.AddPodInfo(getPodInfo(statefulSet, pods, events))
+	}
+
 	cachedResources := &dataselect.CachedResources{
 		Pods: pods,
 	}
-	ssCells, metricPromises, filteredTotal := dataselect.GenericDataSelectWithFilterAndMetrics(
-		toCells(statefulSets), dsQuery, cachedResources, heapsterClient)
+	ssCells, metricPromises, itemMetricPromises, filteredTotal :=
+		dataselect.GenericDataSelectWithFilterAndItemMetrics(toCells(statefulSets), dsQuery, cachedResources,
+			heapsterClient)
 	statefulSets = fromCells(ssCells)
-	statefulSetList.ListMeta = api.ListMeta{TotalItems: filteredTotal}
-
-	for _, statefulSet := range statefulSets {
-		matchingPods := common.FilterPodsByOwnerReference(statefulSet.Namespace,
-			statefulSet.UID, pods)
-		// TODO(floreks): Conversion should be omitted when client type will be updated
-		podInfo := common.GetPodInfo(statefulSet.Status.Replicas,
-			*statefulSet.Spec.Replicas, matchingPods)
-		podInfo.Warnings = event.GetPodsEventWarnings(events, matchingPods)
-		statefulSetList.StatefulSets = append(statefulSetList.StatefulSets,
-			ToStatefulSet(&statefulSet, &podInfo))
+	statefulSetList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, statefulSetList.ListMeta.TotalItems)
+
+	for i, statefulSet := range statefulSets {
+		podInfo := getPodInfo(statefulSet, pods, events)
+		item := ToStatefulSet(&statefulSet, &podInfo)
+		item.Metrics = metric.GetItemMetrics(itemMetricPromises, i)
+		statefulSetList.StatefulSets = append(statefulSetList.StatefulSets, item)
 	}
 
 	cumulativeMetrics, err := metricPromises.GetMetrics()
@@ -145,3 +159,13 @@ func ToStatefulSet(statefulSet *apps.StatefulSet, podInfo *common.PodInfo) State
 		Pods:            *podInfo,
 	}
 }
+
+// getPodInfo returns aggregate information about pods of given stateful set.
+func getPodInfo(statefulSet apps.StatefulSet, pods []v1.Pod, events []v1.Event) common.PodInfo {
+	matchingPods := common.FilterPodsByOwnerReference(statefulSet.Namespace, statefulSet.UID, pods)
+	// TODO(floreks): Conversion should be omitted when client type will be updated
+	podInfo := common.GetPodInfo(statefulSet.Status.Replicas, *statefulSet.Spec.Replicas,
+		matchingPods)
+	podInfo.Warnings = event.GetPodsEventWarnings(events, matchingPods)
+	return podInfo
+}
@@ -142,7 +142,8 @@ func TestGetStatefulSetListFromChannels(t *testing.T) {
 				},
 			},
 			&StatefulSetList{
-				ListMeta:          api.ListMeta{TotalItems: 1},
+				ListMeta:          api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
+				Status:            common.ResourceStatus{Failed: 1},
 				CumulativeMetrics: make([]metric.Metric, 0),
 				StatefulSets: []StatefulSet{{
 					ObjectMeta: api.ObjectMeta{
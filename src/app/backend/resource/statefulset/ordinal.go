@@ -0,0 +1,238 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statefulset
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	k8sClient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	apps "k8s.io/client-go/pkg/apis/apps/v1beta1"
+)
+
+// RevisionLabelKey is the label set by stateful set controller on pods with revision of the
+// stateful set they were created from.
+const RevisionLabelKey = "controller-revision-hash"
+
+// Update strategies of stateful sets.
+const (
+	UpdateStrategyRollingUpdate = "RollingUpdate"
+	UpdateStrategyOnDelete      = "OnDelete"
+)
+
+// StatefulSetUpdateStrategy is the update strategy of a stateful set. Update strategy is not known
+// to the API version used by the client, so it is read from raw stateful set.
+type StatefulSetUpdateStrategy struct {
+	// Type of the strategy, RollingUpdate or OnDelete. Empty on clusters not supporting updates of
+	// stateful sets.
+	Type string `json:"type"`
+
+	// Pods with ordinal greater than or equal to partition are updated by rolling update, others
+	// are kept at their revision. Nil when not set, i.e. all pods are updated.
+	Partition *int32 `json:"partition"`
+}
+
+// OrdinalPod is the pod of a stateful set with given ordinal together with its claims.
+type OrdinalPod struct {
+	Ordinal int    `json:"ordinal"`
+	PodName string `json:"podName"`
+
+	// False when the pod with the ordinal doesn't exist, e.g. while it's being recreated.
+	Exists   bool        `json:"exists"`
+	Phase    v1.PodPhase `json:"phase"`
+	Ready    bool        `json:"ready"`
+	NodeName string      `json:"nodeName"`
+	Revision string      `json:"revision"`
+
+	// True when the pod runs update revision of the stateful set.
+	Updated bool `json:"updated"`
+
+	// True when the ordinal is below partition, so that the pod is not updated by rolling update.
+	Partitioned bool `json:"partitioned"`
+
+	// Claims created from volume claim templates of the stateful set for the pod.
+	Claims []OrdinalClaim `json:"claims"`
+}
+
+// OrdinalClaim is a persistent volume claim created for a stateful set pod from a volume claim
+// template.
+type OrdinalClaim struct {
+	Template string `json:"template"`
+	Name     string `json:"name"`
+
+	// False when the claim doesn't exist, e.g. as it was deleted manually.
+	Exists     bool                          `json:"exists"`
+	Phase      v1.PersistentVolumeClaimPhase `json:"phase"`
+	VolumeName string                        `json:"volumeName"`
+	Capacity   string                        `json:"capacity"`
+}
+
+// rawStatefulSet contains fields of stateful set not known to the API version used by the client.
+type rawStatefulSet struct {
+	Spec struct {
+		UpdateStrategy struct {
+			Type          string `json:"type"`
+			RollingUpdate *struct {
+				Partition *int32 `json:"partition"`
+			} `json:"rollingUpdate"`
+		} `json:"updateStrategy"`
+	} `json:"spec"`
+	Status struct {
+		CurrentRevision string `json:"currentRevision"`
+		UpdateRevision  string `json:"updateRevision"`
+		UpdatedReplicas int32  `json:"updatedReplicas"`
+	} `json:"status"`
+}
+
+// GetUpdateStrategy returns update strategy of given stateful set.
+func GetUpdateStrategy(client k8sClient.Interface, namespace, name string) (
+	*StatefulSetUpdateStrategy, error) {
+	statefulSet, err := getRawStatefulSet(client, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	strategy := statefulSet.updateStrategy()
+	return &strategy, nil
+}
+
+func getRawStatefulSet(client k8sClient.Interface, namespace, name string) (*rawStatefulSet, error) {
+	raw, err := common.JSONRequest(client.AppsV1beta1().RESTClient().Get().
+		Namespace(namespace).
+		Resource("statefulsets").
+		Name(name)).
+		DoRaw()
+	if err != nil {
+		return nil, err
+	}
+	return parseRawStatefulSet(raw)
+}
+
+func parseRawStatefulSet(raw []byte) (*rawStatefulSet, error) {
+	statefulSet := &rawStatefulSet{}
+	if err := json.Unmarshal(raw, statefulSet); err != nil {
+		return nil, err
+	}
+	return statefulSet, nil
+}
+
+func (self *rawStatefulSet) updateStrategy() StatefulSetUpdateStrategy {
+	strategy := StatefulSetUpdateStrategy{Type: self.Spec.UpdateStrategy.Type}
+	if self.Spec.UpdateStrategy.RollingUpdate != nil {
+		strategy.Partition = self.Spec.UpdateStrategy.RollingUpdate.Partition
+	}
+	return strategy
+}
+
+// getClaims returns persistent volume claims in namespace of given stateful set.
+func getClaims(client k8sClient.Interface, statefulSet *apps.StatefulSet) ([]v1.PersistentVolumeClaim, error) {
+	if len(statefulSet.Spec.VolumeClaimTemplates) == 0 {
+		return []v1.PersistentVolumeClaim{}, nil
+	}
+
+	channels := &common.ResourceChannels{
+		PersistentVolumeClaimList: common.GetPersistentVolumeClaimListChannel(client,
+			common.NewSameNamespaceQuery(statefulSet.Namespace), 1),
+	}
+
+	claims := <-channels.PersistentVolumeClaimList.List
+	if err := <-channels.PersistentVolumeClaimList.Error; err != nil {
+		return nil, err
+	}
+	return claims.Items, nil
+}
+
+// toOrdinalPods returns pods of stateful set by ordinal, from 0 up to the number of replicas or the
+// highest existing ordinal, with claims of each pod.
+func toOrdinalPods(statefulSet *apps.StatefulSet, strategy StatefulSetUpdateStrategy,
+	updateRevision string, pods []v1.Pod, claims []v1.PersistentVolumeClaim) []OrdinalPod {
+
+	podsByOrdinal := make(map[int]*v1.Pod)
+	count := 0
+	if statefulSet.Spec.Replicas != nil {
+		count = int(*statefulSet.Spec.Replicas)
+	}
+	for i := range pods {
+		ordinal, ok := getOrdinal(statefulSet.Name, pods[i].Name)
+		if !ok {
+			continue
+		}
+		podsByOrdinal[ordinal] = &pods[i]
+		if ordinal >= count {
+			count = ordinal + 1
+		}
+	}
+
+	claimsByName := make(map[string]*v1.PersistentVolumeClaim)
+	for i := range claims {
+		claimsByName[claims[i].Name] = &claims[i]
+	}
+
+	result := make([]OrdinalPod, 0, count)
+	for ordinal := 0; ordinal < count; ordinal++ {
+		ordinalPod := OrdinalPod{
+			Ordinal: ordinal,
+			PodName: fmt.Sprintf("%s-%d", statefulSet.Name, ordinal),
+			Partitioned: strategy.Type == UpdateStrategyRollingUpdate && strategy.Partition != nil &&
+				int32(ordinal) < *strategy.Partition,
+			Claims: make([]OrdinalClaim, 0),
+		}
+
+		if pod, ok := podsByOrdinal[ordinal]; ok {
+			ordinalPod.Exists = true
+			ordinalPod.Phase = pod.Status.Phase
+			ordinalPod.Ready = common.IsPodReady(pod)
+			ordinalPod.NodeName = pod.Spec.NodeName
+			ordinalPod.Revision = pod.Labels[RevisionLabelKey]
+			ordinalPod.Updated = updateRevision != "" && ordinalPod.Revision == updateRevision
+		}
+
+		for _, template := range statefulSet.Spec.VolumeClaimTemplates {
+			claim := OrdinalClaim{
+				Template: template.Name,
+				Name:     fmt.Sprintf("%s-%s", template.Name, ordinalPod.PodName),
+			}
+			if pvc, ok := claimsByName[claim.Name]; ok {
+				claim.Exists = true
+				claim.Phase = pvc.Status.Phase
+				claim.VolumeName = pvc.Spec.VolumeName
+				if capacity, ok := pvc.Status.Capacity[v1.ResourceStorage]; ok {
+					claim.Capacity = capacity.String()
+				}
+			}
+			ordinalPod.Claims = append(ordinalPod.Claims, claim)
+		}
+
+		result = append(result, ordinalPod)
+	}
+
+	return result
+}
+
+// getOrdinal returns ordinal of stateful set pod from its name, which is the stateful set name
+// followed by the ordinal.
+func getOrdinal(statefulSetName, podName string) (int, bool) {
+	if !strings.HasPrefix(podName, statefulSetName+"-") {
+		return 0, false
+	}
+	ordinal, err := strconv.Atoi(strings.TrimPrefix(podName, statefulSetName+"-"))
+	if err != nil || ordinal < 0 {
+		return 0, false
+	}
+	return ordinal, true
+}
@@ -0,0 +1,109 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statefulset
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/pkg/api/v1"
+	apps "k8s.io/client-go/pkg/apis/apps/v1beta1"
+)
+
+func TestParseRawStatefulSet(t *testing.T) {
+	partition := int32(2)
+	cases := []struct {
+		raw      string
+		expected StatefulSetUpdateStrategy
+	}{
+		{`{"spec":{}}`, StatefulSetUpdateStrategy{}},
+		{`{"spec":{"updateStrategy":{"type":"OnDelete"}}}`, StatefulSetUpdateStrategy{Type: "OnDelete"}},
+		{
+			`{"spec":{"updateStrategy":{"type":"RollingUpdate","rollingUpdate":{"partition":2}}}}`,
+			StatefulSetUpdateStrategy{Type: "RollingUpdate", Partition: &partition},
+		},
+	}
+	for _, c := range cases {
+		statefulSet, err := parseRawStatefulSet([]byte(c.raw))
+		if err != nil {
+			t.Fatalf("parseRawStatefulSet(%#v) == \ngot err %#v", c.raw, err)
+		}
+		actual := statefulSet.updateStrategy()
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("updateStrategy(%#v) == \ngot %#v, \nexpected %#v", c.raw, actual, c.expected)
+		}
+	}
+}
+
+func TestToOrdinalPods(t *testing.T) {
+	replicas := int32(2)
+	partition := int32(1)
+	statefulSet := &apps.StatefulSet{
+		ObjectMeta: metaV1.ObjectMeta{Name: "db"},
+		Spec: apps.StatefulSetSpec{
+			Replicas: &replicas,
+			VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+				{ObjectMeta: metaV1.ObjectMeta{Name: "data"}},
+			},
+		},
+	}
+	strategy := StatefulSetUpdateStrategy{Type: UpdateStrategyRollingUpdate, Partition: &partition}
+	pods := []v1.Pod{
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "db-0", Labels: map[string]string{RevisionLabelKey: "db-1"}},
+			Spec:       v1.PodSpec{NodeName: "node-1"},
+			Status: v1.PodStatus{Phase: v1.PodRunning, Conditions: []v1.PodCondition{
+				{Type: v1.PodReady, Status: v1.ConditionTrue}}},
+		},
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "db-2", Labels: map[string]string{RevisionLabelKey: "db-2"}},
+			Status:     v1.PodStatus{Phase: v1.PodPending},
+		},
+		{ObjectMeta: metaV1.ObjectMeta{Name: "dbx-1"}},
+	}
+	claims := []v1.PersistentVolumeClaim{
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "data-db-0"},
+			Spec:       v1.PersistentVolumeClaimSpec{VolumeName: "pv-1"},
+			Status: v1.PersistentVolumeClaimStatus{
+				Phase:    v1.ClaimBound,
+				Capacity: v1.ResourceList{v1.ResourceStorage: resource.MustParse("1Gi")},
+			},
+		},
+	}
+	expected := []OrdinalPod{
+		{
+			Ordinal: 0, PodName: "db-0", Exists: true, Phase: v1.PodRunning, Ready: true,
+			NodeName: "node-1", Revision: "db-1", Partitioned: true,
+			Claims: []OrdinalClaim{{Template: "data", Name: "data-db-0", Exists: true,
+				Phase: v1.ClaimBound, VolumeName: "pv-1", Capacity: "1Gi"}},
+		},
+		{
+			Ordinal: 1, PodName: "db-1",
+			Claims: []OrdinalClaim{{Template: "data", Name: "data-db-1"}},
+		},
+		{
+			Ordinal: 2, PodName: "db-2", Exists: true, Phase: v1.PodPending, Revision: "db-2",
+			Updated: true, Claims: []OrdinalClaim{{Template: "data", Name: "data-db-2"}},
+		},
+	}
+
+	actual := toOrdinalPods(statefulSet, strategy, "db-2", pods, claims)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toOrdinalPods(%#v) == \ngot %#v, \nexpected %#v", statefulSet, actual, expected)
+	}
+}
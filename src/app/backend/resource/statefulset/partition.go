@@ -0,0 +1,76 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statefulset
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	k8sClient "k8s.io/client-go/kubernetes"
+)
+
+// PartitionSpec is a request to change partition of stateful set rolling update.
+type PartitionSpec struct {
+	// Pods with ordinal greater than or equal to partition are updated. Lowering partition step
+	// by step rolls out the update in stages, 0 updates all pods.
+	Partition int32 `json:"partition"`
+}
+
+// UpdatePartition sets partition of stateful set rolling update. Partition applies to rolling
+// updates only, so it cannot be set on stateful sets using OnDelete strategy.
+func UpdatePartition(client k8sClient.Interface, namespace, name string, spec *PartitionSpec) (
+	*StatefulSetUpdateStrategy, error) {
+	log.Printf("Setting rolling update partition of %s stateful set in %s namespace to %d", name,
+		namespace, spec.Partition)
+
+	if spec.Partition < 0 {
+		return nil, k8serrors.NewBadRequest("Partition must not be negative")
+	}
+
+	current, err := getRawStatefulSet(client, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	switch current.updateStrategy().Type {
+	case "":
+		return nil, k8serrors.NewBadRequest("Updating stateful sets is not supported by the cluster")
+	case UpdateStrategyOnDelete:
+		return nil, k8serrors.NewBadRequest(fmt.Sprintf("Stateful set %s uses %s update strategy, "+
+			"partition applies to %s strategy only", name, UpdateStrategyOnDelete,
+			UpdateStrategyRollingUpdate))
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"updateStrategy":{"rollingUpdate":{"partition":%d}}}}`,
+		spec.Partition)
+	raw, err := common.JSONRequest(client.AppsV1beta1().RESTClient().Patch(types.MergePatchType).
+		Namespace(namespace).
+		Resource("statefulsets").
+		Name(name).
+		Body([]byte(patch))).
+		DoRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	statefulSet, err := parseRawStatefulSet(raw)
+	if err != nil {
+		return nil, err
+	}
+	strategy := statefulSet.updateStrategy()
+	return &strategy, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statefulset
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestUpdatePartition(t *testing.T) {
+	cases := []struct {
+		strategyType     string
+		expectPatch      string
+		expectBadRequest bool
+	}{
+		{UpdateStrategyRollingUpdate, `{"spec":{"updateStrategy":{"rollingUpdate":{"partition":2}}}}`, false},
+		{UpdateStrategyOnDelete, "", true},
+		{"", "", true},
+	}
+	for _, c := range cases {
+		patch := ""
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "PATCH" {
+				body, _ := ioutil.ReadAll(r.Body)
+				patch = string(body)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"spec":{"updateStrategy":{"type":%q,"rollingUpdate":{"partition":2}}}}`,
+				c.strategyType)
+		}))
+
+		client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+		if err != nil {
+			t.Fatalf("Cannot create client: %s", err)
+		}
+		_, err = UpdatePartition(client, "default", "web", &PartitionSpec{Partition: 2})
+		server.Close()
+
+		if k8serrors.IsBadRequest(err) != c.expectBadRequest || (!c.expectBadRequest && err != nil) {
+			t.Errorf("UpdatePartition() of %#v stateful set returns error %v, expected bad request: %v",
+				c.strategyType, err, c.expectBadRequest)
+		}
+		if patch != c.expectPatch {
+			t.Errorf("UpdatePartition() of %#v stateful set == \ngot patch %#v, \nexpected %#v",
+				c.strategyType, patch, c.expectPatch)
+		}
+	}
+}
@@ -62,7 +62,7 @@ func CreateStorageClassList(storageClasses []storage.StorageClass, dsQuery *data
 
 	storageClassCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(storageClasses), dsQuery)
 	storageClasses = fromCells(storageClassCells)
-	storageClassList.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+	storageClassList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, storageClassList.ListMeta.TotalItems)
 
 	for _, storageClass := range storageClasses {
 		storageClassList.StorageClasses = append(storageClassList.StorageClasses, ToStorageClass(&storageClass))
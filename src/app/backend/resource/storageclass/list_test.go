@@ -43,7 +43,7 @@ func TestGetStorageClassList(t *testing.T) {
 				}},
 			expectedActions: []string{"list"},
 			expected: &StorageClassList{
-				ListMeta: api.ListMeta{TotalItems: 1},
+				ListMeta: api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
 				StorageClasses: []StorageClass{
 					{
 						ObjectMeta: api.ObjectMeta{
@@ -30,6 +30,8 @@ func (self ThirdPartyResourceCell) GetProperty(name dataselect.PropertyName) dat
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.UIDProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.UID)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
@@ -63,6 +65,8 @@ func (self ThirdPartyResourceObjectCell) GetProperty(name dataselect.PropertyNam
 		return dataselect.StdComparableTime(self.Metadata.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.Metadata.Namespace)
+	case dataselect.UIDProperty:
+		return dataselect.StdComparableString(self.Metadata.UID)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
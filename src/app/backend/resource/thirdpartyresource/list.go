@@ -72,7 +72,7 @@ func getThirdPartyResourceList(thirdPartyResources []extensions.ThirdPartyResour
 
 	tprCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(thirdPartyResources), dsQuery)
 	thirdPartyResources = fromCells(tprCells)
-	result.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+	result.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, result.ListMeta.TotalItems)
 
 	for _, item := range thirdPartyResources {
 		result.ThirdPartyResources = append(result.ThirdPartyResources,
@@ -43,7 +43,7 @@ func TestGetThirdPartyResourceList(t *testing.T) {
 				},
 			},
 			&ThirdPartyResourceList{
-				ListMeta: api.ListMeta{TotalItems: 1},
+				ListMeta: api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
 				ThirdPartyResources: []ThirdPartyResource{{
 					TypeMeta:   api.TypeMeta{Kind: api.ResourceKindThirdPartyResource},
 					ObjectMeta: api.ObjectMeta{Name: "foo"},
@@ -66,7 +66,7 @@ func GetThirdPartyResourceObjects(client k8sClient.Interface, config *rest.Confi
 	// Return only slice of data, pagination is done here.
 	tprObjectCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toObjectCells(list.Items), dsQuery)
 	list.Items = fromObjectCells(tprObjectCells)
-	list.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+	list.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, list.ListMeta.TotalItems)
 
 	return list, err
 }
@@ -0,0 +1,238 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	"fmt"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/deprecation"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/pod"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	apps "k8s.io/client-go/pkg/apis/apps/v1beta1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+	policy "k8s.io/client-go/pkg/apis/policy/v1beta1"
+)
+
+// MaxKubeletSkew is the number of minor releases kubelets may be older than the API server.
+const MaxKubeletSkew = 2
+
+// workload is a replicated workload checked for availability during node drains.
+type workload struct {
+	kind       api.ResourceKind
+	objectMeta metaV1.ObjectMeta
+	replicas   int32
+	template   v1.PodTemplateSpec
+}
+
+func (self workload) String() string {
+	return fmt.Sprintf("%s %s/%s", self.kind, self.objectMeta.Namespace, self.objectMeta.Name)
+}
+
+// checkDeprecatedAPIs fails for objects written with APIs not served by target release.
+func checkDeprecatedAPIs(client client.Interface, target release) (*UpgradeCheck, error) {
+	report, err := deprecation.GetDeprecationReport(client)
+	if err != nil {
+		return nil, err
+	}
+
+	failures := make([]string, 0)
+	for _, usage := range report.Usages {
+		if !usage.IsRemovedBy(target.String()) {
+			continue
+		}
+		name := usage.Name
+		if usage.Namespace != "" {
+			name = usage.Namespace + "/" + usage.Name
+		}
+		failures = append(failures, fmt.Sprintf("%s %s uses %s removed in %s", usage.Kind, name,
+			usage.GroupVersion, usage.RemovedIn))
+	}
+
+	return newCheck(CheckDeprecatedAPIs,
+		"Objects are not written with APIs removed in the target release",
+		"Migrate manifests and charts of listed objects to the replacement API versions and re-apply "+
+			"them, see the deprecation report for replacements.", failures), nil
+}
+
+// checkDisruptionBudgets fails for replicated workloads without pod disruption budget and for
+// budgets that allow no disruptions.
+func checkDisruptionBudgets(client client.Interface, target release) (*UpgradeCheck, error) {
+	workloads, err := getWorkloads(client)
+	if err != nil {
+		return nil, err
+	}
+
+	channel := common.GetPodDisruptionBudgetListChannel(client, common.NewNamespaceQuery(nil), 1)
+	pdbs := <-channel.List
+	if err := <-channel.Error; err != nil {
+		return nil, err
+	}
+
+	return newCheck(CheckDisruptionBudgets,
+		"Replicated workloads are covered by pod disruption budgets that allow disruptions",
+		"Add pod disruption budgets to listed workloads and make sure each budget allows at least "+
+			"one disruption, otherwise node drains either take all replicas down or hang.",
+		getDisruptionBudgetFailures(workloads, pdbs.Items)), nil
+}
+
+func getDisruptionBudgetFailures(workloads []workload, pdbs []policy.PodDisruptionBudget) []string {
+	failures := make([]string, 0)
+	for _, pdb := range pdbs {
+		if pdb.Status.ExpectedPods > 0 && pdb.Status.PodDisruptionsAllowed == 0 {
+			failures = append(failures, fmt.Sprintf("PodDisruptionBudget %s/%s allows no disruptions",
+				pdb.Namespace, pdb.Name))
+		}
+	}
+
+	for _, workload := range workloads {
+		if workload.replicas > 1 && !isCovered(workload, pdbs) {
+			failures = append(failures, fmt.Sprintf("%s has no pod disruption budget", workload))
+		}
+	}
+	return failures
+}
+
+// isCovered returns true if pods of given workload are selected by any of given budgets.
+func isCovered(workload workload, pdbs []policy.PodDisruptionBudget) bool {
+	for _, pdb := range pdbs {
+		if pdb.Namespace != workload.objectMeta.Namespace || pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metaV1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			continue
+		}
+		if selector.Matches(labels.Set(workload.template.Labels)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSingleReplicas fails for critical workloads running a single replica, which are unavailable
+// while their node is drained.
+func checkSingleReplicas(client client.Interface, target release) (*UpgradeCheck, error) {
+	workloads, err := getWorkloads(client)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCheck(CheckSingleReplicas, "Critical workloads run more than one replica",
+		"Scale listed workloads to at least 2 replicas or plan for their downtime while nodes are "+
+			"drained.", getSingleReplicaFailures(workloads)), nil
+}
+
+func getSingleReplicaFailures(workloads []workload) []string {
+	failures := make([]string, 0)
+	for _, workload := range workloads {
+		if workload.replicas == 1 && isCritical(workload) {
+			failures = append(failures, fmt.Sprintf("%s runs a single replica", workload))
+		}
+	}
+	return failures
+}
+
+// isCritical returns true for workloads in kube-system namespace and workloads of critical pods.
+func isCritical(workload workload) bool {
+	_, critical := workload.template.Annotations[pod.CriticalPodAnnotationKey]
+	return critical || workload.objectMeta.Namespace == metaV1.NamespaceSystem
+}
+
+// checkNodeVersionSkew fails for nodes with kubelets too old for API server of target release.
+func checkNodeVersionSkew(client client.Interface, target release) (*UpgradeCheck, error) {
+	channel := common.GetNodeListChannel(client, 1)
+	nodes := <-channel.List
+	if err := <-channel.Error; err != nil {
+		return nil, err
+	}
+
+	return newCheck(CheckNodeVersionSkew,
+		fmt.Sprintf("Kubelets are at most %d minor releases older than the target release",
+			MaxKubeletSkew),
+		fmt.Sprintf("Upgrade kubelets of listed nodes to at least %d.%d before upgrading the control "+
+			"plane.", target.major, target.minor-MaxKubeletSkew),
+		getNodeVersionSkewFailures(nodes.Items, target)), nil
+}
+
+func getNodeVersionSkewFailures(nodes []v1.Node, target release) []string {
+	failures := make([]string, 0)
+	for _, node := range nodes {
+		kubelet, err := parseRelease(node.Status.NodeInfo.KubeletVersion)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("Node %s has unknown kubelet version %q", node.Name,
+				node.Status.NodeInfo.KubeletVersion))
+			continue
+		}
+		if kubelet.major != target.major || target.minor-kubelet.minor > MaxKubeletSkew {
+			failures = append(failures, fmt.Sprintf("Node %s runs kubelet %s", node.Name,
+				node.Status.NodeInfo.KubeletVersion))
+		}
+	}
+	return failures
+}
+
+// getWorkloads returns deployments and stateful sets in all namespaces.
+func getWorkloads(client client.Interface) ([]workload, error) {
+	nsQuery := common.NewNamespaceQuery(nil)
+	channels := &common.ResourceChannels{
+		DeploymentList:  common.GetDeploymentListChannel(client, nsQuery, 1),
+		StatefulSetList: common.GetStatefulSetListChannel(client, nsQuery, 1),
+	}
+
+	deployments := <-channels.DeploymentList.List
+	if err := <-channels.DeploymentList.Error; err != nil {
+		return nil, err
+	}
+	statefulSets := <-channels.StatefulSetList.List
+	if err := <-channels.StatefulSetList.Error; err != nil {
+		return nil, err
+	}
+
+	return toWorkloads(deployments.Items, statefulSets.Items), nil
+}
+
+func toWorkloads(deployments []extensions.Deployment, statefulSets []apps.StatefulSet) []workload {
+	result := make([]workload, 0)
+	for _, deployment := range deployments {
+		result = append(result, workload{
+			kind:       api.ResourceKindDeployment,
+			objectMeta: deployment.ObjectMeta,
+			replicas:   getReplicas(deployment.Spec.Replicas),
+			template:   deployment.Spec.Template,
+		})
+	}
+	for _, statefulSet := range statefulSets {
+		result = append(result, workload{
+			kind:       api.ResourceKindStatefulSet,
+			objectMeta: statefulSet.ObjectMeta,
+			replicas:   getReplicas(statefulSet.Spec.Replicas),
+			template:   statefulSet.Spec.Template,
+		})
+	}
+	return result
+}
+
+// getReplicas returns desired number of replicas, which defaults to 1.
+func getReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
@@ -0,0 +1,132 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/pod"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+	policy "k8s.io/client-go/pkg/apis/policy/v1beta1"
+)
+
+func newWorkload(namespace, name string, replicas int32, annotations map[string]string) workload {
+	return workload{
+		kind:       api.ResourceKindDeployment,
+		objectMeta: metaV1.ObjectMeta{Namespace: namespace, Name: name},
+		replicas:   replicas,
+		template: v1.PodTemplateSpec{ObjectMeta: metaV1.ObjectMeta{
+			Labels:      map[string]string{"app": name},
+			Annotations: annotations,
+		}},
+	}
+}
+
+func TestGetDisruptionBudgetFailures(t *testing.T) {
+	workloads := []workload{
+		newWorkload("default", "web", 3, nil),
+		newWorkload("default", "api", 2, nil),
+		newWorkload("default", "cron", 1, nil),
+	}
+	pdbs := []policy.PodDisruptionBudget{
+		{
+			ObjectMeta: metaV1.ObjectMeta{Namespace: "default", Name: "web"},
+			Spec: policy.PodDisruptionBudgetSpec{
+				Selector: &metaV1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+			Status: policy.PodDisruptionBudgetStatus{ExpectedPods: 3},
+		},
+		{
+			ObjectMeta: metaV1.ObjectMeta{Namespace: "other", Name: "api"},
+			Spec: policy.PodDisruptionBudgetSpec{
+				Selector: &metaV1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+			},
+			Status: policy.PodDisruptionBudgetStatus{ExpectedPods: 2, PodDisruptionsAllowed: 1},
+		},
+	}
+
+	actual := getDisruptionBudgetFailures(workloads, pdbs)
+	expected := []string{
+		"PodDisruptionBudget default/web allows no disruptions",
+		"deployment default/api has no pod disruption budget",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getDisruptionBudgetFailures() == \ngot %#v, \nexpected %#v", actual, expected)
+	}
+}
+
+func TestGetSingleReplicaFailures(t *testing.T) {
+	workloads := []workload{
+		newWorkload("kube-system", "dns", 1, nil),
+		newWorkload("default", "web", 1, nil),
+		newWorkload("default", "proxy", 1, map[string]string{pod.CriticalPodAnnotationKey: ""}),
+		newWorkload("kube-system", "metrics", 2, nil),
+	}
+
+	actual := getSingleReplicaFailures(workloads)
+	expected := []string{
+		"deployment kube-system/dns runs a single replica",
+		"deployment default/proxy runs a single replica",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getSingleReplicaFailures() == \ngot %#v, \nexpected %#v", actual, expected)
+	}
+}
+
+func TestGetNodeVersionSkewFailures(t *testing.T) {
+	newNode := func(name, version string) v1.Node {
+		return v1.Node{
+			ObjectMeta: metaV1.ObjectMeta{Name: name},
+			Status:     v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{KubeletVersion: version}},
+		}
+	}
+	nodes := []v1.Node{
+		newNode("a", "v1.7.2"),
+		newNode("b", "v1.5.7"),
+		newNode("c", "v1.4.9"),
+		newNode("d", ""),
+	}
+
+	actual := getNodeVersionSkewFailures(nodes, release{major: 1, minor: 7})
+	expected := []string{
+		"Node c runs kubelet v1.4.9",
+		`Node d has unknown kubelet version ""`,
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getNodeVersionSkewFailures() == \ngot %#v, \nexpected %#v", actual, expected)
+	}
+}
+
+func TestCheckSingleReplicas(t *testing.T) {
+	replicas := int32(1)
+	fakeClient := fake.NewSimpleClientset(&extensions.DeploymentList{Items: []extensions.Deployment{{
+		ObjectMeta: metaV1.ObjectMeta{Namespace: "kube-system", Name: "dns"},
+		Spec:       extensions.DeploymentSpec{Replicas: &replicas},
+	}}})
+
+	actual, err := checkSingleReplicas(fakeClient, release{major: 1, minor: 7})
+	if err != nil {
+		t.Fatalf("checkSingleReplicas() == \ngot err %#v", err)
+	}
+	if actual.Passed || !reflect.DeepEqual(actual.Failures,
+		[]string{"deployment kube-system/dns runs a single replica"}) {
+		t.Errorf("checkSingleReplicas() == \ngot %#v, \nexpected single failure", actual)
+	}
+}
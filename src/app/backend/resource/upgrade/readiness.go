@@ -0,0 +1,191 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	client "k8s.io/client-go/kubernetes"
+)
+
+// Names of pre-upgrade checks.
+const (
+	CheckDeprecatedAPIs    = "deprecatedapis"
+	CheckDisruptionBudgets = "disruptionbudgets"
+	CheckSingleReplicas    = "singlereplicas"
+	CheckNodeVersionSkew   = "nodeversionskew"
+)
+
+// UpgradeReadiness is a result of pre-upgrade checks of the cluster.
+type UpgradeReadiness struct {
+	// Release the cluster is running, e.g. 1.6.
+	CurrentRelease string `json:"currentRelease"`
+
+	// Release the cluster is checked for, e.g. 1.7.
+	TargetRelease string `json:"targetRelease"`
+
+	// True if all checks passed.
+	Ready bool `json:"ready"`
+
+	Checks []UpgradeCheck `json:"checks"`
+}
+
+// UpgradeCheck is a result of a single pre-upgrade check.
+type UpgradeCheck struct {
+	Name string `json:"name"`
+
+	Description string `json:"description"`
+
+	Passed bool `json:"passed"`
+
+	// Objects failing the check, with the reason.
+	Failures []string `json:"failures"`
+
+	// What to do to make the check pass, set only when it failed.
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// release is a Kubernetes release in major.minor format.
+type release struct {
+	major int
+	minor int
+}
+
+func (self release) String() string {
+	return fmt.Sprintf("%d.%d", self.major, self.minor)
+}
+
+// checkFunc runs a check for upgrade to given release.
+type checkFunc func(client client.Interface, target release) (*UpgradeCheck, error)
+
+// checks are all pre-upgrade checks, in order they are run.
+var checks = []struct {
+	name string
+	run  checkFunc
+}{
+	{CheckDeprecatedAPIs, checkDeprecatedAPIs},
+	{CheckDisruptionBudgets, checkDisruptionBudgets},
+	{CheckSingleReplicas, checkSingleReplicas},
+	{CheckNodeVersionSkew, checkNodeVersionSkew},
+}
+
+// GetUpgradeReadiness runs given pre-upgrade checks, or all of them when no checks are given, for
+// upgrade to target release. Target release defaults to the release after the one the cluster is
+// running.
+func GetUpgradeReadiness(client client.Interface, names []string, targetRelease string) (
+	*UpgradeReadiness, error) {
+	log.Printf("Checking readiness of upgrade to %s", targetRelease)
+
+	selected, err := selectChecks(names)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := client.Discovery().ServerVersion()
+	if err != nil {
+		return nil, err
+	}
+	current, err := parseRelease(version.GitVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	target := release{major: current.major, minor: current.minor + 1}
+	if targetRelease != "" {
+		target, err = parseRelease(targetRelease)
+		if err != nil {
+			return nil, k8serrors.NewBadRequest(err.Error())
+		}
+	}
+
+	result := &UpgradeReadiness{
+		CurrentRelease: current.String(),
+		TargetRelease:  target.String(),
+		Ready:          true,
+		Checks:         make([]UpgradeCheck, 0),
+	}
+	for _, run := range selected {
+		check, err := run(client, target)
+		if err != nil {
+			return nil, err
+		}
+		result.Checks = append(result.Checks, *check)
+		result.Ready = result.Ready && check.Passed
+	}
+	return result, nil
+}
+
+// selectChecks returns checks of given names in order they are run, all checks when no names are
+// given.
+func selectChecks(names []string) ([]checkFunc, error) {
+	known := make(map[string]bool)
+	for _, check := range checks {
+		known[check.name] = true
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range names {
+		if !known[name] {
+			return nil, k8serrors.NewBadRequest(fmt.Sprintf("Unknown upgrade check %q", name))
+		}
+		wanted[name] = true
+	}
+
+	result := make([]checkFunc, 0)
+	for _, check := range checks {
+		if len(wanted) == 0 || wanted[check.name] {
+			result = append(result, check.run)
+		}
+	}
+	return result, nil
+}
+
+// parseRelease parses release from version in major.minor[.patch] format with optional v prefix,
+// e.g. v1.6.4 or 1.7.
+func parseRelease(version string) (release, error) {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return release{}, fmt.Errorf("Invalid Kubernetes version %q", version)
+	}
+
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return release{}, fmt.Errorf("Invalid Kubernetes version %q", version)
+	}
+	// Minor version of managed clusters may have a suffix, e.g. 1.6+.
+	minor, err := strconv.Atoi(strings.TrimRight(parts[1], "+"))
+	if err != nil {
+		return release{}, fmt.Errorf("Invalid Kubernetes version %q", version)
+	}
+	return release{major: major, minor: minor}, nil
+}
+
+// newCheck returns check of given name which passes when there are no failures.
+func newCheck(name, description, remediation string, failures []string) *UpgradeCheck {
+	check := &UpgradeCheck{
+		Name:        name,
+		Description: description,
+		Passed:      len(failures) == 0,
+		Failures:    failures,
+	}
+	if !check.Passed {
+		check.Remediation = remediation
+	}
+	return check
+}
@@ -0,0 +1,90 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRelease(t *testing.T) {
+	cases := []struct {
+		version     string
+		expected    release
+		expectError bool
+	}{
+		{"v1.6.4", release{major: 1, minor: 6}, false},
+		{"1.7", release{major: 1, minor: 7}, false},
+		{"v1.6.2-gke.0", release{major: 1, minor: 6}, false},
+		{"1", release{}, true},
+		{"v1.x", release{}, true},
+	}
+	for _, c := range cases {
+		actual, err := parseRelease(c.version)
+		if (err != nil) != c.expectError {
+			t.Errorf("parseRelease(%#v) returns error %v, expected error: %v", c.version, err,
+				c.expectError)
+		}
+		if actual != c.expected {
+			t.Errorf("parseRelease(%#v) == \ngot %#v, \nexpected %#v", c.version, actual, c.expected)
+		}
+	}
+}
+
+func TestSelectChecks(t *testing.T) {
+	cases := []struct {
+		names       []string
+		expected    int
+		expectError bool
+	}{
+		{[]string{}, len(checks), false},
+		{[]string{CheckNodeVersionSkew, CheckSingleReplicas}, 2, false},
+		{[]string{"foo"}, 0, true},
+	}
+	for _, c := range cases {
+		actual, err := selectChecks(c.names)
+		if (err != nil) != c.expectError {
+			t.Errorf("selectChecks(%#v) returns error %v, expected error: %v", c.names, err,
+				c.expectError)
+		}
+		if len(actual) != c.expected {
+			t.Errorf("selectChecks(%#v) == \ngot %d checks, \nexpected %d", c.names, len(actual),
+				c.expected)
+		}
+	}
+}
+
+func TestNewCheck(t *testing.T) {
+	cases := []struct {
+		failures []string
+		expected *UpgradeCheck
+	}{
+		{
+			[]string{},
+			&UpgradeCheck{Name: "test", Description: "desc", Passed: true, Failures: []string{}},
+		},
+		{
+			[]string{"fail"},
+			&UpgradeCheck{Name: "test", Description: "desc", Failures: []string{"fail"},
+				Remediation: "fix"},
+		},
+	}
+	for _, c := range cases {
+		actual := newCheck("test", "desc", "fix", c.failures)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("newCheck(%#v) == \ngot %#v, \nexpected %#v", c.failures, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,391 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+)
+
+// Time the backup resource list download can take in total, including waiting until Velero uploads
+// a signed URL to the download request.
+var (
+	downloadTimeout      = 30 * time.Second
+	downloadPollInterval = time.Second
+)
+
+// Maximum size in bytes of the gzipped backup resource list and of its decompressed JSON.
+var (
+	maxBackupResourceListSize     = 8 << 20
+	maxBackupResourceListJSONSize = 64 << 20
+)
+
+// downloadClient is the HTTP client used to download files of backups from object storage.
+var downloadClient = &http.Client{}
+
+// Backup is a presentation layer view of Velero Backup resource.
+type Backup struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	// Phase of the backup, e.g. InProgress, Completed or PartiallyFailed.
+	Phase string `json:"phase"`
+
+	// Name of the schedule that created the backup. Empty for backups created manually.
+	Schedule string `json:"schedule"`
+
+	Spec BackupTemplate `json:"spec"`
+
+	StartTimestamp      *metaV1.Time `json:"startTimestamp"`
+	CompletionTimestamp *metaV1.Time `json:"completionTimestamp"`
+
+	// Time when Velero deletes the backup.
+	Expiration *metaV1.Time `json:"expiration"`
+
+	TotalItems    int    `json:"totalItems"`
+	ItemsBackedUp int    `json:"itemsBackedUp"`
+	Errors        int    `json:"errors"`
+	Warnings      int    `json:"warnings"`
+	FailureReason string `json:"failureReason,omitempty"`
+}
+
+// BackupList contains a list of Velero backups.
+type BackupList struct {
+	ListMeta api.ListMeta `json:"listMeta"`
+	Backups  []Backup     `json:"backups"`
+}
+
+// BackupDetail contains backup and restores created from it.
+type BackupDetail struct {
+	Backup
+	RestoreList RestoreList `json:"restoreList"`
+}
+
+// BackupSpec contains information needed to create a backup.
+type BackupSpec struct {
+	// Name of the backup.
+	Name string `json:"name"`
+
+	// Namespace of Velero, where the backup is created.
+	Namespace string `json:"namespace"`
+
+	BackupTemplate
+}
+
+// BackupResourceGroup contains resources of one kind included in a backup.
+type BackupResourceGroup struct {
+	// Group version and kind of the resources, e.g. apps/v1/Deployment.
+	Resource string `json:"resource"`
+
+	// Resources included in the backup, e.g. default/nginx.
+	Items []string `json:"items"`
+}
+
+// BackupResourceList contains all resources included in a backup.
+type BackupResourceList struct {
+	Resources []BackupResourceGroup `json:"resources"`
+}
+
+// GetBackupList returns a list of Velero backups. Returns empty list when Velero is not installed.
+func GetBackupList(client client.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*BackupList, error) {
+	log.Print("Getting list of Velero backups")
+
+	backups, err := getBackups(client, nsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBackupList(backups, dsQuery), nil
+}
+
+// GetBackupDetail returns Velero backup with given name along with restores created from it.
+func GetBackupDetail(client client.Interface, namespace, name string) (*BackupDetail, error) {
+	log.Printf("Getting details of %s Velero backup in %s namespace", name, namespace)
+
+	groupVersion, err := common.GetInstalledGroupVersion(client, "backups", name, VeleroGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var backup rawBackup
+	if err := common.GetCustomResource(client, groupVersion, namespace, "backups", name,
+		&backup); err != nil {
+		return nil, err
+	}
+
+	restores, err := getRestores(client, common.NewSameNamespaceQuery(namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	return &BackupDetail{
+		Backup: toBackup(backup),
+		RestoreList: *toRestoreList(filterRestoresByBackup(restores, name),
+			dataselect.DefaultDataSelect),
+	}, nil
+}
+
+// CreateBackup creates Velero backup based on given specification.
+func CreateBackup(client client.Interface, spec *BackupSpec) (*Backup, error) {
+	log.Printf("Creating %s Velero backup in %s namespace", spec.Name, spec.Namespace)
+
+	return createBackup(client, spec.Namespace, metaV1.ObjectMeta{Name: spec.Name},
+		spec.BackupTemplate)
+}
+
+func createBackup(client client.Interface, namespace string, meta metaV1.ObjectMeta,
+	template BackupTemplate) (*Backup, error) {
+	groupVersion, err := common.GetInstalledGroupVersion(client, "backups", meta.Name, VeleroGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	meta.Namespace = namespace
+	object := rawObject{APIVersion: groupVersion, Kind: "Backup", ObjectMeta: meta, Spec: template}
+	var backup rawBackup
+	if err := createRaw(client, groupVersion, namespace, "backups", object, &backup); err != nil {
+		return nil, err
+	}
+
+	result := toBackup(backup)
+	return &result, nil
+}
+
+// GetBackupResources returns resources included in given Velero backup. Velero keeps the list in
+// object storage, it is downloaded through a download request the same way velero backup describe
+// does.
+func GetBackupResources(client client.Interface, namespace, name string) (*BackupResourceList,
+	error) {
+	log.Printf("Getting resources of %s Velero backup in %s namespace", name, namespace)
+
+	groupVersion, err := common.GetInstalledGroupVersion(client, "backups", name, VeleroGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), downloadTimeout)
+	defer cancel()
+
+	url, err := getDownloadURL(ctx, client, groupVersion, namespace, "BackupResourceList", name)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := downloadClient.Do(request.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading resources of %s backup failed with status %s", name,
+			response.Status)
+	}
+
+	// Read one byte over the limit to detect too large resource lists.
+	content, err := ioutil.ReadAll(io.LimitReader(response.Body, int64(maxBackupResourceListSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxBackupResourceListSize {
+		return nil, fmt.Errorf("resource list of %s backup is larger than %d bytes", name,
+			maxBackupResourceListSize)
+	}
+
+	return parseBackupResourceList(bytes.NewReader(content))
+}
+
+// rawDownloadRequest is a subset of the DownloadRequest custom resource used by the dashboard.
+type rawDownloadRequest struct {
+	Status struct {
+		Phase       string `json:"phase"`
+		DownloadURL string `json:"downloadURL"`
+	} `json:"status"`
+}
+
+// getDownloadURL creates download request for given file of a backup and waits until Velero
+// processes it or the context is done. The request is deleted afterwards, Velero would delete it
+// once it expires anyway.
+func getDownloadURL(ctx context.Context, client client.Interface, groupVersion, namespace, kind,
+	name string) (string, error) {
+	object := rawObject{
+		APIVersion: groupVersion,
+		Kind:       "DownloadRequest",
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      getTimestampedName(name, time.Now()),
+			Namespace: namespace,
+		},
+		Spec: map[string]interface{}{"target": map[string]string{"kind": kind, "name": name}},
+	}
+	var request rawDownloadRequest
+	if err := createRaw(client, groupVersion, namespace, "downloadrequests", object,
+		&request); err != nil {
+		return "", err
+	}
+	path := common.GetCustomResourcePath(groupVersion, namespace, "downloadrequests",
+		object.ObjectMeta.Name)
+	defer func() {
+		if err := client.CoreV1().RESTClient().Delete().AbsPath(path).Do().Error(); err != nil {
+			log.Printf("Cannot delete %s download request: %s", object.ObjectMeta.Name, err)
+		}
+	}()
+
+	for request.Status.Phase != "Processed" || request.Status.DownloadURL == "" {
+		select {
+		case <-ctx.Done():
+			return "", k8serrors.NewTimeoutError(
+				fmt.Sprintf("Velero did not process download request of %s backup", name), 0)
+		case <-time.After(downloadPollInterval):
+		}
+
+		raw, err := common.JSONRequest(client.CoreV1().RESTClient().Get().AbsPath(path)).
+			Context(ctx).Do().Raw()
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			return "", err
+		}
+		if err := json.Unmarshal(raw, &request); err != nil {
+			return "", err
+		}
+	}
+	return request.Status.DownloadURL, nil
+}
+
+// parseBackupResourceList parses gzipped JSON resource list of a backup, which maps group version
+// and kind to resources included in the backup.
+func parseBackupResourceList(reader io.Reader) (*BackupResourceList, error) {
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, err
+	}
+	defer gzipReader.Close()
+
+	// Read one byte over the limit to detect too large resource lists.
+	content, err := ioutil.ReadAll(io.LimitReader(gzipReader, int64(maxBackupResourceListJSONSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxBackupResourceListJSONSize {
+		return nil, fmt.Errorf("resource list is larger than %d bytes", maxBackupResourceListJSONSize)
+	}
+
+	resources := map[string][]string{}
+	if err := json.Unmarshal(content, &resources); err != nil {
+		return nil, err
+	}
+
+	result := &BackupResourceList{Resources: make([]BackupResourceGroup, 0)}
+	for resource, items := range resources {
+		sort.Strings(items)
+		result.Resources = append(result.Resources, BackupResourceGroup{Resource: resource, Items: items})
+	}
+	sort.Sort(backupResourceGroups(result.Resources))
+	return result, nil
+}
+
+// backupResourceGroups sorts resource groups of a backup by resource.
+type backupResourceGroups []BackupResourceGroup
+
+func (self backupResourceGroups) Len() int           { return len(self) }
+func (self backupResourceGroups) Swap(i, j int)      { self[i], self[j] = self[j], self[i] }
+func (self backupResourceGroups) Less(i, j int) bool { return self[i].Resource < self[j].Resource }
+
+func getBackups(client client.Interface, nsQuery *common.NamespaceQuery) ([]rawBackup, error) {
+	groupVersion, found, err := common.GetGroupVersion(client, VeleroGroup)
+	if err != nil || !found {
+		return make([]rawBackup, 0), err
+	}
+
+	var list rawBackupList
+	if err := common.GetCustomResource(client, groupVersion, nsQuery.ToRequestParam(), "backups",
+		"", &list); err != nil {
+		return nil, err
+	}
+
+	backups := make([]rawBackup, 0)
+	for _, backup := range list.Items {
+		if nsQuery.Matches(backup.ObjectMeta.Namespace) {
+			backups = append(backups, backup)
+		}
+	}
+	return backups, nil
+}
+
+func filterBackupsBySchedule(backups []rawBackup, schedule string) []rawBackup {
+	result := make([]rawBackup, 0)
+	for _, backup := range backups {
+		if backup.ObjectMeta.Labels[scheduleNameLabel] == schedule {
+			result = append(result, backup)
+		}
+	}
+	return result
+}
+
+func toBackupList(backups []rawBackup, dsQuery *dataselect.DataSelectQuery) *BackupList {
+	backupList := &BackupList{
+		Backups:  make([]Backup, 0),
+		ListMeta: api.ListMeta{TotalItems: len(backups)},
+	}
+
+	backupCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toBackupCells(backups), dsQuery)
+	backups = fromBackupCells(backupCells)
+	backupList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, backupList.ListMeta.TotalItems)
+
+	for _, backup := range backups {
+		backupList.Backups = append(backupList.Backups, toBackup(backup))
+	}
+
+	return backupList
+}
+
+func toBackup(backup rawBackup) Backup {
+	return Backup{
+		ObjectMeta:          api.NewObjectMeta(backup.ObjectMeta),
+		TypeMeta:            api.NewTypeMeta(api.ResourceKindBackup),
+		Phase:               backup.Status.Phase,
+		Schedule:            backup.ObjectMeta.Labels[scheduleNameLabel],
+		Spec:                backup.Spec,
+		StartTimestamp:      backup.Status.StartTimestamp,
+		CompletionTimestamp: backup.Status.CompletionTimestamp,
+		Expiration:          backup.Status.Expiration,
+		TotalItems:          backup.Status.Progress.TotalItems,
+		ItemsBackedUp:       backup.Status.Progress.ItemsBackedUp,
+		Errors:              backup.Status.Errors,
+		Warnings:            backup.Status.Warnings,
+		FailureReason:       backup.Status.FailureReason,
+	}
+}
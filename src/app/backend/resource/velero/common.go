@@ -0,0 +1,272 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+)
+
+// VeleroGroup is the API group of Velero custom resources.
+const VeleroGroup = "velero.io"
+
+// scheduleNameLabel is the label Velero puts on backups created by a schedule.
+const scheduleNameLabel = "velero.io/schedule-name"
+
+// nameTimestampFormat is the format of timestamps Velero CLI appends to names of backups created
+// from schedules and of restores, e.g. daily-20170505100000.
+const nameTimestampFormat = "20060102150405"
+
+// createRaw creates given Velero resource and unmarshals the created resource into result.
+func createRaw(client client.Interface, groupVersion, namespace, resource string,
+	object interface{}, result interface{}) error {
+	body, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+
+	request := client.CoreV1().RESTClient().Post().
+		AbsPath(common.GetCustomResourcePath(groupVersion, namespace, resource, ""))
+	raw, err := common.JSONRequest(common.JSONBody(request, body)).Do().Raw()
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, result)
+}
+
+// getTimestampedName returns name of the resource created from another one at given time, e.g. of
+// a backup created from daily schedule.
+func getTimestampedName(name string, now time.Time) string {
+	return name + "-" + now.UTC().Format(nameTimestampFormat)
+}
+
+// BackupTemplate describes what a backup contains. Backups and templates of schedules share it.
+type BackupTemplate struct {
+	// Namespaces to include in the backup, all namespaces are included when empty.
+	IncludedNamespaces []string `json:"includedNamespaces,omitempty"`
+	ExcludedNamespaces []string `json:"excludedNamespaces,omitempty"`
+
+	// Resources to include in the backup, e.g. deployments. All resources are included when empty.
+	IncludedResources []string `json:"includedResources,omitempty"`
+	ExcludedResources []string `json:"excludedResources,omitempty"`
+
+	// Only resources matching the selector are included when it is set.
+	LabelSelector *metaV1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// How long the backup is kept, e.g. 720h0m0s. Velero default is used when empty.
+	TTL string `json:"ttl,omitempty"`
+
+	// Name of the backup storage location, default location is used when empty.
+	StorageLocation string `json:"storageLocation,omitempty"`
+
+	// Whether volume snapshots are taken, Velero default is used when nil.
+	SnapshotVolumes *bool `json:"snapshotVolumes,omitempty"`
+}
+
+// rawObject is the envelope of Velero resources sent to the API server on creation.
+type rawObject struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	ObjectMeta metaV1.ObjectMeta `json:"metadata"`
+	Spec       interface{}       `json:"spec"`
+}
+
+// rawBackup is a subset of the Backup custom resource used by the dashboard.
+type rawBackup struct {
+	ObjectMeta metaV1.ObjectMeta `json:"metadata"`
+	Spec       BackupTemplate    `json:"spec"`
+	Status     struct {
+		Phase               string       `json:"phase"`
+		StartTimestamp      *metaV1.Time `json:"startTimestamp"`
+		CompletionTimestamp *metaV1.Time `json:"completionTimestamp"`
+		Expiration          *metaV1.Time `json:"expiration"`
+		Errors              int          `json:"errors"`
+		Warnings            int          `json:"warnings"`
+		FailureReason       string       `json:"failureReason"`
+		Progress            struct {
+			TotalItems    int `json:"totalItems"`
+			ItemsBackedUp int `json:"itemsBackedUp"`
+		} `json:"progress"`
+	} `json:"status"`
+}
+
+type rawBackupList struct {
+	Items []rawBackup `json:"items"`
+}
+
+// rawRestoreSpec is a subset of the spec of Restore custom resource used by the dashboard.
+type rawRestoreSpec struct {
+	BackupName         string            `json:"backupName"`
+	IncludedNamespaces []string          `json:"includedNamespaces,omitempty"`
+	ExcludedNamespaces []string          `json:"excludedNamespaces,omitempty"`
+	NamespaceMapping   map[string]string `json:"namespaceMapping,omitempty"`
+	RestorePVs         *bool             `json:"restorePVs,omitempty"`
+}
+
+// rawRestore is a subset of the Restore custom resource used by the dashboard.
+type rawRestore struct {
+	ObjectMeta metaV1.ObjectMeta `json:"metadata"`
+	Spec       rawRestoreSpec    `json:"spec"`
+	Status     struct {
+		Phase               string       `json:"phase"`
+		StartTimestamp      *metaV1.Time `json:"startTimestamp"`
+		CompletionTimestamp *metaV1.Time `json:"completionTimestamp"`
+		Errors              int          `json:"errors"`
+		Warnings            int          `json:"warnings"`
+		FailureReason       string       `json:"failureReason"`
+	} `json:"status"`
+}
+
+type rawRestoreList struct {
+	Items []rawRestore `json:"items"`
+}
+
+// rawSchedule is a subset of the Schedule custom resource used by the dashboard.
+type rawSchedule struct {
+	ObjectMeta metaV1.ObjectMeta `json:"metadata"`
+	Spec       struct {
+		Schedule string         `json:"schedule"`
+		Template BackupTemplate `json:"template"`
+		Paused   bool           `json:"paused"`
+	} `json:"spec"`
+	Status struct {
+		Phase      string       `json:"phase"`
+		LastBackup *metaV1.Time `json:"lastBackup"`
+	} `json:"status"`
+}
+
+type rawScheduleList struct {
+	Items []rawSchedule `json:"items"`
+}
+
+// The code below allows to perform complex data section on []rawBackup.
+
+type BackupCell rawBackup
+
+func (self BackupCell) GetProperty(name dataselect.PropertyName) dataselect.ComparableValue {
+	switch name {
+	case dataselect.NameProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Name)
+	case dataselect.CreationTimestampProperty:
+		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
+	case dataselect.NamespaceProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.UIDProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.UID)
+	case dataselect.StatusProperty:
+		return dataselect.StdComparableString(self.Status.Phase)
+	default:
+		// if name is not supported then just return a constant dummy value, sort will have no effect.
+		return nil
+	}
+}
+
+func toBackupCells(std []rawBackup) []dataselect.DataCell {
+	cells := make([]dataselect.DataCell, len(std))
+	for i := range std {
+		cells[i] = BackupCell(std[i])
+	}
+	return cells
+}
+
+func fromBackupCells(cells []dataselect.DataCell) []rawBackup {
+	std := make([]rawBackup, len(cells))
+	for i := range std {
+		std[i] = rawBackup(cells[i].(BackupCell))
+	}
+	return std
+}
+
+// The code below allows to perform complex data section on []rawRestore.
+
+type RestoreCell rawRestore
+
+func (self RestoreCell) GetProperty(name dataselect.PropertyName) dataselect.ComparableValue {
+	switch name {
+	case dataselect.NameProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Name)
+	case dataselect.CreationTimestampProperty:
+		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
+	case dataselect.NamespaceProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.UIDProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.UID)
+	case dataselect.StatusProperty:
+		return dataselect.StdComparableString(self.Status.Phase)
+	default:
+		// if name is not supported then just return a constant dummy value, sort will have no effect.
+		return nil
+	}
+}
+
+func toRestoreCells(std []rawRestore) []dataselect.DataCell {
+	cells := make([]dataselect.DataCell, len(std))
+	for i := range std {
+		cells[i] = RestoreCell(std[i])
+	}
+	return cells
+}
+
+func fromRestoreCells(cells []dataselect.DataCell) []rawRestore {
+	std := make([]rawRestore, len(cells))
+	for i := range std {
+		std[i] = rawRestore(cells[i].(RestoreCell))
+	}
+	return std
+}
+
+// The code below allows to perform complex data section on []rawSchedule.
+
+type ScheduleCell rawSchedule
+
+func (self ScheduleCell) GetProperty(name dataselect.PropertyName) dataselect.ComparableValue {
+	switch name {
+	case dataselect.NameProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Name)
+	case dataselect.CreationTimestampProperty:
+		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
+	case dataselect.NamespaceProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.UIDProperty:
+		return dataselect.StdComparableString(self.ObjectMeta.UID)
+	case dataselect.StatusProperty:
+		return dataselect.StdComparableString(self.Status.Phase)
+	default:
+		// if name is not supported then just return a constant dummy value, sort will have no effect.
+		return nil
+	}
+}
+
+func toScheduleCells(std []rawSchedule) []dataselect.DataCell {
+	cells := make([]dataselect.DataCell, len(std))
+	for i := range std {
+		cells[i] = ScheduleCell(std[i])
+	}
+	return cells
+}
+
+func fromScheduleCells(cells []dataselect.DataCell) []rawSchedule {
+	std := make([]rawSchedule, len(cells))
+	for i := range std {
+		std[i] = rawSchedule(cells[i].(ScheduleCell))
+	}
+	return std
+}
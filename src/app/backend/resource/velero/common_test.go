@@ -0,0 +1,185 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestToBackupList(t *testing.T) {
+	raw := `{"items": [{
+		"metadata": {"name": "daily-20170505100000", "namespace": "velero",
+			"labels": {"velero.io/schedule-name": "daily"}},
+		"spec": {"includedNamespaces": ["default"], "ttl": "720h0m0s", "storageLocation": "aws"},
+		"status": {"phase": "PartiallyFailed", "errors": 2, "warnings": 1,
+			"startTimestamp": "2017-05-05T10:00:00Z", "completionTimestamp": "2017-05-05T10:05:00Z",
+			"progress": {"totalItems": 10, "itemsBackedUp": 8}}
+	}]}`
+
+	var list rawBackupList
+	if err := json.Unmarshal([]byte(raw), &list); err != nil {
+		t.Fatalf("json.Unmarshal(%#v) returned error: %s", raw, err)
+	}
+	start := metaV1.NewTime(time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC).Local())
+	completion := metaV1.NewTime(time.Date(2017, 5, 5, 10, 5, 0, 0, time.UTC).Local())
+	expected := &BackupList{
+		ListMeta: api.ListMeta{TotalItems: 1, TotalBeforeFilter: 1},
+		Backups: []Backup{{
+			ObjectMeta: api.ObjectMeta{Name: "daily-20170505100000", Namespace: "velero",
+				Labels: map[string]string{"velero.io/schedule-name": "daily"}},
+			TypeMeta: api.TypeMeta{Kind: api.ResourceKindBackup},
+			Phase:    "PartiallyFailed",
+			Schedule: "daily",
+			Spec: BackupTemplate{
+				IncludedNamespaces: []string{"default"},
+				TTL:                "720h0m0s",
+				StorageLocation:    "aws",
+			},
+			StartTimestamp:      &start,
+			CompletionTimestamp: &completion,
+			TotalItems:          10,
+			ItemsBackedUp:       8,
+			Errors:              2,
+			Warnings:            1,
+		}},
+	}
+
+	actual := toBackupList(list.Items, dataselect.NoDataSelect)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toBackupList(%#v) == \ngot: %#v, \nexpected %#v", list.Items, actual, expected)
+	}
+}
+
+func TestParseBackupResourceList(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	writer.Write([]byte(`{"v1/Pod": ["default/web-2", "default/web-1"], "apps/v1/Deployment": ["default/web"]}`))
+	writer.Close()
+
+	expected := &BackupResourceList{Resources: []BackupResourceGroup{
+		{Resource: "apps/v1/Deployment", Items: []string{"default/web"}},
+		{Resource: "v1/Pod", Items: []string{"default/web-1", "default/web-2"}},
+	}}
+
+	actual, err := parseBackupResourceList(&buffer)
+	if err != nil {
+		t.Fatalf("parseBackupResourceList() returned error: %s", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("parseBackupResourceList() == \ngot: %#v, \nexpected %#v", actual, expected)
+	}
+
+	if _, err := parseBackupResourceList(bytes.NewBufferString("{}")); err == nil {
+		t.Errorf("parseBackupResourceList() with not gzipped input returned no error")
+	}
+
+	defer func(size int) { maxBackupResourceListJSONSize = size }(maxBackupResourceListJSONSize)
+	maxBackupResourceListJSONSize = 16
+	buffer.Reset()
+	writer = gzip.NewWriter(&buffer)
+	writer.Write([]byte(`{"v1/Pod": ["default/web-1"]}`))
+	writer.Close()
+	if _, err := parseBackupResourceList(&buffer); err == nil {
+		t.Errorf("parseBackupResourceList() with too large resource list returned no error")
+	}
+}
+
+func TestGetBackupResourcesTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/apis" {
+			w.Write([]byte(`{"kind": "APIGroupList", "groups": [
+				{"name": "velero.io", "preferredVersion": {"groupVersion": "velero.io/v1"}}]}`))
+			return
+		}
+		// Velero never processes the download request.
+		w.Write([]byte(`{"status": {}}`))
+	}))
+	defer server.Close()
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("Cannot create client: %v", err)
+	}
+
+	defer func(timeout, interval time.Duration) {
+		downloadTimeout, downloadPollInterval = timeout, interval
+	}(downloadTimeout, downloadPollInterval)
+	downloadTimeout, downloadPollInterval = 100*time.Millisecond, 10*time.Millisecond
+
+	start := time.Now()
+	if _, err := GetBackupResources(client, "velero", "daily"); !k8serrors.IsTimeout(err) {
+		t.Errorf("GetBackupResources() of not processed download request returns %v, expected timeout",
+			err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GetBackupResources() took %s, expected at most %s", elapsed, downloadTimeout)
+	}
+}
+
+func TestGetDetailNotInstalled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/apis" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"kind": "APIGroupList", "groups": []}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("Cannot create client: %v", err)
+	}
+
+	if _, err := GetBackupDetail(client, "velero", "daily"); !k8serrors.IsNotFound(err) {
+		t.Errorf("GetBackupDetail() without Velero returns %v, expected not found", err)
+	}
+	if _, err := GetRestoreDetail(client, "velero", "daily"); !k8serrors.IsNotFound(err) {
+		t.Errorf("GetRestoreDetail() without Velero returns %v, expected not found", err)
+	}
+	if _, err := GetScheduleDetail(client, "velero", "daily"); !k8serrors.IsNotFound(err) {
+		t.Errorf("GetScheduleDetail() without Velero returns %v, expected not found", err)
+	}
+}
+
+func TestGetScheduleBackupMeta(t *testing.T) {
+	schedule := rawSchedule{ObjectMeta: metaV1.ObjectMeta{Name: "daily", Labels: map[string]string{"team": "ops"}}}
+	expected := metaV1.ObjectMeta{
+		Name:   "daily-20170505100000",
+		Labels: map[string]string{"team": "ops", "velero.io/schedule-name": "daily"},
+	}
+
+	actual := getScheduleBackupMeta(schedule, time.Date(2017, 5, 5, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60)))
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getScheduleBackupMeta(%#v) == \ngot: %#v, \nexpected %#v", schedule, actual, expected)
+	}
+	if len(schedule.ObjectMeta.Labels) != 1 {
+		t.Errorf("getScheduleBackupMeta() modified labels of the schedule: %#v", schedule.ObjectMeta.Labels)
+	}
+}
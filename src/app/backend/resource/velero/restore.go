@@ -0,0 +1,216 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"log"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+)
+
+// Restore is a presentation layer view of Velero Restore resource.
+type Restore struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	// Phase of the restore, e.g. InProgress, Completed or PartiallyFailed.
+	Phase string `json:"phase"`
+
+	// Name of the backup the restore is created from.
+	BackupName string `json:"backupName"`
+
+	IncludedNamespaces []string `json:"includedNamespaces"`
+	ExcludedNamespaces []string `json:"excludedNamespaces"`
+
+	// Namespaces restored under different name, by name of the namespace in the backup.
+	NamespaceMapping map[string]string `json:"namespaceMapping"`
+
+	StartTimestamp      *metaV1.Time `json:"startTimestamp"`
+	CompletionTimestamp *metaV1.Time `json:"completionTimestamp"`
+
+	Errors        int    `json:"errors"`
+	Warnings      int    `json:"warnings"`
+	FailureReason string `json:"failureReason,omitempty"`
+}
+
+// RestoreList contains a list of Velero restores.
+type RestoreList struct {
+	ListMeta api.ListMeta `json:"listMeta"`
+	Restores []Restore    `json:"restores"`
+}
+
+// RestoreSpec contains information needed to create a restore.
+type RestoreSpec struct {
+	// Name of the restore. Generated from the backup name when empty, the same way Velero CLI does.
+	Name string `json:"name"`
+
+	// Namespace of Velero, where the restore is created.
+	Namespace string `json:"namespace"`
+
+	// Name of the backup to restore.
+	BackupName string `json:"backupName"`
+
+	// Namespaces to restore, all namespaces of the backup are restored when empty.
+	IncludedNamespaces []string `json:"includedNamespaces"`
+	ExcludedNamespaces []string `json:"excludedNamespaces"`
+
+	// Namespaces to restore under different name, by name of the namespace in the backup.
+	NamespaceMapping map[string]string `json:"namespaceMapping"`
+
+	// Whether persistent volumes are restored from snapshots, Velero default is used when nil.
+	RestorePVs *bool `json:"restorePVs"`
+}
+
+// GetRestoreList returns a list of Velero restores. Returns empty list when Velero is not
+// installed.
+func GetRestoreList(client client.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*RestoreList, error) {
+	log.Print("Getting list of Velero restores")
+
+	restores, err := getRestores(client, nsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	return toRestoreList(restores, dsQuery), nil
+}
+
+// GetRestoreDetail returns Velero restore with given name.
+func GetRestoreDetail(client client.Interface, namespace, name string) (*Restore, error) {
+	log.Printf("Getting details of %s Velero restore in %s namespace", name, namespace)
+
+	groupVersion, err := common.GetInstalledGroupVersion(client, "restores", name, VeleroGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var restore rawRestore
+	if err := common.GetCustomResource(client, groupVersion, namespace, "restores", name,
+		&restore); err != nil {
+		return nil, err
+	}
+
+	result := toRestore(restore)
+	return &result, nil
+}
+
+// CreateRestore creates Velero restore of a backup based on given specification.
+func CreateRestore(client client.Interface, spec *RestoreSpec) (*Restore, error) {
+	if spec.BackupName == "" {
+		return nil, k8serrors.NewBadRequest("Name of the backup to restore is required")
+	}
+	name := spec.Name
+	if name == "" {
+		name = getTimestampedName(spec.BackupName, time.Now())
+	}
+	log.Printf("Creating %s Velero restore of %s backup in %s namespace", name, spec.BackupName,
+		spec.Namespace)
+
+	groupVersion, err := common.GetInstalledGroupVersion(client, "restores", name, VeleroGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	object := rawObject{
+		APIVersion: groupVersion,
+		Kind:       "Restore",
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: spec.Namespace},
+		Spec: rawRestoreSpec{
+			BackupName:         spec.BackupName,
+			IncludedNamespaces: spec.IncludedNamespaces,
+			ExcludedNamespaces: spec.ExcludedNamespaces,
+			NamespaceMapping:   spec.NamespaceMapping,
+			RestorePVs:         spec.RestorePVs,
+		},
+	}
+	var restore rawRestore
+	if err := createRaw(client, groupVersion, spec.Namespace, "restores", object, &restore); err != nil {
+		return nil, err
+	}
+
+	result := toRestore(restore)
+	return &result, nil
+}
+
+func getRestores(client client.Interface, nsQuery *common.NamespaceQuery) ([]rawRestore, error) {
+	groupVersion, found, err := common.GetGroupVersion(client, VeleroGroup)
+	if err != nil || !found {
+		return make([]rawRestore, 0), err
+	}
+
+	var list rawRestoreList
+	if err := common.GetCustomResource(client, groupVersion, nsQuery.ToRequestParam(), "restores",
+		"", &list); err != nil {
+		return nil, err
+	}
+
+	restores := make([]rawRestore, 0)
+	for _, restore := range list.Items {
+		if nsQuery.Matches(restore.ObjectMeta.Namespace) {
+			restores = append(restores, restore)
+		}
+	}
+	return restores, nil
+}
+
+func filterRestoresByBackup(restores []rawRestore, backup string) []rawRestore {
+	result := make([]rawRestore, 0)
+	for _, restore := range restores {
+		if restore.Spec.BackupName == backup {
+			result = append(result, restore)
+		}
+	}
+	return result
+}
+
+func toRestoreList(restores []rawRestore, dsQuery *dataselect.DataSelectQuery) *RestoreList {
+	restoreList := &RestoreList{
+		Restores: make([]Restore, 0),
+		ListMeta: api.ListMeta{TotalItems: len(restores)},
+	}
+
+	restoreCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toRestoreCells(restores), dsQuery)
+	restores = fromRestoreCells(restoreCells)
+	restoreList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, restoreList.ListMeta.TotalItems)
+
+	for _, restore := range restores {
+		restoreList.Restores = append(restoreList.Restores, toRestore(restore))
+	}
+
+	return restoreList
+}
+
+func toRestore(restore rawRestore) Restore {
+	return Restore{
+		ObjectMeta:          api.NewObjectMeta(restore.ObjectMeta),
+		TypeMeta:            api.NewTypeMeta(api.ResourceKindRestore),
+		Phase:               restore.Status.Phase,
+		BackupName:          restore.Spec.BackupName,
+		IncludedNamespaces:  restore.Spec.IncludedNamespaces,
+		ExcludedNamespaces:  restore.Spec.ExcludedNamespaces,
+		NamespaceMapping:    restore.Spec.NamespaceMapping,
+		StartTimestamp:      restore.Status.StartTimestamp,
+		CompletionTimestamp: restore.Status.CompletionTimestamp,
+		Errors:              restore.Status.Errors,
+		Warnings:            restore.Status.Warnings,
+		FailureReason:       restore.Status.FailureReason,
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package velero
+
+import (
+	"log"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+)
+
+// Schedule is a presentation layer view of Velero Schedule resource.
+type Schedule struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+
+	// Cron schedule of backups, e.g. @daily.
+	Schedule string `json:"schedule"`
+
+	// True when no backups are created by the schedule.
+	Paused bool `json:"paused"`
+
+	// Phase of the schedule, e.g. Enabled or FailedValidation.
+	Phase string `json:"phase"`
+
+	// Time of the last backup created by the schedule.
+	LastBackup *metaV1.Time `json:"lastBackup"`
+
+	// Template of backups created by the schedule.
+	Template BackupTemplate `json:"template"`
+}
+
+// ScheduleList contains a list of Velero schedules.
+type ScheduleList struct {
+	ListMeta  api.ListMeta `json:"listMeta"`
+	Schedules []Schedule   `json:"schedules"`
+}
+
+// ScheduleDetail contains schedule and backups created by it.
+type ScheduleDetail struct {
+	Schedule
+	BackupList BackupList `json:"backupList"`
+}
+
+// GetScheduleList returns a list of Velero schedules. Returns empty list when Velero is not
+// installed.
+func GetScheduleList(client client.Interface, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*ScheduleList, error) {
+	log.Print("Getting list of Velero schedules")
+
+	scheduleList := &ScheduleList{Schedules: make([]Schedule, 0)}
+
+	groupVersion, found, err := common.GetGroupVersion(client, VeleroGroup)
+	if err != nil || !found {
+		return scheduleList, err
+	}
+
+	var list rawScheduleList
+	if err := common.GetCustomResource(client, groupVersion, nsQuery.ToRequestParam(), "schedules",
+		"", &list); err != nil {
+		return nil, err
+	}
+
+	schedules := make([]rawSchedule, 0)
+	for _, schedule := range list.Items {
+		if nsQuery.Matches(schedule.ObjectMeta.Namespace) {
+			schedules = append(schedules, schedule)
+		}
+	}
+
+	return toScheduleList(schedules, dsQuery), nil
+}
+
+// GetScheduleDetail returns Velero schedule with given name along with backups created by it.
+func GetScheduleDetail(client client.Interface, namespace, name string) (*ScheduleDetail, error) {
+	log.Printf("Getting details of %s Velero schedule in %s namespace", name, namespace)
+
+	schedule, err := getSchedule(client, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	backups, err := getBackups(client, common.NewSameNamespaceQuery(namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ScheduleDetail{
+		Schedule:   toSchedule(*schedule),
+		BackupList: *toBackupList(filterBackupsBySchedule(backups, name), dataselect.DefaultDataSelect),
+	}, nil
+}
+
+// TriggerSchedule creates a backup from the template of given Velero schedule right away, the same
+// way velero backup create --from-schedule does.
+func TriggerSchedule(client client.Interface, namespace, name string) (*Backup, error) {
+	log.Printf("Triggering %s Velero schedule in %s namespace", name, namespace)
+
+	schedule, err := getSchedule(client, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return createBackup(client, namespace, getScheduleBackupMeta(*schedule, time.Now()),
+		schedule.Spec.Template)
+}
+
+func getSchedule(client client.Interface, namespace, name string) (*rawSchedule, error) {
+	groupVersion, err := common.GetInstalledGroupVersion(client, "schedules", name, VeleroGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var schedule rawSchedule
+	if err := common.GetCustomResource(client, groupVersion, namespace, "schedules", name,
+		&schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// getScheduleBackupMeta returns metadata of the backup created from given schedule at given time.
+// Labels of the schedule are kept, so that backups can be told apart the same way as scheduled ones.
+func getScheduleBackupMeta(schedule rawSchedule, now time.Time) metaV1.ObjectMeta {
+	labels := map[string]string{}
+	for key, value := range schedule.ObjectMeta.Labels {
+		labels[key] = value
+	}
+	labels[scheduleNameLabel] = schedule.ObjectMeta.Name
+
+	return metaV1.ObjectMeta{
+		Name:   getTimestampedName(schedule.ObjectMeta.Name, now),
+		Labels: labels,
+	}
+}
+
+func toScheduleList(schedules []rawSchedule, dsQuery *dataselect.DataSelectQuery) *ScheduleList {
+	scheduleList := &ScheduleList{
+		Schedules: make([]Schedule, 0),
+		ListMeta:  api.ListMeta{TotalItems: len(schedules)},
+	}
+
+	scheduleCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toScheduleCells(schedules), dsQuery)
+	schedules = fromScheduleCells(scheduleCells)
+	scheduleList.ListMeta = dataselect.NewListMeta(dsQuery, filteredTotal, scheduleList.ListMeta.TotalItems)
+
+	for _, schedule := range schedules {
+		scheduleList.Schedules = append(scheduleList.Schedules, toSchedule(schedule))
+	}
+
+	return scheduleList
+}
+
+func toSchedule(schedule rawSchedule) Schedule {
+	return Schedule{
+		ObjectMeta: api.NewObjectMeta(schedule.ObjectMeta),
+		TypeMeta:   api.NewTypeMeta(api.ResourceKindSchedule),
+		Schedule:   schedule.Spec.Schedule,
+		Paused:     schedule.Spec.Paused,
+		Phase:      schedule.Status.Phase,
+		LastBackup: schedule.Status.LastBackup,
+		Template:   schedule.Spec.Template,
+	}
+}
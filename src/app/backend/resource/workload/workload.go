@@ -17,7 +17,7 @@ package workload
 import (
 	"log"
 
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/daemonset"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
@@ -48,7 +48,7 @@ type Workloads struct {
 }
 
 // GetWorkloads returns a list of all workloads in the cluster.
-func GetWorkloads(client *kubernetes.Clientset, heapsterClient heapster.HeapsterClient,
+func GetWorkloads(client *kubernetes.Clientset, heapsterClient metricapi.MetricClient,
 	nsQuery *common.NamespaceQuery, dsQuery *dataselect.DataSelectQuery) (*Workloads, error) {
 
 	log.Print("Getting lists of all workloads")
@@ -60,8 +60,8 @@ func GetWorkloads(client *kubernetes.Clientset, heapsterClient heapster.Heapster
 		DeploymentList:            common.GetDeploymentListChannel(client, nsQuery, 1),
 		StatefulSetList:           common.GetStatefulSetListChannel(client, nsQuery, 1),
 		ServiceList:               common.GetServiceListChannel(client, nsQuery, 1),
-		PodList:                   common.GetPodListChannel(client, nsQuery, 7),
-		EventList:                 common.GetEventListChannel(client, nsQuery, 7),
+		PodList:                   common.GetFanOutPodListChannel(client, nsQuery, 7),
+		EventList:                 common.GetWarningEventListChannel(client, nsQuery, 7),
 	}
 
 	return GetWorkloadsFromChannels(channels, heapsterClient, dsQuery)
@@ -70,7 +70,7 @@ func GetWorkloads(client *kubernetes.Clientset, heapsterClient heapster.Heapster
 // GetWorkloadsFromChannels returns a list of all workloads in the cluster, from the
 // channel sources.
 func GetWorkloadsFromChannels(channels *common.ResourceChannels,
-	heapsterClient heapster.HeapsterClient, dsQuery *dataselect.DataSelectQuery) (*Workloads,
+	heapsterClient metricapi.MetricClient, dsQuery *dataselect.DataSelectQuery) (*Workloads,
 	error) {
 
 	rsChan := make(chan *replicaset.ReplicaSetList)
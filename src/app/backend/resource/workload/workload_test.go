@@ -173,32 +173,37 @@ func TestGetWorkloadsFromChannels(t *testing.T) {
 	for _, c := range cases {
 		expected := &Workloads{
 			ReplicationControllerList: replicationcontroller.ReplicationControllerList{
-				ListMeta:               api.ListMeta{TotalItems: len(c.rcs)},
+				ListMeta:               api.ListMeta{TotalItems: len(c.rcs), TotalBeforeFilter: len(c.rcs), Page: 1, ItemsPerPage: 10},
+				Status:                 common.ResourceStatus{Running: len(c.rcs)},
 				CumulativeMetrics:      make([]metric.Metric, 0),
 				ReplicationControllers: c.rcs,
 			},
 			ReplicaSetList: replicaset.ReplicaSetList{
-				ListMeta:          api.ListMeta{TotalItems: len(c.rs)},
+				ListMeta:          api.ListMeta{TotalItems: len(c.rs), TotalBeforeFilter: len(c.rs), Page: 1, ItemsPerPage: 10},
+				Status:            common.ResourceStatus{Running: len(c.rs)},
 				CumulativeMetrics: make([]metric.Metric, 0),
 				ReplicaSets:       c.rs,
 			},
 			JobList: job.JobList{
-				ListMeta:          api.ListMeta{TotalItems: len(c.jobs)},
+				ListMeta:          api.ListMeta{TotalItems: len(c.jobs), TotalBeforeFilter: len(c.jobs), Page: 1, ItemsPerPage: 10},
+				Status:            common.ResourceStatus{Running: len(c.jobs)},
 				CumulativeMetrics: make([]metric.Metric, 0),
 				Jobs:              c.jobs,
 			},
 			DaemonSetList: daemonset.DaemonSetList{
-				ListMeta:          api.ListMeta{TotalItems: len(c.daemonset)},
+				ListMeta:          api.ListMeta{TotalItems: len(c.daemonset), TotalBeforeFilter: len(c.daemonset), Page: 1, ItemsPerPage: 10},
+				Status:            common.ResourceStatus{Running: len(c.daemonset)},
 				CumulativeMetrics: make([]metric.Metric, 0),
 				DaemonSets:        c.daemonset,
 			},
 			DeploymentList: deployment.DeploymentList{
-				ListMeta:          api.ListMeta{TotalItems: len(c.deployment)},
+				ListMeta:          api.ListMeta{TotalItems: len(c.deployment), TotalBeforeFilter: len(c.deployment), Page: 1, ItemsPerPage: 10},
+				Status:            common.ResourceStatus{Running: len(c.deployment)},
 				CumulativeMetrics: make([]metric.Metric, 0),
 				Deployments:       c.deployment,
 			},
 			PodList: pod.PodList{
-				ListMeta: api.ListMeta{TotalItems: len(c.pod)},
+				ListMeta: api.ListMeta{TotalItems: len(c.pod), TotalBeforeFilter: len(c.pod), Page: 1, ItemsPerPage: 10},
 				CumulativeMetrics: []metric.Metric{
 					{
 						DataPoints: metric.DataPoints{},
@@ -216,7 +221,8 @@ func TestGetWorkloadsFromChannels(t *testing.T) {
 				Pods: c.pod,
 			},
 			StatefulSetList: statefulset.StatefulSetList{
-				ListMeta:          api.ListMeta{TotalItems: len(c.statefulSet)},
+				ListMeta:          api.ListMeta{TotalItems: len(c.statefulSet), TotalBeforeFilter: len(c.statefulSet), Page: 1, ItemsPerPage: 10},
+				Status:            common.ResourceStatus{Running: len(c.statefulSet)},
 				CumulativeMetrics: make([]metric.Metric, 0),
 				StatefulSets:      c.statefulSet,
 			},
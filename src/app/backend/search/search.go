@@ -15,7 +15,10 @@
 package search
 
 import (
-	"github.com/kubernetes/dashboard/src/app/backend/integration/metric/heapster"
+	"log"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	metricapi "github.com/kubernetes/dashboard/src/app/backend/integration/metric/api"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/cluster"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/config"
@@ -44,6 +47,12 @@ import (
 
 // SearchResult is a list of resources matching search criteria found in whole cluster.
 type SearchResult struct {
+	// Total number of matching resources of all kinds.
+	ListMeta api.ListMeta `json:"listMeta"`
+
+	// Number of matching resources by kind, before pagination. Kinds without matches are
+	// omitted.
+	Counts map[api.ResourceKind]int `json:"counts"`
 
 	// Cluster.
 	NamespaceList        namespace.NamespaceList               `json:"namespaceList"`
@@ -73,31 +82,77 @@ type SearchResult struct {
 	// TODO(maciaszczykm): Third party resources.
 }
 
-func Search(client *kubernetes.Clientset, heapsterClient heapster.HeapsterClient,
-	nsQuery *common.NamespaceQuery,
-	dsQuery *dataselect.DataSelectQuery) (*SearchResult, error) {
-
-	clusterResources, err := cluster.GetCluster(client, dsQuery, &heapsterClient)
-	if err != nil {
-		return &SearchResult{}, err
+// NewSearchQuery returns copy of given data select query that additionally keeps only resources
+// with name containing given search term. Query is returned as it is for empty term.
+func NewSearchQuery(term string, dsQuery *dataselect.DataSelectQuery) *dataselect.DataSelectQuery {
+	if len(term) == 0 {
+		return dsQuery
 	}
 
-	configResources, err := config.GetConfig(client, nsQuery, dsQuery)
-	if err != nil {
-		return &SearchResult{}, err
+	filterByList := []dataselect.FilterBy{}
+	if dsQuery.FilterQuery != nil {
+		filterByList = append(filterByList, dsQuery.FilterQuery.FilterByList...)
 	}
+	filterByList = append(filterByList, dataselect.FilterBy{
+		Property: dataselect.NameProperty,
+		Value:    dataselect.StdComparableString(term),
+	})
+
+	query := *dsQuery
+	query.FilterQuery = &dataselect.FilterQuery{FilterByList: filterByList}
+	return &query
+}
 
-	discoveryResources, err := discovery.GetDiscovery(client, nsQuery, dsQuery)
-	if err != nil {
-		return &SearchResult{}, err
+// Search returns resources of all kinds matching given data select query. Resource categories are
+// fetched concurrently.
+func Search(client *kubernetes.Clientset, heapsterClient metricapi.MetricClient,
+	nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*SearchResult, error) {
+	log.Print("Searching resources of all kinds")
+
+	clusterChan := make(chan *cluster.Cluster, 1)
+	configChan := make(chan *config.Config, 1)
+	discoveryChan := make(chan *discovery.Discovery, 1)
+	workloadsChan := make(chan *workload.Workloads, 1)
+	numErrs := 4
+	errChan := make(chan error, numErrs)
+
+	go func() {
+		items, err := cluster.GetCluster(client, dsQuery, &heapsterClient)
+		errChan <- err
+		clusterChan <- items
+	}()
+
+	go func() {
+		items, err := config.GetConfig(client, nsQuery, dsQuery)
+		errChan <- err
+		configChan <- items
+	}()
+
+	go func() {
+		items, err := discovery.GetDiscovery(client, nsQuery, dsQuery)
+		errChan <- err
+		discoveryChan <- items
+	}()
+
+	go func() {
+		items, err := workload.GetWorkloads(client, heapsterClient, nsQuery, dsQuery)
+		errChan <- err
+		workloadsChan <- items
+	}()
+
+	for i := 0; i < numErrs; i++ {
+		if err := <-errChan; err != nil {
+			return &SearchResult{}, err
+		}
 	}
 
-	workloadsResources, err := workload.GetWorkloads(client, heapsterClient, nsQuery, dsQuery)
-	if err != nil {
-		return &SearchResult{}, err
-	}
+	clusterResources := <-clusterChan
+	configResources := <-configChan
+	discoveryResources := <-discoveryChan
+	workloadsResources := <-workloadsChan
 
-	return &SearchResult{
+	result := &SearchResult{
 
 		// Cluster.
 		NamespaceList:        clusterResources.NamespaceList,
@@ -123,5 +178,41 @@ func Search(client *kubernetes.Clientset, heapsterClient heapster.HeapsterClient
 		PodList:                   workloadsResources.PodList,
 		DaemonSetList:             workloadsResources.DaemonSetList,
 		StatefulSetList:           workloadsResources.StatefulSetList,
-	}, nil
+	}
+	result.Counts = getCounts(result)
+	for _, count := range result.Counts {
+		result.ListMeta.TotalItems += count
+	}
+	return result, nil
+}
+
+// getCounts returns number of matching resources of every kind found by the search.
+func getCounts(result *SearchResult) map[api.ResourceKind]int {
+	totals := map[api.ResourceKind]int{
+		api.ResourceKindNamespace:             result.NamespaceList.ListMeta.TotalItems,
+		api.ResourceKindNode:                  result.NodeList.ListMeta.TotalItems,
+		api.ResourceKindPersistentVolume:      result.PersistentVolumeList.ListMeta.TotalItems,
+		api.ResourceKindRbacRole:              result.RoleList.ListMeta.TotalItems,
+		api.ResourceKindStorageClass:          result.StorageClassList.ListMeta.TotalItems,
+		api.ResourceKindConfigMap:             result.ConfigMapList.ListMeta.TotalItems,
+		api.ResourceKindPersistentVolumeClaim: result.PersistentVolumeClaimList.ListMeta.TotalItems,
+		api.ResourceKindSecret:                result.SecretList.ListMeta.TotalItems,
+		api.ResourceKindService:               result.ServiceList.ListMeta.TotalItems,
+		api.ResourceKindIngress:               result.IngressList.ListMeta.TotalItems,
+		api.ResourceKindDeployment:            result.DeploymentList.ListMeta.TotalItems,
+		api.ResourceKindReplicaSet:            result.ReplicaSetList.ListMeta.TotalItems,
+		api.ResourceKindJob:                   result.JobList.ListMeta.TotalItems,
+		api.ResourceKindReplicationController: result.ReplicationControllerList.ListMeta.TotalItems,
+		api.ResourceKindPod:                   result.PodList.ListMeta.TotalItems,
+		api.ResourceKindDaemonSet:             result.DaemonSetList.ListMeta.TotalItems,
+		api.ResourceKindStatefulSet:           result.StatefulSetList.ListMeta.TotalItems,
+	}
+
+	counts := map[api.ResourceKind]int{}
+	for kind, total := range totals {
+		if total > 0 {
+			counts[kind] = total
+		}
+	}
+	return counts
 }
@@ -0,0 +1,66 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/pod"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/service"
+)
+
+func TestNewSearchQuery(t *testing.T) {
+	cases := []struct {
+		term     string
+		dsQuery  *dataselect.DataSelectQuery
+		expected string
+	}{
+		{"", dataselect.NoDataSelect, ""},
+		{"api", dataselect.NoDataSelect, "name,api"},
+		{
+			"api",
+			dataselect.NewDataSelectQuery(dataselect.NoPagination, dataselect.NoSort,
+				dataselect.NewFilterQuery([]string{"namespace", "prod"}), dataselect.NoMetrics),
+			"namespace,prod,name,api",
+		},
+	}
+	for _, c := range cases {
+		actual := NewSearchQuery(c.term, c.dsQuery)
+		if actual.FilterQuery.String() != c.expected {
+			t.Errorf("NewSearchQuery(%#v, %#v) == \ngot: %#v, \nexpected %#v", c.term, c.dsQuery,
+				actual.FilterQuery.String(), c.expected)
+		}
+	}
+
+	if len(dataselect.NoFilter.FilterByList) != 0 {
+		t.Errorf("NewSearchQuery() modified shared filter query: %#v", dataselect.NoFilter)
+	}
+}
+
+func TestGetCounts(t *testing.T) {
+	result := &SearchResult{
+		PodList:     pod.PodList{ListMeta: api.ListMeta{TotalItems: 3}},
+		ServiceList: service.ServiceList{ListMeta: api.ListMeta{TotalItems: 1}},
+	}
+	expected := map[api.ResourceKind]int{api.ResourceKindPod: 3, api.ResourceKindService: 1}
+
+	actual := getCounts(result)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("getCounts(%#v) == \ngot: %#v, \nexpected %#v", result, actual, expected)
+	}
+}
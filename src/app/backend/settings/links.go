@@ -0,0 +1,177 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settings
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	client "k8s.io/client-go/kubernetes"
+)
+
+const (
+	// MaxDeepLinks is the maximum number of stored deep links. The least recently created links
+	// are removed first.
+	MaxDeepLinks = 500
+
+	// maxDeepLinksSize is the maximum size in bytes of all stored deep links. They share the
+	// settings config map, which cannot be larger than 1 MiB, with settings, views and webhooks.
+	maxDeepLinksSize = 256 << 10
+
+	// maxQueryStateLength is the maximum length of serialized query state of a deep link.
+	maxQueryStateLength = 2048
+
+	// deepLinkTokenLength is the number of bytes of query state hash used as deep link token.
+	deepLinkTokenLength = 9
+)
+
+// deepLinkResource is the resource name used in errors about deep links.
+var deepLinkResource = schema.GroupResource{Resource: "links"}
+
+// QueryState is the state of a dashboard view, i.e. listed kind, namespaces, filter, sort and
+// time range of metrics, that can be shared as a deep link.
+type QueryState struct {
+	// Resource kind, e.g. pod or deployment.
+	Kind string `json:"kind"`
+
+	// Name of the resource, if the state is of a resource detail.
+	Name string `json:"name,omitempty"`
+
+	// Namespaces of the listed resources. Empty means all namespaces.
+	Namespaces []string `json:"namespaces"`
+
+	// Filter in the format of filterBy query parameter, e.g. name,foo.
+	Filter string `json:"filter,omitempty"`
+
+	// Sort in the format of sortBy query parameter, e.g. d,creationTimestamp.
+	Sort string `json:"sort,omitempty"`
+
+	// Time range of metrics. Zero values mean default range.
+	From metaV1.Time `json:"from"`
+	To   metaV1.Time `json:"to"`
+}
+
+// DeepLink is a stored query state identified by a short token.
+type DeepLink struct {
+	// Token identifying the link.
+	Token string `json:"token"`
+
+	// Shared query state.
+	State QueryState `json:"state"`
+
+	// Time when the link was created. Creating a link of the same state again refreshes it.
+	Created metaV1.Time `json:"created"`
+}
+
+// CreateDeepLink stores given query state and returns the deep link the state can be resolved by.
+// Token is derived from the state, so that the same state always gets the same token.
+func CreateDeepLink(manager SettingsManager, client client.Interface, state QueryState) (*DeepLink, error) {
+	if state.Kind == "" {
+		return nil, k8serrors.NewBadRequest("Kind of query state is required")
+	}
+	if state.Namespaces == nil {
+		state.Namespaces = make([]string, 0)
+	}
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > maxQueryStateLength {
+		return nil, k8serrors.NewBadRequest(fmt.Sprintf("Query state is longer than %d bytes",
+			maxQueryStateLength))
+	}
+
+	hash := sha256.Sum256(raw)
+	link := DeepLink{
+		Token:   base64.RawURLEncoding.EncodeToString(hash[:deepLinkTokenLength]),
+		State:   state,
+		Created: metaV1.Now(),
+	}
+
+	links, err := manager.GetDeepLinks(client)
+	if err != nil {
+		return nil, err
+	}
+	if err := manager.SaveDeepLinks(client, addDeepLink(links, link)); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// ResolveDeepLink returns deep link with given token.
+func ResolveDeepLink(manager SettingsManager, client client.Interface, token string) (*DeepLink, error) {
+	links, err := manager.GetDeepLinks(client)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, link := range links {
+		if link.Token == token {
+			return &link, nil
+		}
+	}
+	return nil, k8serrors.NewNotFound(deepLinkResource, token)
+}
+
+// addDeepLink returns given links with given link added or replacing the link with the same token.
+// The least recently created links are dropped, so that there are at most MaxDeepLinks links taking
+// at most maxDeepLinksSize bytes.
+func addDeepLink(links []DeepLink, link DeepLink) []DeepLink {
+	result := []DeepLink{link}
+	for _, existing := range links {
+		if existing.Token != link.Token {
+			result = append(result, existing)
+		}
+	}
+
+	sort.Stable(deepLinksByCreation(result))
+	size := 0
+	for i, link := range result {
+		raw, _ := json.Marshal(link)
+		// One more byte for the separator in the serialized list.
+		size += len(raw) + 1
+		if i == MaxDeepLinks || size > maxDeepLinksSize {
+			return result[:i]
+		}
+	}
+	return result
+}
+
+// deepLinksByCreation sorts deep links from the most recently created.
+type deepLinksByCreation []DeepLink
+
+func (self deepLinksByCreation) Len() int      { return len(self) }
+func (self deepLinksByCreation) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+func (self deepLinksByCreation) Less(i, j int) bool {
+	return self[j].Created.Before(self[i].Created)
+}
+
+// GetDeepLinks implements SettingsManager interface.
+func (self *configMapSettingsManager) GetDeepLinks(client client.Interface) ([]DeepLink, error) {
+	links := make([]DeepLink, 0)
+	err := self.getJSONData(client, deepLinksConfigMapKey, &links)
+	return links, err
+}
+
+// SaveDeepLinks implements SettingsManager interface.
+func (self *configMapSettingsManager) SaveDeepLinks(client client.Interface, links []DeepLink) error {
+	return self.saveJSONData(client, deepLinksConfigMapKey, links)
+}
@@ -0,0 +1,107 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCreateDeepLink(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	manager := NewSettingsManager("kube-system")
+	state := QueryState{Kind: "pod", Namespaces: []string{"a", "b"}, Filter: "name,foo"}
+
+	link, err := CreateDeepLink(manager, fakeClient, state)
+	if err != nil {
+		t.Fatalf("CreateDeepLink() returned error: %s", err)
+	}
+	if len(link.Token) != 12 {
+		t.Errorf("CreateDeepLink() should return 12 characters long token, got: %#v", link.Token)
+	}
+	again, err := CreateDeepLink(manager, fakeClient, state)
+	if err != nil || again.Token != link.Token {
+		t.Errorf("CreateDeepLink() of the same state should return the same token, got: %#v, %v", again, err)
+	}
+	other, err := CreateDeepLink(manager, fakeClient, QueryState{Kind: "pod", Namespaces: []string{"a"}})
+	if err != nil || other.Token == link.Token {
+		t.Errorf("CreateDeepLink() of other state should return other token, got: %#v, %v", other, err)
+	}
+
+	actual, err := ResolveDeepLink(manager, fakeClient, link.Token)
+	if err != nil || !reflect.DeepEqual(actual.State, state) {
+		t.Errorf("ResolveDeepLink() == \ngot: %#v, %v \nexpected %#v", actual, err, state)
+	}
+	if _, err := ResolveDeepLink(manager, fakeClient, "foo"); !k8serrors.IsNotFound(err) {
+		t.Errorf("ResolveDeepLink() of unknown token should return not found, got: %v", err)
+	}
+
+	links, err := manager.GetDeepLinks(fakeClient)
+	if err != nil || len(links) != 2 {
+		t.Errorf("GetDeepLinks() should return 2 links, got: %#v, %v", links, err)
+	}
+
+	for _, state := range []QueryState{{}, {Kind: "pod", Filter: strings.Repeat("x", maxQueryStateLength)}} {
+		if _, err := CreateDeepLink(manager, fakeClient, state); !k8serrors.IsBadRequest(err) {
+			t.Errorf("CreateDeepLink(%#v) should return bad request, got: %v", state, err)
+		}
+	}
+}
+
+func TestAddDeepLink(t *testing.T) {
+	now := time.Now()
+	links := []DeepLink{}
+	for i := 0; i < MaxDeepLinks; i++ {
+		links = append(links, DeepLink{Token: fmt.Sprintf("link-%d", i),
+			Created: metaV1.NewTime(now.Add(-time.Duration(i+1) * time.Minute))})
+	}
+	oldest := links[MaxDeepLinks-1]
+
+	actual := addDeepLink(links, DeepLink{Token: "new", Created: metaV1.NewTime(now)})
+	if len(actual) != MaxDeepLinks || actual[0].Token != "new" {
+		t.Errorf("addDeepLink() should add the link first and keep %d links, got %d links starting with %#v",
+			MaxDeepLinks, len(actual), actual[0].Token)
+	}
+	for _, link := range actual {
+		if link.Token == oldest.Token {
+			t.Errorf("addDeepLink() should drop the oldest link %#v", oldest.Token)
+		}
+	}
+
+	refreshed := addDeepLink(actual, DeepLink{Token: actual[5].Token, Created: metaV1.NewTime(now.Add(time.Minute))})
+	if len(refreshed) != MaxDeepLinks || refreshed[0].Token != actual[5].Token {
+		t.Errorf("addDeepLink() should move refreshed link first, got %#v", refreshed[0].Token)
+	}
+	large := []DeepLink{}
+	for i := 0; i < MaxDeepLinks; i++ {
+		large = append(large, DeepLink{Token: fmt.Sprintf("link-%d", i),
+			State:   QueryState{Kind: "pod", Filter: strings.Repeat("x", maxQueryStateLength-100)},
+			Created: metaV1.NewTime(now.Add(-time.Duration(i+1) * time.Minute))})
+	}
+	limited := addDeepLink(large, DeepLink{Token: "new", Created: metaV1.NewTime(now)})
+	raw, _ := json.Marshal(limited)
+	if len(raw) > maxDeepLinksSize || limited[0].Token != "new" {
+		t.Errorf("addDeepLink() should keep links within %d bytes, got %d bytes", maxDeepLinksSize,
+			len(raw))
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settings
+
+import (
+	"encoding/json"
+	"log"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+const (
+	// SettingsConfigMapName is the name of the config map that stores dashboard settings.
+	SettingsConfigMapName = "kubernetes-dashboard-settings"
+
+	// settingsConfigMapKey is the key in config map data that holds settings as JSON.
+	settingsConfigMapKey = "settings"
+
+	// viewsConfigMapKey is the key in config map data that holds saved views as JSON.
+	viewsConfigMapKey = "views"
+
+	// deepLinksConfigMapKey is the key in config map data that holds deep links as JSON.
+	deepLinksConfigMapKey = "links"
+
+	// webhooksConfigMapKey is the key in config map data that holds webhooks as JSON.
+	webhooksConfigMapKey = "webhooks"
+
+	// webhookRecordsConfigMapKey is the key in config map data that holds audit records of
+	// webhook calls as JSON.
+	webhookRecordsConfigMapKey = "webhookRecords"
+)
+
+// LinkTemplate is a template of a link to an external tool, e.g. Grafana dashboard or Kibana
+// search, generated for resources of given kinds.
+type LinkTemplate struct {
+	// Name of the link displayed to the user, e.g. Grafana.
+	Name string `json:"name"`
+
+	// Resource kinds the link is generated for, e.g. pod or deployment. Empty means all kinds.
+	Kinds []string `json:"kinds"`
+
+	// URL template. Supported variables are {namespace}, {name} and {kind}.
+	URL string `json:"url"`
+}
+
+// Settings contains global dashboard settings shared by all users.
+type Settings struct {
+	// Templates of links to external tools shown in resource details.
+	LinkTemplates []LinkTemplate `json:"linkTemplates"`
+
+	// Number of items per page of lists used when client doesn't request a valid one.
+	ItemsPerPage int `json:"itemsPerPage"`
+
+	// Maximum number of items per page of lists. Larger pages requested by clients are clamped.
+	MaxItemsPerPage int `json:"maxItemsPerPage"`
+
+	// How the request reading the settings reached the dashboard, e.g. through kubectl proxy. It
+	// is detected for every request and not stored.
+	AccessMode string `json:"accessMode,omitempty"`
+}
+
+// Default pagination settings.
+const (
+	DefaultItemsPerPage    = 10
+	DefaultMaxItemsPerPage = 100
+)
+
+// GetDefaultSettings returns settings used when no settings are stored in the cluster.
+func GetDefaultSettings() Settings {
+	return Settings{
+		LinkTemplates:   make([]LinkTemplate, 0),
+		ItemsPerPage:    DefaultItemsPerPage,
+		MaxItemsPerPage: DefaultMaxItemsPerPage,
+	}
+}
+
+// SettingsManager reads and stores dashboard settings in a config map.
+type SettingsManager interface {
+	// GetSettings returns stored settings or default ones when there are no settings stored.
+	GetSettings(client client.Interface) (Settings, error)
+
+	// SaveSettings stores given settings.
+	SaveSettings(client client.Interface, settings Settings) error
+
+	// GetViews returns all stored views of all users.
+	GetViews(client client.Interface) ([]View, error)
+
+	// SaveViews replaces stored views with given ones.
+	SaveViews(client client.Interface, views []View) error
+
+	// GetDeepLinks returns all stored deep links.
+	GetDeepLinks(client client.Interface) ([]DeepLink, error)
+
+	// SaveDeepLinks replaces stored deep links with given ones.
+	SaveDeepLinks(client client.Interface, links []DeepLink) error
+
+	// GetWebhooks returns all stored webhooks.
+	GetWebhooks(client client.Interface) ([]Webhook, error)
+
+	// SaveWebhooks replaces stored webhooks with given ones.
+	SaveWebhooks(client client.Interface, webhooks []Webhook) error
+
+	// GetWebhookRecords returns stored records of webhook calls, from the most recent.
+	GetWebhookRecords(client client.Interface) ([]WebhookRecord, error)
+
+	// SaveWebhookRecords replaces stored records of webhook calls with given ones.
+	SaveWebhookRecords(client client.Interface, records []WebhookRecord) error
+}
+
+// configMapSettingsManager is a settings manager that keeps settings in a config map in given
+// namespace.
+type configMapSettingsManager struct {
+	namespace string
+}
+
+// NewSettingsManager creates settings manager that keeps settings in a config map in given
+// namespace.
+func NewSettingsManager(namespace string) SettingsManager {
+	return &configMapSettingsManager{namespace: namespace}
+}
+
+// GetSettings implements SettingsManager interface.
+func (self *configMapSettingsManager) GetSettings(client client.Interface) (Settings, error) {
+	raw, err := self.getData(client, settingsConfigMapKey)
+	if err != nil {
+		return Settings{}, err
+	}
+
+	return unmarshalSettings(raw)
+}
+
+// SaveSettings implements SettingsManager interface.
+func (self *configMapSettingsManager) SaveSettings(client client.Interface, settings Settings) error {
+	log.Printf("Saving settings in %s namespace", self.namespace)
+
+	raw, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+
+	return self.saveData(client, settingsConfigMapKey, string(raw))
+}
+
+// getData returns value stored under given key of the settings config map. Empty value is
+// returned if the config map does not exist.
+func (self *configMapSettingsManager) getData(client client.Interface, key string) (string, error) {
+	configMap, err := client.CoreV1().ConfigMaps(self.namespace).Get(SettingsConfigMapName, metaV1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return configMap.Data[key], nil
+}
+
+// saveData stores given value under given key of the settings config map. The config map is
+// created if it does not exist, other keys are left intact.
+func (self *configMapSettingsManager) saveData(client client.Interface, key, value string) error {
+	configMaps := client.CoreV1().ConfigMaps(self.namespace)
+	configMap, err := configMaps.Get(SettingsConfigMapName, metaV1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		_, err = configMaps.Create(&v1.ConfigMap{
+			ObjectMeta: metaV1.ObjectMeta{Name: SettingsConfigMapName, Namespace: self.namespace},
+			Data:       map[string]string{key: value},
+		})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string)
+	}
+	configMap.Data[key] = value
+	_, err = configMaps.Update(configMap)
+	return err
+}
+
+// getJSONData unmarshals JSON stored under given key of config map data into given value. The value
+// is left untouched if nothing is stored.
+func (self *configMapSettingsManager) getJSONData(client client.Interface, key string,
+	value interface{}) error {
+	raw, err := self.getData(client, key)
+	if err != nil || raw == "" {
+		return err
+	}
+	return json.Unmarshal([]byte(raw), value)
+}
+
+// saveJSONData stores given value as JSON under given key of config map data.
+func (self *configMapSettingsManager) saveJSONData(client client.Interface, key string,
+	value interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return self.saveData(client, key, string(raw))
+}
+
+// unmarshalSettings parses settings stored in the config map. Fields missing in stored settings
+// keep their default values.
+func unmarshalSettings(raw string) (Settings, error) {
+	settings := GetDefaultSettings()
+	if raw == "" {
+		return settings, nil
+	}
+
+	if err := json.Unmarshal([]byte(raw), &settings); err != nil {
+		return Settings{}, err
+	}
+	if settings.LinkTemplates == nil {
+		settings.LinkTemplates = make([]LinkTemplate, 0)
+	}
+	return settings, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settings
+
+import (
+	"reflect"
+	"testing"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+func TestGetSettings(t *testing.T) {
+	cases := []struct {
+		configMap *v1.ConfigMap
+		expected  Settings
+	}{
+		{nil, GetDefaultSettings()},
+		{
+			&v1.ConfigMap{
+				ObjectMeta: metaV1.ObjectMeta{Name: SettingsConfigMapName, Namespace: "kube-system"},
+				Data:       map[string]string{},
+			},
+			GetDefaultSettings(),
+		},
+		{
+			&v1.ConfigMap{
+				ObjectMeta: metaV1.ObjectMeta{Name: SettingsConfigMapName, Namespace: "kube-system"},
+				Data: map[string]string{"settings": `{"linkTemplates": [` +
+					`{"name": "Grafana", "kinds": ["pod"], "url": "http://grafana/?ns={namespace}"}]}`},
+			},
+			Settings{
+				LinkTemplates: []LinkTemplate{
+					{Name: "Grafana", Kinds: []string{"pod"}, URL: "http://grafana/?ns={namespace}"},
+				},
+				ItemsPerPage:    DefaultItemsPerPage,
+				MaxItemsPerPage: DefaultMaxItemsPerPage,
+			},
+		},
+		{
+			&v1.ConfigMap{
+				ObjectMeta: metaV1.ObjectMeta{Name: SettingsConfigMapName, Namespace: "kube-system"},
+				Data:       map[string]string{"settings": `{"itemsPerPage": 25, "maxItemsPerPage": 200}`},
+			},
+			Settings{LinkTemplates: []LinkTemplate{}, ItemsPerPage: 25, MaxItemsPerPage: 200},
+		},
+	}
+
+	for _, c := range cases {
+		fakeClient := fake.NewSimpleClientset()
+		if c.configMap != nil {
+			fakeClient = fake.NewSimpleClientset(c.configMap)
+		}
+
+		actual, err := NewSettingsManager("kube-system").GetSettings(fakeClient)
+		if err != nil {
+			t.Fatalf("GetSettings() returned error: %s", err)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetSettings() == \ngot: %#v, \nexpected %#v", actual, c.expected)
+		}
+	}
+}
+
+func TestSaveSettings(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	manager := NewSettingsManager("kube-system")
+
+	for _, expected := range []Settings{
+		{LinkTemplates: []LinkTemplate{{Name: "Kibana", URL: "http://kibana/{name}"}}},
+		{LinkTemplates: []LinkTemplate{}},
+	} {
+		if err := manager.SaveSettings(fakeClient, expected); err != nil {
+			t.Fatalf("SaveSettings(%#v) returned error: %s", expected, err)
+		}
+
+		actual, err := manager.GetSettings(fakeClient)
+		if err != nil {
+			t.Fatalf("GetSettings() returned error: %s", err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("GetSettings() after SaveSettings() == \ngot: %#v, \nexpected %#v", actual, expected)
+		}
+	}
+}
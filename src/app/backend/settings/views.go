@@ -0,0 +1,277 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settings
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	client "k8s.io/client-go/kubernetes"
+)
+
+const (
+	// MaxViews is the maximum number of stored views of all users.
+	MaxViews = 500
+
+	// maxViewsPerUser is the maximum number of views owned by a single user.
+	maxViewsPerUser = 50
+
+	// maxViewsSize is the maximum size in bytes of all stored views. They share the settings config
+	// map, which cannot be larger than 1 MiB, with settings, deep links and webhooks.
+	maxViewsSize = 256 << 10
+)
+
+// viewResource is the resource name used in errors about views.
+var viewResource = schema.GroupResource{Resource: "views"}
+
+var (
+	errNotViewOwner  = errors.New("view is owned by another user and not shared with user's team")
+	errNotTeamMember = errors.New("view can be shared only with user's own team")
+
+	// errUnknownViewUser is returned when a user unknown to the dashboard modifies views, as they
+	// would be owned by all unknown users.
+	errUnknownViewUser = k8serrors.NewBadRequest(
+		"Views are available only to users authenticated by a proxy")
+)
+
+// View is a named, saved list view, i.e. resource kind with namespaces, filter, sort and columns
+// selected by the user. Views are visible to their owner and to members of their team and can be
+// shared with anyone by their token. Users are known only when the dashboard runs behind an
+// authenticating proxy, other users cannot save views.
+type View struct {
+	// Name of the view displayed to the user.
+	Name string `json:"name"`
+
+	// Resource kind listed by the view, e.g. pod or deployment.
+	Kind string `json:"kind"`
+
+	// Namespaces listed by the view. Empty means all namespaces.
+	Namespaces []string `json:"namespaces"`
+
+	// Filter of the list in the format of filterBy query parameter, e.g. name,foo.
+	Filter string `json:"filter"`
+
+	// Sort of the list in the format of sortBy query parameter, e.g. d,creationTimestamp.
+	Sort string `json:"sort"`
+
+	// Columns of the list displayed to the user.
+	Columns []string `json:"columns"`
+
+	// Name of the user that saved the view. Set by the backend.
+	Owner string `json:"owner"`
+
+	// Team (group of users) the view is shared with. Empty if the view is private.
+	Team string `json:"team,omitempty"`
+
+	// Token identifying the view in URLs. Generated by the backend when the view is saved first.
+	Token string `json:"token"`
+}
+
+// ViewUser is the user that reads or modifies views.
+type ViewUser struct {
+	// Name of the user.
+	Name string
+
+	// Groups (teams) of the user.
+	Groups []string
+}
+
+// canAccess returns true if the user owns given view or is a member of its team. Unknown users
+// own no views.
+func (self ViewUser) canAccess(view View) bool {
+	if self.Name != "" && view.Owner == self.Name {
+		return true
+	}
+	return view.Team != "" && self.isMemberOf(view.Team)
+}
+
+// isMemberOf returns true if the user is a member of given team.
+func (self ViewUser) isMemberOf(team string) bool {
+	for _, group := range self.Groups {
+		if group == team {
+			return true
+		}
+	}
+	return false
+}
+
+// GetViewList returns views visible to given user.
+func GetViewList(manager SettingsManager, client client.Interface, user ViewUser) ([]View, error) {
+	views, err := manager.GetViews(client)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]View, 0)
+	for _, view := range views {
+		if user.canAccess(view) {
+			result = append(result, view)
+		}
+	}
+	return result, nil
+}
+
+// GetViewByToken returns view with given token. Anyone who knows the token can read the view.
+func GetViewByToken(manager SettingsManager, client client.Interface, token string) (*View, error) {
+	views, err := manager.GetViews(client)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, view := range views {
+		if view.Token == token {
+			return &view, nil
+		}
+	}
+	return nil, k8serrors.NewNotFound(viewResource, token)
+}
+
+// SaveView stores given view on behalf of given user. Views with token are updated, other ones are
+// created with a new token. The user becomes the owner of the view and can share it only with their
+// own team.
+func SaveView(manager SettingsManager, client client.Interface, user ViewUser, view View) (*View, error) {
+	if user.Name == "" {
+		return nil, errUnknownViewUser
+	}
+	if view.Name == "" || view.Kind == "" {
+		return nil, k8serrors.NewBadRequest("View name and kind are required")
+	}
+	if view.Team != "" && !user.isMemberOf(view.Team) {
+		return nil, k8serrors.NewForbidden(viewResource, view.Name, errNotTeamMember)
+	}
+	view.Owner = user.Name
+	if view.Namespaces == nil {
+		view.Namespaces = make([]string, 0)
+	}
+	if view.Columns == nil {
+		view.Columns = make([]string, 0)
+	}
+
+	views, err := manager.GetViews(client)
+	if err != nil {
+		return nil, err
+	}
+
+	index := findView(views, view.Token)
+	if index >= 0 {
+		if !user.canAccess(views[index]) {
+			return nil, k8serrors.NewForbidden(viewResource, view.Name, errNotViewOwner)
+		}
+		views[index] = view
+	} else {
+		if err := checkViewLimits(views, user); err != nil {
+			return nil, err
+		}
+		if view.Token, err = generateViewToken(); err != nil {
+			return nil, err
+		}
+		views = append(views, view)
+	}
+
+	if raw, err := json.Marshal(views); err != nil {
+		return nil, err
+	} else if len(raw) > maxViewsSize {
+		return nil, k8serrors.NewBadRequest(fmt.Sprintf("Views cannot be larger than %d bytes",
+			maxViewsSize))
+	}
+
+	log.Printf("Saving view %s of user %s", view.Name, view.Owner)
+	if err := manager.SaveViews(client, views); err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+// DeleteView deletes view with given token on behalf of given user.
+func DeleteView(manager SettingsManager, client client.Interface, user ViewUser, token string) error {
+	if user.Name == "" {
+		return errUnknownViewUser
+	}
+
+	views, err := manager.GetViews(client)
+	if err != nil {
+		return err
+	}
+
+	index := findView(views, token)
+	if index < 0 {
+		return k8serrors.NewNotFound(viewResource, token)
+	}
+	if !user.canAccess(views[index]) {
+		return k8serrors.NewForbidden(viewResource, views[index].Name, errNotViewOwner)
+	}
+
+	return manager.SaveViews(client, append(views[:index], views[index+1:]...))
+}
+
+// GetViews implements SettingsManager interface.
+func (self *configMapSettingsManager) GetViews(client client.Interface) ([]View, error) {
+	views := make([]View, 0)
+	err := self.getJSONData(client, viewsConfigMapKey, &views)
+	return views, err
+}
+
+// SaveViews implements SettingsManager interface.
+func (self *configMapSettingsManager) SaveViews(client client.Interface, views []View) error {
+	return self.saveJSONData(client, viewsConfigMapKey, views)
+}
+
+// checkViewLimits returns error if given user cannot create another view because of the maximum
+// number of views.
+func checkViewLimits(views []View, user ViewUser) error {
+	if len(views) >= MaxViews {
+		return k8serrors.NewBadRequest(fmt.Sprintf("Cannot store more than %d views", MaxViews))
+	}
+
+	owned := 0
+	for _, view := range views {
+		if view.Owner == user.Name {
+			owned++
+		}
+	}
+	if owned >= maxViewsPerUser {
+		return k8serrors.NewBadRequest(fmt.Sprintf("User cannot own more than %d views",
+			maxViewsPerUser))
+	}
+	return nil
+}
+
+// findView returns index of the view with given token or -1 if there is no such view.
+func findView(views []View, token string) int {
+	if token == "" {
+		return -1
+	}
+	for i, view := range views {
+		if view.Token == token {
+			return i
+		}
+	}
+	return -1
+}
+
+// generateViewToken returns random token of a new view.
+func generateViewToken() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settings
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSaveView(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	manager := NewSettingsManager("kube-system")
+	jane := ViewUser{Name: "jane", Groups: []string{"ops"}}
+	john := ViewUser{Name: "john", Groups: []string{"dev"}}
+
+	private, err := SaveView(manager, fakeClient, jane, View{Name: "Failing pods", Kind: "pod",
+		Filter: "status,failed", Owner: "john"})
+	if err != nil {
+		t.Fatalf("SaveView() returned error: %s", err)
+	}
+	if private.Token == "" || private.Owner != "jane" {
+		t.Errorf("SaveView() should set token and owner, got: %#v", private)
+	}
+	shared, err := SaveView(manager, fakeClient, jane, View{Name: "Ops deployments", Kind: "deployment",
+		Namespaces: []string{"ops"}, Team: "ops"})
+	if err != nil {
+		t.Fatalf("SaveView() returned error: %s", err)
+	}
+
+	if _, err := SaveView(manager, fakeClient, john, View{Name: "Ops pods", Kind: "pod", Team: "ops"}); !k8serrors.IsForbidden(err) {
+		t.Errorf("SaveView() sharing view with other team should be forbidden, got: %v", err)
+	}
+	if _, err := SaveView(manager, fakeClient, john, *private); !k8serrors.IsForbidden(err) {
+		t.Errorf("SaveView() updating view of other user should be forbidden, got: %v", err)
+	}
+	if _, err := SaveView(manager, fakeClient, ViewUser{}, View{Name: "Pods", Kind: "pod"}); !k8serrors.IsBadRequest(err) {
+		t.Errorf("SaveView() of unknown user should be bad request, got: %v", err)
+	}
+	if _, err := SaveView(manager, fakeClient, jane, View{Kind: "pod"}); !k8serrors.IsBadRequest(err) {
+		t.Errorf("SaveView() without name should be bad request, got: %v", err)
+	}
+
+	private.Sort = "d,creationTimestamp"
+	updated, err := SaveView(manager, fakeClient, jane, *private)
+	if err != nil {
+		t.Fatalf("SaveView() returned error: %s", err)
+	}
+	if !reflect.DeepEqual(updated, private) {
+		t.Errorf("SaveView() == \ngot: %#v, \nexpected %#v", updated, private)
+	}
+
+	cases := []struct {
+		user     ViewUser
+		expected []View
+	}{
+		{jane, []View{*private, *shared}},
+		{ViewUser{Name: "joe", Groups: []string{"ops"}}, []View{*shared}},
+		{john, []View{}},
+		{ViewUser{}, []View{}},
+	}
+	for _, c := range cases {
+		actual, err := GetViewList(manager, fakeClient, c.user)
+		if err != nil {
+			t.Fatalf("GetViewList() returned error: %s", err)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetViewList(%#v) == \ngot: %#v, \nexpected %#v", c.user, actual, c.expected)
+		}
+	}
+
+	actual, err := GetViewByToken(manager, fakeClient, shared.Token)
+	if err != nil || !reflect.DeepEqual(actual, shared) {
+		t.Errorf("GetViewByToken() == \ngot: %#v, %v \nexpected %#v", actual, err, shared)
+	}
+	if _, err := GetViewByToken(manager, fakeClient, "foo"); !k8serrors.IsNotFound(err) {
+		t.Errorf("GetViewByToken() of unknown token should return not found, got: %v", err)
+	}
+}
+
+func TestDeleteView(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	manager := NewSettingsManager("kube-system")
+	jane := ViewUser{Name: "jane"}
+
+	view, err := SaveView(manager, fakeClient, jane, View{Name: "Pods", Kind: "pod"})
+	if err != nil {
+		t.Fatalf("SaveView() returned error: %s", err)
+	}
+	if err := DeleteView(manager, fakeClient, ViewUser{Name: "john"}, view.Token); !k8serrors.IsForbidden(err) {
+		t.Errorf("DeleteView() of view of other user should be forbidden, got: %v", err)
+	}
+	if err := DeleteView(manager, fakeClient, ViewUser{}, view.Token); !k8serrors.IsBadRequest(err) {
+		t.Errorf("DeleteView() of unknown user should be bad request, got: %v", err)
+	}
+	if err := DeleteView(manager, fakeClient, jane, view.Token); err != nil {
+		t.Fatalf("DeleteView() returned error: %s", err)
+	}
+	if err := DeleteView(manager, fakeClient, jane, view.Token); !k8serrors.IsNotFound(err) {
+		t.Errorf("DeleteView() of deleted view should return not found, got: %v", err)
+	}
+
+	settings, err := manager.GetSettings(fakeClient)
+	if err != nil || !reflect.DeepEqual(settings, GetDefaultSettings()) {
+		t.Errorf("Views should not change settings, got: %#v, %v", settings, err)
+	}
+}
+
+func TestSaveViewLimits(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	manager := NewSettingsManager("kube-system")
+	jane := ViewUser{Name: "jane"}
+
+	for i := 0; i < maxViewsPerUser; i++ {
+		if _, err := SaveView(manager, fakeClient, jane, View{Name: "Pods", Kind: "pod"}); err != nil {
+			t.Fatalf("SaveView() returned error: %s", err)
+		}
+	}
+	if _, err := SaveView(manager, fakeClient, jane, View{Name: "Pods", Kind: "pod"}); !k8serrors.IsBadRequest(err) {
+		t.Errorf("SaveView() above limit of views of user should be bad request, got: %v", err)
+	}
+	if _, err := SaveView(manager, fakeClient, ViewUser{Name: "john"}, View{Name: "Pods", Kind: "pod"}); err != nil {
+		t.Errorf("SaveView() of other user returned error: %s", err)
+	}
+	if _, err := SaveView(manager, fakeClient, ViewUser{Name: "joe"}, View{Name: "Pods", Kind: "pod",
+		Filter: strings.Repeat("a", maxViewsSize)}); !k8serrors.IsBadRequest(err) {
+		t.Errorf("SaveView() above size limit should be bad request, got: %v", err)
+	}
+
+	views := make([]View, MaxViews)
+	for i := range views {
+		views[i] = View{Name: "Pods", Kind: "pod", Owner: fmt.Sprintf("user-%d", i)}
+	}
+	if err := manager.SaveViews(fakeClient, views); err != nil {
+		t.Fatalf("SaveViews() returned error: %s", err)
+	}
+	if _, err := SaveView(manager, fakeClient, ViewUser{Name: "john"}, View{Name: "Pods", Kind: "pod"}); !k8serrors.IsBadRequest(err) {
+		t.Errorf("SaveView() above limit of all views should be bad request, got: %v", err)
+	}
+}
@@ -0,0 +1,314 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settings
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	client "k8s.io/client-go/kubernetes"
+	authorizationApi "k8s.io/client-go/pkg/apis/authorization/v1"
+)
+
+// Actions that can be triggered by webhooks.
+const (
+	// WebhookActionRestart restarts all pods of a deployment.
+	WebhookActionRestart = "restart"
+
+	// WebhookActionSetImage sets image of a container of a deployment.
+	WebhookActionSetImage = "setImage"
+)
+
+const (
+	// MaxWebhookRecords is the maximum number of stored records of webhook calls. The oldest
+	// records are removed first.
+	MaxWebhookRecords = 200
+
+	// webhookTokenLength is the number of random bytes of webhook tokens.
+	webhookTokenLength = 32
+)
+
+// webhookResource is the resource name used in errors about webhooks.
+var webhookResource = schema.GroupResource{Resource: "webhooks"}
+
+// errWebhookNotAllowed is returned when the user cannot update deployments in the scope of a
+// webhook.
+var errWebhookNotAllowed = errors.New("Only users allowed to update deployments in scope of the " +
+	"webhook can manage it")
+
+// errInvalidWebhookToken is returned for unknown webhook tokens and for calls out of the scope
+// of the token. The same error is used for both, so that it does not tell whether a token exists.
+var errInvalidWebhookToken = errors.New("Webhook token is invalid or not allowed to do this action")
+
+// Webhook is a token that external systems, e.g. CI, can use to redeploy deployments without
+// credentials of the cluster.
+type Webhook struct {
+	// Unique name of the webhook.
+	Name string `json:"name"`
+
+	// Namespace of deployments the webhook can redeploy.
+	Namespace string `json:"namespace"`
+
+	// Name of the deployment the webhook can redeploy. Empty means all deployments of the
+	// namespace.
+	Deployment string `json:"deployment,omitempty"`
+
+	// Actions the webhook can trigger, e.g. restart.
+	Actions []string `json:"actions"`
+
+	// Name of the user who created the webhook.
+	Creator string `json:"creator,omitempty"`
+
+	// Time when the webhook was created.
+	Created metaV1.Time `json:"created"`
+
+	// SHA256 hash of the token. Tokens themselves are not stored and are returned only once, when
+	// the webhook is created.
+	TokenHash string `json:"tokenHash,omitempty"`
+}
+
+// CreatedWebhook is a newly created webhook with its token.
+type CreatedWebhook struct {
+	Webhook
+
+	// Token to be sent in X-Webhook-Token header of webhook calls.
+	Token string `json:"token"`
+}
+
+// WebhookRecord is an audit record of a webhook call.
+type WebhookRecord struct {
+	// Name of the called webhook.
+	Webhook string `json:"webhook"`
+
+	// Namespace and name of the redeployed deployment.
+	Namespace  string `json:"namespace"`
+	Deployment string `json:"deployment"`
+
+	// Triggered action, and image in case of setImage action.
+	Action string `json:"action"`
+	Image  string `json:"image,omitempty"`
+
+	// Time of the call.
+	Time metaV1.Time `json:"time"`
+
+	// Error of the action. Empty if the action succeeded.
+	Error string `json:"error,omitempty"`
+}
+
+// CreateWebhook stores given webhook with a newly generated token.
+func CreateWebhook(manager SettingsManager, client client.Interface, creator string,
+	webhook Webhook) (*CreatedWebhook, error) {
+	if webhook.Name == "" || webhook.Namespace == "" {
+		return nil, k8serrors.NewBadRequest("Name and namespace of webhook are required")
+	}
+	if len(webhook.Actions) == 0 {
+		return nil, k8serrors.NewBadRequest("Webhook has to allow at least one action")
+	}
+	for _, action := range webhook.Actions {
+		if action != WebhookActionRestart && action != WebhookActionSetImage {
+			return nil, k8serrors.NewBadRequest(fmt.Sprintf("Unsupported webhook action: %s", action))
+		}
+	}
+
+	if err := checkWebhookAccess(client, webhook); err != nil {
+		return nil, err
+	}
+
+	webhooks, err := manager.GetWebhooks(client)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range webhooks {
+		if existing.Name == webhook.Name {
+			return nil, k8serrors.NewAlreadyExists(webhookResource, webhook.Name)
+		}
+	}
+
+	raw := make([]byte, webhookTokenLength)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	webhook.Creator = creator
+	webhook.Created = metaV1.Now()
+	webhook.TokenHash = hashWebhookToken(token)
+	if err := manager.SaveWebhooks(client, append(webhooks, webhook)); err != nil {
+		return nil, err
+	}
+
+	webhook.TokenHash = ""
+	return &CreatedWebhook{Webhook: webhook, Token: token}, nil
+}
+
+// GetWebhookList returns all webhooks without their token hashes.
+func GetWebhookList(manager SettingsManager, client client.Interface) ([]Webhook, error) {
+	webhooks, err := manager.GetWebhooks(client)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range webhooks {
+		webhooks[i].TokenHash = ""
+	}
+	return webhooks, nil
+}
+
+// GetWebhook returns webhook with given name without its token hash.
+func GetWebhook(manager SettingsManager, client client.Interface, name string) (*Webhook, error) {
+	webhooks, err := GetWebhookList(manager, client)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, webhook := range webhooks {
+		if webhook.Name == name {
+			return &webhook, nil
+		}
+	}
+	return nil, k8serrors.NewNotFound(webhookResource, name)
+}
+
+// DeleteWebhook deletes webhook with given name. Its token stops working immediately.
+func DeleteWebhook(manager SettingsManager, client client.Interface, name string) error {
+	webhooks, err := manager.GetWebhooks(client)
+	if err != nil {
+		return err
+	}
+
+	result := make([]Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		if webhook.Name != name {
+			result = append(result, webhook)
+		} else if err := checkWebhookAccess(client, webhook); err != nil {
+			return err
+		}
+	}
+	if len(result) == len(webhooks) {
+		return k8serrors.NewNotFound(webhookResource, name)
+	}
+	return manager.SaveWebhooks(client, result)
+}
+
+// AuthorizeWebhook returns webhook of given token, if the webhook is allowed to trigger given
+// action on given deployment.
+func AuthorizeWebhook(manager SettingsManager, client client.Interface, token, namespace,
+	deployment, action string) (*Webhook, error) {
+	webhooks, err := manager.GetWebhooks(client)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := hashWebhookToken(token)
+	for _, webhook := range webhooks {
+		if subtle.ConstantTimeCompare([]byte(webhook.TokenHash), []byte(hash)) != 1 {
+			continue
+		}
+		if webhook.Namespace != namespace ||
+			(webhook.Deployment != "" && webhook.Deployment != deployment) {
+			break
+		}
+		for _, allowed := range webhook.Actions {
+			if allowed == action {
+				webhook.TokenHash = ""
+				return &webhook, nil
+			}
+		}
+		break
+	}
+	return nil, k8serrors.NewForbidden(webhookResource, deployment, errInvalidWebhookToken)
+}
+
+// RecordWebhookCall logs and stores given audit record of a webhook call. Time of the record is set
+// to now.
+func RecordWebhookCall(manager SettingsManager, client client.Interface, record *WebhookRecord) error {
+	record.Time = metaV1.Now()
+	log.Printf("Webhook %s triggered %s of %s/%s deployment, error: %q", record.Webhook,
+		record.Action, record.Namespace, record.Deployment, record.Error)
+
+	records, err := manager.GetWebhookRecords(client)
+	if err != nil {
+		return err
+	}
+
+	records = append([]WebhookRecord{*record}, records...)
+	if len(records) > MaxWebhookRecords {
+		records = records[:MaxWebhookRecords]
+	}
+	return manager.SaveWebhookRecords(client, records)
+}
+
+// checkWebhookAccess checks that the user of given client can update deployments in the scope of
+// given webhook. Otherwise webhooks would let users redeploy what they cannot update themselves.
+func checkWebhookAccess(client client.Interface, webhook Webhook) error {
+	review, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(
+		&authorizationApi.SelfSubjectAccessReview{
+			Spec: authorizationApi.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationApi.ResourceAttributes{
+					Namespace: webhook.Namespace,
+					Verb:      "update",
+					Group:     "extensions",
+					Resource:  "deployments",
+					Name:      webhook.Deployment,
+				},
+			},
+		})
+	if err != nil {
+		return err
+	}
+	if !review.Status.Allowed {
+		return k8serrors.NewForbidden(webhookResource, webhook.Name, errWebhookNotAllowed)
+	}
+	return nil
+}
+
+func hashWebhookToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// GetWebhooks implements SettingsManager interface.
+func (self *configMapSettingsManager) GetWebhooks(client client.Interface) ([]Webhook, error) {
+	webhooks := make([]Webhook, 0)
+	err := self.getJSONData(client, webhooksConfigMapKey, &webhooks)
+	return webhooks, err
+}
+
+// SaveWebhooks implements SettingsManager interface.
+func (self *configMapSettingsManager) SaveWebhooks(client client.Interface, webhooks []Webhook) error {
+	return self.saveJSONData(client, webhooksConfigMapKey, webhooks)
+}
+
+// GetWebhookRecords implements SettingsManager interface.
+func (self *configMapSettingsManager) GetWebhookRecords(client client.Interface) ([]WebhookRecord, error) {
+	records := make([]WebhookRecord, 0)
+	err := self.getJSONData(client, webhookRecordsConfigMapKey, &records)
+	return records, err
+}
+
+// SaveWebhookRecords implements SettingsManager interface.
+func (self *configMapSettingsManager) SaveWebhookRecords(client client.Interface,
+	records []WebhookRecord) error {
+	return self.saveJSONData(client, webhookRecordsConfigMapKey, records)
+}
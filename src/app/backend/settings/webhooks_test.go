@@ -0,0 +1,129 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settings
+
+import (
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	authorizationApi "k8s.io/client-go/pkg/apis/authorization/v1"
+	core "k8s.io/client-go/testing"
+)
+
+func allowAccess(fakeClient *fake.Clientset, allowed bool) {
+	fakeClient.PrependReactor("create", "selfsubjectaccessreviews",
+		func(action core.Action) (bool, runtime.Object, error) {
+			return true, &authorizationApi.SelfSubjectAccessReview{
+				Status: authorizationApi.SubjectAccessReviewStatus{Allowed: allowed},
+			}, nil
+		})
+}
+
+func TestCreateWebhook(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	allowAccess(fakeClient, true)
+	manager := NewSettingsManager("kube-system")
+	webhook := Webhook{Name: "ci", Namespace: "prod", Deployment: "app",
+		Actions: []string{WebhookActionSetImage}}
+
+	created, err := CreateWebhook(manager, fakeClient, "jane", webhook)
+	if err != nil {
+		t.Fatalf("CreateWebhook() returned error: %s", err)
+	}
+	if len(created.Token) != 43 || created.Creator != "jane" || created.TokenHash != "" {
+		t.Errorf("CreateWebhook() should return webhook with token and without hash, got: %#v", created)
+	}
+	if _, err := CreateWebhook(manager, fakeClient, "jane", webhook); !k8serrors.IsAlreadyExists(err) {
+		t.Errorf("CreateWebhook() of existing name should return already exists, got: %v", err)
+	}
+
+	webhooks, err := GetWebhookList(manager, fakeClient)
+	if err != nil || len(webhooks) != 1 || webhooks[0].TokenHash != "" {
+		t.Errorf("GetWebhookList() should return 1 webhook without token hash, got: %#v, %v", webhooks, err)
+	}
+
+	cases := []struct {
+		token, namespace, deployment, action string
+		expected                             bool
+	}{
+		{created.Token, "prod", "app", WebhookActionSetImage, true},
+		{created.Token, "prod", "app", WebhookActionRestart, false},
+		{created.Token, "prod", "other", WebhookActionSetImage, false},
+		{created.Token, "dev", "app", WebhookActionSetImage, false},
+		{"foo", "prod", "app", WebhookActionSetImage, false},
+	}
+	for _, c := range cases {
+		actual, err := AuthorizeWebhook(manager, fakeClient, c.token, c.namespace, c.deployment, c.action)
+		if c.expected && (err != nil || actual.Name != "ci") {
+			t.Errorf("AuthorizeWebhook(%#v, %#v, %#v) should return ci webhook, got: %#v, %v",
+				c.namespace, c.deployment, c.action, actual, err)
+		}
+		if !c.expected && !k8serrors.IsForbidden(err) {
+			t.Errorf("AuthorizeWebhook(%#v, %#v, %#v) should return forbidden, got: %v", c.namespace,
+				c.deployment, c.action, err)
+		}
+	}
+
+	if err := DeleteWebhook(manager, fakeClient, "ci"); err != nil {
+		t.Errorf("DeleteWebhook() returned error: %s", err)
+	}
+	if _, err := AuthorizeWebhook(manager, fakeClient, created.Token, "prod", "app",
+		WebhookActionSetImage); !k8serrors.IsForbidden(err) {
+		t.Errorf("AuthorizeWebhook() of deleted webhook should return forbidden, got: %v", err)
+	}
+}
+
+func TestCreateWebhookInvalid(t *testing.T) {
+	cases := []struct {
+		webhook Webhook
+		allowed bool
+		check   func(error) bool
+	}{
+		{Webhook{Namespace: "prod", Actions: []string{WebhookActionRestart}}, true, k8serrors.IsBadRequest},
+		{Webhook{Name: "ci", Namespace: "prod"}, true, k8serrors.IsBadRequest},
+		{Webhook{Name: "ci", Namespace: "prod", Actions: []string{"delete"}}, true, k8serrors.IsBadRequest},
+		{Webhook{Name: "ci", Namespace: "prod", Actions: []string{WebhookActionRestart}}, false,
+			k8serrors.IsForbidden},
+	}
+	for _, c := range cases {
+		fakeClient := fake.NewSimpleClientset()
+		allowAccess(fakeClient, c.allowed)
+
+		_, err := CreateWebhook(NewSettingsManager("kube-system"), fakeClient, "jane", c.webhook)
+		if !c.check(err) {
+			t.Errorf("CreateWebhook(%#v) returned unexpected error: %v", c.webhook, err)
+		}
+	}
+}
+
+func TestRecordWebhookCall(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	manager := NewSettingsManager("kube-system")
+
+	for i := 0; i < MaxWebhookRecords+1; i++ {
+		record := &WebhookRecord{Webhook: "ci", Action: WebhookActionRestart, Deployment: "app"}
+		if err := RecordWebhookCall(manager, fakeClient, record); err != nil || record.Time.IsZero() {
+			t.Fatalf("RecordWebhookCall() returned error: %v, record: %#v", err, record)
+		}
+	}
+
+	records, err := manager.GetWebhookRecords(fakeClient)
+	if err != nil || len(records) != MaxWebhookRecords {
+		t.Errorf("GetWebhookRecords() should return %d records, got: %d, %v", MaxWebhookRecords,
+			len(records), err)
+	}
+}
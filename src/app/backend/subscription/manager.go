@@ -0,0 +1,414 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subscription
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	apps "k8s.io/client-go/pkg/apis/apps/v1beta1"
+	authorizationApi "k8s.io/client-go/pkg/apis/authorization/v1"
+	batch "k8s.io/client-go/pkg/apis/batch/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Maximum number of subscriptions of a user.
+const maxSubscriptions = 100
+
+// Maximum number of notifications kept per user. Older ones are dropped.
+const maxNotifications = 200
+
+// Size of the channel buffer of a watcher. Notifications are dropped for slow watchers.
+const watchBuffer = 100
+
+// Types of notified changes.
+const (
+	NotificationAdded    = "Added"
+	NotificationModified = "Modified"
+	NotificationDeleted  = "Deleted"
+)
+
+// SubscriptionSpec describes resources a user is interested in.
+type SubscriptionSpec struct {
+	// Kind of the resources, e.g. deployment.
+	Kind string `json:"kind"`
+
+	// Namespace of the resources. Empty means all namespaces.
+	Namespace string `json:"namespace"`
+
+	// Name of the resource. Empty means all resources matching the label selector.
+	Name string `json:"name"`
+
+	// Label selector of the resources, e.g. app=foo. Empty means everything.
+	LabelSelector string `json:"labelSelector"`
+}
+
+// Subscription is a registered interest of a user in changes of resources.
+type Subscription struct {
+	ID string `json:"id"`
+	SubscriptionSpec
+	CreationTimestamp metaV1.Time `json:"creationTimestamp"`
+
+	user     string
+	selector labels.Selector
+}
+
+// SubscriptionList is a list of subscriptions of a user.
+type SubscriptionList struct {
+	Subscriptions []Subscription `json:"subscriptions"`
+}
+
+// Notification is a change of a resource matching a subscription.
+type Notification struct {
+	// ID of the notification. IDs grow over time, so that clients can ask for newer notifications.
+	ID             int64       `json:"id"`
+	SubscriptionID string      `json:"subscriptionId"`
+	Time           metaV1.Time `json:"time"`
+	// Type of the change, one of Added, Modified and Deleted.
+	Type      string `json:"type"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// NotificationList is a list of notifications of a user, oldest first.
+type NotificationList struct {
+	Notifications []Notification `json:"notifications"`
+	// ID of the newest notification, to be passed as since parameter of the next poll.
+	LastID int64 `json:"lastId"`
+}
+
+// Manager keeps subscriptions of users and records notifications of changes of subscribed
+// resources. Changes are observed by informers using the dashboard's own credentials, so
+// permissions of users are checked when they subscribe.
+type Manager interface {
+	// Start starts informers of supported resources using given client.
+	Start(client kubernetes.Interface)
+
+	// Subscribe registers new subscription of given user.
+	Subscribe(user string, spec SubscriptionSpec) (*Subscription, error)
+
+	// Unsubscribe deletes subscription with given ID of given user. Returns false if there is no
+	// such subscription.
+	Unsubscribe(user, id string) bool
+
+	// GetSubscriptions returns subscriptions of given user.
+	GetSubscriptions(user string) SubscriptionList
+
+	// GetNotifications returns notifications of given user newer than notification with given ID.
+	GetNotifications(user string, since int64) NotificationList
+
+	// Watch returns a channel of new notifications of given user and a function that stops
+	// watching and closes the channel.
+	Watch(user string) (<-chan Notification, func())
+}
+
+// watchedResource is a kind of resources that can be subscribed to.
+type watchedResource struct {
+	group    string
+	resource string
+	object   runtime.Object
+	client   func(kubernetes.Interface) cache.Getter
+}
+
+var watchedResources = map[string]watchedResource{
+	api.ResourceKindConfigMap: {"", "configmaps", &v1.ConfigMap{},
+		func(c kubernetes.Interface) cache.Getter { return c.CoreV1().RESTClient() }},
+	api.ResourceKindDaemonSet: {"extensions", "daemonsets", &extensions.DaemonSet{},
+		func(c kubernetes.Interface) cache.Getter { return c.ExtensionsV1beta1().RESTClient() }},
+	api.ResourceKindDeployment: {"extensions", "deployments", &extensions.Deployment{},
+		func(c kubernetes.Interface) cache.Getter { return c.ExtensionsV1beta1().RESTClient() }},
+	api.ResourceKindJob: {"batch", "jobs", &batch.Job{},
+		func(c kubernetes.Interface) cache.Getter { return c.BatchV1().RESTClient() }},
+	api.ResourceKindPod: {"", "pods", &v1.Pod{},
+		func(c kubernetes.Interface) cache.Getter { return c.CoreV1().RESTClient() }},
+	api.ResourceKindReplicaSet: {"extensions", "replicasets", &extensions.ReplicaSet{},
+		func(c kubernetes.Interface) cache.Getter { return c.ExtensionsV1beta1().RESTClient() }},
+	api.ResourceKindService: {"", "services", &v1.Service{},
+		func(c kubernetes.Interface) cache.Getter { return c.CoreV1().RESTClient() }},
+	api.ResourceKindStatefulSet: {"apps", "statefulsets", &apps.StatefulSet{},
+		func(c kubernetes.Interface) cache.Getter { return c.AppsV1beta1().RESTClient() }},
+}
+
+// CheckAccess returns Forbidden error if user of given client is not allowed to watch resources
+// of given subscription.
+func CheckAccess(client kubernetes.Interface, spec SubscriptionSpec) error {
+	watched, ok := watchedResources[spec.Kind]
+	if !ok {
+		return k8serrors.NewBadRequest(fmt.Sprintf("Subscriptions to %s are not supported", spec.Kind))
+	}
+
+	review, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(
+		&authorizationApi.SelfSubjectAccessReview{
+			Spec: authorizationApi.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationApi.ResourceAttributes{
+					Verb:      "watch",
+					Group:     watched.group,
+					Resource:  watched.resource,
+					Namespace: spec.Namespace,
+					Name:      spec.Name,
+				},
+			},
+		})
+	if err != nil {
+		return err
+	}
+	if !review.Status.Allowed {
+		return k8serrors.NewForbidden(
+			schema.GroupResource{Group: watched.group, Resource: watched.resource}, spec.Name,
+			fmt.Errorf("user cannot watch %s", watched.resource))
+	}
+	return nil
+}
+
+// manager is an in-memory implementation of Manager.
+type manager struct {
+	lock sync.RWMutex
+	// Whether informers listed existing resources already. Resources listed initially are not
+	// reported as added.
+	synced bool
+	// ID of the last subscription and of the last notification.
+	lastSubscriptionID int64
+	lastNotificationID int64
+	// Subscriptions, notifications and watchers keyed by user.
+	subscriptions map[string][]*Subscription
+	notifications map[string][]Notification
+	watchers      map[string][]chan Notification
+}
+
+// NewManager creates new subscription manager.
+func NewManager() Manager {
+	return &manager{subscriptions: make(map[string][]*Subscription),
+		notifications: make(map[string][]Notification),
+		watchers:      make(map[string][]chan Notification)}
+}
+
+// Start starts informers in background.
+func (self *manager) Start(client kubernetes.Interface) {
+	log.Print("Starting informers of subscribed resources")
+	synced := make([]cache.InformerSynced, 0, len(watchedResources))
+	for kind, watched := range watchedResources {
+		kind := kind
+		listWatch := cache.NewListWatchFromClient(watched.client(client), watched.resource,
+			metaV1.NamespaceAll, fields.Everything())
+		_, controller := cache.NewInformer(listWatch, watched.object, 0, cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				self.notify(kind, NotificationAdded, obj, time.Now())
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				oldMeta, err := meta.Accessor(oldObj)
+				if err == nil && oldMeta.GetResourceVersion() != "" {
+					if newMeta, err := meta.Accessor(newObj); err == nil &&
+						newMeta.GetResourceVersion() == oldMeta.GetResourceVersion() {
+						return
+					}
+				}
+				self.notify(kind, NotificationModified, newObj, time.Now())
+			},
+			DeleteFunc: func(obj interface{}) {
+				if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = unknown.Obj
+				}
+				self.notify(kind, NotificationDeleted, obj, time.Now())
+			},
+		})
+		go controller.Run(make(chan struct{}))
+		synced = append(synced, controller.HasSynced)
+	}
+
+	go func() {
+		cache.WaitForCacheSync(make(chan struct{}), synced...)
+		self.lock.Lock()
+		self.synced = true
+		self.lock.Unlock()
+		log.Print("Informers of subscribed resources synced")
+	}()
+}
+
+// Subscribe validates given spec and registers new subscription.
+func (self *manager) Subscribe(user string, spec SubscriptionSpec) (*Subscription, error) {
+	if _, ok := watchedResources[spec.Kind]; !ok {
+		return nil, k8serrors.NewBadRequest(fmt.Sprintf("Subscriptions to %s are not supported", spec.Kind))
+	}
+	selector, err := labels.Parse(spec.LabelSelector)
+	if err != nil {
+		return nil, k8serrors.NewBadRequest(err.Error())
+	}
+
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if len(self.subscriptions[user]) >= maxSubscriptions {
+		return nil, k8serrors.NewBadRequest(
+			fmt.Sprintf("User cannot have more than %d subscriptions", maxSubscriptions))
+	}
+
+	self.lastSubscriptionID++
+	subscription := &Subscription{
+		ID:                strconv.FormatInt(self.lastSubscriptionID, 10),
+		SubscriptionSpec:  spec,
+		CreationTimestamp: metaV1.Now(),
+		user:              user,
+		selector:          selector,
+	}
+	self.subscriptions[user] = append(self.subscriptions[user], subscription)
+
+	result := *subscription
+	return &result, nil
+}
+
+// Unsubscribe deletes subscription. Notifications of the subscription are kept.
+func (self *manager) Unsubscribe(user, id string) bool {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	subscriptions := self.subscriptions[user]
+	for i, subscription := range subscriptions {
+		if subscription.ID == id {
+			self.subscriptions[user] = append(subscriptions[:i:i], subscriptions[i+1:]...)
+			if len(self.subscriptions[user]) == 0 {
+				delete(self.subscriptions, user)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// GetSubscriptions returns copies of subscriptions of user, oldest first.
+func (self *manager) GetSubscriptions(user string) SubscriptionList {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	list := SubscriptionList{Subscriptions: make([]Subscription, 0)}
+	for _, subscription := range self.subscriptions[user] {
+		list.Subscriptions = append(list.Subscriptions, *subscription)
+	}
+	return list
+}
+
+// GetNotifications returns copies of notifications newer than given one.
+func (self *manager) GetNotifications(user string, since int64) NotificationList {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	list := NotificationList{Notifications: make([]Notification, 0), LastID: since}
+	for _, notification := range self.notifications[user] {
+		if notification.ID > since {
+			list.Notifications = append(list.Notifications, notification)
+			list.LastID = notification.ID
+		}
+	}
+	return list
+}
+
+// Watch registers new watcher of user.
+func (self *manager) Watch(user string) (<-chan Notification, func()) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	channel := make(chan Notification, watchBuffer)
+	self.watchers[user] = append(self.watchers[user], channel)
+
+	var once sync.Once
+	return channel, func() {
+		once.Do(func() {
+			self.lock.Lock()
+			defer self.lock.Unlock()
+
+			watchers := self.watchers[user]
+			for i, watcher := range watchers {
+				if watcher == channel {
+					self.watchers[user] = append(watchers[:i:i], watchers[i+1:]...)
+					break
+				}
+			}
+			if len(self.watchers[user]) == 0 {
+				delete(self.watchers, user)
+			}
+			close(channel)
+		})
+	}
+}
+
+// notify records notification of given change for every subscription matching the object and
+// sends it to watchers of subscribed users.
+func (self *manager) notify(kind, changeType string, obj interface{}, now time.Time) {
+	object, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if !self.synced && changeType == NotificationAdded {
+		return
+	}
+
+	for user, subscriptions := range self.subscriptions {
+		for _, subscription := range subscriptions {
+			if !subscription.matches(kind, object) {
+				continue
+			}
+
+			self.lastNotificationID++
+			notification := Notification{
+				ID:             self.lastNotificationID,
+				SubscriptionID: subscription.ID,
+				Time:           metaV1.NewTime(now),
+				Type:           changeType,
+				Kind:           kind,
+				Namespace:      object.GetNamespace(),
+				Name:           object.GetName(),
+			}
+
+			notifications := append(self.notifications[user], notification)
+			if len(notifications) > maxNotifications {
+				notifications = notifications[len(notifications)-maxNotifications:]
+			}
+			self.notifications[user] = notifications
+
+			for _, watcher := range self.watchers[user] {
+				select {
+				case watcher <- notification:
+				default:
+				}
+			}
+		}
+	}
+}
+
+// matches returns true if given object of given kind is covered by the subscription.
+func (self *Subscription) matches(kind string, object metaV1.Object) bool {
+	return self.Kind == kind &&
+		(self.Namespace == "" || self.Namespace == object.GetNamespace()) &&
+		(self.Name == "" || self.Name == object.GetName()) &&
+		self.selector.Matches(labels.Set(object.GetLabels()))
+}
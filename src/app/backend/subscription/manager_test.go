@@ -0,0 +1,130 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subscription
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/api"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+func TestSubscribe(t *testing.T) {
+	cases := []struct {
+		spec        SubscriptionSpec
+		expectError bool
+	}{
+		{SubscriptionSpec{Kind: api.ResourceKindDeployment, Namespace: "default", Name: "foo"}, false},
+		{SubscriptionSpec{Kind: api.ResourceKindPod, LabelSelector: "app in (foo,bar)"}, false},
+		{SubscriptionSpec{Kind: api.ResourceKindSecret}, true},
+		{SubscriptionSpec{Kind: api.ResourceKindPod, LabelSelector: "app in foo"}, true},
+	}
+	manager := NewManager()
+	for _, c := range cases {
+		actual, err := manager.Subscribe("jane", c.spec)
+		if (err != nil) != c.expectError {
+			t.Errorf("Subscribe(%#v) returns error %v, expected error: %v", c.spec, err, c.expectError)
+		}
+		if err == nil && actual.SubscriptionSpec != c.spec {
+			t.Errorf("Subscribe(%#v) == %#v, expected subscription of the spec", c.spec, actual)
+		}
+	}
+
+	if actual := len(manager.GetSubscriptions("jane").Subscriptions); actual != 2 {
+		t.Errorf("GetSubscriptions() returns %d subscriptions, expected 2", actual)
+	}
+	if actual := len(manager.GetSubscriptions("john").Subscriptions); actual != 0 {
+		t.Errorf("GetSubscriptions() returns %d subscriptions of other user, expected 0", actual)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	now := time.Date(2017, 5, 5, 10, 0, 0, 0, time.UTC)
+	newDeployment := func(namespace, name string) *extensions.Deployment {
+		return &extensions.Deployment{ObjectMeta: metaV1.ObjectMeta{Namespace: namespace, Name: name}}
+	}
+	newPod := func(name, app string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metaV1.ObjectMeta{Namespace: "default", Name: name,
+			Labels: map[string]string{"app": app}}}
+	}
+
+	manager := NewManager().(*manager)
+	deployment, _ := manager.Subscribe("jane", SubscriptionSpec{Kind: api.ResourceKindDeployment,
+		Namespace: "default", Name: "foo"})
+	pods, _ := manager.Subscribe("john", SubscriptionSpec{Kind: api.ResourceKindPod,
+		LabelSelector: "app=foo"})
+	watched, stop := manager.Watch("jane")
+
+	// resources listed initially are not reported
+	manager.notify(api.ResourceKindDeployment, NotificationAdded, newDeployment("default", "foo"), now)
+	manager.synced = true
+	manager.notify(api.ResourceKindDeployment, NotificationModified, newDeployment("default", "foo"), now)
+	manager.notify(api.ResourceKindDeployment, NotificationModified, newDeployment("prod", "foo"), now)
+	manager.notify(api.ResourceKindPod, NotificationAdded, newPod("foo-1", "foo"), now)
+	manager.notify(api.ResourceKindPod, NotificationDeleted, newPod("bar-1", "bar"), now)
+	manager.notify(api.ResourceKindPod, NotificationDeleted, newPod("foo-1", "foo"), now)
+
+	cases := []struct {
+		user     string
+		since    int64
+		expected NotificationList
+	}{
+		{
+			"jane", 0,
+			NotificationList{Notifications: []Notification{
+				{ID: 1, SubscriptionID: deployment.ID, Time: metaV1.NewTime(now),
+					Type: NotificationModified, Kind: api.ResourceKindDeployment, Namespace: "default",
+					Name: "foo"},
+			}, LastID: 1},
+		},
+		{
+			"john", 2,
+			NotificationList{Notifications: []Notification{
+				{ID: 3, SubscriptionID: pods.ID, Time: metaV1.NewTime(now), Type: NotificationDeleted,
+					Kind: api.ResourceKindPod, Namespace: "default", Name: "foo-1"},
+			}, LastID: 3},
+		},
+		{"john", 3, NotificationList{Notifications: []Notification{}, LastID: 3}},
+		{"joe", 0, NotificationList{Notifications: []Notification{}}},
+	}
+	for _, c := range cases {
+		actual := manager.GetNotifications(c.user, c.since)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetNotifications(%#v, %#v) == \ngot: %#v, \nexpected %#v", c.user, c.since,
+				actual, c.expected)
+		}
+	}
+
+	if notification := <-watched; notification.ID != 1 {
+		t.Errorf("Watch() sends notification %#v, expected the one with ID 1", notification)
+	}
+	stop()
+	if _, ok := <-watched; ok {
+		t.Error("Watch() channel is not closed after stop")
+	}
+	stop()
+
+	if !manager.Unsubscribe("john", pods.ID) || manager.Unsubscribe("john", pods.ID) {
+		t.Error("Unsubscribe() does not delete the subscription exactly once")
+	}
+	manager.notify(api.ResourceKindPod, NotificationAdded, newPod("foo-2", "foo"), now)
+	if actual := manager.GetNotifications("john", 3); len(actual.Notifications) != 0 {
+		t.Errorf("GetNotifications() returns %#v after unsubscribing, expected none", actual)
+	}
+}
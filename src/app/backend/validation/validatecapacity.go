@@ -0,0 +1,268 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	client "k8s.io/client-go/kubernetes"
+	api "k8s.io/client-go/pkg/api/v1"
+	helper "k8s.io/client-go/pkg/api/v1/resource"
+)
+
+// NodeReplicaCapacity is the number of replicas that fit into free capacity of a node.
+type NodeReplicaCapacity struct {
+	// Name of the node.
+	Name string `json:"name"`
+
+	// Number of replicas that fit into the node.
+	Replicas int64 `json:"replicas"`
+}
+
+// ScaleCapacity describes whether replicas added by scaling fit into free capacity of nodes they
+// can be scheduled on. Free capacity is allocatable CPU, memory and pods of a node minus requests
+// of pods running on it.
+type ScaleCapacity struct {
+	// Current and desired number of replicas.
+	CurrentReplicas int32 `json:"currentReplicas"`
+	DesiredReplicas int32 `json:"desiredReplicas"`
+
+	// Number of added replicas that fit into free capacity.
+	SchedulableReplicas int32 `json:"schedulableReplicas"`
+
+	// Number of added replicas that would remain pending.
+	PendingReplicas int32 `json:"pendingReplicas"`
+
+	// CPU in millicores and memory in bytes requested by a single replica.
+	CPURequests    int64 `json:"cpuRequests"`
+	MemoryRequests int64 `json:"memoryRequests"`
+
+	// Nodes replicas can be scheduled on, with number of replicas that fit into them.
+	Nodes []NodeReplicaCapacity `json:"nodes"`
+
+	// Warnings about the check, e.g. when replicas request no resources.
+	Warnings []string `json:"warnings"`
+}
+
+// ValidateScaleCapacity checks whether replicas added by scaling given resource to given number
+// of replicas fit into free capacity of nodes matching their node selector and tolerating their
+// taints. Other scheduling constraints, e.g. affinity, are not taken into account. Capacity is
+// not checked, with a warning, when user is not allowed to list nodes and pods.
+func ValidateScaleCapacity(client client.Interface, kind, namespace, name string,
+	replicas int32) (*ScaleCapacity, error) {
+	log.Printf("Validating capacity for scaling %s %s in %s namespace to %d replicas", kind, name,
+		namespace, replicas)
+
+	template, current, err := getScaleTemplate(client, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, _, err := helper.PodRequestsAndLimits(&api.Pod{Spec: template.Spec})
+	if err != nil {
+		return nil, err
+	}
+	cpuRequests, memoryRequests := requests[api.ResourceCPU], requests[api.ResourceMemory]
+	capacity := &ScaleCapacity{
+		CurrentReplicas: current,
+		DesiredReplicas: replicas,
+		CPURequests:     cpuRequests.MilliValue(),
+		MemoryRequests:  memoryRequests.Value(),
+		Nodes:           make([]NodeReplicaCapacity, 0),
+		Warnings:        make([]string, 0),
+	}
+	if capacity.CPURequests == 0 && capacity.MemoryRequests == 0 {
+		capacity.Warnings = append(capacity.Warnings,
+			"Replicas request no CPU or memory, only the number of pods per node is checked")
+	}
+
+	admission, err := admitScheduling(&SchedulingValiditySpec{
+		Namespace:    namespace,
+		NodeSelector: template.Spec.NodeSelector,
+		Tolerations:  template.Spec.Tolerations,
+	}, client)
+	if err != nil {
+		return nil, err
+	}
+	capacity.Warnings = append(capacity.Warnings, admission.Warnings...)
+
+	nodes, pods, err := listNodesAndPods(client, admission.NodeSelector)
+	if k8serrors.IsForbidden(err) {
+		// Users allowed to scale workloads of a namespace are often not allowed to see nodes.
+		capacity.Warnings = append(capacity.Warnings,
+			"Not allowed to list nodes and pods, free capacity of nodes is not checked")
+		return capacity, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var schedulable int64
+	for _, node := range nodes.Items {
+		if node.Spec.Unschedulable || !tolerateTaints(admission.Tolerations, node.Spec.Taints) {
+			continue
+		}
+		fit, err := getNodeReplicaCapacity(node, pods.Items, capacity.CPURequests,
+			capacity.MemoryRequests)
+		if err != nil {
+			return nil, err
+		}
+		capacity.Nodes = append(capacity.Nodes, NodeReplicaCapacity{Name: node.Name, Replicas: fit})
+		schedulable += fit
+	}
+	sort.Sort(nodesByReplicaCapacity(capacity.Nodes))
+
+	added := int64(replicas - current)
+	if added < 0 {
+		added = 0
+	}
+	if admission.Rejected {
+		// pods rejected at admission are not created at all
+		schedulable = 0
+	} else if schedulable > added {
+		schedulable = added
+	}
+	capacity.SchedulableReplicas = int32(schedulable)
+	capacity.PendingReplicas = int32(added - schedulable)
+
+	log.Printf("Validation result for capacity is %d schedulable and %d pending replicas",
+		capacity.SchedulableReplicas, capacity.PendingReplicas)
+	return capacity, nil
+}
+
+// listNodesAndPods returns nodes matching given node selector and pods of all namespaces.
+func listNodesAndPods(client client.Interface, nodeSelector map[string]string) (*api.NodeList,
+	*api.PodList, error) {
+	selector := labels.SelectorFromSet(labels.Set(nodeSelector))
+	nodes, err := client.CoreV1().Nodes().List(metaV1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, nil, err
+	}
+	pods, err := client.CoreV1().Pods("").List(metaV1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	return nodes, pods, nil
+}
+
+// getScaleTemplate returns pod template and current number of replicas of given scalable resource.
+func getScaleTemplate(client client.Interface, kind, namespace, name string) (*api.PodTemplateSpec,
+	int32, error) {
+	var template *api.PodTemplateSpec
+	var replicas *int32
+	switch strings.ToLower(kind) {
+	case "deployment":
+		deployment, err := client.ExtensionsV1beta1().Deployments(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, 0, err
+		}
+		template, replicas = &deployment.Spec.Template, deployment.Spec.Replicas
+	case "replicaset":
+		replicaSet, err := client.ExtensionsV1beta1().ReplicaSets(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, 0, err
+		}
+		template, replicas = &replicaSet.Spec.Template, replicaSet.Spec.Replicas
+	case "replicationcontroller":
+		controller, err := client.CoreV1().ReplicationControllers(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, 0, err
+		}
+		template, replicas = controller.Spec.Template, controller.Spec.Replicas
+	case "statefulset":
+		statefulSet, err := client.AppsV1beta1().StatefulSets(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, 0, err
+		}
+		template, replicas = &statefulSet.Spec.Template, statefulSet.Spec.Replicas
+	default:
+		return nil, 0, k8serrors.NewBadRequest(fmt.Sprintf("Capacity of %s cannot be checked", kind))
+	}
+
+	if template == nil {
+		template = &api.PodTemplateSpec{}
+	}
+	// Replicas default to 1.
+	current := int32(1)
+	if replicas != nil {
+		current = *replicas
+	}
+	return template, current, nil
+}
+
+// getNodeReplicaCapacity returns number of replicas with given requests that fit into free
+// capacity of given node. Pods that are not running on the node or already finished are ignored.
+func getNodeReplicaCapacity(node api.Node, pods []api.Pod, cpuRequests, memoryRequests int64) (
+	int64, error) {
+	allocatable := node.Status.Allocatable
+	if len(allocatable) == 0 {
+		allocatable = node.Status.Capacity
+	}
+	freeCPU, freeMemory, freePods := allocatable.Cpu().MilliValue(), allocatable.Memory().Value(),
+		allocatable.Pods().Value()
+
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName != node.Name || pod.Status.Phase == api.PodSucceeded ||
+			pod.Status.Phase == api.PodFailed {
+			continue
+		}
+		requests, _, err := helper.PodRequestsAndLimits(pod)
+		if err != nil {
+			return 0, err
+		}
+		podCPURequests, podMemoryRequests := requests[api.ResourceCPU], requests[api.ResourceMemory]
+		freeCPU -= podCPURequests.MilliValue()
+		freeMemory -= podMemoryRequests.Value()
+		freePods--
+	}
+
+	fit := freePods
+	if cpuRequests > 0 {
+		fit = minInt64(fit, freeCPU/cpuRequests)
+	}
+	if memoryRequests > 0 {
+		fit = minInt64(fit, freeMemory/memoryRequests)
+	}
+	if fit < 0 {
+		return 0, nil
+	}
+	return fit, nil
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// nodesByReplicaCapacity sorts nodes from the one with the most free capacity.
+type nodesByReplicaCapacity []NodeReplicaCapacity
+
+func (self nodesByReplicaCapacity) Len() int      { return len(self) }
+func (self nodesByReplicaCapacity) Swap(i, j int) { self[i], self[j] = self[j], self[i] }
+func (self nodesByReplicaCapacity) Less(i, j int) bool {
+	if self[i].Replicas != self[j].Replicas {
+		return self[i].Replicas > self[j].Replicas
+	}
+	return self[i].Name < self[j].Name
+}
@@ -0,0 +1,170 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	api "k8s.io/client-go/pkg/api/v1"
+	extensions "k8s.io/client-go/pkg/apis/extensions/v1beta1"
+	core "k8s.io/client-go/testing"
+)
+
+func TestValidateScaleCapacity(t *testing.T) {
+	newResources := func(cpu, memory string) api.ResourceList {
+		return api.ResourceList{
+			api.ResourceCPU:    resource.MustParse(cpu),
+			api.ResourceMemory: resource.MustParse(memory),
+			api.ResourcePods:   resource.MustParse("10"),
+		}
+	}
+	newNode := func(name string, unschedulable bool, taints ...api.Taint) *api.Node {
+		return &api.Node{
+			ObjectMeta: metaV1.ObjectMeta{Name: name},
+			Spec:       api.NodeSpec{Unschedulable: unschedulable, Taints: taints},
+			Status:     api.NodeStatus{Allocatable: newResources("2", "4Gi")},
+		}
+	}
+	newPod := func(name, node, cpu string, phase api.PodPhase) *api.Pod {
+		return &api.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: api.PodSpec{NodeName: node, Containers: []api.Container{{
+				Resources: api.ResourceRequirements{Requests: api.ResourceList{
+					api.ResourceCPU: resource.MustParse(cpu)}},
+			}}},
+			Status: api.PodStatus{Phase: phase},
+		}
+	}
+	newDeployment := func(name string, replicas int32, cpu, memory string) *extensions.Deployment {
+		return &extensions.Deployment{
+			ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: extensions.DeploymentSpec{
+				Replicas: &replicas,
+				Template: api.PodTemplateSpec{Spec: api.PodSpec{Containers: []api.Container{{
+					Resources: api.ResourceRequirements{Requests: api.ResourceList{
+						api.ResourceCPU:    resource.MustParse(cpu),
+						api.ResourceMemory: resource.MustParse(memory),
+					}},
+				}}}},
+			},
+		}
+	}
+	fakeClient := fake.NewSimpleClientset(
+		newNode("node-1", false),
+		newNode("node-2", false),
+		newNode("node-3", true),
+		newNode("node-4", false, api.Taint{Key: "dedicated", Effect: api.TaintEffectNoSchedule}),
+		newPod("running", "node-1", "1500m", api.PodRunning),
+		newPod("succeeded", "node-2", "2", api.PodSucceeded),
+		newDeployment("web", 2, "500m", "1Gi"),
+		newDeployment("empty", 1, "0", "0"),
+		&api.ReplicationController{ObjectMeta: metaV1.ObjectMeta{Name: "rc", Namespace: "default"}},
+	)
+
+	cases := []struct {
+		kind, name  string
+		replicas    int32
+		expected    *ScaleCapacity
+		expectError bool
+	}{
+		{
+			"deployment", "web", 8,
+			&ScaleCapacity{CurrentReplicas: 2, DesiredReplicas: 8, SchedulableReplicas: 5,
+				PendingReplicas: 1, CPURequests: 500, MemoryRequests: 1024 * 1024 * 1024,
+				Nodes: []NodeReplicaCapacity{{Name: "node-2", Replicas: 4},
+					{Name: "node-1", Replicas: 1}},
+				Warnings: []string{}},
+			false,
+		},
+		{
+			"deployment", "web", 1,
+			&ScaleCapacity{CurrentReplicas: 2, DesiredReplicas: 1, SchedulableReplicas: 0,
+				PendingReplicas: 0, CPURequests: 500, MemoryRequests: 1024 * 1024 * 1024,
+				Nodes: []NodeReplicaCapacity{{Name: "node-2", Replicas: 4},
+					{Name: "node-1", Replicas: 1}},
+				Warnings: []string{}},
+			false,
+		},
+		{
+			"Deployment", "empty", 30,
+			&ScaleCapacity{CurrentReplicas: 1, DesiredReplicas: 30, SchedulableReplicas: 19,
+				PendingReplicas: 10,
+				Nodes: []NodeReplicaCapacity{{Name: "node-2", Replicas: 10},
+					{Name: "node-1", Replicas: 9}},
+				Warnings: []string{
+					"Replicas request no CPU or memory, only the number of pods per node is checked"}},
+			false,
+		},
+		{
+			"replicationcontroller", "rc", 2,
+			&ScaleCapacity{CurrentReplicas: 1, DesiredReplicas: 2, SchedulableReplicas: 1,
+				Nodes: []NodeReplicaCapacity{{Name: "node-2", Replicas: 10},
+					{Name: "node-1", Replicas: 9}},
+				Warnings: []string{
+					"Replicas request no CPU or memory, only the number of pods per node is checked"}},
+			false,
+		},
+		{"deployment", "missing", 2, nil, true},
+		{"daemonset", "agent", 2, nil, true},
+	}
+	for _, c := range cases {
+		actual, err := ValidateScaleCapacity(fakeClient, c.kind, "default", c.name, c.replicas)
+		if (err != nil) != c.expectError {
+			t.Errorf("ValidateScaleCapacity(client, %#v, %#v, %#v) returns error %v, expected error: %v",
+				c.kind, c.name, c.replicas, err, c.expectError)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("ValidateScaleCapacity(client, %#v, %#v, %#v) == \ngot: %#v, \nexpected %#v",
+				c.kind, c.name, c.replicas, actual, c.expected)
+		}
+	}
+}
+
+func TestValidateScaleCapacityForbidden(t *testing.T) {
+	replicas := int32(1)
+	fakeClient := fake.NewSimpleClientset(&extensions.Deployment{
+		ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       extensions.DeploymentSpec{Replicas: &replicas},
+	})
+	forbidden := func(resource string) core.ReactionFunc {
+		return func(action core.Action) (bool, runtime.Object, error) {
+			return true, nil, k8serrors.NewForbidden(schema.GroupResource{Resource: resource}, "",
+				nil)
+		}
+	}
+	fakeClient.PrependReactor("get", "namespaces", forbidden("namespaces"))
+	fakeClient.PrependReactor("list", "nodes", forbidden("nodes"))
+
+	expected := &ScaleCapacity{CurrentReplicas: 1, DesiredReplicas: 3,
+		Nodes: []NodeReplicaCapacity{},
+		Warnings: []string{
+			"Replicas request no CPU or memory, only the number of pods per node is checked",
+			"Not allowed to get namespace default, its default scheduling is not checked",
+			"Not allowed to list nodes and pods, free capacity of nodes is not checked",
+		}}
+
+	actual, err := ValidateScaleCapacity(fakeClient, "deployment", "default", "web", 3)
+	if err != nil || !reflect.DeepEqual(actual, expected) {
+		t.Errorf("ValidateScaleCapacity() without access to nodes == \ngot: %#v, %v \nexpected %#v",
+			actual, err, expected)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	client "k8s.io/client-go/kubernetes"
+	api "k8s.io/client-go/pkg/api/v1"
+)
+
+// SchedulingValiditySpec is a specification of pod scheduling validation request.
+type SchedulingValiditySpec struct {
+	// Namespace of the pods. Default scheduling of the namespace is taken into account if set.
+	Namespace string `json:"namespace"`
+
+	// Node selector of the pods.
+	NodeSelector map[string]string `json:"nodeSelector"`
+
+	// Tolerations of the pods.
+	Tolerations []api.Toleration `json:"tolerations"`
+}
+
+// SchedulingValidity describes nodes pods with given node selector and tolerations can run on.
+type SchedulingValidity struct {
+	// True when pods can run on at least one node.
+	Valid bool `json:"valid"`
+
+	// Names of nodes matching the node selector with all taints tolerated.
+	Nodes []string `json:"nodes"`
+
+	// Names of nodes matching the node selector with taints that are not tolerated.
+	UntoleratedNodes []string `json:"untoleratedNodes"`
+
+	// Warnings about changes of the pods made at admission to the namespace, e.g. node selector
+	// added by the namespace, and reasons of their rejection.
+	Warnings []string `json:"warnings"`
+}
+
+// ValidateScheduling validates that pods with given node selector and tolerations can run on the
+// intended nodes, e.g. pods of system agents on all nodes of a node pool. When error is returned,
+// validity could not be determined.
+func ValidateScheduling(spec *SchedulingValiditySpec, client client.Interface) (*SchedulingValidity, error) {
+	log.Printf("Validating scheduling of pods with %v node selector", spec.NodeSelector)
+
+	admission, err := admitScheduling(spec, client)
+	if err != nil {
+		return nil, err
+	}
+
+	selector := labels.SelectorFromSet(labels.Set(admission.NodeSelector))
+	nodes, err := client.CoreV1().Nodes().List(metaV1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	validity := &SchedulingValidity{
+		Nodes:            make([]string, 0),
+		UntoleratedNodes: make([]string, 0),
+		Warnings:         admission.Warnings,
+	}
+	for _, node := range nodes.Items {
+		if tolerateTaints(admission.Tolerations, node.Spec.Taints) {
+			validity.Nodes = append(validity.Nodes, node.Name)
+		} else {
+			validity.UntoleratedNodes = append(validity.UntoleratedNodes, node.Name)
+		}
+	}
+	validity.Valid = len(validity.Nodes) > 0 && !admission.Rejected
+
+	log.Printf("Validation result for scheduling is %t, %d matching nodes have untolerated taints",
+		validity.Valid, len(validity.UntoleratedNodes))
+
+	return validity, nil
+}
+
+// admitScheduling returns scheduling of pods of given spec after admission to its namespace.
+func admitScheduling(spec *SchedulingValiditySpec, client client.Interface) (common.SchedulingAdmission, error) {
+	admission := common.SchedulingAdmission{
+		NodeSelector: spec.NodeSelector,
+		Tolerations:  spec.Tolerations,
+		Warnings:     make([]string, 0),
+	}
+	if spec.Namespace == "" {
+		return admission, nil
+	}
+
+	namespace, err := client.CoreV1().Namespaces().Get(spec.Namespace, metaV1.GetOptions{})
+	if err != nil {
+		if isNotFoundError(err) {
+			return admission, nil
+		}
+		if k8serrors.IsForbidden(err) {
+			admission.Warnings = append(admission.Warnings, fmt.Sprintf(
+				"Not allowed to get namespace %s, its default scheduling is not checked", spec.Namespace))
+			return admission, nil
+		}
+		return admission, err
+	}
+
+	if scheduling := common.GetNamespaceScheduling(namespace.Annotations); scheduling != nil {
+		return scheduling.Admit(spec.NodeSelector, spec.Tolerations), nil
+	}
+	return admission, nil
+}
+
+// tolerateTaints returns true if given tolerations tolerate all taints that prevent pods from
+// running on a node. Taints with PreferNoSchedule effect are ignored.
+func tolerateTaints(tolerations []api.Toleration, taints []api.Taint) bool {
+	for i := range taints {
+		if taints[i].Effect == api.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(&taints[i]) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	api "k8s.io/client-go/pkg/api/v1"
+)
+
+func TestValidateScheduling(t *testing.T) {
+	newNode := func(name, pool string, taints ...api.Taint) *api.Node {
+		return &api.Node{
+			ObjectMeta: metaV1.ObjectMeta{Name: name, Labels: map[string]string{"pool": pool}},
+			Spec:       api.NodeSpec{Taints: taints},
+		}
+	}
+	dedicated := api.Taint{Key: "dedicated", Value: "system", Effect: api.TaintEffectNoSchedule}
+	preferred := api.Taint{Key: "spot", Effect: api.TaintEffectPreferNoSchedule}
+	fakeClient := fake.NewSimpleClientset(
+		newNode("node-1", "system", dedicated),
+		newNode("node-2", "system", dedicated, preferred),
+		newNode("node-3", "default"),
+		&api.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "system", Annotations: map[string]string{
+			"scheduler.alpha.kubernetes.io/node-selector": "pool=system",
+			"scheduler.alpha.kubernetes.io/defaultTolerations": `[{"key": "dedicated", "operator": "Equal", ` +
+				`"value": "system", "effect": "NoSchedule"}]`,
+		}}},
+	)
+	toleration := api.Toleration{Key: "dedicated", Operator: api.TolerationOpEqual, Value: "system",
+		Effect: api.TaintEffectNoSchedule}
+
+	cases := []struct {
+		spec     *SchedulingValiditySpec
+		expected *SchedulingValidity
+	}{
+		{
+			&SchedulingValiditySpec{NodeSelector: map[string]string{"pool": "system"}},
+			&SchedulingValidity{Valid: false, Nodes: []string{},
+				UntoleratedNodes: []string{"node-1", "node-2"}, Warnings: []string{}},
+		},
+		{
+			&SchedulingValiditySpec{NodeSelector: map[string]string{"pool": "system"},
+				Tolerations: []api.Toleration{toleration}},
+			&SchedulingValidity{Valid: true, Nodes: []string{"node-1", "node-2"},
+				UntoleratedNodes: []string{}, Warnings: []string{}},
+		},
+		{
+			&SchedulingValiditySpec{},
+			&SchedulingValidity{Valid: true, Nodes: []string{"node-3"},
+				UntoleratedNodes: []string{"node-1", "node-2"}, Warnings: []string{}},
+		},
+		{
+			&SchedulingValiditySpec{NodeSelector: map[string]string{"pool": "gpu"}},
+			&SchedulingValidity{Valid: false, Nodes: []string{}, UntoleratedNodes: []string{},
+				Warnings: []string{}},
+		},
+		{
+			&SchedulingValiditySpec{Namespace: "system"},
+			&SchedulingValidity{Valid: true, Nodes: []string{"node-1", "node-2"},
+				UntoleratedNodes: []string{}, Warnings: []string{
+					"Namespace node selector pool=system will be added to pods",
+					"Namespace default toleration dedicated=system:NoSchedule will be added to pods",
+				}},
+		},
+		{
+			&SchedulingValiditySpec{Namespace: "system", NodeSelector: map[string]string{"pool": "default"}},
+			&SchedulingValidity{Valid: false, Nodes: []string{"node-3"}, UntoleratedNodes: []string{},
+				Warnings: []string{
+					"Node selector pool=default conflicts with namespace node selector pool=system, " +
+						"pods will be rejected",
+					"Namespace default toleration dedicated=system:NoSchedule will be added to pods",
+				}},
+		},
+	}
+	for _, c := range cases {
+		actual, err := ValidateScheduling(c.spec, fakeClient)
+		if err != nil {
+			t.Fatalf("ValidateScheduling(%#v) returned error: %s", c.spec, err)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("ValidateScheduling(%#v) == \ngot: %#v, \nexpected %#v", c.spec, actual, c.expected)
+		}
+	}
+}